@@ -19,7 +19,9 @@ package main
 import (
 	"flag"
 	"fmt"
+	"math"
 	"os"
+	"time"
 
 	"k8s.io/klog/v2"
 
@@ -39,6 +41,62 @@ func main() {
 		volMetricsOptIn          = flag.Bool("vol-metrics-opt-in", false, "Opt in to emit volume metrics")
 		volMetricsRefreshPeriod  = flag.Float64("vol-metrics-refresh-period", 240, "Refresh period for volume metrics in minutes")
 		volMetricsFsRateLimit    = flag.Int("vol-metrics-fs-rate-limit", 5, "Volume metrics routines rate limiter per file system")
+		tempMountDir             = flag.String("temp-mount-dir", "", "Directory under which DeleteVolume temporarily mounts the file system to delete the access point root directory. Must be an existing writable directory. Defaults to "+driver.TempMountPathPrefix)
+		cleanupMountOptions      = flag.String("cleanup-mount-options", "", "Comma separated mount options used by DeleteVolume to mount the file system when deleting the access point root directory. Replaces the default 'tls,iam' when set. For example, 'tls,mounttargetip=10.0.0.10'")
+		cleanupMountRetries      = flag.Int("cleanup-mount-retries", 3, "Number of times DeleteVolume retries the cleanup mount, with an exponential backoff starting at 1 second. When the mount still fails, the access point is deleted without its root directory.")
+		cleanupMountTimeout      = flag.Duration("cleanup-mount-timeout", time.Minute, "Time after which DeleteVolume stops retrying the cleanup mount. Retries are only bounded by cleanup-mount-retries when 0.")
+		useIam                   = flag.Bool("use-iam", false, "Mount volumes with the iam mount option by default, and always use it for the DeleteVolume cleanup mount. Required when the file system policy denies non IAM NFS clients. Can be overridden per StorageClass with the useIam parameter.")
+		clusterName              = flag.String("cluster-name", "", "Name of the cluster, added as the efs.csi.aws.com/cluster-name tag to created access points. When set, DeleteVolume refuses to delete access points tagged with a different cluster name.")
+		emitEvents               = flag.Bool("emit-events", false, "Record a Warning event on the PVC when CreateVolume fails. Requires the csi-provisioner --extra-create-metadata flag to know the PVC.")
+		denyUntagged             = flag.Bool("deny-provisioning-without-tags", false, "Reject CreateVolume requests without the PVC namespace metadata, so that every access point is tagged with the namespace that owns it. Requires the csi-provisioner --extra-create-metadata flag.")
+		awsProbeInterval         = flag.Duration("aws-probe-interval", 0, "Minimum interval between the EFS API connectivity checks done by the controller Probe, which fails when the API is unreachable or the credentials are invalid. Disabled when 0.")
+		softDeleteGrace          = flag.Duration("soft-delete-grace", 0, "When set, DeleteVolume tags access points with efs.csi.aws.com/deleted-at instead of deleting them, and they are deleted once the grace period has elapsed. Creating a volume with the same name during the grace period recovers the access point. Disabled when 0.")
+		disableDefaultTags       = flag.Bool("disable-default-tags", false, "Do not add the efs.csi.aws.com/cluster tag to EFS resources, only the tags of --tags. File systems provisioned with the efs-fs mode still get the efs.csi.aws.com/file-system-volume tag, which they are recognized by when deleted.")
+		warmupFileSystemIds      = flag.String("warmup-filesystem-ids", "", "Comma separated list of file system IDs whose access points are listed at startup, to report their access point utilization before the first CreateVolume call and to include them in the GID refresh. CreateVolume still lists the access points itself. Failures are logged and not fatal.")
+		maxGid                   = flag.Int64("max-gid", driver.DefaultMaxGid, "Maximum GID of access points. StorageClasses with a gid, ownerGid or gidRangeEnd above it are rejected, and the default GID range is clamped to it.")
+		defaultGidMin            = flag.Int64("default-gid-min", driver.DefaultGidMin, "Start of the GID range of access points whose StorageClass sets neither gidRangeStart nor gidRangeEnd.")
+		defaultGidMax            = flag.Int64("default-gid-max", driver.DefaultGidMax, "End of the GID range of access points whose StorageClass sets neither gidRangeStart nor gidRangeEnd. Must be greater than default-gid-min, and is clamped to max-gid.")
+		useFipsEndpoint          = flag.Bool("use-fips-endpoint", false, "Use the FIPS endpoints of the EFS and STS APIs. The driver fails to start if the region has no FIPS endpoint.")
+		awsEndpoint              = flag.String("aws-endpoint", "", "Endpoint of the EFS and STS APIs, e.g. to test against localstack. Requests are still signed for the region of the driver. Defaults to the AWS_ENDPOINT_URL environment variable, or to the endpoints of the region when it is not set.")
+		volumeTopology           = flag.Bool("volume-topology", false, "Advertise the VOLUME_ACCESSIBILITY_CONSTRAINTS capability, and make volumes provisioned with the efs-ap mode accessible from the zones of the mount targets of their file system only.")
+		allowedFileSystemIds     = flag.String("allowed-filesystem-ids", "", "Comma separated list of the file system IDs StorageClasses may provision access points on, each entry being an ID or a regular expression matching whole IDs, e.g. 'fs-abcd1234,fs-0123.*'. CreateVolume rejects other file systems with PermissionDenied. Every file system is allowed when empty.")
+		gidRefreshInterval       = flag.Duration("gid-refresh-interval", 0, "Interval at which the access points of the file systems volumes were provisioned on are listed again, so that the GIDs of access points deleted out of band are reused. Jittered by up to 20%. Disabled when 0.")
+		detectSubPathCollisions  = flag.Bool("detect-subpath-collisions", false, "Fail CreateVolume with AlreadyExists when the root directory generated by a subPathPattern without ensureUniqueDirectory is already the root directory of another access point of the file system, instead of sharing its data.")
+		shutdownTimeout          = flag.Duration("shutdown-timeout", 20*time.Second, "Time to wait on SIGTERM for the calls in flight, e.g. CreateVolume and DeleteVolume, to complete once new calls are refused. Calls still in flight are then cancelled, and get up to 30s more to roll back what they created. Should be lower than the terminationGracePeriodSeconds of the pod minus 30s.")
+		maxConcurrentCreate      = flag.Int("max-concurrent-create", 0, "Maximum number of mutating EFS calls in flight, e.g. creating, tagging and deleting access points, shared by every AWS role and region. Calls above the limit wait for a free slot until their deadline instead of failing. Unlimited when 0.")
+		inheritFsTags            = flag.Bool("inherit-fs-tags", false, "Add the tags of the file system to the access points created on it. Tags set by the driver, e.g. with --tags, take precedence, and tags with the aws: prefix are not inherited.")
+		inheritFsTagKeys         = flag.String("inherit-fs-tag-keys", "", "Comma separated list of the keys of the file system tags inherited with --inherit-fs-tags, e.g. 'cost-center,environment'. Every tag is inherited when empty.")
+		reservedGids             = flag.String("reserved-gids", "", "Comma separated list of GIDs and inclusive GID ranges never allocated to access points, e.g. '0-99,65534'.")
+		waitApAvailable          = flag.Bool("wait-ap-available", false, "Make CreateVolume wait until the access points it creates are available before returning, so that pods started right away do not fail to mount them. An access point still creating when the call times out is deleted, and the call fails to be retried.")
+		retainAccessPoints       = flag.Bool("retain-access-points", false, "Make DeleteVolume keep the access points of the deleted volumes, and their root directories, whatever the reclaim policy of the volumes. References to shared access points are still released. Retained access points must be deleted manually.")
+		maxRootDirLength         = flag.Int("max-root-dir-length", driver.DefaultMaxRootDirLength, "Maximum length of the directory mounted by the nodes, the root directory of the access point joined with the accessPointSubPath parameter. CreateVolume rejects longer directories with InvalidArgument instead of letting the mount fail.")
+		maxRootDirDepth          = flag.Int("max-root-dir-depth", 0, "Maximum number of directories of the directory mounted by the nodes, the root directory of the access point joined with the accessPointSubPath parameter. CreateVolume rejects deeper directories with InvalidArgument. Unlimited when 0.")
+		tenantBasePaths          = flag.String("tenant-base-paths", "", "Comma separated list of namespace=prefix entries, e.g. 'team-a=/team-a,team-b=/teams/b', restricting the root directories of the access points of each namespace to its prefix. CreateVolume rejects directories outside of the prefix, and namespaces without one, with PermissionDenied. Requires the csi-provisioner --extra-create-metadata flag. Every namespace may provision anywhere when empty.")
+		apWarnThreshold          = flag.Float64("access-point-warn-threshold", driver.DefaultAccessPointWarnThreshold, "Fraction of the access point limit of a file system, between 0 and 1, above which a warning is logged when the access points of the file system are listed, before CreateVolume fails on the limit. Disabled when 0.")
+		metricsAddress           = flag.String("metrics-address", "", "Address to serve the Prometheus metrics of the driver on, e.g. ':8080', at /metrics. Disabled when empty.")
+		preflightIamCheck        = flag.Bool("preflight-iam-check", false, "Check at startup that the driver is granted the IAM permissions it provisions access points with, using calls which cannot modify any resource, and fail to start if one is missing. Only enable on the controller, the node service makes no EFS API calls.")
+		cloudCallTimeouts        = flag.String("cloud-call-timeouts", "", "Comma separated list of call=duration entries, e.g. 'default=10s,ListAccessPoints=1m', bounding each EFS API call made by CreateVolume and DeleteVolume, so that a slow call fails with an error naming it instead of consuming the deadline of the whole CSI call. The default entry applies to the calls not listed. Calls are bounded by the deadline of the CSI call only when empty.")
+		apNamePrefix             = flag.String("access-point-name-prefix", "", "Prefix of the client token and Name tag of the access points created by the driver, e.g. 'cluster-a-', so that clusters sharing a file system never reuse each other's access points for PVs of the same name. Set it before provisioning, as access points provisioned without it are no longer found by retries. Up to 24 letters, numbers and _.:/=+-@.")
+		overflowFileSystemId     = flag.String("overflow-filesystem-id", "", "ID of the file system access points are created on when the file system of their StorageClass is at its limit of 1000 access points, instead of failing CreateVolume. It must be in the region and account of the file systems it takes the overflow of, and is not used for StorageClasses of another region or role. Every overflow is logged as a warning, recorded as an event on the PVC with --emit-events, and counted by the efs_csi_file_system_overflows_total metric. Disabled when empty.")
+		cleanupMountBatchWindow  = flag.Duration("cleanup-mount-batch-window", 0, "Time the cleanup mount of delete-access-point-root-dir is kept once unused, so that DeleteVolume calls deleting access points of the same file system shortly after each other, e.g. when a namespace is deleted, share a single mount instead of mounting and unmounting the file system for each access point. Every call mounts the file system on its own when 0.")
+		cleanupMountBatchSize    = flag.Int("cleanup-mount-batch-size", driver.DefaultCleanupMountBatchSize, "Maximum number of DeleteVolume calls sharing a cleanup mount with cleanup-mount-batch-window, after which the next calls mount the file system again. Unlimited when 0.")
+		awsCredentialsFile       = flag.String("aws-credentials-file", "", "Shared credentials file of the EFS and STS clients, e.g. mounted from a secret, whose credentials are read again whenever it changes, so that rotated credentials are used without restarting the driver. The profile is AWS_PROFILE, or the default profile. The default credential chain is used when empty.")
+		skipFsExistenceCheck     = flag.Bool("skip-fs-existence-check", false, "Skip the DescribeFileSystem call checking that the file system exists before creating an access point with a fixed uid and gid, halving the EFS calls of CreateVolume. A file system which does not exist then fails CreateAccessPoint, with the same InvalidArgument error.")
+		fsDescribeCacheTtl       = flag.Duration("fs-describe-cache-ttl", 0, "Time CreateVolume caches the description of a file system for, so that a burst of calls provisioning volumes on the same file system describes it once. Failed calls are not cached. Disabled when 0.")
+		safeDefaultPerms         = flag.String("safe-default-perms", "", "Octal permissions, e.g. '0750', of the root directory of the access points of StorageClasses without directoryPerms, instead of leaving them to EFS. StorageClasses setting directoryPerms are not affected. No default is applied when empty.")
+		namespacePosixUser       = flag.Bool("namespace-posix-annotations", false, "Set the uid and gid of the access points of a PVC from the efs.csi.aws.com/posix-uid and efs.csi.aws.com/posix-gid annotations of its namespace. The GID allocator assigns those which are not annotated, and StorageClass uid or gid parameters conflicting with the annotations are rejected. Requires the csi-provisioner --extra-create-metadata flag and permissions to list and watch namespaces.")
+		deleteCreatingApWait     = flag.Duration("delete-creating-ap-wait", driver.DefaultDeleteCreatingApWait, "Maximum time DeleteVolume waits for an access point which is still creating to be available before deleting it, as EFS fails to delete it until then. The call fails with Unavailable, to be retried, when the access point is still creating at the end of the wait or at the deadline of the call. Access points which are deleting already are not waited for. DeleteVolume fails right away when 0.")
+		debugAddress             = flag.String("debug-addr", "", "Address to serve debugging endpoints on, e.g. 'localhost:8081'. /debug/gids returns the state of the GID allocator of each file system as JSON: the range of the last allocation, the GIDs in use in it and their high-water mark. Disabled when empty.")
+		verifyEmptyOnReuse       = flag.Bool("verify-empty-on-reuse", false, "Make CreateVolume verify, through a temporary mount, that the root directory of an access point of a StorageClass with ensureUniqueDirectory set to false is empty before reusing it, and fail with FailedPrecondition otherwise, so that a re-created PVC never gets the files of a previous volume.")
+		breakerThreshold         = flag.Int("circuit-breaker-threshold", 0, "Number of consecutive EFS calls failing with a transient error, i.e. throttled, timed out, unreachable or failing on the EFS side, after which EFS calls are short-circuited and fail right away with Unavailable for --circuit-breaker-cooldown, before a single call probes EFS again. 0 disables the circuit breaker.")
+		breakerCoolDown          = flag.Duration("circuit-breaker-cooldown", 30*time.Second, "Duration EFS calls are short-circuited for once --circuit-breaker-threshold consecutive calls failed.")
+		omitEmptyVolumeContext   = flag.Bool("omit-empty-volume-context", true, "Omit the keys of the volume context of the volumes created by CreateVolume whose value is empty, instead of returning them with an empty value.")
+		waitApDeleted            = flag.Duration("wait-ap-deleted", 0, "Maximum time DeleteVolume waits, after deleting an access point, for it to be gone rather than deleting, as an access point deleting still counts against the access point limit of its file system. Its GID is released once it is gone. The call fails with Unavailable, to be retried, when the access point is still there at the end of the wait or at the deadline of the call. Disabled when 0.")
+		efsMountHelperPath       = flag.String("efs-mount-helper-path", driver.DefaultEfsMountHelperPath, "Path of the efs-utils mount helper. The helper is run directly when it is not at the default path, where mount finds it.")
+		cleanupMountFlavor       = flag.String("cleanup-mount-flavor", driver.CleanupMountFlavorAuto, "How DeleteVolume mounts the file system to delete the access point root directory, and CreateVolume with createDirMode=mount: 'efs' with the efs-utils mount helper, 'nfs4' with plain NFS 4 and the options efs-utils would use, dropping efs-utils options such as tls and iam, or 'auto' with efs-utils when --efs-mount-helper-path exists and nfs4 otherwise.")
+		fallbackRegion           = flag.String("fallback-region", "", "Region the calls describing access points and file systems are retried in when they fail transiently, e.g. during an outage of the EFS API of the region of the driver. Only their success is used, a failure in the fallback region returns the failure of the region of the driver. Calls creating, tagging or deleting resources are never retried there. Disabled when empty.")
+		adaptiveConcurrency      = flag.Bool("adaptive-concurrency", false, "Adapt the limit of mutating EFS calls in flight to their throttling, between 1 and --max-concurrent-create: the limit is halved when a call is throttled, and raised by one after as many successful calls as the limit. The current limit is exported as the efs_csi_mutating_call_limit metric. Requires --max-concurrent-create.")
+		gidRangeReserve          = flag.Float64("gid-range-reserve", 0, "Fraction of each GID range, between 0 and 1, kept free as headroom: CreateVolume fails with ResourceExhausted once the GIDs in use reach the rest of the range, leaving operators time to widen the range before it is exhausted. Disabled when 0.")
 		deleteAccessPointRootDir = flag.Bool("delete-access-point-root-dir", false,
 			"Opt in to delete access point root directory by DeleteVolume. By default, DeleteVolume will delete the access point behind Persistent Volume and deleting access point will not delete the access point root directory or its contents.")
 		tags = flag.String("tags", "", "Space separated key:value pairs which will be added as tags for EFS resources. For example, 'environment:prod region:us-east-1'")
@@ -60,7 +118,186 @@ func main() {
 	if err != nil {
 		klog.Fatalln(err)
 	}
-	drv := driver.NewDriver(*endpoint, etcAmazonEfs, *efsUtilsStaticFilesPath, *tags, *volMetricsOptIn, *volMetricsRefreshPeriod, *volMetricsFsRateLimit, *deleteAccessPointRootDir)
+	if *cleanupMountRetries < 0 {
+		klog.Fatalln("cleanup-mount-retries must not be negative")
+	}
+	if *maxConcurrentCreate < 0 {
+		klog.Fatalln("max-concurrent-create must not be negative")
+	}
+	if *shutdownTimeout < 0 {
+		klog.Fatalln("shutdown-timeout must not be negative")
+	}
+	if *gidRefreshInterval < 0 {
+		klog.Fatalln("gid-refresh-interval must not be negative")
+	}
+	if *maxRootDirLength <= 0 {
+		klog.Fatalln("max-root-dir-length must be greater than 0")
+	}
+	if *maxRootDirDepth < 0 {
+		klog.Fatalln("max-root-dir-depth must not be negative")
+	}
+	if *apWarnThreshold < 0 || *apWarnThreshold > 1 {
+		klog.Fatalln("access-point-warn-threshold must be between 0 and 1")
+	}
+	if *maxGid <= 0 || *maxGid > math.MaxUint32-1 {
+		klog.Fatalln("max-gid must be between 1 and", int64(math.MaxUint32-1))
+	}
+	if *defaultGidMin <= 0 || *defaultGidMin >= *maxGid {
+		klog.Fatalln("default-gid-min must be greater than 0 and lower than max-gid", *maxGid)
+	}
+	if *defaultGidMax <= *defaultGidMin {
+		klog.Fatalln("default-gid-max must be greater than default-gid-min", *defaultGidMin)
+	}
+	warmupIds, err := driver.ParseFileSystemIds(*warmupFileSystemIds)
+	if err != nil {
+		klog.Fatalln("invalid warmup-filesystem-ids:", err)
+	}
+	allowedIds, err := driver.ParseAllowedFileSystemIds(*allowedFileSystemIds)
+	if err != nil {
+		klog.Fatalln("invalid allowed-filesystem-ids:", err)
+	}
+	reserved, err := driver.ParseReservedGids(*reservedGids)
+	if err != nil {
+		klog.Fatalln("invalid reserved-gids:", err)
+	}
+	tenantPrefixes, err := driver.ParseTenantBasePaths(*tenantBasePaths)
+	if err != nil {
+		klog.Fatalln("invalid tenant-base-paths:", err)
+	}
+	callTimeouts, err := driver.ParseCloudCallTimeouts(*cloudCallTimeouts)
+	if err != nil {
+		klog.Fatalln("invalid cloud-call-timeouts:", err)
+	}
+	if err := driver.ValidateAccessPointNamePrefix(*apNamePrefix); err != nil {
+		klog.Fatalln("invalid access-point-name-prefix:", err)
+	}
+	if *overflowFileSystemId != "" {
+		if ids, err := driver.ParseFileSystemIds(*overflowFileSystemId); err != nil || len(ids) != 1 {
+			klog.Fatalf("invalid overflow-filesystem-id %q: must be a single file system ID", *overflowFileSystemId)
+		}
+		if allowedIds != nil && !allowedIds.MatchString(*overflowFileSystemId) {
+			klog.Fatalf("overflow-filesystem-id %v is not allowed by allowed-filesystem-ids", *overflowFileSystemId)
+		}
+	}
+	if *cleanupMountBatchWindow < 0 {
+		klog.Fatalln("invalid cleanup-mount-batch-window: must not be negative")
+	}
+	if *cleanupMountBatchSize < 0 {
+		klog.Fatalln("invalid cleanup-mount-batch-size: must not be negative")
+	}
+	if *awsCredentialsFile != "" {
+		if _, err := os.Stat(*awsCredentialsFile); err != nil {
+			klog.Fatalln("invalid aws-credentials-file:", err)
+		}
+	}
+	if *fsDescribeCacheTtl < 0 {
+		klog.Fatalln("invalid fs-describe-cache-ttl: must not be negative")
+	}
+	if err := driver.ValidateSafeDefaultPerms(*safeDefaultPerms); err != nil {
+		klog.Fatalln("invalid safe-default-perms:", err)
+	}
+	if *deleteCreatingApWait < 0 {
+		klog.Fatalln("invalid delete-creating-ap-wait: must not be negative")
+	}
+	if *waitApDeleted < 0 {
+		klog.Fatalln("invalid wait-ap-deleted: must not be negative")
+	}
+	if err := driver.ValidateCleanupMountFlavor(*cleanupMountFlavor); err != nil {
+		klog.Fatalln("invalid cleanup-mount-flavor:", err)
+	}
+	if *adaptiveConcurrency && *maxConcurrentCreate == 0 {
+		klog.Fatalln("invalid adaptive-concurrency: requires max-concurrent-create")
+	}
+	if *gidRangeReserve < 0 || *gidRangeReserve >= 1 {
+		klog.Fatalln("invalid gid-range-reserve: must be at least 0 and less than 1")
+	}
+	if *breakerThreshold < 0 {
+		klog.Fatalln("invalid circuit-breaker-threshold: must not be negative")
+	}
+	if *breakerCoolDown <= 0 {
+		klog.Fatalln("invalid circuit-breaker-cooldown: must be positive")
+	}
+	// The default GID range is clamped to the maximum GID by CreateVolume
+	defaultMax := *defaultGidMax
+	if defaultMax > *maxGid {
+		defaultMax = *maxGid
+	}
+	if count := reserved.Count(*defaultGidMin, defaultMax); count > defaultMax-*defaultGidMin {
+		klog.Fatalf("reserved-gids reserves every GID of the default GID range %v-%v", *defaultGidMin, defaultMax)
+	} else if count > 0 {
+		klog.Infof("%v GIDs of the default GID range %v-%v are reserved", count, *defaultGidMin, defaultMax)
+	}
+	if *tempMountDir != "" {
+		if err := driver.ValidateTempMountDir(*tempMountDir); err != nil {
+			klog.Fatalln(err)
+		}
+	}
+	drv := driver.NewDriver(&driver.DriverOptions{
+		Endpoint:                  *endpoint,
+		EfsUtilsCfgPath:           etcAmazonEfs,
+		EfsUtilsStaticFilesPath:   *efsUtilsStaticFilesPath,
+		Tags:                      *tags,
+		VolMetricsOptIn:           *volMetricsOptIn,
+		VolMetricsRefreshPeriod:   *volMetricsRefreshPeriod,
+		VolMetricsFsRateLimit:     *volMetricsFsRateLimit,
+		DeleteAccessPointRootDir:  *deleteAccessPointRootDir,
+		TempMountDir:              *tempMountDir,
+		CleanupMountOptions:       *cleanupMountOptions,
+		UseIam:                    *useIam,
+		ClusterName:               *clusterName,
+		EmitEvents:                *emitEvents,
+		DenyUntagged:              *denyUntagged,
+		AwsProbeInterval:          *awsProbeInterval,
+		CleanupMountRetries:       *cleanupMountRetries,
+		CleanupMountTimeout:       *cleanupMountTimeout,
+		SoftDeleteGrace:           *softDeleteGrace,
+		DisableDefaultTags:        *disableDefaultTags,
+		WarmupFileSystemIds:       warmupIds,
+		MaxGid:                    *maxGid,
+		UseFipsEndpoint:           *useFipsEndpoint,
+		AwsEndpoint:               *awsEndpoint,
+		VolumeTopologyEnabled:     *volumeTopology,
+		AllowedFileSystemIds:      allowedIds,
+		GidRefreshInterval:        *gidRefreshInterval,
+		DefaultGidMin:             *defaultGidMin,
+		DefaultGidMax:             *defaultGidMax,
+		DetectSubPathCollisions:   *detectSubPathCollisions,
+		ShutdownTimeout:           *shutdownTimeout,
+		MaxConcurrentCreate:       *maxConcurrentCreate,
+		InheritFsTags:             *inheritFsTags,
+		InheritFsTagKeys:          driver.ParseInheritedTagKeys(*inheritFsTagKeys),
+		ReservedGids:              reserved,
+		WaitAccessPointAvailable:  *waitApAvailable,
+		RetainAccessPoints:        *retainAccessPoints,
+		MaxRootDirLength:          *maxRootDirLength,
+		MaxRootDirDepth:           *maxRootDirDepth,
+		TenantBasePaths:           tenantPrefixes,
+		AccessPointWarnThreshold:  *apWarnThreshold,
+		MetricsAddress:            *metricsAddress,
+		PreflightIamCheck:         *preflightIamCheck,
+		CloudCallTimeouts:         callTimeouts,
+		AccessPointNamePrefix:     *apNamePrefix,
+		OverflowFileSystemId:      *overflowFileSystemId,
+		CleanupMountBatchWindow:   *cleanupMountBatchWindow,
+		CleanupMountBatchSize:     *cleanupMountBatchSize,
+		AwsCredentialsFile:        *awsCredentialsFile,
+		SkipFsExistenceCheck:      *skipFsExistenceCheck,
+		FsDescribeCacheTtl:        *fsDescribeCacheTtl,
+		SafeDefaultPerms:          *safeDefaultPerms,
+		NamespacePosixAnnotations: *namespacePosixUser,
+		DeleteCreatingApWait:      *deleteCreatingApWait,
+		DebugAddress:              *debugAddress,
+		VerifyEmptyOnReuse:        *verifyEmptyOnReuse,
+		BreakerThreshold:          *breakerThreshold,
+		BreakerCoolDown:           *breakerCoolDown,
+		OmitEmptyVolumeContext:    *omitEmptyVolumeContext,
+		WaitAccessPointDeleted:    *waitApDeleted,
+		EfsMountHelperPath:        *efsMountHelperPath,
+		CleanupMountFlavor:        *cleanupMountFlavor,
+		FallbackRegion:            *fallbackRegion,
+		AdaptiveConcurrency:       *adaptiveConcurrency,
+		GidRangeReserve:           *gidRangeReserve,
+	})
 	if err := drv.Run(); err != nil {
 		klog.Fatalln(err)
 	}
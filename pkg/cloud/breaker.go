@@ -0,0 +1,112 @@
+package cloud
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/request"
+	"k8s.io/klog/v2"
+)
+
+// ErrCircuitOpen is the error of the EFS calls short-circuited by the CircuitBreaker
+var ErrCircuitOpen = errors.New("EFS calls are short-circuited after repeated failures")
+
+// CircuitBreaker short-circuits the EFS calls once threshold consecutive calls failed with a transient error, e.g.
+// during an EFS incident, so that retries do not worsen the throttling. Calls fail right away with ErrCircuitOpen for
+// coolDown, after which a single call probes EFS: its success closes the circuit, and its failure opens it again.
+type CircuitBreaker struct {
+	threshold int
+	coolDown  time.Duration
+	now       func() time.Time
+
+	mu sync.Mutex
+	// failures is the number of consecutive calls which failed with a transient error
+	failures int
+	// openUntil is the end of the cool-down, and probing whether a call probes EFS once it ended
+	openUntil time.Time
+	probing   bool
+}
+
+// NewCircuitBreaker returns a breaker opening after threshold consecutive transient failures for coolDown, or nil,
+// which never short-circuits calls, when threshold is not positive
+func NewCircuitBreaker(threshold int, coolDown time.Duration) *CircuitBreaker {
+	if threshold <= 0 {
+		return nil
+	}
+	return &CircuitBreaker{threshold: threshold, coolDown: coolDown, now: time.Now}
+}
+
+// allow returns ErrCircuitOpen when the call must be short-circuited
+func (b *CircuitBreaker) allow() error {
+	if b == nil {
+		return nil
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.failures < b.threshold {
+		return nil
+	}
+	if remaining := b.openUntil.Sub(b.now()); remaining > 0 {
+		return fmt.Errorf("%w, retrying in %v", ErrCircuitOpen, remaining.Round(time.Second))
+	}
+	if b.probing {
+		return fmt.Errorf("%w, waiting for a call probing EFS", ErrCircuitOpen)
+	}
+	b.probing = true
+	return nil
+}
+
+// record records the result of a call which was not short-circuited
+func (b *CircuitBreaker) record(err error) {
+	if b == nil || errors.Is(err, ErrCircuitOpen) {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.probing = false
+	if IsTransient(err) {
+		b.failures++
+		if b.failures >= b.threshold {
+			b.openUntil = b.now().Add(b.coolDown)
+			klog.Warningf("%v consecutive EFS calls failed, short-circuiting EFS calls for %v: %v", b.failures, b.coolDown, err)
+		}
+		return
+	}
+	// Any other result, including errors such as NotFound, shows that EFS responds
+	if b.failures >= b.threshold {
+		klog.Infof("EFS responds again, no longer short-circuiting EFS calls")
+	}
+	b.failures = 0
+}
+
+// shortCircuitedKey is the context key of the flag set when an EFS call made with the context is short-circuited
+type shortCircuitedKey struct{}
+
+// WithShortCircuitFlag returns a context whose EFS calls set the returned flag when they are short-circuited, so that
+// the caller can report them as unavailable whatever the error they were wrapped in
+func WithShortCircuitFlag(ctx context.Context) (context.Context, *atomic.Bool) {
+	flag := &atomic.Bool{}
+	return context.WithValue(ctx, shortCircuitedKey{}, flag), flag
+}
+
+// addHandlers makes the breaker short-circuit the requests of an EFS client, and record their results
+func (b *CircuitBreaker) addHandlers(handlers *request.Handlers) {
+	if b == nil {
+		return
+	}
+	handlers.Validate.PushFrontNamed(request.NamedHandler{Name: "efs-csi.CircuitBreaker", Fn: func(r *request.Request) {
+		if err := b.allow(); err != nil {
+			if flag, ok := r.Context().Value(shortCircuitedKey{}).(*atomic.Bool); ok {
+				flag.Store(true)
+			}
+			r.Error = err
+		}
+	}})
+	handlers.Complete.PushBackNamed(request.NamedHandler{Name: "efs-csi.CircuitBreakerRecord", Fn: func(r *request.Request) {
+		b.record(r.Error)
+	}})
+}
@@ -0,0 +1,127 @@
+package cloud
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/efs"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	now := time.Now()
+	breaker := NewCircuitBreaker(2, 30*time.Second)
+	breaker.now = func() time.Time { return now }
+	throttled := awserr.New("ThrottlingException", "Rate exceeded", nil)
+	notFound := awserr.New(efs.ErrCodeAccessPointNotFound, "Access point not found", nil)
+
+	expectAllowed := func(step string, allowed bool) {
+		t.Helper()
+		err := breaker.allow()
+		if allowed && err != nil {
+			t.Fatalf("%v: expected the call to be allowed, got: %v", step, err)
+		}
+		if !allowed && !errors.Is(err, ErrCircuitOpen) {
+			t.Fatalf("%v: expected the call to be short-circuited, got: %v", step, err)
+		}
+	}
+
+	// Failures which are not consecutive do not trip the breaker
+	breaker.record(throttled)
+	breaker.record(notFound)
+	breaker.record(throttled)
+	expectAllowed("Non consecutive failures", true)
+
+	breaker.record(throttled)
+	expectAllowed("Tripped", false)
+	// Short-circuited calls are not recorded
+	breaker.record(fmt.Errorf("wrapped: %w", ErrCircuitOpen))
+	now = now.Add(29 * time.Second)
+	expectAllowed("Cooling down", false)
+
+	// A single call probes EFS after the cool-down, and trips the breaker again when it fails
+	now = now.Add(time.Second)
+	expectAllowed("Probe", true)
+	expectAllowed("Call during the probe", false)
+	breaker.record(throttled)
+	expectAllowed("Failed probe", false)
+
+	// A successful probe resets the breaker
+	now = now.Add(30 * time.Second)
+	expectAllowed("Second probe", true)
+	breaker.record(nil)
+	expectAllowed("Reset", true)
+	breaker.record(throttled)
+	expectAllowed("Single failure after reset", true)
+
+	// A nil breaker never short-circuits calls
+	breaker = NewCircuitBreaker(0, 30*time.Second)
+	if breaker != nil {
+		t.Fatalf("Expected no breaker with a threshold of 0")
+	}
+	breaker.record(throttled)
+	expectAllowed("Disabled", true)
+}
+
+func TestCreateEfsClientCircuitBreaker(t *testing.T) {
+	t.Setenv("AWS_PROFILE", "")
+	t.Setenv("AWS_ACCESS_KEY_ID", "AKIDTEST")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "secret")
+	t.Setenv(endpointURLEnvVar, "")
+
+	var requests, failing atomic.Int32
+	failing.Store(1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if failing.Load() == 1 {
+			w.Header().Set("x-amzn-ErrorType", "InternalServerError")
+			w.WriteHeader(http.StatusInternalServerError)
+			fmt.Fprint(w, `{"message":"Internal failure"}`)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"FileSystems":[]}`)
+	}))
+	defer server.Close()
+
+	now := time.Now()
+	breaker := NewCircuitBreaker(1, time.Minute)
+	breaker.now = func() time.Time { return now }
+	opts := ClientOptions{Endpoint: server.URL, CircuitBreaker: breaker}
+	client := createEfsClient("", "us-east-1", session.Must(session.NewSession()), opts)
+	describe := func() (error, bool) {
+		ctx, shortCircuited := WithShortCircuitFlag(context.Background())
+		_, err := client.DescribeFileSystemsWithContext(ctx, &efs.DescribeFileSystemsInput{})
+		return err, shortCircuited.Load()
+	}
+
+	if err, shortCircuited := describe(); err == nil || shortCircuited {
+		t.Fatalf("Expected the call to fail on EFS, got: %v", err)
+	}
+	sent := requests.Load()
+
+	// The breaker is open, the call fails without reaching EFS
+	err, shortCircuited := describe()
+	if !errors.Is(err, ErrCircuitOpen) || !shortCircuited || !IsTransient(err) {
+		t.Fatalf("Expected the call to be short-circuited, got: %v", err)
+	}
+	if requests.Load() != sent {
+		t.Fatalf("A short-circuited call reached EFS")
+	}
+
+	// EFS recovers, the probe after the cool-down closes the circuit
+	failing.Store(0)
+	now = now.Add(time.Minute)
+	for i := 0; i < 2; i++ {
+		if err, shortCircuited := describe(); err != nil || shortCircuited {
+			t.Fatalf("Expected call %v after the cool-down to succeed, got: %v", i, err)
+		}
+	}
+}
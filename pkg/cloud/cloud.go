@@ -21,16 +21,21 @@ import (
 	"errors"
 	"fmt"
 	"math/rand"
+	"net/url"
 	"os"
+	"strconv"
+	"sync"
 	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/aws/credentials/stscreds"
 	"github.com/aws/aws-sdk-go/aws/ec2metadata"
+	"github.com/aws/aws-sdk-go/aws/endpoints"
 	"github.com/aws/aws-sdk-go/aws/request"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/efs"
+	"github.com/aws/aws-sdk-go/service/sts"
 	"k8s.io/klog/v2"
 )
 
@@ -38,32 +43,124 @@ const (
 	AccessDeniedException    = "AccessDeniedException"
 	AccessPointAlreadyExists = "AccessPointAlreadyExists"
 	PvcNameTagKey            = "pvcName"
+	QuotaBytesTagKey         = "efs.csi.aws.com/quota-bytes"
 	AccessPointPerFsLimit    = 1000
+	// ThroughputModeElastic is not yet part of the ThroughputMode enum of the vendored SDK
+	ThroughputModeElastic = "elastic"
 )
 
 var (
 	ErrNotFound      = errors.New("Resource was not found")
 	ErrAlreadyExists = errors.New("Resource already exists")
 	ErrAccessDenied  = errors.New("Access denied")
+	ErrInvalidRegion = errors.New("Invalid region")
 )
 
+// ClientOptions configures the AWS API clients of the cloud
+type ClientOptions struct {
+	// UseFIPSEndpoint makes the EFS and STS clients use the FIPS endpoints of their region
+	UseFIPSEndpoint bool
+	// Endpoint overrides the endpoint of the EFS and STS clients, e.g. to test against localstack.
+	// The AWS_ENDPOINT_URL environment variable is used when empty.
+	Endpoint string
+	// CallLimiter bounds the mutating EFS calls in flight, shared by the clouds created with the options.
+	// Calls are not limited when nil.
+	CallLimiter *CallLimiter
+	// CredentialsFile is a shared credentials file whose credentials are read again whenever it changes, e.g. when
+	// they are rotated. The default credential chain of the SDK is used when empty.
+	CredentialsFile string
+	// CircuitBreaker short-circuits the EFS calls of the clouds created with the options after repeated failures.
+	// Calls are never short-circuited when nil.
+	CircuitBreaker *CircuitBreaker
+	// FallbackRegion is the region the describe calls of access points and file systems are retried in when they
+	// fail transiently, e.g. during an outage of the EFS API of the region of the driver. Mutating calls are never
+	// retried there, as the resources belong to their region. No describe call is retried when empty.
+	FallbackRegion string
+}
+
+// endpointURLEnvVar is the environment variable overriding the endpoint when ClientOptions.Endpoint is empty
+const endpointURLEnvVar = "AWS_ENDPOINT_URL"
+
+// endpoint returns the endpoint overriding the one of the region, or an empty string to resolve it
+func (o ClientOptions) endpoint() string {
+	if o.Endpoint != "" {
+		return o.Endpoint
+	}
+	return os.Getenv(endpointURLEnvVar)
+}
+
+// checkFIPSEndpoint returns an error if the service has no FIPS endpoint in the region
+func checkFIPSEndpoint(service, region string) error {
+	_, err := endpoints.DefaultResolver().EndpointFor(service, region, endpoints.UseFIPSEndpointOption, endpoints.StrictMatchingOption)
+	if err != nil {
+		return fmt.Errorf("%v has no FIPS endpoint in region %v: %v", service, region, err)
+	}
+	return nil
+}
+
+// RequestError is a failed EFS request. It keeps the AWS request ID, which AWS support needs to investigate the failure.
+type RequestError struct {
+	Message   string
+	RequestID string
+	Err       awserr.RequestFailure
+}
+
+func (e *RequestError) Error() string {
+	return fmt.Sprintf("%s: %s: %s (status code: %d, request ID: %s)", e.Message, e.Err.Code(), e.Err.Message(), e.Err.StatusCode(), e.RequestID)
+}
+
+func (e *RequestError) Unwrap() error {
+	return e.Err
+}
+
+// newRequestError wraps the error of an EFS request, keeping its AWS request ID when the SDK received a response
+func newRequestError(message string, err error) error {
+	reqErr, ok := err.(awserr.RequestFailure)
+	if !ok || reqErr.RequestID() == "" {
+		return fmt.Errorf("%s: %w", message, err)
+	}
+	klog.ErrorS(err, message, "requestID", reqErr.RequestID(), "statusCode", reqErr.StatusCode())
+	return &RequestError{Message: message, RequestID: reqErr.RequestID(), Err: reqErr}
+}
+
 type FileSystem struct {
-	FileSystemId string
+	FileSystemId  string
+	FileSystemArn string
+	Tags          map[string]string
+	// AvailabilityZoneName is the zone of One Zone file systems, empty for Regional file systems
+	AvailabilityZoneName string
+}
+
+type FileSystemOptions struct {
+	// Capacity is used for testing purpose only.
+	// EFS does not consider capacity while provisioning new file systems or access points
+	CapacityGiB                  int64
+	ThroughputMode               string
+	ProvisionedThroughputInMibps float64
+	Tags                         map[string]string
+	// AvailabilityZoneName creates a One Zone file system in the zone when set, a Regional file system otherwise
+	AvailabilityZoneName string
 }
 
 type AccessPoint struct {
 	AccessPointId      string
+	AccessPointArn     string
 	FileSystemId       string
 	AccessPointRootDir string
 	// Capacity is used for testing purpose only
 	// EFS does not consider capacity while provisioning new file systems or access points
 	CapacityGiB int64
 	PosixUser   *PosixUser
+	Tags        map[string]string
+	// LifeCycleState is the EFS lifecycle state of the access point, e.g. available, deleting or error
+	LifeCycleState string
 }
 
 type PosixUser struct {
 	Gid int64
 	Uid int64
+	// SecondaryGids are the secondary groups of the user, empty when it has none
+	SecondaryGids []int64
 }
 
 type AccessPointOptions struct {
@@ -77,6 +174,14 @@ type AccessPointOptions struct {
 	DirectoryPerms string
 	DirectoryPath  string
 	Tags           map[string]string
+	// OwnerUid and OwnerGid own the root directory when EFS creates it
+	OwnerUid int64
+	OwnerGid int64
+	// QuotaBytes is the size the volume should be limited to, 0 when not enforced.
+	// EFS has no quota API for access points, so it is only recorded in the QuotaBytesTagKey tag.
+	QuotaBytes int64
+	// SecondaryGids are the secondary groups of the POSIX user of the access point
+	SecondaryGids []int64
 }
 
 type MountTarget struct {
@@ -86,43 +191,84 @@ type MountTarget struct {
 	IPAddress     string
 }
 
+// ReplicationConfiguration is the replication of a file system to its destination file systems
+type ReplicationConfiguration struct {
+	SourceFileSystemId string
+	Destinations       []*ReplicationDestination
+}
+
+type ReplicationDestination struct {
+	FileSystemId string
+	Region       string
+	// Status is the EFS replication status of the destination, e.g. ENABLED, ENABLING or ERROR
+	Status string
+}
+
 // Efs abstracts efs client(https://docs.aws.amazon.com/sdk-for-go/api/service/efs/)
 type Efs interface {
 	CreateAccessPointWithContext(aws.Context, *efs.CreateAccessPointInput, ...request.Option) (*efs.CreateAccessPointOutput, error)
+	CreateFileSystemWithContext(aws.Context, *efs.CreateFileSystemInput, ...request.Option) (*efs.FileSystemDescription, error)
 	DeleteAccessPointWithContext(aws.Context, *efs.DeleteAccessPointInput, ...request.Option) (*efs.DeleteAccessPointOutput, error)
+	DeleteFileSystemWithContext(aws.Context, *efs.DeleteFileSystemInput, ...request.Option) (*efs.DeleteFileSystemOutput, error)
 	DescribeAccessPointsWithContext(aws.Context, *efs.DescribeAccessPointsInput, ...request.Option) (*efs.DescribeAccessPointsOutput, error)
 	DescribeFileSystemsWithContext(aws.Context, *efs.DescribeFileSystemsInput, ...request.Option) (*efs.DescribeFileSystemsOutput, error)
 	DescribeMountTargetsWithContext(aws.Context, *efs.DescribeMountTargetsInput, ...request.Option) (*efs.DescribeMountTargetsOutput, error)
+	DescribeReplicationConfigurationsWithContext(aws.Context, *efs.DescribeReplicationConfigurationsInput, ...request.Option) (*efs.DescribeReplicationConfigurationsOutput, error)
+	TagResourceWithContext(aws.Context, *efs.TagResourceInput, ...request.Option) (*efs.TagResourceOutput, error)
+	UntagResourceWithContext(aws.Context, *efs.UntagResourceInput, ...request.Option) (*efs.UntagResourceOutput, error)
 }
 
+// Cloud is the interface of the driver to EFS. Besides the AWS implementation returned by NewCloud,
+// it is implemented by the in-memory fake of the fake package, and by the gomock mocks of the driver tests.
 type Cloud interface {
 	GetMetadata() MetadataService
 	CreateAccessPoint(ctx context.Context, clientToken string, accessPointOpts *AccessPointOptions, reuseAccessPoint bool) (accessPoint *AccessPoint, err error)
 	DeleteAccessPoint(ctx context.Context, accessPointId string) (err error)
 	DescribeAccessPoint(ctx context.Context, accessPointId string) (accessPoint *AccessPoint, err error)
+	TagAccessPoint(ctx context.Context, accessPointId string, tags map[string]string) (err error)
+	UntagAccessPoint(ctx context.Context, accessPointId string, tagKeys []string) (err error)
 	ListAccessPoints(ctx context.Context, fileSystemId string) (accessPoints []*AccessPoint, err error)
+	FindAccessPointByClientToken(ctx context.Context, clientToken string, accessPointOpts *AccessPointOptions) (accessPoint *AccessPoint, err error)
+	CreateFileSystem(ctx context.Context, clientToken string, fileSystemOpts *FileSystemOptions) (fs *FileSystem, err error)
+	DeleteFileSystem(ctx context.Context, fileSystemId string) (err error)
 	DescribeFileSystem(ctx context.Context, fileSystemId string) (fs *FileSystem, err error)
 	DescribeMountTargets(ctx context.Context, fileSystemId, az string) (fs *MountTarget, err error)
+	ListMountTargets(ctx context.Context, fileSystemId string) (mountTargets []*MountTarget, err error)
+	DescribeReplicationConfiguration(ctx context.Context, fileSystemId string) (replication *ReplicationConfiguration, err error)
+	WithRegion(region string) (Cloud, error)
+	CheckConnectivity(ctx context.Context) (err error)
 }
 
 type cloud struct {
 	metadata MetadataService
 	efs      Efs
+	// region of the EFS client, empty for the region of the instance
+	region string
+	// newRegionalCloud creates the clouds of other regions, or returns an error if the clients cannot be created for a region
+	newRegionalCloud func(region string) (*cloud, error)
+	// limiter bounds the mutating calls in flight
+	limiter *CallLimiter
+	// fallbackEfs is the EFS client of fallbackRegion, which describe calls failing transiently are retried with
+	fallbackEfs    Efs
+	fallbackRegion string
+
+	mu             sync.Mutex
+	regionalClouds map[string]*cloud
 }
 
 // NewCloud returns a new instance of AWS cloud
 // It panics if session is invalid
-func NewCloud() (Cloud, error) {
-	return createCloud("")
+func NewCloud(opts ClientOptions) (Cloud, error) {
+	return createCloud("", opts)
 }
 
 // NewCloudWithRole returns a new instance of AWS cloud after assuming an aws role
 // It panics if driver does not have permissions to assume role.
-func NewCloudWithRole(awsRoleArn string) (Cloud, error) {
-	return createCloud(awsRoleArn)
+func NewCloudWithRole(awsRoleArn string, opts ClientOptions) (Cloud, error) {
+	return createCloud(awsRoleArn, opts)
 }
 
-func createCloud(awsRoleArn string) (Cloud, error) {
+func createCloud(awsRoleArn string, opts ClientOptions) (Cloud, error) {
 	sess := session.Must(session.NewSession(&aws.Config{}))
 	svc := ec2metadata.New(sess)
 	api, err := DefaultKubernetesAPIClient()
@@ -143,34 +289,158 @@ func createCloud(awsRoleArn string) (Cloud, error) {
 		return nil, fmt.Errorf("could not get metadata: %v", err)
 	}
 
-	efs_client := createEfsClient(awsRoleArn, metadata, sess)
-	klog.V(5).Infof("EFS Client created using the following endpoint: %+v", efs_client.(*efs.EFS).Client.ClientInfo.Endpoint)
+	if opts.CredentialsFile != "" {
+		sess = sess.Copy(&aws.Config{Credentials: newFileCredentials(opts.CredentialsFile)})
+	}
+	c, err := buildCloud(awsRoleArn, metadata, metadata.GetRegion(), sess, opts)
+	if err != nil {
+		return nil, err
+	}
+	klog.V(5).Infof("EFS Client created using the following endpoint: %+v", c.efs.(*efs.EFS).Client.ClientInfo.Endpoint)
+	return c, nil
+}
+
+// buildCloud returns the cloud of the EFS API of a region, with the client of the fallback region. The clouds of
+// WithRegion are built the same way, so that they honor the same client options.
+func buildCloud(awsRoleArn string, metadata MetadataService, region string, sess *session.Session, opts ClientOptions) (*cloud, error) {
+	if err := checkClientOptions(awsRoleArn, region, opts); err != nil {
+		return nil, err
+	}
+
+	var fallbackEfs Efs
+	if opts.FallbackRegion != "" && opts.FallbackRegion != region {
+		if !isValidRegion(opts.FallbackRegion) {
+			return nil, fmt.Errorf("invalid fallback region %q: %w", opts.FallbackRegion, ErrInvalidRegion)
+		}
+		if err := checkClientOptions(awsRoleArn, opts.FallbackRegion, opts); err != nil {
+			return nil, err
+		}
+		// The calls of the fallback region are not short-circuited when the region of the driver fails
+		fallbackOpts := opts
+		fallbackOpts.CircuitBreaker = nil
+		fallbackEfs = createEfsClient(awsRoleArn, opts.FallbackRegion, sess, fallbackOpts)
+		klog.V(2).Infof("EFS Client of region %v created for fallback region %v", region, opts.FallbackRegion)
+	}
 
 	return &cloud{
 		metadata: metadata,
-		efs:      efs_client,
+		efs:      createEfsClient(awsRoleArn, region, sess, opts),
+		newRegionalCloud: func(region string) (*cloud, error) {
+			return buildCloud(awsRoleArn, metadata, region, sess, opts)
+		},
+		limiter:        opts.CallLimiter,
+		fallbackEfs:    fallbackEfs,
+		fallbackRegion: opts.FallbackRegion,
 	}, nil
 }
 
-func createEfsClient(awsRoleArn string, metadata MetadataService, sess *session.Session) Efs {
-	config := aws.NewConfig().WithRegion(metadata.GetRegion())
+// checkClientOptions returns an error if the clients of the region cannot honor the options
+func checkClientOptions(awsRoleArn, region string, opts ClientOptions) error {
+	if endpoint := opts.endpoint(); endpoint != "" {
+		u, err := url.Parse(endpoint)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("invalid endpoint %q: expected an http or https URL", endpoint)
+		}
+		if opts.UseFIPSEndpoint {
+			return fmt.Errorf("the FIPS endpoints cannot be used with the endpoint %q", endpoint)
+		}
+		return nil
+	}
+	if !opts.UseFIPSEndpoint {
+		return nil
+	}
+	if err := checkFIPSEndpoint(efs.EndpointsID, region); err != nil {
+		return err
+	}
+	if awsRoleArn != "" {
+		return checkFIPSEndpoint(sts.EndpointsID, region)
+	}
+	return nil
+}
+
+func createEfsClient(awsRoleArn, region string, sess *session.Session, opts ClientOptions) Efs {
+	config := aws.NewConfig().WithRegion(region)
+	if opts.UseFIPSEndpoint {
+		config.UseFIPSEndpoint = endpoints.FIPSEndpointStateEnabled
+	}
+	// Requests to an overridden endpoint are still signed for the region
+	endpoint := opts.endpoint()
+	if endpoint != "" {
+		config = config.WithEndpoint(endpoint)
+	}
 	if awsRoleArn != "" {
-		config = config.WithCredentials(stscreds.NewCredentials(sess, awsRoleArn))
+		stsSess := sess
+		if opts.UseFIPSEndpoint {
+			// The FIPS endpoints of STS are regional
+			stsSess = sess.Copy(&aws.Config{Region: aws.String(region), UseFIPSEndpoint: endpoints.FIPSEndpointStateEnabled})
+		}
+		if endpoint != "" {
+			stsSess = sess.Copy(&aws.Config{Region: aws.String(region), Endpoint: aws.String(endpoint)})
+		}
+		config = config.WithCredentials(stscreds.NewCredentials(stsSess, awsRoleArn))
+	} else if opts.CredentialsFile != "" {
+		config = config.WithCredentials(sess.Config.Credentials)
 	}
-	return efs.New(session.Must(session.NewSession(config)))
+	client := efs.New(session.Must(session.NewSession(config)))
+	opts.CircuitBreaker.addHandlers(&client.Handlers)
+	return client
 }
 
 func (c *cloud) GetMetadata() MetadataService {
 	return c.metadata
 }
 
+// WithRegion returns a cloud whose EFS client targets the given region.
+// Clients are cached, so that each region is only set up once.
+func (c *cloud) WithRegion(region string) (Cloud, error) {
+	if region == c.region || (c.region == "" && c.metadata != nil && region == c.metadata.GetRegion()) {
+		return c, nil
+	}
+	if !isValidRegion(region) {
+		return nil, ErrInvalidRegion
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if regionalCloud, ok := c.regionalClouds[region]; ok {
+		return regionalCloud, nil
+	}
+	regionalCloud, err := c.newRegionalCloud(region)
+	if err != nil {
+		return nil, err
+	}
+	regionalCloud.region = region
+	if c.regionalClouds == nil {
+		c.regionalClouds = make(map[string]*cloud)
+	}
+	c.regionalClouds[region] = regionalCloud
+	klog.V(2).Infof("EFS Client created for region %v", region)
+	return regionalCloud, nil
+}
+
+func isValidRegion(region string) bool {
+	for _, partition := range endpoints.DefaultPartitions() {
+		if _, ok := partition.Regions()[region]; ok {
+			return true
+		}
+	}
+	return false
+}
+
 func (c *cloud) CreateAccessPoint(ctx context.Context, clientToken string, accessPointOpts *AccessPointOptions, reuseAccessPoint bool) (accessPoint *AccessPoint, err error) {
 	efsTags := parseEfsTags(accessPointOpts.Tags)
+	if accessPointOpts.QuotaBytes > 0 {
+		efsTags = append(efsTags, &efs.Tag{
+			Key:   aws.String(QuotaBytesTagKey),
+			Value: aws.String(strconv.FormatInt(accessPointOpts.QuotaBytes, 10)),
+		})
+	}
 
 	//if reuseAccessPoint is true, check for AP with same Root Directory exists in efs
 	// if found reuse that AP
 	if reuseAccessPoint {
-		existingAP, err := c.findAccessPointByClientToken(ctx, clientToken, accessPointOpts)
+		existingAP, err := c.FindAccessPointByClientToken(ctx, clientToken, accessPointOpts)
 		if err != nil {
 			return nil, fmt.Errorf("failed to find access point: %v", err)
 		}
@@ -178,9 +448,11 @@ func (c *cloud) CreateAccessPoint(ctx context.Context, clientToken string, acces
 			//AP path already exists
 			klog.V(2).Infof("Existing AccessPoint found : %+v", existingAP)
 			return &AccessPoint{
-				AccessPointId: existingAP.AccessPointId,
-				FileSystemId:  existingAP.FileSystemId,
-				CapacityGiB:   accessPointOpts.CapacityGiB,
+				AccessPointId:  existingAP.AccessPointId,
+				AccessPointArn: existingAP.AccessPointArn,
+				FileSystemId:   existingAP.FileSystemId,
+				CapacityGiB:    accessPointOpts.CapacityGiB,
+				PosixUser:      existingAP.PosixUser,
 			}, nil
 		}
 	}
@@ -193,8 +465,8 @@ func (c *cloud) CreateAccessPoint(ctx context.Context, clientToken string, acces
 		},
 		RootDirectory: &efs.RootDirectory{
 			CreationInfo: &efs.CreationInfo{
-				OwnerGid:    &accessPointOpts.Gid,
-				OwnerUid:    &accessPointOpts.Uid,
+				OwnerGid:    &accessPointOpts.OwnerGid,
+				OwnerUid:    &accessPointOpts.OwnerUid,
 				Permissions: &accessPointOpts.DirectoryPerms,
 			},
 			Path: &accessPointOpts.DirectoryPath,
@@ -202,32 +474,63 @@ func (c *cloud) CreateAccessPoint(ctx context.Context, clientToken string, acces
 		Tags: efsTags,
 	}
 
+	if len(accessPointOpts.SecondaryGids) > 0 {
+		createAPInput.PosixUser.SecondaryGids = aws.Int64Slice(accessPointOpts.SecondaryGids)
+	}
+
 	klog.V(5).Infof("Calling Create AP with input: %+v", *createAPInput)
+	release, err := c.limiter.acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
 	res, err := c.efs.CreateAccessPointWithContext(ctx, createAPInput)
+	release(err)
 	if err != nil {
 		if isAccessDenied(err) {
-			return nil, ErrAccessDenied
+			return nil, newError(ErrAccessDenied, err)
+		}
+		if isAccessPointAlreadyExists(err) {
+			return nil, newError(ErrAlreadyExists, err)
 		}
-		return nil, fmt.Errorf("Failed to create access point: %v", err)
+		if isFileSystemNotFound(err) {
+			return nil, newError(ErrNotFound, err)
+		}
+		return nil, newRequestError("Failed to create access point", err)
 	}
 	klog.V(5).Infof("Create AP response : %+v", res)
 
-	return &AccessPoint{
-		AccessPointId: *res.AccessPointId,
-		FileSystemId:  *res.FileSystemId,
-		CapacityGiB:   accessPointOpts.CapacityGiB,
-	}, nil
+	accessPoint = &AccessPoint{
+		AccessPointId:  *res.AccessPointId,
+		AccessPointArn: aws.StringValue(res.AccessPointArn),
+		FileSystemId:   *res.FileSystemId,
+		CapacityGiB:    accessPointOpts.CapacityGiB,
+		Tags:           parseTagsFromEfsTags(res.Tags),
+		LifeCycleState: aws.StringValue(res.LifeCycleState),
+	}
+	if res.PosixUser != nil {
+		accessPoint.PosixUser = &PosixUser{
+			Gid:           aws.Int64Value(res.PosixUser.Gid),
+			Uid:           aws.Int64Value(res.PosixUser.Uid),
+			SecondaryGids: aws.Int64ValueSlice(res.PosixUser.SecondaryGids),
+		}
+	}
+	return accessPoint, nil
 }
 
 func (c *cloud) DeleteAccessPoint(ctx context.Context, accessPointId string) (err error) {
 	deleteAccessPointInput := &efs.DeleteAccessPointInput{AccessPointId: &accessPointId}
+	release, err := c.limiter.acquire(ctx)
+	if err != nil {
+		return err
+	}
 	_, err = c.efs.DeleteAccessPointWithContext(ctx, deleteAccessPointInput)
+	release(err)
 	if err != nil {
 		if isAccessDenied(err) {
-			return ErrAccessDenied
+			return newError(ErrAccessDenied, err)
 		}
 		if isAccessPointNotFound(err) {
-			return ErrNotFound
+			return newError(ErrNotFound, err)
 		}
 		return fmt.Errorf("Failed to delete access point: %v, error: %v", accessPointId, err)
 	}
@@ -235,19 +538,72 @@ func (c *cloud) DeleteAccessPoint(ctx context.Context, accessPointId string) (er
 	return nil
 }
 
+// TagAccessPoint adds the tags to the access point, overwriting the value of existing keys
+func (c *cloud) TagAccessPoint(ctx context.Context, accessPointId string, tags map[string]string) (err error) {
+	tagResourceInput := &efs.TagResourceInput{
+		ResourceId: &accessPointId,
+		Tags:       parseEfsTags(tags),
+	}
+	release, err := c.limiter.acquire(ctx)
+	if err != nil {
+		return err
+	}
+	_, err = c.efs.TagResourceWithContext(ctx, tagResourceInput)
+	release(err)
+	if err != nil {
+		if isAccessDenied(err) {
+			return newError(ErrAccessDenied, err)
+		}
+		if isAccessPointNotFound(err) {
+			return newError(ErrNotFound, err)
+		}
+		return fmt.Errorf("Failed to tag access point: %v, error: %v", accessPointId, err)
+	}
+
+	return nil
+}
+
+// UntagAccessPoint removes the tags with the given keys from the access point
+func (c *cloud) UntagAccessPoint(ctx context.Context, accessPointId string, tagKeys []string) (err error) {
+	untagResourceInput := &efs.UntagResourceInput{
+		ResourceId: &accessPointId,
+		TagKeys:    aws.StringSlice(tagKeys),
+	}
+	release, err := c.limiter.acquire(ctx)
+	if err != nil {
+		return err
+	}
+	_, err = c.efs.UntagResourceWithContext(ctx, untagResourceInput)
+	release(err)
+	if err != nil {
+		if isAccessDenied(err) {
+			return newError(ErrAccessDenied, err)
+		}
+		if isAccessPointNotFound(err) {
+			return newError(ErrNotFound, err)
+		}
+		return fmt.Errorf("Failed to untag access point: %v, error: %v", accessPointId, err)
+	}
+
+	return nil
+}
+
 func (c *cloud) DescribeAccessPoint(ctx context.Context, accessPointId string) (accessPoint *AccessPoint, err error) {
 	describeAPInput := &efs.DescribeAccessPointsInput{
 		AccessPointId: &accessPointId,
 	}
 	res, err := c.efs.DescribeAccessPointsWithContext(ctx, describeAPInput)
+	if c.shouldFailOver(err) {
+		res, err = c.failOverDescribeAccessPoints(ctx, describeAPInput, err)
+	}
 	if err != nil {
 		if isAccessDenied(err) {
-			return nil, ErrAccessDenied
+			return nil, newError(ErrAccessDenied, err)
 		}
 		if isAccessPointNotFound(err) {
-			return nil, ErrNotFound
+			return nil, newError(ErrNotFound, err)
 		}
-		return nil, fmt.Errorf("Describe Access Point failed: %v", err)
+		return nil, newRequestError("Describe Access Point failed", err)
 	}
 
 	accessPoints := res.AccessPoints
@@ -255,14 +611,26 @@ func (c *cloud) DescribeAccessPoint(ctx context.Context, accessPointId string) (
 		return nil, fmt.Errorf("DescribeAccessPoint failed. Expected exactly 1 access point in DescribeAccessPoint result. However, recevied %d access points", len(accessPoints))
 	}
 
-	return &AccessPoint{
+	accessPoint = &AccessPoint{
 		AccessPointId:      *accessPoints[0].AccessPointId,
+		AccessPointArn:     aws.StringValue(accessPoints[0].AccessPointArn),
 		FileSystemId:       *accessPoints[0].FileSystemId,
 		AccessPointRootDir: *accessPoints[0].RootDirectory.Path,
-	}, nil
+		Tags:               parseTagsFromEfsTags(accessPoints[0].Tags),
+		LifeCycleState:     aws.StringValue(accessPoints[0].LifeCycleState),
+	}
+	// DeleteVolume releases the GID of the POSIX user
+	if posixUser := accessPoints[0].PosixUser; posixUser != nil {
+		accessPoint.PosixUser = &PosixUser{
+			Gid:           aws.Int64Value(posixUser.Gid),
+			Uid:           aws.Int64Value(posixUser.Uid),
+			SecondaryGids: aws.Int64ValueSlice(posixUser.SecondaryGids),
+		}
+	}
+	return accessPoint, nil
 }
 
-func (c *cloud) findAccessPointByClientToken(ctx context.Context, clientToken string, accessPointOpts *AccessPointOptions) (accessPoint *AccessPoint, err error) {
+func (c *cloud) FindAccessPointByClientToken(ctx context.Context, clientToken string, accessPointOpts *AccessPointOptions) (accessPoint *AccessPoint, err error) {
 	klog.V(5).Infof("AccessPointOptions to find AP : %+v", accessPointOpts)
 	klog.V(2).Infof("ClientToken to find AP : %s", clientToken)
 	describeAPInput := &efs.DescribeAccessPointsInput{
@@ -283,11 +651,20 @@ func (c *cloud) findAccessPointByClientToken(ctx context.Context, clientToken st
 	for _, ap := range res.AccessPoints {
 		// check if AP exists with same client token
 		if aws.StringValue(ap.ClientToken) == clientToken {
-			return &AccessPoint{
+			accessPoint = &AccessPoint{
 				AccessPointId:      *ap.AccessPointId,
+				AccessPointArn:     aws.StringValue(ap.AccessPointArn),
 				FileSystemId:       *ap.FileSystemId,
 				AccessPointRootDir: *ap.RootDirectory.Path,
-			}, nil
+			}
+			if ap.PosixUser != nil {
+				accessPoint.PosixUser = &PosixUser{
+					Gid:           aws.Int64Value(ap.PosixUser.Gid),
+					Uid:           aws.Int64Value(ap.PosixUser.Uid),
+					SecondaryGids: aws.Int64ValueSlice(ap.PosixUser.SecondaryGids),
+				}
+			}
+			return accessPoint, nil
 		}
 	}
 	klog.V(2).Infof("Access point does not exist")
@@ -302,10 +679,10 @@ func (c *cloud) ListAccessPoints(ctx context.Context, fileSystemId string) (acce
 	res, err := c.efs.DescribeAccessPointsWithContext(ctx, describeAPInput)
 	if err != nil {
 		if isAccessDenied(err) {
-			return nil, ErrAccessDenied
+			return nil, newError(ErrAccessDenied, err)
 		}
 		if isFileSystemNotFound(err) {
-			return nil, ErrNotFound
+			return nil, newError(ErrNotFound, err)
 		}
 		err = fmt.Errorf("List Access Points failed: %v", err)
 		return
@@ -322,9 +699,14 @@ func (c *cloud) ListAccessPoints(ctx context.Context, fileSystemId string) (acce
 			posixUser = nil
 		}
 		accessPoint := &AccessPoint{
-			AccessPointId: *accessPointDescription.AccessPointId,
-			FileSystemId:  *accessPointDescription.FileSystemId,
-			PosixUser:     posixUser,
+			AccessPointId:  *accessPointDescription.AccessPointId,
+			AccessPointArn: aws.StringValue(accessPointDescription.AccessPointArn),
+			FileSystemId:   *accessPointDescription.FileSystemId,
+			PosixUser:      posixUser,
+			Tags:           parseTagsFromEfsTags(accessPointDescription.Tags),
+		}
+		if accessPointDescription.RootDirectory != nil {
+			accessPoint.AccessPointRootDir = aws.StringValue(accessPointDescription.RootDirectory.Path)
 		}
 		accessPoints = append(accessPoints, accessPoint)
 	}
@@ -336,14 +718,17 @@ func (c *cloud) DescribeFileSystem(ctx context.Context, fileSystemId string) (fs
 	describeFsInput := &efs.DescribeFileSystemsInput{FileSystemId: &fileSystemId}
 	klog.V(5).Infof("Calling DescribeFileSystems with input: %+v", *describeFsInput)
 	res, err := c.efs.DescribeFileSystemsWithContext(ctx, describeFsInput)
+	if c.shouldFailOver(err) {
+		res, err = c.failOverDescribeFileSystems(ctx, describeFsInput, err)
+	}
 	if err != nil {
 		if isAccessDenied(err) {
-			return nil, ErrAccessDenied
+			return nil, newError(ErrAccessDenied, err)
 		}
 		if isFileSystemNotFound(err) {
-			return nil, ErrNotFound
+			return nil, newError(ErrNotFound, err)
 		}
-		return nil, fmt.Errorf("Describe File System failed: %v", err)
+		return nil, newRequestError("Describe File System failed", err)
 	}
 
 	fileSystems := res.FileSystems
@@ -351,20 +736,119 @@ func (c *cloud) DescribeFileSystem(ctx context.Context, fileSystemId string) (fs
 		return nil, fmt.Errorf("DescribeFileSystem failed. Expected exactly 1 file system in DescribeFileSystem result. However, recevied %d file systems", len(fileSystems))
 	}
 	return &FileSystem{
-		FileSystemId: *res.FileSystems[0].FileSystemId,
+		FileSystemId:         *res.FileSystems[0].FileSystemId,
+		FileSystemArn:        aws.StringValue(res.FileSystems[0].FileSystemArn),
+		Tags:                 parseTagsFromEfsTags(res.FileSystems[0].Tags),
+		AvailabilityZoneName: aws.StringValue(res.FileSystems[0].AvailabilityZoneName),
 	}, nil
 }
 
+// DescribeReplicationConfiguration returns the replication configuration of a file system, which may be the source or a
+// destination of the replication. It fails with ErrNotFound when the file system is not replicated.
+func (c *cloud) DescribeReplicationConfiguration(ctx context.Context, fileSystemId string) (*ReplicationConfiguration, error) {
+	describeReplicationInput := &efs.DescribeReplicationConfigurationsInput{FileSystemId: &fileSystemId}
+	klog.V(5).Infof("Calling DescribeReplicationConfigurations with input: %+v", *describeReplicationInput)
+	res, err := c.efs.DescribeReplicationConfigurationsWithContext(ctx, describeReplicationInput)
+	if err != nil {
+		if isAccessDenied(err) {
+			return nil, newError(ErrAccessDenied, err)
+		}
+		if isFileSystemNotFound(err) || isReplicationNotFound(err) {
+			return nil, newError(ErrNotFound, err)
+		}
+		return nil, newRequestError("Describe Replication Configurations failed", err)
+	}
+
+	if len(res.Replications) == 0 {
+		return nil, ErrNotFound
+	}
+	replication := &ReplicationConfiguration{
+		SourceFileSystemId: aws.StringValue(res.Replications[0].SourceFileSystemId),
+		Destinations:       []*ReplicationDestination{},
+	}
+	for _, destination := range res.Replications[0].Destinations {
+		replication.Destinations = append(replication.Destinations, &ReplicationDestination{
+			FileSystemId: aws.StringValue(destination.FileSystemId),
+			Region:       aws.StringValue(destination.Region),
+			Status:       aws.StringValue(destination.Status),
+		})
+	}
+	return replication, nil
+}
+
+func (c *cloud) CreateFileSystem(ctx context.Context, clientToken string, fileSystemOpts *FileSystemOptions) (fs *FileSystem, err error) {
+	createFsInput := &efs.CreateFileSystemInput{
+		CreationToken: &clientToken,
+		Encrypted:     aws.Bool(true),
+		Tags:          parseEfsTags(fileSystemOpts.Tags),
+	}
+	if fileSystemOpts.ThroughputMode != "" {
+		createFsInput.ThroughputMode = aws.String(fileSystemOpts.ThroughputMode)
+	}
+	if fileSystemOpts.ProvisionedThroughputInMibps > 0 {
+		createFsInput.ProvisionedThroughputInMibps = aws.Float64(fileSystemOpts.ProvisionedThroughputInMibps)
+	}
+	if fileSystemOpts.AvailabilityZoneName != "" {
+		createFsInput.AvailabilityZoneName = aws.String(fileSystemOpts.AvailabilityZoneName)
+	}
+
+	klog.V(5).Infof("Calling CreateFileSystem with input: %+v", *createFsInput)
+	release, err := c.limiter.acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	res, err := c.efs.CreateFileSystemWithContext(ctx, createFsInput)
+	release(err)
+	if err != nil {
+		if isAccessDenied(err) {
+			return nil, newError(ErrAccessDenied, err)
+		}
+		if isFileSystemAlreadyExists(err) {
+			return nil, newError(ErrAlreadyExists, err)
+		}
+		return nil, fmt.Errorf("Failed to create file system: %v", err)
+	}
+	klog.V(5).Infof("Create FS response : %+v", res)
+
+	return &FileSystem{
+		FileSystemId:         *res.FileSystemId,
+		FileSystemArn:        aws.StringValue(res.FileSystemArn),
+		Tags:                 parseTagsFromEfsTags(res.Tags),
+		AvailabilityZoneName: aws.StringValue(res.AvailabilityZoneName),
+	}, nil
+}
+
+func (c *cloud) DeleteFileSystem(ctx context.Context, fileSystemId string) (err error) {
+	deleteFsInput := &efs.DeleteFileSystemInput{FileSystemId: &fileSystemId}
+	release, err := c.limiter.acquire(ctx)
+	if err != nil {
+		return err
+	}
+	_, err = c.efs.DeleteFileSystemWithContext(ctx, deleteFsInput)
+	release(err)
+	if err != nil {
+		if isAccessDenied(err) {
+			return newError(ErrAccessDenied, err)
+		}
+		if isFileSystemNotFound(err) {
+			return newError(ErrNotFound, err)
+		}
+		return fmt.Errorf("Failed to delete file system: %v, error: %v", fileSystemId, err)
+	}
+
+	return nil
+}
+
 func (c *cloud) DescribeMountTargets(ctx context.Context, fileSystemId, azName string) (fs *MountTarget, err error) {
 	describeMtInput := &efs.DescribeMountTargetsInput{FileSystemId: &fileSystemId}
 	klog.V(5).Infof("Calling DescribeMountTargets with input: %+v", *describeMtInput)
 	res, err := c.efs.DescribeMountTargetsWithContext(ctx, describeMtInput)
 	if err != nil {
 		if isAccessDenied(err) {
-			return nil, ErrAccessDenied
+			return nil, newError(ErrAccessDenied, err)
 		}
 		if isFileSystemNotFound(err) {
-			return nil, ErrNotFound
+			return nil, newError(ErrNotFound, err)
 		}
 		return nil, fmt.Errorf("Describe Mount Targets failed: %v", err)
 	}
@@ -401,6 +885,19 @@ func (c *cloud) DescribeMountTargets(ctx context.Context, fileSystemId, azName s
 	}, nil
 }
 
+// CheckConnectivity verifies that the EFS API is reachable with valid credentials, listing at most one file system
+func (c *cloud) CheckConnectivity(ctx context.Context) (err error) {
+	describeFsInput := &efs.DescribeFileSystemsInput{MaxItems: aws.Int64(1)}
+	_, err = c.efs.DescribeFileSystemsWithContext(ctx, describeFsInput)
+	if err != nil {
+		if isAccessDenied(err) {
+			return newError(ErrAccessDenied, err)
+		}
+		return newRequestError("Describe File Systems failed", err)
+	}
+	return nil
+}
+
 func isFileSystemNotFound(err error) bool {
 	if awsErr, ok := err.(awserr.Error); ok {
 		if awsErr.Code() == efs.ErrCodeFileSystemNotFound {
@@ -410,6 +907,15 @@ func isFileSystemNotFound(err error) bool {
 	return false
 }
 
+func isReplicationNotFound(err error) bool {
+	if awsErr, ok := err.(awserr.Error); ok {
+		if awsErr.Code() == efs.ErrCodeReplicationNotFound {
+			return true
+		}
+	}
+	return false
+}
+
 func isAccessPointNotFound(err error) bool {
 	if awsErr, ok := err.(awserr.Error); ok {
 		if awsErr.Code() == efs.ErrCodeAccessPointNotFound {
@@ -419,6 +925,24 @@ func isAccessPointNotFound(err error) bool {
 	return false
 }
 
+func isAccessPointAlreadyExists(err error) bool {
+	if awsErr, ok := err.(awserr.Error); ok {
+		if awsErr.Code() == AccessPointAlreadyExists {
+			return true
+		}
+	}
+	return false
+}
+
+func isFileSystemAlreadyExists(err error) bool {
+	if awsErr, ok := err.(awserr.Error); ok {
+		if awsErr.Code() == efs.ErrCodeFileSystemAlreadyExists {
+			return true
+		}
+	}
+	return false
+}
+
 func isAccessDenied(err error) bool {
 	if awsErr, ok := err.(awserr.Error); ok {
 		if awsErr.Code() == AccessDeniedException {
@@ -446,6 +970,41 @@ func parseEfsTags(tagMap map[string]string) []*efs.Tag {
 	return efsTags
 }
 
+func parseTagsFromEfsTags(efsTags []*efs.Tag) map[string]string {
+	tags := make(map[string]string)
+	for _, tag := range efsTags {
+		tags[aws.StringValue(tag.Key)] = aws.StringValue(tag.Value)
+	}
+	return tags
+}
+
+// ListMountTargets returns the available mount targets of a file system
+func (c *cloud) ListMountTargets(ctx context.Context, fileSystemId string) (mountTargets []*MountTarget, err error) {
+	describeMtInput := &efs.DescribeMountTargetsInput{FileSystemId: &fileSystemId}
+	klog.V(5).Infof("Calling DescribeMountTargets with input: %+v", *describeMtInput)
+	res, err := c.efs.DescribeMountTargetsWithContext(ctx, describeMtInput)
+	if err != nil {
+		if isAccessDenied(err) {
+			return nil, newError(ErrAccessDenied, err)
+		}
+		if isFileSystemNotFound(err) {
+			return nil, newError(ErrNotFound, err)
+		}
+		return nil, fmt.Errorf("Describe Mount Targets failed: %v", err)
+	}
+
+	mountTargets = []*MountTarget{}
+	for _, mountTarget := range getAvailableMountTargets(res.MountTargets) {
+		mountTargets = append(mountTargets, &MountTarget{
+			AZName:        aws.StringValue(mountTarget.AvailabilityZoneName),
+			AZId:          aws.StringValue(mountTarget.AvailabilityZoneId),
+			MountTargetId: aws.StringValue(mountTarget.MountTargetId),
+			IPAddress:     aws.StringValue(mountTarget.IpAddress),
+		})
+	}
+	return mountTargets, nil
+}
+
 func getAvailableMountTargets(mountTargets []*efs.MountTargetDescription) []*efs.MountTargetDescription {
 	availableMountTargets := []*efs.MountTargetDescription{}
 	for _, mt := range mountTargets {
@@ -4,10 +4,13 @@ import (
 	"context"
 	"errors"
 	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/efs"
 	"github.com/golang/mock/gomock"
 	"github.com/kubernetes-sigs/aws-efs-csi-driver/pkg/cloud/mocks"
@@ -95,12 +98,54 @@ func TestCreateAccessPoint(t *testing.T) {
 					t.Fatalf("AccessPointId mismatched. Expected: %v, Actual: %v", accessPointId, res.AccessPointId)
 				}
 
+				if arn != res.AccessPointArn {
+					t.Fatalf("AccessPointArn mismatched. Expected: %v, Actual: %v", arn, res.AccessPointArn)
+				}
+
 				if fsId != res.FileSystemId {
 					t.Fatalf("FileSystemId mismatched. Expected: %v, Actual: %v", fsId, res.FileSystemId)
 				}
 				mockCtl.Finish()
 			},
 		},
+		{
+			name: "Success - Secondary GIDs are forwarded",
+			testFunc: func(t *testing.T) {
+				mockCtl := gomock.NewController(t)
+				mockEfs := mocks.NewMockEfs(mockCtl)
+				c := &cloud{
+					efs: mockEfs,
+				}
+
+				req := &AccessPointOptions{
+					FileSystemId:   fsId,
+					Uid:            uid,
+					Gid:            gid,
+					DirectoryPerms: directoryPerms,
+					DirectoryPath:  directoryPath,
+					SecondaryGids:  []int64{2000, 2001},
+				}
+
+				output := &efs.CreateAccessPointOutput{
+					AccessPointArn: aws.String(arn),
+					AccessPointId:  aws.String(accessPointId),
+					FileSystemId:   aws.String(fsId),
+				}
+
+				ctx := context.Background()
+				mockEfs.EXPECT().CreateAccessPointWithContext(gomock.Eq(ctx), gomock.Any()).DoAndReturn(
+					func(ctx context.Context, input *efs.CreateAccessPointInput, opts ...request.Option) (*efs.CreateAccessPointOutput, error) {
+						if secondaryGids := aws.Int64ValueSlice(input.PosixUser.SecondaryGids); !reflect.DeepEqual(secondaryGids, req.SecondaryGids) {
+							t.Fatalf("SecondaryGids mismatched. Expected: %v, Actual: %v", req.SecondaryGids, secondaryGids)
+						}
+						return output, nil
+					})
+				if _, err := c.CreateAccessPoint(ctx, clientToken, req, false); err != nil {
+					t.Fatalf("CreateAccessPoint failed: %v", err)
+				}
+				mockCtl.Finish()
+			},
+		},
 		{
 			name: "Success - AP already exists",
 			testFunc: func(t *testing.T) {
@@ -199,12 +244,133 @@ func TestCreateAccessPoint(t *testing.T) {
 				if err == nil {
 					t.Fatalf("CreateAccessPoint did not fail")
 				}
-				if err != ErrAccessDenied {
+				if !errors.Is(err, ErrAccessDenied) {
 					t.Fatalf("Failed. Expected: %v, Actual:%v", ErrAccessDenied, err)
 				}
 				mockCtl.Finish()
 			},
 		},
+		{
+			name: "Fail: Access Point already exists with different parameters",
+			testFunc: func(t *testing.T) {
+				mockCtl := gomock.NewController(t)
+				mockEfs := mocks.NewMockEfs(mockCtl)
+				c := &cloud{efs: mockEfs}
+
+				req := &AccessPointOptions{
+					FileSystemId:   fsId,
+					Uid:            uid,
+					Gid:            gid,
+					DirectoryPerms: directoryPerms,
+					DirectoryPath:  directoryPath,
+				}
+
+				ctx := context.Background()
+				mockEfs.EXPECT().CreateAccessPointWithContext(gomock.Eq(ctx), gomock.Any()).Return(nil, awserr.New(AccessPointAlreadyExists, "Access Point already exists", errors.New("Access Point already exists")))
+				_, err := c.CreateAccessPoint(ctx, clientToken, req, false)
+				if !errors.Is(err, ErrAlreadyExists) {
+					t.Fatalf("Failed. Expected: %v, Actual:%v", ErrAlreadyExists, err)
+				}
+				mockCtl.Finish()
+			},
+		},
+		{
+			name: "Fail: File system not found",
+			testFunc: func(t *testing.T) {
+				mockCtl := gomock.NewController(t)
+				mockEfs := mocks.NewMockEfs(mockCtl)
+				c := &cloud{efs: mockEfs}
+
+				req := &AccessPointOptions{
+					FileSystemId:   fsId,
+					Uid:            uid,
+					Gid:            gid,
+					DirectoryPerms: directoryPerms,
+					DirectoryPath:  directoryPath,
+				}
+
+				ctx := context.Background()
+				mockEfs.EXPECT().CreateAccessPointWithContext(gomock.Eq(ctx), gomock.Any()).Return(nil, awserr.New(efs.ErrCodeFileSystemNotFound, "File System not found", errors.New("File System not found")))
+				_, err := c.CreateAccessPoint(ctx, clientToken, req, false)
+				if !errors.Is(err, ErrNotFound) {
+					t.Fatalf("Failed. Expected: %v, Actual:%v", ErrNotFound, err)
+				}
+				mockCtl.Finish()
+			},
+		},
+		{
+			name: "Success - Quota is recorded as a tag",
+			testFunc: func(t *testing.T) {
+				mockCtl := gomock.NewController(t)
+				mockEfs := mocks.NewMockEfs(mockCtl)
+				c := &cloud{efs: mockEfs}
+
+				req := &AccessPointOptions{
+					FileSystemId:   fsId,
+					Uid:            uid,
+					Gid:            gid,
+					DirectoryPerms: directoryPerms,
+					DirectoryPath:  directoryPath,
+					Tags:           map[string]string{"cluster": "efs"},
+					QuotaBytes:     5368709120,
+				}
+
+				output := &efs.CreateAccessPointOutput{
+					AccessPointId: aws.String(accessPointId),
+					FileSystemId:  aws.String(fsId),
+				}
+
+				ctx := context.Background()
+				mockEfs.EXPECT().CreateAccessPointWithContext(gomock.Eq(ctx), gomock.Any()).DoAndReturn(
+					func(ctx context.Context, input *efs.CreateAccessPointInput, opts ...request.Option) (*efs.CreateAccessPointOutput, error) {
+						tags := parseTagsFromEfsTags(input.Tags)
+						if tags[QuotaBytesTagKey] != "5368709120" {
+							t.Fatalf("Quota tag mismatched. Expected: %v, Actual: %v", "5368709120", tags[QuotaBytesTagKey])
+						}
+						if tags["cluster"] != "efs" {
+							t.Fatalf("Tags mismatched. Expected cluster tag in %v", tags)
+						}
+						return output, nil
+					})
+				_, err := c.CreateAccessPoint(ctx, clientToken, req, false)
+				if err != nil {
+					t.Fatalf("CreateAccessPoint failed: %v", err)
+				}
+				mockCtl.Finish()
+			},
+		},
+		{
+			name: "Fail: Request ID is preserved",
+			testFunc: func(t *testing.T) {
+				mockCtl := gomock.NewController(t)
+				mockEfs := mocks.NewMockEfs(mockCtl)
+				c := &cloud{efs: mockEfs}
+
+				req := &AccessPointOptions{
+					FileSystemId:   fsId,
+					Uid:            uid,
+					Gid:            gid,
+					DirectoryPerms: directoryPerms,
+					DirectoryPath:  directoryPath,
+				}
+
+				ctx := context.Background()
+				awsErr := awserr.NewRequestFailure(awserr.New(efs.ErrCodeInternalServerError, "Internal Server Error", nil), 500, "c3b5bd2e-6a5e-4b0b-9d1a-1c0f7d6e2a51")
+				mockEfs.EXPECT().CreateAccessPointWithContext(gomock.Eq(ctx), gomock.Any()).Return(nil, awsErr)
+				_, err := c.CreateAccessPoint(ctx, clientToken, req, false)
+				var reqErr *RequestError
+				if !errors.As(err, &reqErr) {
+					t.Fatalf("Expected a RequestError, got: %v", err)
+				}
+				if reqErr.RequestID != "c3b5bd2e-6a5e-4b0b-9d1a-1c0f7d6e2a51" {
+					t.Fatalf("Request ID mismatched. Expected: %v, Actual: %v", "c3b5bd2e-6a5e-4b0b-9d1a-1c0f7d6e2a51", reqErr.RequestID)
+				}
+				if !strings.Contains(err.Error(), "c3b5bd2e-6a5e-4b0b-9d1a-1c0f7d6e2a51") {
+					t.Fatalf("Error message does not contain the request ID: %v", err)
+				}
+				mockCtl.Finish()
+			},
+		},
 	}
 
 	for _, tc := range testCases {
@@ -251,7 +417,7 @@ func TestDeleteAccessPoint(t *testing.T) {
 				if err == nil {
 					t.Fatalf("DeleteAccessPoint did not fail")
 				}
-				if err != ErrNotFound {
+				if !errors.Is(err, ErrNotFound) {
 					t.Fatalf("Failed. Expected: %v, Actual:%v", ErrNotFound, err)
 				}
 				mockctl.Finish()
@@ -270,7 +436,7 @@ func TestDeleteAccessPoint(t *testing.T) {
 				if err == nil {
 					t.Fatalf("DeleteAccessPoint did not fail")
 				}
-				if err != ErrAccessDenied {
+				if !errors.Is(err, ErrAccessDenied) {
 					t.Fatalf("Failed. Expected: %v, Actual:%v", ErrAccessDenied, err)
 				}
 				mockctl.Finish()
@@ -294,7 +460,419 @@ func TestDeleteAccessPoint(t *testing.T) {
 		},
 	}
 	for _, tc := range testCases {
-		t.Run(tc.name, tc.testFunc)
+		t.Run(tc.name, tc.testFunc)
+	}
+}
+
+func TestTagAccessPoint(t *testing.T) {
+	var (
+		accessPointId = "fsap-abcd1234xyz987"
+		tags          = map[string]string{"key": "value"}
+	)
+	testCases := []struct {
+		name     string
+		testFunc func(t *testing.T)
+	}{
+		{
+			name: "Success",
+			testFunc: func(t *testing.T) {
+				mockctl := gomock.NewController(t)
+				mockEfs := mocks.NewMockEfs(mockctl)
+				c := &cloud{efs: mockEfs}
+
+				output := &efs.TagResourceOutput{}
+				ctx := context.Background()
+				mockEfs.EXPECT().TagResourceWithContext(gomock.Eq(ctx), gomock.Any()).Return(output, nil).
+					Do(func(ctx context.Context, input *efs.TagResourceInput, opts ...request.Option) {
+						if aws.StringValue(input.ResourceId) != accessPointId {
+							t.Fatalf("Resource Id mismatched. Expected: %v, Actual: %v", accessPointId, aws.StringValue(input.ResourceId))
+						}
+						if len(input.Tags) != 1 || aws.StringValue(input.Tags[0].Key) != "key" || aws.StringValue(input.Tags[0].Value) != "value" {
+							t.Fatalf("Tags mismatched: %v", input.Tags)
+						}
+					})
+				err := c.TagAccessPoint(ctx, accessPointId, tags)
+				if err != nil {
+					t.Fatalf("Tag Access Point failed: %v", err)
+				}
+
+				mockctl.Finish()
+			},
+		},
+		{
+			name: "Fail: Access Point Not Found",
+			testFunc: func(t *testing.T) {
+				mockctl := gomock.NewController(t)
+				mockEfs := mocks.NewMockEfs(mockctl)
+				c := &cloud{efs: mockEfs}
+
+				ctx := context.Background()
+				mockEfs.EXPECT().TagResourceWithContext(gomock.Eq(ctx), gomock.Any()).Return(nil, awserr.New(efs.ErrCodeAccessPointNotFound, "Access Point not found", errors.New("TagResourceWithContext failed")))
+				err := c.TagAccessPoint(ctx, accessPointId, tags)
+				if !errors.Is(err, ErrNotFound) {
+					t.Fatalf("Failed. Expected: %v, Actual:%v", ErrNotFound, err)
+				}
+				mockctl.Finish()
+			},
+		},
+		{
+			name: "Fail: Access Denied",
+			testFunc: func(t *testing.T) {
+				mockctl := gomock.NewController(t)
+				mockEfs := mocks.NewMockEfs(mockctl)
+				c := &cloud{efs: mockEfs}
+
+				ctx := context.Background()
+				mockEfs.EXPECT().TagResourceWithContext(gomock.Eq(ctx), gomock.Any()).Return(nil, awserr.New(AccessDeniedException, "Access Denied", errors.New("Access Denied")))
+				err := c.TagAccessPoint(ctx, accessPointId, tags)
+				if !errors.Is(err, ErrAccessDenied) {
+					t.Fatalf("Failed. Expected: %v, Actual:%v", ErrAccessDenied, err)
+				}
+				mockctl.Finish()
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, tc.testFunc)
+	}
+}
+
+func TestUntagAccessPoint(t *testing.T) {
+	var (
+		accessPointId = "fsap-abcd1234xyz987"
+		tagKeys       = []string{"key"}
+	)
+	testCases := []struct {
+		name     string
+		testFunc func(t *testing.T)
+	}{
+		{
+			name: "Success",
+			testFunc: func(t *testing.T) {
+				mockctl := gomock.NewController(t)
+				mockEfs := mocks.NewMockEfs(mockctl)
+				c := &cloud{efs: mockEfs}
+
+				output := &efs.UntagResourceOutput{}
+				ctx := context.Background()
+				mockEfs.EXPECT().UntagResourceWithContext(gomock.Eq(ctx), gomock.Eq(&efs.UntagResourceInput{
+					ResourceId: aws.String(accessPointId),
+					TagKeys:    aws.StringSlice(tagKeys),
+				})).Return(output, nil)
+				err := c.UntagAccessPoint(ctx, accessPointId, tagKeys)
+				if err != nil {
+					t.Fatalf("Untag Access Point failed: %v", err)
+				}
+
+				mockctl.Finish()
+			},
+		},
+		{
+			name: "Fail: Access Point Not Found",
+			testFunc: func(t *testing.T) {
+				mockctl := gomock.NewController(t)
+				mockEfs := mocks.NewMockEfs(mockctl)
+				c := &cloud{efs: mockEfs}
+
+				ctx := context.Background()
+				mockEfs.EXPECT().UntagResourceWithContext(gomock.Eq(ctx), gomock.Any()).Return(nil, awserr.New(efs.ErrCodeAccessPointNotFound, "Access Point not found", errors.New("UntagResourceWithContext failed")))
+				err := c.UntagAccessPoint(ctx, accessPointId, tagKeys)
+				if !errors.Is(err, ErrNotFound) {
+					t.Fatalf("Failed. Expected: %v, Actual:%v", ErrNotFound, err)
+				}
+				mockctl.Finish()
+			},
+		},
+		{
+			name: "Fail: Access Denied",
+			testFunc: func(t *testing.T) {
+				mockctl := gomock.NewController(t)
+				mockEfs := mocks.NewMockEfs(mockctl)
+				c := &cloud{efs: mockEfs}
+
+				ctx := context.Background()
+				mockEfs.EXPECT().UntagResourceWithContext(gomock.Eq(ctx), gomock.Any()).Return(nil, awserr.New(AccessDeniedException, "Access Denied", errors.New("Access Denied")))
+				err := c.UntagAccessPoint(ctx, accessPointId, tagKeys)
+				if !errors.Is(err, ErrAccessDenied) {
+					t.Fatalf("Failed. Expected: %v, Actual:%v", ErrAccessDenied, err)
+				}
+				mockctl.Finish()
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, tc.testFunc)
+	}
+}
+
+func TestWithRegion(t *testing.T) {
+	testCases := []struct {
+		name     string
+		testFunc func(t *testing.T)
+	}{
+		{
+			name: "Success: Regional client is created once and cached",
+			testFunc: func(t *testing.T) {
+				mockctl := gomock.NewController(t)
+				mockEfs := mocks.NewMockEfs(mockctl)
+				regionalEfs := map[string]*mocks.MockEfs{
+					"eu-west-1":      mocks.NewMockEfs(mockctl),
+					"ap-southeast-2": mocks.NewMockEfs(mockctl),
+				}
+				created := map[string]int{}
+				c := &cloud{
+					metadata: &metadata{"instanceID", "us-east-1", "us-east-1a"},
+					efs:      mockEfs,
+					newRegionalCloud: func(region string) (*cloud, error) {
+						created[region]++
+						return &cloud{efs: regionalEfs[region]}, nil
+					},
+				}
+
+				for _, region := range []string{"eu-west-1", "ap-southeast-2", "eu-west-1"} {
+					regionalCloud, err := c.WithRegion(region)
+					if err != nil {
+						t.Fatalf("WithRegion failed: %v", err)
+					}
+					if regionalCloud.(*cloud).efs != regionalEfs[region] {
+						t.Fatalf("WithRegion returned the client of another region for %v", region)
+					}
+				}
+				if created["eu-west-1"] != 1 || created["ap-southeast-2"] != 1 {
+					t.Fatalf("Regional clients were not cached: %v", created)
+				}
+				mockctl.Finish()
+			},
+		},
+		{
+			name: "Success: Region of the instance uses the default client",
+			testFunc: func(t *testing.T) {
+				mockctl := gomock.NewController(t)
+				mockEfs := mocks.NewMockEfs(mockctl)
+				c := &cloud{
+					metadata: &metadata{"instanceID", "us-east-1", "us-east-1a"},
+					efs:      mockEfs,
+					newRegionalCloud: func(region string) (*cloud, error) {
+						t.Fatalf("Unexpected client creation for region %v", region)
+						return nil, nil
+					},
+				}
+
+				regionalCloud, err := c.WithRegion("us-east-1")
+				if err != nil {
+					t.Fatalf("WithRegion failed: %v", err)
+				}
+				if regionalCloud != c {
+					t.Fatalf("WithRegion did not return the default cloud")
+				}
+				mockctl.Finish()
+			},
+		},
+		{
+			name: "Fail: Unknown region",
+			testFunc: func(t *testing.T) {
+				mockctl := gomock.NewController(t)
+				mockEfs := mocks.NewMockEfs(mockctl)
+				c := &cloud{
+					metadata: &metadata{"instanceID", "us-east-1", "us-east-1a"},
+					efs:      mockEfs,
+				}
+
+				_, err := c.WithRegion("moon-east-1")
+				if err != ErrInvalidRegion {
+					t.Fatalf("Failed. Expected: %v, Actual:%v", ErrInvalidRegion, err)
+				}
+				mockctl.Finish()
+			},
+		},
+		{
+			name: "Fail: Region without a FIPS endpoint",
+			testFunc: func(t *testing.T) {
+				mockctl := gomock.NewController(t)
+				mockEfs := mocks.NewMockEfs(mockctl)
+				c := &cloud{
+					metadata: &metadata{"instanceID", "us-east-1", "us-east-1a"},
+					efs:      mockEfs,
+					newRegionalCloud: func(region string) (*cloud, error) {
+						return buildCloud("", nil, region, session.Must(session.NewSession()), ClientOptions{UseFIPSEndpoint: true})
+					},
+				}
+
+				_, err := c.WithRegion("il-central-1")
+				if err == nil {
+					t.Fatal("WithRegion did not fail")
+				}
+				if _, ok := c.regionalClouds["il-central-1"]; ok {
+					t.Fatal("WithRegion cached the failed regional cloud")
+				}
+				mockctl.Finish()
+			},
+		},
+		{
+			name: "Success: Regional cloud honors the client options",
+			testFunc: func(t *testing.T) {
+				opts := ClientOptions{Endpoint: "https://efs.example.com", FallbackRegion: "us-east-1"}
+				c, err := buildCloud("", &metadata{"instanceID", "us-east-1", "us-east-1a"}, "us-east-1", session.Must(session.NewSession()), opts)
+				if err != nil {
+					t.Fatalf("buildCloud failed: %v", err)
+				}
+				if c.fallbackEfs != nil {
+					t.Fatal("Cloud of the fallback region has a fallback client")
+				}
+
+				regionalCloud, err := c.WithRegion("eu-west-1")
+				if err != nil {
+					t.Fatalf("WithRegion failed: %v", err)
+				}
+				regional := regionalCloud.(*cloud)
+				if endpoint := regional.efs.(*efs.EFS).Client.ClientInfo.Endpoint; endpoint != opts.Endpoint {
+					t.Fatalf("Regional client endpoint mismatched. Expected: %v, Actual: %v", opts.Endpoint, endpoint)
+				}
+				if regional.fallbackEfs == nil || regional.fallbackRegion != "us-east-1" {
+					t.Fatalf("Regional cloud has no client for fallback region %v", opts.FallbackRegion)
+				}
+				// Clouds of other regions are created from the regional cloud as well
+				if _, err := regionalCloud.WithRegion("ap-southeast-2"); err != nil {
+					t.Fatalf("WithRegion of the regional cloud failed: %v", err)
+				}
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, tc.testFunc)
+	}
+}
+
+func TestCreateEfsClient(t *testing.T) {
+	testCases := []struct {
+		name     string
+		region   string
+		opts     ClientOptions
+		env      string
+		endpoint string
+	}{
+		{
+			name:     "Success: Default endpoint",
+			region:   "us-east-1",
+			endpoint: "https://elasticfilesystem.us-east-1.amazonaws.com",
+		},
+		{
+			name:     "Success: FIPS endpoint",
+			region:   "us-east-1",
+			opts:     ClientOptions{UseFIPSEndpoint: true},
+			endpoint: "https://elasticfilesystem-fips.us-east-1.amazonaws.com",
+		},
+		{
+			name:     "Success: FIPS endpoint in GovCloud",
+			region:   "us-gov-west-1",
+			opts:     ClientOptions{UseFIPSEndpoint: true},
+			endpoint: "https://elasticfilesystem-fips.us-gov-west-1.amazonaws.com",
+		},
+		{
+			name:     "Success: Overridden endpoint",
+			region:   "eu-west-1",
+			opts:     ClientOptions{Endpoint: "http://localstack:4566"},
+			endpoint: "http://localstack:4566",
+		},
+		{
+			name:     "Success: Endpoint of the environment",
+			region:   "eu-west-1",
+			env:      "http://localstack:4566",
+			endpoint: "http://localstack:4566",
+		},
+		{
+			name:     "Success: Endpoint option takes precedence over the environment",
+			region:   "eu-west-1",
+			opts:     ClientOptions{Endpoint: "http://localstack:4566"},
+			env:      "http://other:4566",
+			endpoint: "http://localstack:4566",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Setenv(endpointURLEnvVar, tc.env)
+			sess := session.Must(session.NewSession(&aws.Config{}))
+			client := createEfsClient("", tc.region, sess, tc.opts).(*efs.EFS)
+			if client.Endpoint != tc.endpoint {
+				t.Fatalf("Endpoint mismatched. Expected: %v, Actual: %v", tc.endpoint, client.Endpoint)
+			}
+			if client.SigningRegion != tc.region {
+				t.Fatalf("Signing region mismatched. Expected: %v, Actual: %v", tc.region, client.SigningRegion)
+			}
+		})
+	}
+}
+
+func TestCheckClientOptions(t *testing.T) {
+	testCases := []struct {
+		name        string
+		awsRoleArn  string
+		region      string
+		opts        ClientOptions
+		expectError bool
+	}{
+		{
+			name:   "Success: FIPS endpoint disabled",
+			region: "il-central-1",
+		},
+		{
+			name:   "Success: Region with a FIPS endpoint",
+			region: "eu-west-1",
+			opts:   ClientOptions{UseFIPSEndpoint: true},
+		},
+		{
+			name:       "Success: Region with FIPS endpoints for EFS and STS",
+			awsRoleArn: "arn:aws:iam::1234567890:role/EFSCrossAccountRole",
+			region:     "us-east-1",
+			opts:       ClientOptions{UseFIPSEndpoint: true},
+		},
+		{
+			name:        "Fail: Region without a FIPS endpoint",
+			region:      "il-central-1",
+			opts:        ClientOptions{UseFIPSEndpoint: true},
+			expectError: true,
+		},
+		{
+			name:   "Success: Overridden endpoint",
+			region: "il-central-1",
+			opts:   ClientOptions{Endpoint: "http://localstack:4566"},
+		},
+		{
+			name:        "Fail: Endpoint is not an http URL",
+			region:      "eu-west-1",
+			opts:        ClientOptions{Endpoint: "localstack:4566"},
+			expectError: true,
+		},
+		{
+			name:        "Fail: FIPS endpoints with an overridden endpoint",
+			region:      "us-east-1",
+			opts:        ClientOptions{Endpoint: "http://localstack:4566", UseFIPSEndpoint: true},
+			expectError: true,
+		},
+		{
+			name:        "Fail: Region without a FIPS endpoint for STS",
+			awsRoleArn:  "arn:aws:iam::1234567890:role/EFSCrossAccountRole",
+			region:      "eu-west-1",
+			opts:        ClientOptions{UseFIPSEndpoint: true},
+			expectError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Setenv(endpointURLEnvVar, "")
+			err := checkClientOptions(tc.awsRoleArn, tc.region, tc.opts)
+			if tc.expectError && err == nil {
+				t.Fatal("checkClientOptions did not fail")
+			}
+			if !tc.expectError && err != nil {
+				t.Fatalf("checkClientOptions failed: %v", err)
+			}
+		})
 	}
 }
 
@@ -361,6 +939,10 @@ func TestDescribeAccessPoint(t *testing.T) {
 				if fsId != res.FileSystemId {
 					t.Fatalf("FileSystemId mismatched. Expected: %v, Actual: %v", fsId, res.FileSystemId)
 				}
+
+				if res.PosixUser == nil || res.PosixUser.Gid != gid || res.PosixUser.Uid != uid {
+					t.Fatalf("PosixUser mismatched. Expected: %v:%v, Actual: %+v", uid, gid, res.PosixUser)
+				}
 				mockctl.Finish()
 			},
 		},
@@ -504,7 +1086,7 @@ func TestDescribeAccessPoint(t *testing.T) {
 				if err == nil {
 					t.Fatalf("DescribeAccessPoint did not fail")
 				}
-				if err != ErrNotFound {
+				if !errors.Is(err, ErrNotFound) {
 					t.Fatalf("Failed. Expected: %v, Actuak: %v", ErrNotFound, err)
 				}
 				mockctl.Finish()
@@ -523,7 +1105,7 @@ func TestDescribeAccessPoint(t *testing.T) {
 				if err == nil {
 					t.Fatalf("DescribeAccessPoint did not fail")
 				}
-				if err != ErrAccessDenied {
+				if !errors.Is(err, ErrAccessDenied) {
 					t.Fatalf("Failed. Expected: %v, Actual:%v", ErrAccessDenied, err)
 				}
 				mockctl.Finish()
@@ -782,7 +1364,7 @@ func TestDescribeFileSystem(t *testing.T) {
 				if err == nil {
 					t.Fatalf("DescribeFileSystem did not fail")
 				}
-				if err != ErrNotFound {
+				if !errors.Is(err, ErrNotFound) {
 					t.Fatalf("Failed. Expected: %v, Actual:%v", ErrNotFound, err)
 				}
 				mockctl.Finish()
@@ -801,7 +1383,7 @@ func TestDescribeFileSystem(t *testing.T) {
 				if err == nil {
 					t.Fatalf("DescribeFileSystem did not fail")
 				}
-				if err != ErrAccessDenied {
+				if !errors.Is(err, ErrAccessDenied) {
 					t.Fatalf("Failed. Expected: %v, Actual:%v", ErrAccessDenied, err)
 				}
 				mockctl.Finish()
@@ -829,6 +1411,392 @@ func TestDescribeFileSystem(t *testing.T) {
 	}
 }
 
+func TestCreateFileSystem(t *testing.T) {
+	var (
+		fsId        = "fs-abcd1234"
+		clientToken = "volName"
+	)
+	testCases := []struct {
+		name     string
+		testFunc func(t *testing.T)
+	}{
+		{
+			name: "Success",
+			testFunc: func(t *testing.T) {
+				mockctl := gomock.NewController(t)
+				mockEfs := mocks.NewMockEfs(mockctl)
+				c := &cloud{efs: mockEfs}
+
+				req := &FileSystemOptions{
+					ThroughputMode:               efs.ThroughputModeProvisioned,
+					ProvisionedThroughputInMibps: 128,
+					Tags:                         map[string]string{"cluster": "efs"},
+					AvailabilityZoneName:         "us-east-1a",
+				}
+
+				output := &efs.FileSystemDescription{
+					CreationToken: aws.String(clientToken),
+					FileSystemId:  aws.String(fsId),
+					Tags: []*efs.Tag{
+						{Key: aws.String("cluster"), Value: aws.String("efs")},
+					},
+					AvailabilityZoneName: aws.String("us-east-1a"),
+				}
+
+				ctx := context.Background()
+				mockEfs.EXPECT().CreateFileSystemWithContext(gomock.Eq(ctx), gomock.Any()).Return(output, nil).
+					Do(func(ctx context.Context, input *efs.CreateFileSystemInput, opts ...request.Option) {
+						if aws.StringValue(input.CreationToken) != clientToken {
+							t.Fatalf("CreationToken mismatched. Expected: %v, Actual: %v", clientToken, aws.StringValue(input.CreationToken))
+						}
+						if aws.StringValue(input.ThroughputMode) != efs.ThroughputModeProvisioned {
+							t.Fatalf("ThroughputMode mismatched. Expected: %v, Actual: %v", efs.ThroughputModeProvisioned, aws.StringValue(input.ThroughputMode))
+						}
+						if aws.Float64Value(input.ProvisionedThroughputInMibps) != 128 {
+							t.Fatalf("ProvisionedThroughputInMibps mismatched. Expected: %v, Actual: %v", 128, aws.Float64Value(input.ProvisionedThroughputInMibps))
+						}
+						if aws.StringValue(input.AvailabilityZoneName) != "us-east-1a" {
+							t.Fatalf("AvailabilityZoneName mismatched. Expected: %v, Actual: %v", "us-east-1a", aws.StringValue(input.AvailabilityZoneName))
+						}
+					})
+				res, err := c.CreateFileSystem(ctx, clientToken, req)
+				if err != nil {
+					t.Fatalf("Create File System failed: %v", err)
+				}
+
+				if fsId != res.FileSystemId {
+					t.Fatalf("FileSystemId mismatched. Expected: %v, Actual: %v", fsId, res.FileSystemId)
+				}
+				if res.Tags["cluster"] != "efs" {
+					t.Fatalf("Tags mismatched. Expected: %v, Actual: %v", req.Tags, res.Tags)
+				}
+				if res.AvailabilityZoneName != "us-east-1a" {
+					t.Fatalf("AvailabilityZoneName mismatched. Expected: %v, Actual: %v", "us-east-1a", res.AvailabilityZoneName)
+				}
+				mockctl.Finish()
+			},
+		},
+		{
+			name: "Success: throughput mode and availability zone are omitted when not set",
+			testFunc: func(t *testing.T) {
+				mockctl := gomock.NewController(t)
+				mockEfs := mocks.NewMockEfs(mockctl)
+				c := &cloud{efs: mockEfs}
+
+				output := &efs.FileSystemDescription{
+					FileSystemId: aws.String(fsId),
+				}
+
+				ctx := context.Background()
+				mockEfs.EXPECT().CreateFileSystemWithContext(gomock.Eq(ctx), gomock.Any()).Return(output, nil).
+					Do(func(ctx context.Context, input *efs.CreateFileSystemInput, opts ...request.Option) {
+						if input.ThroughputMode != nil || input.ProvisionedThroughputInMibps != nil {
+							t.Fatalf("Throughput should not be set: %+v", *input)
+						}
+						if input.AvailabilityZoneName != nil {
+							t.Fatalf("Availability zone should not be set: %+v", *input)
+						}
+					})
+				_, err := c.CreateFileSystem(ctx, clientToken, &FileSystemOptions{})
+				if err != nil {
+					t.Fatalf("Create File System failed: %v", err)
+				}
+				mockctl.Finish()
+			},
+		},
+		{
+			name: "Fail: Access Denied",
+			testFunc: func(t *testing.T) {
+				mockctl := gomock.NewController(t)
+				mockEfs := mocks.NewMockEfs(mockctl)
+				c := &cloud{efs: mockEfs}
+
+				ctx := context.Background()
+				mockEfs.EXPECT().CreateFileSystemWithContext(gomock.Eq(ctx), gomock.Any()).Return(nil, awserr.New(AccessDeniedException, "Access Denied", errors.New("Access Denied")))
+				_, err := c.CreateFileSystem(ctx, clientToken, &FileSystemOptions{})
+				if !errors.Is(err, ErrAccessDenied) {
+					t.Fatalf("Failed. Expected: %v, Actual:%v", ErrAccessDenied, err)
+				}
+				mockctl.Finish()
+			},
+		},
+		{
+			name: "Fail: File System already exists",
+			testFunc: func(t *testing.T) {
+				mockctl := gomock.NewController(t)
+				mockEfs := mocks.NewMockEfs(mockctl)
+				c := &cloud{efs: mockEfs}
+
+				ctx := context.Background()
+				mockEfs.EXPECT().CreateFileSystemWithContext(gomock.Eq(ctx), gomock.Any()).Return(nil, awserr.New(efs.ErrCodeFileSystemAlreadyExists, "File System already exists", errors.New("File System already exists")))
+				_, err := c.CreateFileSystem(ctx, clientToken, &FileSystemOptions{})
+				if !errors.Is(err, ErrAlreadyExists) {
+					t.Fatalf("Failed. Expected: %v, Actual:%v", ErrAlreadyExists, err)
+				}
+				mockctl.Finish()
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, tc.testFunc)
+	}
+}
+
+func TestDeleteFileSystem(t *testing.T) {
+	var (
+		fsId = "fs-abcd1234"
+	)
+	testCases := []struct {
+		name     string
+		testFunc func(t *testing.T)
+	}{
+		{
+			name: "Success",
+			testFunc: func(t *testing.T) {
+				mockctl := gomock.NewController(t)
+				mockEfs := mocks.NewMockEfs(mockctl)
+				c := &cloud{efs: mockEfs}
+
+				ctx := context.Background()
+				mockEfs.EXPECT().DeleteFileSystemWithContext(gomock.Eq(ctx), gomock.Any()).Return(&efs.DeleteFileSystemOutput{}, nil)
+				err := c.DeleteFileSystem(ctx, fsId)
+				if err != nil {
+					t.Fatalf("Delete File System failed: %v", err)
+				}
+				mockctl.Finish()
+			},
+		},
+		{
+			name: "Fail: File System Not Found",
+			testFunc: func(t *testing.T) {
+				mockctl := gomock.NewController(t)
+				mockEfs := mocks.NewMockEfs(mockctl)
+				c := &cloud{efs: mockEfs}
+
+				ctx := context.Background()
+				mockEfs.EXPECT().DeleteFileSystemWithContext(gomock.Eq(ctx), gomock.Any()).Return(nil, awserr.New(efs.ErrCodeFileSystemNotFound, "File System not found", errors.New("File System not found")))
+				err := c.DeleteFileSystem(ctx, fsId)
+				if !errors.Is(err, ErrNotFound) {
+					t.Fatalf("Failed. Expected: %v, Actual:%v", ErrNotFound, err)
+				}
+				mockctl.Finish()
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, tc.testFunc)
+	}
+}
+
+func TestCheckConnectivity(t *testing.T) {
+	testCases := []struct {
+		name     string
+		testFunc func(t *testing.T)
+	}{
+		{
+			name: "Success",
+			testFunc: func(t *testing.T) {
+				mockCtl := gomock.NewController(t)
+				mockEfs := mocks.NewMockEfs(mockCtl)
+				c := &cloud{efs: mockEfs}
+
+				ctx := context.Background()
+				mockEfs.EXPECT().DescribeFileSystemsWithContext(gomock.Eq(ctx), gomock.Eq(&efs.DescribeFileSystemsInput{MaxItems: aws.Int64(1)})).Return(&efs.DescribeFileSystemsOutput{}, nil)
+				if err := c.CheckConnectivity(ctx); err != nil {
+					t.Fatalf("CheckConnectivity failed: %v", err)
+				}
+				mockCtl.Finish()
+			},
+		},
+		{
+			name: "Fail: Access Denied",
+			testFunc: func(t *testing.T) {
+				mockCtl := gomock.NewController(t)
+				mockEfs := mocks.NewMockEfs(mockCtl)
+				c := &cloud{efs: mockEfs}
+
+				ctx := context.Background()
+				mockEfs.EXPECT().DescribeFileSystemsWithContext(gomock.Eq(ctx), gomock.Any()).Return(nil, awserr.New(AccessDeniedException, "Access Denied", errors.New("Access Denied")))
+				if err := c.CheckConnectivity(ctx); !errors.Is(err, ErrAccessDenied) {
+					t.Fatalf("Failed. Expected: %v, Actual:%v", ErrAccessDenied, err)
+				}
+				mockCtl.Finish()
+			},
+		},
+		{
+			name: "Fail: Other",
+			testFunc: func(t *testing.T) {
+				mockCtl := gomock.NewController(t)
+				mockEfs := mocks.NewMockEfs(mockCtl)
+				c := &cloud{efs: mockEfs}
+
+				ctx := context.Background()
+				mockEfs.EXPECT().DescribeFileSystemsWithContext(gomock.Eq(ctx), gomock.Any()).Return(nil, errors.New("RequestError: send request failed"))
+				if err := c.CheckConnectivity(ctx); err == nil {
+					t.Fatalf("CheckConnectivity did not fail")
+				}
+				mockCtl.Finish()
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, tc.testFunc)
+	}
+}
+
+func TestListMountTargets(t *testing.T) {
+	var (
+		fsId = "fs-abcd1234"
+	)
+
+	mountTarget := func(az, state string) *efs.MountTargetDescription {
+		return &efs.MountTargetDescription{
+			AvailabilityZoneId:   aws.String(az + "-id"),
+			AvailabilityZoneName: aws.String(az),
+			FileSystemId:         aws.String(fsId),
+			IpAddress:            aws.String("127.0.0.1"),
+			LifeCycleState:       aws.String(state),
+			MountTargetId:        aws.String("fsmt-" + az),
+		}
+	}
+
+	testCases := []struct {
+		name          string
+		mockOutput    *efs.DescribeMountTargetsOutput
+		mockError     error
+		expectedZones []string
+		expectError   error
+	}{
+		{
+			name: "Success: Only available mount targets are listed",
+			mockOutput: &efs.DescribeMountTargetsOutput{
+				MountTargets: []*efs.MountTargetDescription{
+					mountTarget("us-east-1a", "available"),
+					mountTarget("us-east-1b", "creating"),
+					mountTarget("us-east-1c", "available"),
+				},
+			},
+			expectedZones: []string{"us-east-1a", "us-east-1c"},
+		},
+		{
+			name:          "Success: No mount target",
+			mockOutput:    &efs.DescribeMountTargetsOutput{},
+			expectedZones: []string{},
+		},
+		{
+			name:        "Fail: File system does not exist",
+			mockError:   awserr.New(efs.ErrCodeFileSystemNotFound, "File system not found", errors.New("File system not found")),
+			expectError: ErrNotFound,
+		},
+		{
+			name:        "Fail: Access Denied",
+			mockError:   awserr.New(AccessDeniedException, "Access Denied", errors.New("Access Denied")),
+			expectError: ErrAccessDenied,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockctl := gomock.NewController(t)
+			mockEfs := mocks.NewMockEfs(mockctl)
+			c := &cloud{efs: mockEfs}
+
+			ctx := context.Background()
+			mockEfs.EXPECT().DescribeMountTargetsWithContext(gomock.Eq(ctx), gomock.Any()).Return(tc.mockOutput, tc.mockError)
+			mountTargets, err := c.ListMountTargets(ctx, fsId)
+			if !errors.Is(err, tc.expectError) {
+				t.Fatalf("Failed. Expected: %v, Actual: %v", tc.expectError, err)
+			}
+			if tc.expectError == nil {
+				zones := []string{}
+				for _, mountTarget := range mountTargets {
+					zones = append(zones, mountTarget.AZName)
+				}
+				if !reflect.DeepEqual(zones, tc.expectedZones) {
+					t.Fatalf("Zones mismatched. Expected: %v, Actual: %v", tc.expectedZones, zones)
+				}
+			}
+			mockctl.Finish()
+		})
+	}
+}
+
+func TestDescribeReplicationConfiguration(t *testing.T) {
+	var (
+		fsId     = "fs-abcd1234"
+		destFsId = "fs-efgh5678"
+	)
+
+	testCases := []struct {
+		name        string
+		mockOutput  *efs.DescribeReplicationConfigurationsOutput
+		mockError   error
+		expected    *ReplicationConfiguration
+		expectError error
+	}{
+		{
+			name: "Success",
+			mockOutput: &efs.DescribeReplicationConfigurationsOutput{
+				Replications: []*efs.ReplicationConfigurationDescription{
+					{
+						SourceFileSystemId: aws.String(fsId),
+						Destinations: []*efs.Destination{
+							{
+								FileSystemId: aws.String(destFsId),
+								Region:       aws.String("us-west-2"),
+								Status:       aws.String(efs.ReplicationStatusEnabled),
+							},
+						},
+					},
+				},
+			},
+			expected: &ReplicationConfiguration{
+				SourceFileSystemId: fsId,
+				Destinations: []*ReplicationDestination{
+					{FileSystemId: destFsId, Region: "us-west-2", Status: efs.ReplicationStatusEnabled},
+				},
+			},
+		},
+		{
+			name:        "Fail: File system is not replicated",
+			mockError:   awserr.New(efs.ErrCodeReplicationNotFound, "Replication not found", errors.New("Replication not found")),
+			expectError: ErrNotFound,
+		},
+		{
+			name:        "Fail: File system does not exist",
+			mockError:   awserr.New(efs.ErrCodeFileSystemNotFound, "File system not found", errors.New("File system not found")),
+			expectError: ErrNotFound,
+		},
+		{
+			name:        "Fail: Access Denied",
+			mockError:   awserr.New(AccessDeniedException, "Access Denied", errors.New("Access Denied")),
+			expectError: ErrAccessDenied,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockctl := gomock.NewController(t)
+			mockEfs := mocks.NewMockEfs(mockctl)
+			c := &cloud{efs: mockEfs}
+
+			ctx := context.Background()
+			mockEfs.EXPECT().DescribeReplicationConfigurationsWithContext(gomock.Eq(ctx), gomock.Any()).Return(tc.mockOutput, tc.mockError)
+			replication, err := c.DescribeReplicationConfiguration(ctx, fsId)
+			if !errors.Is(err, tc.expectError) {
+				t.Fatalf("Failed. Expected: %v, Actual: %v", tc.expectError, err)
+			}
+			if tc.expectError == nil && !reflect.DeepEqual(replication, tc.expected) {
+				t.Fatalf("Replication mismatched. Expected: %+v, Actual: %+v", tc.expected, replication)
+			}
+			mockctl.Finish()
+		})
+	}
+}
+
 func TestDescribeMountTargets(t *testing.T) {
 	var (
 		fsId = "fs-abcd1234"
@@ -915,12 +1883,12 @@ func TestDescribeMountTargets(t *testing.T) {
 		{
 			name:        "Fail: File System Not Found",
 			mockError:   awserr.New(efs.ErrCodeFileSystemNotFound, "File system not found", errors.New("File system not found")),
-			expectError: errtyp{message: "Resource was not found"},
+			expectError: errtyp{message: "Resource was not found: FileSystemNotFound: File system not found\ncaused by: File system not found"},
 		},
 		{
 			name:        "Fail: Access Denied",
 			mockError:   awserr.New(AccessDeniedException, "Access Denied", errors.New("Access Denied")),
-			expectError: errtyp{message: "Access denied"},
+			expectError: errtyp{message: "Access denied: AccessDeniedException: Access Denied\ncaused by: Access Denied"},
 		},
 		{
 			name:        "Fail: Other",
@@ -1024,13 +1992,13 @@ func Test_findAccessPointByPath(t *testing.T) {
 				tt.prepare(mockEfs)
 			}
 
-			gotAccessPoint, err := c.findAccessPointByClientToken(ctx, tt.args.clientToken, tt.args.accessPointOpts)
+			gotAccessPoint, err := c.FindAccessPointByClientToken(ctx, tt.args.clientToken, tt.args.accessPointOpts)
 			if (err != nil) != tt.wantErr {
-				t.Errorf("findAccessPointByClientToken() error = %v, wantErr %v", err, tt.wantErr)
+				t.Errorf("FindAccessPointByClientToken() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
 			if !reflect.DeepEqual(gotAccessPoint, tt.wantAccessPoint) {
-				t.Errorf("findAccessPointByClientToken() gotAccessPoint = %v, want %v", gotAccessPoint, tt.wantAccessPoint)
+				t.Errorf("FindAccessPointByClientToken() gotAccessPoint = %v, want %v", gotAccessPoint, tt.wantAccessPoint)
 			}
 		})
 	}
@@ -0,0 +1,70 @@
+package cloud
+
+import (
+	"os"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"k8s.io/klog/v2"
+)
+
+// FileCredentialsProviderName is the name of the provider of the credentials of ClientOptions.CredentialsFile
+const FileCredentialsProviderName = "RotatingSharedCredentialsProvider"
+
+// fileCredentialsProvider provides the credentials of a shared credentials file, and reads them again whenever the
+// file changes. The shared credentials provider of the SDK reads them once, so that a long running driver keeps using
+// credentials which were rotated, and fails with AccessDenied, until it is restarted.
+type fileCredentialsProvider struct {
+	filename string
+	// profile is the profile of the file, AWS_PROFILE or the default profile when empty
+	profile string
+
+	mu sync.Mutex
+	// read is whether the credentials were read, and modTime and size those of the file they were read from
+	read    bool
+	modTime time.Time
+	size    int64
+}
+
+// newFileCredentials returns the credentials of the shared credentials file, read again whenever it changes
+func newFileCredentials(filename string) *credentials.Credentials {
+	return credentials.NewCredentials(&fileCredentialsProvider{filename: filename})
+}
+
+// Retrieve reads the credentials of the file
+func (p *fileCredentialsProvider) Retrieve() (credentials.Value, error) {
+	// The file is checked before reading it, a change made in between reads the credentials again on the next call
+	info, statErr := os.Stat(p.filename)
+	value, err := credentials.NewSharedCredentials(p.filename, p.profile).Get()
+	if err != nil {
+		return credentials.Value{ProviderName: FileCredentialsProviderName}, err
+	}
+	value.ProviderName = FileCredentialsProviderName
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.read {
+		klog.Infof("Credentials file %v changed, using its new credentials", p.filename)
+	}
+	p.read = true
+	if statErr == nil {
+		p.modTime, p.size = info.ModTime(), info.Size()
+	}
+	return value, nil
+}
+
+// IsExpired returns whether the file changed since its credentials were read. The credentials are kept while the
+// file cannot be read, e.g. while a mounted secret is being updated.
+func (p *fileCredentialsProvider) IsExpired() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if !p.read {
+		return true
+	}
+	info, err := os.Stat(p.filename)
+	if err != nil {
+		return false
+	}
+	return !info.ModTime().Equal(p.modTime) || info.Size() != p.size
+}
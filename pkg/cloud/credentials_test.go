@@ -0,0 +1,116 @@
+package cloud
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/efs"
+)
+
+// writeCredentialsFile writes the credentials file with the access key, with a modification time in the future of
+// the previous one, as rotations within the resolution of the file system time would otherwise go unnoticed
+func writeCredentialsFile(t *testing.T, filename, accessKeyId string, modTime time.Time) {
+	content := fmt.Sprintf("[default]\naws_access_key_id = %v\naws_secret_access_key = secret-%v\n", accessKeyId, accessKeyId)
+	if err := os.WriteFile(filename, []byte(content), 0600); err != nil {
+		t.Fatalf("Failed to write credentials file: %v", err)
+	}
+	if err := os.Chtimes(filename, modTime, modTime); err != nil {
+		t.Fatalf("Failed to set the modification time of the credentials file: %v", err)
+	}
+}
+
+func TestFileCredentials(t *testing.T) {
+	t.Setenv("AWS_PROFILE", "")
+	filename := filepath.Join(t.TempDir(), "credentials")
+	now := time.Now()
+	writeCredentialsFile(t, filename, "AKIDFIRST", now)
+	creds := newFileCredentials(filename)
+
+	value, err := creds.Get()
+	if err != nil {
+		t.Fatalf("Failed to get credentials: %v", err)
+	}
+	if value.AccessKeyID != "AKIDFIRST" || value.ProviderName != FileCredentialsProviderName {
+		t.Fatalf("Unexpected credentials %v of %v", value.AccessKeyID, value.ProviderName)
+	}
+	if creds.IsExpired() {
+		t.Fatalf("Credentials expired while the file did not change")
+	}
+
+	writeCredentialsFile(t, filename, "AKIDSECOND", now.Add(time.Minute))
+	if !creds.IsExpired() {
+		t.Fatalf("Credentials did not expire once the file changed")
+	}
+	value, err = creds.Get()
+	if err != nil {
+		t.Fatalf("Failed to get rotated credentials: %v", err)
+	}
+	if value.AccessKeyID != "AKIDSECOND" {
+		t.Fatalf("Credentials mismatched. Expected: %v, Actual: %v", "AKIDSECOND", value.AccessKeyID)
+	}
+
+	// The credentials are kept while the file is replaced
+	if err := os.Remove(filename); err != nil {
+		t.Fatalf("Failed to remove credentials file: %v", err)
+	}
+	if creds.IsExpired() {
+		t.Fatalf("Credentials expired while the file is missing")
+	}
+}
+
+func TestCreateEfsClientCredentialsRotation(t *testing.T) {
+	t.Setenv("AWS_PROFILE", "")
+	t.Setenv(endpointURLEnvVar, "")
+
+	// The EFS API only accepts the current access key, as once rotated credentials are revoked
+	var mu sync.Mutex
+	validAccessKeyId := "AKIDFIRST"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		if !strings.Contains(r.Header.Get("Authorization"), "Credential="+validAccessKeyId+"/") {
+			w.Header().Set("x-amzn-ErrorType", "AccessDeniedException")
+			w.WriteHeader(http.StatusForbidden)
+			fmt.Fprint(w, `{"message":"The security token included in the request is invalid"}`)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"FileSystems":[]}`)
+	}))
+	defer server.Close()
+
+	filename := filepath.Join(t.TempDir(), "credentials")
+	now := time.Now()
+	writeCredentialsFile(t, filename, "AKIDFIRST", now)
+	opts := ClientOptions{Endpoint: server.URL, CredentialsFile: filename}
+	sess := session.Must(session.NewSession(&aws.Config{}))
+	sess = sess.Copy(&aws.Config{Credentials: newFileCredentials(filename)})
+	client := createEfsClient("", "us-east-1", sess, opts)
+
+	describe := func() error {
+		_, err := client.DescribeFileSystemsWithContext(context.Background(), &efs.DescribeFileSystemsInput{})
+		return err
+	}
+	if err := describe(); err != nil {
+		t.Fatalf("DescribeFileSystems failed: %v", err)
+	}
+
+	// The credentials are rotated, and the previous ones revoked
+	mu.Lock()
+	validAccessKeyId = "AKIDSECOND"
+	mu.Unlock()
+	writeCredentialsFile(t, filename, "AKIDSECOND", now.Add(time.Minute))
+	if err := describe(); err != nil {
+		t.Fatalf("DescribeFileSystems failed after the credentials were rotated: %v", err)
+	}
+}
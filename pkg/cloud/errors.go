@@ -0,0 +1,80 @@
+package cloud
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+)
+
+// Error is a failed EFS request classified as ErrNotFound, ErrAlreadyExists or ErrAccessDenied.
+// errors.Is matches it with its class, and it unwraps to the AWS error, keeping its code, message and request ID.
+type Error struct {
+	// Kind is the sentinel error the request failed with
+	Kind error
+	// RequestID is the AWS request ID, empty when the SDK received no response
+	RequestID string
+	Err       error
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%v: %v", e.Kind, e.Err)
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+func (e *Error) Is(target error) bool {
+	return target == e.Kind
+}
+
+// newError classifies the error of an EFS request as one of the sentinel errors
+func newError(kind, err error) error {
+	e := &Error{Kind: kind, Err: err}
+	var reqErr awserr.RequestFailure
+	if errors.As(err, &reqErr) {
+		e.RequestID = reqErr.RequestID()
+	}
+	return e
+}
+
+// IsNotFound reports whether the error is, or wraps, ErrNotFound
+func IsNotFound(err error) bool {
+	return errors.Is(err, ErrNotFound)
+}
+
+// IsAlreadyExists reports whether the error is, or wraps, ErrAlreadyExists
+func IsAlreadyExists(err error) bool {
+	return errors.Is(err, ErrAlreadyExists)
+}
+
+// IsAccessDenied reports whether the error is, or wraps, ErrAccessDenied
+func IsAccessDenied(err error) bool {
+	return errors.Is(err, ErrAccessDenied)
+}
+
+// IsThrottling reports whether the error wraps an AWS error throttling the request, which can be retried later
+func IsThrottling(err error) bool {
+	var awsErr awserr.Error
+	return errors.As(err, &awsErr) && request.IsErrorThrottle(awsErr)
+}
+
+// IsTransient reports whether the error is likely to go away when the request is retried: a throttled request, a
+// request which failed to reach EFS, timed out or was short-circuited, or a server side failure of EFS
+func IsTransient(err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, ErrCircuitOpen) || IsThrottling(err) {
+		return true
+	}
+	var reqErr awserr.RequestFailure
+	if errors.As(err, &reqErr) && reqErr.StatusCode() >= 500 {
+		return true
+	}
+	var awsErr awserr.Error
+	return errors.As(err, &awsErr) && request.IsErrorRetryable(awsErr)
+}
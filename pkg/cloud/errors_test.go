@@ -0,0 +1,131 @@
+package cloud
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/efs"
+	"github.com/golang/mock/gomock"
+	"github.com/kubernetes-sigs/aws-efs-csi-driver/pkg/cloud/mocks"
+)
+
+func TestErrorClassification(t *testing.T) {
+	accessDenied := awserr.NewRequestFailure(awserr.New(AccessDeniedException, "Access Denied", nil), 403, "c3b5bd2e-6a5e-4b0b-9d1a-1c0f7d6e2a51")
+	throttled := awserr.NewRequestFailure(awserr.New("ThrottlingException", "Rate exceeded", nil), 400, "5f0c1b3e-1d2a-4c43-8f4e-2b7f9a6d0c11")
+
+	testCases := []struct {
+		name          string
+		err           error
+		notFound      bool
+		alreadyExists bool
+		accessDenied  bool
+		throttling    bool
+		transient     bool
+	}{
+		{
+			name:     "Sentinel error",
+			err:      ErrNotFound,
+			notFound: true,
+		},
+		{
+			name:          "Classified AWS error",
+			err:           newError(ErrAlreadyExists, awserr.New(AccessPointAlreadyExists, "Access Point already exists", nil)),
+			alreadyExists: true,
+		},
+		{
+			name:         "Wrapped classified AWS error",
+			err:          fmt.Errorf("Failed to create access point: %w", newError(ErrAccessDenied, accessDenied)),
+			accessDenied: true,
+		},
+		{
+			name:       "Throttled request",
+			err:        newRequestError("Failed to create access point", throttled),
+			throttling: true,
+			transient:  true,
+		},
+		{
+			name:      "Server error",
+			err:       newRequestError("Describe Access Point failed", awserr.NewRequestFailure(awserr.New(efs.ErrCodeInternalServerError, "Internal error", nil), 500, "8e1d6c4a-3f2b-4b8e-9c71-0a5d2e6f4b93")),
+			transient: true,
+		},
+		{
+			name:      "Network error",
+			err:       newRequestError("Describe Access Point failed", awserr.New(request.ErrCodeRequestError, "send request failed", &net.OpError{Op: "dial", Err: errors.New("connection refused")})),
+			transient: true,
+		},
+		{
+			name:      "Timed out request",
+			err:       fmt.Errorf("DescribeAccessPoint timed out after 10s: %w", context.DeadlineExceeded),
+			transient: true,
+		},
+		{
+			name:      "Short-circuited request",
+			err:       newRequestError("Describe Access Point failed", fmt.Errorf("%w, retrying in 30s", ErrCircuitOpen)),
+			transient: true,
+		},
+		{
+			name: "Invalid request",
+			err:  newRequestError("Describe Access Point failed", awserr.NewRequestFailure(awserr.New(efs.ErrCodeBadRequest, "Invalid access point ID", nil), 400, "2a7c9e1f-5b3d-4f6a-8e2c-7d1b0f9a3c54")),
+		},
+		{
+			name: "Other error",
+			err:  errors.New("Resource was not found"),
+		},
+		{
+			name: "No error",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if IsNotFound(tc.err) != tc.notFound {
+				t.Errorf("IsNotFound(%v) mismatched. Expected: %v", tc.err, tc.notFound)
+			}
+			if IsAlreadyExists(tc.err) != tc.alreadyExists {
+				t.Errorf("IsAlreadyExists(%v) mismatched. Expected: %v", tc.err, tc.alreadyExists)
+			}
+			if IsAccessDenied(tc.err) != tc.accessDenied {
+				t.Errorf("IsAccessDenied(%v) mismatched. Expected: %v", tc.err, tc.accessDenied)
+			}
+			if IsThrottling(tc.err) != tc.throttling {
+				t.Errorf("IsThrottling(%v) mismatched. Expected: %v", tc.err, tc.throttling)
+			}
+			if IsTransient(tc.err) != tc.transient {
+				t.Errorf("IsTransient(%v) mismatched. Expected: %v", tc.err, tc.transient)
+			}
+		})
+	}
+}
+
+func TestErrorPreservesAwsError(t *testing.T) {
+	mockCtl := gomock.NewController(t)
+	defer mockCtl.Finish()
+	mockEfs := mocks.NewMockEfs(mockCtl)
+	c := &cloud{efs: mockEfs}
+
+	ctx := context.Background()
+	requestId := "c3b5bd2e-6a5e-4b0b-9d1a-1c0f7d6e2a51"
+	awsErr := awserr.NewRequestFailure(awserr.New(efs.ErrCodeAccessPointNotFound, "Access Point not found", nil), 404, requestId)
+	mockEfs.EXPECT().DeleteAccessPointWithContext(gomock.Eq(ctx), gomock.Any()).Return(nil, awsErr)
+
+	err := c.DeleteAccessPoint(ctx, "fsap-abcd1234xyz987")
+	if !IsNotFound(err) {
+		t.Fatalf("Expected a not found error, got: %v", err)
+	}
+	var cloudErr *Error
+	if !errors.As(err, &cloudErr) {
+		t.Fatalf("Expected an Error, got: %v", err)
+	}
+	if cloudErr.RequestID != requestId {
+		t.Fatalf("Request ID mismatched. Expected: %v, Actual: %v", requestId, cloudErr.RequestID)
+	}
+	var reqErr awserr.RequestFailure
+	if !errors.As(err, &reqErr) || reqErr.Code() != efs.ErrCodeAccessPointNotFound {
+		t.Fatalf("Expected the AWS error to be preserved, got: %v", err)
+	}
+}
@@ -0,0 +1,40 @@
+package cloud
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/efs"
+	"k8s.io/klog/v2"
+)
+
+// shouldFailOver returns whether a describe call which failed in the region of the cloud is retried in its fallback
+// region, which is only the case for transient failures, e.g. during an outage of the EFS API of the region
+func (c *cloud) shouldFailOver(err error) bool {
+	return c.fallbackEfs != nil && IsTransient(err)
+}
+
+// failOverDescribeAccessPoints describes an access point in the fallback region. The error of the region of the
+// cloud is returned when the call fails there too, so that a NotFound of the fallback region, where the access point
+// may be unknown, is never mistaken for the deletion of the access point.
+func (c *cloud) failOverDescribeAccessPoints(ctx context.Context, input *efs.DescribeAccessPointsInput, regionErr error) (*efs.DescribeAccessPointsOutput, error) {
+	res, err := c.fallbackEfs.DescribeAccessPointsWithContext(ctx, input)
+	if err != nil {
+		klog.V(4).Infof("Failed to describe Access Point %v in fallback region %v too: %v", aws.StringValue(input.AccessPointId), c.fallbackRegion, err)
+		return nil, regionErr
+	}
+	klog.Warningf("Described Access Point %v in fallback region %v, as the call failed: %v", aws.StringValue(input.AccessPointId), c.fallbackRegion, regionErr)
+	return res, nil
+}
+
+// failOverDescribeFileSystems describes a file system in the fallback region, returning the error of the region of
+// the cloud when the call fails there too
+func (c *cloud) failOverDescribeFileSystems(ctx context.Context, input *efs.DescribeFileSystemsInput, regionErr error) (*efs.DescribeFileSystemsOutput, error) {
+	res, err := c.fallbackEfs.DescribeFileSystemsWithContext(ctx, input)
+	if err != nil {
+		klog.V(4).Infof("Failed to describe File System %v in fallback region %v too: %v", aws.StringValue(input.FileSystemId), c.fallbackRegion, err)
+		return nil, regionErr
+	}
+	klog.Warningf("Described File System %v in fallback region %v, as the call failed: %v", aws.StringValue(input.FileSystemId), c.fallbackRegion, regionErr)
+	return res, nil
+}
@@ -0,0 +1,146 @@
+package cloud
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/efs"
+	"github.com/golang/mock/gomock"
+
+	"github.com/kubernetes-sigs/aws-efs-csi-driver/pkg/cloud/mocks"
+)
+
+func TestDescribeAccessPointFailOver(t *testing.T) {
+	var (
+		accessPointId = "fsap-abcd1234xyz987"
+		fsId          = "fs-abcd1234"
+		output        = &efs.DescribeAccessPointsOutput{
+			AccessPoints: []*efs.AccessPointDescription{
+				{
+					AccessPointId:  aws.String(accessPointId),
+					FileSystemId:   aws.String(fsId),
+					RootDirectory:  &efs.RootDirectory{Path: aws.String("/test")},
+					LifeCycleState: aws.String(efs.LifeCycleStateAvailable),
+				},
+			},
+		}
+		unavailable  = awserr.NewRequestFailure(awserr.New("ServiceUnavailableException", "Service unavailable", nil), 503, "request-id")
+		notFound     = awserr.New(efs.ErrCodeAccessPointNotFound, "Access point not found", nil)
+		accessDenied = awserr.New(AccessDeniedException, "Access Denied", nil)
+	)
+
+	testCases := []struct {
+		name        string
+		fallback    bool
+		setup       func(mockEfs, mockFallbackEfs *mocks.MockEfs)
+		expectFound bool
+		expectErr   func(err error) bool
+	}{
+		{
+			name:     "Success: Described in the fallback region when the region is unavailable",
+			fallback: true,
+			setup: func(mockEfs, mockFallbackEfs *mocks.MockEfs) {
+				mockEfs.EXPECT().DescribeAccessPointsWithContext(gomock.Any(), gomock.Any()).Return(nil, unavailable)
+				mockFallbackEfs.EXPECT().DescribeAccessPointsWithContext(gomock.Any(), gomock.Any()).Return(output, nil)
+			},
+			expectFound: true,
+		},
+		{
+			name:     "Success: Fallback region not called when the region responds",
+			fallback: true,
+			setup: func(mockEfs, mockFallbackEfs *mocks.MockEfs) {
+				mockEfs.EXPECT().DescribeAccessPointsWithContext(gomock.Any(), gomock.Any()).Return(output, nil)
+			},
+			expectFound: true,
+		},
+		{
+			name:     "Fail: Not found in the region is not failed over",
+			fallback: true,
+			setup: func(mockEfs, mockFallbackEfs *mocks.MockEfs) {
+				mockEfs.EXPECT().DescribeAccessPointsWithContext(gomock.Any(), gomock.Any()).Return(nil, notFound)
+			},
+			expectErr: IsNotFound,
+		},
+		{
+			name:     "Fail: Access denied in the region is not failed over",
+			fallback: true,
+			setup: func(mockEfs, mockFallbackEfs *mocks.MockEfs) {
+				mockEfs.EXPECT().DescribeAccessPointsWithContext(gomock.Any(), gomock.Any()).Return(nil, accessDenied)
+			},
+			expectErr: IsAccessDenied,
+		},
+		{
+			name:     "Fail: Not found in the fallback region returns the error of the region",
+			fallback: true,
+			setup: func(mockEfs, mockFallbackEfs *mocks.MockEfs) {
+				mockEfs.EXPECT().DescribeAccessPointsWithContext(gomock.Any(), gomock.Any()).Return(nil, unavailable)
+				mockFallbackEfs.EXPECT().DescribeAccessPointsWithContext(gomock.Any(), gomock.Any()).Return(nil, notFound)
+			},
+			expectErr: func(err error) bool { return IsTransient(err) && !IsNotFound(err) },
+		},
+		{
+			name: "Fail: Not failed over without fallback region",
+			setup: func(mockEfs, mockFallbackEfs *mocks.MockEfs) {
+				mockEfs.EXPECT().DescribeAccessPointsWithContext(gomock.Any(), gomock.Any()).Return(nil, unavailable)
+			},
+			expectErr: IsTransient,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockCtl := gomock.NewController(t)
+			mockEfs := mocks.NewMockEfs(mockCtl)
+			mockFallbackEfs := mocks.NewMockEfs(mockCtl)
+			c := &cloud{efs: mockEfs}
+			if tc.fallback {
+				c.fallbackEfs, c.fallbackRegion = mockFallbackEfs, "us-west-2"
+			}
+			tc.setup(mockEfs, mockFallbackEfs)
+
+			accessPoint, err := c.DescribeAccessPoint(context.Background(), accessPointId)
+			if tc.expectFound {
+				if err != nil {
+					t.Fatalf("DescribeAccessPoint failed: %v", err)
+				}
+				if accessPoint.AccessPointId != accessPointId || accessPoint.FileSystemId != fsId {
+					t.Fatalf("Access point mismatched: %+v", accessPoint)
+				}
+			} else if err == nil || !tc.expectErr(err) {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			mockCtl.Finish()
+		})
+	}
+}
+
+func TestDescribeFileSystemFailOver(t *testing.T) {
+	fsId := "fs-abcd1234"
+	unavailable := awserr.NewRequestFailure(awserr.New("InternalServerError", "Internal server error", nil), 500, "request-id")
+
+	mockCtl := gomock.NewController(t)
+	mockEfs := mocks.NewMockEfs(mockCtl)
+	mockFallbackEfs := mocks.NewMockEfs(mockCtl)
+	c := &cloud{efs: mockEfs, fallbackEfs: mockFallbackEfs, fallbackRegion: "us-west-2"}
+
+	mockEfs.EXPECT().DescribeFileSystemsWithContext(gomock.Any(), gomock.Any()).Return(nil, unavailable)
+	mockFallbackEfs.EXPECT().DescribeFileSystemsWithContext(gomock.Any(), gomock.Any()).Return(&efs.DescribeFileSystemsOutput{
+		FileSystems: []*efs.FileSystemDescription{{FileSystemId: aws.String(fsId)}},
+	}, nil)
+	fileSystem, err := c.DescribeFileSystem(context.Background(), fsId)
+	if err != nil {
+		t.Fatalf("DescribeFileSystem failed: %v", err)
+	}
+	if fileSystem.FileSystemId != fsId {
+		t.Fatalf("FileSystemId mismatched. Expected: %v, Actual: %v", fsId, fileSystem.FileSystemId)
+	}
+
+	// Mutating calls are not failed over
+	mockEfs.EXPECT().DeleteFileSystemWithContext(gomock.Any(), gomock.Any()).Return(nil, unavailable)
+	if err := c.DeleteFileSystem(context.Background(), fsId); err == nil {
+		t.Fatalf("DeleteFileSystem succeeded, expected the error of the region")
+	}
+	mockCtl.Finish()
+}
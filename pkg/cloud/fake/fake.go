@@ -0,0 +1,439 @@
+// Package fake provides an in-memory implementation of cloud.Cloud, to exercise the driver against
+// a backend which keeps state between calls instead of mocking every call.
+package fake
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+
+	"github.com/kubernetes-sigs/aws-efs-csi-driver/pkg/cloud"
+)
+
+const (
+	// Region is the region of the fake instance and of the ARNs of its resources
+	Region = "us-east-1"
+	// AvailabilityZone is the availability zone of the fake instance
+	AvailabilityZone = "us-east-1a"
+	// InstanceID is the ID of the fake instance
+	InstanceID = "i-0123456789abcdef0"
+	// AccountID is the account of the ARNs of the resources
+	AccountID = "123456789012"
+)
+
+var _ cloud.Cloud = &Cloud{}
+
+// Cloud is an in-memory EFS. File systems are created with CreateFileSystem or AddFileSystem, and the
+// other calls fail with cloud.ErrNotFound for resources which do not exist, like the EFS API does.
+// It is safe for concurrent use.
+type Cloud struct {
+	mu           sync.Mutex
+	metadata     *metadata
+	fileSystems  map[string]*fileSystem
+	accessPoints map[string]*accessPoint
+	lastId       int
+	// creatingDescribes is the number of descriptions new access points are creating for
+	creatingDescribes int
+}
+
+type fileSystem struct {
+	cloud.FileSystem
+	clientToken  string
+	mountTargets []*cloud.MountTarget
+	replication  *cloud.ReplicationConfiguration
+}
+
+type accessPoint struct {
+	cloud.AccessPoint
+	clientToken       string
+	creatingDescribes int
+}
+
+type metadata struct {
+	instanceID       string
+	region           string
+	availabilityZone string
+}
+
+func (m *metadata) GetInstanceID() string {
+	return m.instanceID
+}
+
+func (m *metadata) GetRegion() string {
+	return m.region
+}
+
+func (m *metadata) GetAvailabilityZone() string {
+	return m.availabilityZone
+}
+
+// NewCloud returns an empty fake EFS, whose instance is InstanceID in AvailabilityZone
+func NewCloud() *Cloud {
+	return &Cloud{
+		metadata: &metadata{
+			instanceID:       InstanceID,
+			region:           Region,
+			availabilityZone: AvailabilityZone,
+		},
+		fileSystems:  make(map[string]*fileSystem),
+		accessPoints: make(map[string]*accessPoint),
+	}
+}
+
+// AddFileSystem adds a file system with an available mount target in each of the availability zones,
+// or in AvailabilityZone when none is given, and returns it.
+func (c *Cloud) AddFileSystem(fileSystemId string, azNames ...string) *cloud.FileSystem {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.addFileSystem(fileSystemId, "", nil, azNames)
+}
+
+func (c *Cloud) addFileSystem(fileSystemId, clientToken string, tags map[string]string, azNames []string) *cloud.FileSystem {
+	if len(azNames) == 0 {
+		azNames = []string{c.metadata.availabilityZone}
+	}
+	fs := &fileSystem{
+		FileSystem: cloud.FileSystem{
+			FileSystemId: fileSystemId,
+			Tags:         copyTags(tags),
+		},
+		clientToken: clientToken,
+	}
+	for i, azName := range azNames {
+		fs.mountTargets = append(fs.mountTargets, &cloud.MountTarget{
+			AZName:        azName,
+			AZId:          fmt.Sprintf("use1-az%d", i+1),
+			MountTargetId: c.newId("fsmt-", 17),
+			IPAddress:     fmt.Sprintf("10.0.%d.10", i),
+		})
+	}
+	c.fileSystems[fileSystemId] = fs
+	return copyFileSystem(&fs.FileSystem)
+}
+
+// SetCreatingDescribes makes the access points created afterwards report the creating state to the given number
+// of DescribeAccessPoint calls before becoming available, like EFS does for a moment. They never become available
+// when it is negative, and are available right away when it is 0, the default.
+func (c *Cloud) SetCreatingDescribes(describes int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.creatingDescribes = describes
+}
+
+// SetReplication replicates a file system to the destinations, or stops its replication when there is none.
+// The replication is described for the source file system only.
+func (c *Cloud) SetReplication(fileSystemId string, destinations ...*cloud.ReplicationDestination) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	fs, ok := c.fileSystems[fileSystemId]
+	if !ok {
+		return
+	}
+	fs.replication = nil
+	if len(destinations) > 0 {
+		fs.replication = &cloud.ReplicationConfiguration{SourceFileSystemId: fileSystemId}
+		for _, destination := range destinations {
+			d := *destination
+			fs.replication.Destinations = append(fs.replication.Destinations, &d)
+		}
+	}
+}
+
+// newId returns a new resource ID made of the prefix and of a hexadecimal counter of the given width
+func (c *Cloud) newId(prefix string, width int) string {
+	c.lastId++
+	return fmt.Sprintf("%v%0*x", prefix, width, c.lastId)
+}
+
+func (c *Cloud) GetMetadata() cloud.MetadataService {
+	return c.metadata
+}
+
+// WithRegion returns the same fake, resources are not partitioned by region
+func (c *Cloud) WithRegion(region string) (cloud.Cloud, error) {
+	return c, nil
+}
+
+func (c *Cloud) CheckConnectivity(ctx context.Context) error {
+	return nil
+}
+
+func (c *Cloud) CreateAccessPoint(ctx context.Context, clientToken string, accessPointOpts *cloud.AccessPointOptions, reuseAccessPoint bool) (*cloud.AccessPoint, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, ok := c.fileSystems[accessPointOpts.FileSystemId]; !ok {
+		return nil, cloud.ErrNotFound
+	}
+	if ap := c.findAccessPointByClientToken(clientToken); ap != nil {
+		if reuseAccessPoint {
+			return copyAccessPoint(&ap.AccessPoint), nil
+		}
+		return nil, cloud.ErrAlreadyExists
+	}
+	if len(c.listAccessPoints(accessPointOpts.FileSystemId)) >= cloud.AccessPointPerFsLimit {
+		return nil, fmt.Errorf("file system %v has reached the limit of %v access points", accessPointOpts.FileSystemId, cloud.AccessPointPerFsLimit)
+	}
+
+	apId := c.newId("fsap-", 17)
+	ap := &accessPoint{
+		AccessPoint: cloud.AccessPoint{
+			AccessPointId:      apId,
+			AccessPointArn:     fmt.Sprintf("arn:aws:elasticfilesystem:%v:%v:access-point/%v", c.metadata.region, AccountID, apId),
+			FileSystemId:       accessPointOpts.FileSystemId,
+			AccessPointRootDir: accessPointOpts.DirectoryPath,
+			CapacityGiB:        accessPointOpts.CapacityGiB,
+			PosixUser: &cloud.PosixUser{
+				Gid:           accessPointOpts.Gid,
+				Uid:           accessPointOpts.Uid,
+				SecondaryGids: append([]int64(nil), accessPointOpts.SecondaryGids...),
+			},
+			Tags:           copyTags(accessPointOpts.Tags),
+			LifeCycleState: "available",
+		},
+		clientToken:       clientToken,
+		creatingDescribes: c.creatingDescribes,
+	}
+	if ap.creatingDescribes != 0 {
+		ap.LifeCycleState = "creating"
+	}
+	if accessPointOpts.QuotaBytes > 0 {
+		ap.Tags[cloud.QuotaBytesTagKey] = strconv.FormatInt(accessPointOpts.QuotaBytes, 10)
+	}
+	c.accessPoints[apId] = ap
+	return copyAccessPoint(&ap.AccessPoint), nil
+}
+
+func (c *Cloud) DeleteAccessPoint(ctx context.Context, accessPointId string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, ok := c.accessPoints[accessPointId]; !ok {
+		return cloud.ErrNotFound
+	}
+	delete(c.accessPoints, accessPointId)
+	return nil
+}
+
+func (c *Cloud) DescribeAccessPoint(ctx context.Context, accessPointId string) (*cloud.AccessPoint, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	ap, ok := c.accessPoints[accessPointId]
+	if !ok {
+		return nil, cloud.ErrNotFound
+	}
+	if ap.creatingDescribes > 0 {
+		ap.creatingDescribes--
+	} else if ap.creatingDescribes == 0 {
+		ap.LifeCycleState = "available"
+	}
+	return copyAccessPoint(&ap.AccessPoint), nil
+}
+
+func (c *Cloud) TagAccessPoint(ctx context.Context, accessPointId string, tags map[string]string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	ap, ok := c.accessPoints[accessPointId]
+	if !ok {
+		return cloud.ErrNotFound
+	}
+	for k, v := range tags {
+		ap.Tags[k] = v
+	}
+	return nil
+}
+
+func (c *Cloud) UntagAccessPoint(ctx context.Context, accessPointId string, tagKeys []string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	ap, ok := c.accessPoints[accessPointId]
+	if !ok {
+		return cloud.ErrNotFound
+	}
+	for _, k := range tagKeys {
+		delete(ap.Tags, k)
+	}
+	return nil
+}
+
+// ListAccessPoints returns the access points of a file system, ordered by ID
+func (c *Cloud) ListAccessPoints(ctx context.Context, fileSystemId string) ([]*cloud.AccessPoint, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, ok := c.fileSystems[fileSystemId]; !ok {
+		return nil, cloud.ErrNotFound
+	}
+	accessPoints := []*cloud.AccessPoint{}
+	for _, ap := range c.listAccessPoints(fileSystemId) {
+		accessPoints = append(accessPoints, copyAccessPoint(&ap.AccessPoint))
+	}
+	return accessPoints, nil
+}
+
+func (c *Cloud) listAccessPoints(fileSystemId string) []*accessPoint {
+	accessPoints := []*accessPoint{}
+	for _, ap := range c.accessPoints {
+		if ap.FileSystemId == fileSystemId {
+			accessPoints = append(accessPoints, ap)
+		}
+	}
+	sort.Slice(accessPoints, func(i, j int) bool {
+		return accessPoints[i].AccessPointId < accessPoints[j].AccessPointId
+	})
+	return accessPoints
+}
+
+func (c *Cloud) FindAccessPointByClientToken(ctx context.Context, clientToken string, accessPointOpts *cloud.AccessPointOptions) (*cloud.AccessPoint, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	ap := c.findAccessPointByClientToken(clientToken)
+	if ap == nil || ap.FileSystemId != accessPointOpts.FileSystemId {
+		return nil, nil
+	}
+	return copyAccessPoint(&ap.AccessPoint), nil
+}
+
+func (c *Cloud) findAccessPointByClientToken(clientToken string) *accessPoint {
+	for _, ap := range c.accessPoints {
+		if ap.clientToken == clientToken {
+			return ap
+		}
+	}
+	return nil
+}
+
+func (c *Cloud) CreateFileSystem(ctx context.Context, clientToken string, fileSystemOpts *cloud.FileSystemOptions) (*cloud.FileSystem, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, fs := range c.fileSystems {
+		if fs.clientToken != "" && fs.clientToken == clientToken {
+			return copyFileSystem(&fs.FileSystem), nil
+		}
+	}
+	// One Zone file systems have a single mount target, in their zone
+	var azNames []string
+	if fileSystemOpts.AvailabilityZoneName != "" {
+		azNames = []string{fileSystemOpts.AvailabilityZoneName}
+	}
+	fs := c.addFileSystem(c.newId("fs-", 8), clientToken, fileSystemOpts.Tags, azNames)
+	c.fileSystems[fs.FileSystemId].AvailabilityZoneName = fileSystemOpts.AvailabilityZoneName
+	fs.AvailabilityZoneName = fileSystemOpts.AvailabilityZoneName
+	return fs, nil
+}
+
+// DeleteFileSystem deletes a file system, which fails while it has access points like it does with EFS
+func (c *Cloud) DeleteFileSystem(ctx context.Context, fileSystemId string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, ok := c.fileSystems[fileSystemId]; !ok {
+		return cloud.ErrNotFound
+	}
+	if accessPoints := c.listAccessPoints(fileSystemId); len(accessPoints) > 0 {
+		return fmt.Errorf("file system %v is in use by %v access points", fileSystemId, len(accessPoints))
+	}
+	delete(c.fileSystems, fileSystemId)
+	return nil
+}
+
+func (c *Cloud) DescribeFileSystem(ctx context.Context, fileSystemId string) (*cloud.FileSystem, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	fs, ok := c.fileSystems[fileSystemId]
+	if !ok {
+		return nil, cloud.ErrNotFound
+	}
+	return copyFileSystem(&fs.FileSystem), nil
+}
+
+// DescribeMountTargets returns the mount target in the availability zone, or the first one when there is none
+func (c *Cloud) DescribeMountTargets(ctx context.Context, fileSystemId, azName string) (*cloud.MountTarget, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	fs, ok := c.fileSystems[fileSystemId]
+	if !ok {
+		return nil, cloud.ErrNotFound
+	}
+	if len(fs.mountTargets) == 0 {
+		return nil, fmt.Errorf("Cannot find mount targets for file system %v. Please create mount targets for file system.", fileSystemId)
+	}
+	for _, mt := range fs.mountTargets {
+		if mt.AZName == azName {
+			mountTarget := *mt
+			return &mountTarget, nil
+		}
+	}
+	mountTarget := *fs.mountTargets[0]
+	return &mountTarget, nil
+}
+
+// DescribeReplicationConfiguration returns the replication set with SetReplication
+func (c *Cloud) DescribeReplicationConfiguration(ctx context.Context, fileSystemId string) (*cloud.ReplicationConfiguration, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	fs, ok := c.fileSystems[fileSystemId]
+	if !ok || fs.replication == nil {
+		return nil, cloud.ErrNotFound
+	}
+	replication := &cloud.ReplicationConfiguration{SourceFileSystemId: fs.replication.SourceFileSystemId}
+	for _, destination := range fs.replication.Destinations {
+		d := *destination
+		replication.Destinations = append(replication.Destinations, &d)
+	}
+	return replication, nil
+}
+
+func (c *Cloud) ListMountTargets(ctx context.Context, fileSystemId string) ([]*cloud.MountTarget, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	fs, ok := c.fileSystems[fileSystemId]
+	if !ok {
+		return nil, cloud.ErrNotFound
+	}
+	mountTargets := []*cloud.MountTarget{}
+	for _, mt := range fs.mountTargets {
+		mountTarget := *mt
+		mountTargets = append(mountTargets, &mountTarget)
+	}
+	return mountTargets, nil
+}
+
+// copyAccessPoint returns a copy of an access point, so that callers cannot modify the state of the fake
+func copyAccessPoint(ap *cloud.AccessPoint) *cloud.AccessPoint {
+	accessPoint := *ap
+	if ap.PosixUser != nil {
+		posixUser := *ap.PosixUser
+		posixUser.SecondaryGids = append([]int64(nil), ap.PosixUser.SecondaryGids...)
+		accessPoint.PosixUser = &posixUser
+	}
+	accessPoint.Tags = copyTags(ap.Tags)
+	return &accessPoint
+}
+
+func copyFileSystem(fs *cloud.FileSystem) *cloud.FileSystem {
+	fileSystem := *fs
+	fileSystem.Tags = copyTags(fs.Tags)
+	return &fileSystem
+}
+
+func copyTags(tags map[string]string) map[string]string {
+	copied := make(map[string]string, len(tags))
+	for k, v := range tags {
+		copied[k] = v
+	}
+	return copied
+}
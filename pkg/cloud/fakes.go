@@ -27,6 +27,14 @@ func (c *FakeCloudProvider) GetMetadata() MetadataService {
 	return c.m
 }
 
+func (c *FakeCloudProvider) WithRegion(region string) (Cloud, error) {
+	return c, nil
+}
+
+func (c *FakeCloudProvider) CheckConnectivity(ctx context.Context) (err error) {
+	return nil
+}
+
 func (c *FakeCloudProvider) CreateAccessPoint(ctx context.Context, clientToken string, accessPointOpts *AccessPointOptions, usePvcName bool) (accessPoint *AccessPoint, err error) {
 	ap, exists := c.accessPoints[clientToken]
 	if exists {
@@ -40,9 +48,16 @@ func (c *FakeCloudProvider) CreateAccessPoint(ctx context.Context, clientToken s
 	apId := fmt.Sprintf("fsap-%d", r.Uint64())
 	fsId := accessPointOpts.FileSystemId
 	ap = &AccessPoint{
-		AccessPointId: apId,
-		FileSystemId:  fsId,
-		CapacityGiB:   accessPointOpts.CapacityGiB,
+		AccessPointId:      apId,
+		AccessPointArn:     fmt.Sprintf("arn:aws:elasticfilesystem:%v:123456789012:access-point/%v", c.m.GetRegion(), apId),
+		FileSystemId:       fsId,
+		AccessPointRootDir: accessPointOpts.DirectoryPath,
+		CapacityGiB:        accessPointOpts.CapacityGiB,
+		LifeCycleState:     "available",
+		Tags:               map[string]string{},
+	}
+	for k, v := range accessPointOpts.Tags {
+		ap.Tags[k] = v
 	}
 
 	c.accessPoints[clientToken] = ap
@@ -58,6 +73,13 @@ func (c *FakeCloudProvider) DeleteAccessPoint(ctx context.Context, accessPointId
 	return nil
 }
 
+func (c *FakeCloudProvider) FindAccessPointByClientToken(ctx context.Context, clientToken string, accessPointOpts *AccessPointOptions) (accessPoint *AccessPoint, err error) {
+	if ap, ok := c.accessPoints[clientToken]; ok {
+		return ap, nil
+	}
+	return nil, nil
+}
+
 func (c *FakeCloudProvider) DescribeAccessPoint(ctx context.Context, accessPointId string) (accessPoint *AccessPoint, err error) {
 	for _, ap := range c.accessPoints {
 		if ap.AccessPointId == accessPointId {
@@ -67,6 +89,28 @@ func (c *FakeCloudProvider) DescribeAccessPoint(ctx context.Context, accessPoint
 	return nil, ErrNotFound
 }
 
+func (c *FakeCloudProvider) TagAccessPoint(ctx context.Context, accessPointId string, tags map[string]string) (err error) {
+	ap, err := c.DescribeAccessPoint(ctx, accessPointId)
+	if err != nil {
+		return err
+	}
+	for k, v := range tags {
+		ap.Tags[k] = v
+	}
+	return nil
+}
+
+func (c *FakeCloudProvider) UntagAccessPoint(ctx context.Context, accessPointId string, tagKeys []string) (err error) {
+	ap, err := c.DescribeAccessPoint(ctx, accessPointId)
+	if err != nil {
+		return err
+	}
+	for _, k := range tagKeys {
+		delete(ap.Tags, k)
+	}
+	return nil
+}
+
 // CreateVolume calls DescribeFileSystem and then CreateAccessPoint.
 // Add file system into the map here to allow CreateVolume sanity tests to succeed.
 func (c *FakeCloudProvider) DescribeFileSystem(ctx context.Context, fileSystemId string) (fileSystem *FileSystem, err error) {
@@ -98,9 +142,46 @@ func (c *FakeCloudProvider) DescribeMountTargets(ctx context.Context, fileSystem
 	return nil, ErrNotFound
 }
 
+func (c *FakeCloudProvider) ListMountTargets(ctx context.Context, fileSystemId string) (mountTargets []*MountTarget, err error) {
+	if mt, ok := c.mountTargets[fileSystemId]; ok {
+		return []*MountTarget{mt}, nil
+	}
+
+	return nil, ErrNotFound
+}
+
 func (c *FakeCloudProvider) ListAccessPoints(ctx context.Context, fileSystemId string) ([]*AccessPoint, error) {
 	accessPoints := []*AccessPoint{
 		c.accessPoints[fileSystemId],
 	}
 	return accessPoints, nil
 }
+
+func (c *FakeCloudProvider) CreateFileSystem(ctx context.Context, clientToken string, fileSystemOpts *FileSystemOptions) (fileSystem *FileSystem, err error) {
+	if fs, ok := c.fileSystems[clientToken]; ok {
+		return fs, nil
+	}
+
+	r := rand.New(rand.NewSource(time.Now().UnixNano()))
+	fsId := fmt.Sprintf("fs-%d", r.Uint64())
+	fs := &FileSystem{
+		FileSystemId:  fsId,
+		FileSystemArn: fmt.Sprintf("arn:aws:elasticfilesystem:%v:123456789012:file-system/%v", c.m.GetRegion(), fsId),
+		Tags:          fileSystemOpts.Tags,
+	}
+	c.fileSystems[clientToken] = fs
+	return fs, nil
+}
+
+func (c *FakeCloudProvider) DeleteFileSystem(ctx context.Context, fileSystemId string) (err error) {
+	for name, fs := range c.fileSystems {
+		if fs.FileSystemId == fileSystemId {
+			delete(c.fileSystems, name)
+		}
+	}
+	return nil
+}
+
+func (c *FakeCloudProvider) DescribeReplicationConfiguration(ctx context.Context, fileSystemId string) (replication *ReplicationConfiguration, err error) {
+	return nil, ErrNotFound
+}
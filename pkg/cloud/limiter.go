@@ -0,0 +1,117 @@
+package cloud
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"k8s.io/klog/v2"
+)
+
+// CallLimiter bounds the number of mutating EFS calls in flight, to keep mass provisioning from being throttled.
+// Calls above the limit wait for a call in flight to complete, until their context is done.
+// An adaptive limiter halves its limit when a call is throttled, and raises it by one after a limit's worth of
+// successful calls, up to its maximum, so that it settles just below the rate EFS throttles calls at.
+type CallLimiter struct {
+	max      int
+	adaptive bool
+
+	mu       sync.Mutex
+	limit    int
+	inFlight int
+	// released is closed, and replaced, whenever a call in flight completes
+	released chan struct{}
+	// successes counts the calls which succeeded since the limit last changed
+	successes int
+	// generation changes with each decrease of the limit. The calls made before a decrease were throttled at the
+	// previous limit, so that their throttling does not decrease the limit again.
+	generation int
+}
+
+// NewCallLimiter returns a limiter allowing limit calls in flight, or nil, which does not limit calls, when limit is not positive
+func NewCallLimiter(limit int) *CallLimiter {
+	if limit <= 0 {
+		return nil
+	}
+	return &CallLimiter{max: limit, limit: limit, released: make(chan struct{})}
+}
+
+// NewAdaptiveCallLimiter returns a limiter allowing between 1 and max calls in flight depending on the throttling of
+// the calls, starting at max, or nil, which does not limit calls, when max is not positive
+func NewAdaptiveCallLimiter(max int) *CallLimiter {
+	l := NewCallLimiter(max)
+	if l != nil {
+		l.adaptive = true
+	}
+	return l
+}
+
+// Limit returns the number of calls currently allowed in flight, 0 when calls are not limited
+func (l *CallLimiter) Limit() int {
+	if l == nil {
+		return 0
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.limit
+}
+
+// acquire blocks until a call may be made, and returns the function to call with the result of the call once it completes
+func (l *CallLimiter) acquire(ctx context.Context) (func(error), error) {
+	if l == nil {
+		return func(error) {}, nil
+	}
+	l.mu.Lock()
+	for l.inFlight >= l.limit {
+		released, limit := l.released, l.limit
+		l.mu.Unlock()
+		select {
+		case <-released:
+		case <-ctx.Done():
+			return nil, fmt.Errorf("gave up waiting for one of the %d mutating EFS calls in flight to complete: %w", limit, ctx.Err())
+		}
+		l.mu.Lock()
+	}
+	l.inFlight++
+	generation := l.generation
+	l.mu.Unlock()
+	return func(err error) { l.release(generation, err) }, nil
+}
+
+func (l *CallLimiter) release(generation int, err error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.inFlight--
+	if l.adaptive {
+		l.adapt(generation, err)
+	}
+	close(l.released)
+	l.released = make(chan struct{})
+}
+
+// adapt changes the limit of an adaptive limiter after a call completed. Errors other than throttling say nothing
+// about the rate of the calls, and leave the limit as is.
+func (l *CallLimiter) adapt(generation int, err error) {
+	if IsThrottling(err) {
+		l.successes = 0
+		if generation != l.generation || l.limit == 1 {
+			return
+		}
+		l.limit /= 2
+		if l.limit < 1 {
+			l.limit = 1
+		}
+		l.generation++
+		klog.Warningf("Mutating EFS call throttled, lowering the limit of calls in flight to %d: %v", l.limit, err)
+		return
+	}
+	if err != nil || l.limit >= l.max {
+		return
+	}
+	l.successes++
+	if l.successes >= l.limit {
+		l.limit++
+		l.successes = 0
+		klog.V(4).Infof("Raising the limit of mutating EFS calls in flight to %d", l.limit)
+	}
+}
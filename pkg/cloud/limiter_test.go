@@ -0,0 +1,232 @@
+package cloud
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/efs"
+	"github.com/golang/mock/gomock"
+	"github.com/kubernetes-sigs/aws-efs-csi-driver/pkg/cloud/mocks"
+)
+
+func TestCallLimiter(t *testing.T) {
+	testCases := []struct {
+		name     string
+		testFunc func(t *testing.T)
+	}{
+		{
+			name: "Success: Calls in flight are capped",
+			testFunc: func(t *testing.T) {
+				limit, calls := 3, 20
+				limiter := NewCallLimiter(limit)
+
+				var inFlight, maxInFlight int32
+				var wg sync.WaitGroup
+				for i := 0; i < calls; i++ {
+					wg.Add(1)
+					go func() {
+						defer wg.Done()
+						release, err := limiter.acquire(context.Background())
+						if err != nil {
+							t.Errorf("acquire failed: %v", err)
+							return
+						}
+						defer release(nil)
+						n := atomic.AddInt32(&inFlight, 1)
+						for {
+							max := atomic.LoadInt32(&maxInFlight)
+							if n <= max || atomic.CompareAndSwapInt32(&maxInFlight, max, n) {
+								break
+							}
+						}
+						time.Sleep(10 * time.Millisecond)
+						atomic.AddInt32(&inFlight, -1)
+					}()
+				}
+				wg.Wait()
+
+				if maxInFlight != int32(limit) {
+					t.Fatalf("Expected %v calls in flight at most, got %v", limit, maxInFlight)
+				}
+			},
+		},
+		{
+			name: "Fail: Context is done while waiting",
+			testFunc: func(t *testing.T) {
+				limiter := NewCallLimiter(1)
+				release, err := limiter.acquire(context.Background())
+				if err != nil {
+					t.Fatalf("acquire failed: %v", err)
+				}
+
+				ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+				defer cancel()
+				if _, err := limiter.acquire(ctx); !errors.Is(err, context.DeadlineExceeded) {
+					t.Fatalf("Expected a deadline exceeded error, got: %v", err)
+				}
+
+				// The slot is free once released
+				release(nil)
+				release, err = limiter.acquire(context.Background())
+				if err != nil {
+					t.Fatalf("acquire failed: %v", err)
+				}
+				release(nil)
+			},
+		},
+		{
+			name: "Success: Nil limiter does not limit calls",
+			testFunc: func(t *testing.T) {
+				limiter := NewCallLimiter(0)
+				if limiter != nil {
+					t.Fatalf("Expected a nil limiter, got %v", limiter)
+				}
+				for i := 0; i < 10; i++ {
+					if _, err := limiter.acquire(context.Background()); err != nil {
+						t.Fatalf("acquire failed: %v", err)
+					}
+				}
+			},
+		},
+		{
+			name: "Success: Adaptive limit decreases on throttling and recovers",
+			testFunc: func(t *testing.T) {
+				throttled := awserr.New("ThrottlingException", "Rate exceeded", nil)
+				limiter := NewAdaptiveCallLimiter(8)
+				call := func(err error) {
+					release, acquireErr := limiter.acquire(context.Background())
+					if acquireErr != nil {
+						t.Fatalf("acquire failed: %v", acquireErr)
+					}
+					release(err)
+				}
+				expectLimit := func(expected int) {
+					if limit := limiter.Limit(); limit != expected {
+						t.Fatalf("Limit mismatched. Expected: %v, Actual: %v", expected, limit)
+					}
+				}
+
+				// Calls throttled together lower the limit once
+				var releases []func(error)
+				for i := 0; i < 3; i++ {
+					release, err := limiter.acquire(context.Background())
+					if err != nil {
+						t.Fatalf("acquire failed: %v", err)
+					}
+					releases = append(releases, release)
+				}
+				for _, release := range releases {
+					release(throttled)
+				}
+				expectLimit(4)
+
+				// Throttling of later calls lowers it again, down to a single call
+				call(throttled)
+				expectLimit(2)
+				call(throttled)
+				call(throttled)
+				expectLimit(1)
+
+				// Other errors leave the limit as is
+				call(errors.New("Access point already exists"))
+				expectLimit(1)
+
+				// Successes raise it by one per limit's worth of calls, up to the maximum
+				call(nil)
+				expectLimit(2)
+				call(nil)
+				call(nil)
+				expectLimit(3)
+				for i := 0; i < 100; i++ {
+					call(nil)
+				}
+				expectLimit(8)
+			},
+		},
+		{
+			name: "Success: Fixed limit does not adapt",
+			testFunc: func(t *testing.T) {
+				limiter := NewCallLimiter(4)
+				release, err := limiter.acquire(context.Background())
+				if err != nil {
+					t.Fatalf("acquire failed: %v", err)
+				}
+				release(awserr.New("ThrottlingException", "Rate exceeded", nil))
+				if limit := limiter.Limit(); limit != 4 {
+					t.Fatalf("Limit mismatched. Expected: %v, Actual: %v", 4, limit)
+				}
+			},
+		},
+		{
+			name: "Success: Throttled CreateAccessPoint lowers the adaptive limit",
+			testFunc: func(t *testing.T) {
+				mockCtl := gomock.NewController(t)
+				mockEfs := mocks.NewMockEfs(mockCtl)
+				c := &cloud{efs: mockEfs, limiter: NewAdaptiveCallLimiter(4)}
+				accessPointOpts := &AccessPointOptions{
+					FileSystemId:   "fs-abcd1234",
+					DirectoryPerms: "0777",
+					DirectoryPath:  "/test",
+				}
+
+				mockEfs.EXPECT().CreateAccessPointWithContext(gomock.Any(), gomock.Any()).Return(nil, awserr.New("ThrottlingException", "Rate exceeded", nil))
+				if _, err := c.CreateAccessPoint(context.Background(), "volName1", accessPointOpts, false); !IsThrottling(err) {
+					t.Fatalf("Expected a throttling error, got: %v", err)
+				}
+				if limit := c.limiter.Limit(); limit != 2 {
+					t.Fatalf("Limit mismatched. Expected: %v, Actual: %v", 2, limit)
+				}
+				mockCtl.Finish()
+			},
+		},
+		{
+			name: "Fail: CreateAccessPoint waiting for a call in flight times out",
+			testFunc: func(t *testing.T) {
+				mockCtl := gomock.NewController(t)
+				mockEfs := mocks.NewMockEfs(mockCtl)
+				c := &cloud{efs: mockEfs, limiter: NewCallLimiter(1)}
+				accessPointOpts := &AccessPointOptions{
+					FileSystemId:   "fs-abcd1234",
+					DirectoryPerms: "0777",
+					DirectoryPath:  "/test",
+				}
+
+				started, done := make(chan struct{}), make(chan struct{})
+				mockEfs.EXPECT().CreateAccessPointWithContext(gomock.Any(), gomock.Any()).DoAndReturn(
+					func(ctx aws.Context, input *efs.CreateAccessPointInput, opts ...request.Option) (*efs.CreateAccessPointOutput, error) {
+						close(started)
+						<-done
+						return &efs.CreateAccessPointOutput{
+							AccessPointId: aws.String("fsap-abcd1234xyz987"),
+							FileSystemId:  aws.String("fs-abcd1234"),
+						}, nil
+					})
+				go func() {
+					if _, err := c.CreateAccessPoint(context.Background(), "volName1", accessPointOpts, false); err != nil {
+						t.Errorf("CreateAccessPoint failed: %v", err)
+					}
+				}()
+				<-started
+				defer close(done)
+
+				// The second call must not reach EFS
+				ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+				defer cancel()
+				if _, err := c.CreateAccessPoint(ctx, "volName2", accessPointOpts, false); !errors.Is(err, context.DeadlineExceeded) {
+					t.Fatalf("Expected a deadline exceeded error, got: %v", err)
+				}
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, tc.testFunc)
+	}
+}
@@ -0,0 +1,41 @@
+package driver
+
+import (
+	"fmt"
+	"regexp"
+)
+
+const (
+	// maxAccessPointNamePrefixLength leaves room in the 64 characters of a client token for the CSI volume name
+	maxAccessPointNamePrefixLength = 24
+	// maxClientTokenLength is the maximum length of the client token of an access point
+	maxClientTokenLength = 64
+)
+
+// validAccessPointNamePrefix only allows characters valid in both client tokens and tag values
+var validAccessPointNamePrefix = regexp.MustCompile(`^[a-zA-Z0-9_.:/=+@-]*$`)
+
+// ValidateAccessPointNamePrefix validates the --access-point-name-prefix value
+func ValidateAccessPointNamePrefix(prefix string) error {
+	if len(prefix) > maxAccessPointNamePrefixLength {
+		return fmt.Errorf("prefix %q is longer than %v characters", prefix, maxAccessPointNamePrefixLength)
+	}
+	if !validAccessPointNamePrefix.MatchString(prefix) {
+		return fmt.Errorf("prefix %q contains characters other than letters, numbers and _.:/=+-@", prefix)
+	}
+	return nil
+}
+
+// accessPointClientToken namespaces the client token of an access point with --access-point-name-prefix, so that
+// clusters sharing a file system never create or reuse each other's access points for volumes of the same name.
+// Tokens which no longer fit in a client token once prefixed are hashed.
+func (d *Driver) accessPointClientToken(token string) string {
+	if d.accessPointNamePrefix == "" {
+		return token
+	}
+	token = d.accessPointNamePrefix + token
+	if len(token) > maxClientTokenLength {
+		return get64LenHash(token)
+	}
+	return token
+}
@@ -0,0 +1,145 @@
+package driver
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	"github.com/golang/mock/gomock"
+
+	"github.com/kubernetes-sigs/aws-efs-csi-driver/pkg/cloud"
+	"github.com/kubernetes-sigs/aws-efs-csi-driver/pkg/driver/mocks"
+)
+
+func TestValidateAccessPointNamePrefix(t *testing.T) {
+	testCases := []struct {
+		name      string
+		prefix    string
+		expectErr bool
+	}{
+		{
+			name:   "Success: Empty",
+			prefix: "",
+		},
+		{
+			name:   "Success: Prefix",
+			prefix: "cluster-a/",
+		},
+		{
+			name:      "Fail: Too long",
+			prefix:    strings.Repeat("a", maxAccessPointNamePrefixLength+1),
+			expectErr: true,
+		},
+		{
+			name:      "Fail: Invalid characters",
+			prefix:    "cluster#a",
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateAccessPointNamePrefix(tc.prefix)
+			if tc.expectErr && err == nil {
+				t.Fatalf("Expected an error for prefix %q", tc.prefix)
+			}
+			if !tc.expectErr && err != nil {
+				t.Fatalf("ValidateAccessPointNamePrefix failed: %v", err)
+			}
+		})
+	}
+}
+
+func TestCreateVolumeAccessPointNamePrefix(t *testing.T) {
+	var (
+		fsId       = "fs-abcd1234"
+		volumeName = "pvc-5f2ed5c1-1a43-4d2e-9a63-8d1a3f0c4b21"
+	)
+
+	testCases := []struct {
+		name          string
+		prefix        string
+		params        map[string]string
+		expectedToken string
+		expectedName  string
+	}{
+		{
+			name:          "Success: Client token and name are prefixed",
+			prefix:        "cluster-a-",
+			params:        map[string]string{NameTag: "data"},
+			expectedToken: "cluster-a-" + volumeName,
+			expectedName:  "cluster-a-data",
+		},
+		{
+			name:          "Success: Client token too long once prefixed is hashed",
+			prefix:        "cluster-with-a-long-name-",
+			expectedToken: get64LenHash("cluster-with-a-long-name-" + volumeName),
+		},
+		{
+			name:          "Success: Client token of a reused access point is prefixed",
+			prefix:        "cluster-a-",
+			params:        map[string]string{ReuseAccessPointKey: "true", PvcNameKey: "data"},
+			expectedToken: get64LenHash("cluster-a-" + get64LenHash("data")),
+		},
+		{
+			name:          "Success: No prefix",
+			params:        map[string]string{NameTag: "data"},
+			expectedToken: volumeName,
+			expectedName:  "data",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockCtl := gomock.NewController(t)
+			mockCloud := mocks.NewMockCloud(mockCtl)
+			driver := &Driver{
+				cloud:                 mockCloud,
+				gidAllocator:          NewGidAllocator(),
+				accessPointNamePrefix: tc.prefix,
+			}
+
+			params := map[string]string{
+				ProvisioningMode: AccessPointMode,
+				FsId:             fsId,
+				DirectoryPerms:   "700",
+				Uid:              "1000",
+				Gid:              "1000",
+			}
+			for k, v := range tc.params {
+				params[k] = v
+			}
+
+			ctx := context.Background()
+			mockCloud.EXPECT().DescribeFileSystem(gomock.Eq(ctx), gomock.Eq(fsId)).Return(&cloud.FileSystem{FileSystemId: fsId}, nil)
+			mockCloud.EXPECT().CreateAccessPoint(gomock.Eq(ctx), gomock.Eq(tc.expectedToken), gomock.Any(), gomock.Any()).DoAndReturn(
+				func(ctx context.Context, clientToken string, accessPointOpts *cloud.AccessPointOptions, reuseAccessPoint bool) (*cloud.AccessPoint, error) {
+					if name := accessPointOpts.Tags[NameTagKey]; name != tc.expectedName {
+						t.Fatalf("Name tag mismatched. Expected: %q, Actual: %q", tc.expectedName, name)
+					}
+					return &cloud.AccessPoint{AccessPointId: "fsap-abcd1234xyz987", FileSystemId: fsId}, nil
+				})
+
+			_, err := driver.CreateVolume(ctx, &csi.CreateVolumeRequest{
+				Name: volumeName,
+				VolumeCapabilities: []*csi.VolumeCapability{
+					{
+						AccessType: &csi.VolumeCapability_Mount{
+							Mount: &csi.VolumeCapability_MountVolume{},
+						},
+						AccessMode: &csi.VolumeCapability_AccessMode{
+							Mode: csi.VolumeCapability_AccessMode_MULTI_NODE_MULTI_WRITER,
+						},
+					},
+				},
+				CapacityRange: &csi.CapacityRange{RequiredBytes: 5 * 1024 * 1024 * 1024},
+				Parameters:    params,
+			})
+			if err != nil {
+				t.Fatalf("CreateVolume failed: %v", err)
+			}
+			mockCtl.Finish()
+		})
+	}
+}
@@ -0,0 +1,58 @@
+package driver
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"k8s.io/klog/v2"
+
+	"github.com/kubernetes-sigs/aws-efs-csi-driver/pkg/cloud"
+)
+
+// DefaultAccessPointWarnThreshold is the default fraction of the access point limit of a file system above which a warning is logged
+const DefaultAccessPointWarnThreshold = 0.8
+
+var (
+	// accessPointUtilization is the fraction of the access point limit used on each file system, as of its last listing
+	accessPointUtilization = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "efs_csi_access_point_utilization",
+		Help: "Fraction of the access point limit of the file system in use, as of the last listing of its access points by the controller.",
+	}, []string{"file_system_id"})
+	// metricsRegistry holds the metrics served on --metrics-address
+	metricsRegistry = prometheus.NewRegistry()
+)
+
+func init() {
+	metricsRegistry.MustRegister(accessPointUtilization)
+}
+
+// recordAccessPointUtilization records the number of access points listed on a file system, and logs a warning when
+// it crosses --access-point-warn-threshold, before provisioning fails on the limit. The warning is logged again only
+// once the utilization went below the threshold. It returns whether the warning was logged.
+func (d *Driver) recordAccessPointUtilization(fileSystemId string, accessPoints int) bool {
+	utilization := float64(accessPoints) / cloud.AccessPointPerFsLimit
+	accessPointUtilization.WithLabelValues(fileSystemId).Set(utilization)
+	if d.accessPointWarnThreshold <= 0 {
+		return false
+	}
+
+	above := utilization >= d.accessPointWarnThreshold
+	wasAbove, _ := d.accessPointWarnings.Swap(fileSystemId, above)
+	if !above || wasAbove == true {
+		return false
+	}
+	klog.Warningf("File system %v uses %v of its %v access points, above the warning threshold of %v%%. CreateVolume fails once the limit is reached",
+		fileSystemId, accessPoints, cloud.AccessPointPerFsLimit, d.accessPointWarnThreshold*100)
+	return true
+}
+
+// serveMetrics serves the metrics of the driver on /metrics at --metrics-address. Failures are logged and not fatal.
+func (d *Driver) serveMetrics() {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.HandlerFor(metricsRegistry, promhttp.HandlerOpts{}))
+	klog.Infof("Serving metrics on %v/metrics", d.metricsAddress)
+	if err := http.ListenAndServe(d.metricsAddress, mux); err != nil {
+		klog.Errorf("Failed to serve metrics on %v: %v", d.metricsAddress, err)
+	}
+}
@@ -0,0 +1,102 @@
+package driver
+
+import (
+	"context"
+	"testing"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/kubernetes-sigs/aws-efs-csi-driver/pkg/cloud/fake"
+)
+
+func TestRecordAccessPointUtilization(t *testing.T) {
+	fsId := "fs-abcd1234"
+
+	testCases := []struct {
+		name      string
+		threshold float64
+		// accessPoints are the numbers of access points of successive listings, and warnings whether each one warns
+		accessPoints []int
+		warnings     []bool
+	}{
+		{
+			name:         "Warning at the threshold",
+			threshold:    0.8,
+			accessPoints: []int{799, 800},
+			warnings:     []bool{false, true},
+		},
+		{
+			name:         "Warning once above the threshold",
+			threshold:    0.8,
+			accessPoints: []int{850, 900, 1000},
+			warnings:     []bool{true, false, false},
+		},
+		{
+			name:         "Warning again after going below the threshold",
+			threshold:    0.8,
+			accessPoints: []int{850, 700, 800},
+			warnings:     []bool{true, false, true},
+		},
+		{
+			name:         "No warning when disabled",
+			accessPoints: []int{1000},
+			warnings:     []bool{false},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			driver := &Driver{accessPointWarnThreshold: tc.threshold}
+			for i, accessPoints := range tc.accessPoints {
+				if warned := driver.recordAccessPointUtilization(fsId, accessPoints); warned != tc.warnings[i] {
+					t.Fatalf("Warning mismatched for %v access points. Expected: %v, Actual: %v", accessPoints, tc.warnings[i], warned)
+				}
+				expected := float64(accessPoints) / 1000
+				if utilization := testutil.ToFloat64(accessPointUtilization.WithLabelValues(fsId)); utilization != expected {
+					t.Fatalf("Utilization mismatched. Expected: %v, Actual: %v", expected, utilization)
+				}
+			}
+		})
+	}
+}
+
+func TestCreateVolumeAccessPointUtilization(t *testing.T) {
+	fsId := "fs-ef015678"
+	fakeCloud := fake.NewCloud()
+	fakeCloud.AddFileSystem(fsId)
+	driver := &Driver{
+		cloud:                    fakeCloud,
+		gidAllocator:             NewGidAllocator(),
+		accessPointWarnThreshold: DefaultAccessPointWarnThreshold,
+	}
+
+	for _, name := range []string{"pvc-1", "pvc-2", "pvc-3"} {
+		_, err := driver.CreateVolume(context.Background(), &csi.CreateVolumeRequest{
+			Name: name,
+			VolumeCapabilities: []*csi.VolumeCapability{
+				{
+					AccessType: &csi.VolumeCapability_Mount{
+						Mount: &csi.VolumeCapability_MountVolume{},
+					},
+					AccessMode: &csi.VolumeCapability_AccessMode{
+						Mode: csi.VolumeCapability_AccessMode_MULTI_NODE_MULTI_WRITER,
+					},
+				},
+			},
+			CapacityRange: &csi.CapacityRange{RequiredBytes: 5 * 1024 * 1024 * 1024},
+			Parameters: map[string]string{
+				ProvisioningMode: AccessPointMode,
+				FsId:             fsId,
+				DirectoryPerms:   "700",
+			},
+		})
+		if err != nil {
+			t.Fatalf("CreateVolume failed: %v", err)
+		}
+	}
+	// The access points are counted as listed before creating the last one
+	if utilization := testutil.ToFloat64(accessPointUtilization.WithLabelValues(fsId)); utilization != 0.002 {
+		t.Fatalf("Utilization mismatched. Expected: %v, Actual: %v", 0.002, utilization)
+	}
+}
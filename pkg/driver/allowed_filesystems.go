@@ -0,0 +1,33 @@
+package driver
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// ParseAllowedFileSystemIds parses the comma separated --allowed-filesystem-ids value into a single expression.
+// Each entry is a file system ID or a regular expression, which must match the whole ID, for example fs-abcd1234 or fs-0123.*
+// It returns nil when the value is empty, allowing every file system.
+func ParseAllowedFileSystemIds(value string) (*regexp.Regexp, error) {
+	entries := []string{}
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		if _, err := regexp.Compile(entry); err != nil {
+			return nil, fmt.Errorf("invalid file system ID expression %q: %v", entry, err)
+		}
+		entries = append(entries, "(?:"+entry+")")
+	}
+	if len(entries) == 0 {
+		return nil, nil
+	}
+	return regexp.Compile("^(?:" + strings.Join(entries, "|") + ")$")
+}
+
+// isAllowedFileSystem returns whether volumes may be provisioned on a file system
+func (d *Driver) isAllowedFileSystem(fileSystemId string) bool {
+	return d.allowedFileSystemIds == nil || d.allowedFileSystemIds.MatchString(fileSystemId)
+}
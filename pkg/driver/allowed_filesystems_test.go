@@ -0,0 +1,158 @@
+package driver
+
+import (
+	"context"
+	"testing"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	"github.com/golang/mock/gomock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/kubernetes-sigs/aws-efs-csi-driver/pkg/cloud"
+	"github.com/kubernetes-sigs/aws-efs-csi-driver/pkg/driver/mocks"
+)
+
+func TestParseAllowedFileSystemIds(t *testing.T) {
+	testCases := []struct {
+		name        string
+		value       string
+		allowed     []string
+		denied      []string
+		expectError bool
+	}{
+		{
+			name:    "Success: Empty value allows every file system",
+			value:   " , ",
+			allowed: []string{"fs-abcd1234", "fs-01234567"},
+		},
+		{
+			name:    "Success: List of IDs",
+			value:   "fs-abcd1234, fs-01234567",
+			allowed: []string{"fs-abcd1234", "fs-01234567"},
+			denied:  []string{"fs-abcd12345", "fs-abcd", "fs-99999999"},
+		},
+		{
+			name:    "Success: Regular expression matching whole IDs",
+			value:   "fs-abcd.*,fs-0123456[0-9]",
+			allowed: []string{"fs-abcd1234", "fs-abcd", "fs-01234567"},
+			denied:  []string{"fs-01234567a", "xfs-abcd1234", "fs-99999999"},
+		},
+		{
+			name:        "Fail: Invalid regular expression",
+			value:       "fs-abcd1234,fs-(",
+			expectError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			allowedFileSystemIds, err := ParseAllowedFileSystemIds(tc.value)
+			if tc.expectError {
+				if err == nil {
+					t.Fatalf("ParseAllowedFileSystemIds did not fail")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseAllowedFileSystemIds failed: %v", err)
+			}
+			driver := &Driver{allowedFileSystemIds: allowedFileSystemIds}
+			for _, fsId := range tc.allowed {
+				if !driver.isAllowedFileSystem(fsId) {
+					t.Errorf("File system %v is not allowed", fsId)
+				}
+			}
+			for _, fsId := range tc.denied {
+				if driver.isAllowedFileSystem(fsId) {
+					t.Errorf("File system %v is allowed", fsId)
+				}
+			}
+		})
+	}
+}
+
+func TestCreateVolumeAllowedFileSystemIds(t *testing.T) {
+	var (
+		apId      = "fsap-abcd1234xyz987"
+		stdVolCap = &csi.VolumeCapability{
+			AccessType: &csi.VolumeCapability_Mount{
+				Mount: &csi.VolumeCapability_MountVolume{},
+			},
+			AccessMode: &csi.VolumeCapability_AccessMode{
+				Mode: csi.VolumeCapability_AccessMode_MULTI_NODE_MULTI_WRITER,
+			},
+		}
+	)
+
+	createRequest := func(fsId string) *csi.CreateVolumeRequest {
+		return &csi.CreateVolumeRequest{
+			Name:               "volumeName",
+			VolumeCapabilities: []*csi.VolumeCapability{stdVolCap},
+			Parameters: map[string]string{
+				ProvisioningMode: "efs-ap",
+				FsId:             fsId,
+				DirectoryPerms:   "777",
+			},
+		}
+	}
+
+	allowedFileSystemIds, err := ParseAllowedFileSystemIds("fs-abcd1234,fs-ffff.*")
+	if err != nil {
+		t.Fatalf("ParseAllowedFileSystemIds failed: %v", err)
+	}
+
+	testCases := []struct {
+		name     string
+		testFunc func(t *testing.T)
+	}{
+		{
+			name: "Success: File system allowed",
+			testFunc: func(t *testing.T) {
+				mockCtl := gomock.NewController(t)
+				mockCloud := mocks.NewMockCloud(mockCtl)
+				driver := &Driver{
+					cloud:                mockCloud,
+					gidAllocator:         NewGidAllocator(),
+					allowedFileSystemIds: allowedFileSystemIds,
+				}
+
+				fsId := "fs-abcd1234"
+				accessPoint := &cloud.AccessPoint{
+					AccessPointId: apId,
+					FileSystemId:  fsId,
+				}
+				ctx := context.Background()
+				mockCloud.EXPECT().ListAccessPoints(gomock.Eq(ctx), gomock.Eq(fsId)).Return(nil, nil)
+				mockCloud.EXPECT().CreateAccessPoint(gomock.Eq(ctx), gomock.Any(), gomock.Any(), gomock.Any()).Return(accessPoint, nil)
+				_, err := driver.CreateVolume(ctx, createRequest(fsId))
+				if err != nil {
+					t.Fatalf("CreateVolume failed: %v", err)
+				}
+				mockCtl.Finish()
+			},
+		},
+		{
+			name: "Fail: File system denied",
+			testFunc: func(t *testing.T) {
+				mockCtl := gomock.NewController(t)
+				mockCloud := mocks.NewMockCloud(mockCtl)
+				driver := &Driver{
+					cloud:                mockCloud,
+					gidAllocator:         NewGidAllocator(),
+					allowedFileSystemIds: allowedFileSystemIds,
+				}
+
+				_, err := driver.CreateVolume(context.Background(), createRequest("fs-01234567"))
+				if status.Code(err) != codes.PermissionDenied {
+					t.Fatalf("Expected a PermissionDenied error, got: %v", err)
+				}
+				mockCtl.Finish()
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, tc.testFunc)
+	}
+}
@@ -0,0 +1,25 @@
+package driver
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/kubernetes-sigs/aws-efs-csi-driver/pkg/cloud"
+)
+
+// newCallLimiter returns the limiter of the mutating EFS calls of --max-concurrent-create, whose limit adapts to the
+// throttling of the calls with --adaptive-concurrency. Its current limit is exported as a metric.
+func newCallLimiter(maxConcurrentCreate int, adaptiveConcurrency bool) *cloud.CallLimiter {
+	limiter := cloud.NewCallLimiter(maxConcurrentCreate)
+	if adaptiveConcurrency {
+		limiter = cloud.NewAdaptiveCallLimiter(maxConcurrentCreate)
+	}
+	if limiter != nil {
+		metricsRegistry.MustRegister(prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Name: "efs_csi_mutating_call_limit",
+			Help: "Number of mutating EFS calls allowed in flight, which adapts to the throttling of the calls with --adaptive-concurrency.",
+		}, func() float64 {
+			return float64(limiter.Limit())
+		}))
+	}
+	return limiter
+}
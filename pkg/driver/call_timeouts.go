@@ -0,0 +1,219 @@
+package driver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/kubernetes-sigs/aws-efs-csi-driver/pkg/cloud"
+)
+
+// DefaultCallTimeoutKey is the key of --cloud-call-timeouts setting the timeout of the calls not listed
+const DefaultCallTimeoutKey = "default"
+
+// timedCalls are the calls of cloud.Cloud which can be given a timeout by --cloud-call-timeouts
+var timedCalls = map[string]bool{
+	"CreateAccessPoint":                true,
+	"DeleteAccessPoint":                true,
+	"DescribeAccessPoint":              true,
+	"TagAccessPoint":                   true,
+	"UntagAccessPoint":                 true,
+	"ListAccessPoints":                 true,
+	"FindAccessPointByClientToken":     true,
+	"CreateFileSystem":                 true,
+	"DeleteFileSystem":                 true,
+	"DescribeFileSystem":               true,
+	"DescribeMountTargets":             true,
+	"ListMountTargets":                 true,
+	"DescribeReplicationConfiguration": true,
+	"CheckConnectivity":                true,
+}
+
+// ParseCloudCallTimeouts parses the comma separated --cloud-call-timeouts value, a list of call=duration entries,
+// e.g. default=10s,ListAccessPoints=1m. The default entry applies to the calls not listed.
+// It returns nil when the value is empty, leaving calls bounded by the deadline of the CSI call only.
+func ParseCloudCallTimeouts(value string) (map[string]time.Duration, error) {
+	var timeouts map[string]time.Duration
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		call, duration, ok := strings.Cut(entry, "=")
+		call, duration = strings.TrimSpace(call), strings.TrimSpace(duration)
+		if !ok || call == "" || duration == "" {
+			return nil, fmt.Errorf("invalid entry %q: must be call=duration", entry)
+		}
+		if call != DefaultCallTimeoutKey && !timedCalls[call] {
+			calls := make([]string, 0, len(timedCalls))
+			for c := range timedCalls {
+				calls = append(calls, c)
+			}
+			sort.Strings(calls)
+			return nil, fmt.Errorf("unknown call %q, must be %v or one of %v", call, DefaultCallTimeoutKey, strings.Join(calls, ", "))
+		}
+		timeout, err := time.ParseDuration(duration)
+		if err != nil {
+			return nil, fmt.Errorf("invalid timeout of %v: %v", call, err)
+		}
+		if timeout <= 0 {
+			return nil, fmt.Errorf("invalid timeout of %v: must be positive", call)
+		}
+		if timeouts == nil {
+			timeouts = map[string]time.Duration{}
+		}
+		if _, ok := timeouts[call]; ok {
+			return nil, fmt.Errorf("call %v is set more than once", call)
+		}
+		timeouts[call] = timeout
+	}
+	return timeouts, nil
+}
+
+// timeoutCloud bounds each call to EFS by its timeout, so that a slow call fails on its own instead of consuming
+// the deadline of the whole CSI call. Calls without a timeout are bounded by the deadline of the CSI call only.
+type timeoutCloud struct {
+	cloud.Cloud
+	timeouts map[string]time.Duration
+}
+
+// withCallTimeouts wraps the cloud with the timeouts of --cloud-call-timeouts, if any
+func (d *Driver) withCallTimeouts(c cloud.Cloud) cloud.Cloud {
+	if len(d.cloudCallTimeouts) == 0 {
+		return c
+	}
+	return &timeoutCloud{Cloud: c, timeouts: d.cloudCallTimeouts}
+}
+
+// call runs the call with a context bounded by its timeout. A call failing once its own timeout expired returns
+// an error naming it, unless the CSI call itself was cancelled or reached its deadline first.
+func (c *timeoutCloud) call(ctx context.Context, name string, call func(ctx context.Context) error) error {
+	timeout, ok := c.timeouts[name]
+	if !ok {
+		timeout, ok = c.timeouts[DefaultCallTimeoutKey]
+	}
+	if !ok {
+		return call(ctx)
+	}
+	callCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	err := call(callCtx)
+	if err != nil && ctx.Err() == nil && errors.Is(callCtx.Err(), context.DeadlineExceeded) {
+		return fmt.Errorf("%v timed out after %v: %w", name, timeout, err)
+	}
+	return err
+}
+
+func (c *timeoutCloud) CreateAccessPoint(ctx context.Context, clientToken string, accessPointOpts *cloud.AccessPointOptions, reuseAccessPoint bool) (accessPoint *cloud.AccessPoint, err error) {
+	err = c.call(ctx, "CreateAccessPoint", func(ctx context.Context) error {
+		accessPoint, err = c.Cloud.CreateAccessPoint(ctx, clientToken, accessPointOpts, reuseAccessPoint)
+		return err
+	})
+	return accessPoint, err
+}
+
+func (c *timeoutCloud) DeleteAccessPoint(ctx context.Context, accessPointId string) error {
+	return c.call(ctx, "DeleteAccessPoint", func(ctx context.Context) error {
+		return c.Cloud.DeleteAccessPoint(ctx, accessPointId)
+	})
+}
+
+func (c *timeoutCloud) DescribeAccessPoint(ctx context.Context, accessPointId string) (accessPoint *cloud.AccessPoint, err error) {
+	err = c.call(ctx, "DescribeAccessPoint", func(ctx context.Context) error {
+		accessPoint, err = c.Cloud.DescribeAccessPoint(ctx, accessPointId)
+		return err
+	})
+	return accessPoint, err
+}
+
+func (c *timeoutCloud) TagAccessPoint(ctx context.Context, accessPointId string, tags map[string]string) error {
+	return c.call(ctx, "TagAccessPoint", func(ctx context.Context) error {
+		return c.Cloud.TagAccessPoint(ctx, accessPointId, tags)
+	})
+}
+
+func (c *timeoutCloud) UntagAccessPoint(ctx context.Context, accessPointId string, tagKeys []string) error {
+	return c.call(ctx, "UntagAccessPoint", func(ctx context.Context) error {
+		return c.Cloud.UntagAccessPoint(ctx, accessPointId, tagKeys)
+	})
+}
+
+func (c *timeoutCloud) ListAccessPoints(ctx context.Context, fileSystemId string) (accessPoints []*cloud.AccessPoint, err error) {
+	err = c.call(ctx, "ListAccessPoints", func(ctx context.Context) error {
+		accessPoints, err = c.Cloud.ListAccessPoints(ctx, fileSystemId)
+		return err
+	})
+	return accessPoints, err
+}
+
+func (c *timeoutCloud) FindAccessPointByClientToken(ctx context.Context, clientToken string, accessPointOpts *cloud.AccessPointOptions) (accessPoint *cloud.AccessPoint, err error) {
+	err = c.call(ctx, "FindAccessPointByClientToken", func(ctx context.Context) error {
+		accessPoint, err = c.Cloud.FindAccessPointByClientToken(ctx, clientToken, accessPointOpts)
+		return err
+	})
+	return accessPoint, err
+}
+
+func (c *timeoutCloud) CreateFileSystem(ctx context.Context, clientToken string, fileSystemOpts *cloud.FileSystemOptions) (fs *cloud.FileSystem, err error) {
+	err = c.call(ctx, "CreateFileSystem", func(ctx context.Context) error {
+		fs, err = c.Cloud.CreateFileSystem(ctx, clientToken, fileSystemOpts)
+		return err
+	})
+	return fs, err
+}
+
+func (c *timeoutCloud) DeleteFileSystem(ctx context.Context, fileSystemId string) error {
+	return c.call(ctx, "DeleteFileSystem", func(ctx context.Context) error {
+		return c.Cloud.DeleteFileSystem(ctx, fileSystemId)
+	})
+}
+
+func (c *timeoutCloud) DescribeFileSystem(ctx context.Context, fileSystemId string) (fs *cloud.FileSystem, err error) {
+	err = c.call(ctx, "DescribeFileSystem", func(ctx context.Context) error {
+		fs, err = c.Cloud.DescribeFileSystem(ctx, fileSystemId)
+		return err
+	})
+	return fs, err
+}
+
+func (c *timeoutCloud) DescribeMountTargets(ctx context.Context, fileSystemId, az string) (mountTarget *cloud.MountTarget, err error) {
+	err = c.call(ctx, "DescribeMountTargets", func(ctx context.Context) error {
+		mountTarget, err = c.Cloud.DescribeMountTargets(ctx, fileSystemId, az)
+		return err
+	})
+	return mountTarget, err
+}
+
+func (c *timeoutCloud) ListMountTargets(ctx context.Context, fileSystemId string) (mountTargets []*cloud.MountTarget, err error) {
+	err = c.call(ctx, "ListMountTargets", func(ctx context.Context) error {
+		mountTargets, err = c.Cloud.ListMountTargets(ctx, fileSystemId)
+		return err
+	})
+	return mountTargets, err
+}
+
+func (c *timeoutCloud) DescribeReplicationConfiguration(ctx context.Context, fileSystemId string) (replication *cloud.ReplicationConfiguration, err error) {
+	err = c.call(ctx, "DescribeReplicationConfiguration", func(ctx context.Context) error {
+		replication, err = c.Cloud.DescribeReplicationConfiguration(ctx, fileSystemId)
+		return err
+	})
+	return replication, err
+}
+
+func (c *timeoutCloud) CheckConnectivity(ctx context.Context) error {
+	return c.call(ctx, "CheckConnectivity", func(ctx context.Context) error {
+		return c.Cloud.CheckConnectivity(ctx)
+	})
+}
+
+// WithRegion keeps the timeouts of the clouds of other regions
+func (c *timeoutCloud) WithRegion(region string) (cloud.Cloud, error) {
+	regional, err := c.Cloud.WithRegion(region)
+	if err != nil {
+		return nil, err
+	}
+	return &timeoutCloud{Cloud: regional, timeouts: c.timeouts}, nil
+}
@@ -0,0 +1,230 @@
+package driver
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	"github.com/golang/mock/gomock"
+
+	"github.com/kubernetes-sigs/aws-efs-csi-driver/pkg/cloud"
+	"github.com/kubernetes-sigs/aws-efs-csi-driver/pkg/driver/mocks"
+)
+
+func TestParseCloudCallTimeouts(t *testing.T) {
+	testCases := []struct {
+		name      string
+		value     string
+		expected  map[string]time.Duration
+		expectErr bool
+	}{
+		{
+			name:  "Success: Empty",
+			value: "",
+		},
+		{
+			name:     "Success: Timeouts",
+			value:    "default=10s, ListAccessPoints = 1m",
+			expected: map[string]time.Duration{DefaultCallTimeoutKey: 10 * time.Second, "ListAccessPoints": time.Minute},
+		},
+		{
+			name:      "Fail: Unknown call",
+			value:     "DescribeFileSystems=10s",
+			expectErr: true,
+		},
+		{
+			name:      "Fail: Missing duration",
+			value:     "CreateAccessPoint",
+			expectErr: true,
+		},
+		{
+			name:      "Fail: Invalid duration",
+			value:     "CreateAccessPoint=10",
+			expectErr: true,
+		},
+		{
+			name:      "Fail: Zero duration",
+			value:     "default=0s",
+			expectErr: true,
+		},
+		{
+			name:      "Fail: Duplicate call",
+			value:     "CreateAccessPoint=10s,CreateAccessPoint=20s",
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			timeouts, err := ParseCloudCallTimeouts(tc.value)
+			if tc.expectErr {
+				if err == nil {
+					t.Fatalf("Expected an error, got timeouts %v", timeouts)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseCloudCallTimeouts failed: %v", err)
+			}
+			if !reflect.DeepEqual(timeouts, tc.expected) {
+				t.Fatalf("Timeouts mismatched. Expected: %v, Actual: %v", tc.expected, timeouts)
+			}
+		})
+	}
+}
+
+func TestCreateVolumeCallTimeouts(t *testing.T) {
+	var (
+		fsId      = "fs-abcd1234"
+		apId      = "fsap-abcd1234xyz987"
+		callLimit = 50 * time.Millisecond
+	)
+
+	req := &csi.CreateVolumeRequest{
+		Name: "pvc-1",
+		VolumeCapabilities: []*csi.VolumeCapability{
+			{
+				AccessType: &csi.VolumeCapability_Mount{
+					Mount: &csi.VolumeCapability_MountVolume{},
+				},
+				AccessMode: &csi.VolumeCapability_AccessMode{
+					Mode: csi.VolumeCapability_AccessMode_MULTI_NODE_MULTI_WRITER,
+				},
+			},
+		},
+		CapacityRange: &csi.CapacityRange{RequiredBytes: 5 * 1024 * 1024 * 1024},
+		Parameters: map[string]string{
+			ProvisioningMode: AccessPointMode,
+			FsId:             fsId,
+			DirectoryPerms:   "700",
+			Uid:              "1000",
+			Gid:              "1000",
+		},
+	}
+	// slowCall blocks until its context is done, as a call to an unresponsive EFS API would
+	slowCall := func(ctx context.Context, fileSystemId string) (*cloud.FileSystem, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+
+	testCases := []struct {
+		name     string
+		testFunc func(t *testing.T)
+	}{
+		{
+			name: "Fail: Slow call fails on its own timeout",
+			testFunc: func(t *testing.T) {
+				mockCtl := gomock.NewController(t)
+				mockCloud := mocks.NewMockCloud(mockCtl)
+				driver := &Driver{
+					cloud:             mockCloud,
+					gidAllocator:      NewGidAllocator(),
+					cloudCallTimeouts: map[string]time.Duration{"DescribeFileSystem": callLimit},
+				}
+
+				ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+				defer cancel()
+				mockCloud.EXPECT().DescribeFileSystem(gomock.Any(), gomock.Eq(fsId)).DoAndReturn(slowCall)
+				start := time.Now()
+				_, err := driver.CreateVolume(ctx, req)
+				if err == nil || !strings.Contains(err.Error(), "DescribeFileSystem timed out after "+callLimit.String()) {
+					t.Fatalf("Expected DescribeFileSystem to time out, got: %v", err)
+				}
+				if elapsed := time.Since(start); elapsed > 10*time.Second {
+					t.Fatalf("CreateVolume took %v, expected the call to fail after %v", elapsed, callLimit)
+				}
+				mockCtl.Finish()
+			},
+		},
+		{
+			name: "Success: Each call gets its own timeout",
+			testFunc: func(t *testing.T) {
+				mockCtl := gomock.NewController(t)
+				mockCloud := mocks.NewMockCloud(mockCtl)
+				driver := &Driver{
+					cloud:             mockCloud,
+					gidAllocator:      NewGidAllocator(),
+					cloudCallTimeouts: map[string]time.Duration{DefaultCallTimeoutKey: time.Second, "CreateAccessPoint": 2 * time.Second},
+				}
+
+				ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+				defer cancel()
+				checkDeadline := func(ctx context.Context, timeout time.Duration) {
+					deadline, ok := ctx.Deadline()
+					if !ok || time.Until(deadline) > timeout {
+						t.Fatalf("Expected a deadline within %v, got %v", timeout, time.Until(deadline))
+					}
+				}
+				mockCloud.EXPECT().DescribeFileSystem(gomock.Any(), gomock.Eq(fsId)).DoAndReturn(
+					func(ctx context.Context, fileSystemId string) (*cloud.FileSystem, error) {
+						checkDeadline(ctx, time.Second)
+						return &cloud.FileSystem{FileSystemId: fsId}, nil
+					})
+				mockCloud.EXPECT().CreateAccessPoint(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+					func(ctx context.Context, clientToken string, accessPointOpts *cloud.AccessPointOptions, reuseAccessPoint bool) (*cloud.AccessPoint, error) {
+						checkDeadline(ctx, 2*time.Second)
+						return &cloud.AccessPoint{AccessPointId: apId, FileSystemId: fsId}, nil
+					})
+				if _, err := driver.CreateVolume(ctx, req); err != nil {
+					t.Fatalf("CreateVolume failed: %v", err)
+				}
+				mockCtl.Finish()
+			},
+		},
+		{
+			name: "Fail: Deadline of the CSI call is not reported as a call timeout",
+			testFunc: func(t *testing.T) {
+				mockCtl := gomock.NewController(t)
+				mockCloud := mocks.NewMockCloud(mockCtl)
+				driver := &Driver{
+					cloud:             mockCloud,
+					gidAllocator:      NewGidAllocator(),
+					cloudCallTimeouts: map[string]time.Duration{DefaultCallTimeoutKey: time.Minute},
+				}
+
+				ctx, cancel := context.WithTimeout(context.Background(), callLimit)
+				defer cancel()
+				mockCloud.EXPECT().DescribeFileSystem(gomock.Any(), gomock.Eq(fsId)).DoAndReturn(slowCall)
+				_, err := driver.CreateVolume(ctx, req)
+				if err == nil || strings.Contains(err.Error(), "timed out after") {
+					t.Fatalf("Expected the CSI call deadline error, got: %v", err)
+				}
+				mockCtl.Finish()
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, tc.testFunc)
+	}
+}
+
+func TestDeleteVolumeCallTimeouts(t *testing.T) {
+	var (
+		apId      = "fsap-abcd1234xyz987"
+		volumeId  = "fs-abcd1234::fsap-abcd1234xyz987"
+		callLimit = 50 * time.Millisecond
+	)
+
+	mockCtl := gomock.NewController(t)
+	mockCloud := mocks.NewMockCloud(mockCtl)
+	driver := &Driver{
+		cloud:             mockCloud,
+		gidAllocator:      NewGidAllocator(),
+		cloudCallTimeouts: map[string]time.Duration{DefaultCallTimeoutKey: callLimit},
+	}
+
+	mockCloud.EXPECT().DescribeAccessPoint(gomock.Any(), gomock.Eq(apId)).DoAndReturn(
+		func(ctx context.Context, accessPointId string) (*cloud.AccessPoint, error) {
+			<-ctx.Done()
+			return nil, ctx.Err()
+		})
+	_, err := driver.DeleteVolume(context.Background(), &csi.DeleteVolumeRequest{VolumeId: volumeId})
+	if err == nil || !strings.Contains(err.Error(), "DescribeAccessPoint timed out after "+callLimit.String()) {
+		t.Fatalf("Expected DescribeAccessPoint to time out, got: %v", err)
+	}
+	mockCtl.Finish()
+}
@@ -0,0 +1,26 @@
+package driver
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/kubernetes-sigs/aws-efs-csi-driver/pkg/cloud"
+)
+
+// unavailableOnShortCircuit is a unary server interceptor failing the call with Unavailable when it failed because
+// the circuit breaker short-circuited one of its EFS calls, whatever code the error it was wrapped in had, so that
+// the CO retries with backoff once EFS recovers.
+func (d *Driver) unavailableOnShortCircuit(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if d.cloudOptions.CircuitBreaker == nil {
+		return handler(ctx, req)
+	}
+	ctx, shortCircuited := cloud.WithShortCircuitFlag(ctx)
+	resp, err := handler(ctx, req)
+	if err != nil && shortCircuited.Load() && status.Code(err) != codes.Unavailable {
+		return resp, status.Errorf(codes.Unavailable, "%v: %v", cloud.ErrCircuitOpen, status.Convert(err).Message())
+	}
+	return resp, err
+}
@@ -0,0 +1,94 @@
+package driver
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"k8s.io/klog/v2"
+)
+
+const (
+	// DefaultEfsMountHelperPath is where efs-utils installs its mount helper, the one mount runs for the efs type
+	DefaultEfsMountHelperPath = "/sbin/mount.efs"
+
+	// CleanupMountFlavorAuto mounts with efs-utils when its mount helper is installed, and with nfs4 otherwise
+	CleanupMountFlavorAuto = "auto"
+	// CleanupMountFlavorEfs mounts with the efs-utils mount helper
+	CleanupMountFlavorEfs = "efs"
+	// CleanupMountFlavorNfs4 mounts with plain NFS 4, without the TLS and IAM authorization of efs-utils
+	CleanupMountFlavorNfs4 = "nfs4"
+)
+
+var (
+	// nfs4CleanupMountOptions are the options of the nfs4 cleanup mount, those efs-utils mounts EFS with
+	nfs4CleanupMountOptions = []string{"nfsvers=4.1", "rsize=1048576", "wsize=1048576", "hard", "timeo=600", "retrans=2", "noresvport"}
+	// nfsCleanupMountOptionKeys are the keys of the cleanup mount options understood by nfs, the others are efs-utils ones
+	nfsCleanupMountOptionKeys = []string{"hard", "soft", "noresvport", "port", "retrans", "rsize", "wsize", "timeo", "nfsvers", "vers"}
+	// conflictingNfsOptionKeys are the keys of the nfs options which a default option is not added next to
+	conflictingNfsOptionKeys = map[string]string{"nfsvers": "vers", "hard": "soft"}
+)
+
+// ValidateCleanupMountFlavor validates the --cleanup-mount-flavor value
+func ValidateCleanupMountFlavor(flavor string) error {
+	switch flavor {
+	case CleanupMountFlavorAuto, CleanupMountFlavorEfs, CleanupMountFlavorNfs4:
+		return nil
+	}
+	return fmt.Errorf("%q is not one of %v, %v or %v", flavor, CleanupMountFlavorAuto, CleanupMountFlavorEfs, CleanupMountFlavorNfs4)
+}
+
+// selectCleanupMountFlavor returns the flavor of the cleanup mount, resolving the auto flavor to efs when the efs-utils
+// mount helper is an executable file, and to nfs4 otherwise
+func selectCleanupMountFlavor(flavor, helperPath string) string {
+	if flavor != CleanupMountFlavorAuto {
+		return flavor
+	}
+	info, err := os.Stat(helperPath)
+	if err == nil && info.Mode().IsRegular() && info.Mode().Perm()&0111 != 0 {
+		return CleanupMountFlavorEfs
+	}
+	klog.Warningf("efs-utils mount helper %q is not installed, the cleanup mount uses nfs4 without TLS nor IAM authorization", helperPath)
+	return CleanupMountFlavorNfs4
+}
+
+// cleanupMountArgs returns the source, file system type and options of the cleanup mount of a file system.
+// The nfs4 flavor mounts the DNS name of the file system, or its mounttargetip, with the options efs-utils would use,
+// dropping the efs-utils options such as tls and iam. The nfs options of the cleanup mount take precedence.
+func (d *Driver) cleanupMountArgs(fileSystemId string, mountOptions []string) (string, string, []string) {
+	if d.cleanupMountFlavor != CleanupMountFlavorNfs4 {
+		return fileSystemId, "efs", mountOptions
+	}
+	host := ""
+	options := []string{}
+	for _, option := range mountOptions {
+		key, value, _ := strings.Cut(option, "=")
+		switch {
+		case key == MountTargetIp:
+			host = value
+		case hasOption(nfsCleanupMountOptionKeys, key):
+			options = append(options, option)
+		default:
+			klog.V(4).Infof("Dropping efs-utils option %q from the nfs4 cleanup mount of %v", option, fileSystemId)
+		}
+	}
+	for _, option := range nfs4CleanupMountOptions {
+		key, _, _ := strings.Cut(option, "=")
+		if !hasOptionKey(options, key) && !hasOptionKey(options, conflictingNfsOptionKeys[key]) {
+			options = append(options, option)
+		}
+	}
+	if host == "" {
+		host = efsDnsName(fileSystemId, d.cloud.GetMetadata().GetRegion())
+	}
+	return host + ":/", CleanupMountFlavorNfs4, options
+}
+
+// efsDnsName returns the DNS name of a file system, which resolves to the mount target of the availability zone
+func efsDnsName(fileSystemId, region string) string {
+	suffix := "amazonaws.com"
+	if strings.HasPrefix(region, "cn-") {
+		suffix = "amazonaws.com.cn"
+	}
+	return fmt.Sprintf("%v.efs.%v.%v", fileSystemId, region, suffix)
+}
@@ -0,0 +1,209 @@
+package driver
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	"github.com/golang/mock/gomock"
+
+	"github.com/kubernetes-sigs/aws-efs-csi-driver/pkg/cloud"
+	"github.com/kubernetes-sigs/aws-efs-csi-driver/pkg/driver/mocks"
+)
+
+// regionMetadata is the metadata of an instance in a region
+type regionMetadata string
+
+func (m regionMetadata) GetInstanceID() string       { return "i-abcd1234" }
+func (m regionMetadata) GetRegion() string           { return string(m) }
+func (m regionMetadata) GetAvailabilityZone() string { return string(m) + "a" }
+
+func TestSelectCleanupMountFlavor(t *testing.T) {
+	dir := t.TempDir()
+	helper := filepath.Join(dir, "mount.efs")
+	if err := os.WriteFile(helper, []byte("#!/bin/sh\n"), 0755); err != nil {
+		t.Fatalf("Failed to write the helper: %v", err)
+	}
+	notExecutable := filepath.Join(dir, "mount.efs.txt")
+	if err := os.WriteFile(notExecutable, nil, 0644); err != nil {
+		t.Fatalf("Failed to write the helper: %v", err)
+	}
+
+	testCases := []struct {
+		name       string
+		flavor     string
+		helperPath string
+		expected   string
+	}{
+		{
+			name:       "Auto with the helper installed",
+			flavor:     CleanupMountFlavorAuto,
+			helperPath: helper,
+			expected:   CleanupMountFlavorEfs,
+		},
+		{
+			name:       "Auto without the helper",
+			flavor:     CleanupMountFlavorAuto,
+			helperPath: filepath.Join(dir, "missing"),
+			expected:   CleanupMountFlavorNfs4,
+		},
+		{
+			name:       "Auto with a helper which is not executable",
+			flavor:     CleanupMountFlavorAuto,
+			helperPath: notExecutable,
+			expected:   CleanupMountFlavorNfs4,
+		},
+		{
+			name:       "Auto with a directory",
+			flavor:     CleanupMountFlavorAuto,
+			helperPath: dir,
+			expected:   CleanupMountFlavorNfs4,
+		},
+		{
+			name:       "Efs without the helper",
+			flavor:     CleanupMountFlavorEfs,
+			helperPath: filepath.Join(dir, "missing"),
+			expected:   CleanupMountFlavorEfs,
+		},
+		{
+			name:       "Nfs4 with the helper installed",
+			flavor:     CleanupMountFlavorNfs4,
+			helperPath: helper,
+			expected:   CleanupMountFlavorNfs4,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := ValidateCleanupMountFlavor(tc.flavor); err != nil {
+				t.Fatalf("ValidateCleanupMountFlavor failed: %v", err)
+			}
+			if flavor := selectCleanupMountFlavor(tc.flavor, tc.helperPath); flavor != tc.expected {
+				t.Fatalf("Flavor mismatched. Expected: %v, Actual: %v", tc.expected, flavor)
+			}
+		})
+	}
+
+	if err := ValidateCleanupMountFlavor("nfs"); err == nil {
+		t.Fatalf("Expected an error for an unknown flavor")
+	}
+}
+
+func TestCleanupMountArgs(t *testing.T) {
+	fsId := "fs-abcd1234"
+
+	testCases := []struct {
+		name            string
+		flavor          string
+		region          string
+		mountOptions    []string
+		expectedSource  string
+		expectedFsType  string
+		expectedOptions []string
+	}{
+		{
+			name:            "Efs flavor by default",
+			mountOptions:    []string{"tls", "iam"},
+			expectedSource:  fsId,
+			expectedFsType:  "efs",
+			expectedOptions: []string{"tls", "iam"},
+		},
+		{
+			name:            "Efs flavor",
+			flavor:          CleanupMountFlavorEfs,
+			mountOptions:    []string{"tls", "mounttargetip=10.0.0.10"},
+			expectedSource:  fsId,
+			expectedFsType:  "efs",
+			expectedOptions: []string{"tls", "mounttargetip=10.0.0.10"},
+		},
+		{
+			name:            "Nfs4 flavor drops the efs-utils options",
+			flavor:          CleanupMountFlavorNfs4,
+			region:          "us-east-1",
+			mountOptions:    []string{"tls", "iam"},
+			expectedSource:  fsId + ".efs.us-east-1.amazonaws.com:/",
+			expectedFsType:  "nfs4",
+			expectedOptions: []string{"nfsvers=4.1", "rsize=1048576", "wsize=1048576", "hard", "timeo=600", "retrans=2", "noresvport"},
+		},
+		{
+			name:            "Nfs4 flavor in China",
+			flavor:          CleanupMountFlavorNfs4,
+			region:          "cn-north-1",
+			expectedSource:  fsId + ".efs.cn-north-1.amazonaws.com.cn:/",
+			expectedFsType:  "nfs4",
+			expectedOptions: []string{"nfsvers=4.1", "rsize=1048576", "wsize=1048576", "hard", "timeo=600", "retrans=2", "noresvport"},
+		},
+		{
+			name:            "Nfs4 flavor mounts the mount target IP",
+			flavor:          CleanupMountFlavorNfs4,
+			region:          "us-east-1",
+			mountOptions:    []string{"tls", "mounttargetip=10.0.0.10"},
+			expectedSource:  "10.0.0.10:/",
+			expectedFsType:  "nfs4",
+			expectedOptions: []string{"nfsvers=4.1", "rsize=1048576", "wsize=1048576", "hard", "timeo=600", "retrans=2", "noresvport"},
+		},
+		{
+			name:            "Nfs4 flavor keeps the nfs options",
+			flavor:          CleanupMountFlavorNfs4,
+			region:          "us-east-1",
+			mountOptions:    []string{"iam", "soft", "timeo=100", "vers=4.0"},
+			expectedSource:  fsId + ".efs.us-east-1.amazonaws.com:/",
+			expectedFsType:  "nfs4",
+			expectedOptions: []string{"soft", "timeo=100", "vers=4.0", "rsize=1048576", "wsize=1048576", "retrans=2", "noresvport"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockCtl := gomock.NewController(t)
+			mockCloud := mocks.NewMockCloud(mockCtl)
+			mockCloud.EXPECT().GetMetadata().Return(regionMetadata(tc.region)).AnyTimes()
+			driver := &Driver{
+				cloud:              mockCloud,
+				cleanupMountFlavor: tc.flavor,
+			}
+
+			source, fsType, options := driver.cleanupMountArgs(fsId, tc.mountOptions)
+			if source != tc.expectedSource || fsType != tc.expectedFsType {
+				t.Fatalf("Mount mismatched. Expected: %v %v, Actual: %v %v", tc.expectedSource, tc.expectedFsType, source, fsType)
+			}
+			if !reflect.DeepEqual(options, tc.expectedOptions) {
+				t.Fatalf("Options mismatched. Expected: %v, Actual: %v", tc.expectedOptions, options)
+			}
+			mockCtl.Finish()
+		})
+	}
+}
+
+func TestDeleteVolumeCleanupMountFlavor(t *testing.T) {
+	var (
+		fsId = "fs-abcd1234"
+		apId = "fsap-abcd1234xyz987"
+	)
+	mockCtl := gomock.NewController(t)
+	mockCloud := mocks.NewMockCloud(mockCtl)
+	mockMounter := mocks.NewMockMounter(mockCtl)
+	driver := &Driver{
+		cloud:                    mockCloud,
+		mounter:                  mockMounter,
+		gidAllocator:             NewGidAllocator(),
+		deleteAccessPointRootDir: true,
+		cleanupMountFlavor:       CleanupMountFlavorNfs4,
+		tempMountPathPrefix:      t.TempDir(),
+	}
+
+	accessPoint := &cloud.AccessPoint{AccessPointId: apId, FileSystemId: fsId, AccessPointRootDir: "/data"}
+	mockCloud.EXPECT().GetMetadata().Return(regionMetadata("us-east-1")).AnyTimes()
+	mockCloud.EXPECT().DescribeAccessPoint(gomock.Any(), gomock.Eq(apId)).Return(accessPoint, nil)
+	mockCloud.EXPECT().DeleteAccessPoint(gomock.Any(), gomock.Eq(apId)).Return(nil)
+	mockMounter.EXPECT().MakeDir(gomock.Any()).Return(nil)
+	mockMounter.EXPECT().Mount(gomock.Eq(fsId+".efs.us-east-1.amazonaws.com:/"), gomock.Any(), gomock.Eq("nfs4"), gomock.Eq(nfs4CleanupMountOptions)).Return(nil)
+	mockMounter.EXPECT().Unmount(gomock.Any()).Return(nil)
+	if _, err := driver.DeleteVolume(context.Background(), &csi.DeleteVolumeRequest{VolumeId: fsId + "::" + apId}); err != nil {
+		t.Fatalf("DeleteVolume failed: %v", err)
+	}
+	mockCtl.Finish()
+}
@@ -0,0 +1,180 @@
+package driver
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"k8s.io/klog/v2"
+)
+
+// DefaultCleanupMountBatchSize is the default number of DeleteVolume calls sharing a cleanup mount
+const DefaultCleanupMountBatchSize = 50
+
+// cleanupMount is a mount of the root of a file system shared by the DeleteVolume calls of a batch, so that
+// deleting many access points of the file system at once, e.g. when a namespace is deleted, mounts it once
+type cleanupMount struct {
+	key    string
+	target string
+	// refs is the number of calls using the mount, and uses the number of calls of the batch
+	refs int
+	uses int
+	// ready is closed once the mount succeeded or failed with err
+	ready chan struct{}
+	err   error
+	// idle unmounts the mount once no call used it for --cleanup-mount-batch-window
+	idle *time.Timer
+}
+
+// cleanupMounts holds the cleanup mount of the current batch of each file system and mount options
+type cleanupMounts struct {
+	mu     sync.Mutex
+	mounts map[string]*cleanupMount
+	seq    int
+}
+
+// cleanupMountKey returns the key of the cleanup mounts of a file system with the mount options, in any order
+func cleanupMountKey(fileSystemId string, mountOptions []string) string {
+	options := append([]string{}, mountOptions...)
+	sort.Strings(options)
+	return fileSystemId + ":" + strings.Join(options, ",")
+}
+
+// deleteRootDirWithSharedMount deletes the root directory of an access point through the cleanup mount of the
+// current batch of its file system, mounting it when there is none. Failing to mount is not an error, as for the
+// cleanup mount of a single call.
+func (d *Driver) deleteRootDirWithSharedMount(fileSystemId, accessPointId, rootDir string, mountOptions []string) error {
+	// The root of the file system is shared by the batch, it must never be deleted
+	if cleaned := path.Clean("/" + rootDir); cleaned == "/" {
+		klog.Warningf("DeleteVolume: access point %v has the root of file system %v as root directory, not deleting it", accessPointId, fileSystemId)
+		return nil
+	}
+	target, release, err := d.acquireCleanupMount(fileSystemId, mountOptions)
+	if err != nil {
+		klog.Warningf("DeleteVolume: could not mount %q, access point root directory %q of %v is not deleted: %v", fileSystemId, rootDir, accessPointId, err)
+		return nil
+	}
+	defer release()
+	if err := os.RemoveAll(target + rootDir); err != nil {
+		return status.Errorf(codes.Internal, "Could not delete access point root directory %q: %v", rootDir, err)
+	}
+	return nil
+}
+
+// acquireCleanupMount returns the target of the cleanup mount of the current batch of the file system with the
+// mount options, and the function releasing it. The batch is closed once --cleanup-mount-batch-size calls used
+// its mount, and the mount of a batch is unmounted once unused for --cleanup-mount-batch-window.
+func (d *Driver) acquireCleanupMount(fileSystemId string, mountOptions []string) (string, func(), error) {
+	m := &d.cleanupMounts
+	key := cleanupMountKey(fileSystemId, mountOptions)
+
+	m.mu.Lock()
+	mnt := m.mounts[key]
+	if mnt != nil && d.cleanupMountBatchSize > 0 && mnt.uses >= d.cleanupMountBatchSize {
+		klog.V(4).Infof("Cleanup mount %v of %v is used by %v calls, starting a new batch", mnt.target, fileSystemId, mnt.uses)
+		delete(m.mounts, key)
+		if mnt.refs == 0 && mnt.idle != nil {
+			mnt.idle.Stop()
+			go d.unmountCleanupMount(mnt)
+		}
+		mnt = nil
+	}
+	owner := mnt == nil
+	if owner {
+		if m.mounts == nil {
+			m.mounts = map[string]*cleanupMount{}
+		}
+		m.seq++
+		mnt = &cleanupMount{
+			key:    key,
+			target: fmt.Sprintf("%v/%v-batch-%d", d.getTempMountPathPrefix(), fileSystemId, m.seq),
+			ready:  make(chan struct{}),
+		}
+		m.mounts[key] = mnt
+	}
+	mnt.refs++
+	mnt.uses++
+	if mnt.idle != nil {
+		mnt.idle.Stop()
+		mnt.idle = nil
+	}
+	m.mu.Unlock()
+
+	if owner {
+		mnt.err = d.mountCleanupMount(fileSystemId, mnt.target, mountOptions)
+		if mnt.err != nil {
+			// The next calls mount again, instead of failing with the error of this batch
+			m.mu.Lock()
+			if m.mounts[key] == mnt {
+				delete(m.mounts, key)
+			}
+			m.mu.Unlock()
+		}
+		close(mnt.ready)
+	} else {
+		<-mnt.ready
+		klog.V(4).Infof("Reusing cleanup mount %v of %v", mnt.target, fileSystemId)
+	}
+	if mnt.err != nil {
+		d.releaseCleanupMount(mnt)
+		return "", nil, mnt.err
+	}
+	return mnt.target, func() { d.releaseCleanupMount(mnt) }, nil
+}
+
+// mountCleanupMount creates the target of a cleanup mount and mounts the root of the file system on it
+func (d *Driver) mountCleanupMount(fileSystemId, target string, mountOptions []string) error {
+	if err := d.mounter.MakeDir(target); err != nil {
+		return fmt.Errorf("could not create dir %q: %v", target, err)
+	}
+	if err := d.mountForCleanup(fileSystemId, target, mountOptions); err != nil {
+		os.Remove(target)
+		return err
+	}
+	return nil
+}
+
+// releaseCleanupMount releases a cleanup mount used by a call. A mount unused by any call is unmounted right away
+// when its batch is closed, and after --cleanup-mount-batch-window otherwise, unless a call uses it again.
+func (d *Driver) releaseCleanupMount(mnt *cleanupMount) {
+	m := &d.cleanupMounts
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	mnt.refs--
+	if mnt.refs > 0 || mnt.err != nil {
+		return
+	}
+	if m.mounts[mnt.key] != mnt {
+		go d.unmountCleanupMount(mnt)
+		return
+	}
+	mnt.idle = time.AfterFunc(d.cleanupMountBatchWindow, func() {
+		m.mu.Lock()
+		if mnt.refs > 0 || m.mounts[mnt.key] != mnt {
+			m.mu.Unlock()
+			return
+		}
+		delete(m.mounts, mnt.key)
+		m.mu.Unlock()
+		d.unmountCleanupMount(mnt)
+	})
+}
+
+// unmountCleanupMount unmounts a cleanup mount no longer used and removes its target. Failures are only logged, as
+// the root directories were already deleted.
+func (d *Driver) unmountCleanupMount(mnt *cleanupMount) {
+	klog.V(4).Infof("Unmounting cleanup mount %v used by %v calls", mnt.target, mnt.uses)
+	if err := d.mounter.Unmount(mnt.target); err != nil {
+		klog.Warningf("Could not unmount cleanup mount %q: %v", mnt.target, err)
+		return
+	}
+	if err := os.RemoveAll(mnt.target); err != nil {
+		klog.Warningf("Could not delete %q: %v", mnt.target, err)
+	}
+}
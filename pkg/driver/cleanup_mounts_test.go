@@ -0,0 +1,151 @@
+package driver
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	"github.com/golang/mock/gomock"
+
+	"github.com/kubernetes-sigs/aws-efs-csi-driver/pkg/cloud"
+	"github.com/kubernetes-sigs/aws-efs-csi-driver/pkg/driver/mocks"
+)
+
+func TestDeleteVolumeCleanupMountBatch(t *testing.T) {
+	fsId := "fs-abcd1234"
+
+	testCases := []struct {
+		name        string
+		batchWindow time.Duration
+		batchSize   int
+		volumes     int
+		concurrent  bool
+		// expectedMounts is the number of times the file system is mounted and unmounted
+		expectedMounts int
+	}{
+		{
+			name:           "Mount per call without batching",
+			volumes:        4,
+			expectedMounts: 4,
+		},
+		{
+			name:           "Mount shared by successive calls",
+			batchWindow:    time.Second,
+			volumes:        4,
+			expectedMounts: 1,
+		},
+		{
+			name:           "Mount shared by concurrent calls",
+			batchWindow:    time.Second,
+			volumes:        8,
+			concurrent:     true,
+			expectedMounts: 1,
+		},
+		{
+			name:           "Batches bounded by size",
+			batchWindow:    time.Second,
+			batchSize:      2,
+			volumes:        5,
+			expectedMounts: 3,
+		},
+		{
+			name:           "Batches bounded by time",
+			batchWindow:    time.Millisecond,
+			volumes:        3,
+			expectedMounts: 3,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockCtl := gomock.NewController(t)
+			mockCloud := mocks.NewMockCloud(mockCtl)
+			mockMounter := mocks.NewMockMounter(mockCtl)
+			driver := &Driver{
+				cloud:                    mockCloud,
+				mounter:                  mockMounter,
+				gidAllocator:             NewGidAllocator(),
+				deleteAccessPointRootDir: true,
+				tempMountPathPrefix:      t.TempDir(),
+				cleanupMountBatchWindow:  tc.batchWindow,
+				cleanupMountBatchSize:    tc.batchSize,
+			}
+
+			var mu sync.Mutex
+			mounted := map[string]bool{}
+			unmounts := make(chan string, tc.volumes)
+			mockMounter.EXPECT().MakeDir(gomock.Any()).DoAndReturn(func(target string) error {
+				return os.MkdirAll(target, 0755)
+			}).Times(tc.expectedMounts)
+			mockMounter.EXPECT().Mount(gomock.Eq(fsId), gomock.Any(), gomock.Eq("efs"), gomock.Any()).DoAndReturn(
+				func(source, target, fstype string, options []string) error {
+					mu.Lock()
+					defer mu.Unlock()
+					mounted[target] = true
+					return nil
+				}).Times(tc.expectedMounts)
+			mockMounter.EXPECT().Unmount(gomock.Any()).DoAndReturn(func(target string) error {
+				mu.Lock()
+				defer mu.Unlock()
+				if !mounted[target] {
+					t.Errorf("Unmounting %v, which is not mounted", target)
+				}
+				delete(mounted, target)
+				unmounts <- target
+				return nil
+			}).Times(tc.expectedMounts)
+
+			ctx := context.Background()
+			deleteVolume := func(i int) {
+				apId := fmt.Sprintf("fsap-abcd1234xyz%03d", i)
+				mockCloud.EXPECT().DescribeAccessPoint(gomock.Eq(ctx), gomock.Eq(apId)).Return(&cloud.AccessPoint{
+					AccessPointId:      apId,
+					FileSystemId:       fsId,
+					AccessPointRootDir: fmt.Sprintf("/pvc-%d", i),
+				}, nil)
+				mockCloud.EXPECT().DeleteAccessPoint(gomock.Eq(ctx), gomock.Eq(apId)).Return(nil)
+				if _, err := driver.DeleteVolume(ctx, &csi.DeleteVolumeRequest{VolumeId: fsId + "::" + apId}); err != nil {
+					t.Errorf("DeleteVolume of %v failed: %v", apId, err)
+				}
+			}
+			var wg sync.WaitGroup
+			for i := 0; i < tc.volumes; i++ {
+				if tc.concurrent {
+					wg.Add(1)
+					go func(i int) {
+						defer wg.Done()
+						deleteVolume(i)
+					}(i)
+					continue
+				}
+				deleteVolume(i)
+				if tc.batchWindow == time.Millisecond {
+					// Let the mount of the call be unmounted before the next call
+					<-unmounts
+				}
+			}
+			wg.Wait()
+
+			// Every mount is unmounted once the batch window elapsed
+			deadline := time.After(10 * time.Second)
+			for {
+				mu.Lock()
+				remaining := len(mounted)
+				mu.Unlock()
+				if remaining == 0 {
+					break
+				}
+				select {
+				case <-deadline:
+					t.Fatalf("%v cleanup mounts are still mounted", remaining)
+				case <-time.After(10 * time.Millisecond):
+				}
+			}
+			mockCtl.Finish()
+		})
+	}
+}
@@ -0,0 +1,211 @@
+package driver
+
+import (
+	"context"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"k8s.io/klog/v2"
+
+	"github.com/kubernetes-sigs/aws-efs-csi-driver/pkg/cloud"
+)
+
+// cloneSource is the volume a volume is cloned from
+type cloneSource struct {
+	volumeId      string
+	fileSystemId  string
+	subPath       string
+	accessPointId string
+}
+
+// parseCloneSource validates the content source of a volume. Only access point volumes can be cloned, from another
+// access point volume. Reused access points may already hold data, which a clone would overwrite.
+func parseCloneSource(source *csi.VolumeContentSource, provisioningMode string, reuseAccessPoint bool) (*cloneSource, error) {
+	if source.GetSnapshot() != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "Volumes cannot be created from snapshots, EFS has no snapshots")
+	}
+	volume := source.GetVolume()
+	if volume == nil {
+		return nil, status.Errorf(codes.InvalidArgument, "Unsupported volume content source %v", source)
+	}
+	if provisioningMode != AccessPointMode {
+		return nil, status.Errorf(codes.InvalidArgument, "Volumes can only be cloned with the %v provisioning mode", AccessPointMode)
+	}
+	if reuseAccessPoint {
+		return nil, status.Errorf(codes.InvalidArgument, "Volumes cannot be cloned with %v, as the reused access point may already hold data", ReuseAccessPointKey)
+	}
+	// A volume ID of another driver cannot be the ID of an existing volume
+	if !isDriverVolumeId(volume.GetVolumeId()) {
+		return nil, status.Errorf(codes.NotFound, "Source volume %v not found", volume.GetVolumeId())
+	}
+	fileSystemId, subPath, accessPointId, err := parseVolumeId(volume.GetVolumeId())
+	if err != nil {
+		return nil, err
+	}
+	if accessPointId == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "Source volume %v is not backed by an access point, only access point volumes can be cloned", volume.GetVolumeId())
+	}
+	return &cloneSource{volumeId: volume.GetVolumeId(), fileSystemId: fileSystemId, subPath: subPath, accessPointId: accessPointId}, nil
+}
+
+// cloneSourceDir returns the directory of the source volume in its file system, which must be the file system of the
+// clone, as the copy goes through a single mount of the file system. The clone must be at least as large as the
+// size requested for the source volume, and its root directory must not overlap with the source directory.
+func cloneSourceDir(ctx context.Context, localCloud cloud.Cloud, source *cloneSource, accessPointOpts *cloud.AccessPointOptions, volSize int64) (string, error) {
+	fileSystemId := accessPointOpts.FileSystemId
+	if source.fileSystemId != fileSystemId {
+		return "", status.Errorf(codes.InvalidArgument, "Source volume %v is on File System %v, volumes can only be cloned within File System %v", source.volumeId, source.fileSystemId, fileSystemId)
+	}
+	accessPoint, err := localCloud.DescribeAccessPoint(ctx, source.accessPointId)
+	if err != nil {
+		if cloud.IsNotFound(err) {
+			return "", status.Errorf(codes.NotFound, "Source volume %v not found: %v", source.volumeId, err)
+		}
+		if cloud.IsAccessDenied(err) {
+			return "", status.Errorf(codes.Unauthenticated, "Access Denied. Please ensure you have the right AWS permissions: %v", err)
+		}
+		return "", status.Errorf(codes.Internal, "Failed to describe Access Point %v of source volume %v: %v", source.accessPointId, source.volumeId, err)
+	}
+	if accessPoint.FileSystemId != "" && accessPoint.FileSystemId != source.fileSystemId {
+		return "", status.Errorf(codes.NotFound, "Source volume %v not found: Access Point %v is in File System %v", source.volumeId, source.accessPointId, accessPoint.FileSystemId)
+	}
+	if value, ok := accessPoint.Tags[RequestedBytesTagKey]; ok && volSize > 0 {
+		if sourceSize, err := strconv.ParseInt(value, 10, 64); err == nil && sourceSize > volSize {
+			return "", status.Errorf(codes.OutOfRange, "Requested capacity %v is smaller than the capacity %v of source volume %v", volSize, sourceSize, source.volumeId)
+		}
+	}
+	sourceDir := path.Join("/", accessPoint.AccessPointRootDir, source.subPath)
+	if isSubPath(accessPointOpts.DirectoryPath, sourceDir) || isSubPath(sourceDir, accessPointOpts.DirectoryPath) {
+		return "", status.Errorf(codes.InvalidArgument, "Root directory %q of the clone overlaps with directory %q of source volume %v", accessPointOpts.DirectoryPath, sourceDir, source.volumeId)
+	}
+	return sourceDir, nil
+}
+
+// isSubPath returns whether the directory is dir or one of its descendants
+func isSubPath(name, dir string) bool {
+	return name == dir || strings.HasPrefix(name, strings.TrimSuffix(dir, "/")+"/")
+}
+
+// cloneVolume copies the content of the source directory into the root directory of the clone, both relative to the
+// mount point of the root of their file system. A source directory which does not exist, as EFS creates it on the
+// first mount of the source volume, is empty. The copy is bounded by the context of the request.
+func cloneVolume(ctx context.Context, target, sourceDir, rootDir string) error {
+	err := copyTree(ctx, target+sourceDir, target+rootDir)
+	if err == nil {
+		klog.V(2).Infof("Copied %q into %q", sourceDir, rootDir)
+		return nil
+	}
+	if ctx.Err() != nil {
+		return status.FromContextError(ctx.Err()).Err()
+	}
+	return status.Errorf(codes.Internal, "Could not copy %q into %q: %v", sourceDir, rootDir, err)
+}
+
+// copyTree copies the content of the directory src into the existing directory dst, with the permissions and owners
+// of the copied files, symbolic links being copied as is. Existing files are overwritten, so that a retried copy
+// completes. Special files are skipped.
+func copyTree(ctx context.Context, src, dst string) error {
+	if _, err := os.Stat(src); os.IsNotExist(err) {
+		return nil
+	}
+	return filepath.WalkDir(src, func(name string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, name)
+		if err != nil {
+			return err
+		}
+		// The root directory of the clone keeps the permissions and owner of its access point
+		if rel == "." {
+			return nil
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+		switch {
+		case info.IsDir():
+			if err := os.Mkdir(target, info.Mode().Perm()); err != nil && !os.IsExist(err) {
+				return err
+			}
+		case info.Mode().IsRegular():
+			if err := copyFileContent(ctx, name, target, info); err != nil {
+				return err
+			}
+		case info.Mode()&fs.ModeSymlink != 0:
+			link, err := os.Readlink(name)
+			if err != nil {
+				return err
+			}
+			if err := os.Remove(target); err != nil && !os.IsNotExist(err) {
+				return err
+			}
+			if err := os.Symlink(link, target); err != nil {
+				return err
+			}
+			if stat, ok := info.Sys().(*syscall.Stat_t); ok {
+				return os.Lchown(target, int(stat.Uid), int(stat.Gid))
+			}
+			return nil
+		default:
+			klog.Warningf("Not copying special file %q", name)
+			return nil
+		}
+		// The owner is changed first, as it clears the setuid and setgid bits
+		if stat, ok := info.Sys().(*syscall.Stat_t); ok {
+			if err := os.Chown(target, int(stat.Uid), int(stat.Gid)); err != nil {
+				return err
+			}
+		}
+		return os.Chmod(target, info.Mode()&(fs.ModePerm|fs.ModeSetuid|fs.ModeSetgid|fs.ModeSticky))
+	})
+}
+
+// copyFileContent copies the content and modification time of a regular file, streaming it so that the copy stops once the
+// context is done
+func copyFileContent(ctx context.Context, src, dst string, info fs.FileInfo) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, info.Mode().Perm())
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, &contextReader{ctx: ctx, r: in}); err != nil {
+		out.Close()
+		return err
+	}
+	if err := out.Close(); err != nil {
+		return err
+	}
+	return os.Chtimes(dst, info.ModTime(), info.ModTime())
+}
+
+// contextReader stops reading once the context is done
+type contextReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+func (r *contextReader) Read(p []byte) (int, error) {
+	if err := r.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return r.r.Read(p)
+}
@@ -0,0 +1,287 @@
+package driver
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	"github.com/golang/mock/gomock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/kubernetes-sigs/aws-efs-csi-driver/pkg/cloud"
+	"github.com/kubernetes-sigs/aws-efs-csi-driver/pkg/driver/mocks"
+)
+
+func TestCreateVolumeClone(t *testing.T) {
+	var (
+		fsId           = "fs-abcd1234"
+		apId           = "fsap-abcd1234xyz987"
+		sourceApId     = "fsap-abcd5678xyz987"
+		sourceVolumeId = fsId + "::" + sourceApId
+		volName        = "volumeName"
+		stdVolCap      = &csi.VolumeCapability{
+			AccessType: &csi.VolumeCapability_Mount{
+				Mount: &csi.VolumeCapability_MountVolume{},
+			},
+			AccessMode: &csi.VolumeCapability_AccessMode{
+				Mode: csi.VolumeCapability_AccessMode_MULTI_NODE_MULTI_WRITER,
+			},
+		}
+	)
+
+	createRequest := func(sourceVolumeId string, params map[string]string) *csi.CreateVolumeRequest {
+		parameters := map[string]string{
+			ProvisioningMode: "efs-ap",
+			FsId:             fsId,
+			DirectoryPerms:   "700",
+			Uid:              "1000",
+			Gid:              "1000",
+		}
+		for k, v := range params {
+			parameters[k] = v
+		}
+		return &csi.CreateVolumeRequest{
+			Name:               volName,
+			VolumeCapabilities: []*csi.VolumeCapability{stdVolCap},
+			CapacityRange:      &csi.CapacityRange{RequiredBytes: 5 * 1024 * 1024 * 1024},
+			Parameters:         parameters,
+			VolumeContentSource: &csi.VolumeContentSource{
+				Type: &csi.VolumeContentSource_Volume{
+					Volume: &csi.VolumeContentSource_VolumeSource{VolumeId: sourceVolumeId},
+				},
+			},
+		}
+	}
+
+	sourceAccessPoint := &cloud.AccessPoint{
+		AccessPointId:      sourceApId,
+		FileSystemId:       fsId,
+		AccessPointRootDir: "/source",
+		Tags:               map[string]string{RequestedBytesTagKey: "1073741824"},
+	}
+
+	// newDriver returns a driver whose mounts of the file system are simulated by symbolic links to fsDir
+	newDriver := func(t *testing.T, mockCtl *gomock.Controller, fsDir string) (*Driver, *mocks.MockCloud) {
+		mockCloud := mocks.NewMockCloud(mockCtl)
+		mockMounter := mocks.NewMockMounter(mockCtl)
+		tempMountDir := t.TempDir()
+		target := tempMountDir + "/" + volName
+		mockMounter.EXPECT().MakeDir(gomock.Eq(target)).Return(nil).AnyTimes()
+		mockMounter.EXPECT().Mount(gomock.Eq(fsId), gomock.Eq(target), gomock.Eq("efs"), gomock.Any()).DoAndReturn(
+			func(source, target, fstype string, options []string) error {
+				return os.Symlink(fsDir, target)
+			}).AnyTimes()
+		mockMounter.EXPECT().Unmount(gomock.Eq(target)).DoAndReturn(func(target string) error {
+			return os.Remove(target)
+		}).AnyTimes()
+		return &Driver{
+			cloud:               mockCloud,
+			mounter:             mockMounter,
+			gidAllocator:        NewGidAllocator(),
+			tempMountPathPrefix: tempMountDir,
+		}, mockCloud
+	}
+
+	// writeSource fills the directory of the source volume
+	writeSource := func(t *testing.T, fsDir string) {
+		if err := os.MkdirAll(fsDir+"/source/data", 0750); err != nil {
+			t.Fatalf("Could not create the source volume: %v", err)
+		}
+		if err := os.WriteFile(fsDir+"/source/data/file", []byte("content"), 0640); err != nil {
+			t.Fatalf("Could not write the source volume: %v", err)
+		}
+		if err := os.Symlink("data/file", fsDir+"/source/link"); err != nil {
+			t.Fatalf("Could not write the source volume: %v", err)
+		}
+	}
+
+	testCases := []struct {
+		name     string
+		testFunc func(t *testing.T)
+	}{
+		{
+			name: "Success: Content of the source volume is copied",
+			testFunc: func(t *testing.T) {
+				mockCtl := gomock.NewController(t)
+				fsDir := t.TempDir()
+				writeSource(t, fsDir)
+				driver, mockCloud := newDriver(t, mockCtl, fsDir)
+
+				mockCloud.EXPECT().DescribeFileSystem(gomock.Any(), gomock.Eq(fsId)).Return(&cloud.FileSystem{FileSystemId: fsId}, nil)
+				mockCloud.EXPECT().DescribeAccessPoint(gomock.Any(), gomock.Eq(sourceApId)).Return(sourceAccessPoint, nil)
+				mockCloud.EXPECT().CreateAccessPoint(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(&cloud.AccessPoint{AccessPointId: apId, FileSystemId: fsId}, nil)
+
+				req := createRequest(sourceVolumeId, nil)
+				res, err := driver.CreateVolume(context.Background(), req)
+				if err != nil {
+					t.Fatalf("CreateVolume failed: %v", err)
+				}
+				if res.Volume.GetContentSource().GetVolume().GetVolumeId() != sourceVolumeId {
+					t.Fatalf("Content source mismatched. Expected: %v, Actual: %v", req.VolumeContentSource, res.Volume.ContentSource)
+				}
+
+				rootDir := fsDir + "/" + volName
+				info, err := os.Stat(rootDir)
+				if err != nil {
+					t.Fatalf("Root directory was not created: %v", err)
+				}
+				if expected := os.FileMode(0700) | os.ModeDir; info.Mode() != expected {
+					t.Fatalf("Root directory mode mismatched. Expected: %v, Actual: %v", expected, info.Mode())
+				}
+				content, err := os.ReadFile(rootDir + "/data/file")
+				if err != nil || string(content) != "content" {
+					t.Fatalf("File was not copied: %q, %v", content, err)
+				}
+				for name, expected := range map[string]os.FileMode{
+					"data":      0750 | os.ModeDir,
+					"data/file": 0640,
+				} {
+					info, err := os.Stat(filepath.Join(rootDir, name))
+					if err != nil {
+						t.Fatalf("Could not stat %v: %v", name, err)
+					}
+					if info.Mode() != expected {
+						t.Fatalf("Mode of %v mismatched. Expected: %v, Actual: %v", name, expected, info.Mode())
+					}
+				}
+				if link, err := os.Readlink(rootDir + "/link"); err != nil || link != "data/file" {
+					t.Fatalf("Symbolic link was not copied: %q, %v", link, err)
+				}
+				mockCtl.Finish()
+			},
+		},
+		{
+			name: "Fail: Failed copy deletes the access point and the root directory",
+			testFunc: func(t *testing.T) {
+				mockCtl := gomock.NewController(t)
+				fsDir := t.TempDir()
+				writeSource(t, fsDir)
+				driver, mockCloud := newDriver(t, mockCtl, fsDir)
+
+				// The request is cancelled once the access point is created
+				ctx, cancel := context.WithCancel(context.Background())
+				defer cancel()
+				mockCloud.EXPECT().DescribeFileSystem(gomock.Any(), gomock.Eq(fsId)).Return(&cloud.FileSystem{FileSystemId: fsId}, nil)
+				mockCloud.EXPECT().DescribeAccessPoint(gomock.Any(), gomock.Eq(sourceApId)).Return(sourceAccessPoint, nil)
+				mockCloud.EXPECT().CreateAccessPoint(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+					func(ctx context.Context, clientToken string, accessPointOpts *cloud.AccessPointOptions, reuseAccessPoint bool) (*cloud.AccessPoint, error) {
+						cancel()
+						return &cloud.AccessPoint{AccessPointId: apId, FileSystemId: fsId}, nil
+					})
+				mockCloud.EXPECT().DeleteAccessPoint(gomock.Any(), gomock.Eq(apId)).Return(nil)
+
+				_, err := driver.CreateVolume(ctx, createRequest(sourceVolumeId, nil))
+				if status.Code(err) != codes.Canceled {
+					t.Fatalf("Expected a Canceled error, got: %v", err)
+				}
+				if _, err := os.Stat(fsDir + "/" + volName); !os.IsNotExist(err) {
+					t.Fatalf("Root directory was not removed: %v", err)
+				}
+				mockCtl.Finish()
+			},
+		},
+		{
+			name: "Fail: Source volume not found",
+			testFunc: func(t *testing.T) {
+				mockCtl := gomock.NewController(t)
+				driver, mockCloud := newDriver(t, mockCtl, t.TempDir())
+
+				mockCloud.EXPECT().DescribeFileSystem(gomock.Any(), gomock.Eq(fsId)).Return(&cloud.FileSystem{FileSystemId: fsId}, nil)
+				mockCloud.EXPECT().DescribeAccessPoint(gomock.Any(), gomock.Eq(sourceApId)).Return(nil, cloud.ErrNotFound)
+
+				_, err := driver.CreateVolume(context.Background(), createRequest(sourceVolumeId, nil))
+				if status.Code(err) != codes.NotFound {
+					t.Fatalf("Expected a NotFound error, got: %v", err)
+				}
+				mockCtl.Finish()
+			},
+		},
+		{
+			name: "Fail: Capacity smaller than the source volume",
+			testFunc: func(t *testing.T) {
+				mockCtl := gomock.NewController(t)
+				driver, mockCloud := newDriver(t, mockCtl, t.TempDir())
+
+				largeSource := *sourceAccessPoint
+				largeSource.Tags = map[string]string{RequestedBytesTagKey: "10737418240"}
+				mockCloud.EXPECT().DescribeFileSystem(gomock.Any(), gomock.Eq(fsId)).Return(&cloud.FileSystem{FileSystemId: fsId}, nil)
+				mockCloud.EXPECT().DescribeAccessPoint(gomock.Any(), gomock.Eq(sourceApId)).Return(&largeSource, nil)
+
+				_, err := driver.CreateVolume(context.Background(), createRequest(sourceVolumeId, nil))
+				if status.Code(err) != codes.OutOfRange {
+					t.Fatalf("Expected an OutOfRange error, got: %v", err)
+				}
+				mockCtl.Finish()
+			},
+		},
+		{
+			name: "Fail: Root directory of the clone within the source volume",
+			testFunc: func(t *testing.T) {
+				mockCtl := gomock.NewController(t)
+				driver, mockCloud := newDriver(t, mockCtl, t.TempDir())
+
+				rootSource := *sourceAccessPoint
+				rootSource.AccessPointRootDir = "/"
+				mockCloud.EXPECT().DescribeFileSystem(gomock.Any(), gomock.Eq(fsId)).Return(&cloud.FileSystem{FileSystemId: fsId}, nil)
+				mockCloud.EXPECT().DescribeAccessPoint(gomock.Any(), gomock.Eq(sourceApId)).Return(&rootSource, nil)
+
+				_, err := driver.CreateVolume(context.Background(), createRequest(sourceVolumeId, nil))
+				if status.Code(err) != codes.InvalidArgument {
+					t.Fatalf("Expected an InvalidArgument error, got: %v", err)
+				}
+				mockCtl.Finish()
+			},
+		},
+		{
+			name: "Fail: Source volume on another file system",
+			testFunc: func(t *testing.T) {
+				mockCtl := gomock.NewController(t)
+				driver, mockCloud := newDriver(t, mockCtl, t.TempDir())
+
+				mockCloud.EXPECT().DescribeFileSystem(gomock.Any(), gomock.Eq(fsId)).Return(&cloud.FileSystem{FileSystemId: fsId}, nil)
+
+				_, err := driver.CreateVolume(context.Background(), createRequest("fs-ef015678::"+sourceApId, nil))
+				if status.Code(err) != codes.InvalidArgument {
+					t.Fatalf("Expected an InvalidArgument error, got: %v", err)
+				}
+				mockCtl.Finish()
+			},
+		},
+		{
+			name: "Fail: Invalid content sources",
+			testFunc: func(t *testing.T) {
+				for _, req := range []*csi.CreateVolumeRequest{
+					createRequest(fsId, nil),
+					createRequest(sourceVolumeId, map[string]string{ProvisioningMode: FileSystemMode}),
+					createRequest(sourceVolumeId, map[string]string{ReuseAccessPointKey: "true"}),
+					{
+						Name:               volName,
+						VolumeCapabilities: []*csi.VolumeCapability{stdVolCap},
+						Parameters:         createRequest(sourceVolumeId, nil).Parameters,
+						VolumeContentSource: &csi.VolumeContentSource{
+							Type: &csi.VolumeContentSource_Snapshot{
+								Snapshot: &csi.VolumeContentSource_SnapshotSource{SnapshotId: "snapshot"},
+							},
+						},
+					},
+				} {
+					mockCtl := gomock.NewController(t)
+					driver, _ := newDriver(t, mockCtl, t.TempDir())
+
+					_, err := driver.CreateVolume(context.Background(), req)
+					if status.Code(err) != codes.InvalidArgument {
+						t.Fatalf("Expected an InvalidArgument error with %v, got: %v", req.VolumeContentSource, err)
+					}
+					mockCtl.Finish()
+				}
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, tc.testFunc)
+	}
+}
@@ -20,51 +20,133 @@ import (
 	"context"
 	"crypto/sha256"
 	"fmt"
-	"github.com/google/uuid"
+	"math"
 	"os"
 	"path"
+	"regexp"
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 
+	"github.com/aws/aws-sdk-go/service/efs"
 	"github.com/container-storage-interface/spec/lib/go/csi"
 	"github.com/kubernetes-sigs/aws-efs-csi-driver/pkg/cloud"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/klog/v2"
 )
 
 const (
+	AccessPointArn        = "accessPointArn"
 	AccessPointMode       = "efs-ap"
+	AccessPointShareKey   = "accessPointShareKey"
+	AccessPointSubPath    = "accessPointSubPath"
+	AvailabilityZoneName  = "availabilityZoneName"
 	AzName                = "az"
 	BasePath              = "basePath"
+	BasePathPerms         = "basePathPerms"
+	ClusterNameTagKey     = "efs.csi.aws.com/cluster-name"
+	CreateDirMode         = "createDirMode"
+	CreateDirAccessPoint  = "accesspoint"
+	CreateDirMount        = "mount"
+	DeletedAtTagKey       = "efs.csi.aws.com/deleted-at"
 	DefaultGidMin         = int64(50000)
 	DefaultGidMax         = DefaultGidMin + cloud.AccessPointPerFsLimit
+	DefaultMaxGid         = int64(math.MaxInt32)
 	DefaultTagKey         = "efs.csi.aws.com/cluster"
 	DefaultTagValue       = "true"
+	DefaultTagValueParam  = "defaultTagValue"
 	DirectoryPerms        = "directoryPerms"
+	EnforceQuota          = "enforceQuota"
 	EnsureUniqueDirectory = "ensureUniqueDirectory"
+	FileSystemMode        = "efs-fs"
 	FsId                  = "fileSystemId"
+	FsVolumeTagKey        = "efs.csi.aws.com/file-system-volume"
 	Gid                   = "gid"
+	GidAllocationMode     = "gidAllocationMode"
+	GidHashed             = "hashed"
 	GidMin                = "gidRangeStart"
 	GidMax                = "gidRangeEnd"
+	GidSequential         = "sequential"
 	MountTargetIp         = "mounttargetip"
+	MountTargetIpAuto     = "auto"
+	MountTargetIpParam    = "mountTargetIp"
+	MountTargetIps        = "mountTargetIps"
+	MountOptions          = "mountOptions"
+	MountOptionsTagKey    = "efs.csi.aws.com/mount-options"
+	NameTag               = "nameTag"
+	NameTagKey            = "Name"
+	OwnerGid              = "ownerGid"
+	OwnerUid              = "ownerUid"
+	ParentDirOwnerGid     = "parentDirOwnerGid"
+	ParentDirOwnerUid     = "parentDirOwnerUid"
+	ProvisionedThroughput = "provisionedThroughputInMibps"
 	ProvisioningMode      = "provisioningMode"
 	PvName                = "csi.storage.k8s.io/pv/name"
 	PvcName               = "csi.storage.k8s.io/pvc/name"
 	PvcNamespace          = "csi.storage.k8s.io/pvc/namespace"
+	PvcTagKey             = "efs.csi.aws.com/pvc"
+	QuotaBytes            = "quotaBytes"
+	ReadOnly              = "readOnly"
+	Region                = "region"
+	ReplicationDestFsId   = "replicationDestinationFsId"
+	ReplicationDestTagKey = "efs.csi.aws.com/replication-destination"
+	RequestedBytesTagKey  = "efs.csi.aws.com/requested-bytes"
+	RequireReplication    = "requireReplication"
 	RoleArn               = "awsRoleArn"
+	SecondaryGids         = "secondaryGids"
+	ShareKeyTagKey        = "efs.csi.aws.com/share-key"
+	ShareRefTagKeyPrefix  = "efs.csi.aws.com/share-ref/"
+	StorageClassName      = "storageClassName"
+	StorageClassTagKey    = "efs.csi.aws.com/storageclass"
 	SubPathPattern        = "subPathPattern"
 	TempMountPathPrefix   = "/var/lib/csi/pv"
+	ThroughputMode        = "throughputMode"
 	Uid                   = "uid"
+	UseIam                = "useIam"
 	ReuseAccessPointKey   = "reuseAccessPoint"
+	RootDirSuffix         = "rootDirSuffix"
+	RootDirSuffixFull     = "full"
+	RootDirSuffixShort    = "short"
+	RootDirSuffixPvcName  = "pvcname"
 	PvcNameKey            = "csi.storage.k8s.io/pvc/name"
 )
 
+// maxTagKeyLength and maxTagValueLength are the maximum lengths of AWS tag keys and values, in characters
+const (
+	maxTagKeyLength   = 128
+	maxTagValueLength = 256
+)
+
+// maxRootDirNameAttempts is the number of suffixes tried before giving up on finding an unused root directory
+const maxRootDirNameAttempts = 5
+
+// accessPointRollbackTimeout bounds the deletion of the access point of a failed CreateVolume call
+const accessPointRollbackTimeout = 30 * time.Second
+
 var (
+	// invalidTagChars matches the characters AWS does not allow in tag keys and values
+	invalidTagChars = regexp.MustCompile(`[^\p{L}\p{Z}\p{N}_.:/=+\-@]`)
+	// invalidRootDirChars matches the characters replaced when using the PVC name in a root directory name
+	invalidRootDirChars = regexp.MustCompile(`[^a-zA-Z0-9._-]`)
+	// cleanupMountBackoff is the delay between attempts of the DeleteVolume cleanup mount, doubled after each attempt
+	cleanupMountBackoff = wait.Backoff{Duration: time.Second, Factor: 2, Jitter: 0.1}
+	// defaultCleanupMountOptions are used for the DeleteVolume cleanup mount unless overridden by --cleanup-mount-options
+	defaultCleanupMountOptions = []string{"tls", "iam"}
+	// knownCleanupMountOptionKeys are the mount option keys understood by efs-utils and nfs that make sense for the cleanup mount
+	knownCleanupMountOptionKeys = []string{
+		"tls", "notls", "iam", "accesspoint", "awsprofile", "awscredsuri", "az", "cafile", "crossaccount", "fsap",
+		"hard", "soft", "mounttargetip", "netns", "noresvport", "ocsp", "noocsp", "port", "retrans", "rsize",
+		"wsize", "timeo", "tlsport", "verify", "nfsvers", "vers",
+	}
 	// controllerCaps represents the capability of controller service
 	controllerCaps = []csi.ControllerServiceCapability_RPC_Type{
 		csi.ControllerServiceCapability_RPC_CREATE_DELETE_VOLUME,
+		csi.ControllerServiceCapability_RPC_GET_VOLUME,
+		csi.ControllerServiceCapability_RPC_VOLUME_CONDITION,
+		csi.ControllerServiceCapability_RPC_CLONE_VOLUME,
 	}
 	// subPathPatternComponents shows the elements that we allow to be in the construction of the root directory
 	// of the access point, as well as the values we need to extract them from the Volume Parameters.
@@ -78,6 +160,21 @@ var (
 func (d *Driver) CreateVolume(ctx context.Context, req *csi.CreateVolumeRequest) (*csi.CreateVolumeResponse, error) {
 	klog.V(4).Infof("CreateVolume: called with args %+v", *req)
 
+	return d.inFlightCreates.do(ctx, req, func() (*csi.CreateVolumeResponse, error) {
+		res, err := d.createVolume(ctx, req)
+		if err != nil {
+			d.recordProvisioningFailure(req, err)
+			return nil, err
+		}
+		if d.omitEmptyVolumeContext {
+			omitEmptyValues(res.Volume.VolumeContext)
+		}
+		return res, nil
+	})
+}
+
+func (d *Driver) createVolume(ctx context.Context, req *csi.CreateVolumeRequest) (*csi.CreateVolumeResponse, error) {
+
 	var reuseAccessPoint bool
 	var err error
 	volumeParams := req.GetParameters()
@@ -96,13 +193,120 @@ func (d *Driver) CreateVolume(ctx context.Context, req *csi.CreateVolumeRequest)
 			klog.V(5).Infof("Client token : %s", clientToken)
 		}
 	}
+	clientToken = d.accessPointClientToken(clientToken)
+
+	// Volumes with the same share key are backed by a single access point, deleted with the last of them
+	shareKey := ""
+	if value, ok := volumeParams[AccessPointShareKey]; ok {
+		if !reuseAccessPoint {
+			return nil, status.Errorf(codes.InvalidArgument, "Parameter %v requires %v to be true", AccessPointShareKey, ReuseAccessPointKey)
+		}
+		shareKey = strings.TrimSpace(value)
+		if shareKey == "" {
+			return nil, status.Errorf(codes.InvalidArgument, "Parameter %v cannot be empty", AccessPointShareKey)
+		}
+	}
+
+	// The requested size is recorded on the access point and passed to the node, EFS itself does not enforce it
+	enforceQuota := false
+	if value, ok := volumeParams[EnforceQuota]; ok {
+		enforceQuota, err = strconv.ParseBool(value)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "Invalid value for %v parameter: %v", EnforceQuota, err)
+		}
+		if enforceQuota && shareKey != "" {
+			return nil, status.Errorf(codes.InvalidArgument, "Parameter %v cannot be used with %v, as volumes sharing an access point share its quota", EnforceQuota, AccessPointShareKey)
+		}
+	}
+
+	// The subdirectory of the access point mounted by the node is encoded in the volume ID
+	subPath := ""
+	if value, ok := volumeParams[AccessPointSubPath]; ok {
+		subPath, err = parseAccessPointSubPath(value)
+		if err != nil {
+			return nil, err
+		}
+	}
+	// The root directory is created by EFS through the access point, or by the controller through a mount of the file system
+	createDirMode := CreateDirAccessPoint
+	if value, ok := volumeParams[CreateDirMode]; ok {
+		if value != CreateDirAccessPoint && value != CreateDirMount {
+			return nil, status.Errorf(codes.InvalidArgument, "Invalid %v %q. Supported values are %v and %v", CreateDirMode, value, CreateDirAccessPoint, CreateDirMount)
+		}
+		createDirMode = value
+		if createDirMode == CreateDirMount {
+			if _, err := parseDirectoryPerms(d.directoryPerms(volumeParams)); err != nil {
+				return nil, status.Errorf(codes.InvalidArgument, "Parameter %v=%v requires a valid %v: %v", CreateDirMode, CreateDirMount, DirectoryPerms, err)
+			}
+		}
+	}
+	// The missing directories of basePath are created by the controller when basePathPerms is set
+	var basePathPerms os.FileMode
+	value, ensureBasePath := volumeParams[BasePathPerms]
+	if ensureBasePath {
+		if _, ok := volumeParams[BasePath]; !ok {
+			return nil, status.Errorf(codes.InvalidArgument, "Parameter %v requires %v", BasePathPerms, BasePath)
+		}
+		basePathPerms, err = parseDirectoryPerms(value)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "Invalid %v: %v", BasePathPerms, err)
+		}
+	}
+	// The directories of basePath created by the controller are owned by the controller unless parentDirOwnerUid
+	// and parentDirOwnerGid are set, which are unrelated to the POSIX user of the access point
+	parentDirUid, err := parseParentDirOwnerId(volumeParams, ParentDirOwnerUid, ensureBasePath)
+	if err != nil {
+		return nil, err
+	}
+	parentDirGid, err := parseParentDirOwnerId(volumeParams, ParentDirOwnerGid, ensureBasePath)
+	if err != nil {
+		return nil, err
+	}
+	// The NFS options of the node mounts are recorded in the volume context, and on the access point for the cleanup mount
+	var mountOptions []string
+	if value, ok := volumeParams[MountOptions]; ok {
+		mountOptions, err = parseMountOptions(value)
+		if err != nil {
+			return nil, err
+		}
+	}
+	// Read-only volumes only allow reader access modes, and are mounted read-only by the node
+	readOnly := false
+	if value, ok := volumeParams[ReadOnly]; ok {
+		readOnly, err = strconv.ParseBool(value)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "Invalid value for %v parameter: %v", ReadOnly, err)
+		}
+	}
+	// The replication of the file system is recorded for disaster recovery, and checked when required
+	replicationDestFsId := ""
+	if value, ok := volumeParams[ReplicationDestFsId]; ok {
+		replicationDestFsId = strings.TrimSpace(value)
+		if !isValidFileSystemId(replicationDestFsId) {
+			return nil, status.Errorf(codes.InvalidArgument, "Invalid %v %q", ReplicationDestFsId, value)
+		}
+	}
+	requireReplication := false
+	if value, ok := volumeParams[RequireReplication]; ok {
+		requireReplication, err = strconv.ParseBool(value)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "Invalid value for %v parameter: %v", RequireReplication, err)
+		}
+	}
 	if volName == "" {
 		return nil, status.Error(codes.InvalidArgument, "Volume name not provided")
 	}
+	// Access points must be attributable to the namespace owning them
+	if d.denyUntagged && strings.TrimSpace(volumeParams[PvcNamespace]) == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "Provisioning without the %v metadata tag is denied. Please run the csi-provisioner with --extra-create-metadata", PvcNamespace)
+	}
 
 	// Volume size is required to match PV to PVC by k8s.
 	// Volume size is not consumed by EFS for any purposes.
 	volSize := req.GetCapacityRange().GetRequiredBytes()
+	if enforceQuota && volSize <= 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "Parameter %v requires a requested capacity", EnforceQuota)
+	}
 
 	volCaps := req.GetVolumeCapabilities()
 	if len(volCaps) == 0 {
@@ -115,6 +319,11 @@ func (d *Driver) CreateVolume(ctx context.Context, req *csi.CreateVolumeRequest)
 	if err := d.validateFStype(volCaps); err != nil {
 		return nil, status.Error(codes.InvalidArgument, fmt.Sprintf("Volume fstype not supported: %s", err))
 	}
+	if readOnly {
+		if err := validateReaderAccessModes(volCaps); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "Parameter %v only supports the ReadOnlyMany access mode: %v", ReadOnly, err)
+		}
+	}
 
 	var (
 		azName           string
@@ -124,6 +333,8 @@ func (d *Driver) CreateVolume(ctx context.Context, req *csi.CreateVolumeRequest)
 		gidMax           int64
 		localCloud       cloud.Cloud
 		provisioningMode string
+		ownerGid         int64
+		ownerUid         int64
 		roleArn          string
 		uid              int64
 	)
@@ -131,27 +342,99 @@ func (d *Driver) CreateVolume(ctx context.Context, req *csi.CreateVolumeRequest)
 	//Parse parameters
 	if value, ok := volumeParams[ProvisioningMode]; ok {
 		provisioningMode = value
-		//TODO: Add FS provisioning mode check when implemented
-		if provisioningMode != AccessPointMode {
-			errStr := "Provisioning mode " + provisioningMode + " is not supported. Only Access point provisioning: 'efs-ap' is supported"
+		if provisioningMode != AccessPointMode && provisioningMode != FileSystemMode {
+			errStr := "Provisioning mode " + provisioningMode + " is not supported. Only Access point provisioning: 'efs-ap' and File system provisioning: 'efs-fs' are supported"
 			return nil, status.Error(codes.InvalidArgument, errStr)
 		}
 	} else {
 		return nil, status.Errorf(codes.InvalidArgument, "Missing %v parameter", ProvisioningMode)
 	}
 
+	// Volumes are cloned by copying the directory of the source volume into the root directory of the clone
+	var source *cloneSource
+	if contentSource := req.GetVolumeContentSource(); contentSource != nil {
+		source, err = parseCloneSource(contentSource, provisioningMode, reuseAccessPoint)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	// Create tags
-	tags := map[string]string{
-		DefaultTagKey: DefaultTagValue,
+	tags := d.driverTags()
+
+	// The value of the default tag may carry e.g. the cluster name or environment. Its key is unchanged, as the
+	// driver recognizes its resources by the key only.
+	if value, ok := volumeParams[DefaultTagValueParam]; ok {
+		if d.disableDefaultTags {
+			return nil, status.Errorf(codes.InvalidArgument, "Parameter %v cannot be used with --disable-default-tags, as the %v tag is not added", DefaultTagValueParam, DefaultTagKey)
+		}
+		if strings.TrimSpace(value) == "" {
+			return nil, status.Errorf(codes.InvalidArgument, "Parameter %v cannot be empty", DefaultTagValueParam)
+		}
+		tags[DefaultTagKey] = value
 	}
 
-	// Append input tags to default tag
-	if len(d.tags) != 0 {
-		for k, v := range d.tags {
-			tags[k] = v
+	// The display name in the AWS console, the client token remains the idempotency key
+	if value, ok := volumeParams[NameTag]; ok {
+		name, err := interpolateNameTag(value, volumeParams)
+		if err != nil {
+			return nil, err
+		}
+		if provisioningMode == AccessPointMode {
+			name = d.accessPointNamePrefix + name
+		}
+		tags[NameTagKey] = name
+	}
+
+	// The provisioner does not pass the StorageClass of the PVC, the StorageClass names itself
+	if value, ok := volumeParams[StorageClassName]; ok {
+		storageClass := sanitizeTagValue(value)
+		if storageClass == "" {
+			return nil, status.Errorf(codes.InvalidArgument, "Parameter %v cannot be empty", StorageClassName)
+		}
+		tags[StorageClassTagKey] = storageClass
+	}
+
+	// The PVC tells a retry whose basePath or subPathPattern changed from another volume of the same name, see
+	// accessPointMatchesRequest
+	if provisioningMode == AccessPointMode && volumeParams[PvcNamespace] != "" && volumeParams[PvcName] != "" {
+		tags[PvcTagKey] = sanitizeTagValue(volumeParams[PvcNamespace] + "/" + volumeParams[PvcName])
+	}
+
+	// Invalid tags would only be reported by an opaque failure of the AWS call
+	tags, err = normalizeTags(tags)
+	if err != nil {
+		return nil, err
+	}
+
+	// One Zone file systems are created by the efs-fs mode, access points use the zone of their file system
+	if _, ok := volumeParams[AvailabilityZoneName]; ok && provisioningMode != FileSystemMode {
+		return nil, status.Errorf(codes.InvalidArgument, "Parameter %v is only supported by the %v mode", AvailabilityZoneName, FileSystemMode)
+	}
+
+	if provisioningMode == FileSystemMode {
+		if replicationDestFsId != "" || requireReplication {
+			return nil, status.Errorf(codes.InvalidArgument, "Parameters %v and %v are not supported by the %v mode, as new file systems are not replicated", ReplicationDestFsId, RequireReplication, FileSystemMode)
 		}
+		res, err := d.createFileSystemVolume(ctx, req, volName, volSize, tags)
+		if err != nil {
+			return nil, err
+		}
+		setMountOptions(res.Volume.VolumeContext, mountOptions)
+		setReadOnly(res.Volume.VolumeContext, readOnly)
+		return res, nil
 	}
 
+	if len(mountOptions) > 0 {
+		tags[MountOptionsTagKey] = strings.Join(mountOptions, " ")
+	}
+	if replicationDestFsId != "" {
+		tags[ReplicationDestTagKey] = replicationDestFsId
+	}
+	// Shared access points back volumes of different sizes, only the size of dedicated ones is recorded
+	if volSize > 0 && shareKey == "" {
+		tags[RequestedBytesTagKey] = strconv.FormatInt(volSize, 10)
+	}
 	accessPointsOptions := &cloud.AccessPointOptions{
 		CapacityGiB: volSize,
 		Tags:        tags,
@@ -161,10 +444,17 @@ func (d *Driver) CreateVolume(ctx context.Context, req *csi.CreateVolumeRequest)
 		if strings.TrimSpace(value) == "" {
 			return nil, status.Errorf(codes.InvalidArgument, "Parameter %v cannot be empty", FsId)
 		}
-		accessPointsOptions.FileSystemId = value
+		fsId, valid := normalizeFileSystemId(value)
+		if !valid {
+			return nil, status.Errorf(codes.InvalidArgument, "Invalid %v %q: expected a file system ID of the form 'fs-' followed by hexadecimal digits, or its ARN", FsId, value)
+		}
+		accessPointsOptions.FileSystemId = fsId
 	} else {
 		return nil, status.Errorf(codes.InvalidArgument, "Missing %v parameter", FsId)
 	}
+	if !d.isAllowedFileSystem(accessPointsOptions.FileSystemId) {
+		return nil, status.Errorf(codes.PermissionDenied, "File System %v is not allowed by --allowed-filesystem-ids", accessPointsOptions.FileSystemId)
+	}
 
 	uid = -1
 	if value, ok := volumeParams[Uid]; ok {
@@ -188,6 +478,55 @@ func (d *Driver) CreateVolume(ctx context.Context, req *csi.CreateVolumeRequest)
 		}
 	}
 
+	// The namespace policy of --namespace-posix-annotations takes the place of the parameters it sets
+	nsUid, nsGid, err := d.namespacePosixUser(volumeParams)
+	if err != nil {
+		return nil, err
+	}
+	if uid, err = mergeNamespacePosixId(Uid, uid, NamespacePosixUidAnnotation, nsUid); err != nil {
+		return nil, err
+	}
+	if gid, err = mergeNamespacePosixId(Gid, gid, NamespacePosixGidAnnotation, nsGid); err != nil {
+		return nil, err
+	}
+
+	ownerUid = -1
+	if value, ok := volumeParams[OwnerUid]; ok {
+		ownerUid, err = strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "Failed to parse invalid %v: %v", OwnerUid, err)
+		}
+		if ownerUid < 0 {
+			return nil, status.Errorf(codes.InvalidArgument, "%v must be greater or equal than 0", OwnerUid)
+		}
+	}
+
+	ownerGid = -1
+	if value, ok := volumeParams[OwnerGid]; ok {
+		ownerGid, err = strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "Failed to parse invalid %v: %v", OwnerGid, err)
+		}
+		if ownerGid < 0 {
+			return nil, status.Errorf(codes.InvalidArgument, "%v must be greater or equal than 0", OwnerGid)
+		}
+	}
+
+	// Hashed GIDs are derived from the PVC, so that re-creating it gets the same GID while it is unused
+	gidHashKey := ""
+	if value, ok := volumeParams[GidAllocationMode]; ok {
+		switch strings.ToLower(strings.TrimSpace(value)) {
+		case GidSequential:
+		case GidHashed:
+			if volumeParams[PvcName] == "" {
+				return nil, status.Errorf(codes.InvalidArgument, "Parameter %v=%v requires the %v metadata. Please run the csi-provisioner with --extra-create-metadata", GidAllocationMode, GidHashed, PvcName)
+			}
+			gidHashKey = volumeParams[PvcNamespace] + "/" + volumeParams[PvcName]
+		default:
+			return nil, status.Errorf(codes.InvalidArgument, "Invalid value %q for %v parameter, must be %v or %v", value, GidAllocationMode, GidSequential, GidHashed)
+		}
+	}
+
 	if value, ok := volumeParams[GidMin]; ok {
 		gidMin, err = strconv.ParseInt(value, 10, 64)
 		if err != nil {
@@ -217,26 +556,68 @@ func (d *Driver) CreateVolume(ctx context.Context, req *csi.CreateVolumeRequest)
 		}
 	}
 
-	// Assign default GID ranges if not provided
-	if gidMin == 0 && gidMax == 0 {
-		gidMin = DefaultGidMin
-		gidMax = DefaultGidMax
+	// GIDs above the ceiling are not valid on every NFS client
+	maxGid := d.maxGid
+	if maxGid == 0 {
+		maxGid = DefaultMaxGid
+	}
+	if gid > maxGid {
+		return nil, status.Errorf(codes.InvalidArgument, "%v %v exceeds the maximum GID %v", Gid, gid, maxGid)
+	}
+	if ownerGid > maxGid {
+		return nil, status.Errorf(codes.InvalidArgument, "%v %v exceeds the maximum GID %v", OwnerGid, ownerGid, maxGid)
+	}
+	if gidMax > maxGid {
+		return nil, status.Errorf(codes.InvalidArgument, "%v %v exceeds the maximum GID %v", GidMax, gidMax, maxGid)
+	}
+	if value, ok := volumeParams[SecondaryGids]; ok {
+		accessPointsOptions.SecondaryGids, err = parseSecondaryGids(value, maxGid)
+		if err != nil {
+			return nil, err
+		}
 	}
 
-	if value, ok := volumeParams[DirectoryPerms]; ok {
-		accessPointsOptions.DirectoryPerms = value
+	// Assign default GID ranges if not provided, clamped to the maximum GID
+	if gidMin == 0 && gidMax == 0 {
+		defaultGidMin, defaultGidMax := d.defaultGidRange()
+		if maxGid <= defaultGidMin {
+			return nil, status.Errorf(codes.InvalidArgument, "The default GID range starts at %v above the maximum GID %v, please set %v and %v", defaultGidMin, maxGid, GidMin, GidMax)
+		}
+		gidMin = defaultGidMin
+		gidMax = defaultGidMax
+		if gidMax > maxGid {
+			klog.V(4).Infof("Clamping the default GID range %v-%v to the maximum GID %v", gidMin, gidMax, maxGid)
+			gidMax = maxGid
+		}
 	}
 
-	// Storage class parameter `az` will be used to fetch preferred mount target for cross account mount.
-	// If the `az` storage class parameter is not provided, a random mount target will be picked for mounting.
-	// This storage class parameter different from `az` mount option provided by efs-utils https://github.com/aws/efs-utils/blob/v1.31.1/src/mount_efs/__init__.py#L195
-	// The `az` mount option provided by efs-utils is used for cross az mount or to provide az of efs one zone file system mount within the same aws-account.
-	// To make use of the `az` mount option, add it under storage class's `mountOptions` section. https://kubernetes.io/docs/concepts/storage/storage-classes/#mount-options
+	accessPointsOptions.DirectoryPerms = d.directoryPerms(volumeParams)
+
+	// Storage class parameter `az` pins the mount target used by the node, and is used to fetch the preferred mount target for cross account mount.
+	// If the `az` storage class parameter is not provided, a random mount target will be picked for cross account mount.
+	// The `az` is passed to the node in the volume context, which mounts with the `az` mount option of efs-utils https://github.com/aws/efs-utils/blob/v1.31.1/src/mount_efs/__init__.py#L195
 	if value, ok := volumeParams[AzName]; ok {
 		azName = value
 	}
 
-	localCloud, roleArn, err = getCloud(req.GetSecrets(), d)
+	// Nodes mount by IP address where the DNS name of the file system does not resolve reliably
+	mountTargetIp := ""
+	if value, ok := volumeParams[MountTargetIpParam]; ok {
+		mountTargetIp, err = parseMountTargetIpParam(value)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	useIam := d.useIam
+	if value, ok := volumeParams[UseIam]; ok {
+		useIam, err = strconv.ParseBool(value)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "Failed to parse invalid %v: %v", UseIam, err)
+		}
+	}
+
+	localCloud, roleArn, err = getCloud(req.GetSecrets(), volumeParams[Region], d)
 	if err != nil {
 		return nil, err
 	}
@@ -244,27 +625,116 @@ func (d *Driver) CreateVolume(ctx context.Context, req *csi.CreateVolumeRequest)
 	// Check if file system exists. Describe FS or List APs handle appropriate error codes
 	// With dynamic uid/gid provisioning we can save a call to describe FS, as list APs fails if FS ID does not exist
 	var accessPoints []*cloud.AccessPoint
+	var fileSystem *cloud.FileSystem
+	// With --skip-fs-existence-check, a file system which does not exist fails CreateAccessPoint instead
 	if uid == -1 || gid == -1 {
 		accessPoints, err = localCloud.ListAccessPoints(ctx, accessPointsOptions.FileSystemId)
-	} else {
-		_, err = localCloud.DescribeFileSystem(ctx, accessPointsOptions.FileSystemId)
+	} else if !d.skipFsExistenceCheck {
+		fileSystem, err = d.describeFileSystem(ctx, localCloud, roleArn, volumeParams[Region], accessPointsOptions.FileSystemId)
 	}
 	if err != nil {
-		if err == cloud.ErrAccessDenied {
+		if cloud.IsAccessDenied(err) {
 			return nil, status.Errorf(codes.Unauthenticated, "Access Denied. Please ensure you have the right AWS permissions: %v", err)
 		}
-		if err == cloud.ErrNotFound {
+		if cloud.IsNotFound(err) {
 			return nil, status.Errorf(codes.InvalidArgument, "File System does not exist: %v", err)
 		}
 		return nil, status.Errorf(codes.Internal, "Failed to fetch Access Points or Describe File System: %v", err)
 	}
+	if uid == -1 || gid == -1 {
+		d.recordAccessPointUtilization(accessPointsOptions.FileSystemId, len(accessPoints))
+	}
+	// The overflow file system is in the region and account of the driver
+	if d.overflowFileSystemId != "" && roleArn == "" && volumeParams[Region] == "" {
+		fileSystemId := accessPointsOptions.FileSystemId
+		accessPointsOptions.FileSystemId, accessPoints, err = d.selectFileSystem(ctx, localCloud, req, fileSystemId, accessPoints, uid == -1 || gid == -1)
+		if err != nil {
+			return nil, err
+		}
+		if accessPointsOptions.FileSystemId != fileSystemId {
+			// The file system described is not the one of the access point anymore
+			fileSystem = nil
+		}
+	}
+	if requireReplication {
+		if err := checkReplication(ctx, localCloud, accessPointsOptions.FileSystemId, replicationDestFsId); err != nil {
+			return nil, err
+		}
+	}
+
+	if d.inheritFsTags {
+		if fileSystem == nil {
+			fileSystem, err = d.describeFileSystem(ctx, localCloud, roleArn, volumeParams[Region], accessPointsOptions.FileSystemId)
+			if err != nil {
+				if cloud.IsAccessDenied(err) {
+					return nil, status.Errorf(codes.Unauthenticated, "Access Denied. Please ensure you have the right AWS permissions: %v", err)
+				}
+				if cloud.IsNotFound(err) {
+					return nil, status.Errorf(codes.InvalidArgument, "File System does not exist: %v", err)
+				}
+				return nil, status.Errorf(codes.Internal, "Failed to describe File System %v to inherit its tags: %v", accessPointsOptions.FileSystemId, err)
+			}
+		}
+		inheritFileSystemTags(accessPointsOptions.Tags, fileSystem.Tags, d.inheritFsTagKeys)
+	}
+
+	// Ensure the file system has a mount target in the requested availability zone
+	var mountTarget *cloud.MountTarget
+	if azName != "" {
+		mountTarget, err = localCloud.DescribeMountTargets(ctx, accessPointsOptions.FileSystemId, azName)
+		if err != nil {
+			if cloud.IsAccessDenied(err) {
+				return nil, status.Errorf(codes.Unauthenticated, "Access Denied. Please ensure you have the right AWS permissions: %v", err)
+			}
+			if cloud.IsNotFound(err) {
+				return nil, status.Errorf(codes.InvalidArgument, "File System does not exist: %v", err)
+			}
+			return nil, status.Errorf(codes.Internal, "Failed to describe mount targets of File System %v: %v", accessPointsOptions.FileSystemId, err)
+		}
+		if mountTarget.AZName != azName {
+			return nil, status.Errorf(codes.InvalidArgument, "File System %v has no available mount target in %v %v", accessPointsOptions.FileSystemId, AzName, azName)
+		}
+	}
+	mountTargetIps := ""
+	if mountTargetIp == MountTargetIpAuto {
+		zone := azName
+		if zone == "" {
+			zone = preferredZone(req)
+		}
+		mountTargetIp, mountTargetIps, err = resolveMountTargetIps(ctx, localCloud, accessPointsOptions.FileSystemId, zone)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Remember if the GID was chosen by the user, so it can be compared with an already existing access point
+	fixedGid := gid != -1
 
 	var allocatedGid int64
+	// Tracks whether an access point was created with the allocated GID, otherwise its reservation is released
+	allocatedGidUsed := false
 	if uid == -1 || gid == -1 {
-		allocatedGid, err = d.gidAllocator.getNextGid(accessPointsOptions.FileSystemId, accessPoints, gidMin, gidMax)
+		// The GIDs of file systems of another region or account are refreshed with their cloud
+		if d.gidRefreshInterval > 0 {
+			d.gidAllocator.setCloud(accessPointsOptions.FileSystemId, localCloud)
+		}
+		if gidHashKey != "" {
+			allocatedGid, err = d.gidAllocator.getHashedGid(accessPointsOptions.FileSystemId, accessPoints, gidMin, gidMax, gidHashKey)
+		} else {
+			allocatedGid, err = d.gidAllocator.getNextGid(accessPointsOptions.FileSystemId, accessPoints, gidMin, gidMax)
+		}
 		if err != nil {
+			if exhaustedErr, ok := err.(*GidRangeExhaustedError); ok {
+				return nil, status.Errorf(codes.ResourceExhausted, "Failed to locate a free GID: %v. "+
+					"Please widen the %v/%v range or create a new storage class with a new file system", exhaustedErr, GidMin, GidMax)
+			}
 			return nil, err
 		}
+		defer func() {
+			if !allocatedGidUsed {
+				d.gidAllocator.releaseGid(accessPointsOptions.FileSystemId, allocatedGid)
+			}
+		}()
 	}
 	if uid == -1 {
 		uid = allocatedGid
@@ -272,12 +742,34 @@ func (d *Driver) CreateVolume(ctx context.Context, req *csi.CreateVolumeRequest)
 	if gid == -1 {
 		gid = allocatedGid
 	}
+	// The root directory is owned by the POSIX user unless requested otherwise
+	if ownerUid == -1 {
+		ownerUid = uid
+	}
+	if ownerGid == -1 {
+		ownerGid = gid
+	}
 
 	if value, ok := volumeParams[BasePath]; ok {
-		basePath = value
+		basePath, err = normalizeBasePath(value)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	rootDirSuffixMode := RootDirSuffixFull
+	if value, ok := volumeParams[RootDirSuffix]; ok {
+		switch value {
+		case RootDirSuffixFull, RootDirSuffixShort, RootDirSuffixPvcName:
+			rootDirSuffixMode = value
+		default:
+			return nil, status.Errorf(codes.InvalidArgument, "Invalid %v %q. Supported values are %v, %v and %v",
+				RootDirSuffix, value, RootDirSuffixFull, RootDirSuffixShort, RootDirSuffixPvcName)
+		}
 	}
 
 	rootDirName := volName
+	uniqueRootDir := false
 	// Check if a custom structure should be imposed on the access point directory
 	if value, ok := volumeParams[SubPathPattern]; ok {
 		// Try and construct the root directory and check it only contains supported components
@@ -289,12 +781,28 @@ func (d *Driver) CreateVolume(ctx context.Context, req *csi.CreateVolumeRequest)
 				if ensureUniqueDirectory, err := strconv.ParseBool(value); !ensureUniqueDirectory && err == nil {
 					klog.Infof("Not appending PVC UID to path.")
 				} else {
-					klog.Infof("Appending PVC UID to path.")
-					rootDirName = fmt.Sprintf("%s-%s", val, uuid.New().String())
+					uniqueRootDir = true
 				}
 			} else {
+				uniqueRootDir = true
+			}
+			if uniqueRootDir {
 				klog.Infof("Appending PVC UID to path.")
-				rootDirName = fmt.Sprintf("%s-%s", val, uuid.New().String())
+				// Short suffixes keep 32 random bits only, so they are checked against the root directories of the
+				// access points even when the access points were not listed to allocate a GID
+				if accessPoints == nil && rootDirSuffixMode != RootDirSuffixFull {
+					accessPoints, err = localCloud.ListAccessPoints(ctx, accessPointsOptions.FileSystemId)
+					if err != nil {
+						if cloud.IsAccessDenied(err) {
+							return nil, status.Errorf(codes.Unauthenticated, "Access Denied. Please ensure you have the right AWS permissions: %v", err)
+						}
+						return nil, status.Errorf(codes.Internal, "Failed to list Access Points of File System %v: %v", accessPointsOptions.FileSystemId, err)
+					}
+				}
+				rootDirName, err = d.getUniqueRootDirName(val, basePath, rootDirSuffixMode, volumeParams, accessPoints)
+				if err != nil {
+					return nil, err
+				}
 			}
 		} else {
 			return nil, err
@@ -307,135 +815,573 @@ func (d *Driver) CreateVolume(ctx context.Context, req *csi.CreateVolumeRequest)
 	if ok, err := validateEfsPathRequirements(rootDir); !ok {
 		return nil, err
 	}
+	if err := d.validateRootDirLimits(path.Join(rootDir, subPath)); err != nil {
+		return nil, err
+	}
+	if err := d.checkTenantBasePath(strings.TrimSpace(volumeParams[PvcNamespace]), rootDir); err != nil {
+		return nil, err
+	}
 	klog.Infof("Using %v as the access point directory.", rootDir)
 
 	accessPointsOptions.Uid = uid
 	accessPointsOptions.Gid = gid
+	accessPointsOptions.OwnerUid = ownerUid
+	accessPointsOptions.OwnerGid = ownerGid
 	accessPointsOptions.DirectoryPath = rootDir
+	if enforceQuota {
+		accessPointsOptions.QuotaBytes = volSize
+	}
 
-	accessPointId, err := localCloud.CreateAccessPoint(ctx, clientToken, accessPointsOptions, reuseAccessPoint)
-	if err != nil {
-		if err == cloud.ErrAccessDenied {
-			return nil, status.Errorf(codes.Unauthenticated, "Access Denied. Please ensure you have the right AWS permissions: %v", err)
-		}
-		if err == cloud.ErrAlreadyExists {
-			return nil, status.Errorf(codes.AlreadyExists, "Access Point already exists")
+	// Volumes sharing an access point share its root directory on purpose
+	if d.detectSubPathCollisions && volumeParams[SubPathPattern] != "" && !uniqueRootDir && shareKey == "" {
+		if err := d.checkSubPathCollision(ctx, localCloud, clientToken, accessPointsOptions, accessPoints); err != nil {
+			return nil, err
 		}
-		return nil, status.Errorf(codes.Internal, "Failed to create Access point in File System %v : %v", accessPointsOptions.FileSystemId, err)
 	}
 
-	volContext := map[string]string{}
+	// A directory without a unique suffix may have been used by a previous volume
+	if d.verifyEmptyOnReuse && volumeParams[SubPathPattern] != "" && !uniqueRootDir && shareKey == "" {
+		if err := d.verifyRootDirEmpty(ctx, localCloud, roleArn, useIam, volName, clientToken, accessPointsOptions); err != nil {
+			return nil, err
+		}
+	}
 
-	// Fetch mount target Ip for cross-account mount
-	if roleArn != "" {
-		mountTarget, err := localCloud.DescribeMountTargets(ctx, accessPointsOptions.FileSystemId, azName)
+	sourceDir := ""
+	if source != nil {
+		sourceDir, err = cloneSourceDir(ctx, localCloud, source, accessPointsOptions, volSize)
 		if err != nil {
-			klog.Warningf("Failed to describe mount targets for file system %v. Skip using `mounttargetip` mount option: %v", accessPointsOptions.FileSystemId, err)
-		} else {
-			volContext[MountTargetIp] = mountTarget.IPAddress
+			return nil, err
 		}
 	}
 
-	return &csi.CreateVolumeResponse{
-		Volume: &csi.Volume{
-			CapacityBytes: volSize,
-			VolumeId:      accessPointsOptions.FileSystemId + "::" + accessPointId.AccessPointId,
-			VolumeContext: volContext,
-		},
-	}, nil
-}
-
-func (d *Driver) DeleteVolume(ctx context.Context, req *csi.DeleteVolumeRequest) (*csi.DeleteVolumeResponse, error) {
-	var (
-		localCloud cloud.Cloud
-		roleArn    string
-		err        error
-	)
+	if ensureBasePath && strings.Trim(basePath, "/") != "" {
+		if err := d.ensureBasePath(ctx, localCloud, roleArn, useIam, volName, accessPointsOptions.FileSystemId, basePath, basePathPerms, parentDirUid, parentDirGid); err != nil {
+			return nil, err
+		}
+	}
 
-	localCloud, roleArn, err = getCloud(req.GetSecrets(), d)
-	if err != nil {
-		return nil, err
+	// Whether the root directory created through a mount is kept, it is removed when no access point uses it
+	keepRootDir := false
+	// Clones are copied through the mount of the file system creating their root directory
+	fsRoot := ""
+	if createDirMode == CreateDirMount || source != nil {
+		target, release, err := d.createRootDir(ctx, localCloud, roleArn, useIam, volName, accessPointsOptions)
+		if err != nil {
+			return nil, err
+		}
+		fsRoot = target
+		defer func() {
+			release(!keepRootDir)
+		}()
 	}
 
-	klog.V(4).Infof("DeleteVolume: called with args %+v", *req)
-	volId := req.GetVolumeId()
-	if volId == "" {
-		return nil, status.Error(codes.InvalidArgument, "Volume ID not provided")
+	if shareKey != "" {
+		accessPoint, err := d.createSharedAccessPoint(ctx, localCloud, shareKey, volName, accessPointsOptions)
+		if err != nil {
+			return nil, err
+		}
+		allocatedGidUsed = usesGid(accessPoint, accessPointsOptions.Gid)
+		keepRootDir = true
+		res := d.accessPointVolumeResponse(ctx, localCloud, accessPointsOptions.FileSystemId, accessPoint, subPath, volSize, azName, volumeParams[Region], roleArn, useIam, mountTarget)
+		// Each volume gets its own volume ID, so that DeleteVolume releases the reference of the volume only
+		res.Volume.VolumeId += ":" + shareRef(volName)
+		setMountTargetIp(res.Volume.VolumeContext, mountTargetIp, mountTargetIps)
+		setMountOptions(res.Volume.VolumeContext, mountOptions)
+		setReplicationDestination(res.Volume.VolumeContext, replicationDestFsId)
+		setReadOnly(res.Volume.VolumeContext, readOnly)
+		return res, nil
 	}
 
-	fileSystemId, _, accessPointId, err := parseVolumeId(volId)
+	// Whether the access point existed with another root directory, in which case the one created through a mount is unused
+	rootDirDrift := false
+	accessPointId, err := localCloud.CreateAccessPoint(ctx, clientToken, accessPointsOptions, reuseAccessPoint)
+	// An access point reused through its client token keeps its own GID
+	allocatedGidUsed = err == nil && usesGid(accessPointId, accessPointsOptions.Gid)
+	// The access point is deleted if a later step fails, unless it may have existed before this call
+	rollback := false
+	if err == nil && !reuseAccessPoint {
+		_, softDeleted := accessPointId.Tags[DeletedAtTagKey]
+		rollback = !softDeleted
+	}
+	defer func() {
+		if rollback {
+			d.rollbackAccessPoint(localCloud, accessPointId.AccessPointId)
+			allocatedGidUsed = false
+		}
+	}()
+	if cloud.IsAlreadyExists(err) {
+		// A previous call with the same name already created an access point. CSI requires returning it
+		// if it is compatible with this request, so that retries of the provisioner succeed.
+		existingAccessPoint, findErr := localCloud.FindAccessPointByClientToken(ctx, clientToken, accessPointsOptions)
+		if findErr != nil || existingAccessPoint == nil {
+			return nil, status.Errorf(codes.AlreadyExists, "Access Point already exists")
+		}
+		if !accessPointMatchesRequest(existingAccessPoint, accessPointsOptions, fixedGid, uniqueRootDir) {
+			return nil, status.Errorf(codes.AlreadyExists, "Access Point %v already exists with incompatible parameters", existingAccessPoint.AccessPointId)
+		}
+		// A retry of the same PVC returns the access point even if mutable parameters such as basePath were edited in
+		// the meantime, instead of provisioning the volume a second directory
+		if rootDirDrifted(existingAccessPoint, accessPointsOptions, uniqueRootDir) {
+			klog.Warningf("Parameters of volume %v changed since Access Point %v was created: its root directory is %v, the parameters now give %v. Keeping the existing Access Point",
+				volName, existingAccessPoint.AccessPointId, existingAccessPoint.AccessPointRootDir, accessPointsOptions.DirectoryPath)
+			rootDirDrift = true
+		}
+		klog.V(2).Infof("Access Point %v already exists for volume %v, returning it", existingAccessPoint.AccessPointId, volName)
+		accessPointId, err = existingAccessPoint, nil
+	}
 	if err != nil {
-		//Returning success for an invalid volume ID. See here - https://github.com/kubernetes-csi/csi-test/blame/5deb83d58fea909b2895731d43e32400380aae3c/pkg/sanity/controller.go#L733
-		klog.V(5).Infof("DeleteVolume: Failed to parse volumeID: %v, err: %v, returning success", volId, err)
-		return &csi.DeleteVolumeResponse{}, nil
+		if cloud.IsAccessDenied(err) {
+			return nil, status.Errorf(codes.Unauthenticated, "Access Denied. Please ensure you have the right AWS permissions: %v", err)
+		}
+		if cloud.IsNotFound(err) {
+			d.invalidateFileSystem(roleArn, volumeParams[Region], accessPointsOptions.FileSystemId)
+			return nil, status.Errorf(codes.InvalidArgument, "File System does not exist: %v", err)
+		}
+		return nil, status.Errorf(codes.Internal, "Failed to create Access point in File System %v : %v", accessPointsOptions.FileSystemId, err)
 	}
 
-	//TODO: Add Delete File System when FS provisioning is implemented
-	if accessPointId != "" {
+	// A volume created again with the same name during the grace period recovers the soft deleted access point
+	if d.softDeleteGrace > 0 {
+		d.softDeletes.track(accessPointsOptions.FileSystemId, localCloud, roleArn)
+		if err := d.undeleteAccessPoint(ctx, localCloud, accessPointId.AccessPointId); err != nil {
+			return nil, err
+		}
+	}
 
-		// Delete access point root directory if delete-access-point-root-dir is set.
-		if d.deleteAccessPointRootDir {
-			// Check if Access point exists.
-			// If access point exists, retrieve its root directory and delete it/
-			accessPoint, err := localCloud.DescribeAccessPoint(ctx, accessPointId)
-			if err != nil {
-				if err == cloud.ErrAccessDenied {
-					return nil, status.Errorf(codes.Unauthenticated, "Access Denied. Please ensure you have the right AWS permissions: %v", err)
-				}
-				if err == cloud.ErrNotFound {
-					klog.V(5).Infof("DeleteVolume: Access Point %v not found, returning success", accessPointId)
-					return &csi.DeleteVolumeResponse{}, nil
-				}
-				return nil, status.Errorf(codes.Internal, "Could not get describe Access Point: %v , error: %v", accessPointId, err)
-			}
+	if source != nil {
+		if rootDirDrift {
+			return nil, status.Errorf(codes.FailedPrecondition, "Access Point %v of volume %v already exists with root directory %v, not cloning volume %v into it",
+				accessPointId.AccessPointId, volName, accessPointId.AccessPointRootDir, source.volumeId)
+		}
+		if err := cloneVolume(ctx, fsRoot, sourceDir, accessPointsOptions.DirectoryPath); err != nil {
+			return nil, err
+		}
+	}
 
-			//Mount File System at it root and delete access point root directory
-			mountOptions := []string{"tls", "iam"}
-			if roleArn != "" {
-				mountTarget, err := localCloud.DescribeMountTargets(ctx, fileSystemId, "")
+	if d.waitAccessPointAvailable {
+		if err := waitForAccessPoint(ctx, localCloud, accessPointId); err != nil {
+			return nil, err
+		}
+	}
 
-				if err == nil {
-					mountOptions = append(mountOptions, MountTargetIp+"="+mountTarget.IPAddress)
-				} else {
-					klog.Warningf("Failed to describe mount targets for file system %v. Skip using `mounttargetip` mount option: %v", fileSystemId, err)
-				}
-			}
+	res := d.accessPointVolumeResponse(ctx, localCloud, accessPointsOptions.FileSystemId, accessPointId, subPath, volSize, azName, volumeParams[Region], roleArn, useIam, mountTarget)
+	if enforceQuota {
+		res.Volume.VolumeContext[QuotaBytes] = strconv.FormatInt(volSize, 10)
+	}
+	setMountTargetIp(res.Volume.VolumeContext, mountTargetIp, mountTargetIps)
+	setMountOptions(res.Volume.VolumeContext, mountOptions)
+	setReplicationDestination(res.Volume.VolumeContext, replicationDestFsId)
+	setReadOnly(res.Volume.VolumeContext, readOnly)
+	res.Volume.ContentSource = req.GetVolumeContentSource()
+	rollback = false
+	keepRootDir = !rootDirDrift
+	return res, nil
+}
 
-			target := TempMountPathPrefix + "/" + accessPointId
-			if err := d.mounter.MakeDir(target); err != nil {
-				return nil, status.Errorf(codes.Internal, "Could not create dir %q: %v", target, err)
-			}
-			if err := d.mounter.Mount(fileSystemId, target, "efs", mountOptions); err != nil {
-				os.Remove(target)
-				return nil, status.Errorf(codes.Internal, "Could not mount %q at %q: %v", fileSystemId, target, err)
-			}
-			err = os.RemoveAll(target + accessPoint.AccessPointRootDir)
-			if err != nil {
-				return nil, status.Errorf(codes.Internal, "Could not delete access point root directory %q: %v", accessPoint.AccessPointRootDir, err)
-			}
-			err = d.mounter.Unmount(target)
-			if err != nil {
-				return nil, status.Errorf(codes.Internal, "Could not unmount %q: %v", target, err)
+// usesGid returns whether the access point returned for a volume uses the GID of the request, i.e. was created for it
+// rather than reused with its own GID. An access point returned without its POSIX user is assumed to use it.
+func usesGid(accessPoint *cloud.AccessPoint, gid int64) bool {
+	return accessPoint.PosixUser == nil || accessPoint.PosixUser.Gid == gid
+}
+
+// defaultGidRange returns the GID range of StorageClasses without gidRangeStart and gidRangeEnd,
+// set by --default-gid-min and --default-gid-max
+func (d *Driver) defaultGidRange() (int64, int64) {
+	gidMin, gidMax := d.defaultGidMin, d.defaultGidMax
+	if gidMin == 0 {
+		gidMin = DefaultGidMin
+	}
+	if gidMax == 0 {
+		gidMax = DefaultGidMax
+	}
+	return gidMin, gidMax
+}
+
+// rollbackAccessPoint deletes the access point created by a CreateVolume call failing afterwards. Failures are only logged.
+// EFS creates the root directory on the first mount through the access point, and a root directory created with
+// createDirMode=mount is removed by createVolume, so no directory is left behind.
+func (d *Driver) rollbackAccessPoint(localCloud cloud.Cloud, accessPointId string) {
+	// The context of the request may be the cause of the failure
+	ctx, cancel := context.WithTimeout(context.Background(), accessPointRollbackTimeout)
+	defer cancel()
+	if err := localCloud.DeleteAccessPoint(ctx, accessPointId); err != nil && !cloud.IsNotFound(err) {
+		klog.Warningf("Failed to roll back Access Point %v, it must be deleted manually: %v", accessPointId, err)
+		return
+	}
+	klog.V(2).Infof("Rolled back Access Point %v", accessPointId)
+}
+
+// accessPointVolumeResponse builds the CreateVolume response of a volume backed by an access point
+func (d *Driver) accessPointVolumeResponse(ctx context.Context, localCloud cloud.Cloud, fileSystemId string, accessPoint *cloud.AccessPoint, subPath string, volSize int64, azName, region, roleArn string, useIam bool, mountTarget *cloud.MountTarget) *csi.CreateVolumeResponse {
+	volContext := regionVolumeContext(region)
+	if useIam {
+		volContext[UseIam] = "true"
+	}
+
+	// The ARN is informational, for references to the access point in IAM policies
+	if accessPoint.AccessPointArn != "" {
+		volContext[AccessPointArn] = accessPoint.AccessPointArn
+	}
+	volumeId := formatVolumeId(regionalFileSystemId(fileSystemId, region, accessPoint.AccessPointArn), subPath, accessPoint.AccessPointId)
+	klog.V(2).Infof("Volume %v uses Access Point %v with ARN %q", volumeId, accessPoint.AccessPointId, accessPoint.AccessPointArn)
+
+	if azName != "" {
+		volContext[AzName] = azName
+	}
+
+	// Fetch mount target Ip for cross-account mount
+	if roleArn != "" {
+		var err error
+		if mountTarget == nil {
+			mountTarget, err = localCloud.DescribeMountTargets(ctx, fileSystemId, azName)
+		}
+		if err != nil {
+			klog.Warningf("Failed to describe mount targets for file system %v. Skip using `mounttargetip` mount option: %v", fileSystemId, err)
+		} else {
+			volContext[MountTargetIp] = mountTarget.IPAddress
+		}
+	}
+
+	return &csi.CreateVolumeResponse{
+		Volume: &csi.Volume{
+			CapacityBytes:      volSize,
+			VolumeId:           volumeId,
+			VolumeContext:      volContext,
+			AccessibleTopology: d.volumeTopology(ctx, localCloud, fileSystemId, azName, region, roleArn),
+		},
+	}
+}
+
+func (d *Driver) createFileSystemVolume(ctx context.Context, req *csi.CreateVolumeRequest, volName string, volSize int64, tags map[string]string) (*csi.CreateVolumeResponse, error) {
+	// Only file systems carrying the tag of their volume are deleted by DeleteVolume. Unlike the default tag, it cannot
+	// be disabled, so that a static volume of a user managed file system never causes its deletion.
+	fileSystemTags := map[string]string{FsVolumeTagKey: volName}
+	for k, v := range tags {
+		fileSystemTags[k] = v
+	}
+	fileSystemOptions := &cloud.FileSystemOptions{
+		CapacityGiB: volSize,
+		Tags:        fileSystemTags,
+	}
+
+	throughputMode, provisionedThroughput, err := parseThroughputParameters(req.GetParameters())
+	if err != nil {
+		return nil, err
+	}
+	fileSystemOptions.ThroughputMode = throughputMode
+	fileSystemOptions.ProvisionedThroughputInMibps = provisionedThroughput
+
+	region := req.GetParameters()[Region]
+	localCloud, roleArn, err := getCloud(req.GetSecrets(), region, d)
+	if err != nil {
+		return nil, err
+	}
+
+	// One Zone file systems are created in a zone of the region of the driver, or of the region parameter
+	if value, ok := req.GetParameters()[AvailabilityZoneName]; ok {
+		fileSystemRegion := region
+		if fileSystemRegion == "" {
+			fileSystemRegion = localCloud.GetMetadata().GetRegion()
+		}
+		if err := validateAvailabilityZone(value, fileSystemRegion); err != nil {
+			return nil, err
+		}
+		fileSystemOptions.AvailabilityZoneName = value
+	}
+
+	fileSystem, err := localCloud.CreateFileSystem(ctx, volName, fileSystemOptions)
+	if err != nil {
+		if cloud.IsAccessDenied(err) {
+			return nil, status.Errorf(codes.Unauthenticated, "Access Denied. Please ensure you have the right AWS permissions: %v", err)
+		}
+		if cloud.IsAlreadyExists(err) {
+			return nil, status.Errorf(codes.AlreadyExists, "File System already exists")
+		}
+		return nil, status.Errorf(codes.Internal, "Failed to create File System: %v", err)
+	}
+
+	// Nodes mount One Zone file systems through the mount target of their zone, where their pods are scheduled
+	volContext := regionVolumeContext(region)
+	var topology []*csi.Topology
+	if fileSystemOptions.AvailabilityZoneName != "" {
+		volContext[AzName] = fileSystemOptions.AvailabilityZoneName
+		topology = d.volumeTopology(ctx, localCloud, fileSystem.FileSystemId, fileSystemOptions.AvailabilityZoneName, region, roleArn)
+	}
+	return &csi.CreateVolumeResponse{
+		Volume: &csi.Volume{
+			CapacityBytes:      volSize,
+			VolumeId:           regionalFileSystemId(fileSystem.FileSystemId, region, fileSystem.FileSystemArn),
+			VolumeContext:      volContext,
+			AccessibleTopology: topology,
+		},
+	}, nil
+}
+
+// validateAvailabilityZone validates that the availabilityZoneName parameter is a zone of the region, e.g. us-east-1a
+// in us-east-1. The zones of Local Zones and Wavelength Zones do not support One Zone file systems.
+func validateAvailabilityZone(zone, region string) error {
+	suffix := strings.TrimPrefix(zone, region)
+	if region == "" || suffix == zone || len(suffix) != 1 || suffix[0] < 'a' || suffix[0] > 'z' {
+		return status.Errorf(codes.InvalidArgument, "Invalid %v %q: Expected an availability zone of region %v, e.g. %va", AvailabilityZoneName, zone, region, region)
+	}
+	return nil
+}
+
+func (d *Driver) DeleteVolume(ctx context.Context, req *csi.DeleteVolumeRequest) (*csi.DeleteVolumeResponse, error) {
+	var (
+		localCloud cloud.Cloud
+		roleArn    string
+		err        error
+	)
+
+	klog.V(4).Infof("DeleteVolume: called with args %+v", *req)
+	volId := req.GetVolumeId()
+	if volId == "" {
+		return nil, status.Error(codes.InvalidArgument, "Volume ID not provided")
+	}
+
+	// The volume ID of volumes provisioned with the region parameter starts with the ARN of their file system, which
+	// records their region. The region of volumes with other volume IDs can be given in the provisioner secret.
+	region := fileSystemArnRegion(volId)
+	if region == "" {
+		region = req.GetSecrets()[Region]
+	}
+	localCloud, roleArn, err = getCloud(req.GetSecrets(), region, d)
+	if err != nil {
+		return nil, err
+	}
+
+	fileSystemId, subpath, accessPointId, err := parseVolumeId(volId)
+	if err != nil {
+		// A volume ID which was not created by the driver names a volume that does not exist, so returning success.
+		// See here - https://github.com/kubernetes-csi/csi-test/blame/5deb83d58fea909b2895731d43e32400380aae3c/pkg/sanity/controller.go#L733
+		if !isDriverVolumeId(volId) {
+			klog.V(2).Infof("DeleteVolume: Volume ID %v was not created by the driver, returning success: %v", volId, err)
+			return &csi.DeleteVolumeResponse{}, nil
+		}
+		// A malformed volume ID of the driver is a bug, which must not be hidden
+		return nil, err
+	}
+
+	if accessPointId == "" && subpath == "" {
+		return d.deleteFileSystemVolume(ctx, localCloud, fileSystemId)
+	}
+
+	// Directories imported as static volumes were not created by the driver, neither are they deleted by it
+	if accessPointId == "" {
+		klog.V(2).Infof("DeleteVolume: Volume %v is a directory without an access point, leaving it in place", volId)
+		return &csi.DeleteVolumeResponse{}, nil
+	}
+
+	// Check if Access point exists.
+	// The access point is needed to delete its root directory, to verify it belongs to this cluster,
+	// and to find out if it is shared with other volumes.
+	accessPoint, err := localCloud.DescribeAccessPoint(ctx, accessPointId)
+	if err != nil {
+		if cloud.IsAccessDenied(err) {
+			return nil, status.Errorf(codes.Unauthenticated, "Access Denied. Please ensure you have the right AWS permissions: %v", err)
+		}
+		if cloud.IsNotFound(err) {
+			klog.V(5).Infof("DeleteVolume: Access Point %v not found, returning success", accessPointId)
+			return &csi.DeleteVolumeResponse{}, nil
+		}
+		// The provisioner retries either way, Unavailable tells that retrying is expected to succeed
+		if cloud.IsTransient(err) {
+			return nil, status.Errorf(codes.Unavailable, "Could not describe Access Point %v, retrying may succeed: %v", accessPointId, err)
+		}
+		return nil, status.Errorf(codes.Internal, "Could not get describe Access Point: %v , error: %v", accessPointId, err)
+	}
+	if owner, ok := accessPoint.Tags[ClusterNameTagKey]; ok && d.clusterName != "" && owner != d.clusterName {
+		return nil, status.Errorf(codes.FailedPrecondition, "Access Point %v belongs to cluster %q, refusing to delete it from cluster %q", accessPointId, owner, d.clusterName)
+	}
+
+	// EFS deletes an access point which is deleting already, and fails to delete one which is still creating
+	switch accessPoint.LifeCycleState {
+	case accessPointDeleting:
+		// The access point is deleted by a previous call which did not see it gone
+		if d.waitAccessPointDeleted > 0 {
+			if err := waitForDeletedAccessPoint(ctx, localCloud, accessPointId, d.waitAccessPointDeleted); err != nil {
+				return nil, err
 			}
-			err = os.RemoveAll(target)
-			if err != nil {
-				return nil, status.Errorf(codes.Internal, "Could not delete %q: %v", target, err)
+		} else {
+			klog.V(2).Infof("DeleteVolume: Access Point %v is deleting, returning success", accessPointId)
+		}
+		if accessPoint.PosixUser != nil {
+			d.gidAllocator.releaseGid(fileSystemId, accessPoint.PosixUser.Gid)
+		}
+		return &csi.DeleteVolumeResponse{}, nil
+	case accessPointCreating:
+		creating := accessPoint
+		accessPoint, err = waitForCreatingAccessPoint(ctx, localCloud, accessPoint, d.deleteCreatingApWait)
+		if err != nil {
+			return nil, err
+		}
+		if accessPoint == nil {
+			if creating.PosixUser != nil {
+				d.gidAllocator.releaseGid(fileSystemId, creating.PosixUser.Gid)
 			}
+			return &csi.DeleteVolumeResponse{}, nil
 		}
+	}
 
-		// Delete access point
-		if err = localCloud.DeleteAccessPoint(ctx, accessPointId); err != nil {
-			if err == cloud.ErrAccessDenied {
-				return nil, status.Errorf(codes.Unauthenticated, "Access Denied. Please ensure you have the right AWS permissions: %v", err)
+	// A shared access point is only deleted with the last volume referencing it
+	if shareKey, ok := accessPoint.Tags[ShareKeyTagKey]; ok {
+		unlock := d.sharedAccessPointLocks.lock(sharedAccessPointLockKey(fileSystemId, shareKey))
+		defer unlock()
+		refCount, err := d.releaseSharedAccessPoint(ctx, localCloud, accessPointId, parseShareRef(volId))
+		if err != nil {
+			return nil, err
+		}
+		if refCount > 0 {
+			return &csi.DeleteVolumeResponse{}, nil
+		}
+	}
+
+	if d.retainAccessPoints {
+		klog.Infof("DeleteVolume: retaining Access Point %v of File System %v with --retain-access-points, it must be deleted manually", accessPointId, fileSystemId)
+		return &csi.DeleteVolumeResponse{}, nil
+	}
+
+	// Keep the access point during the grace period, so that the volume can be recovered
+	if d.softDeleteGrace > 0 {
+		if err := d.softDeleteAccessPoint(ctx, localCloud, roleArn, fileSystemId, accessPointId, accessPoint.Tags); err != nil {
+			return nil, err
+		}
+		return &csi.DeleteVolumeResponse{}, nil
+	}
+
+	if err := d.destroyAccessPoint(ctx, localCloud, roleArn, fileSystemId, accessPoint); err != nil {
+		return nil, err
+	}
+
+	return &csi.DeleteVolumeResponse{}, nil
+}
+
+// hasDriverTags returns whether the tags of an EFS resource show it was created by the driver.
+// With --disable-default-tags, the resource must carry every tag of --tags instead of the default tag.
+func (d *Driver) hasDriverTags(tags map[string]string) bool {
+	if _, ok := tags[DefaultTagKey]; ok {
+		return true
+	}
+	if !d.disableDefaultTags || len(d.tags) == 0 {
+		return false
+	}
+	for k, v := range d.tags {
+		if tags[k] != v {
+			return false
+		}
+	}
+	return true
+}
+
+// isProvisionedFileSystem returns whether the tags of a file system show it was provisioned by the efs-fs mode. The
+// default tag and the tags of --tags are not enough, as they are also found on file systems managed by users.
+func isProvisionedFileSystem(tags map[string]string) bool {
+	_, ok := tags[FsVolumeTagKey]
+	return ok
+}
+
+// destroyAccessPoint deletes an access point, and its root directory if delete-access-point-root-dir is set.
+// The root directory is deleted with the mount options recorded in the tags of the access point.
+// An access point which no longer exists is not an error. The GID of the access point is released once it is deleted,
+// or once it is gone with --wait-ap-deleted.
+func (d *Driver) destroyAccessPoint(ctx context.Context, localCloud cloud.Cloud, roleArn, fileSystemId string, accessPoint *cloud.AccessPoint) error {
+	accessPointId, rootDir := accessPoint.AccessPointId, accessPoint.AccessPointRootDir
+	if d.deleteAccessPointRootDir {
+		//Mount File System at it root and delete access point root directory
+		mountOptions := d.getRootMountOptions(ctx, localCloud, roleArn, fileSystemId, d.useIam)
+		mountOptions = appendMountOptions(mountOptions, cleanupMountOptionsFromTags(accessPoint.Tags))
+		// With --cleanup-mount-batch-window, the calls deleting access points of the file system share a mount
+		if d.cleanupMountBatchWindow > 0 {
+			if err := d.deleteRootDirWithSharedMount(fileSystemId, accessPointId, rootDir, mountOptions); err != nil {
+				return err
 			}
-			if err == cloud.ErrNotFound {
-				klog.V(5).Infof("DeleteVolume: Access Point not found, returning success")
-				return &csi.DeleteVolumeResponse{}, nil
+		} else if err := d.deleteRootDir(fileSystemId, accessPointId, rootDir, mountOptions); err != nil {
+			return err
+		}
+	}
+
+	if err := localCloud.DeleteAccessPoint(ctx, accessPointId); err != nil {
+		if cloud.IsAccessDenied(err) {
+			return status.Errorf(codes.Unauthenticated, "Access Denied. Please ensure you have the right AWS permissions: %v", err)
+		}
+		if !cloud.IsNotFound(err) {
+			return status.Errorf(codes.Internal, "Failed to Delete Access Point %v: %v", accessPointId, err)
+		}
+		klog.V(5).Infof("DeleteVolume: Access Point %v not found, returning success", accessPointId)
+	} else if d.waitAccessPointDeleted > 0 {
+		// The GID is kept until the access point is gone, so that a retry finding it deleting releases it
+		if err := waitForDeletedAccessPoint(ctx, localCloud, accessPointId, d.waitAccessPointDeleted); err != nil {
+			return err
+		}
+	}
+	// Without waiting for the next listing, so that the GID is reusable by the next CreateVolume call
+	if accessPoint.PosixUser != nil {
+		d.gidAllocator.releaseGid(fileSystemId, accessPoint.PosixUser.Gid)
+	}
+	return nil
+}
+
+// deleteRootDir deletes the root directory of an access point through a mount of the root of its file system
+// made for this call only. Failing to mount is not an error: deleting the access point matters more than its root
+// directory, which can be removed manually.
+func (d *Driver) deleteRootDir(fileSystemId, accessPointId, rootDir string, mountOptions []string) (err error) {
+	target := d.getTempMountPathPrefix() + "/" + accessPointId
+	if err := d.mounter.MakeDir(target); err != nil {
+		return status.Errorf(codes.Internal, "Could not create dir %q: %v", target, err)
+	}
+	if err := d.mountForCleanup(fileSystemId, target, mountOptions); err != nil {
+		os.Remove(target)
+		klog.Warningf("DeleteVolume: could not mount %q at %q, access point root directory %q of %v is not deleted: %v", fileSystemId, target, rootDir, accessPointId, err)
+		return nil
+	}
+	// The file system is unmounted even if the root directory cannot be deleted, the first error being returned
+	defer func() {
+		if unmountErr := d.mounter.Unmount(target); unmountErr != nil {
+			if err == nil {
+				err = status.Errorf(codes.Internal, "Could not unmount %q: %v", target, unmountErr)
 			}
-			return nil, status.Errorf(codes.Internal, "Failed to Delete volume %v: %v", volId, err)
+			return
 		}
-	} else {
-		return nil, status.Errorf(codes.NotFound, "Failed to find access point for volume: %v", volId)
+		if removeErr := os.RemoveAll(target); removeErr != nil && err == nil {
+			err = status.Errorf(codes.Internal, "Could not delete %q: %v", target, removeErr)
+		}
+	}()
+	if err := os.RemoveAll(target + rootDir); err != nil {
+		return status.Errorf(codes.Internal, "Could not delete access point root directory %q: %v", rootDir, err)
+	}
+	return nil
+}
+
+// deleteFileSystemVolume deletes a file system provisioned with the efs-fs mode.
+// File systems which do not carry the FsVolumeTagKey tag are left untouched, so statically provisioned
+// volumes can never cause the deletion of a user managed file system.
+func (d *Driver) deleteFileSystemVolume(ctx context.Context, localCloud cloud.Cloud, fileSystemId string) (*csi.DeleteVolumeResponse, error) {
+	fileSystem, err := localCloud.DescribeFileSystem(ctx, fileSystemId)
+	if err != nil {
+		if cloud.IsAccessDenied(err) {
+			return nil, status.Errorf(codes.Unauthenticated, "Access Denied. Please ensure you have the right AWS permissions: %v", err)
+		}
+		if cloud.IsNotFound(err) {
+			klog.V(5).Infof("DeleteVolume: File System %v not found, returning success", fileSystemId)
+			return &csi.DeleteVolumeResponse{}, nil
+		}
+		return nil, status.Errorf(codes.Internal, "Could not describe File System: %v , error: %v", fileSystemId, err)
+	}
+
+	if !isProvisionedFileSystem(fileSystem.Tags) {
+		return nil, status.Errorf(codes.FailedPrecondition, "File System %v was not provisioned by the driver, refusing to delete it", fileSystemId)
+	}
+
+	if err = localCloud.DeleteFileSystem(ctx, fileSystemId); err != nil {
+		if cloud.IsAccessDenied(err) {
+			return nil, status.Errorf(codes.Unauthenticated, "Access Denied. Please ensure you have the right AWS permissions: %v", err)
+		}
+		if cloud.IsNotFound(err) {
+			klog.V(5).Infof("DeleteVolume: File System not found, returning success")
+			return &csi.DeleteVolumeResponse{}, nil
+		}
+		return nil, status.Errorf(codes.Internal, "Failed to Delete File System %v: %v", fileSystemId, err)
 	}
 
 	return &csi.DeleteVolumeResponse{}, nil
@@ -467,11 +1413,20 @@ func (d *Driver) ValidateVolumeCapabilities(ctx context.Context, req *csi.Valida
 	}
 
 	var confirmed *csi.ValidateVolumeCapabilitiesResponse_Confirmed
-	if err := d.isValidVolumeCapabilities(volCaps); err == nil {
+	message := ""
+	err = d.isValidVolumeCapabilities(volCaps)
+	// Writer modes are not confirmed for volumes of a read-only StorageClass
+	if err == nil && isReadOnlyVolume(req.GetVolumeContext()) {
+		err = validateReaderAccessModes(volCaps)
+	}
+	if err == nil {
 		confirmed = &csi.ValidateVolumeCapabilitiesResponse_Confirmed{VolumeCapabilities: volCaps}
+	} else {
+		message = err.Error()
 	}
 	return &csi.ValidateVolumeCapabilitiesResponse{
 		Confirmed: confirmed,
+		Message:   message,
 	}, nil
 }
 
@@ -516,11 +1471,221 @@ func (d *Driver) ControllerExpandVolume(ctx context.Context, req *csi.Controller
 }
 
 func (d *Driver) ControllerGetVolume(ctx context.Context, req *csi.ControllerGetVolumeRequest) (*csi.ControllerGetVolumeResponse, error) {
+	klog.V(4).Infof("ControllerGetVolume: called with args %+v", *req)
+	volId := req.GetVolumeId()
+	if volId == "" {
+		return nil, status.Error(codes.InvalidArgument, "Volume ID not provided")
+	}
 
-	return nil, status.Error(codes.Unimplemented, "")
+	fileSystemId, _, accessPointId, err := parseVolumeId(volId)
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "Volume %v not found: %v", volId, err)
+	}
+
+	// The request has no secrets, the volume is described with the role of the driver in the region of its volume ID
+	localCloud, _, err := getCloud(nil, fileSystemArnRegion(volId), d)
+	if err != nil {
+		return nil, err
+	}
+
+	condition := &csi.VolumeCondition{
+		Abnormal: false,
+		Message:  "",
+	}
+	// File systems have no size, their capacity is reported as unknown
+	var capacityBytes int64
+
+	if accessPointId == "" {
+		// Volumes without an access point are only as healthy as their file system being reachable
+		_, err = localCloud.DescribeFileSystem(ctx, fileSystemId)
+		if err != nil {
+			if cloud.IsAccessDenied(err) {
+				return nil, status.Errorf(codes.Unauthenticated, "Access Denied. Please ensure you have the right AWS permissions: %v", err)
+			}
+			if cloud.IsNotFound(err) {
+				return nil, status.Errorf(codes.NotFound, "File System %v not found", fileSystemId)
+			}
+			return nil, status.Errorf(codes.Internal, "Could not describe File System: %v , error: %v", fileSystemId, err)
+		}
+	} else {
+		accessPoint, err := localCloud.DescribeAccessPoint(ctx, accessPointId)
+		if err != nil {
+			if cloud.IsAccessDenied(err) {
+				return nil, status.Errorf(codes.Unauthenticated, "Access Denied. Please ensure you have the right AWS permissions: %v", err)
+			}
+			if cloud.IsNotFound(err) {
+				return nil, status.Errorf(codes.NotFound, "Access Point %v not found", accessPointId)
+			}
+			return nil, status.Errorf(codes.Internal, "Could not get describe Access Point: %v , error: %v", accessPointId, err)
+		}
+		if accessPoint.LifeCycleState != efs.LifeCycleStateAvailable {
+			condition.Abnormal = true
+			condition.Message = fmt.Sprintf("Access Point %v is in %v state", accessPointId, accessPoint.LifeCycleState)
+		}
+		capacityBytes = accessPointCapacityBytes(accessPoint)
+	}
+
+	return &csi.ControllerGetVolumeResponse{
+		Volume: &csi.Volume{
+			VolumeId:      volId,
+			CapacityBytes: capacityBytes,
+		},
+		Status: &csi.ControllerGetVolumeResponse_VolumeStatus{
+			VolumeCondition: condition,
+		},
+	}, nil
+}
+
+// accessPointCapacityBytes returns the capacity of the volume of an access point: its quota when enforced, otherwise
+// the capacity requested when it was provisioned. It returns 0, an unknown capacity, when neither is recorded in its tags.
+func accessPointCapacityBytes(accessPoint *cloud.AccessPoint) int64 {
+	for _, key := range []string{cloud.QuotaBytesTagKey, RequestedBytesTagKey} {
+		value, ok := accessPoint.Tags[key]
+		if !ok {
+			continue
+		}
+		bytes, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || bytes <= 0 {
+			klog.Warningf("Ignoring invalid tag %v=%q of Access Point %v", key, value, accessPoint.AccessPointId)
+			continue
+		}
+		return bytes
+	}
+	return 0
+}
+
+// accessPointMatchesRequest checks if an existing access point satisfies the options of a create request.
+// The GID is only compared when it was not generated by the driver for this request. A root directory which differs,
+// see rootDirDrifted, is only accepted when the access point was provisioned for the PVC of the request: its
+// basePath or subPathPattern were then edited between retries, rather than another volume having the same name.
+func accessPointMatchesRequest(accessPoint *cloud.AccessPoint, accessPointOpts *cloud.AccessPointOptions, compareGid, uniqueRootDir bool) bool {
+	if accessPoint.FileSystemId != accessPointOpts.FileSystemId {
+		return false
+	}
+	// Capacity is only recorded by the fake cloud provider, see cloud.AccessPoint
+	if accessPoint.CapacityGiB != 0 && accessPoint.CapacityGiB != accessPointOpts.CapacityGiB {
+		return false
+	}
+	if compareGid && accessPoint.PosixUser != nil && accessPoint.PosixUser.Gid != accessPointOpts.Gid {
+		return false
+	}
+	if rootDirDrifted(accessPoint, accessPointOpts, uniqueRootDir) {
+		pvc, ok := accessPoint.Tags[PvcTagKey]
+		return ok && pvc == accessPointOpts.Tags[PvcTagKey]
+	}
+	return true
 }
 
-func getCloud(secrets map[string]string, driver *Driver) (cloud.Cloud, string, error) {
+// rootDirDrifted returns whether the root directory of an existing access point differs from the one the parameters
+// of the request give, e.g. when basePath or subPathPattern were edited between a failed and a retried CreateVolume.
+// Unique root directories are only compared up to their random suffix, as it differs between retries.
+func rootDirDrifted(accessPoint *cloud.AccessPoint, accessPointOpts *cloud.AccessPointOptions, uniqueRootDir bool) bool {
+	if accessPoint.AccessPointRootDir == "" {
+		return false
+	}
+	if uniqueRootDir {
+		return path.Dir(accessPoint.AccessPointRootDir) != path.Dir(accessPointOpts.DirectoryPath)
+	}
+	return accessPoint.AccessPointRootDir != accessPointOpts.DirectoryPath
+}
+
+// getRootMountOptions returns the options of the controller's mounts of the root of a file system.
+// File systems of another account are mounted through the IP address of one of their mount targets.
+func (d *Driver) getRootMountOptions(ctx context.Context, localCloud cloud.Cloud, roleArn, fileSystemId string, useIam bool) []string {
+	mountOptions := d.getCleanupMountOptions()
+	if useIam && !hasOption(mountOptions, "iam") {
+		mountOptions = append(mountOptions, "iam")
+	}
+	if roleArn != "" && !hasOptionKey(mountOptions, MountTargetIp) {
+		mountTarget, err := localCloud.DescribeMountTargets(ctx, fileSystemId, "")
+
+		if err == nil {
+			mountOptions = append(mountOptions, MountTargetIp+"="+mountTarget.IPAddress)
+		} else {
+			klog.Warningf("Failed to describe mount targets for file system %v. Skip using `mounttargetip` mount option: %v", fileSystemId, err)
+		}
+	}
+	return mountOptions
+}
+
+// mountForCleanup mounts the file system for the DeleteVolume cleanup, and for the creation of root directories with createDirMode=mount. The mount is retried with an exponential backoff,
+// as it fails transiently while the mount target is not ready or its DNS name does not resolve yet.
+// Retries stop after cleanupMountRetries attempts, or once cleanupMountTimeout has elapsed when set.
+// The file system is mounted with efs-utils, or with nfs4 with --cleanup-mount-flavor.
+func (d *Driver) mountForCleanup(fileSystemId, target string, mountOptions []string) error {
+	backoff := cleanupMountBackoff
+	backoff.Steps = d.cleanupMountRetries + 1
+	var deadline time.Time
+	if d.cleanupMountTimeout > 0 {
+		deadline = d.currentTime().Add(d.cleanupMountTimeout)
+	}
+
+	source, fsType, options := d.cleanupMountArgs(fileSystemId, mountOptions)
+	attempt := 0
+	var mountErr error
+	err := wait.ExponentialBackoff(backoff, func() (bool, error) {
+		attempt++
+		mountErr = d.mounter.Mount(source, target, fsType, options)
+		if mountErr == nil {
+			return true, nil
+		}
+		klog.Warningf("Attempt %d to mount %q at %q failed: %v", attempt, fileSystemId, target, mountErr)
+		if !deadline.IsZero() && d.currentTime().After(deadline) {
+			return false, mountErr
+		}
+		return false, nil
+	})
+	if err == wait.ErrWaitTimeout {
+		return mountErr
+	}
+	return err
+}
+
+// getTempMountPathPrefix returns the directory used for the DeleteVolume cleanup mount.
+func (d *Driver) getTempMountPathPrefix() string {
+	if d.tempMountPathPrefix != "" {
+		return d.tempMountPathPrefix
+	}
+	return TempMountPathPrefix
+}
+
+// getCleanupMountOptions returns a copy of the mount options used for the DeleteVolume cleanup mount.
+func (d *Driver) getCleanupMountOptions() []string {
+	if len(d.cleanupMountOptions) != 0 {
+		return append([]string{}, d.cleanupMountOptions...)
+	}
+	return append([]string{}, defaultCleanupMountOptions...)
+}
+
+// ParseCleanupMountOptions parses the comma separated --cleanup-mount-options value.
+// Options with a key unknown to efs-utils are kept, but a warning is logged for each of them.
+func ParseCleanupMountOptions(optionsStr string) []string {
+	options := []string{}
+	for _, option := range strings.Split(optionsStr, ",") {
+		option = strings.TrimSpace(option)
+		if option == "" {
+			continue
+		}
+		key := strings.SplitN(option, "=", 2)[0]
+		if !hasOption(knownCleanupMountOptionKeys, key) {
+			klog.Warningf("Unknown cleanup mount option %q, passing it to the mount helper as is", key)
+		}
+		options = append(options, option)
+	}
+	return options
+}
+
+// hasOptionKey checks if options contain an option with the given key, with or without a value
+func hasOptionKey(options []string, key string) bool {
+	for _, o := range options {
+		if o == key || strings.HasPrefix(o, key+"=") {
+			return true
+		}
+	}
+	return false
+}
+
+func getCloud(secrets map[string]string, region string, driver *Driver) (cloud.Cloud, string, error) {
 
 	var localCloud cloud.Cloud
 	var roleArn string
@@ -533,7 +1698,7 @@ func getCloud(secrets map[string]string, driver *Driver) (cloud.Cloud, string, e
 	}
 
 	if roleArn != "" {
-		localCloud, err = cloud.NewCloudWithRole(roleArn)
+		localCloud, err = cloud.NewCloudWithRole(roleArn, driver.cloudOptions)
 		if err != nil {
 			return nil, "", status.Errorf(codes.Unauthenticated, "Unable to initialize aws cloud: %v. Please verify role has the correct AWS permissions for cross account mount", err)
 		}
@@ -541,7 +1706,56 @@ func getCloud(secrets map[string]string, driver *Driver) (cloud.Cloud, string, e
 		localCloud = driver.cloud
 	}
 
-	return localCloud, roleArn, nil
+	// Target the EFS API of another region than the one of the instance
+	if region != "" {
+		localCloud, err = localCloud.WithRegion(region)
+		if err != nil {
+			if err == cloud.ErrInvalidRegion {
+				return nil, "", status.Errorf(codes.InvalidArgument, "Invalid %v %q: %v", Region, region, err)
+			}
+			return nil, "", status.Errorf(codes.Internal, "Unable to initialize aws cloud for %v %v: %v", Region, region, err)
+		}
+	}
+
+	return driver.withCallTimeouts(localCloud), roleArn, nil
+}
+
+// omitEmptyValues deletes the keys of the volume context with an empty value, which some consumers of the context,
+// e.g. static provisioning tools round-tripping it, do not accept
+func omitEmptyValues(volContext map[string]string) {
+	for key, value := range volContext {
+		if value == "" {
+			klog.V(4).Infof("Omitting empty volume context key %v", key)
+			delete(volContext, key)
+		}
+	}
+}
+
+// regionalFileSystemId returns the file system part of the volume ID of a volume provisioned with the region parameter:
+// the ARN of its file system, recording the region for DeleteVolume, whose request has no volume context. resourceArn is
+// the ARN of the file system or of one of its access points. Without region, the file system ID is returned.
+func regionalFileSystemId(fileSystemId, region, resourceArn string) string {
+	if region == "" {
+		return fileSystemId
+	}
+	fields := strings.SplitN(resourceArn, ":", 6)
+	if len(fields) == 6 {
+		fileSystemArn := strings.Join(fields[:5], ":") + ":file-system/" + fileSystemId
+		if fileSystemArnRegion(fileSystemArn) != "" {
+			return fileSystemArn
+		}
+	}
+	klog.Warningf("Could not record %v %v in the volume ID of File System %v from ARN %q, DeleteVolume requires the %v secret", Region, region, fileSystemId, resourceArn, Region)
+	return fileSystemId
+}
+
+// regionVolumeContext returns the volume context making the node mount the file system in region
+func regionVolumeContext(region string) map[string]string {
+	volContext := map[string]string{}
+	if region != "" {
+		volContext[Region] = region
+	}
+	return volContext
 }
 
 func interpolateRootDirectoryName(rootDirectoryPath string, volumeParams map[string]string) (string, error) {
@@ -557,6 +1771,50 @@ func interpolateRootDirectoryName(rootDirectoryPath string, volumeParams map[str
 	return result, nil
 }
 
+// driverTags returns the tags the driver adds to every EFS resource it creates
+func (d *Driver) driverTags() map[string]string {
+	tags := map[string]string{}
+	if !d.disableDefaultTags {
+		tags[DefaultTagKey] = DefaultTagValue
+	}
+
+	// Append input tags to default tag
+	for k, v := range d.tags {
+		tags[k] = v
+	}
+
+	// Record the owning cluster so that clusters sharing a file system never delete each other's access points
+	if d.clusterName != "" {
+		tags[ClusterNameTagKey] = d.clusterName
+	}
+	return tags
+}
+
+// interpolateNameTag builds the Name tag from the nameTag parameter, which may reference the same PVC and PV metadata
+// as subPathPattern. Characters not allowed in AWS tag values are replaced with '-', and the name is truncated to their maximum length.
+func interpolateNameTag(pattern string, volumeParams map[string]string) (string, error) {
+	r := strings.NewReplacer(createListOfVariableSubstitutions(volumeParams)...)
+	name := r.Replace(pattern)
+	if strings.Contains(name, "${") {
+		return "", status.Errorf(codes.InvalidArgument, "Parameter %v %q contains invalid elements. Can only contain %v", NameTag, pattern, getSupportedComponentNames())
+	}
+
+	name = sanitizeTagValue(name)
+	if name == "" {
+		return "", status.Errorf(codes.InvalidArgument, "Parameter %v %q results in an empty name", NameTag, pattern)
+	}
+	return name, nil
+}
+
+// sanitizeTagValue replaces the characters not allowed in AWS tag values with '-', and truncates the value to their maximum length
+func sanitizeTagValue(value string) string {
+	value = invalidTagChars.ReplaceAllString(strings.TrimSpace(value), "-")
+	if runes := []rune(value); len(runes) > maxTagValueLength {
+		value = string(runes[:maxTagValueLength])
+	}
+	return value
+}
+
 func createListOfVariableSubstitutions(volumeParams map[string]string) []string {
 	variableSubstitutions := make([]string, 2*len(subPathPatternComponents))
 	i := 0
@@ -580,6 +1838,84 @@ func getSupportedComponentNames() []string {
 	return keys
 }
 
+// parseParentDirOwnerId parses the parentDirOwnerUid or parentDirOwnerGid parameter, -1 when unset.
+// It only applies to the directories of basePath created with basePathPerms.
+func parseParentDirOwnerId(volumeParams map[string]string, param string, ensureBasePath bool) (int64, error) {
+	value, ok := volumeParams[param]
+	if !ok {
+		return -1, nil
+	}
+	if !ensureBasePath {
+		return 0, status.Errorf(codes.InvalidArgument, "Parameter %v requires %v", param, BasePathPerms)
+	}
+	id, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return 0, status.Errorf(codes.InvalidArgument, "Failed to parse invalid %v: %v", param, err)
+	}
+	if id < 0 {
+		return 0, status.Errorf(codes.InvalidArgument, "%v must be greater or equal than 0", param)
+	}
+	return id, nil
+}
+
+// normalizeBasePath validates that basePath is absolute and free of ".." segments, and cleans it.
+// An empty basePath refers to the root of the file system.
+func normalizeBasePath(basePath string) (string, error) {
+	if basePath == "" {
+		return "", nil
+	}
+	if !strings.HasPrefix(basePath, "/") {
+		return "", status.Errorf(codes.InvalidArgument, "%v %q must be an absolute path", BasePath, basePath)
+	}
+	for _, segment := range strings.Split(basePath, "/") {
+		if segment == ".." {
+			return "", status.Errorf(codes.InvalidArgument, "%v %q must not contain '..'", BasePath, basePath)
+		}
+	}
+	return path.Clean(basePath), nil
+}
+
+// getUniqueRootDirName appends a suffix to name according to the rootDirSuffix mode. Suffixes resulting in
+// the root directory of an existing access point are discarded and a new one is generated.
+func (d *Driver) getUniqueRootDirName(name, basePath, suffixMode string, volumeParams map[string]string, accessPoints []*cloud.AccessPoint) (string, error) {
+	usedRootDirs := make(map[string]bool)
+	for _, ap := range accessPoints {
+		if ap != nil && ap.AccessPointRootDir != "" {
+			usedRootDirs[ap.AccessPointRootDir] = true
+		}
+	}
+
+	for i := 0; i < maxRootDirNameAttempts; i++ {
+		suffix, err := d.getRootDirSuffix(suffixMode, volumeParams)
+		if err != nil {
+			return "", err
+		}
+		rootDirName := fmt.Sprintf("%s-%s", name, suffix)
+		if !usedRootDirs[path.Join("/", basePath, rootDirName)] {
+			return rootDirName, nil
+		}
+		klog.V(4).Infof("Access point root directory %v is already in use, generating a new suffix", rootDirName)
+	}
+	return "", status.Errorf(codes.Internal, "Failed to generate an unused access point root directory for %v after %d attempts", name, maxRootDirNameAttempts)
+}
+
+func (d *Driver) getRootDirSuffix(suffixMode string, volumeParams map[string]string) (string, error) {
+	id := d.generateUUID()
+	switch suffixMode {
+	case RootDirSuffixShort:
+		return strings.ReplaceAll(id, "-", "")[:8], nil
+	case RootDirSuffixPvcName:
+		pvcName := invalidRootDirChars.ReplaceAllString(volumeParams[PvcName], "-")
+		if pvcName == "" {
+			return "", status.Errorf(codes.InvalidArgument, "%v %v requires the PVC name. Please enable extra-create-metadata on the csi-provisioner", RootDirSuffix, RootDirSuffixPvcName)
+		}
+		// The PVC name is not unique across namespaces, so a short random part is still needed
+		return pvcName + "-" + strings.ReplaceAll(id, "-", "")[:8], nil
+	default:
+		return id, nil
+	}
+}
+
 func validateEfsPathRequirements(proposedPath string) (bool, error) {
 	if len(proposedPath) > 100 {
 		// Check the proposed path is 100 characters or fewer
@@ -592,6 +1928,64 @@ func validateEfsPathRequirements(proposedPath string) (bool, error) {
 	}
 }
 
+// parseAccessPointSubPath validates the accessPointSubPath parameter, and returns the cleaned subdirectory.
+// It must be absolute and cannot contain '..', nor the ':' separating the fields of the volume ID.
+func parseAccessPointSubPath(value string) (string, error) {
+	value = strings.TrimSpace(value)
+	if !strings.HasPrefix(value, "/") {
+		return "", status.Errorf(codes.InvalidArgument, "Parameter %v must be an absolute path, got %q", AccessPointSubPath, value)
+	}
+	if strings.Contains(value, ":") || strings.Contains(value, "..") {
+		return "", status.Errorf(codes.InvalidArgument, "Parameter %v cannot contain ':' or '..', got %q", AccessPointSubPath, value)
+	}
+	subPath := path.Clean(value)
+	if subPath == "/" {
+		return "", nil
+	}
+	return subPath, nil
+}
+
+// parseThroughputParameters parses the throughput related parameters of the efs-fs provisioning mode.
+// provisionedThroughputInMibps is required with, and only allowed with, throughputMode=provisioned.
+func parseThroughputParameters(volumeParams map[string]string) (string, float64, error) {
+	var (
+		throughputMode        string
+		provisionedThroughput float64
+		err                   error
+	)
+
+	if value, ok := volumeParams[ThroughputMode]; ok {
+		throughputMode = value
+		switch throughputMode {
+		case efs.ThroughputModeBursting, efs.ThroughputModeProvisioned, cloud.ThroughputModeElastic:
+		default:
+			return "", 0, status.Errorf(codes.InvalidArgument, "Invalid %v %q. Supported values are %v, %v and %v",
+				ThroughputMode, throughputMode, efs.ThroughputModeBursting, efs.ThroughputModeProvisioned, cloud.ThroughputModeElastic)
+		}
+	}
+
+	if value, ok := volumeParams[ProvisionedThroughput]; ok {
+		if throughputMode != efs.ThroughputModeProvisioned {
+			return "", 0, status.Errorf(codes.InvalidArgument, "%v can only be set when %v is %v", ProvisionedThroughput, ThroughputMode, efs.ThroughputModeProvisioned)
+		}
+		provisionedThroughput, err = strconv.ParseFloat(value, 64)
+		if err != nil {
+			return "", 0, status.Errorf(codes.InvalidArgument, "Failed to parse invalid %v: %v", ProvisionedThroughput, err)
+		}
+		// ParseFloat accepts NaN and infinities, and NaN would pass the bound check below
+		if math.IsNaN(provisionedThroughput) || math.IsInf(provisionedThroughput, 0) {
+			return "", 0, status.Errorf(codes.InvalidArgument, "%v must be a finite number, got %q", ProvisionedThroughput, value)
+		}
+		if provisionedThroughput <= 0 {
+			return "", 0, status.Errorf(codes.InvalidArgument, "%v must be greater than 0", ProvisionedThroughput)
+		}
+	} else if throughputMode == efs.ThroughputModeProvisioned {
+		return "", 0, status.Errorf(codes.InvalidArgument, "Missing %v parameter", ProvisionedThroughput)
+	}
+
+	return throughputMode, provisionedThroughput, nil
+}
+
 func get64LenHash(text string) string {
 	h := sha256.New()
 	h.Write([]byte(text))
@@ -4,13 +4,19 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"os"
+	"reflect"
 	"regexp"
 	"strconv"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/google/uuid"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"k8s.io/apimachinery/pkg/util/wait"
 
 	"github.com/container-storage-interface/spec/lib/go/csi"
 	"github.com/golang/mock/gomock"
@@ -64,7 +70,7 @@ func TestCreateVolume(t *testing.T) {
 						ProvisioningMode: "efs-ap",
 						FsId:             fsId,
 						DirectoryPerms:   "777",
-						BasePath:         "test",
+						BasePath:         "/test",
 						Uid:              "1000",
 						Gid:              "1001",
 					},
@@ -87,6 +93,12 @@ func TestCreateVolume(t *testing.T) {
 						if accessPointsOptions.Gid != 1001 {
 							t.Fatalf("Gid mismatched. Expected: %v, actual: %v", 1001, accessPointsOptions.Gid)
 						}
+						if accessPointsOptions.OwnerUid != 1000 {
+							t.Fatalf("OwnerUid mismatched. Expected: %v, actual: %v", 1000, accessPointsOptions.OwnerUid)
+						}
+						if accessPointsOptions.OwnerGid != 1001 {
+							t.Fatalf("OwnerGid mismatched. Expected: %v, actual: %v", 1001, accessPointsOptions.OwnerGid)
+						}
 					})
 
 				res, err := driver.CreateVolume(ctx, req)
@@ -105,6 +117,227 @@ func TestCreateVolume(t *testing.T) {
 				mockCtl.Finish()
 			},
 		},
+		{
+			name: "Success: Access point subpath is encoded in the volume Id",
+			testFunc: func(t *testing.T) {
+				mockCtl := gomock.NewController(t)
+				mockCloud := mocks.NewMockCloud(mockCtl)
+
+				driver := &Driver{
+					endpoint:     endpoint,
+					cloud:        mockCloud,
+					gidAllocator: NewGidAllocator(),
+				}
+
+				req := &csi.CreateVolumeRequest{
+					Name: volumeName,
+					VolumeCapabilities: []*csi.VolumeCapability{
+						stdVolCap,
+					},
+					CapacityRange: &csi.CapacityRange{
+						RequiredBytes: capacityRange,
+					},
+					Parameters: map[string]string{
+						ProvisioningMode:   "efs-ap",
+						FsId:               fsId,
+						DirectoryPerms:     "777",
+						Uid:                "1000",
+						Gid:                "1001",
+						AccessPointSubPath: "/data/",
+					},
+				}
+
+				ctx := context.Background()
+				fileSystem := &cloud.FileSystem{
+					FileSystemId: fsId,
+				}
+				accessPoint := &cloud.AccessPoint{
+					AccessPointId: apId,
+					FileSystemId:  fsId,
+				}
+				mockCloud.EXPECT().DescribeFileSystem(gomock.Eq(ctx), gomock.Any()).Return(fileSystem, nil)
+				mockCloud.EXPECT().CreateAccessPoint(gomock.Eq(ctx), gomock.Eq(volumeName), gomock.Any(), gomock.Eq(false)).Return(accessPoint, nil)
+
+				res, err := driver.CreateVolume(ctx, req)
+				if err != nil {
+					t.Fatalf("CreateVolume failed: %v", err)
+				}
+
+				expectedVolumeId := fsId + ":/data:" + apId
+				if res.Volume.VolumeId != expectedVolumeId {
+					t.Fatalf("Volume Id mismatched. Expected: %v, Actual: %v", expectedVolumeId, res.Volume.VolumeId)
+				}
+				mockCtl.Finish()
+			},
+		},
+		{
+			name: "Fail: Access point subpath contains the volume Id separator",
+			testFunc: func(t *testing.T) {
+				mockCtl := gomock.NewController(t)
+				mockCloud := mocks.NewMockCloud(mockCtl)
+
+				driver := &Driver{
+					endpoint:     endpoint,
+					cloud:        mockCloud,
+					gidAllocator: NewGidAllocator(),
+				}
+
+				req := &csi.CreateVolumeRequest{
+					Name: volumeName,
+					VolumeCapabilities: []*csi.VolumeCapability{
+						stdVolCap,
+					},
+					Parameters: map[string]string{
+						ProvisioningMode:   "efs-ap",
+						FsId:               fsId,
+						DirectoryPerms:     "777",
+						AccessPointSubPath: "/data:fsap-other",
+					},
+				}
+
+				ctx := context.Background()
+				_, err := driver.CreateVolume(ctx, req)
+				if status.Code(err) != codes.InvalidArgument {
+					t.Fatalf("Expected InvalidArgument, got: %v", err)
+				}
+				mockCtl.Finish()
+			},
+		},
+		{
+			name: "Success: Using ownerUid/ownerGid for the root directory",
+			testFunc: func(t *testing.T) {
+				mockCtl := gomock.NewController(t)
+				mockCloud := mocks.NewMockCloud(mockCtl)
+
+				driver := &Driver{
+					endpoint:     endpoint,
+					cloud:        mockCloud,
+					gidAllocator: NewGidAllocator(),
+				}
+
+				req := &csi.CreateVolumeRequest{
+					Name: volumeName,
+					VolumeCapabilities: []*csi.VolumeCapability{
+						stdVolCap,
+					},
+					CapacityRange: &csi.CapacityRange{
+						RequiredBytes: capacityRange,
+					},
+					Parameters: map[string]string{
+						ProvisioningMode: "efs-ap",
+						FsId:             fsId,
+						DirectoryPerms:   "777",
+						Uid:              "1000",
+						Gid:              "1001",
+						OwnerUid:         "0",
+						OwnerGid:         "0",
+					},
+				}
+
+				ctx := context.Background()
+				fileSystem := &cloud.FileSystem{
+					FileSystemId: fsId,
+				}
+				accessPoint := &cloud.AccessPoint{
+					AccessPointId: apId,
+					FileSystemId:  fsId,
+				}
+				mockCloud.EXPECT().DescribeFileSystem(gomock.Eq(ctx), gomock.Any()).Return(fileSystem, nil)
+				mockCloud.EXPECT().CreateAccessPoint(gomock.Eq(ctx), gomock.Eq(volumeName), gomock.Any(), gomock.Eq(false)).Return(accessPoint, nil).
+					Do(func(ctx context.Context, clientToken string, accessPointsOptions *cloud.AccessPointOptions, reuseAccessPointName bool) {
+						if accessPointsOptions.Uid != 1000 {
+							t.Fatalf("Uid mismatched. Expected: %v, actual: %v", 1000, accessPointsOptions.Uid)
+						}
+						if accessPointsOptions.Gid != 1001 {
+							t.Fatalf("Gid mismatched. Expected: %v, actual: %v", 1001, accessPointsOptions.Gid)
+						}
+						if accessPointsOptions.OwnerUid != 0 {
+							t.Fatalf("OwnerUid mismatched. Expected: %v, actual: %v", 0, accessPointsOptions.OwnerUid)
+						}
+						if accessPointsOptions.OwnerGid != 0 {
+							t.Fatalf("OwnerGid mismatched. Expected: %v, actual: %v", 0, accessPointsOptions.OwnerGid)
+						}
+					})
+
+				_, err := driver.CreateVolume(ctx, req)
+				if err != nil {
+					t.Fatalf("CreateVolume failed: %v", err)
+				}
+				mockCtl.Finish()
+			},
+		},
+		{
+			name: "Fail: Negative ownerUid",
+			testFunc: func(t *testing.T) {
+				mockCtl := gomock.NewController(t)
+				mockCloud := mocks.NewMockCloud(mockCtl)
+
+				driver := &Driver{
+					endpoint:     endpoint,
+					cloud:        mockCloud,
+					gidAllocator: NewGidAllocator(),
+				}
+
+				req := &csi.CreateVolumeRequest{
+					Name: volumeName,
+					VolumeCapabilities: []*csi.VolumeCapability{
+						stdVolCap,
+					},
+					CapacityRange: &csi.CapacityRange{
+						RequiredBytes: capacityRange,
+					},
+					Parameters: map[string]string{
+						ProvisioningMode: "efs-ap",
+						FsId:             fsId,
+						DirectoryPerms:   "777",
+						OwnerUid:         "-1",
+					},
+				}
+
+				ctx := context.Background()
+				_, err := driver.CreateVolume(ctx, req)
+				if status.Code(err) != codes.InvalidArgument {
+					t.Fatalf("Expected InvalidArgument, got: %v", err)
+				}
+				mockCtl.Finish()
+			},
+		},
+		{
+			name: "Fail: Invalid ownerGid",
+			testFunc: func(t *testing.T) {
+				mockCtl := gomock.NewController(t)
+				mockCloud := mocks.NewMockCloud(mockCtl)
+
+				driver := &Driver{
+					endpoint:     endpoint,
+					cloud:        mockCloud,
+					gidAllocator: NewGidAllocator(),
+				}
+
+				req := &csi.CreateVolumeRequest{
+					Name: volumeName,
+					VolumeCapabilities: []*csi.VolumeCapability{
+						stdVolCap,
+					},
+					CapacityRange: &csi.CapacityRange{
+						RequiredBytes: capacityRange,
+					},
+					Parameters: map[string]string{
+						ProvisioningMode: "efs-ap",
+						FsId:             fsId,
+						DirectoryPerms:   "777",
+						OwnerGid:         "root",
+					},
+				}
+
+				ctx := context.Background()
+				_, err := driver.CreateVolume(ctx, req)
+				if status.Code(err) != codes.InvalidArgument {
+					t.Fatalf("Expected InvalidArgument, got: %v", err)
+				}
+				mockCtl.Finish()
+			},
+		},
 		{
 			name: "Success: Using fixed UID/GID and GID range",
 			testFunc: func(t *testing.T) {
@@ -129,7 +362,7 @@ func TestCreateVolume(t *testing.T) {
 						ProvisioningMode: "efs-ap",
 						FsId:             fsId,
 						DirectoryPerms:   "777",
-						BasePath:         "test",
+						BasePath:         "/test",
 						GidMin:           "5000",
 						GidMax:           "10000",
 						Uid:              "1000",
@@ -196,7 +429,7 @@ func TestCreateVolume(t *testing.T) {
 						ProvisioningMode: "efs-ap",
 						FsId:             fsId,
 						DirectoryPerms:   "777",
-						BasePath:         "test",
+						BasePath:         "/test",
 						GidMin:           "1001",
 						GidMax:           "1003",
 					},
@@ -278,7 +511,7 @@ func TestCreateVolume(t *testing.T) {
 						ProvisioningMode: "efs-ap",
 						FsId:             fsId,
 						DirectoryPerms:   "777",
-						BasePath:         "test",
+						BasePath:         "/test",
 						GidMin:           "1001",
 						GidMax:           "1005",
 					},
@@ -407,7 +640,7 @@ func TestCreateVolume(t *testing.T) {
 						ProvisioningMode: "efs-ap",
 						FsId:             fsId,
 						DirectoryPerms:   "777",
-						BasePath:         "test",
+						BasePath:         "/test",
 						GidMin:           "1000",
 						GidMax:           "2000",
 					},
@@ -497,7 +730,7 @@ func TestCreateVolume(t *testing.T) {
 						ProvisioningMode: "efs-ap",
 						FsId:             fsId,
 						DirectoryPerms:   "777",
-						BasePath:         "test",
+						BasePath:         "/test",
 						GidMin:           "1000",
 						GidMax:           "1000000",
 					},
@@ -574,6 +807,13 @@ func TestCreateVolume(t *testing.T) {
 				}
 				accessPoints := []*cloud.AccessPoint{accessPoint}
 				mockCloud.EXPECT().ListAccessPoints(gomock.Eq(ctx), gomock.Any()).Return(accessPoints, nil)
+				mountTarget := &cloud.MountTarget{
+					AZName:        "us-east-1a",
+					AZId:          "mock-AZ-id",
+					MountTargetId: "fsmt-abcd1234",
+					IPAddress:     "127.0.0.1",
+				}
+				mockCloud.EXPECT().DescribeMountTargets(gomock.Eq(ctx), gomock.Eq(fsId), gomock.Eq("us-east-1a")).Return(mountTarget, nil)
 				mockCloud.EXPECT().CreateAccessPoint(gomock.Eq(ctx), gomock.Eq(volumeName), gomock.Any(), gomock.Any()).Return(accessPoint, nil)
 
 				res, err := driver.CreateVolume(ctx, req)
@@ -589,6 +829,10 @@ func TestCreateVolume(t *testing.T) {
 				if res.Volume.VolumeId != volumeId {
 					t.Fatalf("Volume Id mismatched. Expected: %v, Actual: %v", volumeId, res.Volume.VolumeId)
 				}
+
+				if res.Volume.VolumeContext[AzName] != "us-east-1a" {
+					t.Fatalf("Volume context az mismatched. Expected: %v, Actual: %v", "us-east-1a", res.Volume.VolumeContext[AzName])
+				}
 				mockCtl.Finish()
 			},
 		},
@@ -616,7 +860,7 @@ func TestCreateVolume(t *testing.T) {
 						ProvisioningMode: "efs-ap",
 						FsId:             fsId,
 						DirectoryPerms:   "777",
-						BasePath:         "test",
+						BasePath:         "/test",
 					},
 				}
 
@@ -649,16 +893,17 @@ func TestCreateVolume(t *testing.T) {
 			},
 		},
 		{
-			name: "Success: Normal flow with tags",
+			name: "Success: Default GID range set by flags is used when the StorageClass omits it",
 			testFunc: func(t *testing.T) {
 				mockCtl := gomock.NewController(t)
 				mockCloud := mocks.NewMockCloud(mockCtl)
 
 				driver := &Driver{
-					endpoint:     endpoint,
-					cloud:        mockCloud,
-					gidAllocator: NewGidAllocator(),
-					tags:         parseTagsFromStr("cluster:efs"),
+					endpoint:      endpoint,
+					cloud:         mockCloud,
+					gidAllocator:  NewGidAllocator(),
+					defaultGidMin: 3000,
+					defaultGidMax: 3100,
 				}
 
 				req := &csi.CreateVolumeRequest{
@@ -672,8 +917,6 @@ func TestCreateVolume(t *testing.T) {
 					Parameters: map[string]string{
 						ProvisioningMode: "efs-ap",
 						FsId:             fsId,
-						GidMin:           "1000",
-						GidMax:           "2000",
 						DirectoryPerms:   "777",
 					},
 				}
@@ -682,42 +925,34 @@ func TestCreateVolume(t *testing.T) {
 				accessPoint := &cloud.AccessPoint{
 					AccessPointId: apId,
 					FileSystemId:  fsId,
-					PosixUser: &cloud.PosixUser{
-						Gid: 1000,
-						Uid: 1000,
-					},
 				}
-				accessPoints := []*cloud.AccessPoint{accessPoint}
-				mockCloud.EXPECT().ListAccessPoints(gomock.Eq(ctx), gomock.Any()).Return(accessPoints, nil)
-				mockCloud.EXPECT().CreateAccessPoint(gomock.Eq(ctx), gomock.Any(), gomock.Any(), gomock.Any()).Return(accessPoint, nil)
-
-				res, err := driver.CreateVolume(ctx, req)
+				mockCloud.EXPECT().ListAccessPoints(gomock.Eq(ctx), gomock.Any()).Return(nil, nil)
+				mockCloud.EXPECT().CreateAccessPoint(gomock.Eq(ctx), gomock.Any(), gomock.Any(), gomock.Any()).Return(accessPoint, nil).
+					Do(func(ctx context.Context, clientToken string, accessPointsOptions *cloud.AccessPointOptions, reuseAccessPointName bool) {
+						if accessPointsOptions.Gid != 3000 {
+							t.Fatalf("Gid mismatched. Expected: %v, actual: %v", 3000, accessPointsOptions.Gid)
+						}
+					})
 
+				_, err := driver.CreateVolume(ctx, req)
 				if err != nil {
 					t.Fatalf("CreateVolume failed: %v", err)
 				}
-
-				if res.Volume == nil {
-					t.Fatal("Volume is nil")
-				}
-
-				if res.Volume.VolumeId != volumeId {
-					t.Fatalf("Volume Id mismatched. Expected: %v, Actual: %v", volumeId, res.Volume.VolumeId)
-				}
 				mockCtl.Finish()
 			},
 		},
 		{
-			name: "Success: Normal flow with invalid tags",
+			name: "Success: StorageClass GID range takes precedence over the default GID range set by flags",
 			testFunc: func(t *testing.T) {
 				mockCtl := gomock.NewController(t)
 				mockCloud := mocks.NewMockCloud(mockCtl)
 
 				driver := &Driver{
-					endpoint:     endpoint,
-					cloud:        mockCloud,
-					gidAllocator: NewGidAllocator(),
-					tags:         parseTagsFromStr("cluster-efs"),
+					endpoint:      endpoint,
+					cloud:         mockCloud,
+					gidAllocator:  NewGidAllocator(),
+					defaultGidMin: 3000,
+					defaultGidMax: 3100,
 				}
 
 				req := &csi.CreateVolumeRequest{
@@ -731,9 +966,9 @@ func TestCreateVolume(t *testing.T) {
 					Parameters: map[string]string{
 						ProvisioningMode: "efs-ap",
 						FsId:             fsId,
-						GidMin:           "1000",
-						GidMax:           "2000",
 						DirectoryPerms:   "777",
+						GidMin:           "5000",
+						GidMax:           "5100",
 					},
 				}
 
@@ -741,44 +976,101 @@ func TestCreateVolume(t *testing.T) {
 				accessPoint := &cloud.AccessPoint{
 					AccessPointId: apId,
 					FileSystemId:  fsId,
-					PosixUser: &cloud.PosixUser{
-						Gid: 1000,
-						Uid: 1000,
-					},
 				}
-				accessPoints := []*cloud.AccessPoint{accessPoint}
-				mockCloud.EXPECT().ListAccessPoints(gomock.Eq(ctx), gomock.Any()).Return(accessPoints, nil)
-				mockCloud.EXPECT().CreateAccessPoint(gomock.Eq(ctx), gomock.Any(), gomock.Any(), gomock.Any()).Return(accessPoint, nil)
-
-				res, err := driver.CreateVolume(ctx, req)
+				mockCloud.EXPECT().ListAccessPoints(gomock.Eq(ctx), gomock.Any()).Return(nil, nil)
+				mockCloud.EXPECT().CreateAccessPoint(gomock.Eq(ctx), gomock.Any(), gomock.Any(), gomock.Any()).Return(accessPoint, nil).
+					Do(func(ctx context.Context, clientToken string, accessPointsOptions *cloud.AccessPointOptions, reuseAccessPointName bool) {
+						if accessPointsOptions.Gid != 5000 {
+							t.Fatalf("Gid mismatched. Expected: %v, actual: %v", 5000, accessPointsOptions.Gid)
+						}
+					})
 
+				_, err := driver.CreateVolume(ctx, req)
 				if err != nil {
 					t.Fatalf("CreateVolume failed: %v", err)
 				}
+				mockCtl.Finish()
+			},
+		},
+		{
+			name: "Success: Default GID range is clamped to the maximum GID",
+			testFunc: func(t *testing.T) {
+				mockCtl := gomock.NewController(t)
+				mockCloud := mocks.NewMockCloud(mockCtl)
 
-				if res.Volume == nil {
-					t.Fatal("Volume is nil")
+				driver := &Driver{
+					endpoint:     endpoint,
+					cloud:        mockCloud,
+					gidAllocator: NewGidAllocator(),
+					maxGid:       DefaultGidMin + 1,
 				}
 
-				if res.Volume.VolumeId != volumeId {
-					t.Fatalf("Volume Id mismatched. Expected: %v, Actual: %v", volumeId, res.Volume.VolumeId)
+				req := &csi.CreateVolumeRequest{
+					Name: volumeName,
+					VolumeCapabilities: []*csi.VolumeCapability{
+						stdVolCap,
+					},
+					CapacityRange: &csi.CapacityRange{
+						RequiredBytes: capacityRange,
+					},
+					Parameters: map[string]string{
+						ProvisioningMode: "efs-ap",
+						FsId:             fsId,
+						DirectoryPerms:   "777",
+					},
+				}
+
+				ctx := context.Background()
+				accessPoints := []*cloud.AccessPoint{
+					{
+						AccessPointId: apId,
+						FileSystemId:  fsId,
+						PosixUser: &cloud.PosixUser{
+							Gid: DefaultGidMin,
+						},
+					},
+				}
+				mockCloud.EXPECT().ListAccessPoints(gomock.Eq(ctx), gomock.Any()).Return(accessPoints, nil).Times(2)
+				createdAccessPoint := &cloud.AccessPoint{
+					AccessPointId: apId,
+					FileSystemId:  fsId,
+					PosixUser: &cloud.PosixUser{
+						Gid: DefaultGidMin + 1,
+					},
+				}
+				mockCloud.EXPECT().CreateAccessPoint(gomock.Eq(ctx), gomock.Any(), gomock.Any(), gomock.Any()).Return(createdAccessPoint, nil).
+					Do(func(ctx context.Context, clientToken string, accessPointsOptions *cloud.AccessPointOptions, reuseAccessPointName bool) {
+						if accessPointsOptions.Gid != DefaultGidMin+1 {
+							t.Fatalf("Gid mismatched. Expected: %v, actual: %v", DefaultGidMin+1, accessPointsOptions.Gid)
+						}
+					})
+
+				_, err := driver.CreateVolume(ctx, req)
+				if err != nil {
+					t.Fatalf("CreateVolume failed: %v", err)
+				}
+
+				// Every GID of the clamped range is used
+				req.Name = volumeName + "-2"
+				_, err = driver.CreateVolume(ctx, req)
+				if status.Code(err) != codes.ResourceExhausted {
+					t.Fatalf("Expected ResourceExhausted, got: %v", err)
 				}
 				mockCtl.Finish()
 			},
 		},
 		{
-			name: "Success: reuseAccessPointName is true",
+			name: "Fail: Access point is rolled back when a later step fails",
 			testFunc: func(t *testing.T) {
 				mockCtl := gomock.NewController(t)
 				mockCloud := mocks.NewMockCloud(mockCtl)
 
 				driver := &Driver{
-					endpoint:     endpoint,
-					cloud:        mockCloud,
-					gidAllocator: NewGidAllocator(),
-					tags:         parseTagsFromStr(""),
+					endpoint:        endpoint,
+					cloud:           mockCloud,
+					gidAllocator:    NewGidAllocator(),
+					softDeleteGrace: time.Hour,
 				}
-				pvcNameVal := "test-pvc"
 
 				req := &csi.CreateVolumeRequest{
 					Name: volumeName,
@@ -789,50 +1081,83 @@ func TestCreateVolume(t *testing.T) {
 						RequiredBytes: capacityRange,
 					},
 					Parameters: map[string]string{
-						ProvisioningMode:    "efs-ap",
-						FsId:                fsId,
-						GidMin:              "1000",
-						GidMax:              "2000",
-						DirectoryPerms:      "777",
-						AzName:              "us-east-1a",
-						ReuseAccessPointKey: "true",
-						PvcNameKey:          pvcNameVal,
+						ProvisioningMode: "efs-ap",
+						FsId:             fsId,
+						DirectoryPerms:   "777",
 					},
 				}
 
 				ctx := context.Background()
-
 				accessPoint := &cloud.AccessPoint{
 					AccessPointId: apId,
 					FileSystemId:  fsId,
-					PosixUser: &cloud.PosixUser{
-						Gid: 1000,
-						Uid: 1000,
-					},
 				}
-				accessPoints := []*cloud.AccessPoint{accessPoint}
-				mockCloud.EXPECT().ListAccessPoints(gomock.Eq(ctx), gomock.Any()).Return(accessPoints, nil)
-				mockCloud.EXPECT().CreateAccessPoint(gomock.Eq(ctx), gomock.Eq(get64LenHash(pvcNameVal)), gomock.Any(), gomock.Any()).Return(accessPoint, nil)
+				mockCloud.EXPECT().ListAccessPoints(gomock.Eq(ctx), gomock.Any()).Return(nil, nil)
+				mockCloud.EXPECT().CreateAccessPoint(gomock.Eq(ctx), gomock.Any(), gomock.Any(), gomock.Any()).Return(accessPoint, nil)
+				mockCloud.EXPECT().DescribeAccessPoint(gomock.Eq(ctx), gomock.Eq(apId)).Return(nil, errors.New("DescribeAccessPoint failed"))
+				mockCloud.EXPECT().DeleteAccessPoint(gomock.Any(), gomock.Eq(apId)).Return(nil)
 
-				res, err := driver.CreateVolume(ctx, req)
+				_, err := driver.CreateVolume(ctx, req)
+				if status.Code(err) != codes.Internal {
+					t.Fatalf("Expected Internal, got: %v", err)
+				}
+				if reserved := driver.gidAllocator.getFsState(fsId).reserved; len(reserved) != 0 {
+					t.Fatalf("Expected 0 reserved GIDs, got %v", reserved)
+				}
+				mockCtl.Finish()
+			},
+		},
+		{
+			name: "Fail: Soft deleted access point is not rolled back",
+			testFunc: func(t *testing.T) {
+				mockCtl := gomock.NewController(t)
+				mockCloud := mocks.NewMockCloud(mockCtl)
 
-				if err != nil {
-					t.Fatalf("CreateVolume failed: %v", err)
+				driver := &Driver{
+					endpoint:        endpoint,
+					cloud:           mockCloud,
+					gidAllocator:    NewGidAllocator(),
+					softDeleteGrace: time.Hour,
 				}
 
-				if res.Volume == nil {
-					t.Fatal("Volume is nil")
+				req := &csi.CreateVolumeRequest{
+					Name: volumeName,
+					VolumeCapabilities: []*csi.VolumeCapability{
+						stdVolCap,
+					},
+					CapacityRange: &csi.CapacityRange{
+						RequiredBytes: capacityRange,
+					},
+					Parameters: map[string]string{
+						ProvisioningMode: "efs-ap",
+						FsId:             fsId,
+						DirectoryPerms:   "777",
+					},
 				}
 
-				if res.Volume.VolumeId != volumeId {
-					t.Fatalf("Volume Id mismatched. Expected: %v, Actual: %v", volumeId, res.Volume.VolumeId)
+				ctx := context.Background()
+				accessPoint := &cloud.AccessPoint{
+					AccessPointId: apId,
+					FileSystemId:  fsId,
+					Tags:          map[string]string{DeletedAtTagKey: "2024-01-01T00:00:00Z"},
 				}
+				mockCloud.EXPECT().ListAccessPoints(gomock.Eq(ctx), gomock.Any()).Return(nil, nil)
+				mockCloud.EXPECT().CreateAccessPoint(gomock.Eq(ctx), gomock.Any(), gomock.Any(), gomock.Any()).Return(accessPoint, nil)
+				mockCloud.EXPECT().DescribeAccessPoint(gomock.Eq(ctx), gomock.Eq(apId)).Return(accessPoint, nil)
+				mockCloud.EXPECT().UntagAccessPoint(gomock.Eq(ctx), gomock.Eq(apId), gomock.Any()).Return(errors.New("UntagResource failed"))
 
+				_, err := driver.CreateVolume(ctx, req)
+				if status.Code(err) != codes.Internal {
+					t.Fatalf("Expected Internal, got: %v", err)
+				}
+				if reserved := driver.gidAllocator.getFsState(fsId).reserved; len(reserved) != 1 {
+					t.Fatalf("Expected 1 reserved GIDs, got %v", reserved)
+				}
 				mockCtl.Finish()
 			},
 		},
 		{
-			name: "Success: Normal flow with a valid directory structure set",
+			name: "Success: Normal flow with tags",
 			testFunc: func(t *testing.T) {
 				mockCtl := gomock.NewController(t)
 				mockCloud := mocks.NewMockCloud(mockCtl)
@@ -841,13 +1166,9 @@ func TestCreateVolume(t *testing.T) {
 					endpoint:     endpoint,
 					cloud:        mockCloud,
 					gidAllocator: NewGidAllocator(),
-					tags:         parseTagsFromStr(""),
+					tags:         parseTagsFromStr("cluster:efs"),
 				}
 
-				pvName := "foo"
-				pvcName := "bar"
-				directoryCreated := fmt.Sprintf("/%s/%s", pvName, pvcName)
-
 				req := &csi.CreateVolumeRequest{
 					Name: volumeName,
 					VolumeCapabilities: []*csi.VolumeCapability{
@@ -862,9 +1183,6 @@ func TestCreateVolume(t *testing.T) {
 						GidMin:           "1000",
 						GidMax:           "2000",
 						DirectoryPerms:   "777",
-						SubPathPattern:   "${.PV.name}/${.PVC.name}",
-						PvName:           pvName,
-						PvcName:          pvcName,
 					},
 				}
 
@@ -872,17 +1190,14 @@ func TestCreateVolume(t *testing.T) {
 				accessPoint := &cloud.AccessPoint{
 					AccessPointId: apId,
 					FileSystemId:  fsId,
+					PosixUser: &cloud.PosixUser{
+						Gid: 1000,
+						Uid: 1000,
+					},
 				}
-				mockCloud.EXPECT().ListAccessPoints(gomock.Eq(ctx), gomock.Any()).Return(nil, nil)
-
-				mockCloud.EXPECT().CreateAccessPoint(gomock.Eq(ctx), gomock.Any(), gomock.Any(), gomock.Any()).Return(accessPoint, nil).
-					Do(func(ctx context.Context, clientToken string, accessPointOpts *cloud.AccessPointOptions, reuseAccessPoint bool) {
-						if !verifyPathWhenUUIDIncluded(accessPointOpts.DirectoryPath, directoryCreated) {
-							t.Fatalf("Root directory mismatch. Expected: %v (with UID appended), actual: %v",
-								directoryCreated,
-								accessPointOpts.DirectoryPath)
-						}
-					})
+				accessPoints := []*cloud.AccessPoint{accessPoint}
+				mockCloud.EXPECT().ListAccessPoints(gomock.Eq(ctx), gomock.Any()).Return(accessPoints, nil)
+				mockCloud.EXPECT().CreateAccessPoint(gomock.Eq(ctx), gomock.Any(), gomock.Any(), gomock.Any()).Return(accessPoint, nil)
 
 				res, err := driver.CreateVolume(ctx, req)
 
@@ -897,12 +1212,11 @@ func TestCreateVolume(t *testing.T) {
 				if res.Volume.VolumeId != volumeId {
 					t.Fatalf("Volume Id mismatched. Expected: %v, Actual: %v", volumeId, res.Volume.VolumeId)
 				}
-
 				mockCtl.Finish()
 			},
 		},
 		{
-			name: "Success: Normal flow with a valid directory structure set, using a single element",
+			name: "Success: Normal flow with invalid tags",
 			testFunc: func(t *testing.T) {
 				mockCtl := gomock.NewController(t)
 				mockCloud := mocks.NewMockCloud(mockCtl)
@@ -911,12 +1225,9 @@ func TestCreateVolume(t *testing.T) {
 					endpoint:     endpoint,
 					cloud:        mockCloud,
 					gidAllocator: NewGidAllocator(),
-					tags:         parseTagsFromStr(""),
+					tags:         parseTagsFromStr("cluster-efs"),
 				}
 
-				pvcName := "foo"
-				directoryCreated := fmt.Sprintf("/%s", pvcName)
-
 				req := &csi.CreateVolumeRequest{
 					Name: volumeName,
 					VolumeCapabilities: []*csi.VolumeCapability{
@@ -931,8 +1242,6 @@ func TestCreateVolume(t *testing.T) {
 						GidMin:           "1000",
 						GidMax:           "2000",
 						DirectoryPerms:   "777",
-						SubPathPattern:   "${.PVC.name}",
-						PvcName:          pvcName,
 					},
 				}
 
@@ -940,17 +1249,14 @@ func TestCreateVolume(t *testing.T) {
 				accessPoint := &cloud.AccessPoint{
 					AccessPointId: apId,
 					FileSystemId:  fsId,
+					PosixUser: &cloud.PosixUser{
+						Gid: 1000,
+						Uid: 1000,
+					},
 				}
-				mockCloud.EXPECT().ListAccessPoints(gomock.Eq(ctx), gomock.Any()).Return(nil, nil)
-
-				mockCloud.EXPECT().CreateAccessPoint(gomock.Eq(ctx), gomock.Any(), gomock.Any(), gomock.Any()).Return(accessPoint, nil).
-					Do(func(ctx context.Context, clientToken string, accessPointOpts *cloud.AccessPointOptions, reuseAccessPointName bool) {
-						if !verifyPathWhenUUIDIncluded(accessPointOpts.DirectoryPath, directoryCreated) {
-							t.Fatalf("Root directory mismatch. Expected: %v (with UID appended), actual: %v",
-								directoryCreated,
-								accessPointOpts.DirectoryPath)
-						}
-					})
+				accessPoints := []*cloud.AccessPoint{accessPoint}
+				mockCloud.EXPECT().ListAccessPoints(gomock.Eq(ctx), gomock.Any()).Return(accessPoints, nil)
+				mockCloud.EXPECT().CreateAccessPoint(gomock.Eq(ctx), gomock.Any(), gomock.Any(), gomock.Any()).Return(accessPoint, nil)
 
 				res, err := driver.CreateVolume(ctx, req)
 
@@ -965,12 +1271,11 @@ func TestCreateVolume(t *testing.T) {
 				if res.Volume.VolumeId != volumeId {
 					t.Fatalf("Volume Id mismatched. Expected: %v, Actual: %v", volumeId, res.Volume.VolumeId)
 				}
-
 				mockCtl.Finish()
 			},
 		},
 		{
-			name: "Success: Normal flow with a valid directory structure set, and a basePath",
+			name: "Success: reuseAccessPointName is true",
 			testFunc: func(t *testing.T) {
 				mockCtl := gomock.NewController(t)
 				mockCloud := mocks.NewMockCloud(mockCtl)
@@ -981,10 +1286,7 @@ func TestCreateVolume(t *testing.T) {
 					gidAllocator: NewGidAllocator(),
 					tags:         parseTagsFromStr(""),
 				}
-
-				pvcName := "foo"
-				basePath := "bash"
-				directoryCreated := fmt.Sprintf("/%s/%s", basePath, pvcName)
+				pvcNameVal := "test-pvc"
 
 				req := &csi.CreateVolumeRequest{
 					Name: volumeName,
@@ -995,33 +1297,37 @@ func TestCreateVolume(t *testing.T) {
 						RequiredBytes: capacityRange,
 					},
 					Parameters: map[string]string{
-						ProvisioningMode:      "efs-ap",
-						FsId:                  fsId,
-						GidMin:                "1000",
-						GidMax:                "2000",
-						DirectoryPerms:        "777",
-						SubPathPattern:        "${.PVC.name}",
-						BasePath:              basePath,
-						EnsureUniqueDirectory: "true",
-						PvcName:               pvcName,
+						ProvisioningMode:    "efs-ap",
+						FsId:                fsId,
+						GidMin:              "1000",
+						GidMax:              "2000",
+						DirectoryPerms:      "777",
+						AzName:              "us-east-1a",
+						ReuseAccessPointKey: "true",
+						PvcNameKey:          pvcNameVal,
 					},
 				}
 
 				ctx := context.Background()
+
 				accessPoint := &cloud.AccessPoint{
 					AccessPointId: apId,
 					FileSystemId:  fsId,
+					PosixUser: &cloud.PosixUser{
+						Gid: 1000,
+						Uid: 1000,
+					},
 				}
-				mockCloud.EXPECT().ListAccessPoints(gomock.Eq(ctx), gomock.Any()).Return(nil, nil)
-
-				mockCloud.EXPECT().CreateAccessPoint(gomock.Eq(ctx), gomock.Any(), gomock.Any(), gomock.Any()).Return(accessPoint, nil).
-					Do(func(ctx context.Context, clientToken string, accessPointOpts *cloud.AccessPointOptions, reuseAccessPointName bool) {
-						if !verifyPathWhenUUIDIncluded(accessPointOpts.DirectoryPath, directoryCreated) {
-							t.Fatalf("Root directory mismatch. Expected: %v (with UID appended), actual: %v",
-								directoryCreated,
-								accessPointOpts.DirectoryPath)
-						}
-					})
+				accessPoints := []*cloud.AccessPoint{accessPoint}
+				mockCloud.EXPECT().ListAccessPoints(gomock.Eq(ctx), gomock.Any()).Return(accessPoints, nil)
+				mountTarget := &cloud.MountTarget{
+					AZName:        "us-east-1a",
+					AZId:          "mock-AZ-id",
+					MountTargetId: "fsmt-abcd1234",
+					IPAddress:     "127.0.0.1",
+				}
+				mockCloud.EXPECT().DescribeMountTargets(gomock.Eq(ctx), gomock.Eq(fsId), gomock.Eq("us-east-1a")).Return(mountTarget, nil)
+				mockCloud.EXPECT().CreateAccessPoint(gomock.Eq(ctx), gomock.Eq(get64LenHash(pvcNameVal)), gomock.Any(), gomock.Any()).Return(accessPoint, nil)
 
 				res, err := driver.CreateVolume(ctx, req)
 
@@ -1037,11 +1343,16 @@ func TestCreateVolume(t *testing.T) {
 					t.Fatalf("Volume Id mismatched. Expected: %v, Actual: %v", volumeId, res.Volume.VolumeId)
 				}
 
+				// The reused access point keeps its GID, the one allocated for the request is released
+				if _, pending := driver.gidAllocator.trackedGids(fsId); len(pending) != 0 {
+					t.Fatalf("Expected the allocated GID to be released, pending GIDs: %v", pending)
+				}
+
 				mockCtl.Finish()
 			},
 		},
 		{
-			name: "Success: Normal flow with a valid directory structure set, and a basePath, and uniqueness guarantees turned off",
+			name: "Success: Normal flow with a valid directory structure set",
 			testFunc: func(t *testing.T) {
 				mockCtl := gomock.NewController(t)
 				mockCloud := mocks.NewMockCloud(mockCtl)
@@ -1053,9 +1364,9 @@ func TestCreateVolume(t *testing.T) {
 					tags:         parseTagsFromStr(""),
 				}
 
-				pvcName := "foo"
-				basePath := "bash"
-				directoryCreated := fmt.Sprintf("/%s/%s", basePath, pvcName)
+				pvName := "foo"
+				pvcName := "bar"
+				directoryCreated := fmt.Sprintf("/%s/%s", pvName, pvcName)
 
 				req := &csi.CreateVolumeRequest{
 					Name: volumeName,
@@ -1066,15 +1377,14 @@ func TestCreateVolume(t *testing.T) {
 						RequiredBytes: capacityRange,
 					},
 					Parameters: map[string]string{
-						ProvisioningMode:      "efs-ap",
-						FsId:                  fsId,
-						GidMin:                "1000",
-						GidMax:                "2000",
-						DirectoryPerms:        "777",
-						SubPathPattern:        "${.PVC.name}",
-						BasePath:              basePath,
-						EnsureUniqueDirectory: "false",
-						PvcName:               pvcName,
+						ProvisioningMode: "efs-ap",
+						FsId:             fsId,
+						GidMin:           "1000",
+						GidMax:           "2000",
+						DirectoryPerms:   "777",
+						SubPathPattern:   "${.PV.name}/${.PVC.name}",
+						PvName:           pvName,
+						PvcName:          pvcName,
 					},
 				}
 
@@ -1086,9 +1396,9 @@ func TestCreateVolume(t *testing.T) {
 				mockCloud.EXPECT().ListAccessPoints(gomock.Eq(ctx), gomock.Any()).Return(nil, nil)
 
 				mockCloud.EXPECT().CreateAccessPoint(gomock.Eq(ctx), gomock.Any(), gomock.Any(), gomock.Any()).Return(accessPoint, nil).
-					Do(func(ctx context.Context, clientToken string, accessPointOpts *cloud.AccessPointOptions, reuseAccessPointName bool) {
-						if accessPointOpts.DirectoryPath != directoryCreated {
-							t.Fatalf("Root directory mismatch. Expected: %v, actual: %v",
+					Do(func(ctx context.Context, clientToken string, accessPointOpts *cloud.AccessPointOptions, reuseAccessPoint bool) {
+						if !verifyPathWhenUUIDIncluded(accessPointOpts.DirectoryPath, directoryCreated) {
+							t.Fatalf("Root directory mismatch. Expected: %v (with UID appended), actual: %v",
 								directoryCreated,
 								accessPointOpts.DirectoryPath)
 						}
@@ -1112,8 +1422,7 @@ func TestCreateVolume(t *testing.T) {
 			},
 		},
 		{
-			name: "Success: Normal flow with a valid directory structure set, but ensuring uniqueness is set incorrectly, so default of true is used." +
-				"",
+			name: "Success: Root directory named with the injected UUID source",
 			testFunc: func(t *testing.T) {
 				mockCtl := gomock.NewController(t)
 				mockCloud := mocks.NewMockCloud(mockCtl)
@@ -1123,11 +1432,9 @@ func TestCreateVolume(t *testing.T) {
 					cloud:        mockCloud,
 					gidAllocator: NewGidAllocator(),
 					tags:         parseTagsFromStr(""),
+					newUUID:      func() string { return "3d2c6a36-9b0c-4c59-8d2f-6d3d1c8e1a01" },
 				}
 
-				pvcName := "foo"
-				directoryCreated := fmt.Sprintf("/%s", pvcName)
-
 				req := &csi.CreateVolumeRequest{
 					Name: volumeName,
 					VolumeCapabilities: []*csi.VolumeCapability{
@@ -1137,14 +1444,135 @@ func TestCreateVolume(t *testing.T) {
 						RequiredBytes: capacityRange,
 					},
 					Parameters: map[string]string{
-						ProvisioningMode:      "efs-ap",
-						FsId:                  fsId,
-						GidMin:                "1000",
-						GidMax:                "2000",
-						DirectoryPerms:        "777",
-						SubPathPattern:        "${.PVC.name}",
-						EnsureUniqueDirectory: "banana",
-						PvcName:               pvcName,
+						ProvisioningMode: "efs-ap",
+						FsId:             fsId,
+						GidMin:           "1000",
+						GidMax:           "2000",
+						DirectoryPerms:   "777",
+						SubPathPattern:   "${.PV.name}/${.PVC.name}",
+						PvName:           "foo",
+						PvcName:          "bar",
+					},
+				}
+
+				ctx := context.Background()
+				accessPoint := &cloud.AccessPoint{
+					AccessPointId: apId,
+					FileSystemId:  fsId,
+				}
+				mockCloud.EXPECT().ListAccessPoints(gomock.Eq(ctx), gomock.Any()).Return(nil, nil)
+				mockCloud.EXPECT().CreateAccessPoint(gomock.Eq(ctx), gomock.Any(), gomock.Any(), gomock.Any()).Return(accessPoint, nil).
+					Do(func(ctx context.Context, clientToken string, accessPointOpts *cloud.AccessPointOptions, reuseAccessPoint bool) {
+						expected := "/foo/bar-3d2c6a36-9b0c-4c59-8d2f-6d3d1c8e1a01"
+						if accessPointOpts.DirectoryPath != expected {
+							t.Fatalf("Root directory mismatched. Expected: %v, Actual: %v", expected, accessPointOpts.DirectoryPath)
+						}
+					})
+
+				if _, err := driver.CreateVolume(ctx, req); err != nil {
+					t.Fatalf("CreateVolume failed: %v", err)
+				}
+				mockCtl.Finish()
+			},
+		},
+		{
+			name: "Success: Short root directory suffix checked for collisions with a fixed uid and gid",
+			testFunc: func(t *testing.T) {
+				mockCtl := gomock.NewController(t)
+				mockCloud := mocks.NewMockCloud(mockCtl)
+
+				uuids := []string{"3d2c6a36-9b0c-4c59-8d2f-6d3d1c8e1a01", "7f1e0b42-5a6d-4e8b-9c3f-2b1a0d9e8c7f"}
+				i := 0
+				driver := &Driver{
+					endpoint:     endpoint,
+					cloud:        mockCloud,
+					gidAllocator: NewGidAllocator(),
+					tags:         parseTagsFromStr(""),
+					newUUID: func() string {
+						id := uuids[i%len(uuids)]
+						i++
+						return id
+					},
+				}
+
+				req := &csi.CreateVolumeRequest{
+					Name: volumeName,
+					VolumeCapabilities: []*csi.VolumeCapability{
+						stdVolCap,
+					},
+					CapacityRange: &csi.CapacityRange{
+						RequiredBytes: capacityRange,
+					},
+					Parameters: map[string]string{
+						ProvisioningMode: "efs-ap",
+						FsId:             fsId,
+						Uid:              "1000",
+						Gid:              "1000",
+						DirectoryPerms:   "777",
+						SubPathPattern:   "${.PVC.name}",
+						RootDirSuffix:    RootDirSuffixShort,
+						PvcName:          "bar",
+					},
+				}
+
+				ctx := context.Background()
+				accessPoint := &cloud.AccessPoint{
+					AccessPointId: apId,
+					FileSystemId:  fsId,
+				}
+				fileSystem := &cloud.FileSystem{
+					FileSystemId: fsId,
+				}
+				mockCloud.EXPECT().DescribeFileSystem(gomock.Eq(ctx), gomock.Any()).Return(fileSystem, nil)
+				mockCloud.EXPECT().ListAccessPoints(gomock.Eq(ctx), gomock.Eq(fsId)).Return([]*cloud.AccessPoint{
+					{AccessPointId: "fsap-abcd1234xyz000", FileSystemId: fsId, AccessPointRootDir: "/bar-3d2c6a36"},
+				}, nil)
+				mockCloud.EXPECT().CreateAccessPoint(gomock.Eq(ctx), gomock.Any(), gomock.Any(), gomock.Any()).Return(accessPoint, nil).
+					Do(func(ctx context.Context, clientToken string, accessPointOpts *cloud.AccessPointOptions, reuseAccessPoint bool) {
+						expected := "/bar-7f1e0b42"
+						if accessPointOpts.DirectoryPath != expected {
+							t.Fatalf("Root directory mismatched. Expected: %v, Actual: %v", expected, accessPointOpts.DirectoryPath)
+						}
+					})
+
+				if _, err := driver.CreateVolume(ctx, req); err != nil {
+					t.Fatalf("CreateVolume failed: %v", err)
+				}
+				mockCtl.Finish()
+			},
+		},
+		{
+			name: "Success: Normal flow with a valid directory structure set, using a single element",
+			testFunc: func(t *testing.T) {
+				mockCtl := gomock.NewController(t)
+				mockCloud := mocks.NewMockCloud(mockCtl)
+
+				driver := &Driver{
+					endpoint:     endpoint,
+					cloud:        mockCloud,
+					gidAllocator: NewGidAllocator(),
+					tags:         parseTagsFromStr(""),
+				}
+
+				pvcName := "foo"
+				directoryCreated := fmt.Sprintf("/%s", pvcName)
+
+				req := &csi.CreateVolumeRequest{
+					Name: volumeName,
+					VolumeCapabilities: []*csi.VolumeCapability{
+						stdVolCap,
+					},
+					CapacityRange: &csi.CapacityRange{
+						RequiredBytes: capacityRange,
+					},
+					Parameters: map[string]string{
+						ProvisioningMode: "efs-ap",
+						FsId:             fsId,
+						GidMin:           "1000",
+						GidMax:           "2000",
+						DirectoryPerms:   "777",
+						SubPathPattern:   "${.PVC.name}",
+						PvcName:          pvcName,
 					},
 				}
 
@@ -1182,7 +1610,7 @@ func TestCreateVolume(t *testing.T) {
 			},
 		},
 		{
-			name: "Success: Normal flow with an empty subPath Pattern, no basePath and uniqueness guarantees turned off",
+			name: "Success: Normal flow with a valid directory structure set, and a basePath",
 			testFunc: func(t *testing.T) {
 				mockCtl := gomock.NewController(t)
 				mockCloud := mocks.NewMockCloud(mockCtl)
@@ -1194,6 +1622,10 @@ func TestCreateVolume(t *testing.T) {
 					tags:         parseTagsFromStr(""),
 				}
 
+				pvcName := "foo"
+				basePath := "/bash"
+				directoryCreated := fmt.Sprintf("%s/%s", basePath, pvcName)
+
 				req := &csi.CreateVolumeRequest{
 					Name: volumeName,
 					VolumeCapabilities: []*csi.VolumeCapability{
@@ -1208,8 +1640,10 @@ func TestCreateVolume(t *testing.T) {
 						GidMin:                "1000",
 						GidMax:                "2000",
 						DirectoryPerms:        "777",
-						SubPathPattern:        "",
-						EnsureUniqueDirectory: "false",
+						SubPathPattern:        "${.PVC.name}",
+						BasePath:              basePath,
+						EnsureUniqueDirectory: "true",
+						PvcName:               pvcName,
 					},
 				}
 
@@ -1222,9 +1656,9 @@ func TestCreateVolume(t *testing.T) {
 
 				mockCloud.EXPECT().CreateAccessPoint(gomock.Eq(ctx), gomock.Any(), gomock.Any(), gomock.Any()).Return(accessPoint, nil).
 					Do(func(ctx context.Context, clientToken string, accessPointOpts *cloud.AccessPointOptions, reuseAccessPointName bool) {
-						if accessPointOpts.DirectoryPath != "/" {
-							t.Fatalf("Root directory mismatch. Expected: %v, actual: %v",
-								"/",
+						if !verifyPathWhenUUIDIncluded(accessPointOpts.DirectoryPath, directoryCreated) {
+							t.Fatalf("Root directory mismatch. Expected: %v (with UID appended), actual: %v",
+								directoryCreated,
 								accessPointOpts.DirectoryPath)
 						}
 					})
@@ -1247,7 +1681,7 @@ func TestCreateVolume(t *testing.T) {
 			},
 		},
 		{
-			name: "Success: Normal flow with an empty subPath Pattern, and basePath set to /",
+			name: "Success: Normal flow with a valid directory structure set, and a basePath, and uniqueness guarantees turned off",
 			testFunc: func(t *testing.T) {
 				mockCtl := gomock.NewController(t)
 				mockCloud := mocks.NewMockCloud(mockCtl)
@@ -1259,6 +1693,10 @@ func TestCreateVolume(t *testing.T) {
 					tags:         parseTagsFromStr(""),
 				}
 
+				pvcName := "foo"
+				basePath := "/bash"
+				directoryCreated := fmt.Sprintf("%s/%s", basePath, pvcName)
+
 				req := &csi.CreateVolumeRequest{
 					Name: volumeName,
 					VolumeCapabilities: []*csi.VolumeCapability{
@@ -1273,9 +1711,10 @@ func TestCreateVolume(t *testing.T) {
 						GidMin:                "1000",
 						GidMax:                "2000",
 						DirectoryPerms:        "777",
-						SubPathPattern:        "",
-						BasePath:              "/",
+						SubPathPattern:        "${.PVC.name}",
+						BasePath:              basePath,
 						EnsureUniqueDirectory: "false",
+						PvcName:               pvcName,
 					},
 				}
 
@@ -1288,9 +1727,9 @@ func TestCreateVolume(t *testing.T) {
 
 				mockCloud.EXPECT().CreateAccessPoint(gomock.Eq(ctx), gomock.Any(), gomock.Any(), gomock.Any()).Return(accessPoint, nil).
 					Do(func(ctx context.Context, clientToken string, accessPointOpts *cloud.AccessPointOptions, reuseAccessPointName bool) {
-						if accessPointOpts.DirectoryPath != "/" {
+						if accessPointOpts.DirectoryPath != directoryCreated {
 							t.Fatalf("Root directory mismatch. Expected: %v, actual: %v",
-								"/",
+								directoryCreated,
 								accessPointOpts.DirectoryPath)
 						}
 					})
@@ -1313,7 +1752,8 @@ func TestCreateVolume(t *testing.T) {
 			},
 		},
 		{
-			name: "Success: Normal flow with a valid directory structure set, using repeated elements (uses PVC Name in subpath pattern multiple times)",
+			name: "Success: Normal flow with a valid directory structure set, but ensuring uniqueness is set incorrectly, so default of true is used." +
+				"",
 			testFunc: func(t *testing.T) {
 				mockCtl := gomock.NewController(t)
 				mockCloud := mocks.NewMockCloud(mockCtl)
@@ -1326,7 +1766,7 @@ func TestCreateVolume(t *testing.T) {
 				}
 
 				pvcName := "foo"
-				directoryCreated := fmt.Sprintf("/%s/%s", pvcName, pvcName)
+				directoryCreated := fmt.Sprintf("/%s", pvcName)
 
 				req := &csi.CreateVolumeRequest{
 					Name: volumeName,
@@ -1337,13 +1777,14 @@ func TestCreateVolume(t *testing.T) {
 						RequiredBytes: capacityRange,
 					},
 					Parameters: map[string]string{
-						ProvisioningMode: "efs-ap",
-						FsId:             fsId,
-						GidMin:           "1000",
-						GidMax:           "2000",
-						DirectoryPerms:   "777",
-						SubPathPattern:   "${.PVC.name}/${.PVC.name}",
-						PvcName:          pvcName,
+						ProvisioningMode:      "efs-ap",
+						FsId:                  fsId,
+						GidMin:                "1000",
+						GidMax:                "2000",
+						DirectoryPerms:        "777",
+						SubPathPattern:        "${.PVC.name}",
+						EnsureUniqueDirectory: "banana",
+						PvcName:               pvcName,
 					},
 				}
 
@@ -1381,7 +1822,7 @@ func TestCreateVolume(t *testing.T) {
 			},
 		},
 		{
-			name: "Fail: Volume name missing",
+			name: "Success: Normal flow with an empty subPath Pattern, no basePath and uniqueness guarantees turned off",
 			testFunc: func(t *testing.T) {
 				mockCtl := gomock.NewController(t)
 				mockCloud := mocks.NewMockCloud(mockCtl)
@@ -1390,55 +1831,63 @@ func TestCreateVolume(t *testing.T) {
 					endpoint:     endpoint,
 					cloud:        mockCloud,
 					gidAllocator: NewGidAllocator(),
+					tags:         parseTagsFromStr(""),
 				}
 
 				req := &csi.CreateVolumeRequest{
+					Name: volumeName,
+					VolumeCapabilities: []*csi.VolumeCapability{
+						stdVolCap,
+					},
+					CapacityRange: &csi.CapacityRange{
+						RequiredBytes: capacityRange,
+					},
 					Parameters: map[string]string{
-						ProvisioningMode: "efs-ap",
-						FsId:             fsId,
-						DirectoryPerms:   "777",
+						ProvisioningMode:      "efs-ap",
+						FsId:                  fsId,
+						GidMin:                "1000",
+						GidMax:                "2000",
+						DirectoryPerms:        "777",
+						SubPathPattern:        "",
+						EnsureUniqueDirectory: "false",
 					},
 				}
 
 				ctx := context.Background()
-				_, err := driver.CreateVolume(ctx, req)
-				if err == nil {
-					t.Fatal("CreateVolume did not fail")
+				accessPoint := &cloud.AccessPoint{
+					AccessPointId: apId,
+					FileSystemId:  fsId,
 				}
-				mockCtl.Finish()
-			},
-		},
-		{
-			name: "Fail: Capacity Range missing",
-			testFunc: func(t *testing.T) {
-				mockCtl := gomock.NewController(t)
-				mockCloud := mocks.NewMockCloud(mockCtl)
+				mockCloud.EXPECT().ListAccessPoints(gomock.Eq(ctx), gomock.Any()).Return(nil, nil)
 
-				driver := &Driver{
-					endpoint:     endpoint,
-					cloud:        mockCloud,
-					gidAllocator: NewGidAllocator(),
+				mockCloud.EXPECT().CreateAccessPoint(gomock.Eq(ctx), gomock.Any(), gomock.Any(), gomock.Any()).Return(accessPoint, nil).
+					Do(func(ctx context.Context, clientToken string, accessPointOpts *cloud.AccessPointOptions, reuseAccessPointName bool) {
+						if accessPointOpts.DirectoryPath != "/" {
+							t.Fatalf("Root directory mismatch. Expected: %v, actual: %v",
+								"/",
+								accessPointOpts.DirectoryPath)
+						}
+					})
+
+				res, err := driver.CreateVolume(ctx, req)
+
+				if err != nil {
+					t.Fatalf("CreateVolume failed: %v", err)
 				}
 
-				req := &csi.CreateVolumeRequest{
-					Name: volumeName,
-					Parameters: map[string]string{
-						ProvisioningMode: "efs-ap",
-						FsId:             fsId,
-						DirectoryPerms:   "777",
-					},
+				if res.Volume == nil {
+					t.Fatal("Volume is nil")
 				}
 
-				ctx := context.Background()
-				_, err := driver.CreateVolume(ctx, req)
-				if err == nil {
-					t.Fatal("CreateVolume did not fail")
+				if res.Volume.VolumeId != volumeId {
+					t.Fatalf("Volume Id mismatched. Expected: %v, Actual: %v", volumeId, res.Volume.VolumeId)
 				}
+
 				mockCtl.Finish()
 			},
 		},
 		{
-			name: "Fail: Volume capability Missing",
+			name: "Success: Normal flow with an empty subPath Pattern, and basePath set to /",
 			testFunc: func(t *testing.T) {
 				mockCtl := gomock.NewController(t)
 				mockCloud := mocks.NewMockCloud(mockCtl)
@@ -1447,72 +1896,64 @@ func TestCreateVolume(t *testing.T) {
 					endpoint:     endpoint,
 					cloud:        mockCloud,
 					gidAllocator: NewGidAllocator(),
+					tags:         parseTagsFromStr(""),
 				}
 
 				req := &csi.CreateVolumeRequest{
 					Name: volumeName,
+					VolumeCapabilities: []*csi.VolumeCapability{
+						stdVolCap,
+					},
 					CapacityRange: &csi.CapacityRange{
 						RequiredBytes: capacityRange,
 					},
 					Parameters: map[string]string{
-						ProvisioningMode: "efs-ap",
-						FsId:             fsId,
-						DirectoryPerms:   "777",
+						ProvisioningMode:      "efs-ap",
+						FsId:                  fsId,
+						GidMin:                "1000",
+						GidMax:                "2000",
+						DirectoryPerms:        "777",
+						SubPathPattern:        "",
+						BasePath:              "/",
+						EnsureUniqueDirectory: "false",
 					},
 				}
 
 				ctx := context.Background()
-				_, err := driver.CreateVolume(ctx, req)
-				if err == nil {
-					t.Fatal("CreateVolume did not fail")
+				accessPoint := &cloud.AccessPoint{
+					AccessPointId: apId,
+					FileSystemId:  fsId,
 				}
-				mockCtl.Finish()
-			},
-		},
-		{
-			name: "Fail: Volume capability Not Supported",
-			testFunc: func(t *testing.T) {
-				mockCtl := gomock.NewController(t)
-				mockCloud := mocks.NewMockCloud(mockCtl)
+				mockCloud.EXPECT().ListAccessPoints(gomock.Eq(ctx), gomock.Any()).Return(nil, nil)
 
-				driver := &Driver{
-					endpoint:     endpoint,
-					cloud:        mockCloud,
-					gidAllocator: NewGidAllocator(),
+				mockCloud.EXPECT().CreateAccessPoint(gomock.Eq(ctx), gomock.Any(), gomock.Any(), gomock.Any()).Return(accessPoint, nil).
+					Do(func(ctx context.Context, clientToken string, accessPointOpts *cloud.AccessPointOptions, reuseAccessPointName bool) {
+						if accessPointOpts.DirectoryPath != "/" {
+							t.Fatalf("Root directory mismatch. Expected: %v, actual: %v",
+								"/",
+								accessPointOpts.DirectoryPath)
+						}
+					})
+
+				res, err := driver.CreateVolume(ctx, req)
+
+				if err != nil {
+					t.Fatalf("CreateVolume failed: %v", err)
 				}
 
-				req := &csi.CreateVolumeRequest{
-					Name: volumeName,
-					CapacityRange: &csi.CapacityRange{
-						RequiredBytes: capacityRange,
-					},
-					VolumeCapabilities: []*csi.VolumeCapability{
-						{
-							AccessType: &csi.VolumeCapability_Mount{
-								Mount: &csi.VolumeCapability_MountVolume{},
-							},
-							AccessMode: &csi.VolumeCapability_AccessMode{
-								Mode: csi.VolumeCapability_AccessMode_SINGLE_NODE_READER_ONLY,
-							},
-						},
-					},
-					Parameters: map[string]string{
-						ProvisioningMode: "efs-ap",
-						FsId:             fsId,
-						DirectoryPerms:   "777",
-					},
+				if res.Volume == nil {
+					t.Fatal("Volume is nil")
 				}
 
-				ctx := context.Background()
-				_, err := driver.CreateVolume(ctx, req)
-				if err == nil {
-					t.Fatal("CreateVolume did not fail")
+				if res.Volume.VolumeId != volumeId {
+					t.Fatalf("Volume Id mismatched. Expected: %v, Actual: %v", volumeId, res.Volume.VolumeId)
 				}
+
 				mockCtl.Finish()
 			},
 		},
 		{
-			name: "Fail: Volume fsType not supported",
+			name: "Success: Normal flow with a valid directory structure set, using repeated elements (uses PVC Name in subpath pattern multiple times)",
 			testFunc: func(t *testing.T) {
 				mockCtl := gomock.NewController(t)
 				mockCloud := mocks.NewMockCloud(mockCtl)
@@ -1524,19 +1965,13 @@ func TestCreateVolume(t *testing.T) {
 					tags:         parseTagsFromStr(""),
 				}
 
+				pvcName := "foo"
+				directoryCreated := fmt.Sprintf("/%s/%s", pvcName, pvcName)
+
 				req := &csi.CreateVolumeRequest{
 					Name: volumeName,
 					VolumeCapabilities: []*csi.VolumeCapability{
-						{
-							AccessType: &csi.VolumeCapability_Mount{
-								Mount: &csi.VolumeCapability_MountVolume{
-									FsType: "abc",
-								},
-							},
-							AccessMode: &csi.VolumeCapability_AccessMode{
-								Mode: csi.VolumeCapability_AccessMode_MULTI_NODE_MULTI_WRITER,
-							},
-						},
+						stdVolCap,
 					},
 					CapacityRange: &csi.CapacityRange{
 						RequiredBytes: capacityRange,
@@ -1547,20 +1982,46 @@ func TestCreateVolume(t *testing.T) {
 						GidMin:           "1000",
 						GidMax:           "2000",
 						DirectoryPerms:   "777",
-						AzName:           "us-east-1a",
+						SubPathPattern:   "${.PVC.name}/${.PVC.name}",
+						PvcName:          pvcName,
 					},
 				}
 
 				ctx := context.Background()
-				_, err := driver.CreateVolume(ctx, req)
-				if err == nil {
-					t.Fatal("CreateVolume did not fail")
+				accessPoint := &cloud.AccessPoint{
+					AccessPointId: apId,
+					FileSystemId:  fsId,
+				}
+				mockCloud.EXPECT().ListAccessPoints(gomock.Eq(ctx), gomock.Any()).Return(nil, nil)
+
+				mockCloud.EXPECT().CreateAccessPoint(gomock.Eq(ctx), gomock.Any(), gomock.Any(), gomock.Any()).Return(accessPoint, nil).
+					Do(func(ctx context.Context, clientToken string, accessPointOpts *cloud.AccessPointOptions, reuseAccessPointName bool) {
+						if !verifyPathWhenUUIDIncluded(accessPointOpts.DirectoryPath, directoryCreated) {
+							t.Fatalf("Root directory mismatch. Expected: %v (with UID appended), actual: %v",
+								directoryCreated,
+								accessPointOpts.DirectoryPath)
+						}
+					})
+
+				res, err := driver.CreateVolume(ctx, req)
+
+				if err != nil {
+					t.Fatalf("CreateVolume failed: %v", err)
+				}
+
+				if res.Volume == nil {
+					t.Fatal("Volume is nil")
+				}
+
+				if res.Volume.VolumeId != volumeId {
+					t.Fatalf("Volume Id mismatched. Expected: %v, Actual: %v", volumeId, res.Volume.VolumeId)
 				}
+
 				mockCtl.Finish()
 			},
 		},
 		{
-			name: "Fail: AccessType is block",
+			name: "Success: Provision file system with provisioned throughput",
 			testFunc: func(t *testing.T) {
 				mockCtl := gomock.NewController(t)
 				mockCloud := mocks.NewMockCloud(mockCtl)
@@ -1573,36 +2034,54 @@ func TestCreateVolume(t *testing.T) {
 
 				req := &csi.CreateVolumeRequest{
 					Name: volumeName,
+					VolumeCapabilities: []*csi.VolumeCapability{
+						stdVolCap,
+					},
 					CapacityRange: &csi.CapacityRange{
 						RequiredBytes: capacityRange,
 					},
-					VolumeCapabilities: []*csi.VolumeCapability{
-						{
-							AccessType: &csi.VolumeCapability_Block{
-								Block: &csi.VolumeCapability_BlockVolume{},
-							},
-							AccessMode: &csi.VolumeCapability_AccessMode{
-								Mode: csi.VolumeCapability_AccessMode_MULTI_NODE_MULTI_WRITER,
-							},
-						},
-					},
 					Parameters: map[string]string{
-						ProvisioningMode: "efs-ap",
-						FsId:             fsId,
-						DirectoryPerms:   "777",
+						ProvisioningMode:      FileSystemMode,
+						ThroughputMode:        "provisioned",
+						ProvisionedThroughput: "128",
 					},
 				}
 
 				ctx := context.Background()
-				_, err := driver.CreateVolume(ctx, req)
-				if err == nil {
-					t.Fatal("CreateVolume did not fail")
+				fileSystem := &cloud.FileSystem{
+					FileSystemId: fsId,
+				}
+				mockCloud.EXPECT().CreateFileSystem(gomock.Eq(ctx), gomock.Eq(volumeName), gomock.Any()).Return(fileSystem, nil).
+					Do(func(ctx context.Context, clientToken string, fileSystemOpts *cloud.FileSystemOptions) {
+						if fileSystemOpts.ThroughputMode != "provisioned" {
+							t.Fatalf("ThroughputMode mismatched. Expected: %v, actual: %v", "provisioned", fileSystemOpts.ThroughputMode)
+						}
+						if fileSystemOpts.ProvisionedThroughputInMibps != 128 {
+							t.Fatalf("ProvisionedThroughputInMibps mismatched. Expected: %v, actual: %v", 128, fileSystemOpts.ProvisionedThroughputInMibps)
+						}
+						if fileSystemOpts.Tags[DefaultTagKey] != DefaultTagValue {
+							t.Fatalf("Default tag missing from file system tags: %v", fileSystemOpts.Tags)
+						}
+					})
+
+				res, err := driver.CreateVolume(ctx, req)
+
+				if err != nil {
+					t.Fatalf("CreateVolume failed: %v", err)
+				}
+
+				if res.Volume == nil {
+					t.Fatal("Volume is nil")
+				}
+
+				if res.Volume.VolumeId != fsId {
+					t.Fatalf("Volume Id mismatched. Expected: %v, Actual: %v", fsId, res.Volume.VolumeId)
 				}
 				mockCtl.Finish()
 			},
 		},
 		{
-			name: "Fail: Provisioning Mode Not Supported",
+			name: "Fail: Provision file system with invalid throughput parameters",
 			testFunc: func(t *testing.T) {
 				mockCtl := gomock.NewController(t)
 				mockCloud := mocks.NewMockCloud(mockCtl)
@@ -1615,16 +2094,16 @@ func TestCreateVolume(t *testing.T) {
 
 				req := &csi.CreateVolumeRequest{
 					Name: volumeName,
-					CapacityRange: &csi.CapacityRange{
-						RequiredBytes: capacityRange,
-					},
 					VolumeCapabilities: []*csi.VolumeCapability{
 						stdVolCap,
 					},
+					CapacityRange: &csi.CapacityRange{
+						RequiredBytes: capacityRange,
+					},
 					Parameters: map[string]string{
-						ProvisioningMode: "efs-fs",
-						FsId:             fsId,
-						DirectoryPerms:   "777",
+						ProvisioningMode:      FileSystemMode,
+						ThroughputMode:        "bursting",
+						ProvisionedThroughput: "128",
 					},
 				}
 
@@ -1633,11 +2112,14 @@ func TestCreateVolume(t *testing.T) {
 				if err == nil {
 					t.Fatal("CreateVolume did not fail")
 				}
+				if status.Code(err) != codes.InvalidArgument {
+					t.Fatalf("Expected error code %v, got: %v", codes.InvalidArgument, status.Code(err))
+				}
 				mockCtl.Finish()
 			},
 		},
 		{
-			name: "Fail: Missing Provisioning Mode parameter",
+			name: "Success: useIam is recorded in the volume context",
 			testFunc: func(t *testing.T) {
 				mockCtl := gomock.NewController(t)
 				mockCloud := mocks.NewMockCloud(mockCtl)
@@ -1650,28 +2132,46 @@ func TestCreateVolume(t *testing.T) {
 
 				req := &csi.CreateVolumeRequest{
 					Name: volumeName,
-					CapacityRange: &csi.CapacityRange{
-						RequiredBytes: capacityRange,
-					},
 					VolumeCapabilities: []*csi.VolumeCapability{
 						stdVolCap,
 					},
+					CapacityRange: &csi.CapacityRange{
+						RequiredBytes: capacityRange,
+					},
 					Parameters: map[string]string{
-						FsId:           fsId,
-						DirectoryPerms: "777",
+						ProvisioningMode: "efs-ap",
+						FsId:             fsId,
+						DirectoryPerms:   "777",
+						Uid:              "1000",
+						Gid:              "1001",
+						UseIam:           "true",
 					},
 				}
 
 				ctx := context.Background()
-				_, err := driver.CreateVolume(ctx, req)
-				if err == nil {
-					t.Fatal("CreateVolume did not fail")
+				fileSystem := &cloud.FileSystem{
+					FileSystemId: fsId,
+				}
+				accessPoint := &cloud.AccessPoint{
+					AccessPointId: apId,
+					FileSystemId:  fsId,
+				}
+				mockCloud.EXPECT().DescribeFileSystem(gomock.Eq(ctx), gomock.Any()).Return(fileSystem, nil)
+				mockCloud.EXPECT().CreateAccessPoint(gomock.Eq(ctx), gomock.Any(), gomock.Any(), gomock.Any()).Return(accessPoint, nil)
+
+				res, err := driver.CreateVolume(ctx, req)
+				if err != nil {
+					t.Fatalf("CreateVolume failed: %v", err)
+				}
+
+				if res.Volume.VolumeContext[UseIam] != "true" {
+					t.Fatalf("Expected %v in volume context, got: %v", UseIam, res.Volume.VolumeContext)
 				}
 				mockCtl.Finish()
 			},
 		},
 		{
-			name: "Fail: Missing Parameter FsId",
+			name: "Success: Access point ARN in volume context",
 			testFunc: func(t *testing.T) {
 				mockCtl := gomock.NewController(t)
 				mockCloud := mocks.NewMockCloud(mockCtl)
@@ -1684,28 +2184,47 @@ func TestCreateVolume(t *testing.T) {
 
 				req := &csi.CreateVolumeRequest{
 					Name: volumeName,
-					CapacityRange: &csi.CapacityRange{
-						RequiredBytes: capacityRange,
-					},
 					VolumeCapabilities: []*csi.VolumeCapability{
 						stdVolCap,
 					},
+					CapacityRange: &csi.CapacityRange{
+						RequiredBytes: capacityRange,
+					},
 					Parameters: map[string]string{
 						ProvisioningMode: "efs-ap",
+						FsId:             fsId,
 						DirectoryPerms:   "777",
+						Uid:              "1000",
+						Gid:              "1001",
 					},
 				}
 
 				ctx := context.Background()
-				_, err := driver.CreateVolume(ctx, req)
-				if err == nil {
-					t.Fatal("CreateVolume did not fail")
+				fileSystem := &cloud.FileSystem{
+					FileSystemId: fsId,
+				}
+				arn := "arn:aws:elasticfilesystem:us-east-1:123456789012:access-point/" + apId
+				accessPoint := &cloud.AccessPoint{
+					AccessPointId:  apId,
+					AccessPointArn: arn,
+					FileSystemId:   fsId,
+				}
+				mockCloud.EXPECT().DescribeFileSystem(gomock.Eq(ctx), gomock.Any()).Return(fileSystem, nil)
+				mockCloud.EXPECT().CreateAccessPoint(gomock.Eq(ctx), gomock.Any(), gomock.Any(), gomock.Any()).Return(accessPoint, nil)
+
+				res, err := driver.CreateVolume(ctx, req)
+				if err != nil {
+					t.Fatalf("CreateVolume failed: %v", err)
+				}
+
+				if res.Volume.VolumeContext[AccessPointArn] != arn {
+					t.Fatalf("Volume context %v mismatched. Expected: %v, Actual: %v", AccessPointArn, arn, res.Volume.VolumeContext[AccessPointArn])
 				}
 				mockCtl.Finish()
 			},
 		},
 		{
-			name: "Fail: FsId cannot be blank",
+			name: "Success: Name tag derived from the PVC metadata",
 			testFunc: func(t *testing.T) {
 				mockCtl := gomock.NewController(t)
 				mockCloud := mocks.NewMockCloud(mockCtl)
@@ -1718,29 +2237,49 @@ func TestCreateVolume(t *testing.T) {
 
 				req := &csi.CreateVolumeRequest{
 					Name: volumeName,
-					CapacityRange: &csi.CapacityRange{
-						RequiredBytes: capacityRange,
-					},
 					VolumeCapabilities: []*csi.VolumeCapability{
 						stdVolCap,
 					},
+					CapacityRange: &csi.CapacityRange{
+						RequiredBytes: capacityRange,
+					},
 					Parameters: map[string]string{
 						ProvisioningMode: "efs-ap",
-						FsId:             "     ",
+						FsId:             fsId,
 						DirectoryPerms:   "777",
+						Uid:              "1000",
+						Gid:              "1001",
+						NameTag:          "${.PVC.namespace}/${.PVC.name}",
+						PvcName:          "data",
+						PvcNamespace:     "team-a",
 					},
 				}
 
 				ctx := context.Background()
+				fileSystem := &cloud.FileSystem{
+					FileSystemId: fsId,
+				}
+				accessPoint := &cloud.AccessPoint{
+					AccessPointId: apId,
+					FileSystemId:  fsId,
+				}
+				mockCloud.EXPECT().DescribeFileSystem(gomock.Eq(ctx), gomock.Any()).Return(fileSystem, nil)
+				mockCloud.EXPECT().CreateAccessPoint(gomock.Eq(ctx), gomock.Eq(volumeName), gomock.Any(), gomock.Any()).Return(accessPoint, nil).
+					Do(func(ctx context.Context, clientToken string, accessPointOpts *cloud.AccessPointOptions, reuseAccessPoint bool) {
+						if accessPointOpts.Tags[NameTagKey] != "team-a/data" {
+							t.Fatalf("Name tag mismatched. Expected: %v, actual: %v", "team-a/data", accessPointOpts.Tags[NameTagKey])
+						}
+					})
+
 				_, err := driver.CreateVolume(ctx, req)
-				if err == nil {
-					t.Fatal("CreateVolume did not fail")
+				if err != nil {
+					t.Fatalf("CreateVolume failed: %v", err)
 				}
 				mockCtl.Finish()
 			},
 		},
 		{
-			name: "Fail: Uid invalid",
+			name: "Success: StorageClass tag sanitized",
 			testFunc: func(t *testing.T) {
 				mockCtl := gomock.NewController(t)
 				mockCloud := mocks.NewMockCloud(mockCtl)
@@ -1753,30 +2292,47 @@ func TestCreateVolume(t *testing.T) {
 
 				req := &csi.CreateVolumeRequest{
 					Name: volumeName,
-					CapacityRange: &csi.CapacityRange{
-						RequiredBytes: capacityRange,
-					},
 					VolumeCapabilities: []*csi.VolumeCapability{
 						stdVolCap,
 					},
+					CapacityRange: &csi.CapacityRange{
+						RequiredBytes: capacityRange,
+					},
 					Parameters: map[string]string{
 						ProvisioningMode: "efs-ap",
 						FsId:             fsId,
 						DirectoryPerms:   "777",
-						Uid:              "invalid",
+						Uid:              "1000",
+						Gid:              "1001",
+						StorageClassName: " efs-sc (standard)* ",
 					},
 				}
 
 				ctx := context.Background()
+				fileSystem := &cloud.FileSystem{
+					FileSystemId: fsId,
+				}
+				accessPoint := &cloud.AccessPoint{
+					AccessPointId: apId,
+					FileSystemId:  fsId,
+				}
+				mockCloud.EXPECT().DescribeFileSystem(gomock.Eq(ctx), gomock.Any()).Return(fileSystem, nil)
+				mockCloud.EXPECT().CreateAccessPoint(gomock.Eq(ctx), gomock.Eq(volumeName), gomock.Any(), gomock.Any()).Return(accessPoint, nil).
+					Do(func(ctx context.Context, clientToken string, accessPointOpts *cloud.AccessPointOptions, reuseAccessPoint bool) {
+						if accessPointOpts.Tags[StorageClassTagKey] != "efs-sc -standard--" {
+							t.Fatalf("StorageClass tag mismatched. Expected: %v, actual: %v", "efs-sc -standard--", accessPointOpts.Tags[StorageClassTagKey])
+						}
+					})
+
 				_, err := driver.CreateVolume(ctx, req)
-				if err == nil {
-					t.Fatal("CreateVolume did not fail")
+				if err != nil {
+					t.Fatalf("CreateVolume failed: %v", err)
 				}
 				mockCtl.Finish()
 			},
 		},
 		{
-			name: "Fail: Uid cannot be negative",
+			name: "Fail: StorageClass tag empty",
 			testFunc: func(t *testing.T) {
 				mockCtl := gomock.NewController(t)
 				mockCloud := mocks.NewMockCloud(mockCtl)
@@ -1789,30 +2345,29 @@ func TestCreateVolume(t *testing.T) {
 
 				req := &csi.CreateVolumeRequest{
 					Name: volumeName,
-					CapacityRange: &csi.CapacityRange{
-						RequiredBytes: capacityRange,
-					},
 					VolumeCapabilities: []*csi.VolumeCapability{
 						stdVolCap,
 					},
+					CapacityRange: &csi.CapacityRange{
+						RequiredBytes: capacityRange,
+					},
 					Parameters: map[string]string{
 						ProvisioningMode: "efs-ap",
 						FsId:             fsId,
 						DirectoryPerms:   "777",
-						Uid:              "-5",
+						StorageClassName: "  ",
 					},
 				}
 
-				ctx := context.Background()
-				_, err := driver.CreateVolume(ctx, req)
-				if err == nil {
-					t.Fatal("CreateVolume did not fail")
+				_, err := driver.CreateVolume(context.Background(), req)
+				if status.Code(err) != codes.InvalidArgument {
+					t.Fatalf("Expected an InvalidArgument error, got: %v", err)
 				}
 				mockCtl.Finish()
 			},
 		},
 		{
-			name: "Fail: Gid invalid",
+			name: "Success: useIam parameter overrides the driver default",
 			testFunc: func(t *testing.T) {
 				mockCtl := gomock.NewController(t)
 				mockCloud := mocks.NewMockCloud(mockCtl)
@@ -1821,34 +2376,51 @@ func TestCreateVolume(t *testing.T) {
 					endpoint:     endpoint,
 					cloud:        mockCloud,
 					gidAllocator: NewGidAllocator(),
+					useIam:       true,
 				}
 
 				req := &csi.CreateVolumeRequest{
 					Name: volumeName,
-					CapacityRange: &csi.CapacityRange{
-						RequiredBytes: capacityRange,
-					},
 					VolumeCapabilities: []*csi.VolumeCapability{
 						stdVolCap,
 					},
+					CapacityRange: &csi.CapacityRange{
+						RequiredBytes: capacityRange,
+					},
 					Parameters: map[string]string{
 						ProvisioningMode: "efs-ap",
 						FsId:             fsId,
 						DirectoryPerms:   "777",
-						Gid:              "invalid",
+						Uid:              "1000",
+						Gid:              "1001",
+						UseIam:           "false",
 					},
 				}
 
 				ctx := context.Background()
-				_, err := driver.CreateVolume(ctx, req)
-				if err == nil {
-					t.Fatal("CreateVolume did not fail")
+				fileSystem := &cloud.FileSystem{
+					FileSystemId: fsId,
+				}
+				accessPoint := &cloud.AccessPoint{
+					AccessPointId: apId,
+					FileSystemId:  fsId,
+				}
+				mockCloud.EXPECT().DescribeFileSystem(gomock.Eq(ctx), gomock.Any()).Return(fileSystem, nil)
+				mockCloud.EXPECT().CreateAccessPoint(gomock.Eq(ctx), gomock.Any(), gomock.Any(), gomock.Any()).Return(accessPoint, nil)
+
+				res, err := driver.CreateVolume(ctx, req)
+				if err != nil {
+					t.Fatalf("CreateVolume failed: %v", err)
+				}
+
+				if _, ok := res.Volume.VolumeContext[UseIam]; ok {
+					t.Fatalf("Expected no %v in volume context, got: %v", UseIam, res.Volume.VolumeContext)
 				}
 				mockCtl.Finish()
 			},
 		},
 		{
-			name: "Fail: Gid cannot be negative",
+			name: "Success: cluster name is added to the access point tags",
 			testFunc: func(t *testing.T) {
 				mockCtl := gomock.NewController(t)
 				mockCloud := mocks.NewMockCloud(mockCtl)
@@ -1857,34 +2429,54 @@ func TestCreateVolume(t *testing.T) {
 					endpoint:     endpoint,
 					cloud:        mockCloud,
 					gidAllocator: NewGidAllocator(),
+					clusterName:  "cluster-a",
 				}
 
 				req := &csi.CreateVolumeRequest{
 					Name: volumeName,
-					CapacityRange: &csi.CapacityRange{
-						RequiredBytes: capacityRange,
-					},
 					VolumeCapabilities: []*csi.VolumeCapability{
 						stdVolCap,
 					},
+					CapacityRange: &csi.CapacityRange{
+						RequiredBytes: capacityRange,
+					},
 					Parameters: map[string]string{
 						ProvisioningMode: "efs-ap",
 						FsId:             fsId,
 						DirectoryPerms:   "777",
-						Gid:              "-5",
+						Uid:              "1000",
+						Gid:              "1001",
 					},
 				}
 
 				ctx := context.Background()
+				fileSystem := &cloud.FileSystem{
+					FileSystemId: fsId,
+				}
+				accessPoint := &cloud.AccessPoint{
+					AccessPointId: apId,
+					FileSystemId:  fsId,
+				}
+				mockCloud.EXPECT().DescribeFileSystem(gomock.Eq(ctx), gomock.Any()).Return(fileSystem, nil)
+				mockCloud.EXPECT().CreateAccessPoint(gomock.Eq(ctx), gomock.Any(), gomock.Any(), gomock.Any()).Return(accessPoint, nil).
+					Do(func(ctx context.Context, clientToken string, accessPointOpts *cloud.AccessPointOptions, reuseAccessPointName bool) {
+						if accessPointOpts.Tags[ClusterNameTagKey] != "cluster-a" {
+							t.Fatalf("Cluster name tag mismatched. Expected: %v, actual: %v", "cluster-a", accessPointOpts.Tags)
+						}
+						if accessPointOpts.Tags[DefaultTagKey] != DefaultTagValue {
+							t.Fatalf("Default tag missing from access point tags: %v", accessPointOpts.Tags)
+						}
+					})
+
 				_, err := driver.CreateVolume(ctx, req)
-				if err == nil {
-					t.Fatal("CreateVolume did not fail")
+				if err != nil {
+					t.Fatalf("CreateVolume failed: %v", err)
 				}
 				mockCtl.Finish()
 			},
 		},
 		{
-			name: "Fail: Gid min cannot be 0",
+			name: "Fail: Volume name missing",
 			testFunc: func(t *testing.T) {
 				mockCtl := gomock.NewController(t)
 				mockCloud := mocks.NewMockCloud(mockCtl)
@@ -1896,18 +2488,10 @@ func TestCreateVolume(t *testing.T) {
 				}
 
 				req := &csi.CreateVolumeRequest{
-					Name: volumeName,
-					CapacityRange: &csi.CapacityRange{
-						RequiredBytes: capacityRange,
-					},
-					VolumeCapabilities: []*csi.VolumeCapability{
-						stdVolCap,
-					},
 					Parameters: map[string]string{
 						ProvisioningMode: "efs-ap",
 						FsId:             fsId,
 						DirectoryPerms:   "777",
-						GidMin:           "0",
 					},
 				}
 
@@ -1920,7 +2504,7 @@ func TestCreateVolume(t *testing.T) {
 			},
 		},
 		{
-			name: "Fail: GidMin must be an integer",
+			name: "Fail: Capacity Range missing",
 			testFunc: func(t *testing.T) {
 				mockCtl := gomock.NewController(t)
 				mockCloud := mocks.NewMockCloud(mockCtl)
@@ -1933,17 +2517,10 @@ func TestCreateVolume(t *testing.T) {
 
 				req := &csi.CreateVolumeRequest{
 					Name: volumeName,
-					CapacityRange: &csi.CapacityRange{
-						RequiredBytes: capacityRange,
-					},
-					VolumeCapabilities: []*csi.VolumeCapability{
-						stdVolCap,
-					},
 					Parameters: map[string]string{
 						ProvisioningMode: "efs-ap",
 						FsId:             fsId,
 						DirectoryPerms:   "777",
-						GidMin:           "test",
 					},
 				}
 
@@ -1956,7 +2533,7 @@ func TestCreateVolume(t *testing.T) {
 			},
 		},
 		{
-			name: "Fail: GidMax must be an integer",
+			name: "Fail: Volume capability Missing",
 			testFunc: func(t *testing.T) {
 				mockCtl := gomock.NewController(t)
 				mockCloud := mocks.NewMockCloud(mockCtl)
@@ -1972,15 +2549,10 @@ func TestCreateVolume(t *testing.T) {
 					CapacityRange: &csi.CapacityRange{
 						RequiredBytes: capacityRange,
 					},
-					VolumeCapabilities: []*csi.VolumeCapability{
-						stdVolCap,
-					},
 					Parameters: map[string]string{
 						ProvisioningMode: "efs-ap",
 						FsId:             fsId,
 						DirectoryPerms:   "777",
-						GidMin:           "2000",
-						GidMax:           "test",
 					},
 				}
 
@@ -1993,7 +2565,7 @@ func TestCreateVolume(t *testing.T) {
 			},
 		},
 		{
-			name: "Fail: GidMax must be greater than GidMin",
+			name: "Fail: Volume capability Not Supported",
 			testFunc: func(t *testing.T) {
 				mockCtl := gomock.NewController(t)
 				mockCloud := mocks.NewMockCloud(mockCtl)
@@ -2010,14 +2582,19 @@ func TestCreateVolume(t *testing.T) {
 						RequiredBytes: capacityRange,
 					},
 					VolumeCapabilities: []*csi.VolumeCapability{
-						stdVolCap,
+						{
+							AccessType: &csi.VolumeCapability_Mount{
+								Mount: &csi.VolumeCapability_MountVolume{},
+							},
+							AccessMode: &csi.VolumeCapability_AccessMode{
+								Mode: csi.VolumeCapability_AccessMode_SINGLE_NODE_READER_ONLY,
+							},
+						},
 					},
 					Parameters: map[string]string{
 						ProvisioningMode: "efs-ap",
 						FsId:             fsId,
 						DirectoryPerms:   "777",
-						GidMin:           "2000",
-						GidMax:           "1000",
 					},
 				}
 
@@ -2030,7 +2607,7 @@ func TestCreateVolume(t *testing.T) {
 			},
 		},
 		{
-			name: "Fail: GidMax must be provided with GidMin",
+			name: "Fail: Volume fsType not supported",
 			testFunc: func(t *testing.T) {
 				mockCtl := gomock.NewController(t)
 				mockCloud := mocks.NewMockCloud(mockCtl)
@@ -2039,21 +2616,33 @@ func TestCreateVolume(t *testing.T) {
 					endpoint:     endpoint,
 					cloud:        mockCloud,
 					gidAllocator: NewGidAllocator(),
+					tags:         parseTagsFromStr(""),
 				}
 
 				req := &csi.CreateVolumeRequest{
 					Name: volumeName,
+					VolumeCapabilities: []*csi.VolumeCapability{
+						{
+							AccessType: &csi.VolumeCapability_Mount{
+								Mount: &csi.VolumeCapability_MountVolume{
+									FsType: "abc",
+								},
+							},
+							AccessMode: &csi.VolumeCapability_AccessMode{
+								Mode: csi.VolumeCapability_AccessMode_MULTI_NODE_MULTI_WRITER,
+							},
+						},
+					},
 					CapacityRange: &csi.CapacityRange{
 						RequiredBytes: capacityRange,
 					},
-					VolumeCapabilities: []*csi.VolumeCapability{
-						stdVolCap,
-					},
 					Parameters: map[string]string{
 						ProvisioningMode: "efs-ap",
 						FsId:             fsId,
+						GidMin:           "1000",
+						GidMax:           "2000",
 						DirectoryPerms:   "777",
-						GidMin:           "2000",
+						AzName:           "us-east-1a",
 					},
 				}
 
@@ -2066,7 +2655,7 @@ func TestCreateVolume(t *testing.T) {
 			},
 		},
 		{
-			name: "Fail: GidMin must be provided with GidMax",
+			name: "Fail: AccessType is block",
 			testFunc: func(t *testing.T) {
 				mockCtl := gomock.NewController(t)
 				mockCloud := mocks.NewMockCloud(mockCtl)
@@ -2083,13 +2672,19 @@ func TestCreateVolume(t *testing.T) {
 						RequiredBytes: capacityRange,
 					},
 					VolumeCapabilities: []*csi.VolumeCapability{
-						stdVolCap,
+						{
+							AccessType: &csi.VolumeCapability_Block{
+								Block: &csi.VolumeCapability_BlockVolume{},
+							},
+							AccessMode: &csi.VolumeCapability_AccessMode{
+								Mode: csi.VolumeCapability_AccessMode_MULTI_NODE_MULTI_WRITER,
+							},
+						},
 					},
 					Parameters: map[string]string{
 						ProvisioningMode: "efs-ap",
 						FsId:             fsId,
 						DirectoryPerms:   "777",
-						GidMax:           "2000",
 					},
 				}
 
@@ -2102,7 +2697,7 @@ func TestCreateVolume(t *testing.T) {
 			},
 		},
 		{
-			name: "Fail: File system does not exist with fixed uid/gid",
+			name: "Fail: Provisioning Mode Not Supported",
 			testFunc: func(t *testing.T) {
 				mockCtl := gomock.NewController(t)
 				mockCloud := mocks.NewMockCloud(mockCtl)
@@ -2115,25 +2710,20 @@ func TestCreateVolume(t *testing.T) {
 
 				req := &csi.CreateVolumeRequest{
 					Name: volumeName,
-					VolumeCapabilities: []*csi.VolumeCapability{
-						stdVolCap,
-					},
 					CapacityRange: &csi.CapacityRange{
 						RequiredBytes: capacityRange,
 					},
+					VolumeCapabilities: []*csi.VolumeCapability{
+						stdVolCap,
+					},
 					Parameters: map[string]string{
-						ProvisioningMode: "efs-ap",
+						ProvisioningMode: "efs-xyz",
 						FsId:             fsId,
-						GidMin:           "1000",
-						GidMax:           "2000",
 						DirectoryPerms:   "777",
-						Uid:              "1000",
-						Gid:              "1001",
 					},
 				}
 
 				ctx := context.Background()
-				mockCloud.EXPECT().DescribeFileSystem(gomock.Eq(ctx), gomock.Any()).Return(nil, cloud.ErrNotFound)
 				_, err := driver.CreateVolume(ctx, req)
 				if err == nil {
 					t.Fatal("CreateVolume did not fail")
@@ -2142,7 +2732,7 @@ func TestCreateVolume(t *testing.T) {
 			},
 		},
 		{
-			name: "Fail: File system does not exist",
+			name: "Fail: Missing Provisioning Mode parameter",
 			testFunc: func(t *testing.T) {
 				mockCtl := gomock.NewController(t)
 				mockCloud := mocks.NewMockCloud(mockCtl)
@@ -2155,23 +2745,19 @@ func TestCreateVolume(t *testing.T) {
 
 				req := &csi.CreateVolumeRequest{
 					Name: volumeName,
-					VolumeCapabilities: []*csi.VolumeCapability{
-						stdVolCap,
-					},
 					CapacityRange: &csi.CapacityRange{
 						RequiredBytes: capacityRange,
 					},
+					VolumeCapabilities: []*csi.VolumeCapability{
+						stdVolCap,
+					},
 					Parameters: map[string]string{
-						ProvisioningMode: "efs-ap",
-						FsId:             fsId,
-						GidMin:           "1000",
-						GidMax:           "2000",
-						DirectoryPerms:   "777",
+						FsId:           fsId,
+						DirectoryPerms: "777",
 					},
 				}
 
 				ctx := context.Background()
-				mockCloud.EXPECT().ListAccessPoints(gomock.Eq(ctx), gomock.Any()).Return(nil, cloud.ErrNotFound)
 				_, err := driver.CreateVolume(ctx, req)
 				if err == nil {
 					t.Fatal("CreateVolume did not fail")
@@ -2180,7 +2766,7 @@ func TestCreateVolume(t *testing.T) {
 			},
 		},
 		{
-			name: "Fail: DescribeFileSystem Access Denied with fixed uid/gid",
+			name: "Fail: Missing Parameter FsId",
 			testFunc: func(t *testing.T) {
 				mockCtl := gomock.NewController(t)
 				mockCloud := mocks.NewMockCloud(mockCtl)
@@ -2193,25 +2779,19 @@ func TestCreateVolume(t *testing.T) {
 
 				req := &csi.CreateVolumeRequest{
 					Name: volumeName,
-					VolumeCapabilities: []*csi.VolumeCapability{
-						stdVolCap,
-					},
 					CapacityRange: &csi.CapacityRange{
 						RequiredBytes: capacityRange,
 					},
+					VolumeCapabilities: []*csi.VolumeCapability{
+						stdVolCap,
+					},
 					Parameters: map[string]string{
 						ProvisioningMode: "efs-ap",
-						FsId:             fsId,
-						GidMin:           "1000",
-						GidMax:           "2000",
 						DirectoryPerms:   "777",
-						Uid:              "1000",
-						Gid:              "1001",
 					},
 				}
 
 				ctx := context.Background()
-				mockCloud.EXPECT().DescribeFileSystem(gomock.Eq(ctx), gomock.Any()).Return(nil, cloud.ErrAccessDenied)
 				_, err := driver.CreateVolume(ctx, req)
 				if err == nil {
 					t.Fatal("CreateVolume did not fail")
@@ -2220,7 +2800,7 @@ func TestCreateVolume(t *testing.T) {
 			},
 		},
 		{
-			name: "Fail: DescribeFileSystem Access Denied",
+			name: "Fail: FsId cannot be blank",
 			testFunc: func(t *testing.T) {
 				mockCtl := gomock.NewController(t)
 				mockCloud := mocks.NewMockCloud(mockCtl)
@@ -2233,23 +2813,20 @@ func TestCreateVolume(t *testing.T) {
 
 				req := &csi.CreateVolumeRequest{
 					Name: volumeName,
-					VolumeCapabilities: []*csi.VolumeCapability{
-						stdVolCap,
-					},
 					CapacityRange: &csi.CapacityRange{
 						RequiredBytes: capacityRange,
 					},
+					VolumeCapabilities: []*csi.VolumeCapability{
+						stdVolCap,
+					},
 					Parameters: map[string]string{
 						ProvisioningMode: "efs-ap",
-						FsId:             fsId,
-						GidMin:           "1000",
-						GidMax:           "2000",
+						FsId:             "     ",
 						DirectoryPerms:   "777",
 					},
 				}
 
 				ctx := context.Background()
-				mockCloud.EXPECT().ListAccessPoints(gomock.Eq(ctx), gomock.Any()).Return(nil, cloud.ErrAccessDenied)
 				_, err := driver.CreateVolume(ctx, req)
 				if err == nil {
 					t.Fatal("CreateVolume did not fail")
@@ -2258,7 +2835,7 @@ func TestCreateVolume(t *testing.T) {
 			},
 		},
 		{
-			name: "Fail: Describe File system call fails with fixed uid/gid",
+			name: "Fail: FsId malformed",
 			testFunc: func(t *testing.T) {
 				mockCtl := gomock.NewController(t)
 				mockCloud := mocks.NewMockCloud(mockCtl)
@@ -2271,34 +2848,29 @@ func TestCreateVolume(t *testing.T) {
 
 				req := &csi.CreateVolumeRequest{
 					Name: volumeName,
-					VolumeCapabilities: []*csi.VolumeCapability{
-						stdVolCap,
-					},
 					CapacityRange: &csi.CapacityRange{
 						RequiredBytes: capacityRange,
 					},
+					VolumeCapabilities: []*csi.VolumeCapability{
+						stdVolCap,
+					},
 					Parameters: map[string]string{
 						ProvisioningMode: "efs-ap",
-						FsId:             fsId,
-						GidMin:           "1000",
-						GidMax:           "2000",
+						FsId:             "fs-",
 						DirectoryPerms:   "777",
-						Uid:              "1000",
-						Gid:              "1001",
 					},
 				}
 
 				ctx := context.Background()
-				mockCloud.EXPECT().DescribeFileSystem(gomock.Eq(ctx), gomock.Any()).Return(nil, errors.New("DescribeFileSystem failed"))
 				_, err := driver.CreateVolume(ctx, req)
-				if err == nil {
-					t.Fatal("CreateVolume did not fail")
+				if status.Code(err) != codes.InvalidArgument || !strings.Contains(err.Error(), FsId) {
+					t.Fatalf("Expected an InvalidArgument error naming %v, got: %v", FsId, err)
 				}
 				mockCtl.Finish()
 			},
 		},
 		{
-			name: "Fail: List access points call fails",
+			name: "Success: FsId given by its ARN",
 			testFunc: func(t *testing.T) {
 				mockCtl := gomock.NewController(t)
 				mockCloud := mocks.NewMockCloud(mockCtl)
@@ -2311,32 +2883,47 @@ func TestCreateVolume(t *testing.T) {
 
 				req := &csi.CreateVolumeRequest{
 					Name: volumeName,
-					VolumeCapabilities: []*csi.VolumeCapability{
-						stdVolCap,
-					},
 					CapacityRange: &csi.CapacityRange{
 						RequiredBytes: capacityRange,
 					},
+					VolumeCapabilities: []*csi.VolumeCapability{
+						stdVolCap,
+					},
 					Parameters: map[string]string{
 						ProvisioningMode: "efs-ap",
-						FsId:             fsId,
-						GidMin:           "1000",
-						GidMax:           "2000",
+						FsId:             "arn:aws:elasticfilesystem:us-east-1:123456789012:file-system/" + fsId,
 						DirectoryPerms:   "777",
+						Uid:              "1000",
+						Gid:              "1000",
 					},
 				}
 
 				ctx := context.Background()
-				mockCloud.EXPECT().ListAccessPoints(gomock.Eq(ctx), gomock.Any()).Return(nil, errors.New("ListAccessPoints failed"))
-				_, err := driver.CreateVolume(ctx, req)
-				if err == nil {
-					t.Fatal("CreateVolume did not fail")
+				accessPoint := &cloud.AccessPoint{
+					AccessPointId: apId,
+					FileSystemId:  fsId,
+				}
+				mockCloud.EXPECT().DescribeFileSystem(gomock.Eq(ctx), gomock.Eq(fsId)).Return(&cloud.FileSystem{FileSystemId: fsId}, nil)
+				mockCloud.EXPECT().CreateAccessPoint(gomock.Eq(ctx), gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+					func(ctx context.Context, clientToken string, accessPointOpts *cloud.AccessPointOptions, reuseAccessPoint bool) (*cloud.AccessPoint, error) {
+						if accessPointOpts.FileSystemId != fsId {
+							t.Fatalf("Access point requested on file system %v, expected %v", accessPointOpts.FileSystemId, fsId)
+						}
+						return accessPoint, nil
+					})
+
+				res, err := driver.CreateVolume(ctx, req)
+				if err != nil {
+					t.Fatalf("CreateVolume failed: %v", err)
+				}
+				if res.Volume.VolumeId != volumeId {
+					t.Fatalf("Volume Id mismatched. Expected: %v, Actual: %v", volumeId, res.Volume.VolumeId)
 				}
 				mockCtl.Finish()
 			},
 		},
 		{
-			name: "Fail: Create Access Point call fails",
+			name: "Fail: Uid invalid",
 			testFunc: func(t *testing.T) {
 				mockCtl := gomock.NewController(t)
 				mockCloud := mocks.NewMockCloud(mockCtl)
@@ -2349,24 +2936,21 @@ func TestCreateVolume(t *testing.T) {
 
 				req := &csi.CreateVolumeRequest{
 					Name: volumeName,
-					VolumeCapabilities: []*csi.VolumeCapability{
-						stdVolCap,
-					},
 					CapacityRange: &csi.CapacityRange{
 						RequiredBytes: capacityRange,
 					},
+					VolumeCapabilities: []*csi.VolumeCapability{
+						stdVolCap,
+					},
 					Parameters: map[string]string{
 						ProvisioningMode: "efs-ap",
 						FsId:             fsId,
-						GidMin:           "1000",
-						GidMax:           "2000",
 						DirectoryPerms:   "777",
+						Uid:              "invalid",
 					},
 				}
 
 				ctx := context.Background()
-				mockCloud.EXPECT().ListAccessPoints(gomock.Eq(ctx), gomock.Any()).Return([]*cloud.AccessPoint{}, nil)
-				mockCloud.EXPECT().CreateAccessPoint(gomock.Eq(ctx), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, errors.New("CreateAccessPoint call failed"))
 				_, err := driver.CreateVolume(ctx, req)
 				if err == nil {
 					t.Fatal("CreateVolume did not fail")
@@ -2375,7 +2959,7 @@ func TestCreateVolume(t *testing.T) {
 			},
 		},
 		{
-			name: "Fail: CreateAccessPoint Access Denied",
+			name: "Fail: Uid cannot be negative",
 			testFunc: func(t *testing.T) {
 				mockCtl := gomock.NewController(t)
 				mockCloud := mocks.NewMockCloud(mockCtl)
@@ -2388,24 +2972,21 @@ func TestCreateVolume(t *testing.T) {
 
 				req := &csi.CreateVolumeRequest{
 					Name: volumeName,
-					VolumeCapabilities: []*csi.VolumeCapability{
-						stdVolCap,
-					},
 					CapacityRange: &csi.CapacityRange{
 						RequiredBytes: capacityRange,
 					},
+					VolumeCapabilities: []*csi.VolumeCapability{
+						stdVolCap,
+					},
 					Parameters: map[string]string{
 						ProvisioningMode: "efs-ap",
 						FsId:             fsId,
-						GidMin:           "1000",
-						GidMax:           "2000",
 						DirectoryPerms:   "777",
+						Uid:              "-5",
 					},
 				}
 
 				ctx := context.Background()
-				mockCloud.EXPECT().ListAccessPoints(gomock.Eq(ctx), gomock.Any()).Return([]*cloud.AccessPoint{}, nil)
-				mockCloud.EXPECT().CreateAccessPoint(gomock.Eq(ctx), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, cloud.ErrAccessDenied)
 				_, err := driver.CreateVolume(ctx, req)
 				if err == nil {
 					t.Fatal("CreateVolume did not fail")
@@ -2414,7 +2995,7 @@ func TestCreateVolume(t *testing.T) {
 			},
 		},
 		{
-			name: "Fail: Run out of GIDs",
+			name: "Fail: Gid invalid",
 			testFunc: func(t *testing.T) {
 				mockCtl := gomock.NewController(t)
 				mockCloud := mocks.NewMockCloud(mockCtl)
@@ -2427,53 +3008,30 @@ func TestCreateVolume(t *testing.T) {
 
 				req := &csi.CreateVolumeRequest{
 					Name: volumeName,
-					VolumeCapabilities: []*csi.VolumeCapability{
-						stdVolCap,
-					},
 					CapacityRange: &csi.CapacityRange{
 						RequiredBytes: capacityRange,
 					},
+					VolumeCapabilities: []*csi.VolumeCapability{
+						stdVolCap,
+					},
 					Parameters: map[string]string{
 						ProvisioningMode: "efs-ap",
 						FsId:             fsId,
-						GidMin:           "1000",
-						GidMax:           "1001",
 						DirectoryPerms:   "777",
+						Gid:              "invalid",
 					},
 				}
 
 				ctx := context.Background()
-				ap1 := &cloud.AccessPoint{
-					AccessPointId: apId,
-					FileSystemId:  fsId,
-					PosixUser: &cloud.PosixUser{
-						Gid: 1000,
-						Uid: 1000,
-					},
-				}
-				ap2 := &cloud.AccessPoint{
-					AccessPointId: apId,
-					FileSystemId:  fsId,
-					PosixUser: &cloud.PosixUser{
-						Gid: 1001,
-						Uid: 1001,
-					},
-				}
-				mockCloud.EXPECT().ListAccessPoints(gomock.Eq(ctx), gomock.Any()).Return([]*cloud.AccessPoint{ap1, ap2}, nil).AnyTimes()
-				mockCloud.EXPECT().CreateAccessPoint(gomock.Eq(ctx), gomock.Any(), gomock.Any(), gomock.Any()).Return(ap2, nil).AnyTimes()
-
-				var err error
-				// All GIDs from available range are taken, CreateVolume should fail.
-				_, err = driver.CreateVolume(ctx, req)
-
+				_, err := driver.CreateVolume(ctx, req)
 				if err == nil {
-					t.Fatalf("CreateVolume did not fail")
+					t.Fatal("CreateVolume did not fail")
 				}
 				mockCtl.Finish()
 			},
 		},
 		{
-			name: "Fail: Cannot assume role for x-account",
+			name: "Fail: Gid cannot be negative",
 			testFunc: func(t *testing.T) {
 				mockCtl := gomock.NewController(t)
 				mockCloud := mocks.NewMockCloud(mockCtl)
@@ -2482,50 +3040,38 @@ func TestCreateVolume(t *testing.T) {
 					endpoint:     endpoint,
 					cloud:        mockCloud,
 					gidAllocator: NewGidAllocator(),
-					tags:         parseTagsFromStr(""),
 				}
 
-				secrets := map[string]string{}
-				secrets["awsRoleArn"] = "arn:aws:iam::1234567890:role/EFSCrossAccountRole"
-
 				req := &csi.CreateVolumeRequest{
 					Name: volumeName,
-					VolumeCapabilities: []*csi.VolumeCapability{
-						stdVolCap,
-					},
 					CapacityRange: &csi.CapacityRange{
 						RequiredBytes: capacityRange,
 					},
+					VolumeCapabilities: []*csi.VolumeCapability{
+						stdVolCap,
+					},
 					Parameters: map[string]string{
 						ProvisioningMode: "efs-ap",
 						FsId:             fsId,
-						GidMin:           "1000",
-						GidMax:           "2000",
 						DirectoryPerms:   "777",
-						AzName:           "us-east-1a",
+						Gid:              "-5",
 					},
-					Secrets: secrets,
 				}
 
 				ctx := context.Background()
-
 				_, err := driver.CreateVolume(ctx, req)
-
 				if err == nil {
-					t.Fatalf("CreateVolume did not fail")
+					t.Fatal("CreateVolume did not fail")
 				}
-
 				mockCtl.Finish()
 			},
 		},
 		{
-			name: "Fail: subPathPattern is specified but uses unsupported attributes",
+			name: "Fail: Gid min cannot be 0",
 			testFunc: func(t *testing.T) {
 				mockCtl := gomock.NewController(t)
 				mockCloud := mocks.NewMockCloud(mockCtl)
 
-				subPathPattern := "${.PVC.name}/${foo}"
-
 				driver := &Driver{
 					endpoint:     endpoint,
 					cloud:        mockCloud,
@@ -2534,42 +3080,34 @@ func TestCreateVolume(t *testing.T) {
 
 				req := &csi.CreateVolumeRequest{
 					Name: volumeName,
-					VolumeCapabilities: []*csi.VolumeCapability{
-						stdVolCap,
-					},
 					CapacityRange: &csi.CapacityRange{
 						RequiredBytes: capacityRange,
 					},
+					VolumeCapabilities: []*csi.VolumeCapability{
+						stdVolCap,
+					},
 					Parameters: map[string]string{
 						ProvisioningMode: "efs-ap",
 						FsId:             fsId,
 						DirectoryPerms:   "777",
-						SubPathPattern:   subPathPattern,
+						GidMin:           "0",
 					},
 				}
 
 				ctx := context.Background()
-
-				mockCloud.EXPECT().ListAccessPoints(gomock.Eq(ctx), gomock.Any()).Return(nil, nil)
-
 				_, err := driver.CreateVolume(ctx, req)
 				if err == nil {
 					t.Fatal("CreateVolume did not fail")
 				}
-				if status.Code(err) != codes.InvalidArgument {
-					t.Fatalf("Did not throw InvalidArgument error, instead threw %v", err)
-				}
 				mockCtl.Finish()
 			},
 		},
 		{
-			name: "Fail: resulting accessPointDirectory is too over 100 characters",
+			name: "Fail: GidMin must be an integer",
 			testFunc: func(t *testing.T) {
 				mockCtl := gomock.NewController(t)
 				mockCloud := mocks.NewMockCloud(mockCtl)
 
-				subPathPattern := "this-directory-name-is-far-too-long-for-any-practical-purposes-and-only-serves-to-prove-a-point"
-
 				driver := &Driver{
 					endpoint:     endpoint,
 					cloud:        mockCloud,
@@ -2578,42 +3116,34 @@ func TestCreateVolume(t *testing.T) {
 
 				req := &csi.CreateVolumeRequest{
 					Name: volumeName,
-					VolumeCapabilities: []*csi.VolumeCapability{
-						stdVolCap,
-					},
 					CapacityRange: &csi.CapacityRange{
 						RequiredBytes: capacityRange,
 					},
+					VolumeCapabilities: []*csi.VolumeCapability{
+						stdVolCap,
+					},
 					Parameters: map[string]string{
 						ProvisioningMode: "efs-ap",
 						FsId:             fsId,
 						DirectoryPerms:   "777",
-						SubPathPattern:   subPathPattern,
+						GidMin:           "test",
 					},
 				}
 
 				ctx := context.Background()
-
-				mockCloud.EXPECT().ListAccessPoints(gomock.Eq(ctx), gomock.Any()).Return(nil, nil)
-
 				_, err := driver.CreateVolume(ctx, req)
 				if err == nil {
 					t.Fatal("CreateVolume did not fail")
 				}
-				if status.Code(err) != codes.InvalidArgument {
-					t.Fatalf("Did not throw InvalidArgument error, instead threw %v", err)
-				}
 				mockCtl.Finish()
 			},
 		},
 		{
-			name: "Fail:  resulting accessPointDirectory contains over 4 subdirectories",
+			name: "Fail: GidMax must be an integer",
 			testFunc: func(t *testing.T) {
 				mockCtl := gomock.NewController(t)
 				mockCloud := mocks.NewMockCloud(mockCtl)
 
-				subPathPattern := "a/b/c/d/e/f"
-
 				driver := &Driver{
 					endpoint:     endpoint,
 					cloud:        mockCloud,
@@ -2622,55 +3152,31 @@ func TestCreateVolume(t *testing.T) {
 
 				req := &csi.CreateVolumeRequest{
 					Name: volumeName,
-					VolumeCapabilities: []*csi.VolumeCapability{
-						stdVolCap,
-					},
 					CapacityRange: &csi.CapacityRange{
 						RequiredBytes: capacityRange,
 					},
+					VolumeCapabilities: []*csi.VolumeCapability{
+						stdVolCap,
+					},
 					Parameters: map[string]string{
 						ProvisioningMode: "efs-ap",
 						FsId:             fsId,
 						DirectoryPerms:   "777",
-						SubPathPattern:   subPathPattern,
+						GidMin:           "2000",
+						GidMax:           "test",
 					},
 				}
 
 				ctx := context.Background()
-
-				mockCloud.EXPECT().ListAccessPoints(gomock.Eq(ctx), gomock.Any()).Return(nil, nil)
-
 				_, err := driver.CreateVolume(ctx, req)
 				if err == nil {
 					t.Fatal("CreateVolume did not fail")
 				}
-				if status.Code(err) != codes.InvalidArgument {
-					t.Fatalf("Did not throw InvalidArgument error, instead threw %v", err)
-				}
 				mockCtl.Finish()
 			},
 		},
-	}
-
-	for _, tc := range testCases {
-		t.Run(tc.name, tc.testFunc)
-	}
-}
-
-func TestDeleteVolume(t *testing.T) {
-	var (
-		apId     = "fsap-abcd1234xyz987"
-		fsId     = "fs-abcd1234"
-		endpoint = "endpoint"
-		volumeId = "fs-abcd1234::fsap-abcd1234xyz987"
-	)
-
-	testCases := []struct {
-		name     string
-		testFunc func(t *testing.T)
-	}{
 		{
-			name: "Success: Normal flow",
+			name: "Fail: GidMax must be greater than GidMin",
 			testFunc: func(t *testing.T) {
 				mockCtl := gomock.NewController(t)
 				mockCloud := mocks.NewMockCloud(mockCtl)
@@ -2681,418 +3187,3589 @@ func TestDeleteVolume(t *testing.T) {
 					gidAllocator: NewGidAllocator(),
 				}
 
-				req := &csi.DeleteVolumeRequest{
-					VolumeId: volumeId,
+				req := &csi.CreateVolumeRequest{
+					Name: volumeName,
+					CapacityRange: &csi.CapacityRange{
+						RequiredBytes: capacityRange,
+					},
+					VolumeCapabilities: []*csi.VolumeCapability{
+						stdVolCap,
+					},
+					Parameters: map[string]string{
+						ProvisioningMode: "efs-ap",
+						FsId:             fsId,
+						DirectoryPerms:   "777",
+						GidMin:           "2000",
+						GidMax:           "1000",
+					},
 				}
 
 				ctx := context.Background()
-				mockCloud.EXPECT().DeleteAccessPoint(gomock.Eq(ctx), gomock.Eq(apId)).Return(nil)
-				_, err := driver.DeleteVolume(ctx, req)
-				if err != nil {
-					t.Fatalf("Delete Volume failed: %v", err)
+				_, err := driver.CreateVolume(ctx, req)
+				if err == nil {
+					t.Fatal("CreateVolume did not fail")
 				}
 				mockCtl.Finish()
 			},
 		},
 		{
-			name: "Success: Normal flow with deleteAccessPointRootDir",
+			name: "Fail: GidMax exceeds the maximum GID",
 			testFunc: func(t *testing.T) {
 				mockCtl := gomock.NewController(t)
 				mockCloud := mocks.NewMockCloud(mockCtl)
-				mockMounter := mocks.NewMockMounter(mockCtl)
 
 				driver := &Driver{
-					endpoint:                 endpoint,
-					cloud:                    mockCloud,
-					mounter:                  mockMounter,
-					gidAllocator:             NewGidAllocator(),
-					deleteAccessPointRootDir: true,
-				}
-
-				req := &csi.DeleteVolumeRequest{
-					VolumeId: volumeId,
+					endpoint:     endpoint,
+					cloud:        mockCloud,
+					gidAllocator: NewGidAllocator(),
 				}
 
-				accessPoint := &cloud.AccessPoint{
-					AccessPointId:      apId,
-					FileSystemId:       fsId,
-					AccessPointRootDir: "",
-					CapacityGiB:        0,
+				req := &csi.CreateVolumeRequest{
+					Name: volumeName,
+					CapacityRange: &csi.CapacityRange{
+						RequiredBytes: capacityRange,
+					},
+					VolumeCapabilities: []*csi.VolumeCapability{
+						stdVolCap,
+					},
+					Parameters: map[string]string{
+						ProvisioningMode: "efs-ap",
+						FsId:             fsId,
+						DirectoryPerms:   "777",
+						GidMin:           "1000",
+						GidMax:           "4294967295",
+					},
 				}
 
 				ctx := context.Background()
-				mockMounter.EXPECT().MakeDir(gomock.Any()).Return(nil)
-				mockMounter.EXPECT().Mount(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
-				mockMounter.EXPECT().Unmount(gomock.Any()).Return(nil)
-				mockCloud.EXPECT().DescribeAccessPoint(gomock.Eq(ctx), gomock.Eq(apId)).Return(accessPoint, nil)
-				mockCloud.EXPECT().DeleteAccessPoint(gomock.Eq(ctx), gomock.Eq(apId)).Return(nil)
-				_, err := driver.DeleteVolume(ctx, req)
-				if err != nil {
-					t.Fatalf("Delete Volume failed: %v", err)
+				_, err := driver.CreateVolume(ctx, req)
+				if status.Code(err) != codes.InvalidArgument {
+					t.Fatalf("Expected InvalidArgument, got: %v", err)
 				}
 				mockCtl.Finish()
 			},
 		},
 		{
-			name: "Success: DescribeAccessPoint Access Point Does not exist",
+			name: "Fail: Gid exceeds the maximum GID",
 			testFunc: func(t *testing.T) {
 				mockCtl := gomock.NewController(t)
 				mockCloud := mocks.NewMockCloud(mockCtl)
-				mockMounter := mocks.NewMockMounter(mockCtl)
 
 				driver := &Driver{
-					endpoint:                 endpoint,
-					cloud:                    mockCloud,
-					mounter:                  mockMounter,
-					gidAllocator:             NewGidAllocator(),
-					deleteAccessPointRootDir: true,
+					endpoint:     endpoint,
+					cloud:        mockCloud,
+					gidAllocator: NewGidAllocator(),
+					maxGid:       2000,
 				}
 
-				req := &csi.DeleteVolumeRequest{
-					VolumeId: volumeId,
+				req := &csi.CreateVolumeRequest{
+					Name: volumeName,
+					CapacityRange: &csi.CapacityRange{
+						RequiredBytes: capacityRange,
+					},
+					VolumeCapabilities: []*csi.VolumeCapability{
+						stdVolCap,
+					},
+					Parameters: map[string]string{
+						ProvisioningMode: "efs-ap",
+						FsId:             fsId,
+						DirectoryPerms:   "777",
+						Uid:              "1000",
+						Gid:              "3000",
+					},
 				}
 
 				ctx := context.Background()
-				mockCloud.EXPECT().DescribeAccessPoint(gomock.Eq(ctx), gomock.Eq(apId)).Return(nil, cloud.ErrNotFound)
-				_, err := driver.DeleteVolume(ctx, req)
-				if err != nil {
-					t.Fatalf("Delete Volume failed: %v", err)
+				_, err := driver.CreateVolume(ctx, req)
+				if status.Code(err) != codes.InvalidArgument {
+					t.Fatalf("Expected InvalidArgument, got: %v", err)
 				}
 				mockCtl.Finish()
 			},
 		},
 		{
-			name: "Fail: DescribeAccessPoint Access Denied",
+			name: "Fail: Default GID range is above the maximum GID",
 			testFunc: func(t *testing.T) {
 				mockCtl := gomock.NewController(t)
 				mockCloud := mocks.NewMockCloud(mockCtl)
-				mockMounter := mocks.NewMockMounter(mockCtl)
 
 				driver := &Driver{
-					endpoint:                 endpoint,
-					cloud:                    mockCloud,
-					mounter:                  mockMounter,
-					gidAllocator:             NewGidAllocator(),
-					deleteAccessPointRootDir: true,
+					endpoint:     endpoint,
+					cloud:        mockCloud,
+					gidAllocator: NewGidAllocator(),
+					maxGid:       1000,
 				}
 
-				req := &csi.DeleteVolumeRequest{
+				req := &csi.CreateVolumeRequest{
+					Name: volumeName,
+					CapacityRange: &csi.CapacityRange{
+						RequiredBytes: capacityRange,
+					},
+					VolumeCapabilities: []*csi.VolumeCapability{
+						stdVolCap,
+					},
+					Parameters: map[string]string{
+						ProvisioningMode: "efs-ap",
+						FsId:             fsId,
+						DirectoryPerms:   "777",
+					},
+				}
+
+				ctx := context.Background()
+				_, err := driver.CreateVolume(ctx, req)
+				if status.Code(err) != codes.InvalidArgument {
+					t.Fatalf("Expected InvalidArgument, got: %v", err)
+				}
+				mockCtl.Finish()
+			},
+		},
+		{
+			name: "Fail: GidMax must be provided with GidMin",
+			testFunc: func(t *testing.T) {
+				mockCtl := gomock.NewController(t)
+				mockCloud := mocks.NewMockCloud(mockCtl)
+
+				driver := &Driver{
+					endpoint:     endpoint,
+					cloud:        mockCloud,
+					gidAllocator: NewGidAllocator(),
+				}
+
+				req := &csi.CreateVolumeRequest{
+					Name: volumeName,
+					CapacityRange: &csi.CapacityRange{
+						RequiredBytes: capacityRange,
+					},
+					VolumeCapabilities: []*csi.VolumeCapability{
+						stdVolCap,
+					},
+					Parameters: map[string]string{
+						ProvisioningMode: "efs-ap",
+						FsId:             fsId,
+						DirectoryPerms:   "777",
+						GidMin:           "2000",
+					},
+				}
+
+				ctx := context.Background()
+				_, err := driver.CreateVolume(ctx, req)
+				if err == nil {
+					t.Fatal("CreateVolume did not fail")
+				}
+				mockCtl.Finish()
+			},
+		},
+		{
+			name: "Fail: GidMin must be provided with GidMax",
+			testFunc: func(t *testing.T) {
+				mockCtl := gomock.NewController(t)
+				mockCloud := mocks.NewMockCloud(mockCtl)
+
+				driver := &Driver{
+					endpoint:     endpoint,
+					cloud:        mockCloud,
+					gidAllocator: NewGidAllocator(),
+				}
+
+				req := &csi.CreateVolumeRequest{
+					Name: volumeName,
+					CapacityRange: &csi.CapacityRange{
+						RequiredBytes: capacityRange,
+					},
+					VolumeCapabilities: []*csi.VolumeCapability{
+						stdVolCap,
+					},
+					Parameters: map[string]string{
+						ProvisioningMode: "efs-ap",
+						FsId:             fsId,
+						DirectoryPerms:   "777",
+						GidMax:           "2000",
+					},
+				}
+
+				ctx := context.Background()
+				_, err := driver.CreateVolume(ctx, req)
+				if err == nil {
+					t.Fatal("CreateVolume did not fail")
+				}
+				mockCtl.Finish()
+			},
+		},
+		{
+			name: "Fail: File system does not exist with fixed uid/gid",
+			testFunc: func(t *testing.T) {
+				mockCtl := gomock.NewController(t)
+				mockCloud := mocks.NewMockCloud(mockCtl)
+
+				driver := &Driver{
+					endpoint:     endpoint,
+					cloud:        mockCloud,
+					gidAllocator: NewGidAllocator(),
+				}
+
+				req := &csi.CreateVolumeRequest{
+					Name: volumeName,
+					VolumeCapabilities: []*csi.VolumeCapability{
+						stdVolCap,
+					},
+					CapacityRange: &csi.CapacityRange{
+						RequiredBytes: capacityRange,
+					},
+					Parameters: map[string]string{
+						ProvisioningMode: "efs-ap",
+						FsId:             fsId,
+						GidMin:           "1000",
+						GidMax:           "2000",
+						DirectoryPerms:   "777",
+						Uid:              "1000",
+						Gid:              "1001",
+					},
+				}
+
+				ctx := context.Background()
+				mockCloud.EXPECT().DescribeFileSystem(gomock.Eq(ctx), gomock.Any()).Return(nil, cloud.ErrNotFound)
+				_, err := driver.CreateVolume(ctx, req)
+				if err == nil {
+					t.Fatal("CreateVolume did not fail")
+				}
+				mockCtl.Finish()
+			},
+		},
+		{
+			name: "Success: File system existence check skipped with fixed uid/gid",
+			testFunc: func(t *testing.T) {
+				mockCtl := gomock.NewController(t)
+				mockCloud := mocks.NewMockCloud(mockCtl)
+
+				driver := &Driver{
+					endpoint:             endpoint,
+					cloud:                mockCloud,
+					gidAllocator:         NewGidAllocator(),
+					skipFsExistenceCheck: true,
+				}
+
+				req := &csi.CreateVolumeRequest{
+					Name: volumeName,
+					VolumeCapabilities: []*csi.VolumeCapability{
+						stdVolCap,
+					},
+					CapacityRange: &csi.CapacityRange{
+						RequiredBytes: capacityRange,
+					},
+					Parameters: map[string]string{
+						ProvisioningMode: "efs-ap",
+						FsId:             fsId,
+						DirectoryPerms:   "777",
+						Uid:              "1000",
+						Gid:              "1001",
+					},
+				}
+
+				ctx := context.Background()
+				accessPoint := &cloud.AccessPoint{
+					AccessPointId: apId,
+					FileSystemId:  fsId,
+				}
+				mockCloud.EXPECT().CreateAccessPoint(gomock.Eq(ctx), gomock.Eq(volumeName), gomock.Any(), gomock.Any()).Return(accessPoint, nil)
+				res, err := driver.CreateVolume(ctx, req)
+				if err != nil {
+					t.Fatalf("CreateVolume failed: %v", err)
+				}
+				if res.Volume.VolumeId != volumeId {
+					t.Fatalf("Volume Id mismatched. Expected: %v, Actual: %v", volumeId, res.Volume.VolumeId)
+				}
+				mockCtl.Finish()
+			},
+		},
+		{
+			name: "Fail: File system does not exist with the existence check skipped",
+			testFunc: func(t *testing.T) {
+				mockCtl := gomock.NewController(t)
+				mockCloud := mocks.NewMockCloud(mockCtl)
+
+				driver := &Driver{
+					endpoint:             endpoint,
+					cloud:                mockCloud,
+					gidAllocator:         NewGidAllocator(),
+					skipFsExistenceCheck: true,
+				}
+
+				req := &csi.CreateVolumeRequest{
+					Name: volumeName,
+					VolumeCapabilities: []*csi.VolumeCapability{
+						stdVolCap,
+					},
+					CapacityRange: &csi.CapacityRange{
+						RequiredBytes: capacityRange,
+					},
+					Parameters: map[string]string{
+						ProvisioningMode: "efs-ap",
+						FsId:             fsId,
+						DirectoryPerms:   "777",
+						Uid:              "1000",
+						Gid:              "1001",
+					},
+				}
+
+				ctx := context.Background()
+				mockCloud.EXPECT().CreateAccessPoint(gomock.Eq(ctx), gomock.Eq(volumeName), gomock.Any(), gomock.Any()).Return(nil, cloud.ErrNotFound)
+				_, err := driver.CreateVolume(ctx, req)
+				if status.Code(err) != codes.InvalidArgument {
+					t.Fatalf("Expected error code %v, got: %v", codes.InvalidArgument, err)
+				}
+				mockCtl.Finish()
+			},
+		},
+		{
+			name: "Fail: File system does not exist",
+			testFunc: func(t *testing.T) {
+				mockCtl := gomock.NewController(t)
+				mockCloud := mocks.NewMockCloud(mockCtl)
+
+				driver := &Driver{
+					endpoint:     endpoint,
+					cloud:        mockCloud,
+					gidAllocator: NewGidAllocator(),
+				}
+
+				req := &csi.CreateVolumeRequest{
+					Name: volumeName,
+					VolumeCapabilities: []*csi.VolumeCapability{
+						stdVolCap,
+					},
+					CapacityRange: &csi.CapacityRange{
+						RequiredBytes: capacityRange,
+					},
+					Parameters: map[string]string{
+						ProvisioningMode: "efs-ap",
+						FsId:             fsId,
+						GidMin:           "1000",
+						GidMax:           "2000",
+						DirectoryPerms:   "777",
+					},
+				}
+
+				ctx := context.Background()
+				mockCloud.EXPECT().ListAccessPoints(gomock.Eq(ctx), gomock.Any()).Return(nil, cloud.ErrNotFound)
+				_, err := driver.CreateVolume(ctx, req)
+				if err == nil {
+					t.Fatal("CreateVolume did not fail")
+				}
+				mockCtl.Finish()
+			},
+		},
+		{
+			name: "Fail: DescribeFileSystem Access Denied with fixed uid/gid",
+			testFunc: func(t *testing.T) {
+				mockCtl := gomock.NewController(t)
+				mockCloud := mocks.NewMockCloud(mockCtl)
+
+				driver := &Driver{
+					endpoint:     endpoint,
+					cloud:        mockCloud,
+					gidAllocator: NewGidAllocator(),
+				}
+
+				req := &csi.CreateVolumeRequest{
+					Name: volumeName,
+					VolumeCapabilities: []*csi.VolumeCapability{
+						stdVolCap,
+					},
+					CapacityRange: &csi.CapacityRange{
+						RequiredBytes: capacityRange,
+					},
+					Parameters: map[string]string{
+						ProvisioningMode: "efs-ap",
+						FsId:             fsId,
+						GidMin:           "1000",
+						GidMax:           "2000",
+						DirectoryPerms:   "777",
+						Uid:              "1000",
+						Gid:              "1001",
+					},
+				}
+
+				ctx := context.Background()
+				mockCloud.EXPECT().DescribeFileSystem(gomock.Eq(ctx), gomock.Any()).Return(nil, cloud.ErrAccessDenied)
+				_, err := driver.CreateVolume(ctx, req)
+				if err == nil {
+					t.Fatal("CreateVolume did not fail")
+				}
+				mockCtl.Finish()
+			},
+		},
+		{
+			name: "Fail: DescribeFileSystem Access Denied",
+			testFunc: func(t *testing.T) {
+				mockCtl := gomock.NewController(t)
+				mockCloud := mocks.NewMockCloud(mockCtl)
+
+				driver := &Driver{
+					endpoint:     endpoint,
+					cloud:        mockCloud,
+					gidAllocator: NewGidAllocator(),
+				}
+
+				req := &csi.CreateVolumeRequest{
+					Name: volumeName,
+					VolumeCapabilities: []*csi.VolumeCapability{
+						stdVolCap,
+					},
+					CapacityRange: &csi.CapacityRange{
+						RequiredBytes: capacityRange,
+					},
+					Parameters: map[string]string{
+						ProvisioningMode: "efs-ap",
+						FsId:             fsId,
+						GidMin:           "1000",
+						GidMax:           "2000",
+						DirectoryPerms:   "777",
+					},
+				}
+
+				ctx := context.Background()
+				mockCloud.EXPECT().ListAccessPoints(gomock.Eq(ctx), gomock.Any()).Return(nil, cloud.ErrAccessDenied)
+				_, err := driver.CreateVolume(ctx, req)
+				if err == nil {
+					t.Fatal("CreateVolume did not fail")
+				}
+				mockCtl.Finish()
+			},
+		},
+		{
+			name: "Fail: Describe File system call fails with fixed uid/gid",
+			testFunc: func(t *testing.T) {
+				mockCtl := gomock.NewController(t)
+				mockCloud := mocks.NewMockCloud(mockCtl)
+
+				driver := &Driver{
+					endpoint:     endpoint,
+					cloud:        mockCloud,
+					gidAllocator: NewGidAllocator(),
+				}
+
+				req := &csi.CreateVolumeRequest{
+					Name: volumeName,
+					VolumeCapabilities: []*csi.VolumeCapability{
+						stdVolCap,
+					},
+					CapacityRange: &csi.CapacityRange{
+						RequiredBytes: capacityRange,
+					},
+					Parameters: map[string]string{
+						ProvisioningMode: "efs-ap",
+						FsId:             fsId,
+						GidMin:           "1000",
+						GidMax:           "2000",
+						DirectoryPerms:   "777",
+						Uid:              "1000",
+						Gid:              "1001",
+					},
+				}
+
+				ctx := context.Background()
+				mockCloud.EXPECT().DescribeFileSystem(gomock.Eq(ctx), gomock.Any()).Return(nil, errors.New("DescribeFileSystem failed"))
+				_, err := driver.CreateVolume(ctx, req)
+				if err == nil {
+					t.Fatal("CreateVolume did not fail")
+				}
+				mockCtl.Finish()
+			},
+		},
+		{
+			name: "Fail: List access points call fails",
+			testFunc: func(t *testing.T) {
+				mockCtl := gomock.NewController(t)
+				mockCloud := mocks.NewMockCloud(mockCtl)
+
+				driver := &Driver{
+					endpoint:     endpoint,
+					cloud:        mockCloud,
+					gidAllocator: NewGidAllocator(),
+				}
+
+				req := &csi.CreateVolumeRequest{
+					Name: volumeName,
+					VolumeCapabilities: []*csi.VolumeCapability{
+						stdVolCap,
+					},
+					CapacityRange: &csi.CapacityRange{
+						RequiredBytes: capacityRange,
+					},
+					Parameters: map[string]string{
+						ProvisioningMode: "efs-ap",
+						FsId:             fsId,
+						GidMin:           "1000",
+						GidMax:           "2000",
+						DirectoryPerms:   "777",
+					},
+				}
+
+				ctx := context.Background()
+				mockCloud.EXPECT().ListAccessPoints(gomock.Eq(ctx), gomock.Any()).Return(nil, errors.New("ListAccessPoints failed"))
+				_, err := driver.CreateVolume(ctx, req)
+				if err == nil {
+					t.Fatal("CreateVolume did not fail")
+				}
+				mockCtl.Finish()
+			},
+		},
+		{
+			name: "Fail: Create Access Point call fails",
+			testFunc: func(t *testing.T) {
+				mockCtl := gomock.NewController(t)
+				mockCloud := mocks.NewMockCloud(mockCtl)
+
+				driver := &Driver{
+					endpoint:     endpoint,
+					cloud:        mockCloud,
+					gidAllocator: NewGidAllocator(),
+				}
+
+				req := &csi.CreateVolumeRequest{
+					Name: volumeName,
+					VolumeCapabilities: []*csi.VolumeCapability{
+						stdVolCap,
+					},
+					CapacityRange: &csi.CapacityRange{
+						RequiredBytes: capacityRange,
+					},
+					Parameters: map[string]string{
+						ProvisioningMode: "efs-ap",
+						FsId:             fsId,
+						GidMin:           "1000",
+						GidMax:           "2000",
+						DirectoryPerms:   "777",
+					},
+				}
+
+				ctx := context.Background()
+				mockCloud.EXPECT().ListAccessPoints(gomock.Eq(ctx), gomock.Any()).Return([]*cloud.AccessPoint{}, nil)
+				mockCloud.EXPECT().CreateAccessPoint(gomock.Eq(ctx), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, errors.New("CreateAccessPoint call failed"))
+				_, err := driver.CreateVolume(ctx, req)
+				if err == nil {
+					t.Fatal("CreateVolume did not fail")
+				}
+				mockCtl.Finish()
+			},
+		},
+		{
+			name: "Success: Access point already exists with matching parameters",
+			testFunc: func(t *testing.T) {
+				mockCtl := gomock.NewController(t)
+				mockCloud := mocks.NewMockCloud(mockCtl)
+
+				driver := &Driver{
+					endpoint:     endpoint,
+					cloud:        mockCloud,
+					gidAllocator: NewGidAllocator(),
+				}
+
+				req := &csi.CreateVolumeRequest{
+					Name: volumeName,
+					VolumeCapabilities: []*csi.VolumeCapability{
+						stdVolCap,
+					},
+					CapacityRange: &csi.CapacityRange{
+						RequiredBytes: capacityRange,
+					},
+					Parameters: map[string]string{
+						ProvisioningMode: "efs-ap",
+						FsId:             fsId,
+						DirectoryPerms:   "777",
+						BasePath:         "/test",
+						Uid:              "1000",
+						Gid:              "1001",
+					},
+				}
+
+				ctx := context.Background()
+				fileSystem := &cloud.FileSystem{
+					FileSystemId: fsId,
+				}
+				existingAccessPoint := &cloud.AccessPoint{
+					AccessPointId:      apId,
+					FileSystemId:       fsId,
+					AccessPointRootDir: "/test/" + volumeName,
+					PosixUser: &cloud.PosixUser{
+						Gid: 1001,
+						Uid: 1000,
+					},
+				}
+				mockCloud.EXPECT().DescribeFileSystem(gomock.Eq(ctx), gomock.Any()).Return(fileSystem, nil)
+				mockCloud.EXPECT().CreateAccessPoint(gomock.Eq(ctx), gomock.Eq(volumeName), gomock.Any(), gomock.Any()).Return(nil, cloud.ErrAlreadyExists)
+				mockCloud.EXPECT().FindAccessPointByClientToken(gomock.Eq(ctx), gomock.Eq(volumeName), gomock.Any()).Return(existingAccessPoint, nil)
+
+				res, err := driver.CreateVolume(ctx, req)
+				if err != nil {
+					t.Fatalf("CreateVolume failed: %v", err)
+				}
+
+				if res.Volume.VolumeId != volumeId {
+					t.Fatalf("Volume Id mismatched. Expected: %v, Actual: %v", volumeId, res.Volume.VolumeId)
+				}
+				mockCtl.Finish()
+			},
+		},
+		{
+			name: "Fail: Access point already exists with conflicting parameters",
+			testFunc: func(t *testing.T) {
+				mockCtl := gomock.NewController(t)
+				mockCloud := mocks.NewMockCloud(mockCtl)
+
+				driver := &Driver{
+					endpoint:     endpoint,
+					cloud:        mockCloud,
+					gidAllocator: NewGidAllocator(),
+				}
+
+				req := &csi.CreateVolumeRequest{
+					Name: volumeName,
+					VolumeCapabilities: []*csi.VolumeCapability{
+						stdVolCap,
+					},
+					CapacityRange: &csi.CapacityRange{
+						RequiredBytes: capacityRange,
+					},
+					Parameters: map[string]string{
+						ProvisioningMode: "efs-ap",
+						FsId:             fsId,
+						DirectoryPerms:   "777",
+						BasePath:         "/test",
+						Uid:              "1000",
+						Gid:              "1001",
+					},
+				}
+
+				ctx := context.Background()
+				fileSystem := &cloud.FileSystem{
+					FileSystemId: fsId,
+				}
+				existingAccessPoint := &cloud.AccessPoint{
+					AccessPointId:      apId,
+					FileSystemId:       fsId,
+					AccessPointRootDir: "/test/" + volumeName,
+					PosixUser: &cloud.PosixUser{
+						Gid: 2002,
+						Uid: 1000,
+					},
+				}
+				mockCloud.EXPECT().DescribeFileSystem(gomock.Eq(ctx), gomock.Any()).Return(fileSystem, nil)
+				mockCloud.EXPECT().CreateAccessPoint(gomock.Eq(ctx), gomock.Eq(volumeName), gomock.Any(), gomock.Any()).Return(nil, cloud.ErrAlreadyExists)
+				mockCloud.EXPECT().FindAccessPointByClientToken(gomock.Eq(ctx), gomock.Eq(volumeName), gomock.Any()).Return(existingAccessPoint, nil)
+
+				res, err := driver.CreateVolume(ctx, req)
+				if err == nil {
+					t.Fatalf("CreateVolume did not fail, got: %v", res)
+				}
+				if status.Code(err) != codes.AlreadyExists {
+					t.Fatalf("Expected error code %v, got: %v", codes.AlreadyExists, status.Code(err))
+				}
+				mockCtl.Finish()
+			},
+		},
+		{
+			name: "Fail: Access point already exists with another root directory",
+			testFunc: func(t *testing.T) {
+				mockCtl := gomock.NewController(t)
+				mockCloud := mocks.NewMockCloud(mockCtl)
+
+				driver := &Driver{
+					endpoint:     endpoint,
+					cloud:        mockCloud,
+					gidAllocator: NewGidAllocator(),
+				}
+
+				req := &csi.CreateVolumeRequest{
+					Name: volumeName,
+					VolumeCapabilities: []*csi.VolumeCapability{
+						stdVolCap,
+					},
+					CapacityRange: &csi.CapacityRange{
+						RequiredBytes: capacityRange,
+					},
+					Parameters: map[string]string{
+						ProvisioningMode: "efs-ap",
+						FsId:             fsId,
+						DirectoryPerms:   "777",
+						BasePath:         "/test",
+						Uid:              "1000",
+						Gid:              "1001",
+					},
+				}
+
+				ctx := context.Background()
+				fileSystem := &cloud.FileSystem{
+					FileSystemId: fsId,
+				}
+				existingAccessPoint := &cloud.AccessPoint{
+					AccessPointId:      apId,
+					FileSystemId:       fsId,
+					AccessPointRootDir: "/other/" + volumeName,
+					PosixUser: &cloud.PosixUser{
+						Gid: 1001,
+						Uid: 1000,
+					},
+				}
+				mockCloud.EXPECT().DescribeFileSystem(gomock.Eq(ctx), gomock.Any()).Return(fileSystem, nil)
+				mockCloud.EXPECT().CreateAccessPoint(gomock.Eq(ctx), gomock.Eq(volumeName), gomock.Any(), gomock.Any()).Return(nil, cloud.ErrAlreadyExists)
+				mockCloud.EXPECT().FindAccessPointByClientToken(gomock.Eq(ctx), gomock.Eq(volumeName), gomock.Any()).Return(existingAccessPoint, nil)
+
+				res, err := driver.CreateVolume(ctx, req)
+				if err == nil {
+					t.Fatalf("CreateVolume did not fail, got: %v", res)
+				}
+				if status.Code(err) != codes.AlreadyExists {
+					t.Fatalf("Expected error code %v, got: %v", codes.AlreadyExists, status.Code(err))
+				}
+				mockCtl.Finish()
+			},
+		},
+		{
+			name: "Fail: CreateAccessPoint Access Denied",
+			testFunc: func(t *testing.T) {
+				mockCtl := gomock.NewController(t)
+				mockCloud := mocks.NewMockCloud(mockCtl)
+
+				driver := &Driver{
+					endpoint:     endpoint,
+					cloud:        mockCloud,
+					gidAllocator: NewGidAllocator(),
+				}
+
+				req := &csi.CreateVolumeRequest{
+					Name: volumeName,
+					VolumeCapabilities: []*csi.VolumeCapability{
+						stdVolCap,
+					},
+					CapacityRange: &csi.CapacityRange{
+						RequiredBytes: capacityRange,
+					},
+					Parameters: map[string]string{
+						ProvisioningMode: "efs-ap",
+						FsId:             fsId,
+						GidMin:           "1000",
+						GidMax:           "2000",
+						DirectoryPerms:   "777",
+					},
+				}
+
+				ctx := context.Background()
+				mockCloud.EXPECT().ListAccessPoints(gomock.Eq(ctx), gomock.Any()).Return([]*cloud.AccessPoint{}, nil)
+				mockCloud.EXPECT().CreateAccessPoint(gomock.Eq(ctx), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, cloud.ErrAccessDenied)
+				_, err := driver.CreateVolume(ctx, req)
+				if err == nil {
+					t.Fatal("CreateVolume did not fail")
+				}
+				mockCtl.Finish()
+			},
+		},
+		{
+			name: "Fail: az has no mount target",
+			testFunc: func(t *testing.T) {
+				mockCtl := gomock.NewController(t)
+				mockCloud := mocks.NewMockCloud(mockCtl)
+
+				driver := &Driver{
+					endpoint:     endpoint,
+					cloud:        mockCloud,
+					gidAllocator: NewGidAllocator(),
+				}
+
+				req := &csi.CreateVolumeRequest{
+					Name: volumeName,
+					VolumeCapabilities: []*csi.VolumeCapability{
+						stdVolCap,
+					},
+					CapacityRange: &csi.CapacityRange{
+						RequiredBytes: capacityRange,
+					},
+					Parameters: map[string]string{
+						ProvisioningMode: "efs-ap",
+						FsId:             fsId,
+						DirectoryPerms:   "777",
+						AzName:           "us-east-1c",
+					},
+				}
+
+				ctx := context.Background()
+				// DescribeMountTargets falls back to a random mount target when the az has none
+				mountTarget := &cloud.MountTarget{
+					AZName:        "us-east-1a",
+					AZId:          "mock-AZ-id",
+					MountTargetId: "fsmt-abcd1234",
+					IPAddress:     "127.0.0.1",
+				}
+				mockCloud.EXPECT().ListAccessPoints(gomock.Eq(ctx), gomock.Any()).Return(nil, nil)
+				mockCloud.EXPECT().DescribeMountTargets(gomock.Eq(ctx), gomock.Eq(fsId), gomock.Eq("us-east-1c")).Return(mountTarget, nil)
+
+				_, err := driver.CreateVolume(ctx, req)
+				if status.Code(err) != codes.InvalidArgument {
+					t.Fatalf("Expected InvalidArgument, got: %v", err)
+				}
+				mockCtl.Finish()
+			},
+		},
+		{
+			name: "Fail: Describe mount targets for az fails",
+			testFunc: func(t *testing.T) {
+				mockCtl := gomock.NewController(t)
+				mockCloud := mocks.NewMockCloud(mockCtl)
+
+				driver := &Driver{
+					endpoint:     endpoint,
+					cloud:        mockCloud,
+					gidAllocator: NewGidAllocator(),
+				}
+
+				req := &csi.CreateVolumeRequest{
+					Name: volumeName,
+					VolumeCapabilities: []*csi.VolumeCapability{
+						stdVolCap,
+					},
+					CapacityRange: &csi.CapacityRange{
+						RequiredBytes: capacityRange,
+					},
+					Parameters: map[string]string{
+						ProvisioningMode: "efs-ap",
+						FsId:             fsId,
+						DirectoryPerms:   "777",
+						AzName:           "us-east-1a",
+					},
+				}
+
+				ctx := context.Background()
+				mockCloud.EXPECT().ListAccessPoints(gomock.Eq(ctx), gomock.Any()).Return(nil, nil)
+				mockCloud.EXPECT().DescribeMountTargets(gomock.Eq(ctx), gomock.Eq(fsId), gomock.Eq("us-east-1a")).Return(nil, cloud.ErrAccessDenied)
+
+				_, err := driver.CreateVolume(ctx, req)
+				if status.Code(err) != codes.Unauthenticated {
+					t.Fatalf("Expected Unauthenticated, got: %v", err)
+				}
+				mockCtl.Finish()
+			},
+		},
+		{
+			name: "Success: Using the regional client of the region parameter",
+			testFunc: func(t *testing.T) {
+				mockCtl := gomock.NewController(t)
+				mockCloud := mocks.NewMockCloud(mockCtl)
+				regionalCloud := mocks.NewMockCloud(mockCtl)
+
+				driver := &Driver{
+					endpoint:     endpoint,
+					cloud:        mockCloud,
+					gidAllocator: NewGidAllocator(),
+				}
+
+				req := &csi.CreateVolumeRequest{
+					Name: volumeName,
+					VolumeCapabilities: []*csi.VolumeCapability{
+						stdVolCap,
+					},
+					CapacityRange: &csi.CapacityRange{
+						RequiredBytes: capacityRange,
+					},
+					Parameters: map[string]string{
+						ProvisioningMode: "efs-ap",
+						FsId:             fsId,
+						DirectoryPerms:   "777",
+						Region:           "eu-west-1",
+					},
+				}
+
+				ctx := context.Background()
+				accessPoint := &cloud.AccessPoint{
+					AccessPointId:  apId,
+					AccessPointArn: "arn:aws:elasticfilesystem:eu-west-1:123456789012:access-point/" + apId,
+					FileSystemId:   fsId,
+				}
+				mockCloud.EXPECT().WithRegion(gomock.Eq("eu-west-1")).Return(regionalCloud, nil)
+				regionalCloud.EXPECT().ListAccessPoints(gomock.Eq(ctx), gomock.Eq(fsId)).Return(nil, nil)
+				regionalCloud.EXPECT().CreateAccessPoint(gomock.Eq(ctx), gomock.Eq(volumeName), gomock.Any(), gomock.Eq(false)).Return(accessPoint, nil)
+
+				res, err := driver.CreateVolume(ctx, req)
+				if err != nil {
+					t.Fatalf("CreateVolume failed: %v", err)
+				}
+				if res.Volume.VolumeContext[Region] != "eu-west-1" {
+					t.Fatalf("Volume context region mismatched. Expected: %v, Actual: %v", "eu-west-1", res.Volume.VolumeContext[Region])
+				}
+				// The volume ID records the region for DeleteVolume
+				expectedVolumeId := "arn:aws:elasticfilesystem:eu-west-1:123456789012:file-system/" + fsId + "::" + apId
+				if res.Volume.VolumeId != expectedVolumeId {
+					t.Fatalf("Volume Id mismatched. Expected: %v, Actual: %v", expectedVolumeId, res.Volume.VolumeId)
+				}
+				mockCtl.Finish()
+			},
+		},
+		{
+			name: "Fail: Unknown region",
+			testFunc: func(t *testing.T) {
+				mockCtl := gomock.NewController(t)
+				mockCloud := mocks.NewMockCloud(mockCtl)
+
+				driver := &Driver{
+					endpoint:     endpoint,
+					cloud:        mockCloud,
+					gidAllocator: NewGidAllocator(),
+				}
+
+				req := &csi.CreateVolumeRequest{
+					Name: volumeName,
+					VolumeCapabilities: []*csi.VolumeCapability{
+						stdVolCap,
+					},
+					CapacityRange: &csi.CapacityRange{
+						RequiredBytes: capacityRange,
+					},
+					Parameters: map[string]string{
+						ProvisioningMode: "efs-ap",
+						FsId:             fsId,
+						DirectoryPerms:   "777",
+						Region:           "moon-east-1",
+					},
+				}
+
+				ctx := context.Background()
+				mockCloud.EXPECT().WithRegion(gomock.Eq("moon-east-1")).Return(nil, cloud.ErrInvalidRegion)
+
+				_, err := driver.CreateVolume(ctx, req)
+				if status.Code(err) != codes.InvalidArgument {
+					t.Fatalf("Expected InvalidArgument, got: %v", err)
+				}
+				mockCtl.Finish()
+			},
+		},
+		{
+			name: "Success: Provisioning with the PVC namespace when untagged provisioning is denied",
+			testFunc: func(t *testing.T) {
+				mockCtl := gomock.NewController(t)
+				mockCloud := mocks.NewMockCloud(mockCtl)
+
+				driver := &Driver{
+					endpoint:     endpoint,
+					cloud:        mockCloud,
+					gidAllocator: NewGidAllocator(),
+					denyUntagged: true,
+				}
+
+				req := &csi.CreateVolumeRequest{
+					Name: volumeName,
+					VolumeCapabilities: []*csi.VolumeCapability{
+						stdVolCap,
+					},
+					CapacityRange: &csi.CapacityRange{
+						RequiredBytes: capacityRange,
+					},
+					Parameters: map[string]string{
+						ProvisioningMode: "efs-ap",
+						FsId:             fsId,
+						DirectoryPerms:   "777",
+						PvcNamespace:     "team-a",
+					},
+				}
+
+				ctx := context.Background()
+				accessPoint := &cloud.AccessPoint{
+					AccessPointId: apId,
+					FileSystemId:  fsId,
+				}
+				mockCloud.EXPECT().ListAccessPoints(gomock.Eq(ctx), gomock.Eq(fsId)).Return(nil, nil)
+				mockCloud.EXPECT().CreateAccessPoint(gomock.Eq(ctx), gomock.Eq(volumeName), gomock.Any(), gomock.Eq(false)).Return(accessPoint, nil)
+
+				_, err := driver.CreateVolume(ctx, req)
+				if err != nil {
+					t.Fatalf("CreateVolume failed: %v", err)
+				}
+				mockCtl.Finish()
+			},
+		},
+		{
+			name: "Fail: Provisioning without the PVC namespace when untagged provisioning is denied",
+			testFunc: func(t *testing.T) {
+				mockCtl := gomock.NewController(t)
+				mockCloud := mocks.NewMockCloud(mockCtl)
+
+				driver := &Driver{
+					endpoint:     endpoint,
+					cloud:        mockCloud,
+					gidAllocator: NewGidAllocator(),
+					denyUntagged: true,
+				}
+
+				req := &csi.CreateVolumeRequest{
+					Name: volumeName,
+					VolumeCapabilities: []*csi.VolumeCapability{
+						stdVolCap,
+					},
+					CapacityRange: &csi.CapacityRange{
+						RequiredBytes: capacityRange,
+					},
+					Parameters: map[string]string{
+						ProvisioningMode: "efs-ap",
+						FsId:             fsId,
+						DirectoryPerms:   "777",
+					},
+				}
+
+				ctx := context.Background()
+				_, err := driver.CreateVolume(ctx, req)
+				if status.Code(err) != codes.InvalidArgument {
+					t.Fatalf("Expected InvalidArgument, got: %v", err)
+				}
+				mockCtl.Finish()
+			},
+		},
+		{
+			name: "Fail: CreateAccessPoint error keeps the AWS request ID",
+			testFunc: func(t *testing.T) {
+				mockCtl := gomock.NewController(t)
+				mockCloud := mocks.NewMockCloud(mockCtl)
+
+				driver := &Driver{
+					endpoint:     endpoint,
+					cloud:        mockCloud,
+					gidAllocator: NewGidAllocator(),
+				}
+
+				req := &csi.CreateVolumeRequest{
+					Name: volumeName,
+					VolumeCapabilities: []*csi.VolumeCapability{
+						stdVolCap,
+					},
+					CapacityRange: &csi.CapacityRange{
+						RequiredBytes: capacityRange,
+					},
+					Parameters: map[string]string{
+						ProvisioningMode: "efs-ap",
+						FsId:             fsId,
+						DirectoryPerms:   "777",
+					},
+				}
+
+				ctx := context.Background()
+				requestId := "c3b5bd2e-6a5e-4b0b-9d1a-1c0f7d6e2a51"
+				createErr := &cloud.RequestError{
+					Message:   "Failed to create access point",
+					RequestID: requestId,
+					Err:       awserr.NewRequestFailure(awserr.New("InternalServerError", "Internal Server Error", nil), 500, requestId),
+				}
+				mockCloud.EXPECT().ListAccessPoints(gomock.Eq(ctx), gomock.Eq(fsId)).Return(nil, nil)
+				mockCloud.EXPECT().CreateAccessPoint(gomock.Eq(ctx), gomock.Eq(volumeName), gomock.Any(), gomock.Eq(false)).Return(nil, createErr)
+
+				_, err := driver.CreateVolume(ctx, req)
+				if status.Code(err) != codes.Internal {
+					t.Fatalf("Expected Internal, got: %v", err)
+				}
+				if !strings.Contains(status.Convert(err).Message(), requestId) {
+					t.Fatalf("Error message does not contain the request ID: %v", err)
+				}
+				mockCtl.Finish()
+			},
+		},
+		{
+			name: "Success: Requested size is propagated with enforceQuota",
+			testFunc: func(t *testing.T) {
+				mockCtl := gomock.NewController(t)
+				mockCloud := mocks.NewMockCloud(mockCtl)
+
+				driver := &Driver{
+					endpoint:     endpoint,
+					cloud:        mockCloud,
+					gidAllocator: NewGidAllocator(),
+				}
+
+				req := &csi.CreateVolumeRequest{
+					Name: volumeName,
+					VolumeCapabilities: []*csi.VolumeCapability{
+						stdVolCap,
+					},
+					CapacityRange: &csi.CapacityRange{
+						RequiredBytes: capacityRange,
+					},
+					Parameters: map[string]string{
+						ProvisioningMode: "efs-ap",
+						FsId:             fsId,
+						DirectoryPerms:   "777",
+						EnforceQuota:     "true",
+					},
+				}
+
+				ctx := context.Background()
+				accessPoint := &cloud.AccessPoint{
+					AccessPointId: apId,
+					FileSystemId:  fsId,
+				}
+				mockCloud.EXPECT().ListAccessPoints(gomock.Eq(ctx), gomock.Eq(fsId)).Return(nil, nil)
+				mockCloud.EXPECT().CreateAccessPoint(gomock.Eq(ctx), gomock.Eq(volumeName), gomock.Any(), gomock.Eq(false)).DoAndReturn(
+					func(ctx context.Context, clientToken string, accessPointOpts *cloud.AccessPointOptions, reuseAccessPoint bool) (*cloud.AccessPoint, error) {
+						if accessPointOpts.QuotaBytes != capacityRange {
+							t.Fatalf("QuotaBytes mismatched. Expected: %v, Actual: %v", capacityRange, accessPointOpts.QuotaBytes)
+						}
+						return accessPoint, nil
+					})
+
+				res, err := driver.CreateVolume(ctx, req)
+				if err != nil {
+					t.Fatalf("CreateVolume failed: %v", err)
+				}
+				if res.Volume.VolumeContext[QuotaBytes] != strconv.FormatInt(capacityRange, 10) {
+					t.Fatalf("Volume context quota mismatched. Expected: %v, Actual: %v", capacityRange, res.Volume.VolumeContext[QuotaBytes])
+				}
+				mockCtl.Finish()
+			},
+		},
+		{
+			name: "Success: Requested size is not propagated without enforceQuota",
+			testFunc: func(t *testing.T) {
+				mockCtl := gomock.NewController(t)
+				mockCloud := mocks.NewMockCloud(mockCtl)
+
+				driver := &Driver{
+					endpoint:     endpoint,
+					cloud:        mockCloud,
+					gidAllocator: NewGidAllocator(),
+				}
+
+				req := &csi.CreateVolumeRequest{
+					Name: volumeName,
+					VolumeCapabilities: []*csi.VolumeCapability{
+						stdVolCap,
+					},
+					CapacityRange: &csi.CapacityRange{
+						RequiredBytes: capacityRange,
+					},
+					Parameters: map[string]string{
+						ProvisioningMode: "efs-ap",
+						FsId:             fsId,
+						DirectoryPerms:   "777",
+					},
+				}
+
+				ctx := context.Background()
+				accessPoint := &cloud.AccessPoint{
+					AccessPointId: apId,
+					FileSystemId:  fsId,
+				}
+				mockCloud.EXPECT().ListAccessPoints(gomock.Eq(ctx), gomock.Eq(fsId)).Return(nil, nil)
+				mockCloud.EXPECT().CreateAccessPoint(gomock.Eq(ctx), gomock.Eq(volumeName), gomock.Any(), gomock.Eq(false)).DoAndReturn(
+					func(ctx context.Context, clientToken string, accessPointOpts *cloud.AccessPointOptions, reuseAccessPoint bool) (*cloud.AccessPoint, error) {
+						if accessPointOpts.QuotaBytes != 0 {
+							t.Fatalf("QuotaBytes mismatched. Expected: 0, Actual: %v", accessPointOpts.QuotaBytes)
+						}
+						return accessPoint, nil
+					})
+
+				res, err := driver.CreateVolume(ctx, req)
+				if err != nil {
+					t.Fatalf("CreateVolume failed: %v", err)
+				}
+				if _, ok := res.Volume.VolumeContext[QuotaBytes]; ok {
+					t.Fatalf("Volume context unexpectedly contains %v", QuotaBytes)
+				}
+				mockCtl.Finish()
+			},
+		},
+		{
+			name: "Fail: enforceQuota with accessPointShareKey",
+			testFunc: func(t *testing.T) {
+				mockCtl := gomock.NewController(t)
+				mockCloud := mocks.NewMockCloud(mockCtl)
+
+				driver := &Driver{
+					endpoint:     endpoint,
+					cloud:        mockCloud,
+					gidAllocator: NewGidAllocator(),
+				}
+
+				req := &csi.CreateVolumeRequest{
+					Name: volumeName,
+					VolumeCapabilities: []*csi.VolumeCapability{
+						stdVolCap,
+					},
+					CapacityRange: &csi.CapacityRange{
+						RequiredBytes: capacityRange,
+					},
+					Parameters: map[string]string{
+						ProvisioningMode:    "efs-ap",
+						FsId:                fsId,
+						DirectoryPerms:      "777",
+						EnforceQuota:        "true",
+						ReuseAccessPointKey: "true",
+						AccessPointShareKey: "shared",
+					},
+				}
+
+				ctx := context.Background()
+				_, err := driver.CreateVolume(ctx, req)
+				if status.Code(err) != codes.InvalidArgument {
+					t.Fatalf("Expected InvalidArgument, got: %v", err)
+				}
+				mockCtl.Finish()
+			},
+		},
+		{
+			name: "Success: Default tag is omitted from access points when disabled",
+			testFunc: func(t *testing.T) {
+				mockCtl := gomock.NewController(t)
+				mockCloud := mocks.NewMockCloud(mockCtl)
+
+				driver := &Driver{
+					endpoint:           endpoint,
+					cloud:              mockCloud,
+					gidAllocator:       NewGidAllocator(),
+					tags:               parseTagsFromStr("cost-center:storage"),
+					disableDefaultTags: true,
+				}
+
+				req := &csi.CreateVolumeRequest{
+					Name: volumeName,
+					VolumeCapabilities: []*csi.VolumeCapability{
+						stdVolCap,
+					},
+					CapacityRange: &csi.CapacityRange{
+						RequiredBytes: capacityRange,
+					},
+					Parameters: map[string]string{
+						ProvisioningMode: "efs-ap",
+						FsId:             fsId,
+						DirectoryPerms:   "777",
+					},
+				}
+
+				ctx := context.Background()
+				accessPoint := &cloud.AccessPoint{
+					AccessPointId: apId,
+					FileSystemId:  fsId,
+				}
+				mockCloud.EXPECT().ListAccessPoints(gomock.Eq(ctx), gomock.Eq(fsId)).Return(nil, nil)
+				mockCloud.EXPECT().CreateAccessPoint(gomock.Eq(ctx), gomock.Eq(volumeName), gomock.Any(), gomock.Eq(false)).DoAndReturn(
+					func(ctx context.Context, clientToken string, accessPointOpts *cloud.AccessPointOptions, reuseAccessPoint bool) (*cloud.AccessPoint, error) {
+						if _, ok := accessPointOpts.Tags[DefaultTagKey]; ok {
+							t.Fatalf("Default tag was added to access point tags: %v", accessPointOpts.Tags)
+						}
+						if accessPointOpts.Tags["cost-center"] != "storage" {
+							t.Fatalf("Tags mismatched. Expected cost-center tag in %v", accessPointOpts.Tags)
+						}
+						return accessPoint, nil
+					})
+
+				_, err := driver.CreateVolume(ctx, req)
+				if err != nil {
+					t.Fatalf("CreateVolume failed: %v", err)
+				}
+				mockCtl.Finish()
+			},
+		},
+		{
+			name: "Success: Default tag is omitted from file systems when disabled",
+			testFunc: func(t *testing.T) {
+				mockCtl := gomock.NewController(t)
+				mockCloud := mocks.NewMockCloud(mockCtl)
+
+				driver := &Driver{
+					endpoint:           endpoint,
+					cloud:              mockCloud,
+					gidAllocator:       NewGidAllocator(),
+					tags:               parseTagsFromStr("cost-center:storage"),
+					disableDefaultTags: true,
+				}
+
+				req := &csi.CreateVolumeRequest{
+					Name: volumeName,
+					VolumeCapabilities: []*csi.VolumeCapability{
+						stdVolCap,
+					},
+					CapacityRange: &csi.CapacityRange{
+						RequiredBytes: capacityRange,
+					},
+					Parameters: map[string]string{
+						ProvisioningMode: FileSystemMode,
+					},
+				}
+
+				ctx := context.Background()
+				fileSystem := &cloud.FileSystem{
+					FileSystemId: fsId,
+				}
+				mockCloud.EXPECT().CreateFileSystem(gomock.Eq(ctx), gomock.Eq(volumeName), gomock.Any()).DoAndReturn(
+					func(ctx context.Context, clientToken string, fileSystemOpts *cloud.FileSystemOptions) (*cloud.FileSystem, error) {
+						if _, ok := fileSystemOpts.Tags[DefaultTagKey]; ok {
+							t.Fatalf("Default tag was added to file system tags: %v", fileSystemOpts.Tags)
+						}
+						if fileSystemOpts.Tags["cost-center"] != "storage" {
+							t.Fatalf("Tags mismatched. Expected cost-center tag in %v", fileSystemOpts.Tags)
+						}
+						// The tag DeleteVolume recognizes the file system by is never disabled
+						if fileSystemOpts.Tags[FsVolumeTagKey] != volumeName {
+							t.Fatalf("Tags mismatched. Expected %v tag in %v", FsVolumeTagKey, fileSystemOpts.Tags)
+						}
+						return fileSystem, nil
+					})
+
+				_, err := driver.CreateVolume(ctx, req)
+				if err != nil {
+					t.Fatalf("CreateVolume failed: %v", err)
+				}
+				mockCtl.Finish()
+			},
+		},
+		{
+			name: "Fail: Run out of GIDs",
+			testFunc: func(t *testing.T) {
+				mockCtl := gomock.NewController(t)
+				mockCloud := mocks.NewMockCloud(mockCtl)
+
+				driver := &Driver{
+					endpoint:     endpoint,
+					cloud:        mockCloud,
+					gidAllocator: NewGidAllocator(),
+				}
+
+				req := &csi.CreateVolumeRequest{
+					Name: volumeName,
+					VolumeCapabilities: []*csi.VolumeCapability{
+						stdVolCap,
+					},
+					CapacityRange: &csi.CapacityRange{
+						RequiredBytes: capacityRange,
+					},
+					Parameters: map[string]string{
+						ProvisioningMode: "efs-ap",
+						FsId:             fsId,
+						GidMin:           "1000",
+						GidMax:           "1001",
+						DirectoryPerms:   "777",
+					},
+				}
+
+				ctx := context.Background()
+				ap1 := &cloud.AccessPoint{
+					AccessPointId: apId,
+					FileSystemId:  fsId,
+					PosixUser: &cloud.PosixUser{
+						Gid: 1000,
+						Uid: 1000,
+					},
+				}
+				ap2 := &cloud.AccessPoint{
+					AccessPointId: apId,
+					FileSystemId:  fsId,
+					PosixUser: &cloud.PosixUser{
+						Gid: 1001,
+						Uid: 1001,
+					},
+				}
+				mockCloud.EXPECT().ListAccessPoints(gomock.Eq(ctx), gomock.Any()).Return([]*cloud.AccessPoint{ap1, ap2}, nil).AnyTimes()
+				mockCloud.EXPECT().CreateAccessPoint(gomock.Eq(ctx), gomock.Any(), gomock.Any(), gomock.Any()).Return(ap2, nil).AnyTimes()
+
+				var err error
+				// All GIDs from available range are taken, CreateVolume should fail.
+				_, err = driver.CreateVolume(ctx, req)
+
+				if err == nil {
+					t.Fatalf("CreateVolume did not fail")
+				}
+				if status.Code(err) != codes.ResourceExhausted {
+					t.Fatalf("Expected ResourceExhausted, got: %v", err)
+				}
+				if !strings.Contains(err.Error(), fsId) || !strings.Contains(err.Error(), "1000-1001") {
+					t.Fatalf("Error does not name the file system and GID range: %v", err)
+				}
+				mockCtl.Finish()
+			},
+		},
+		{
+			name: "Fail: Cannot assume role for x-account",
+			testFunc: func(t *testing.T) {
+				mockCtl := gomock.NewController(t)
+				mockCloud := mocks.NewMockCloud(mockCtl)
+
+				driver := &Driver{
+					endpoint:     endpoint,
+					cloud:        mockCloud,
+					gidAllocator: NewGidAllocator(),
+					tags:         parseTagsFromStr(""),
+				}
+
+				secrets := map[string]string{}
+				secrets["awsRoleArn"] = "arn:aws:iam::1234567890:role/EFSCrossAccountRole"
+
+				req := &csi.CreateVolumeRequest{
+					Name: volumeName,
+					VolumeCapabilities: []*csi.VolumeCapability{
+						stdVolCap,
+					},
+					CapacityRange: &csi.CapacityRange{
+						RequiredBytes: capacityRange,
+					},
+					Parameters: map[string]string{
+						ProvisioningMode: "efs-ap",
+						FsId:             fsId,
+						GidMin:           "1000",
+						GidMax:           "2000",
+						DirectoryPerms:   "777",
+						AzName:           "us-east-1a",
+					},
+					Secrets: secrets,
+				}
+
+				ctx := context.Background()
+
+				_, err := driver.CreateVolume(ctx, req)
+
+				if err == nil {
+					t.Fatalf("CreateVolume did not fail")
+				}
+
+				mockCtl.Finish()
+			},
+		},
+		{
+			name: "Fail: subPathPattern is specified but uses unsupported attributes",
+			testFunc: func(t *testing.T) {
+				mockCtl := gomock.NewController(t)
+				mockCloud := mocks.NewMockCloud(mockCtl)
+
+				subPathPattern := "${.PVC.name}/${foo}"
+
+				driver := &Driver{
+					endpoint:     endpoint,
+					cloud:        mockCloud,
+					gidAllocator: NewGidAllocator(),
+				}
+
+				req := &csi.CreateVolumeRequest{
+					Name: volumeName,
+					VolumeCapabilities: []*csi.VolumeCapability{
+						stdVolCap,
+					},
+					CapacityRange: &csi.CapacityRange{
+						RequiredBytes: capacityRange,
+					},
+					Parameters: map[string]string{
+						ProvisioningMode: "efs-ap",
+						FsId:             fsId,
+						DirectoryPerms:   "777",
+						SubPathPattern:   subPathPattern,
+					},
+				}
+
+				ctx := context.Background()
+
+				mockCloud.EXPECT().ListAccessPoints(gomock.Eq(ctx), gomock.Any()).Return(nil, nil)
+
+				_, err := driver.CreateVolume(ctx, req)
+				if err == nil {
+					t.Fatal("CreateVolume did not fail")
+				}
+				if status.Code(err) != codes.InvalidArgument {
+					t.Fatalf("Did not throw InvalidArgument error, instead threw %v", err)
+				}
+				mockCtl.Finish()
+			},
+		},
+		{
+			name: "Fail: resulting accessPointDirectory is too over 100 characters",
+			testFunc: func(t *testing.T) {
+				mockCtl := gomock.NewController(t)
+				mockCloud := mocks.NewMockCloud(mockCtl)
+
+				subPathPattern := "this-directory-name-is-far-too-long-for-any-practical-purposes-and-only-serves-to-prove-a-point"
+
+				driver := &Driver{
+					endpoint:     endpoint,
+					cloud:        mockCloud,
+					gidAllocator: NewGidAllocator(),
+				}
+
+				req := &csi.CreateVolumeRequest{
+					Name: volumeName,
+					VolumeCapabilities: []*csi.VolumeCapability{
+						stdVolCap,
+					},
+					CapacityRange: &csi.CapacityRange{
+						RequiredBytes: capacityRange,
+					},
+					Parameters: map[string]string{
+						ProvisioningMode: "efs-ap",
+						FsId:             fsId,
+						DirectoryPerms:   "777",
+						SubPathPattern:   subPathPattern,
+					},
+				}
+
+				ctx := context.Background()
+
+				mockCloud.EXPECT().ListAccessPoints(gomock.Eq(ctx), gomock.Any()).Return(nil, nil)
+
+				_, err := driver.CreateVolume(ctx, req)
+				if err == nil {
+					t.Fatal("CreateVolume did not fail")
+				}
+				if status.Code(err) != codes.InvalidArgument {
+					t.Fatalf("Did not throw InvalidArgument error, instead threw %v", err)
+				}
+				mockCtl.Finish()
+			},
+		},
+		{
+			name: "Fail:  resulting accessPointDirectory contains over 4 subdirectories",
+			testFunc: func(t *testing.T) {
+				mockCtl := gomock.NewController(t)
+				mockCloud := mocks.NewMockCloud(mockCtl)
+
+				subPathPattern := "a/b/c/d/e/f"
+
+				driver := &Driver{
+					endpoint:     endpoint,
+					cloud:        mockCloud,
+					gidAllocator: NewGidAllocator(),
+				}
+
+				req := &csi.CreateVolumeRequest{
+					Name: volumeName,
+					VolumeCapabilities: []*csi.VolumeCapability{
+						stdVolCap,
+					},
+					CapacityRange: &csi.CapacityRange{
+						RequiredBytes: capacityRange,
+					},
+					Parameters: map[string]string{
+						ProvisioningMode: "efs-ap",
+						FsId:             fsId,
+						DirectoryPerms:   "777",
+						SubPathPattern:   subPathPattern,
+					},
+				}
+
+				ctx := context.Background()
+
+				mockCloud.EXPECT().ListAccessPoints(gomock.Eq(ctx), gomock.Any()).Return(nil, nil)
+
+				_, err := driver.CreateVolume(ctx, req)
+				if err == nil {
+					t.Fatal("CreateVolume did not fail")
+				}
+				if status.Code(err) != codes.InvalidArgument {
+					t.Fatalf("Did not throw InvalidArgument error, instead threw %v", err)
+				}
+				mockCtl.Finish()
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, tc.testFunc)
+	}
+}
+
+func TestParseThroughputParameters(t *testing.T) {
+	testCases := []struct {
+		name                  string
+		params                map[string]string
+		throughputMode        string
+		provisionedThroughput float64
+		expectErr             bool
+	}{
+		{
+			name:   "No throughput parameters",
+			params: map[string]string{},
+		},
+		{
+			name:           "Bursting",
+			params:         map[string]string{ThroughputMode: "bursting"},
+			throughputMode: "bursting",
+		},
+		{
+			name:           "Elastic",
+			params:         map[string]string{ThroughputMode: "elastic"},
+			throughputMode: "elastic",
+		},
+		{
+			name:                  "Provisioned with throughput",
+			params:                map[string]string{ThroughputMode: "provisioned", ProvisionedThroughput: "256.5"},
+			throughputMode:        "provisioned",
+			provisionedThroughput: 256.5,
+		},
+		{
+			name:      "Provisioned without throughput",
+			params:    map[string]string{ThroughputMode: "provisioned"},
+			expectErr: true,
+		},
+		{
+			name:      "Throughput without mode",
+			params:    map[string]string{ProvisionedThroughput: "128"},
+			expectErr: true,
+		},
+		{
+			name:      "Throughput with bursting",
+			params:    map[string]string{ThroughputMode: "bursting", ProvisionedThroughput: "128"},
+			expectErr: true,
+		},
+		{
+			name:      "Throughput with elastic",
+			params:    map[string]string{ThroughputMode: "elastic", ProvisionedThroughput: "128"},
+			expectErr: true,
+		},
+		{
+			name:      "Unknown mode",
+			params:    map[string]string{ThroughputMode: "fast"},
+			expectErr: true,
+		},
+		{
+			name:      "Throughput is not a number",
+			params:    map[string]string{ThroughputMode: "provisioned", ProvisionedThroughput: "a lot"},
+			expectErr: true,
+		},
+		{
+			name:      "Throughput is not positive",
+			params:    map[string]string{ThroughputMode: "provisioned", ProvisionedThroughput: "0"},
+			expectErr: true,
+		},
+		{
+			name:      "Throughput is NaN",
+			params:    map[string]string{ThroughputMode: "provisioned", ProvisionedThroughput: "NaN"},
+			expectErr: true,
+		},
+		{
+			name:      "Throughput is infinite",
+			params:    map[string]string{ThroughputMode: "provisioned", ProvisionedThroughput: "Inf"},
+			expectErr: true,
+		},
+		{
+			name:      "Throughput is positive infinity",
+			params:    map[string]string{ThroughputMode: "provisioned", ProvisionedThroughput: "+Inf"},
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			throughputMode, provisionedThroughput, err := parseThroughputParameters(tc.params)
+			if tc.expectErr {
+				if err == nil {
+					t.Fatal("parseThroughputParameters did not fail")
+				}
+				if status.Code(err) != codes.InvalidArgument {
+					t.Fatalf("Expected error code %v, got: %v", codes.InvalidArgument, status.Code(err))
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseThroughputParameters failed: %v", err)
+			}
+			if throughputMode != tc.throughputMode {
+				t.Fatalf("Throughput mode mismatched. Expected: %v, actual: %v", tc.throughputMode, throughputMode)
+			}
+			if provisionedThroughput != tc.provisionedThroughput {
+				t.Fatalf("Provisioned throughput mismatched. Expected: %v, actual: %v", tc.provisionedThroughput, provisionedThroughput)
+			}
+		})
+	}
+}
+
+func TestParseCleanupMountOptions(t *testing.T) {
+	testCases := []struct {
+		name     string
+		options  string
+		expected []string
+	}{
+		{
+			name:     "Empty",
+			options:  "",
+			expected: []string{},
+		},
+		{
+			name:     "Known options with whitespace",
+			options:  " tls , iam,mounttargetip=10.0.0.10,",
+			expected: []string{"tls", "iam", "mounttargetip=10.0.0.10"},
+		},
+		{
+			name:     "Unknown options are kept",
+			options:  "tls,foo=bar",
+			expected: []string{"tls", "foo=bar"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			options := ParseCleanupMountOptions(tc.options)
+			if !reflect.DeepEqual(options, tc.expected) {
+				t.Fatalf("Options mismatched. Expected: %v, actual: %v", tc.expected, options)
+			}
+		})
+	}
+}
+
+func TestInterpolateNameTag(t *testing.T) {
+	volumeParams := map[string]string{
+		PvcName:      "data",
+		PvcNamespace: "team-a",
+		PvName:       "pvc-0d1c7b1e-3c6a-4c55-9e5b-1f1f5c1c7e2a",
+	}
+
+	testCases := []struct {
+		name      string
+		nameTag   string
+		expected  string
+		expectErr bool
+	}{
+		{
+			name:     "Static name is kept",
+			nameTag:  "shared-data",
+			expected: "shared-data",
+		},
+		{
+			name:     "PVC metadata is interpolated",
+			nameTag:  "${.PVC.namespace}/${.PVC.name}",
+			expected: "team-a/data",
+		},
+		{
+			name:     "PV name is interpolated",
+			nameTag:  "efs ${.PV.name}",
+			expected: "efs pvc-0d1c7b1e-3c6a-4c55-9e5b-1f1f5c1c7e2a",
+		},
+		{
+			name:     "Characters not allowed in tag values are replaced",
+			nameTag:  "data (${.PVC.name})*",
+			expected: "data -data--",
+		},
+		{
+			name:     "Name is truncated to the maximum tag length",
+			nameTag:  strings.Repeat("é", maxTagValueLength+10),
+			expected: strings.Repeat("é", maxTagValueLength),
+		},
+		{
+			name:      "Unknown element is rejected",
+			nameTag:   "${.PVC.labels}",
+			expectErr: true,
+		},
+		{
+			name:      "Empty name is rejected",
+			nameTag:   "  ",
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			name, err := interpolateNameTag(tc.nameTag, volumeParams)
+			if tc.expectErr {
+				if status.Code(err) != codes.InvalidArgument {
+					t.Fatalf("Expected error code %v, got: %v", codes.InvalidArgument, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("interpolateNameTag failed: %v", err)
+			}
+			if name != tc.expected {
+				t.Fatalf("Name mismatched. Expected: %v, actual: %v", tc.expected, name)
+			}
+		})
+	}
+}
+
+func TestNormalizeBasePath(t *testing.T) {
+	testCases := []struct {
+		name      string
+		basePath  string
+		expected  string
+		expectErr bool
+	}{
+		{
+			name:     "Empty base path is the file system root",
+			basePath: "",
+			expected: "",
+		},
+		{
+			name:     "Root",
+			basePath: "/",
+			expected: "/",
+		},
+		{
+			name:     "Absolute path is kept",
+			basePath: "/a/b",
+			expected: "/a/b",
+		},
+		{
+			name:     "Duplicate and trailing slashes are collapsed",
+			basePath: "/a//b/",
+			expected: "/a/b",
+		},
+		{
+			name:     "Current directory segments are removed",
+			basePath: "/a/./b",
+			expected: "/a/b",
+		},
+		{
+			name:      "Relative path is rejected",
+			basePath:  "foo",
+			expectErr: true,
+		},
+		{
+			name:      "Parent directory segment is rejected",
+			basePath:  "/a/../b",
+			expectErr: true,
+		},
+		{
+			name:      "Trailing parent directory segment is rejected",
+			basePath:  "/a/..",
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			basePath, err := normalizeBasePath(tc.basePath)
+			if tc.expectErr {
+				if err == nil {
+					t.Fatal("normalizeBasePath did not fail")
+				}
+				if status.Code(err) != codes.InvalidArgument {
+					t.Fatalf("Expected error code %v, got: %v", codes.InvalidArgument, status.Code(err))
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("normalizeBasePath failed: %v", err)
+			}
+			if basePath != tc.expected {
+				t.Fatalf("Base path mismatched. Expected: %v, actual: %v", tc.expected, basePath)
+			}
+		})
+	}
+}
+
+func TestGetUniqueRootDirName(t *testing.T) {
+	uuids := []string{
+		"3d2c6a36-9b0c-4c59-8d2f-6d3d1c8e1a01",
+		"7f1e0b42-5a3d-4e8f-9c1b-2a4b6c8d0e02",
+	}
+	volumeParams := map[string]string{PvcName: "my pvc"}
+
+	testCases := []struct {
+		name         string
+		suffixMode   string
+		volumeParams map[string]string
+		accessPoints []*cloud.AccessPoint
+		expected     string
+		expectErr    bool
+	}{
+		{
+			name:       "Full UUID",
+			suffixMode: RootDirSuffixFull,
+			expected:   "dir-" + uuids[0],
+		},
+		{
+			name:       "Short UUID",
+			suffixMode: RootDirSuffixShort,
+			expected:   "dir-3d2c6a36",
+		},
+		{
+			name:         "Sanitized PVC name with short random suffix",
+			suffixMode:   RootDirSuffixPvcName,
+			volumeParams: volumeParams,
+			expected:     "dir-my-pvc-3d2c6a36",
+		},
+		{
+			name:       "PVC name mode requires the PVC name",
+			suffixMode: RootDirSuffixPvcName,
+			expectErr:  true,
+		},
+		{
+			name:       "Collision with an existing access point generates a new suffix",
+			suffixMode: RootDirSuffixShort,
+			accessPoints: []*cloud.AccessPoint{
+				{AccessPointRootDir: "/base/dir-3d2c6a36"},
+			},
+			expected: "dir-7f1e0b42",
+		},
+		{
+			name:       "Fail when every suffix collides",
+			suffixMode: RootDirSuffixShort,
+			accessPoints: []*cloud.AccessPoint{
+				{AccessPointRootDir: "/base/dir-3d2c6a36"},
+				{AccessPointRootDir: "/base/dir-7f1e0b42"},
+			},
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			i := 0
+			driver := &Driver{newUUID: func() string {
+				id := uuids[i%len(uuids)]
+				i++
+				return id
+			}}
+
+			rootDirName, err := driver.getUniqueRootDirName("dir", "/base", tc.suffixMode, tc.volumeParams, tc.accessPoints)
+			if tc.expectErr {
+				if err == nil {
+					t.Fatalf("getUniqueRootDirName did not fail, got: %v", rootDirName)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("getUniqueRootDirName failed: %v", err)
+			}
+			if rootDirName != tc.expected {
+				t.Fatalf("Root directory name mismatched. Expected: %v, actual: %v", tc.expected, rootDirName)
+			}
+		})
+	}
+}
+
+func TestDeleteVolume(t *testing.T) {
+	var (
+		apId     = "fsap-abcd1234xyz987"
+		fsId     = "fs-abcd1234"
+		endpoint = "endpoint"
+		volumeId = "fs-abcd1234::fsap-abcd1234xyz987"
+	)
+
+	testCases := []struct {
+		name     string
+		testFunc func(t *testing.T)
+	}{
+		{
+			name: "Success: Normal flow",
+			testFunc: func(t *testing.T) {
+				mockCtl := gomock.NewController(t)
+				mockCloud := mocks.NewMockCloud(mockCtl)
+
+				driver := &Driver{
+					endpoint:     endpoint,
+					cloud:        mockCloud,
+					gidAllocator: NewGidAllocator(),
+				}
+
+				req := &csi.DeleteVolumeRequest{
+					VolumeId: volumeId,
+				}
+
+				ctx := context.Background()
+				accessPoint := &cloud.AccessPoint{
+					AccessPointId: apId,
+					FileSystemId:  fsId,
+				}
+				mockCloud.EXPECT().DescribeAccessPoint(gomock.Eq(ctx), gomock.Eq(apId)).Return(accessPoint, nil)
+				mockCloud.EXPECT().DeleteAccessPoint(gomock.Eq(ctx), gomock.Eq(apId)).Return(nil)
+				_, err := driver.DeleteVolume(ctx, req)
+				if err != nil {
+					t.Fatalf("Delete Volume failed: %v", err)
+				}
+				mockCtl.Finish()
+			},
+		},
+		{
+			name: "Success: Using the regional client of the region secret",
+			testFunc: func(t *testing.T) {
+				mockCtl := gomock.NewController(t)
+				mockCloud := mocks.NewMockCloud(mockCtl)
+				regionalCloud := mocks.NewMockCloud(mockCtl)
+
+				driver := &Driver{
+					endpoint:     endpoint,
+					cloud:        mockCloud,
+					gidAllocator: NewGidAllocator(),
+				}
+
+				req := &csi.DeleteVolumeRequest{
+					VolumeId: volumeId,
+					Secrets:  map[string]string{Region: "eu-west-1"},
+				}
+
+				ctx := context.Background()
+				accessPoint := &cloud.AccessPoint{
+					AccessPointId: apId,
+					FileSystemId:  fsId,
+				}
+				mockCloud.EXPECT().WithRegion(gomock.Eq("eu-west-1")).Return(regionalCloud, nil)
+				regionalCloud.EXPECT().DescribeAccessPoint(gomock.Eq(ctx), gomock.Eq(apId)).Return(accessPoint, nil)
+				regionalCloud.EXPECT().DeleteAccessPoint(gomock.Eq(ctx), gomock.Eq(apId)).Return(nil)
+				_, err := driver.DeleteVolume(ctx, req)
+				if err != nil {
+					t.Fatalf("Delete Volume failed: %v", err)
+				}
+				mockCtl.Finish()
+			},
+		},
+		{
+			name: "Success: Using the regional client of the region of the volume ID",
+			testFunc: func(t *testing.T) {
+				mockCtl := gomock.NewController(t)
+				mockCloud := mocks.NewMockCloud(mockCtl)
+				regionalCloud := mocks.NewMockCloud(mockCtl)
+
+				driver := &Driver{
+					endpoint:     endpoint,
+					cloud:        mockCloud,
+					gidAllocator: NewGidAllocator(),
+				}
+
+				// Without the region secret, the volume ID written by CreateVolume for the region parameter is enough
+				req := &csi.DeleteVolumeRequest{
+					VolumeId: "arn:aws:elasticfilesystem:eu-west-1:123456789012:file-system/" + fsId + "::" + apId,
+				}
+
+				ctx := context.Background()
+				accessPoint := &cloud.AccessPoint{
+					AccessPointId: apId,
+					FileSystemId:  fsId,
+				}
+				mockCloud.EXPECT().WithRegion(gomock.Eq("eu-west-1")).Return(regionalCloud, nil)
+				regionalCloud.EXPECT().DescribeAccessPoint(gomock.Eq(ctx), gomock.Eq(apId)).Return(accessPoint, nil)
+				regionalCloud.EXPECT().DeleteAccessPoint(gomock.Eq(ctx), gomock.Eq(apId)).Return(nil)
+				_, err := driver.DeleteVolume(ctx, req)
+				if err != nil {
+					t.Fatalf("Delete Volume failed: %v", err)
+				}
+				mockCtl.Finish()
+			},
+		},
+		{
+			name: "Success: Normal flow with deleteAccessPointRootDir",
+			testFunc: func(t *testing.T) {
+				mockCtl := gomock.NewController(t)
+				mockCloud := mocks.NewMockCloud(mockCtl)
+				mockMounter := mocks.NewMockMounter(mockCtl)
+
+				driver := &Driver{
+					endpoint:                 endpoint,
+					cloud:                    mockCloud,
+					mounter:                  mockMounter,
+					gidAllocator:             NewGidAllocator(),
+					deleteAccessPointRootDir: true,
+				}
+
+				req := &csi.DeleteVolumeRequest{
+					VolumeId: volumeId,
+				}
+
+				accessPoint := &cloud.AccessPoint{
+					AccessPointId:      apId,
+					FileSystemId:       fsId,
+					AccessPointRootDir: "",
+					CapacityGiB:        0,
+				}
+
+				ctx := context.Background()
+				mockMounter.EXPECT().MakeDir(gomock.Any()).Return(nil)
+				mockMounter.EXPECT().Mount(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+				mockMounter.EXPECT().Unmount(gomock.Any()).Return(nil)
+				mockCloud.EXPECT().DescribeAccessPoint(gomock.Eq(ctx), gomock.Eq(apId)).Return(accessPoint, nil)
+				mockCloud.EXPECT().DeleteAccessPoint(gomock.Eq(ctx), gomock.Eq(apId)).Return(nil)
+				_, err := driver.DeleteVolume(ctx, req)
+				if err != nil {
+					t.Fatalf("Delete Volume failed: %v", err)
+				}
+				mockCtl.Finish()
+			},
+		},
+		{
+			name: "Success: Normal flow with deleteAccessPointRootDir and custom temp mount dir",
+			testFunc: func(t *testing.T) {
+				mockCtl := gomock.NewController(t)
+				mockCloud := mocks.NewMockCloud(mockCtl)
+				mockMounter := mocks.NewMockMounter(mockCtl)
+				tempMountDir := t.TempDir()
+
+				driver := &Driver{
+					endpoint:                 endpoint,
+					cloud:                    mockCloud,
+					mounter:                  mockMounter,
+					gidAllocator:             NewGidAllocator(),
+					deleteAccessPointRootDir: true,
+					tempMountPathPrefix:      tempMountDir,
+				}
+
+				req := &csi.DeleteVolumeRequest{
+					VolumeId: volumeId,
+				}
+
+				accessPoint := &cloud.AccessPoint{
+					AccessPointId:      apId,
+					FileSystemId:       fsId,
+					AccessPointRootDir: "",
+					CapacityGiB:        0,
+				}
+
+				target := tempMountDir + "/" + apId
+				ctx := context.Background()
+				mockMounter.EXPECT().MakeDir(gomock.Eq(target)).Return(nil)
+				mockMounter.EXPECT().Mount(gomock.Eq(fsId), gomock.Eq(target), gomock.Any(), gomock.Any()).Return(nil)
+				mockMounter.EXPECT().Unmount(gomock.Eq(target)).Return(nil)
+				mockCloud.EXPECT().DescribeAccessPoint(gomock.Eq(ctx), gomock.Eq(apId)).Return(accessPoint, nil)
+				mockCloud.EXPECT().DeleteAccessPoint(gomock.Eq(ctx), gomock.Eq(apId)).Return(nil)
+				_, err := driver.DeleteVolume(ctx, req)
+				if err != nil {
+					t.Fatalf("Delete Volume failed: %v", err)
+				}
+				mockCtl.Finish()
+			},
+		},
+		{
+			name: "Success: Normal flow with deleteAccessPointRootDir and custom cleanup mount options",
+			testFunc: func(t *testing.T) {
+				mockCtl := gomock.NewController(t)
+				mockCloud := mocks.NewMockCloud(mockCtl)
+				mockMounter := mocks.NewMockMounter(mockCtl)
+
+				driver := &Driver{
+					endpoint:                 endpoint,
+					cloud:                    mockCloud,
+					mounter:                  mockMounter,
+					gidAllocator:             NewGidAllocator(),
+					deleteAccessPointRootDir: true,
+					tempMountPathPrefix:      t.TempDir(),
+					cleanupMountOptions:      ParseCleanupMountOptions("notls, mounttargetip=10.0.0.10"),
+				}
+
+				req := &csi.DeleteVolumeRequest{
+					VolumeId: volumeId,
+				}
+
+				accessPoint := &cloud.AccessPoint{
+					AccessPointId:      apId,
+					FileSystemId:       fsId,
+					AccessPointRootDir: "",
+					CapacityGiB:        0,
+				}
+
+				ctx := context.Background()
+				mockMounter.EXPECT().MakeDir(gomock.Any()).Return(nil)
+				mockMounter.EXPECT().Mount(gomock.Eq(fsId), gomock.Any(), gomock.Eq("efs"), gomock.Eq([]string{"notls", "mounttargetip=10.0.0.10"})).Return(nil)
+				mockMounter.EXPECT().Unmount(gomock.Any()).Return(nil)
+				mockCloud.EXPECT().DescribeAccessPoint(gomock.Eq(ctx), gomock.Eq(apId)).Return(accessPoint, nil)
+				mockCloud.EXPECT().DeleteAccessPoint(gomock.Eq(ctx), gomock.Eq(apId)).Return(nil)
+				_, err := driver.DeleteVolume(ctx, req)
+				if err != nil {
+					t.Fatalf("Delete Volume failed: %v", err)
+				}
+				mockCtl.Finish()
+			},
+		},
+		{
+			name: "Success: Normal flow with deleteAccessPointRootDir adds iam to cleanup mount options when useIam is set",
+			testFunc: func(t *testing.T) {
+				mockCtl := gomock.NewController(t)
+				mockCloud := mocks.NewMockCloud(mockCtl)
+				mockMounter := mocks.NewMockMounter(mockCtl)
+
+				driver := &Driver{
+					endpoint:                 endpoint,
+					cloud:                    mockCloud,
+					mounter:                  mockMounter,
+					gidAllocator:             NewGidAllocator(),
+					deleteAccessPointRootDir: true,
+					tempMountPathPrefix:      t.TempDir(),
+					cleanupMountOptions:      []string{"tls"},
+					useIam:                   true,
+				}
+
+				req := &csi.DeleteVolumeRequest{
+					VolumeId: volumeId,
+				}
+
+				accessPoint := &cloud.AccessPoint{
+					AccessPointId:      apId,
+					FileSystemId:       fsId,
+					AccessPointRootDir: "",
+					CapacityGiB:        0,
+				}
+
+				ctx := context.Background()
+				mockMounter.EXPECT().MakeDir(gomock.Any()).Return(nil)
+				mockMounter.EXPECT().Mount(gomock.Eq(fsId), gomock.Any(), gomock.Eq("efs"), gomock.Eq([]string{"tls", "iam"})).Return(nil)
+				mockMounter.EXPECT().Unmount(gomock.Any()).Return(nil)
+				mockCloud.EXPECT().DescribeAccessPoint(gomock.Eq(ctx), gomock.Eq(apId)).Return(accessPoint, nil)
+				mockCloud.EXPECT().DeleteAccessPoint(gomock.Eq(ctx), gomock.Eq(apId)).Return(nil)
+				_, err := driver.DeleteVolume(ctx, req)
+				if err != nil {
+					t.Fatalf("Delete Volume failed: %v", err)
+				}
+				mockCtl.Finish()
+			},
+		},
+		{
+			name: "Success: Access point owned by the same cluster is deleted",
+			testFunc: func(t *testing.T) {
+				mockCtl := gomock.NewController(t)
+				mockCloud := mocks.NewMockCloud(mockCtl)
+
+				driver := &Driver{
+					endpoint:     endpoint,
+					cloud:        mockCloud,
+					gidAllocator: NewGidAllocator(),
+					clusterName:  "cluster-a",
+				}
+
+				req := &csi.DeleteVolumeRequest{
+					VolumeId: volumeId,
+				}
+
+				accessPoint := &cloud.AccessPoint{
+					AccessPointId: apId,
+					FileSystemId:  fsId,
+					Tags:          map[string]string{ClusterNameTagKey: "cluster-a"},
+				}
+
+				ctx := context.Background()
+				mockCloud.EXPECT().DescribeAccessPoint(gomock.Eq(ctx), gomock.Eq(apId)).Return(accessPoint, nil)
+				mockCloud.EXPECT().DeleteAccessPoint(gomock.Eq(ctx), gomock.Eq(apId)).Return(nil)
+				_, err := driver.DeleteVolume(ctx, req)
+				if err != nil {
+					t.Fatalf("Delete Volume failed: %v", err)
+				}
+				mockCtl.Finish()
+			},
+		},
+		{
+			name: "Fail: Access point owned by another cluster is not deleted",
+			testFunc: func(t *testing.T) {
+				mockCtl := gomock.NewController(t)
+				mockCloud := mocks.NewMockCloud(mockCtl)
+
+				driver := &Driver{
+					endpoint:     endpoint,
+					cloud:        mockCloud,
+					gidAllocator: NewGidAllocator(),
+					clusterName:  "cluster-a",
+				}
+
+				req := &csi.DeleteVolumeRequest{
+					VolumeId: volumeId,
+				}
+
+				accessPoint := &cloud.AccessPoint{
+					AccessPointId: apId,
+					FileSystemId:  fsId,
+					Tags:          map[string]string{ClusterNameTagKey: "cluster-b"},
+				}
+
+				ctx := context.Background()
+				mockCloud.EXPECT().DescribeAccessPoint(gomock.Eq(ctx), gomock.Eq(apId)).Return(accessPoint, nil)
+				_, err := driver.DeleteVolume(ctx, req)
+				if err == nil {
+					t.Fatal("DeleteVolume did not fail")
+				}
+				if status.Code(err) != codes.FailedPrecondition {
+					t.Fatalf("Expected error code %v, got: %v", codes.FailedPrecondition, status.Code(err))
+				}
+				mockCtl.Finish()
+			},
+		},
+		{
+			name: "Success: DescribeAccessPoint Access Point Does not exist",
+			testFunc: func(t *testing.T) {
+				mockCtl := gomock.NewController(t)
+				mockCloud := mocks.NewMockCloud(mockCtl)
+				mockMounter := mocks.NewMockMounter(mockCtl)
+
+				driver := &Driver{
+					endpoint:                 endpoint,
+					cloud:                    mockCloud,
+					mounter:                  mockMounter,
+					gidAllocator:             NewGidAllocator(),
+					deleteAccessPointRootDir: true,
+				}
+
+				req := &csi.DeleteVolumeRequest{
+					VolumeId: volumeId,
+				}
+
+				ctx := context.Background()
+				mockCloud.EXPECT().DescribeAccessPoint(gomock.Eq(ctx), gomock.Eq(apId)).Return(nil, cloud.ErrNotFound)
+				_, err := driver.DeleteVolume(ctx, req)
+				if err != nil {
+					t.Fatalf("Delete Volume failed: %v", err)
+				}
+				mockCtl.Finish()
+			},
+		},
+		{
+			name: "Fail: DescribeAccessPoint Access Denied",
+			testFunc: func(t *testing.T) {
+				mockCtl := gomock.NewController(t)
+				mockCloud := mocks.NewMockCloud(mockCtl)
+				mockMounter := mocks.NewMockMounter(mockCtl)
+
+				driver := &Driver{
+					endpoint:                 endpoint,
+					cloud:                    mockCloud,
+					mounter:                  mockMounter,
+					gidAllocator:             NewGidAllocator(),
+					deleteAccessPointRootDir: true,
+				}
+
+				req := &csi.DeleteVolumeRequest{
+					VolumeId: volumeId,
+				}
+
+				ctx := context.Background()
+				mockCloud.EXPECT().DescribeAccessPoint(gomock.Eq(ctx), gomock.Eq(apId)).Return(nil, cloud.ErrAccessDenied)
+				_, err := driver.DeleteVolume(ctx, req)
+				if err == nil {
+					t.Fatalf("DeleteVolume did not fail")
+				}
+				mockCtl.Finish()
+			},
+		},
+		{
+			name: "Fail: DescribeAccessPoint failed",
+			testFunc: func(t *testing.T) {
+				mockCtl := gomock.NewController(t)
+				mockCloud := mocks.NewMockCloud(mockCtl)
+				mockMounter := mocks.NewMockMounter(mockCtl)
+
+				driver := &Driver{
+					endpoint:                 endpoint,
+					cloud:                    mockCloud,
+					mounter:                  mockMounter,
+					gidAllocator:             NewGidAllocator(),
+					deleteAccessPointRootDir: true,
+				}
+
+				req := &csi.DeleteVolumeRequest{
+					VolumeId: volumeId,
+				}
+
+				ctx := context.Background()
+				mockCloud.EXPECT().DescribeAccessPoint(gomock.Eq(ctx), gomock.Eq(apId)).Return(nil, errors.New("Describe Access Point failed"))
+				_, err := driver.DeleteVolume(ctx, req)
+				if status.Code(err) != codes.Internal {
+					t.Fatalf("Expected Internal, got: %v", err)
+				}
+				mockCtl.Finish()
+			},
+		},
+		{
+			name: "Fail: DescribeAccessPoint throttling is retryable",
+			testFunc: func(t *testing.T) {
+				mockCtl := gomock.NewController(t)
+				mockCloud := mocks.NewMockCloud(mockCtl)
+				mockMounter := mocks.NewMockMounter(mockCtl)
+
+				driver := &Driver{
+					endpoint:                 endpoint,
+					cloud:                    mockCloud,
+					mounter:                  mockMounter,
+					gidAllocator:             NewGidAllocator(),
+					deleteAccessPointRootDir: true,
+				}
+
+				req := &csi.DeleteVolumeRequest{
+					VolumeId: volumeId,
+				}
+
+				ctx := context.Background()
+				describeErr := fmt.Errorf("Describe Access Point failed: %w", awserr.NewRequestFailure(awserr.New("ThrottlingException", "Rate exceeded", nil), 400, "5f0c1b3e-1d2a-4c43-8f4e-2b7f9a6d0c11"))
+				mockCloud.EXPECT().DescribeAccessPoint(gomock.Eq(ctx), gomock.Eq(apId)).Return(nil, describeErr)
+				_, err := driver.DeleteVolume(ctx, req)
+				if status.Code(err) != codes.Unavailable {
+					t.Fatalf("Expected Unavailable, got: %v", err)
+				}
+				mockCtl.Finish()
+			},
+		},
+		{
+			name: "Fail: DescribeAccessPoint server error is retryable",
+			testFunc: func(t *testing.T) {
+				mockCtl := gomock.NewController(t)
+				mockCloud := mocks.NewMockCloud(mockCtl)
+				mockMounter := mocks.NewMockMounter(mockCtl)
+
+				driver := &Driver{
+					endpoint:                 endpoint,
+					cloud:                    mockCloud,
+					mounter:                  mockMounter,
+					gidAllocator:             NewGidAllocator(),
+					deleteAccessPointRootDir: true,
+				}
+
+				req := &csi.DeleteVolumeRequest{
+					VolumeId: volumeId,
+				}
+
+				ctx := context.Background()
+				describeErr := fmt.Errorf("Describe Access Point failed: %w", awserr.NewRequestFailure(awserr.New("InternalServerError", "Internal error", nil), 500, "8e1d6c4a-3f2b-4b8e-9c71-0a5d2e6f4b93"))
+				mockCloud.EXPECT().DescribeAccessPoint(gomock.Eq(ctx), gomock.Eq(apId)).Return(nil, describeErr)
+				_, err := driver.DeleteVolume(ctx, req)
+				if status.Code(err) != codes.Unavailable {
+					t.Fatalf("Expected Unavailable, got: %v", err)
+				}
+				mockCtl.Finish()
+			},
+		},
+		{
+			name: "Fail: DescribeAccessPoint network error is retryable",
+			testFunc: func(t *testing.T) {
+				mockCtl := gomock.NewController(t)
+				mockCloud := mocks.NewMockCloud(mockCtl)
+				mockMounter := mocks.NewMockMounter(mockCtl)
+
+				driver := &Driver{
+					endpoint:                 endpoint,
+					cloud:                    mockCloud,
+					mounter:                  mockMounter,
+					gidAllocator:             NewGidAllocator(),
+					deleteAccessPointRootDir: true,
+				}
+
+				req := &csi.DeleteVolumeRequest{
+					VolumeId: volumeId,
+				}
+
+				ctx := context.Background()
+				describeErr := fmt.Errorf("Describe Access Point failed: %w", awserr.New("RequestError", "send request failed", errors.New("read: connection reset by peer")))
+				mockCloud.EXPECT().DescribeAccessPoint(gomock.Eq(ctx), gomock.Eq(apId)).Return(nil, describeErr)
+				_, err := driver.DeleteVolume(ctx, req)
+				if status.Code(err) != codes.Unavailable {
+					t.Fatalf("Expected Unavailable, got: %v", err)
+				}
+				mockCtl.Finish()
+			},
+		},
+		{
+			name: "Fail: Fail to make directory for access point mount",
+			testFunc: func(t *testing.T) {
+				mockCtl := gomock.NewController(t)
+				mockCloud := mocks.NewMockCloud(mockCtl)
+				mockMounter := mocks.NewMockMounter(mockCtl)
+
+				driver := &Driver{
+					endpoint:                 endpoint,
+					cloud:                    mockCloud,
+					mounter:                  mockMounter,
+					gidAllocator:             NewGidAllocator(),
+					deleteAccessPointRootDir: true,
+				}
+
+				req := &csi.DeleteVolumeRequest{
+					VolumeId: volumeId,
+				}
+
+				accessPoint := &cloud.AccessPoint{
+					AccessPointId:      apId,
+					FileSystemId:       fsId,
+					AccessPointRootDir: "",
+					CapacityGiB:        0,
+				}
+
+				ctx := context.Background()
+				mockMounter.EXPECT().MakeDir(gomock.Any()).Return(errors.New("Failed to makeDir"))
+				mockCloud.EXPECT().DescribeAccessPoint(gomock.Eq(ctx), gomock.Eq(apId)).Return(accessPoint, nil)
+				_, err := driver.DeleteVolume(ctx, req)
+				if err == nil {
+					t.Fatal("DeleteVolume did not fail")
+				}
+				mockCtl.Finish()
+			},
+		},
+		{
+			name: "Success: Access point is deleted when the cleanup mount keeps failing",
+			testFunc: func(t *testing.T) {
+				mockCtl := gomock.NewController(t)
+				mockCloud := mocks.NewMockCloud(mockCtl)
+				mockMounter := mocks.NewMockMounter(mockCtl)
+
+				driver := &Driver{
+					endpoint:                 endpoint,
+					cloud:                    mockCloud,
+					mounter:                  mockMounter,
+					gidAllocator:             NewGidAllocator(),
+					deleteAccessPointRootDir: true,
+					cleanupMountRetries:      2,
+				}
+				defer func(backoff wait.Backoff) { cleanupMountBackoff = backoff }(cleanupMountBackoff)
+				cleanupMountBackoff.Duration = time.Millisecond
+
+				req := &csi.DeleteVolumeRequest{
+					VolumeId: volumeId,
+				}
+
+				accessPoint := &cloud.AccessPoint{
+					AccessPointId:      apId,
+					FileSystemId:       fsId,
+					AccessPointRootDir: "",
+					CapacityGiB:        0,
+				}
+
+				ctx := context.Background()
+				mockMounter.EXPECT().MakeDir(gomock.Any()).Return(nil)
+				mockMounter.EXPECT().Mount(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(errors.New("Failed to mount")).Times(3)
+				mockCloud.EXPECT().DescribeAccessPoint(gomock.Eq(ctx), gomock.Eq(apId)).Return(accessPoint, nil)
+				mockCloud.EXPECT().DeleteAccessPoint(gomock.Eq(ctx), gomock.Eq(apId)).Return(nil)
+				_, err := driver.DeleteVolume(ctx, req)
+				if err != nil {
+					t.Fatalf("Delete Volume failed: %v", err)
+				}
+				mockCtl.Finish()
+			},
+		},
+		{
+			name: "Success: Cleanup mount is retried until it succeeds",
+			testFunc: func(t *testing.T) {
+				mockCtl := gomock.NewController(t)
+				mockCloud := mocks.NewMockCloud(mockCtl)
+				mockMounter := mocks.NewMockMounter(mockCtl)
+
+				driver := &Driver{
+					endpoint:                 endpoint,
+					cloud:                    mockCloud,
+					mounter:                  mockMounter,
+					gidAllocator:             NewGidAllocator(),
+					deleteAccessPointRootDir: true,
+					tempMountPathPrefix:      t.TempDir(),
+					cleanupMountRetries:      3,
+				}
+				defer func(backoff wait.Backoff) { cleanupMountBackoff = backoff }(cleanupMountBackoff)
+				cleanupMountBackoff.Duration = time.Millisecond
+
+				req := &csi.DeleteVolumeRequest{
+					VolumeId: volumeId,
+				}
+
+				accessPoint := &cloud.AccessPoint{
+					AccessPointId:      apId,
+					FileSystemId:       fsId,
+					AccessPointRootDir: "",
+					CapacityGiB:        0,
+				}
+
+				ctx := context.Background()
+				mockMounter.EXPECT().MakeDir(gomock.Any()).Return(nil)
+				gomock.InOrder(
+					mockMounter.EXPECT().Mount(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(errors.New("Failed to resolve mount target")).Times(2),
+					mockMounter.EXPECT().Mount(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+				)
+				mockMounter.EXPECT().Unmount(gomock.Any()).Return(nil)
+				mockCloud.EXPECT().DescribeAccessPoint(gomock.Eq(ctx), gomock.Eq(apId)).Return(accessPoint, nil)
+				mockCloud.EXPECT().DeleteAccessPoint(gomock.Eq(ctx), gomock.Eq(apId)).Return(nil)
+				_, err := driver.DeleteVolume(ctx, req)
+				if err != nil {
+					t.Fatalf("Delete Volume failed: %v", err)
+				}
+				mockCtl.Finish()
+			},
+		},
+		{
+			name: "Success: Cleanup mount retries stop after the timeout",
+			testFunc: func(t *testing.T) {
+				mockCtl := gomock.NewController(t)
+				mockCloud := mocks.NewMockCloud(mockCtl)
+				mockMounter := mocks.NewMockMounter(mockCtl)
+
+				driver := &Driver{
+					endpoint:                 endpoint,
+					cloud:                    mockCloud,
+					mounter:                  mockMounter,
+					gidAllocator:             NewGidAllocator(),
+					deleteAccessPointRootDir: true,
+					cleanupMountRetries:      100,
+					cleanupMountTimeout:      time.Nanosecond,
+				}
+				defer func(backoff wait.Backoff) { cleanupMountBackoff = backoff }(cleanupMountBackoff)
+				cleanupMountBackoff.Duration = time.Millisecond
+
+				req := &csi.DeleteVolumeRequest{
+					VolumeId: volumeId,
+				}
+
+				accessPoint := &cloud.AccessPoint{
+					AccessPointId:      apId,
+					FileSystemId:       fsId,
+					AccessPointRootDir: "",
+					CapacityGiB:        0,
+				}
+
+				ctx := context.Background()
+				mockMounter.EXPECT().MakeDir(gomock.Any()).Return(nil)
+				mockMounter.EXPECT().Mount(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(errors.New("Failed to mount")).Times(1)
+				mockCloud.EXPECT().DescribeAccessPoint(gomock.Eq(ctx), gomock.Eq(apId)).Return(accessPoint, nil)
+				mockCloud.EXPECT().DeleteAccessPoint(gomock.Eq(ctx), gomock.Eq(apId)).Return(nil)
+				_, err := driver.DeleteVolume(ctx, req)
+				if err != nil {
+					t.Fatalf("Delete Volume failed: %v", err)
+				}
+				mockCtl.Finish()
+			},
+		},
+		{
+			name: "Fail: Fail to unmount file system after access point root directory removal",
+			testFunc: func(t *testing.T) {
+				mockCtl := gomock.NewController(t)
+				mockCloud := mocks.NewMockCloud(mockCtl)
+				mockMounter := mocks.NewMockMounter(mockCtl)
+
+				driver := &Driver{
+					endpoint:                 endpoint,
+					cloud:                    mockCloud,
+					mounter:                  mockMounter,
+					gidAllocator:             NewGidAllocator(),
+					deleteAccessPointRootDir: true,
+				}
+
+				req := &csi.DeleteVolumeRequest{
+					VolumeId: volumeId,
+				}
+
+				accessPoint := &cloud.AccessPoint{
+					AccessPointId:      apId,
+					FileSystemId:       fsId,
+					AccessPointRootDir: "",
+					CapacityGiB:        0,
+				}
+
+				ctx := context.Background()
+				mockMounter.EXPECT().MakeDir(gomock.Any()).Return(nil)
+				mockMounter.EXPECT().Mount(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+				mockMounter.EXPECT().Unmount(gomock.Any()).Return(errors.New("Failed to unmount"))
+				mockCloud.EXPECT().DescribeAccessPoint(gomock.Eq(ctx), gomock.Eq(apId)).Return(accessPoint, nil)
+				_, err := driver.DeleteVolume(ctx, req)
+				if err == nil {
+					t.Fatal("DeleteVolume did not fail")
+				}
+				mockCtl.Finish()
+			},
+		},
+		{
+			name: "Fail: File system unmounted after failing to remove the access point root directory",
+			testFunc: func(t *testing.T) {
+				mockCtl := gomock.NewController(t)
+				mockCloud := mocks.NewMockCloud(mockCtl)
+				mockMounter := mocks.NewMockMounter(mockCtl)
+
+				driver := &Driver{
+					endpoint:                 endpoint,
+					cloud:                    mockCloud,
+					mounter:                  mockMounter,
+					gidAllocator:             NewGidAllocator(),
+					deleteAccessPointRootDir: true,
+					tempMountPathPrefix:      t.TempDir(),
+				}
+
+				req := &csi.DeleteVolumeRequest{
+					VolumeId: volumeId,
+				}
+
+				// A root directory under a regular file cannot be removed
+				accessPoint := &cloud.AccessPoint{
+					AccessPointId:      apId,
+					FileSystemId:       fsId,
+					AccessPointRootDir: "/file/dir",
+					CapacityGiB:        0,
+				}
+
+				target := driver.getTempMountPathPrefix() + "/" + apId
+				ctx := context.Background()
+				mockMounter.EXPECT().MakeDir(gomock.Eq(target)).DoAndReturn(func(target string) error {
+					if err := os.MkdirAll(target, 0755); err != nil {
+						return err
+					}
+					return os.WriteFile(target+"/file", nil, 0644)
+				})
+				mockMounter.EXPECT().Mount(gomock.Any(), gomock.Eq(target), gomock.Any(), gomock.Any()).Return(nil)
+				mockMounter.EXPECT().Unmount(gomock.Eq(target)).Return(nil)
+				mockCloud.EXPECT().DescribeAccessPoint(gomock.Eq(ctx), gomock.Eq(apId)).Return(accessPoint, nil)
+				_, err := driver.DeleteVolume(ctx, req)
+				if status.Code(err) != codes.Internal {
+					t.Fatalf("Expected Internal, got: %v", err)
+				}
+				if _, err := os.Stat(target); !os.IsNotExist(err) {
+					t.Fatalf("Expected %q to be removed, got: %v", target, err)
+				}
+				mockCtl.Finish()
+			},
+		},
+		{
+			name: "Success: Access Point already deleted",
+			testFunc: func(t *testing.T) {
+				mockCtl := gomock.NewController(t)
+				mockCloud := mocks.NewMockCloud(mockCtl)
+
+				driver := &Driver{
+					endpoint:     endpoint,
+					cloud:        mockCloud,
+					gidAllocator: NewGidAllocator(),
+				}
+
+				req := &csi.DeleteVolumeRequest{
+					VolumeId: volumeId,
+				}
+
+				ctx := context.Background()
+				accessPoint := &cloud.AccessPoint{
+					AccessPointId: apId,
+					FileSystemId:  fsId,
+				}
+				mockCloud.EXPECT().DescribeAccessPoint(gomock.Eq(ctx), gomock.Eq(apId)).Return(accessPoint, nil)
+				mockCloud.EXPECT().DeleteAccessPoint(gomock.Eq(ctx), gomock.Eq(apId)).Return(cloud.ErrNotFound)
+				_, err := driver.DeleteVolume(ctx, req)
+				if err != nil {
+					t.Fatalf("Delete Volume failed: %v", err)
+				}
+				mockCtl.Finish()
+			},
+		},
+		{
+			name: "Success: Access Point already deleted with a wrapped error",
+			testFunc: func(t *testing.T) {
+				mockCtl := gomock.NewController(t)
+				mockCloud := mocks.NewMockCloud(mockCtl)
+
+				driver := &Driver{
+					endpoint:     endpoint,
+					cloud:        mockCloud,
+					gidAllocator: NewGidAllocator(),
+				}
+
+				req := &csi.DeleteVolumeRequest{
+					VolumeId: volumeId,
+				}
+
+				ctx := context.Background()
+				accessPoint := &cloud.AccessPoint{
+					AccessPointId: apId,
+					FileSystemId:  fsId,
+				}
+				mockCloud.EXPECT().DescribeAccessPoint(gomock.Eq(ctx), gomock.Eq(apId)).Return(accessPoint, nil)
+				mockCloud.EXPECT().DeleteAccessPoint(gomock.Eq(ctx), gomock.Eq(apId)).Return(&cloud.Error{Kind: cloud.ErrNotFound, Err: errors.New("AccessPointNotFound: Access Point not found")})
+				_, err := driver.DeleteVolume(ctx, req)
+				if err != nil {
+					t.Fatalf("Delete Volume failed: %v", err)
+				}
+				mockCtl.Finish()
+			},
+		},
+		{
+			name: "Fail: DeleteAccessPoint access denied",
+			testFunc: func(t *testing.T) {
+				mockCtl := gomock.NewController(t)
+				mockCloud := mocks.NewMockCloud(mockCtl)
+
+				driver := &Driver{
+					endpoint:     endpoint,
+					cloud:        mockCloud,
+					gidAllocator: NewGidAllocator(),
+				}
+
+				req := &csi.DeleteVolumeRequest{
+					VolumeId: volumeId,
+				}
+
+				ctx := context.Background()
+				accessPoint := &cloud.AccessPoint{
+					AccessPointId: apId,
+					FileSystemId:  fsId,
+				}
+				mockCloud.EXPECT().DescribeAccessPoint(gomock.Eq(ctx), gomock.Eq(apId)).Return(accessPoint, nil)
+				mockCloud.EXPECT().DeleteAccessPoint(gomock.Eq(ctx), gomock.Eq(apId)).Return(cloud.ErrAccessDenied)
+				_, err := driver.DeleteVolume(ctx, req)
+				if err == nil {
+					t.Fatal("DeleteVolume did not fail")
+				}
+				mockCtl.Finish()
+			},
+		},
+		{
+			name: "Fail: DeleteVolume fails",
+			testFunc: func(t *testing.T) {
+				mockCtl := gomock.NewController(t)
+				mockCloud := mocks.NewMockCloud(mockCtl)
+
+				driver := &Driver{
+					endpoint:     endpoint,
+					cloud:        mockCloud,
+					gidAllocator: NewGidAllocator(),
+				}
+
+				req := &csi.DeleteVolumeRequest{
 					VolumeId: volumeId,
 				}
 
 				ctx := context.Background()
-				mockCloud.EXPECT().DescribeAccessPoint(gomock.Eq(ctx), gomock.Eq(apId)).Return(nil, cloud.ErrAccessDenied)
+				accessPoint := &cloud.AccessPoint{
+					AccessPointId: apId,
+					FileSystemId:  fsId,
+				}
+				mockCloud.EXPECT().DescribeAccessPoint(gomock.Eq(ctx), gomock.Eq(apId)).Return(accessPoint, nil)
+				mockCloud.EXPECT().DeleteAccessPoint(gomock.Eq(ctx), gomock.Eq(apId)).Return(errors.New("Delete Volume failed"))
+				_, err := driver.DeleteVolume(ctx, req)
+				if err == nil {
+					t.Fatal("DeleteVolume did not fail")
+				}
+				mockCtl.Finish()
+			},
+		},
+		{
+			name: "Success: Delete file system provisioned with efs-fs mode",
+			testFunc: func(t *testing.T) {
+				mockCtl := gomock.NewController(t)
+				mockCloud := mocks.NewMockCloud(mockCtl)
+
+				driver := &Driver{
+					endpoint:     endpoint,
+					cloud:        mockCloud,
+					gidAllocator: NewGidAllocator(),
+				}
+
+				req := &csi.DeleteVolumeRequest{
+					VolumeId: fsId,
+				}
+
+				fileSystem := &cloud.FileSystem{
+					FileSystemId: fsId,
+					Tags:         map[string]string{DefaultTagKey: DefaultTagValue, FsVolumeTagKey: "pvc-1"},
+				}
+
+				ctx := context.Background()
+				mockCloud.EXPECT().DescribeFileSystem(gomock.Eq(ctx), gomock.Eq(fsId)).Return(fileSystem, nil)
+				mockCloud.EXPECT().DeleteFileSystem(gomock.Eq(ctx), gomock.Eq(fsId)).Return(nil)
+				_, err := driver.DeleteVolume(ctx, req)
+				if err != nil {
+					t.Fatalf("Delete Volume failed: %v", err)
+				}
+				mockCtl.Finish()
+			},
+		},
+		{
+			name: "Success: Delete file system provisioned with the default tag disabled",
+			testFunc: func(t *testing.T) {
+				mockCtl := gomock.NewController(t)
+				mockCloud := mocks.NewMockCloud(mockCtl)
+
+				driver := &Driver{
+					endpoint:           endpoint,
+					cloud:              mockCloud,
+					gidAllocator:       NewGidAllocator(),
+					tags:               parseTagsFromStr("cost-center:storage"),
+					disableDefaultTags: true,
+				}
+
+				req := &csi.DeleteVolumeRequest{
+					VolumeId: fsId,
+				}
+
+				fileSystem := &cloud.FileSystem{
+					FileSystemId: fsId,
+					Tags:         map[string]string{"cost-center": "storage", FsVolumeTagKey: "pvc-1"},
+				}
+
+				ctx := context.Background()
+				mockCloud.EXPECT().DescribeFileSystem(gomock.Eq(ctx), gomock.Eq(fsId)).Return(fileSystem, nil)
+				mockCloud.EXPECT().DeleteFileSystem(gomock.Eq(ctx), gomock.Eq(fsId)).Return(nil)
+				_, err := driver.DeleteVolume(ctx, req)
+				if err != nil {
+					t.Fatalf("Delete Volume failed: %v", err)
+				}
+				mockCtl.Finish()
+			},
+		},
+		{
+			name: "Fail: File system carrying the driver tags of a static volume is not deleted",
+			testFunc: func(t *testing.T) {
+				mockCtl := gomock.NewController(t)
+				mockCloud := mocks.NewMockCloud(mockCtl)
+
+				driver := &Driver{
+					endpoint:           endpoint,
+					cloud:              mockCloud,
+					gidAllocator:       NewGidAllocator(),
+					tags:               parseTagsFromStr("cost-center:storage"),
+					disableDefaultTags: true,
+				}
+
+				req := &csi.DeleteVolumeRequest{
+					VolumeId: fsId,
+				}
+
+				// A user managed file system tagged like the resources of the driver
+				fileSystem := &cloud.FileSystem{
+					FileSystemId: fsId,
+					Tags:         map[string]string{DefaultTagKey: DefaultTagValue, "cost-center": "storage"},
+				}
+
+				ctx := context.Background()
+				mockCloud.EXPECT().DescribeFileSystem(gomock.Eq(ctx), gomock.Eq(fsId)).Return(fileSystem, nil)
+				_, err := driver.DeleteVolume(ctx, req)
+				if status.Code(err) != codes.FailedPrecondition {
+					t.Fatalf("Expected FailedPrecondition, got: %v", err)
+				}
+				mockCtl.Finish()
+			},
+		},
+		{
+			name: "Success: File system already deleted",
+			testFunc: func(t *testing.T) {
+				mockCtl := gomock.NewController(t)
+				mockCloud := mocks.NewMockCloud(mockCtl)
+
+				driver := &Driver{
+					endpoint:     endpoint,
+					cloud:        mockCloud,
+					gidAllocator: NewGidAllocator(),
+				}
+
+				req := &csi.DeleteVolumeRequest{
+					VolumeId: fsId,
+				}
+
+				ctx := context.Background()
+				mockCloud.EXPECT().DescribeFileSystem(gomock.Eq(ctx), gomock.Eq(fsId)).Return(nil, cloud.ErrNotFound)
+				_, err := driver.DeleteVolume(ctx, req)
+				if err != nil {
+					t.Fatalf("Delete Volume failed: %v", err)
+				}
+				mockCtl.Finish()
+			},
+		},
+		{
+			name: "Fail: File system was not provisioned by the driver",
+			testFunc: func(t *testing.T) {
+				mockCtl := gomock.NewController(t)
+				mockCloud := mocks.NewMockCloud(mockCtl)
+
+				driver := &Driver{
+					endpoint:     endpoint,
+					cloud:        mockCloud,
+					gidAllocator: NewGidAllocator(),
+				}
+
+				req := &csi.DeleteVolumeRequest{
+					VolumeId: fsId,
+				}
+
+				fileSystem := &cloud.FileSystem{
+					FileSystemId: fsId,
+					Tags:         map[string]string{},
+				}
+
+				ctx := context.Background()
+				mockCloud.EXPECT().DescribeFileSystem(gomock.Eq(ctx), gomock.Eq(fsId)).Return(fileSystem, nil)
+				_, err := driver.DeleteVolume(ctx, req)
+				if err == nil {
+					t.Fatal("DeleteVolume did not fail")
+				}
+				if status.Code(err) != codes.FailedPrecondition {
+					t.Fatalf("Expected error code %v, got: %v", codes.FailedPrecondition, status.Code(err))
+				}
+				mockCtl.Finish()
+			},
+		},
+		{
+			name: "Success: Directory without access point is left in place",
+			testFunc: func(t *testing.T) {
+				mockCtl := gomock.NewController(t)
+				mockCloud := mocks.NewMockCloud(mockCtl)
+				mockMounter := mocks.NewMockMounter(mockCtl)
+
+				driver := &Driver{
+					endpoint:                 endpoint,
+					cloud:                    mockCloud,
+					mounter:                  mockMounter,
+					gidAllocator:             NewGidAllocator(),
+					deleteAccessPointRootDir: true,
+				}
+
+				req := &csi.DeleteVolumeRequest{
+					VolumeId: "fs-abcd1234:/subpath",
+				}
+
+				// Neither the cloud nor the mounter is called
+				ctx := context.Background()
+				_, err := driver.DeleteVolume(ctx, req)
+				if err != nil {
+					t.Fatalf("DeleteVolume failed: %v", err)
+				}
+				mockCtl.Finish()
+			},
+		},
+		{
+			name: "Success: Volume Id was not created by the driver",
+			testFunc: func(t *testing.T) {
+				mockCtl := gomock.NewController(t)
+				mockCloud := mocks.NewMockCloud(mockCtl)
+
+				driver := &Driver{
+					endpoint:     endpoint,
+					cloud:        mockCloud,
+					gidAllocator: NewGidAllocator(),
+				}
+
+				req := &csi.DeleteVolumeRequest{
+					VolumeId: "reallyfakevolumeid",
+				}
+
+				ctx := context.Background()
 				_, err := driver.DeleteVolume(ctx, req)
-				if err == nil {
-					t.Fatalf("DeleteVolume did not fail")
+				if err != nil {
+					t.Fatalf("DeleteVolume failed: %v", err)
 				}
 				mockCtl.Finish()
 			},
 		},
 		{
-			name: "Fail: DescribeAccessPoint failed",
+			name: "Fail: Malformed volume Id of the driver",
 			testFunc: func(t *testing.T) {
 				mockCtl := gomock.NewController(t)
 				mockCloud := mocks.NewMockCloud(mockCtl)
-				mockMounter := mocks.NewMockMounter(mockCtl)
 
 				driver := &Driver{
-					endpoint:                 endpoint,
-					cloud:                    mockCloud,
-					mounter:                  mockMounter,
-					gidAllocator:             NewGidAllocator(),
-					deleteAccessPointRootDir: true,
+					endpoint:     endpoint,
+					cloud:        mockCloud,
+					gidAllocator: NewGidAllocator(),
+				}
+
+				req := &csi.DeleteVolumeRequest{
+					VolumeId: "fs-abcd1234::fsap-abcd1234xyz987:extra",
+				}
+
+				ctx := context.Background()
+				_, err := driver.DeleteVolume(ctx, req)
+				if status.Code(err) != codes.InvalidArgument {
+					t.Fatalf("Expected InvalidArgument, got: %v", err)
+				}
+				mockCtl.Finish()
+			},
+		},
+		{
+			name: "Fail: Cannot assume role for x-account",
+			testFunc: func(t *testing.T) {
+				mockCtl := gomock.NewController(t)
+				mockCloud := mocks.NewMockCloud(mockCtl)
+
+				driver := &Driver{
+					endpoint:     endpoint,
+					cloud:        mockCloud,
+					gidAllocator: NewGidAllocator(),
+					tags:         parseTagsFromStr(""),
 				}
 
+				secrets := map[string]string{}
+				secrets["awsRoleArn"] = "arn:aws:iam::1234567890:role/EFSCrossAccountRole"
+
 				req := &csi.DeleteVolumeRequest{
 					VolumeId: volumeId,
+					Secrets:  secrets,
 				}
 
 				ctx := context.Background()
-				mockCloud.EXPECT().DescribeAccessPoint(gomock.Eq(ctx), gomock.Eq(apId)).Return(nil, errors.New("Describe Access Point failed"))
+
 				_, err := driver.DeleteVolume(ctx, req)
+
 				if err == nil {
 					t.Fatalf("DeleteVolume did not fail")
 				}
+
+				mockCtl.Finish()
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, tc.testFunc)
+	}
+}
+
+func TestValidateVolumeCapabilities(t *testing.T) {
+	var (
+		endpoint       = "endpoint"
+		volumeId       = "fs-abcd1234::fsap-abcd1234xyz987"
+		stdVolCapValid = &csi.VolumeCapability{
+			AccessType: &csi.VolumeCapability_Mount{
+				Mount: &csi.VolumeCapability_MountVolume{},
+			},
+			AccessMode: &csi.VolumeCapability_AccessMode{
+				Mode: csi.VolumeCapability_AccessMode_MULTI_NODE_MULTI_WRITER,
+			},
+		}
+		stdVolCapInvalid = &csi.VolumeCapability{
+			AccessType: &csi.VolumeCapability_Mount{
+				Mount: &csi.VolumeCapability_MountVolume{},
+			},
+			AccessMode: &csi.VolumeCapability_AccessMode{
+				Mode: csi.VolumeCapability_AccessMode_SINGLE_NODE_READER_ONLY,
+			},
+		}
+	)
+	testCases := []struct {
+		name     string
+		testFunc func(t *testing.T)
+	}{
+		{
+			name: "Success",
+			testFunc: func(t *testing.T) {
+				mockCtl := gomock.NewController(t)
+				mockCloud := mocks.NewMockCloud(mockCtl)
+
+				driver := &Driver{
+					endpoint: endpoint,
+					cloud:    mockCloud,
+				}
+
+				req := &csi.ValidateVolumeCapabilitiesRequest{
+					VolumeId: volumeId,
+					VolumeCapabilities: []*csi.VolumeCapability{
+						stdVolCapValid,
+					},
+				}
+
+				ctx := context.Background()
+				res, err := driver.ValidateVolumeCapabilities(ctx, req)
+				if err != nil {
+					t.Fatalf("ValidateVolumeCapabilities failed: %v", err)
+				}
+
+				if res.Confirmed == nil {
+					t.Fatalf("Capability is not supported")
+				}
+				mockCtl.Finish()
+			},
+		},
+		{
+			name: "Success: Directory without access point",
+			testFunc: func(t *testing.T) {
+				mockCtl := gomock.NewController(t)
+				mockCloud := mocks.NewMockCloud(mockCtl)
+
+				driver := &Driver{
+					endpoint: endpoint,
+					cloud:    mockCloud,
+				}
+
+				req := &csi.ValidateVolumeCapabilitiesRequest{
+					VolumeId: "fs-abcd1234:/data/imported",
+					VolumeCapabilities: []*csi.VolumeCapability{
+						stdVolCapValid,
+					},
+				}
+
+				ctx := context.Background()
+				res, err := driver.ValidateVolumeCapabilities(ctx, req)
+				if err != nil {
+					t.Fatalf("ValidateVolumeCapabilities failed: %v", err)
+				}
+
+				if res.Confirmed == nil {
+					t.Fatalf("Capability is not supported")
+				}
+				mockCtl.Finish()
+			},
+		},
+		{
+			name: "Success: Unsupported volume capability",
+			testFunc: func(t *testing.T) {
+				mockCtl := gomock.NewController(t)
+				mockCloud := mocks.NewMockCloud(mockCtl)
+
+				driver := &Driver{
+					endpoint: endpoint,
+					cloud:    mockCloud,
+				}
+
+				req := &csi.ValidateVolumeCapabilitiesRequest{
+					VolumeId: volumeId,
+					VolumeCapabilities: []*csi.VolumeCapability{
+						stdVolCapInvalid,
+					},
+				}
+
+				ctx := context.Background()
+				res, err := driver.ValidateVolumeCapabilities(ctx, req)
+				if err != nil {
+					t.Fatalf("ValidateVolumeCapabilities failed: %v", err)
+				}
+
+				if res.Confirmed != nil {
+					t.Fatal("ValidateVolumeCapabilities did not fail")
+				}
+				mockCtl.Finish()
+			},
+		},
+		{
+			name: "Fail: Volume Id is missing",
+			testFunc: func(t *testing.T) {
+				mockCtl := gomock.NewController(t)
+				mockCloud := mocks.NewMockCloud(mockCtl)
+
+				driver := &Driver{
+					endpoint: endpoint,
+					cloud:    mockCloud,
+				}
+
+				req := &csi.ValidateVolumeCapabilitiesRequest{
+					VolumeCapabilities: []*csi.VolumeCapability{
+						stdVolCapValid,
+					},
+				}
+
+				ctx := context.Background()
+				_, err := driver.ValidateVolumeCapabilities(ctx, req)
+				if err == nil {
+					t.Fatal("ValidateVolumeCapabilities did not fail")
+				}
+				mockCtl.Finish()
+			},
+		},
+		{
+			name: "Fail: Volume Capabilities is missing",
+			testFunc: func(t *testing.T) {
+				mockCtl := gomock.NewController(t)
+				mockCloud := mocks.NewMockCloud(mockCtl)
+
+				driver := &Driver{
+					endpoint: endpoint,
+					cloud:    mockCloud,
+				}
+
+				req := &csi.ValidateVolumeCapabilitiesRequest{
+					VolumeId: volumeId,
+				}
+
+				ctx := context.Background()
+				_, err := driver.ValidateVolumeCapabilities(ctx, req)
+				if err == nil {
+					t.Fatal("ValidateVolumeCapabilities did not fail")
+				}
 				mockCtl.Finish()
 			},
 		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, tc.testFunc)
+	}
+}
+
+func TestValidateVolumeCapabilitiesAccessModes(t *testing.T) {
+	testCases := []struct {
+		mode      csi.VolumeCapability_AccessMode_Mode
+		confirmed bool
+	}{
+		{mode: csi.VolumeCapability_AccessMode_UNKNOWN, confirmed: false},
+		{mode: csi.VolumeCapability_AccessMode_SINGLE_NODE_WRITER, confirmed: true},
+		{mode: csi.VolumeCapability_AccessMode_SINGLE_NODE_READER_ONLY, confirmed: false},
+		{mode: csi.VolumeCapability_AccessMode_MULTI_NODE_READER_ONLY, confirmed: true},
+		{mode: csi.VolumeCapability_AccessMode_MULTI_NODE_SINGLE_WRITER, confirmed: false},
+		{mode: csi.VolumeCapability_AccessMode_MULTI_NODE_MULTI_WRITER, confirmed: true},
+		{mode: csi.VolumeCapability_AccessMode_SINGLE_NODE_SINGLE_WRITER, confirmed: false},
+		{mode: csi.VolumeCapability_AccessMode_SINGLE_NODE_MULTI_WRITER, confirmed: false},
+	}
+
+	if len(testCases) != len(csi.VolumeCapability_AccessMode_Mode_name) {
+		t.Fatalf("Not every access mode is tested, expected %v test cases", len(csi.VolumeCapability_AccessMode_Mode_name))
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.mode.String(), func(t *testing.T) {
+			driver := &Driver{endpoint: "endpoint"}
+			req := &csi.ValidateVolumeCapabilitiesRequest{
+				VolumeId: "fs-abcd1234::fsap-abcd1234xyz987",
+				VolumeCapabilities: []*csi.VolumeCapability{
+					{
+						AccessType: &csi.VolumeCapability_Mount{
+							Mount: &csi.VolumeCapability_MountVolume{},
+						},
+						AccessMode: &csi.VolumeCapability_AccessMode{
+							Mode: tc.mode,
+						},
+					},
+				},
+			}
+
+			res, err := driver.ValidateVolumeCapabilities(context.Background(), req)
+			if err != nil {
+				t.Fatalf("ValidateVolumeCapabilities failed: %v", err)
+			}
+			if (res.Confirmed != nil) != tc.confirmed {
+				t.Fatalf("Access mode %v confirmed mismatched. Expected: %v, Actual: %v", tc.mode, tc.confirmed, res.Confirmed != nil)
+			}
+		})
+	}
+}
+
+func TestControllerGetCapabilities(t *testing.T) {
+	var endpoint = "endpoint"
+	mockCtl := gomock.NewController(t)
+	mockCloud := mocks.NewMockCloud(mockCtl)
+
+	driver := &Driver{
+		endpoint: endpoint,
+		cloud:    mockCloud,
+	}
+
+	ctx := context.Background()
+	_, err := driver.ControllerGetCapabilities(ctx, &csi.ControllerGetCapabilitiesRequest{})
+	if err != nil {
+		t.Fatalf("ControllerGetCapabilities failed: %v", err)
+	}
+}
+
+func TestControllerGetVolume(t *testing.T) {
+	var (
+		endpoint       = "endpoint"
+		fsId           = "fs-abcd1234"
+		apId           = "fsap-abcd1234xyz987"
+		volumeId       = "fs-abcd1234::fsap-abcd1234xyz987"
+		fsOnlyVolumeId = "fs-abcd1234"
+	)
+	testCases := []struct {
+		name     string
+		testFunc func(t *testing.T)
+	}{
 		{
-			name: "Fail: Fail to make directory for access point mount",
+			name: "Success: Access point available",
 			testFunc: func(t *testing.T) {
 				mockCtl := gomock.NewController(t)
 				mockCloud := mocks.NewMockCloud(mockCtl)
-				mockMounter := mocks.NewMockMounter(mockCtl)
 
 				driver := &Driver{
-					endpoint:                 endpoint,
-					cloud:                    mockCloud,
-					mounter:                  mockMounter,
-					gidAllocator:             NewGidAllocator(),
-					deleteAccessPointRootDir: true,
-				}
-
-				req := &csi.DeleteVolumeRequest{
-					VolumeId: volumeId,
+					endpoint: endpoint,
+					cloud:    mockCloud,
 				}
 
 				accessPoint := &cloud.AccessPoint{
-					AccessPointId:      apId,
-					FileSystemId:       fsId,
-					AccessPointRootDir: "",
-					CapacityGiB:        0,
+					AccessPointId:  apId,
+					FileSystemId:   fsId,
+					LifeCycleState: "available",
 				}
 
 				ctx := context.Background()
-				mockMounter.EXPECT().MakeDir(gomock.Any()).Return(errors.New("Failed to makeDir"))
 				mockCloud.EXPECT().DescribeAccessPoint(gomock.Eq(ctx), gomock.Eq(apId)).Return(accessPoint, nil)
-				_, err := driver.DeleteVolume(ctx, req)
-				if err == nil {
-					t.Fatal("DeleteVolume did not fail")
+				res, err := driver.ControllerGetVolume(ctx, &csi.ControllerGetVolumeRequest{VolumeId: volumeId})
+				if err != nil {
+					t.Fatalf("ControllerGetVolume failed: %v", err)
+				}
+				if res.Volume.VolumeId != volumeId {
+					t.Fatalf("Volume ID mismatched. Expected: %v, actual: %v", volumeId, res.Volume.VolumeId)
+				}
+				if res.Status.VolumeCondition.Abnormal {
+					t.Fatalf("Volume condition is abnormal: %v", res.Status.VolumeCondition.Message)
 				}
 				mockCtl.Finish()
 			},
 		},
 		{
-			name: "Fail: Fail to mount file system on directory for access point root directory removal",
+			name: "Success: Volume of another region",
 			testFunc: func(t *testing.T) {
 				mockCtl := gomock.NewController(t)
 				mockCloud := mocks.NewMockCloud(mockCtl)
-				mockMounter := mocks.NewMockMounter(mockCtl)
+				regionalCloud := mocks.NewMockCloud(mockCtl)
 
 				driver := &Driver{
-					endpoint:                 endpoint,
-					cloud:                    mockCloud,
-					mounter:                  mockMounter,
-					gidAllocator:             NewGidAllocator(),
-					deleteAccessPointRootDir: true,
-				}
-
-				req := &csi.DeleteVolumeRequest{
-					VolumeId: volumeId,
+					endpoint: endpoint,
+					cloud:    mockCloud,
 				}
 
 				accessPoint := &cloud.AccessPoint{
-					AccessPointId:      apId,
-					FileSystemId:       fsId,
-					AccessPointRootDir: "",
-					CapacityGiB:        0,
+					AccessPointId:  apId,
+					FileSystemId:   fsId,
+					LifeCycleState: "available",
 				}
 
 				ctx := context.Background()
-				mockMounter.EXPECT().MakeDir(gomock.Any()).Return(nil)
-				mockMounter.EXPECT().Mount(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(errors.New("Failed to mount"))
-				mockCloud.EXPECT().DescribeAccessPoint(gomock.Eq(ctx), gomock.Eq(apId)).Return(accessPoint, nil)
-				_, err := driver.DeleteVolume(ctx, req)
-				if err == nil {
-					t.Fatal("DeleteVolume did not fail")
+				regionalVolumeId := "arn:aws:elasticfilesystem:eu-west-1:123456789012:file-system/" + volumeId
+				mockCloud.EXPECT().WithRegion(gomock.Eq("eu-west-1")).Return(regionalCloud, nil)
+				regionalCloud.EXPECT().DescribeAccessPoint(gomock.Eq(ctx), gomock.Eq(apId)).Return(accessPoint, nil)
+				res, err := driver.ControllerGetVolume(ctx, &csi.ControllerGetVolumeRequest{VolumeId: regionalVolumeId})
+				if err != nil {
+					t.Fatalf("ControllerGetVolume failed: %v", err)
+				}
+				if res.Volume.VolumeId != regionalVolumeId {
+					t.Fatalf("Volume ID mismatched. Expected: %v, actual: %v", regionalVolumeId, res.Volume.VolumeId)
 				}
 				mockCtl.Finish()
 			},
 		},
 		{
-			name: "Fail: Fail to unmount file system after access point root directory removal",
+			name: "Success: Capacity is the quota of the access point",
 			testFunc: func(t *testing.T) {
 				mockCtl := gomock.NewController(t)
 				mockCloud := mocks.NewMockCloud(mockCtl)
-				mockMounter := mocks.NewMockMounter(mockCtl)
 
 				driver := &Driver{
-					endpoint:                 endpoint,
-					cloud:                    mockCloud,
-					mounter:                  mockMounter,
-					gidAllocator:             NewGidAllocator(),
-					deleteAccessPointRootDir: true,
-				}
-
-				req := &csi.DeleteVolumeRequest{
-					VolumeId: volumeId,
+					endpoint: endpoint,
+					cloud:    mockCloud,
 				}
 
 				accessPoint := &cloud.AccessPoint{
-					AccessPointId:      apId,
-					FileSystemId:       fsId,
-					AccessPointRootDir: "",
-					CapacityGiB:        0,
+					AccessPointId:  apId,
+					FileSystemId:   fsId,
+					LifeCycleState: "available",
+					Tags:           map[string]string{cloud.QuotaBytesTagKey: "1073741824", RequestedBytesTagKey: "5368709120"},
 				}
 
 				ctx := context.Background()
-				mockMounter.EXPECT().MakeDir(gomock.Any()).Return(nil)
-				mockMounter.EXPECT().Mount(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
-				mockMounter.EXPECT().Unmount(gomock.Any()).Return(errors.New("Failed to unmount"))
 				mockCloud.EXPECT().DescribeAccessPoint(gomock.Eq(ctx), gomock.Eq(apId)).Return(accessPoint, nil)
-				_, err := driver.DeleteVolume(ctx, req)
-				if err == nil {
-					t.Fatal("DeleteVolume did not fail")
+				res, err := driver.ControllerGetVolume(ctx, &csi.ControllerGetVolumeRequest{VolumeId: volumeId})
+				if err != nil {
+					t.Fatalf("ControllerGetVolume failed: %v", err)
+				}
+				if res.Volume.CapacityBytes != 1073741824 {
+					t.Fatalf("Capacity mismatched. Expected: %v, actual: %v", 1073741824, res.Volume.CapacityBytes)
 				}
 				mockCtl.Finish()
 			},
 		},
 		{
-			name: "Success: Access Point already deleted",
+			name: "Success: Capacity falls back to the requested size",
 			testFunc: func(t *testing.T) {
 				mockCtl := gomock.NewController(t)
 				mockCloud := mocks.NewMockCloud(mockCtl)
 
 				driver := &Driver{
-					endpoint:     endpoint,
-					cloud:        mockCloud,
-					gidAllocator: NewGidAllocator(),
+					endpoint: endpoint,
+					cloud:    mockCloud,
 				}
 
-				req := &csi.DeleteVolumeRequest{
-					VolumeId: volumeId,
+				accessPoint := &cloud.AccessPoint{
+					AccessPointId:  apId,
+					FileSystemId:   fsId,
+					LifeCycleState: "available",
+					Tags:           map[string]string{RequestedBytesTagKey: "5368709120"},
 				}
 
 				ctx := context.Background()
-				mockCloud.EXPECT().DeleteAccessPoint(gomock.Eq(ctx), gomock.Eq(apId)).Return(cloud.ErrNotFound)
-				_, err := driver.DeleteVolume(ctx, req)
+				mockCloud.EXPECT().DescribeAccessPoint(gomock.Eq(ctx), gomock.Eq(apId)).Return(accessPoint, nil)
+				res, err := driver.ControllerGetVolume(ctx, &csi.ControllerGetVolumeRequest{VolumeId: volumeId})
 				if err != nil {
-					t.Fatalf("Delete Volume failed: %v", err)
+					t.Fatalf("ControllerGetVolume failed: %v", err)
+				}
+				if res.Volume.CapacityBytes != 5368709120 {
+					t.Fatalf("Capacity mismatched. Expected: %v, actual: %v", 5368709120, res.Volume.CapacityBytes)
 				}
 				mockCtl.Finish()
 			},
 		},
 		{
-			name: "Fail: DeleteAccessPoint access denied",
+			name: "Success: Capacity unknown without size tags",
 			testFunc: func(t *testing.T) {
 				mockCtl := gomock.NewController(t)
 				mockCloud := mocks.NewMockCloud(mockCtl)
 
 				driver := &Driver{
-					endpoint:     endpoint,
-					cloud:        mockCloud,
-					gidAllocator: NewGidAllocator(),
+					endpoint: endpoint,
+					cloud:    mockCloud,
 				}
 
-				req := &csi.DeleteVolumeRequest{
-					VolumeId: volumeId,
+				accessPoint := &cloud.AccessPoint{
+					AccessPointId:  apId,
+					FileSystemId:   fsId,
+					LifeCycleState: "available",
+					Tags:           map[string]string{},
 				}
 
 				ctx := context.Background()
-				mockCloud.EXPECT().DeleteAccessPoint(gomock.Eq(ctx), gomock.Eq(apId)).Return(cloud.ErrAccessDenied)
-				_, err := driver.DeleteVolume(ctx, req)
-				if err == nil {
-					t.Fatal("DeleteVolume did not fail")
+				mockCloud.EXPECT().DescribeAccessPoint(gomock.Eq(ctx), gomock.Eq(apId)).Return(accessPoint, nil)
+				res, err := driver.ControllerGetVolume(ctx, &csi.ControllerGetVolumeRequest{VolumeId: volumeId})
+				if err != nil {
+					t.Fatalf("ControllerGetVolume failed: %v", err)
+				}
+				if res.Volume.CapacityBytes != 0 {
+					t.Fatalf("Capacity mismatched. Expected: %v, actual: %v", 0, res.Volume.CapacityBytes)
 				}
 				mockCtl.Finish()
 			},
 		},
 		{
-			name: "Fail: DeleteVolume fails",
+			name: "Success: Invalid quota tag is ignored",
 			testFunc: func(t *testing.T) {
 				mockCtl := gomock.NewController(t)
 				mockCloud := mocks.NewMockCloud(mockCtl)
 
 				driver := &Driver{
-					endpoint:     endpoint,
-					cloud:        mockCloud,
-					gidAllocator: NewGidAllocator(),
+					endpoint: endpoint,
+					cloud:    mockCloud,
 				}
 
-				req := &csi.DeleteVolumeRequest{
-					VolumeId: volumeId,
+				accessPoint := &cloud.AccessPoint{
+					AccessPointId:  apId,
+					FileSystemId:   fsId,
+					LifeCycleState: "available",
+					Tags:           map[string]string{cloud.QuotaBytesTagKey: "1Gi", RequestedBytesTagKey: "5368709120"},
 				}
 
 				ctx := context.Background()
-				mockCloud.EXPECT().DeleteAccessPoint(gomock.Eq(ctx), gomock.Eq(apId)).Return(errors.New("Delete Volume failed"))
-				_, err := driver.DeleteVolume(ctx, req)
-				if err == nil {
-					t.Fatal("DeleteVolume did not fail")
+				mockCloud.EXPECT().DescribeAccessPoint(gomock.Eq(ctx), gomock.Eq(apId)).Return(accessPoint, nil)
+				res, err := driver.ControllerGetVolume(ctx, &csi.ControllerGetVolumeRequest{VolumeId: volumeId})
+				if err != nil {
+					t.Fatalf("ControllerGetVolume failed: %v", err)
+				}
+				if res.Volume.CapacityBytes != 5368709120 {
+					t.Fatalf("Capacity mismatched. Expected: %v, actual: %v", 5368709120, res.Volume.CapacityBytes)
 				}
 				mockCtl.Finish()
 			},
 		},
 		{
-			name: "Fail: Access Point is missing in volume Id",
+			name: "Success: Access point deleting is reported abnormal",
 			testFunc: func(t *testing.T) {
 				mockCtl := gomock.NewController(t)
 				mockCloud := mocks.NewMockCloud(mockCtl)
 
 				driver := &Driver{
-					endpoint:     endpoint,
-					cloud:        mockCloud,
-					gidAllocator: NewGidAllocator(),
+					endpoint: endpoint,
+					cloud:    mockCloud,
 				}
 
-				req := &csi.DeleteVolumeRequest{
-					VolumeId: "fs-abcd1234",
+				accessPoint := &cloud.AccessPoint{
+					AccessPointId:  apId,
+					FileSystemId:   fsId,
+					LifeCycleState: "deleting",
 				}
 
 				ctx := context.Background()
-				_, err := driver.DeleteVolume(ctx, req)
-				if err == nil {
-					t.Fatal("DeleteVolume did not fail")
+				mockCloud.EXPECT().DescribeAccessPoint(gomock.Eq(ctx), gomock.Eq(apId)).Return(accessPoint, nil)
+				res, err := driver.ControllerGetVolume(ctx, &csi.ControllerGetVolumeRequest{VolumeId: volumeId})
+				if err != nil {
+					t.Fatalf("ControllerGetVolume failed: %v", err)
+				}
+				if !res.Status.VolumeCondition.Abnormal {
+					t.Fatalf("Volume condition is not abnormal")
+				}
+				if !strings.Contains(res.Status.VolumeCondition.Message, "deleting") {
+					t.Fatalf("Volume condition message does not contain the lifecycle state: %v", res.Status.VolumeCondition.Message)
 				}
 				mockCtl.Finish()
 			},
 		},
 		{
-			name: "Fail: Cannot assume role for x-account",
+			name: "Success: Access point error is reported abnormal",
 			testFunc: func(t *testing.T) {
 				mockCtl := gomock.NewController(t)
 				mockCloud := mocks.NewMockCloud(mockCtl)
 
 				driver := &Driver{
-					endpoint:     endpoint,
-					cloud:        mockCloud,
-					gidAllocator: NewGidAllocator(),
-					tags:         parseTagsFromStr(""),
+					endpoint: endpoint,
+					cloud:    mockCloud,
 				}
 
-				secrets := map[string]string{}
-				secrets["awsRoleArn"] = "arn:aws:iam::1234567890:role/EFSCrossAccountRole"
-
-				req := &csi.DeleteVolumeRequest{
-					VolumeId: volumeId,
-					Secrets:  secrets,
+				accessPoint := &cloud.AccessPoint{
+					AccessPointId:  apId,
+					FileSystemId:   fsId,
+					LifeCycleState: "error",
 				}
 
 				ctx := context.Background()
-
-				_, err := driver.DeleteVolume(ctx, req)
-
-				if err == nil {
-					t.Fatalf("DeleteVolume did not fail")
+				mockCloud.EXPECT().DescribeAccessPoint(gomock.Eq(ctx), gomock.Eq(apId)).Return(accessPoint, nil)
+				res, err := driver.ControllerGetVolume(ctx, &csi.ControllerGetVolumeRequest{VolumeId: volumeId})
+				if err != nil {
+					t.Fatalf("ControllerGetVolume failed: %v", err)
+				}
+				if !res.Status.VolumeCondition.Abnormal {
+					t.Fatalf("Volume condition is not abnormal")
 				}
-
 				mockCtl.Finish()
 			},
 		},
-	}
-
-	for _, tc := range testCases {
-		t.Run(tc.name, tc.testFunc)
-	}
-}
-
-func TestValidateVolumeCapabilities(t *testing.T) {
-	var (
-		endpoint       = "endpoint"
-		volumeId       = "fs-abcd1234::fsap-abcd1234xyz987"
-		stdVolCapValid = &csi.VolumeCapability{
-			AccessType: &csi.VolumeCapability_Mount{
-				Mount: &csi.VolumeCapability_MountVolume{},
-			},
-			AccessMode: &csi.VolumeCapability_AccessMode{
-				Mode: csi.VolumeCapability_AccessMode_MULTI_NODE_MULTI_WRITER,
-			},
-		}
-		stdVolCapInvalid = &csi.VolumeCapability{
-			AccessType: &csi.VolumeCapability_Mount{
-				Mount: &csi.VolumeCapability_MountVolume{},
-			},
-			AccessMode: &csi.VolumeCapability_AccessMode{
-				Mode: csi.VolumeCapability_AccessMode_MULTI_NODE_READER_ONLY,
-			},
-		}
-	)
-	testCases := []struct {
-		name     string
-		testFunc func(t *testing.T)
-	}{
 		{
-			name: "Success",
+			name: "Success: Volume without access point",
 			testFunc: func(t *testing.T) {
 				mockCtl := gomock.NewController(t)
 				mockCloud := mocks.NewMockCloud(mockCtl)
@@ -3102,27 +6779,24 @@ func TestValidateVolumeCapabilities(t *testing.T) {
 					cloud:    mockCloud,
 				}
 
-				req := &csi.ValidateVolumeCapabilitiesRequest{
-					VolumeId: volumeId,
-					VolumeCapabilities: []*csi.VolumeCapability{
-						stdVolCapValid,
-					},
+				fileSystem := &cloud.FileSystem{
+					FileSystemId: fsId,
 				}
 
 				ctx := context.Background()
-				res, err := driver.ValidateVolumeCapabilities(ctx, req)
+				mockCloud.EXPECT().DescribeFileSystem(gomock.Eq(ctx), gomock.Eq(fsId)).Return(fileSystem, nil)
+				res, err := driver.ControllerGetVolume(ctx, &csi.ControllerGetVolumeRequest{VolumeId: fsOnlyVolumeId})
 				if err != nil {
-					t.Fatalf("ValidateVolumeCapabilities failed: %v", err)
+					t.Fatalf("ControllerGetVolume failed: %v", err)
 				}
-
-				if res.Confirmed == nil {
-					t.Fatalf("Capability is not supported")
+				if res.Status.VolumeCondition.Abnormal {
+					t.Fatalf("Volume condition is abnormal: %v", res.Status.VolumeCondition.Message)
 				}
 				mockCtl.Finish()
 			},
 		},
 		{
-			name: "Success: Unsupported volume capability",
+			name: "Fail: Access point not found",
 			testFunc: func(t *testing.T) {
 				mockCtl := gomock.NewController(t)
 				mockCloud := mocks.NewMockCloud(mockCtl)
@@ -3132,27 +6806,17 @@ func TestValidateVolumeCapabilities(t *testing.T) {
 					cloud:    mockCloud,
 				}
 
-				req := &csi.ValidateVolumeCapabilitiesRequest{
-					VolumeId: volumeId,
-					VolumeCapabilities: []*csi.VolumeCapability{
-						stdVolCapInvalid,
-					},
-				}
-
 				ctx := context.Background()
-				res, err := driver.ValidateVolumeCapabilities(ctx, req)
-				if err != nil {
-					t.Fatalf("ValidateVolumeCapabilities failed: %v", err)
-				}
-
-				if res.Confirmed != nil {
-					t.Fatal("ValidateVolumeCapabilities did not fail")
+				mockCloud.EXPECT().DescribeAccessPoint(gomock.Eq(ctx), gomock.Eq(apId)).Return(nil, cloud.ErrNotFound)
+				_, err := driver.ControllerGetVolume(ctx, &csi.ControllerGetVolumeRequest{VolumeId: volumeId})
+				if status.Code(err) != codes.NotFound {
+					t.Fatalf("Expected NotFound, got: %v", err)
 				}
 				mockCtl.Finish()
 			},
 		},
 		{
-			name: "Fail: Volume Id is missing",
+			name: "Fail: Describe access point access denied",
 			testFunc: func(t *testing.T) {
 				mockCtl := gomock.NewController(t)
 				mockCloud := mocks.NewMockCloud(mockCtl)
@@ -3162,22 +6826,17 @@ func TestValidateVolumeCapabilities(t *testing.T) {
 					cloud:    mockCloud,
 				}
 
-				req := &csi.ValidateVolumeCapabilitiesRequest{
-					VolumeCapabilities: []*csi.VolumeCapability{
-						stdVolCapValid,
-					},
-				}
-
 				ctx := context.Background()
-				_, err := driver.ValidateVolumeCapabilities(ctx, req)
-				if err == nil {
-					t.Fatal("ValidateVolumeCapabilities did not fail")
+				mockCloud.EXPECT().DescribeAccessPoint(gomock.Eq(ctx), gomock.Eq(apId)).Return(nil, cloud.ErrAccessDenied)
+				_, err := driver.ControllerGetVolume(ctx, &csi.ControllerGetVolumeRequest{VolumeId: volumeId})
+				if status.Code(err) != codes.Unauthenticated {
+					t.Fatalf("Expected Unauthenticated, got: %v", err)
 				}
 				mockCtl.Finish()
 			},
 		},
 		{
-			name: "Fail: Volume Capabilities is missing",
+			name: "Fail: Volume ID not provided",
 			testFunc: func(t *testing.T) {
 				mockCtl := gomock.NewController(t)
 				mockCloud := mocks.NewMockCloud(mockCtl)
@@ -3187,14 +6846,10 @@ func TestValidateVolumeCapabilities(t *testing.T) {
 					cloud:    mockCloud,
 				}
 
-				req := &csi.ValidateVolumeCapabilitiesRequest{
-					VolumeId: volumeId,
-				}
-
 				ctx := context.Background()
-				_, err := driver.ValidateVolumeCapabilities(ctx, req)
-				if err == nil {
-					t.Fatal("ValidateVolumeCapabilities did not fail")
+				_, err := driver.ControllerGetVolume(ctx, &csi.ControllerGetVolumeRequest{})
+				if status.Code(err) != codes.InvalidArgument {
+					t.Fatalf("Expected InvalidArgument, got: %v", err)
 				}
 				mockCtl.Finish()
 			},
@@ -3206,23 +6861,6 @@ func TestValidateVolumeCapabilities(t *testing.T) {
 	}
 }
 
-func TestControllerGetCapabilities(t *testing.T) {
-	var endpoint = "endpoint"
-	mockCtl := gomock.NewController(t)
-	mockCloud := mocks.NewMockCloud(mockCtl)
-
-	driver := &Driver{
-		endpoint: endpoint,
-		cloud:    mockCloud,
-	}
-
-	ctx := context.Background()
-	_, err := driver.ControllerGetCapabilities(ctx, &csi.ControllerGetCapabilitiesRequest{})
-	if err != nil {
-		t.Fatalf("ControllerGetCapabilities failed: %v", err)
-	}
-}
-
 func verifyPathWhenUUIDIncluded(pathToVerify string, expectedPathWithoutUUID string) bool {
 	r := regexp.MustCompile("(.*)-([0-9A-fA-F]+-[0-9A-fA-F]+-[0-9A-fA-F]+-[0-9A-fA-F]+-[0-9A-fA-F]+$)")
 	matches := r.FindStringSubmatch(pathToVerify)
@@ -0,0 +1,198 @@
+package driver
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"k8s.io/klog/v2"
+
+	"github.com/kubernetes-sigs/aws-efs-csi-driver/pkg/cloud"
+)
+
+// directoryPermsPattern matches the octal permissions accepted by EFS for the root directory of an access point
+var directoryPermsPattern = regexp.MustCompile(`^[0-7]{3,4}$`)
+
+// ValidateSafeDefaultPerms validates the --safe-default-perms value, empty when unset
+func ValidateSafeDefaultPerms(value string) error {
+	if value == "" {
+		return nil
+	}
+	_, err := parseDirectoryPerms(value)
+	return err
+}
+
+// directoryPerms returns the directoryPerms parameter, or --safe-default-perms when the StorageClass omits it
+func (d *Driver) directoryPerms(volumeParams map[string]string) string {
+	if value, ok := volumeParams[DirectoryPerms]; ok {
+		return value
+	}
+	return d.safeDefaultPerms
+}
+
+// parseDirectoryPerms converts the octal directoryPerms parameter to a file mode, including the setuid, setgid and sticky bits
+func parseDirectoryPerms(value string) (os.FileMode, error) {
+	if !directoryPermsPattern.MatchString(value) {
+		return 0, fmt.Errorf("%q is not an octal permission of 3 or 4 digits", value)
+	}
+	perms, err := strconv.ParseUint(value, 8, 32)
+	if err != nil {
+		return 0, err
+	}
+	mode := os.FileMode(perms & 0777)
+	if perms&04000 != 0 {
+		mode |= os.ModeSetuid
+	}
+	if perms&02000 != 0 {
+		mode |= os.ModeSetgid
+	}
+	if perms&01000 != 0 {
+		mode |= os.ModeSticky
+	}
+	return mode, nil
+}
+
+// createRootDir creates the root directory of an access point through a temporary mount of the root of its file system,
+// with the permissions and ownership EFS would give it, for file system policies that do not let EFS create it.
+// A directory which already exists is left untouched. It returns the mount point of the file system, and a function
+// to call once the access point is created or has failed to be: it unmounts the file system, and removes the
+// directory it created when remove is true.
+func (d *Driver) createRootDir(ctx context.Context, localCloud cloud.Cloud, roleArn string, useIam bool, volName string, accessPointOpts *cloud.AccessPointOptions) (string, func(remove bool), error) {
+	fileSystemId, rootDir := accessPointOpts.FileSystemId, accessPointOpts.DirectoryPath
+	perms, err := parseDirectoryPerms(accessPointOpts.DirectoryPerms)
+	if err != nil {
+		return "", nil, status.Errorf(codes.InvalidArgument, "Invalid %v: %v", DirectoryPerms, err)
+	}
+
+	target, unmount, err := d.mountFileSystemRoot(ctx, localCloud, roleArn, useIam, fileSystemId, volName)
+	if err != nil {
+		return "", nil, err
+	}
+
+	dir := target + rootDir
+	if _, err := os.Stat(dir); err == nil {
+		klog.V(2).Infof("Root directory %q of File System %v already exists", rootDir, fileSystemId)
+		return target, func(bool) { unmount() }, nil
+	} else if !os.IsNotExist(err) {
+		unmount()
+		return "", nil, status.Errorf(codes.Internal, "Could not stat root directory %q of File System %v: %v", rootDir, fileSystemId, err)
+	}
+
+	if err := makeRootDir(dir, perms, accessPointOpts.OwnerUid, accessPointOpts.OwnerGid); err != nil {
+		if removeErr := os.RemoveAll(dir); removeErr != nil {
+			klog.Warningf("Could not delete root directory %q of File System %v: %v", rootDir, fileSystemId, removeErr)
+		}
+		unmount()
+		return "", nil, status.Errorf(codes.Internal, "Could not create root directory %q of File System %v: %v", rootDir, fileSystemId, err)
+	}
+	klog.V(2).Infof("Created root directory %q of File System %v with permissions %v owned by %v:%v", rootDir, fileSystemId, perms, accessPointOpts.OwnerUid, accessPointOpts.OwnerGid)
+
+	return target, func(remove bool) {
+		if remove {
+			if err := os.RemoveAll(dir); err != nil {
+				klog.Warningf("Could not delete root directory %q of File System %v: %v", rootDir, fileSystemId, err)
+			} else {
+				klog.V(2).Infof("Deleted root directory %q of File System %v", rootDir, fileSystemId)
+			}
+		}
+		unmount()
+	}, nil
+}
+
+// ensureBasePath creates the missing directories of basePath through a temporary mount of the root of the file system,
+// with the basePathPerms permissions and owned by uid and gid, -1 keeping the owner of the controller, so that they
+// are not created by EFS with the owner and permissions of the first access point under them. Directories which
+// already exist are left untouched.
+func (d *Driver) ensureBasePath(ctx context.Context, localCloud cloud.Cloud, roleArn string, useIam bool, volName, fileSystemId, basePath string, perms os.FileMode, uid, gid int64) error {
+	target, unmount, err := d.mountFileSystemRoot(ctx, localCloud, roleArn, useIam, fileSystemId, volName)
+	if err != nil {
+		return err
+	}
+	defer unmount()
+
+	dir := target
+	for _, name := range strings.Split(strings.Trim(basePath, "/"), "/") {
+		if name == "" {
+			continue
+		}
+		dir = filepath.Join(dir, name)
+		created, err := makeBasePathDir(dir, perms, uid, gid)
+		if err != nil {
+			return status.Errorf(codes.Internal, "Could not create %v %q of File System %v: %v", BasePath, basePath, fileSystemId, err)
+		}
+		if created {
+			klog.V(2).Infof("Created directory %q of File System %v with permissions %v owned by %v:%v", strings.TrimPrefix(dir, target), fileSystemId, perms, uid, gid)
+		}
+	}
+	return nil
+}
+
+// makeBasePathDir creates a directory with the given permissions and owner unless it exists, and returns whether it
+// created it. A directory created concurrently, e.g. by another CreateVolume call, is not an error.
+func makeBasePathDir(dir string, perms os.FileMode, uid, gid int64) (bool, error) {
+	if info, err := os.Stat(dir); err == nil {
+		if !info.IsDir() {
+			return false, fmt.Errorf("%q is not a directory", dir)
+		}
+		return false, nil
+	}
+	if err := os.Mkdir(dir, perms.Perm()); err != nil {
+		if os.IsExist(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	if err := os.Chmod(dir, perms); err != nil {
+		return true, err
+	}
+	if uid == -1 && gid == -1 {
+		return true, nil
+	}
+	return true, os.Chown(dir, int(uid), int(gid))
+}
+
+// mountFileSystemRoot mounts the root of the file system at a temporary mount point named after name,
+// and returns the mount point and the function unmounting it.
+func (d *Driver) mountFileSystemRoot(ctx context.Context, localCloud cloud.Cloud, roleArn string, useIam bool, fileSystemId, name string) (string, func(), error) {
+	mountOptions := d.getRootMountOptions(ctx, localCloud, roleArn, fileSystemId, useIam)
+	target := d.getTempMountPathPrefix() + "/" + name
+	if err := d.mounter.MakeDir(target); err != nil {
+		return "", nil, status.Errorf(codes.Internal, "Could not create dir %q: %v", target, err)
+	}
+	if err := d.mountForCleanup(fileSystemId, target, mountOptions); err != nil {
+		os.Remove(target)
+		return "", nil, status.Errorf(codes.Internal, "Could not mount %q at %q: %v", fileSystemId, target, err)
+	}
+	unmount := func() {
+		if err := d.mounter.Unmount(target); err != nil {
+			klog.Warningf("Could not unmount %q: %v", target, err)
+			return
+		}
+		// Only the mount point itself is removed, in case the file system is still mounted
+		if err := os.Remove(target); err != nil {
+			klog.Warningf("Could not delete %q: %v", target, err)
+		}
+	}
+	return target, unmount, nil
+}
+
+// makeRootDir creates a directory and its missing parents. Only the directory itself gets the permissions and owner.
+// It is chmodded after its creation, as the umask applies to the mode passed to mkdir.
+func makeRootDir(dir string, perms os.FileMode, uid, gid int64) error {
+	if err := os.MkdirAll(filepath.Dir(dir), 0755); err != nil {
+		return err
+	}
+	if err := os.Mkdir(dir, perms.Perm()); err != nil {
+		return err
+	}
+	if err := os.Chmod(dir, perms); err != nil {
+		return err
+	}
+	return os.Chown(dir, int(uid), int(gid))
+}
@@ -0,0 +1,534 @@
+package driver
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strconv"
+	"syscall"
+	"testing"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	"github.com/golang/mock/gomock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/kubernetes-sigs/aws-efs-csi-driver/pkg/cloud"
+	"github.com/kubernetes-sigs/aws-efs-csi-driver/pkg/driver/mocks"
+)
+
+func TestCreateVolumeCreateDirMode(t *testing.T) {
+	var (
+		fsId      = "fs-abcd1234"
+		apId      = "fsap-abcd1234xyz987"
+		volName   = "volumeName"
+		uid       = os.Getuid()
+		gid       = os.Getgid()
+		stdVolCap = &csi.VolumeCapability{
+			AccessType: &csi.VolumeCapability_Mount{
+				Mount: &csi.VolumeCapability_MountVolume{},
+			},
+			AccessMode: &csi.VolumeCapability_AccessMode{
+				Mode: csi.VolumeCapability_AccessMode_MULTI_NODE_MULTI_WRITER,
+			},
+		}
+	)
+
+	createRequest := func(params map[string]string) *csi.CreateVolumeRequest {
+		parameters := map[string]string{
+			ProvisioningMode: "efs-ap",
+			FsId:             fsId,
+			DirectoryPerms:   "2775",
+			Uid:              strconv.Itoa(uid),
+			Gid:              strconv.Itoa(gid),
+		}
+		for k, v := range params {
+			parameters[k] = v
+		}
+		return &csi.CreateVolumeRequest{
+			Name:               volName,
+			VolumeCapabilities: []*csi.VolumeCapability{stdVolCap},
+			Parameters:         parameters,
+		}
+	}
+
+	accessPoint := &cloud.AccessPoint{
+		AccessPointId: apId,
+		FileSystemId:  fsId,
+	}
+
+	// unmountTarget simulates the unmount of the file system by removing what was created under the mount point
+	unmountTarget := func(target string) error {
+		return os.RemoveAll(target + "/" + volName)
+	}
+
+	testCases := []struct {
+		name     string
+		testFunc func(t *testing.T)
+	}{
+		{
+			name: "Success: Directory created by the access point",
+			testFunc: func(t *testing.T) {
+				mockCtl := gomock.NewController(t)
+				mockCloud := mocks.NewMockCloud(mockCtl)
+				mockMounter := mocks.NewMockMounter(mockCtl)
+				driver := &Driver{
+					cloud:        mockCloud,
+					mounter:      mockMounter,
+					gidAllocator: NewGidAllocator(),
+				}
+
+				ctx := context.Background()
+				mockCloud.EXPECT().DescribeFileSystem(gomock.Eq(ctx), gomock.Eq(fsId)).Return(&cloud.FileSystem{FileSystemId: fsId}, nil)
+				mockCloud.EXPECT().CreateAccessPoint(gomock.Eq(ctx), gomock.Any(), gomock.Any(), gomock.Any()).Return(accessPoint, nil)
+				_, err := driver.CreateVolume(ctx, createRequest(map[string]string{CreateDirMode: CreateDirAccessPoint}))
+				if err != nil {
+					t.Fatalf("CreateVolume failed: %v", err)
+				}
+				mockCtl.Finish()
+			},
+		},
+		{
+			name: "Success: Directory created through a mount",
+			testFunc: func(t *testing.T) {
+				mockCtl := gomock.NewController(t)
+				mockCloud := mocks.NewMockCloud(mockCtl)
+				mockMounter := mocks.NewMockMounter(mockCtl)
+				tempMountDir := t.TempDir()
+				driver := &Driver{
+					cloud:               mockCloud,
+					mounter:             mockMounter,
+					gidAllocator:        NewGidAllocator(),
+					tempMountPathPrefix: tempMountDir,
+				}
+
+				target := tempMountDir + "/" + volName
+				rootDir := target + "/" + volName
+				ctx := context.Background()
+				mockCloud.EXPECT().DescribeFileSystem(gomock.Eq(ctx), gomock.Eq(fsId)).Return(&cloud.FileSystem{FileSystemId: fsId}, nil)
+				mockMounter.EXPECT().MakeDir(gomock.Eq(target)).DoAndReturn(func(target string) error {
+					return os.MkdirAll(target, 0755)
+				})
+				mockMounter.EXPECT().Mount(gomock.Eq(fsId), gomock.Eq(target), gomock.Eq("efs"), gomock.Any()).Return(nil)
+				mockCloud.EXPECT().CreateAccessPoint(gomock.Eq(ctx), gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+					func(ctx context.Context, clientToken string, accessPointOpts *cloud.AccessPointOptions, reuseAccessPoint bool) (*cloud.AccessPoint, error) {
+						info, err := os.Stat(rootDir)
+						if err != nil {
+							t.Fatalf("Root directory was not created before the access point: %v", err)
+						}
+						if expected := os.FileMode(0775) | os.ModeDir | os.ModeSetgid; info.Mode() != expected {
+							t.Fatalf("Root directory mode mismatched. Expected: %v, Actual: %v", expected, info.Mode())
+						}
+						stat := info.Sys().(*syscall.Stat_t)
+						if int(stat.Uid) != uid || int(stat.Gid) != gid {
+							t.Fatalf("Root directory owner mismatched. Expected: %v:%v, Actual: %v:%v", uid, gid, stat.Uid, stat.Gid)
+						}
+						if accessPointOpts.DirectoryPath != "/"+volName {
+							t.Fatalf("Access point directory mismatched. Expected: %v, Actual: %v", "/"+volName, accessPointOpts.DirectoryPath)
+						}
+						return accessPoint, nil
+					})
+				mockMounter.EXPECT().Unmount(gomock.Eq(target)).DoAndReturn(func(target string) error {
+					if _, err := os.Stat(rootDir); err != nil {
+						t.Fatalf("Root directory was removed: %v", err)
+					}
+					return unmountTarget(target)
+				})
+
+				_, err := driver.CreateVolume(ctx, createRequest(map[string]string{CreateDirMode: CreateDirMount}))
+				if err != nil {
+					t.Fatalf("CreateVolume failed: %v", err)
+				}
+				if _, err := os.Stat(target); !os.IsNotExist(err) {
+					t.Fatalf("Mount point %v was not removed: %v", target, err)
+				}
+				mockCtl.Finish()
+			},
+		},
+		{
+			name: "Fail: Directory created through a mount is removed when the access point creation fails",
+			testFunc: func(t *testing.T) {
+				mockCtl := gomock.NewController(t)
+				mockCloud := mocks.NewMockCloud(mockCtl)
+				mockMounter := mocks.NewMockMounter(mockCtl)
+				tempMountDir := t.TempDir()
+				driver := &Driver{
+					cloud:               mockCloud,
+					mounter:             mockMounter,
+					gidAllocator:        NewGidAllocator(),
+					tempMountPathPrefix: tempMountDir,
+				}
+
+				target := tempMountDir + "/" + volName
+				rootDir := target + "/" + volName
+				ctx := context.Background()
+				mockCloud.EXPECT().DescribeFileSystem(gomock.Eq(ctx), gomock.Eq(fsId)).Return(&cloud.FileSystem{FileSystemId: fsId}, nil)
+				mockMounter.EXPECT().MakeDir(gomock.Eq(target)).DoAndReturn(func(target string) error {
+					return os.MkdirAll(target, 0755)
+				})
+				mockMounter.EXPECT().Mount(gomock.Eq(fsId), gomock.Eq(target), gomock.Eq("efs"), gomock.Any()).Return(nil)
+				mockCloud.EXPECT().CreateAccessPoint(gomock.Eq(ctx), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, errors.New("CreateAccessPoint failed"))
+				mockMounter.EXPECT().Unmount(gomock.Eq(target)).DoAndReturn(func(target string) error {
+					if _, err := os.Stat(rootDir); !os.IsNotExist(err) {
+						t.Fatalf("Root directory was not removed before the unmount: %v", err)
+					}
+					return unmountTarget(target)
+				})
+
+				_, err := driver.CreateVolume(ctx, createRequest(map[string]string{CreateDirMode: CreateDirMount}))
+				if status.Code(err) != codes.Internal {
+					t.Fatalf("Expected an Internal error, got: %v", err)
+				}
+				if _, err := os.Stat(target); !os.IsNotExist(err) {
+					t.Fatalf("Mount point %v was not removed: %v", target, err)
+				}
+				mockCtl.Finish()
+			},
+		},
+		{
+			name: "Fail: File system cannot be mounted",
+			testFunc: func(t *testing.T) {
+				mockCtl := gomock.NewController(t)
+				mockCloud := mocks.NewMockCloud(mockCtl)
+				mockMounter := mocks.NewMockMounter(mockCtl)
+				tempMountDir := t.TempDir()
+				driver := &Driver{
+					cloud:               mockCloud,
+					mounter:             mockMounter,
+					gidAllocator:        NewGidAllocator(),
+					tempMountPathPrefix: tempMountDir,
+				}
+
+				target := tempMountDir + "/" + volName
+				ctx := context.Background()
+				mockCloud.EXPECT().DescribeFileSystem(gomock.Eq(ctx), gomock.Eq(fsId)).Return(&cloud.FileSystem{FileSystemId: fsId}, nil)
+				mockMounter.EXPECT().MakeDir(gomock.Eq(target)).DoAndReturn(func(target string) error {
+					return os.MkdirAll(target, 0755)
+				})
+				mockMounter.EXPECT().Mount(gomock.Eq(fsId), gomock.Eq(target), gomock.Eq("efs"), gomock.Any()).Return(errors.New("mount failed"))
+
+				_, err := driver.CreateVolume(ctx, createRequest(map[string]string{CreateDirMode: CreateDirMount}))
+				if status.Code(err) != codes.Internal {
+					t.Fatalf("Expected an Internal error, got: %v", err)
+				}
+				if _, err := os.Stat(target); !os.IsNotExist(err) {
+					t.Fatalf("Mount point %v was not removed: %v", target, err)
+				}
+				mockCtl.Finish()
+			},
+		},
+		{
+			name: "Fail: Invalid createDirMode",
+			testFunc: func(t *testing.T) {
+				mockCtl := gomock.NewController(t)
+				mockCloud := mocks.NewMockCloud(mockCtl)
+				driver := &Driver{
+					cloud:        mockCloud,
+					gidAllocator: NewGidAllocator(),
+				}
+
+				_, err := driver.CreateVolume(context.Background(), createRequest(map[string]string{CreateDirMode: "nfs"}))
+				if status.Code(err) != codes.InvalidArgument {
+					t.Fatalf("Expected an InvalidArgument error, got: %v", err)
+				}
+				mockCtl.Finish()
+			},
+		},
+		{
+			name: "Fail: Mount mode with invalid directoryPerms",
+			testFunc: func(t *testing.T) {
+				mockCtl := gomock.NewController(t)
+				mockCloud := mocks.NewMockCloud(mockCtl)
+				driver := &Driver{
+					cloud:        mockCloud,
+					gidAllocator: NewGidAllocator(),
+				}
+
+				_, err := driver.CreateVolume(context.Background(), createRequest(map[string]string{
+					CreateDirMode:  CreateDirMount,
+					DirectoryPerms: "rwx",
+				}))
+				if status.Code(err) != codes.InvalidArgument {
+					t.Fatalf("Expected an InvalidArgument error, got: %v", err)
+				}
+				mockCtl.Finish()
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, tc.testFunc)
+	}
+}
+
+func TestCreateVolumeBasePathPerms(t *testing.T) {
+	var (
+		fsId      = "fs-abcd1234"
+		apId      = "fsap-abcd1234xyz987"
+		volName   = "volumeName"
+		stdVolCap = &csi.VolumeCapability{
+			AccessType: &csi.VolumeCapability_Mount{
+				Mount: &csi.VolumeCapability_MountVolume{},
+			},
+			AccessMode: &csi.VolumeCapability_AccessMode{
+				Mode: csi.VolumeCapability_AccessMode_MULTI_NODE_MULTI_WRITER,
+			},
+		}
+	)
+
+	createRequest := func(params map[string]string) *csi.CreateVolumeRequest {
+		parameters := map[string]string{
+			ProvisioningMode: "efs-ap",
+			FsId:             fsId,
+			DirectoryPerms:   "700",
+			Uid:              "1000",
+			Gid:              "1000",
+		}
+		for k, v := range params {
+			parameters[k] = v
+		}
+		return &csi.CreateVolumeRequest{
+			Name:               volName,
+			VolumeCapabilities: []*csi.VolumeCapability{stdVolCap},
+			Parameters:         parameters,
+		}
+	}
+
+	accessPoint := &cloud.AccessPoint{
+		AccessPointId: apId,
+		FileSystemId:  fsId,
+	}
+
+	testCases := []struct {
+		name     string
+		testFunc func(t *testing.T)
+	}{
+		{
+			name: "Success: Missing directories of the base path are created",
+			testFunc: func(t *testing.T) {
+				mockCtl := gomock.NewController(t)
+				mockCloud := mocks.NewMockCloud(mockCtl)
+				mockMounter := mocks.NewMockMounter(mockCtl)
+				tempMountDir := t.TempDir()
+				driver := &Driver{
+					cloud:               mockCloud,
+					mounter:             mockMounter,
+					gidAllocator:        NewGidAllocator(),
+					tempMountPathPrefix: tempMountDir,
+				}
+
+				target := tempMountDir + "/" + volName
+				// The file system already has the first directory of the base path, which must be left untouched
+				existingDir := tempMountDir + "/fs/csi"
+				if err := os.MkdirAll(existingDir, 0700); err != nil {
+					t.Fatalf("Could not create %v: %v", existingDir, err)
+				}
+				ctx := context.Background()
+				mockCloud.EXPECT().DescribeFileSystem(gomock.Eq(ctx), gomock.Eq(fsId)).Return(&cloud.FileSystem{FileSystemId: fsId}, nil)
+				mockMounter.EXPECT().MakeDir(gomock.Eq(target)).Return(nil)
+				// The file system is simulated by a symbolic link to its directory
+				mockMounter.EXPECT().Mount(gomock.Eq(fsId), gomock.Eq(target), gomock.Eq("efs"), gomock.Any()).DoAndReturn(
+					func(source, target, fstype string, options []string) error {
+						return os.Symlink(tempMountDir+"/fs", target)
+					})
+				mockMounter.EXPECT().Unmount(gomock.Eq(target)).DoAndReturn(func(target string) error {
+					return os.Remove(target)
+				})
+				mockCloud.EXPECT().CreateAccessPoint(gomock.Eq(ctx), gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+					func(ctx context.Context, clientToken string, accessPointOpts *cloud.AccessPointOptions, reuseAccessPoint bool) (*cloud.AccessPoint, error) {
+						if accessPointOpts.DirectoryPath != "/csi/volumes/"+volName {
+							t.Fatalf("Access point directory mismatched. Expected: %v, Actual: %v", "/csi/volumes/"+volName, accessPointOpts.DirectoryPath)
+						}
+						return accessPoint, nil
+					})
+
+				_, err := driver.CreateVolume(ctx, createRequest(map[string]string{
+					BasePath:      "/csi/volumes",
+					BasePathPerms: "0711",
+				}))
+				if err != nil {
+					t.Fatalf("CreateVolume failed: %v", err)
+				}
+
+				for dir, expected := range map[string]os.FileMode{
+					existingDir:              0700 | os.ModeDir,
+					existingDir + "/volumes": 0711 | os.ModeDir,
+				} {
+					info, err := os.Stat(dir)
+					if err != nil {
+						t.Fatalf("Could not stat %v: %v", dir, err)
+					}
+					if info.Mode() != expected {
+						t.Fatalf("Mode of %v mismatched. Expected: %v, Actual: %v", dir, expected, info.Mode())
+					}
+				}
+				if _, err := os.Lstat(target); !os.IsNotExist(err) {
+					t.Fatalf("Mount point %v was not removed: %v", target, err)
+				}
+				mockCtl.Finish()
+			},
+		},
+		{
+			name: "Success: Created directories are owned by parentDirOwnerUid and parentDirOwnerGid",
+			testFunc: func(t *testing.T) {
+				mockCtl := gomock.NewController(t)
+				mockCloud := mocks.NewMockCloud(mockCtl)
+				mockMounter := mocks.NewMockMounter(mockCtl)
+				tempMountDir := t.TempDir()
+				driver := &Driver{
+					cloud:               mockCloud,
+					mounter:             mockMounter,
+					gidAllocator:        NewGidAllocator(),
+					tempMountPathPrefix: tempMountDir,
+				}
+				// Only root can give directories away, the owner is otherwise the current user
+				ownerUid, ownerGid := os.Getuid(), os.Getgid()
+				if ownerUid == 0 {
+					ownerUid, ownerGid = 2000, 3000
+				}
+
+				target := tempMountDir + "/" + volName
+				if err := os.MkdirAll(tempMountDir+"/fs", 0755); err != nil {
+					t.Fatalf("Could not create %v: %v", tempMountDir+"/fs", err)
+				}
+				ctx := context.Background()
+				mockCloud.EXPECT().DescribeFileSystem(gomock.Eq(ctx), gomock.Eq(fsId)).Return(&cloud.FileSystem{FileSystemId: fsId}, nil)
+				mockMounter.EXPECT().MakeDir(gomock.Eq(target)).Return(nil)
+				mockMounter.EXPECT().Mount(gomock.Eq(fsId), gomock.Eq(target), gomock.Eq("efs"), gomock.Any()).DoAndReturn(
+					func(source, target, fstype string, options []string) error {
+						return os.Symlink(tempMountDir+"/fs", target)
+					})
+				mockMounter.EXPECT().Unmount(gomock.Eq(target)).DoAndReturn(func(target string) error {
+					return os.Remove(target)
+				})
+				mockCloud.EXPECT().CreateAccessPoint(gomock.Eq(ctx), gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+					func(ctx context.Context, clientToken string, accessPointOpts *cloud.AccessPointOptions, reuseAccessPoint bool) (*cloud.AccessPoint, error) {
+						// The POSIX user of the access point is unrelated to the owner of the base path
+						if accessPointOpts.Uid != 1000 || accessPointOpts.Gid != 1000 {
+							t.Fatalf("Access point POSIX user mismatched. Expected: 1000:1000, Actual: %v:%v", accessPointOpts.Uid, accessPointOpts.Gid)
+						}
+						return accessPoint, nil
+					})
+
+				_, err := driver.CreateVolume(ctx, createRequest(map[string]string{
+					BasePath:          "/teams/a",
+					BasePathPerms:     "0750",
+					ParentDirOwnerUid: strconv.Itoa(ownerUid),
+					ParentDirOwnerGid: strconv.Itoa(ownerGid),
+				}))
+				if err != nil {
+					t.Fatalf("CreateVolume failed: %v", err)
+				}
+
+				for _, dir := range []string{tempMountDir + "/fs/teams", tempMountDir + "/fs/teams/a"} {
+					info, err := os.Stat(dir)
+					if err != nil {
+						t.Fatalf("Could not stat %v: %v", dir, err)
+					}
+					stat := info.Sys().(*syscall.Stat_t)
+					if int(stat.Uid) != ownerUid || int(stat.Gid) != ownerGid {
+						t.Fatalf("Owner of %v mismatched. Expected: %v:%v, Actual: %v:%v", dir, ownerUid, ownerGid, stat.Uid, stat.Gid)
+					}
+				}
+				mockCtl.Finish()
+			},
+		},
+		{
+			name: "Fail: File system cannot be mounted",
+			testFunc: func(t *testing.T) {
+				mockCtl := gomock.NewController(t)
+				mockCloud := mocks.NewMockCloud(mockCtl)
+				mockMounter := mocks.NewMockMounter(mockCtl)
+				tempMountDir := t.TempDir()
+				driver := &Driver{
+					cloud:               mockCloud,
+					mounter:             mockMounter,
+					gidAllocator:        NewGidAllocator(),
+					tempMountPathPrefix: tempMountDir,
+				}
+
+				target := tempMountDir + "/" + volName
+				ctx := context.Background()
+				mockCloud.EXPECT().DescribeFileSystem(gomock.Eq(ctx), gomock.Eq(fsId)).Return(&cloud.FileSystem{FileSystemId: fsId}, nil)
+				mockMounter.EXPECT().MakeDir(gomock.Eq(target)).DoAndReturn(func(target string) error {
+					return os.MkdirAll(target, 0755)
+				})
+				mockMounter.EXPECT().Mount(gomock.Eq(fsId), gomock.Eq(target), gomock.Eq("efs"), gomock.Any()).Return(errors.New("mount failed"))
+
+				_, err := driver.CreateVolume(ctx, createRequest(map[string]string{
+					BasePath:      "/csi",
+					BasePathPerms: "0755",
+				}))
+				if status.Code(err) != codes.Internal {
+					t.Fatalf("Expected an Internal error, got: %v", err)
+				}
+				mockCtl.Finish()
+			},
+		},
+		{
+			name: "Fail: basePathPerms without basePath",
+			testFunc: func(t *testing.T) {
+				mockCtl := gomock.NewController(t)
+				mockCloud := mocks.NewMockCloud(mockCtl)
+				driver := &Driver{
+					cloud:        mockCloud,
+					gidAllocator: NewGidAllocator(),
+				}
+
+				_, err := driver.CreateVolume(context.Background(), createRequest(map[string]string{BasePathPerms: "0755"}))
+				if status.Code(err) != codes.InvalidArgument {
+					t.Fatalf("Expected an InvalidArgument error, got: %v", err)
+				}
+				mockCtl.Finish()
+			},
+		},
+		{
+			name: "Fail: Invalid basePathPerms",
+			testFunc: func(t *testing.T) {
+				mockCtl := gomock.NewController(t)
+				mockCloud := mocks.NewMockCloud(mockCtl)
+				driver := &Driver{
+					cloud:        mockCloud,
+					gidAllocator: NewGidAllocator(),
+				}
+
+				_, err := driver.CreateVolume(context.Background(), createRequest(map[string]string{
+					BasePath:      "/csi",
+					BasePathPerms: "rwx",
+				}))
+				if status.Code(err) != codes.InvalidArgument {
+					t.Fatalf("Expected an InvalidArgument error, got: %v", err)
+				}
+				mockCtl.Finish()
+			},
+		},
+		{
+			name: "Fail: Invalid parent directory owner",
+			testFunc: func(t *testing.T) {
+				for _, params := range []map[string]string{
+					{BasePath: "/csi", ParentDirOwnerUid: "1000"},
+					{BasePath: "/csi", ParentDirOwnerGid: "1000"},
+					{BasePath: "/csi", BasePathPerms: "0755", ParentDirOwnerUid: "-1"},
+					{BasePath: "/csi", BasePathPerms: "0755", ParentDirOwnerGid: "admins"},
+				} {
+					mockCtl := gomock.NewController(t)
+					mockCloud := mocks.NewMockCloud(mockCtl)
+					driver := &Driver{
+						cloud:        mockCloud,
+						gidAllocator: NewGidAllocator(),
+					}
+
+					_, err := driver.CreateVolume(context.Background(), createRequest(params))
+					if status.Code(err) != codes.InvalidArgument {
+						t.Fatalf("Expected an InvalidArgument error with %v, got: %v", params, err)
+					}
+					mockCtl.Finish()
+				}
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, tc.testFunc)
+	}
+}
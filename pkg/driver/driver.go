@@ -18,11 +18,21 @@ package driver
 
 import (
 	"context"
+	"fmt"
 	"net"
+	"os"
+	"regexp"
 	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/container-storage-interface/spec/lib/go/csi"
+	"github.com/google/uuid"
 	"google.golang.org/grpc"
+	"k8s.io/apimachinery/pkg/util/wait"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/klog/v2"
 
 	"github.com/kubernetes-sigs/aws-efs-csi-driver/pkg/cloud"
@@ -40,51 +50,288 @@ type Driver struct {
 	endpoint                 string
 	nodeID                   string
 	srv                      *grpc.Server
+	shutdownTimeout          time.Duration
+	inFlightCtx              context.Context
+	cancelInFlight           context.CancelFunc
 	mounter                  Mounter
 	efsWatchdog              Watchdog
 	cloud                    cloud.Cloud
+	cloudOptions             cloud.ClientOptions
 	nodeCaps                 []csi.NodeServiceCapability_RPC_Type
 	volMetricsOptIn          bool
 	volMetricsRefreshPeriod  float64
 	volMetricsFsRateLimit    int
 	volStatter               VolStatter
+	volumeQuotas             sync.Map
 	gidAllocator             GidAllocator
+	warmupFileSystemIds      []string
+	warmingUp                atomic.Bool
+	allowedFileSystemIds     *regexp.Regexp
+	gidRefreshInterval       time.Duration
+	detectSubPathCollisions  bool
+	verifyEmptyOnReuse       bool
+	omitEmptyVolumeContext   bool
+	inFlightCreates          inFlightCreates
+	waitAccessPointAvailable bool
+	deleteCreatingApWait     time.Duration
+	waitAccessPointDeleted   time.Duration
+	inheritFsTags            bool
+	inheritFsTagKeys         []string
+	maxGid                   int64
+	defaultGidMin            int64
+	defaultGidMax            int64
+	volumeTopologyEnabled    bool
+	sharedAccessPointLocks   keyMutex
+	softDeleteGrace          time.Duration
+	softDeletes              softDeleteTracker
 	deleteAccessPointRootDir bool
+	retainAccessPoints       bool
+	maxRootDirLength         int
+	maxRootDirDepth          int
+	tenantBasePaths          map[string]string
+	accessPointWarnThreshold float64
+	accessPointWarnings      sync.Map
+	metricsAddress           string
+	debugAddress             string
+	preflightIamCheck        bool
+	cloudCallTimeouts        map[string]time.Duration
+	accessPointNamePrefix    string
+	overflowFileSystemId     string
+	skipFsExistenceCheck     bool
+	fsDescribeCacheTtl       time.Duration
+	fsDescribeCache          fsDescribeCache
+	safeDefaultPerms         string
+	namespaceLister          corelisters.NamespaceLister
+	tempMountPathPrefix      string
+	cleanupMountOptions      []string
+	cleanupMountFlavor       string
+	cleanupMountRetries      int
+	cleanupMountTimeout      time.Duration
+	cleanupMountBatchWindow  time.Duration
+	cleanupMountBatchSize    int
+	cleanupMounts            cleanupMounts
+	useIam                   bool
+	clusterName              string
+	eventRecorder            record.EventRecorder
+	denyUntagged             bool
+	awsProbeInterval         time.Duration
+	awsProbe                 awsProbe
 	tags                     map[string]string
+	disableDefaultTags       bool
+	newUUID                  func() string
+	now                      func() time.Time
 }
 
-func NewDriver(endpoint, efsUtilsCfgPath, efsUtilsStaticFilesPath, tags string, volMetricsOptIn bool, volMetricsRefreshPeriod float64, volMetricsFsRateLimit int, deleteAccessPointRootDir bool) *Driver {
-	cloud, err := cloud.NewCloud()
+// DriverOptions holds the settings of the driver, which cmd/main.go sets from the command line flags
+type DriverOptions struct {
+	Endpoint                  string
+	EfsUtilsCfgPath           string
+	EfsUtilsStaticFilesPath   string
+	Tags                      string
+	VolMetricsOptIn           bool
+	VolMetricsRefreshPeriod   float64
+	VolMetricsFsRateLimit     int
+	DeleteAccessPointRootDir  bool
+	TempMountDir              string
+	CleanupMountOptions       string
+	UseIam                    bool
+	ClusterName               string
+	EmitEvents                bool
+	DenyUntagged              bool
+	AwsProbeInterval          time.Duration
+	CleanupMountRetries       int
+	CleanupMountTimeout       time.Duration
+	SoftDeleteGrace           time.Duration
+	DisableDefaultTags        bool
+	WarmupFileSystemIds       []string
+	MaxGid                    int64
+	UseFipsEndpoint           bool
+	AwsEndpoint               string
+	VolumeTopologyEnabled     bool
+	AllowedFileSystemIds      *regexp.Regexp
+	GidRefreshInterval        time.Duration
+	DefaultGidMin             int64
+	DefaultGidMax             int64
+	DetectSubPathCollisions   bool
+	ShutdownTimeout           time.Duration
+	MaxConcurrentCreate       int
+	InheritFsTags             bool
+	InheritFsTagKeys          []string
+	ReservedGids              GidRanges
+	WaitAccessPointAvailable  bool
+	RetainAccessPoints        bool
+	MaxRootDirLength          int
+	MaxRootDirDepth           int
+	TenantBasePaths           map[string]string
+	AccessPointWarnThreshold  float64
+	MetricsAddress            string
+	PreflightIamCheck         bool
+	CloudCallTimeouts         map[string]time.Duration
+	AccessPointNamePrefix     string
+	OverflowFileSystemId      string
+	CleanupMountBatchWindow   time.Duration
+	CleanupMountBatchSize     int
+	AwsCredentialsFile        string
+	SkipFsExistenceCheck      bool
+	FsDescribeCacheTtl        time.Duration
+	SafeDefaultPerms          string
+	NamespacePosixAnnotations bool
+	DeleteCreatingApWait      time.Duration
+	DebugAddress              string
+	VerifyEmptyOnReuse        bool
+	BreakerThreshold          int
+	BreakerCoolDown           time.Duration
+	OmitEmptyVolumeContext    bool
+	WaitAccessPointDeleted    time.Duration
+	EfsMountHelperPath        string
+	CleanupMountFlavor        string
+	FallbackRegion            string
+	AdaptiveConcurrency       bool
+	GidRangeReserve           float64
+}
+
+func NewDriver(options *DriverOptions) *Driver {
+	var eventRecorder record.EventRecorder
+	if options.EmitEvents {
+		recorder, err := newEventRecorder(cloud.DefaultKubernetesAPIClient)
+		if err != nil {
+			klog.Warningf("Failed to create event recorder, provisioning failures will not be recorded as events: %v", err)
+		} else {
+			eventRecorder = recorder
+		}
+	}
+
+	var namespaceLister corelisters.NamespaceLister
+	if options.NamespacePosixAnnotations {
+		lister, err := newNamespaceLister(cloud.DefaultKubernetesAPIClient)
+		if err != nil {
+			klog.Fatalf("Failed to watch namespaces for --namespace-posix-annotations: %v", err)
+		}
+		namespaceLister = lister
+	}
+
+	if options.DisableDefaultTags {
+		klog.Warningf("The %v tag is not added to EFS resources. IAM policies conditioned on it, such as the example policy, deny creating and deleting access points", DefaultTagKey)
+		if strings.TrimSpace(options.Tags) == "" {
+			klog.Warningf("No tags are set with --tags, the GID audit cannot recognize the access points of the driver")
+		}
+	}
+
+	if options.RetainAccessPoints && (options.DeleteAccessPointRootDir || options.SoftDeleteGrace > 0) {
+		klog.Warningf("Access points are retained, --delete-access-point-root-dir and --soft-delete-grace have no effect")
+	}
+
+	cloudOptions := cloud.ClientOptions{
+		UseFIPSEndpoint: options.UseFipsEndpoint,
+		Endpoint:        options.AwsEndpoint,
+		CallLimiter:     newCallLimiter(options.MaxConcurrentCreate, options.AdaptiveConcurrency),
+		CredentialsFile: options.AwsCredentialsFile,
+		CircuitBreaker:  cloud.NewCircuitBreaker(options.BreakerThreshold, options.BreakerCoolDown),
+		FallbackRegion:  options.FallbackRegion,
+	}
+	cloud, err := cloud.NewCloud(cloudOptions)
 	if err != nil {
 		klog.Fatalln(err)
 	}
 
-	nodeCaps := SetNodeCapOptInFeatures(volMetricsOptIn)
-	watchdog := newExecWatchdog(efsUtilsCfgPath, efsUtilsStaticFilesPath, "amazon-efs-mount-watchdog")
-	return &Driver{
-		endpoint:                 endpoint,
+	nodeCaps := SetNodeCapOptInFeatures(options.VolMetricsOptIn)
+	watchdog := newExecWatchdog(options.EfsUtilsCfgPath, options.EfsUtilsStaticFilesPath, "amazon-efs-mount-watchdog")
+	d := &Driver{
+		endpoint:                 options.Endpoint,
 		nodeID:                   cloud.GetMetadata().GetInstanceID(),
-		mounter:                  newNodeMounter(),
+		mounter:                  newNodeMounter(options.EfsMountHelperPath),
 		efsWatchdog:              watchdog,
 		cloud:                    cloud,
+		cloudOptions:             cloudOptions,
 		nodeCaps:                 nodeCaps,
 		volStatter:               NewVolStatter(),
-		volMetricsOptIn:          volMetricsOptIn,
-		volMetricsRefreshPeriod:  volMetricsRefreshPeriod,
-		volMetricsFsRateLimit:    volMetricsFsRateLimit,
+		volMetricsOptIn:          options.VolMetricsOptIn,
+		volMetricsRefreshPeriod:  options.VolMetricsRefreshPeriod,
+		volMetricsFsRateLimit:    options.VolMetricsFsRateLimit,
 		gidAllocator:             NewGidAllocator(),
-		deleteAccessPointRootDir: deleteAccessPointRootDir,
-		tags:                     parseTagsFromStr(strings.TrimSpace(tags)),
+		warmupFileSystemIds:      options.WarmupFileSystemIds,
+		allowedFileSystemIds:     options.AllowedFileSystemIds,
+		gidRefreshInterval:       options.GidRefreshInterval,
+		detectSubPathCollisions:  options.DetectSubPathCollisions,
+		verifyEmptyOnReuse:       options.VerifyEmptyOnReuse,
+		omitEmptyVolumeContext:   options.OmitEmptyVolumeContext,
+		waitAccessPointAvailable: options.WaitAccessPointAvailable,
+		deleteCreatingApWait:     options.DeleteCreatingApWait,
+		waitAccessPointDeleted:   options.WaitAccessPointDeleted,
+		inheritFsTags:            options.InheritFsTags,
+		inheritFsTagKeys:         options.InheritFsTagKeys,
+		shutdownTimeout:          options.ShutdownTimeout,
+		maxGid:                   options.MaxGid,
+		defaultGidMin:            options.DefaultGidMin,
+		defaultGidMax:            options.DefaultGidMax,
+		volumeTopologyEnabled:    options.VolumeTopologyEnabled,
+		softDeleteGrace:          options.SoftDeleteGrace,
+		deleteAccessPointRootDir: options.DeleteAccessPointRootDir,
+		retainAccessPoints:       options.RetainAccessPoints,
+		maxRootDirLength:         options.MaxRootDirLength,
+		maxRootDirDepth:          options.MaxRootDirDepth,
+		tenantBasePaths:          options.TenantBasePaths,
+		accessPointWarnThreshold: options.AccessPointWarnThreshold,
+		metricsAddress:           options.MetricsAddress,
+		debugAddress:             options.DebugAddress,
+		preflightIamCheck:        options.PreflightIamCheck,
+		cloudCallTimeouts:        options.CloudCallTimeouts,
+		accessPointNamePrefix:    options.AccessPointNamePrefix,
+		overflowFileSystemId:     options.OverflowFileSystemId,
+		skipFsExistenceCheck:     options.SkipFsExistenceCheck,
+		fsDescribeCacheTtl:       options.FsDescribeCacheTtl,
+		safeDefaultPerms:         options.SafeDefaultPerms,
+		namespaceLister:          namespaceLister,
+		tempMountPathPrefix:      options.TempMountDir,
+		cleanupMountOptions:      ParseCleanupMountOptions(options.CleanupMountOptions),
+		cleanupMountFlavor:       selectCleanupMountFlavor(options.CleanupMountFlavor, options.EfsMountHelperPath),
+		cleanupMountRetries:      options.CleanupMountRetries,
+		cleanupMountTimeout:      options.CleanupMountTimeout,
+		cleanupMountBatchWindow:  options.CleanupMountBatchWindow,
+		cleanupMountBatchSize:    options.CleanupMountBatchSize,
+		useIam:                   options.UseIam,
+		clusterName:              options.ClusterName,
+		eventRecorder:            eventRecorder,
+		denyUntagged:             options.DenyUntagged,
+		awsProbeInterval:         options.AwsProbeInterval,
+		tags:                     parseTagsFromStr(strings.TrimSpace(options.Tags)),
+		disableDefaultTags:       options.DisableDefaultTags,
+	}
+	d.gidAllocator.reservedGids = options.ReservedGids
+	d.gidAllocator.rangeReserve = options.GidRangeReserve
+	d.gidAllocator.now = d.currentTime
+	return d
+}
+
+// generateUUID returns a random UUID, e.g. for the suffix of access point root directory names. Tests inject newUUID
+// to get deterministic names.
+func (d *Driver) generateUUID() string {
+	if d.newUUID != nil {
+		return d.newUUID()
+	}
+	return uuid.NewString()
+}
+
+// currentTime returns the current time, from the clock injected by tests if any
+func (d *Driver) currentTime() time.Time {
+	if d.now != nil {
+		return d.now()
 	}
+	return time.Now()
 }
 
 func SetNodeCapOptInFeatures(volMetricsOptIn bool) []csi.NodeServiceCapability_RPC_Type {
-	var nCaps = []csi.NodeServiceCapability_RPC_Type{}
+	// Only the capabilities of implemented RPCs are advertised. NodeStageVolume and NodeExpandVolume are not, as an
+	// EFS file system is mounted once per pod and has no size. Without the opt in, the volume stats are the stats
+	// of the whole file system.
+	var nCaps = []csi.NodeServiceCapability_RPC_Type{
+		csi.NodeServiceCapability_RPC_GET_VOLUME_STATS,
+		csi.NodeServiceCapability_RPC_VOLUME_CONDITION,
+	}
 	if volMetricsOptIn {
-		klog.V(4).Infof("Enabling Node Service capability for Get Volume Stats")
-		nCaps = append(nCaps, csi.NodeServiceCapability_RPC_GET_VOLUME_STATS)
+		klog.V(4).Infof("Enabling the computation of the usage of volumes for Get Volume Stats")
 	} else {
-		klog.V(4).Infof("Node Service capability for Get Volume Stats Not enabled")
+		klog.V(4).Infof("Get Volume Stats reports the usage of the whole file system")
 	}
 	return nCaps
 }
@@ -95,28 +342,19 @@ func (d *Driver) Run() error {
 		return err
 	}
 
+	if d.preflightIamCheck {
+		klog.Info("Checking the IAM permissions of the driver")
+		if err := d.checkIamPermissions(context.Background()); err != nil {
+			return err
+		}
+	}
+
 	listener, err := net.Listen(scheme, addr)
 	if err != nil {
 		return err
 	}
 
-	logErr := func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
-		resp, err := handler(ctx, req)
-		if err != nil {
-			klog.Errorf("GRPC error: %v", err)
-		}
-		return resp, err
-	}
-	opts := []grpc.ServerOption{
-		grpc.UnaryInterceptor(logErr),
-	}
-	d.srv = grpc.NewServer(opts...)
-
-	csi.RegisterIdentityServer(d.srv, d)
-	klog.Info("Registering Node Server")
-	csi.RegisterNodeServer(d.srv, d)
-	klog.Info("Registering Controller Server")
-	csi.RegisterControllerServer(d.srv, d)
+	d.newServer()
 
 	klog.Info("Starting efs-utils watchdog")
 	if err := d.efsWatchdog.start(); err != nil {
@@ -127,6 +365,30 @@ func (d *Driver) Run() error {
 	klog.Info("Starting reaper")
 	reaper.start()
 
+	if len(d.warmupFileSystemIds) > 0 {
+		// The GIDs are warmed up while serving, Probe reporting the driver as not ready until they are
+		d.warmingUp.Store(true)
+		go func() {
+			defer d.warmingUp.Store(false)
+			klog.Infof("Warming up the GIDs used on file systems %v", d.warmupFileSystemIds)
+			d.warmUpGids(d.warmupFileSystemIds)
+		}()
+	}
+
+	if d.gidRefreshInterval > 0 {
+		klog.Infof("Refreshing the GIDs used on file systems every %v", d.gidRefreshInterval)
+		go func() {
+			// The first refresh is delayed too, as the GIDs were just warmed up
+			time.Sleep(wait.Jitter(d.gidRefreshInterval, gidRefreshJitter))
+			wait.JitterUntil(d.refreshGids, d.gidRefreshInterval, gidRefreshJitter, true, wait.NeverStop)
+		}()
+	}
+
+	if d.softDeleteGrace > 0 {
+		klog.Infof("Starting soft delete reaper, soft deleted access points are deleted after %v", d.softDeleteGrace)
+		go wait.Until(d.purgeExpiredSoftDeletes, d.softDeleteReapPeriod(), wait.NeverStop)
+	}
+
 	// Remove taint from node to indicate driver startup success
 	// This is done at the last possible moment to prevent race conditions or false positive removals
 	err = removeNotReadyTaint(cloud.DefaultKubernetesAPIClient)
@@ -134,10 +396,53 @@ func (d *Driver) Run() error {
 		klog.ErrorS(err, "Unexpected failure when attempting to remove node taint(s)")
 	}
 
+	if d.metricsAddress != "" {
+		go d.serveMetrics()
+	}
+
+	if d.debugAddress != "" {
+		go d.serveDebug()
+	}
+
+	go d.stopOnSignal()
+	go d.auditGidsOnSignal()
+
 	klog.Infof("Listening for connections on address: %#v", listener.Addr())
 	return d.srv.Serve(listener)
 }
 
+// newServer creates the gRPC server and registers the CSI services
+func (d *Driver) newServer() {
+	d.inFlightCtx, d.cancelInFlight = context.WithCancel(context.Background())
+	opts := []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(logGRPC, d.cancelOnShutdown, d.unavailableOnShortCircuit),
+	}
+	d.srv = grpc.NewServer(opts...)
+
+	csi.RegisterIdentityServer(d.srv, d)
+	klog.Info("Registering Node Server")
+	csi.RegisterNodeServer(d.srv, d)
+	klog.Info("Registering Controller Server")
+	csi.RegisterControllerServer(d.srv, d)
+}
+
+// ValidateTempMountDir checks that dir is an existing directory the driver is able to write to.
+func ValidateTempMountDir(dir string) error {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return fmt.Errorf("temp mount dir %q is not accessible: %v", dir, err)
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("temp mount dir %q is not a directory", dir)
+	}
+	f, err := os.CreateTemp(dir, ".efs-csi-write-check")
+	if err != nil {
+		return fmt.Errorf("temp mount dir %q is not writable: %v", dir, err)
+	}
+	f.Close()
+	return os.Remove(f.Name())
+}
+
 func parseTagsFromStr(tagStr string) map[string]string {
 	defer func() {
 		if r := recover(); r != nil {
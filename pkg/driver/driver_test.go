@@ -0,0 +1,64 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestValidateTempMountDir(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "file")
+	if err := os.WriteFile(file, []byte{}, 0644); err != nil {
+		t.Fatalf("Failed to create file: %v", err)
+	}
+
+	testCases := []struct {
+		name      string
+		dir       string
+		expectErr bool
+	}{
+		{
+			name: "Success: existing writable directory",
+			dir:  dir,
+		},
+		{
+			name:      "Fail: directory does not exist",
+			dir:       filepath.Join(dir, "missing"),
+			expectErr: true,
+		},
+		{
+			name:      "Fail: path is a file",
+			dir:       file,
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateTempMountDir(tc.dir)
+			if tc.expectErr && err == nil {
+				t.Fatal("ValidateTempMountDir did not fail")
+			}
+			if !tc.expectErr && err != nil {
+				t.Fatalf("ValidateTempMountDir failed: %v", err)
+			}
+		})
+	}
+}
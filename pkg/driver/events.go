@@ -0,0 +1,73 @@
+package driver
+
+import (
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	"google.golang.org/grpc/status"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/klog/v2"
+
+	"github.com/kubernetes-sigs/aws-efs-csi-driver/pkg/cloud"
+)
+
+const (
+	// ProvisioningFailedReason is the reason of the events recorded on a PVC when CreateVolume fails
+	ProvisioningFailedReason = "ProvisioningFailed"
+	// FileSystemOverflowReason is the reason of the events recorded on a PVC provisioned on --overflow-filesystem-id
+	FileSystemOverflowReason = "FileSystemOverflow"
+)
+
+// newEventRecorder returns a recorder sending events to the API server.
+// Events are queued and dropped when the queue is full, so recording never blocks the caller.
+func newEventRecorder(k8sClient cloud.KubernetesAPIClient) (record.EventRecorder, error) {
+	clientset, err := k8sClient()
+	if err != nil {
+		return nil, err
+	}
+	broadcaster := record.NewBroadcaster()
+	broadcaster.StartRecordingToSink(&typedcorev1.EventSinkImpl{Interface: clientset.CoreV1().Events("")})
+	return broadcaster.NewRecorder(scheme.Scheme, corev1.EventSource{Component: driverName}), nil
+}
+
+// recordProvisioningFailure records a warning event on the PVC of a failed CreateVolume request.
+// The PVC is only known when the csi-provisioner passes extra-create-metadata.
+func (d *Driver) recordProvisioningFailure(req *csi.CreateVolumeRequest, err error) {
+	pvc := d.eventPvc(req)
+	if pvc == nil {
+		return
+	}
+	s := status.Convert(err)
+	d.eventRecorder.Eventf(pvc, corev1.EventTypeWarning, ProvisioningFailedReason, "Failed to provision volume %v with %v: %v", req.GetName(), s.Code(), s.Message())
+}
+
+// recordFileSystemOverflow records a warning event on the PVC of a volume provisioned on the overflow file system
+func (d *Driver) recordFileSystemOverflow(req *csi.CreateVolumeRequest, fileSystemId, overflowFileSystemId string) {
+	pvc := d.eventPvc(req)
+	if pvc == nil {
+		return
+	}
+	d.eventRecorder.Eventf(pvc, corev1.EventTypeWarning, FileSystemOverflowReason, "File system %v is at its limit of %v access points, volume %v is provisioned on overflow file system %v",
+		fileSystemId, cloud.AccessPointPerFsLimit, req.GetName(), overflowFileSystemId)
+}
+
+// eventPvc returns the PVC of a CreateVolume request to record events on, nil when events are not recorded or the
+// PVC is unknown
+func (d *Driver) eventPvc(req *csi.CreateVolumeRequest) *corev1.ObjectReference {
+	if d.eventRecorder == nil {
+		return nil
+	}
+	volumeParams := req.GetParameters()
+	pvcName, pvcNamespace := volumeParams[PvcName], volumeParams[PvcNamespace]
+	if pvcName == "" || pvcNamespace == "" {
+		klog.V(5).Infof("Not recording event of volume %v, PVC is unknown", req.GetName())
+		return nil
+	}
+	return &corev1.ObjectReference{
+		APIVersion: "v1",
+		Kind:       "PersistentVolumeClaim",
+		Name:       pvcName,
+		Namespace:  pvcNamespace,
+	}
+}
@@ -0,0 +1,146 @@
+package driver
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	"github.com/golang/mock/gomock"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/kubernetes-sigs/aws-efs-csi-driver/pkg/cloud"
+	"github.com/kubernetes-sigs/aws-efs-csi-driver/pkg/driver/mocks"
+)
+
+func TestRecordProvisioningFailure(t *testing.T) {
+	var (
+		endpoint  = "endpoint"
+		fsId      = "fs-abcd1234"
+		stdVolCap = &csi.VolumeCapability{
+			AccessType: &csi.VolumeCapability_Mount{
+				Mount: &csi.VolumeCapability_MountVolume{},
+			},
+			AccessMode: &csi.VolumeCapability_AccessMode{
+				Mode: csi.VolumeCapability_AccessMode_MULTI_NODE_MULTI_WRITER,
+			},
+		}
+	)
+
+	createRequest := func(params map[string]string) *csi.CreateVolumeRequest {
+		parameters := map[string]string{
+			ProvisioningMode: "efs-ap",
+			FsId:             fsId,
+			DirectoryPerms:   "777",
+		}
+		for k, v := range params {
+			parameters[k] = v
+		}
+		return &csi.CreateVolumeRequest{
+			Name:               "volume-name",
+			VolumeCapabilities: []*csi.VolumeCapability{stdVolCap},
+			Parameters:         parameters,
+		}
+	}
+
+	testCases := []struct {
+		name     string
+		testFunc func(t *testing.T)
+	}{
+		{
+			name: "Success: Warning event recorded on the PVC",
+			testFunc: func(t *testing.T) {
+				mockCtl := gomock.NewController(t)
+				mockCloud := mocks.NewMockCloud(mockCtl)
+				recorder := record.NewFakeRecorder(1)
+
+				driver := &Driver{
+					endpoint:      endpoint,
+					cloud:         mockCloud,
+					gidAllocator:  NewGidAllocator(),
+					eventRecorder: recorder,
+				}
+
+				ctx := context.Background()
+				mockCloud.EXPECT().ListAccessPoints(gomock.Eq(ctx), gomock.Any()).Return(nil, cloud.ErrNotFound)
+				_, err := driver.CreateVolume(ctx, createRequest(map[string]string{
+					PvcName:      "pvc-name",
+					PvcNamespace: "pvc-namespace",
+				}))
+				if err == nil {
+					t.Fatalf("CreateVolume did not fail")
+				}
+
+				select {
+				case event := <-recorder.Events:
+					if !strings.HasPrefix(event, "Warning "+ProvisioningFailedReason) {
+						t.Fatalf("Unexpected event: %v", event)
+					}
+					if !strings.Contains(event, "File System does not exist") {
+						t.Fatalf("Event does not describe the failure: %v", event)
+					}
+				default:
+					t.Fatalf("No event was recorded")
+				}
+				mockCtl.Finish()
+			},
+		},
+		{
+			name: "Success: No event without the PVC metadata",
+			testFunc: func(t *testing.T) {
+				mockCtl := gomock.NewController(t)
+				mockCloud := mocks.NewMockCloud(mockCtl)
+				recorder := record.NewFakeRecorder(1)
+
+				driver := &Driver{
+					endpoint:      endpoint,
+					cloud:         mockCloud,
+					gidAllocator:  NewGidAllocator(),
+					eventRecorder: recorder,
+				}
+
+				ctx := context.Background()
+				mockCloud.EXPECT().ListAccessPoints(gomock.Eq(ctx), gomock.Any()).Return(nil, cloud.ErrNotFound)
+				_, err := driver.CreateVolume(ctx, createRequest(nil))
+				if err == nil {
+					t.Fatalf("CreateVolume did not fail")
+				}
+
+				select {
+				case event := <-recorder.Events:
+					t.Fatalf("Unexpected event: %v", event)
+				default:
+				}
+				mockCtl.Finish()
+			},
+		},
+		{
+			name: "Success: Events disabled",
+			testFunc: func(t *testing.T) {
+				mockCtl := gomock.NewController(t)
+				mockCloud := mocks.NewMockCloud(mockCtl)
+
+				driver := &Driver{
+					endpoint:     endpoint,
+					cloud:        mockCloud,
+					gidAllocator: NewGidAllocator(),
+				}
+
+				ctx := context.Background()
+				mockCloud.EXPECT().ListAccessPoints(gomock.Eq(ctx), gomock.Any()).Return(nil, cloud.ErrNotFound)
+				_, err := driver.CreateVolume(ctx, createRequest(map[string]string{
+					PvcName:      "pvc-name",
+					PvcNamespace: "pvc-namespace",
+				}))
+				if err == nil {
+					t.Fatalf("CreateVolume did not fail")
+				}
+				mockCtl.Finish()
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, tc.testFunc)
+	}
+}
@@ -0,0 +1,99 @@
+package driver
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"k8s.io/klog/v2"
+
+	"github.com/kubernetes-sigs/aws-efs-csi-driver/pkg/cloud"
+)
+
+// fsDescribeCacheEntry is a described file system, or a describe call in flight until done is closed
+type fsDescribeCacheEntry struct {
+	done       chan struct{}
+	fileSystem *cloud.FileSystem
+	err        error
+	expiry     time.Time
+}
+
+// fsDescribeCache caches the file systems described by CreateVolume for --fs-describe-cache-ttl, so that a burst of
+// calls provisioning volumes on the same file system describes it once. Failed calls are not cached.
+type fsDescribeCache struct {
+	mu      sync.Mutex
+	entries map[string]*fsDescribeCacheEntry
+}
+
+// fsDescribeCacheKey returns the key of a file system of the region, described with the role
+func fsDescribeCacheKey(roleArn, region, fileSystemId string) string {
+	return roleArn + "|" + region + "|" + fileSystemId
+}
+
+// describeFileSystem describes the file system of a CreateVolume call, through the cache when --fs-describe-cache-ttl
+// is set. Concurrent calls for a file system which is not cached wait for the describe call of the first one.
+func (d *Driver) describeFileSystem(ctx context.Context, localCloud cloud.Cloud, roleArn, region, fileSystemId string) (*cloud.FileSystem, error) {
+	if d.fsDescribeCacheTtl <= 0 {
+		return localCloud.DescribeFileSystem(ctx, fileSystemId)
+	}
+	c := &d.fsDescribeCache
+	key := fsDescribeCacheKey(roleArn, region, fileSystemId)
+
+	c.mu.Lock()
+	if entry, ok := c.entries[key]; ok {
+		select {
+		case <-entry.done:
+			if d.currentTime().Before(entry.expiry) {
+				c.mu.Unlock()
+				klog.V(5).Infof("Using cached description of File System %v", fileSystemId)
+				return entry.fileSystem, nil
+			}
+		default:
+			c.mu.Unlock()
+			select {
+			case <-entry.done:
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+			if entry.err != nil {
+				// The error may be specific to the call which described it, e.g. its deadline
+				return localCloud.DescribeFileSystem(ctx, fileSystemId)
+			}
+			return entry.fileSystem, nil
+		}
+	}
+	if c.entries == nil {
+		c.entries = map[string]*fsDescribeCacheEntry{}
+	}
+	entry := &fsDescribeCacheEntry{done: make(chan struct{})}
+	c.entries[key] = entry
+	c.mu.Unlock()
+
+	entry.fileSystem, entry.err = localCloud.DescribeFileSystem(ctx, fileSystemId)
+	c.mu.Lock()
+	if entry.err != nil {
+		if c.entries[key] == entry {
+			delete(c.entries, key)
+		}
+	} else {
+		entry.expiry = d.currentTime().Add(d.fsDescribeCacheTtl)
+	}
+	c.mu.Unlock()
+	close(entry.done)
+	return entry.fileSystem, entry.err
+}
+
+// invalidateFileSystem removes a file system from the cache, once a call found it does not exist
+func (d *Driver) invalidateFileSystem(roleArn, region, fileSystemId string) {
+	c := &d.fsDescribeCache
+	key := fsDescribeCacheKey(roleArn, region, fileSystemId)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if entry, ok := c.entries[key]; ok {
+		select {
+		case <-entry.done:
+			delete(c.entries, key)
+		default:
+		}
+	}
+}
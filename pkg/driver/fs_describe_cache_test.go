@@ -0,0 +1,201 @@
+package driver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	"github.com/golang/mock/gomock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/kubernetes-sigs/aws-efs-csi-driver/pkg/cloud"
+	"github.com/kubernetes-sigs/aws-efs-csi-driver/pkg/driver/mocks"
+)
+
+func TestCreateVolumeFsDescribeCache(t *testing.T) {
+	var (
+		fsId       = "fs-abcd1234"
+		apId       = "fsap-abcd1234xyz987"
+		fileSystem = &cloud.FileSystem{FileSystemId: fsId}
+	)
+
+	newRequest := func(name string) *csi.CreateVolumeRequest {
+		return &csi.CreateVolumeRequest{
+			Name: name,
+			VolumeCapabilities: []*csi.VolumeCapability{
+				{
+					AccessType: &csi.VolumeCapability_Mount{
+						Mount: &csi.VolumeCapability_MountVolume{},
+					},
+					AccessMode: &csi.VolumeCapability_AccessMode{
+						Mode: csi.VolumeCapability_AccessMode_MULTI_NODE_MULTI_WRITER,
+					},
+				},
+			},
+			CapacityRange: &csi.CapacityRange{RequiredBytes: 5 * 1024 * 1024 * 1024},
+			Parameters: map[string]string{
+				ProvisioningMode: AccessPointMode,
+				FsId:             fsId,
+				DirectoryPerms:   "700",
+				Uid:              "1000",
+				Gid:              "1000",
+			},
+		}
+	}
+	newDriver := func(mockCloud cloud.Cloud, ttl time.Duration) *Driver {
+		return &Driver{
+			cloud:              mockCloud,
+			gidAllocator:       NewGidAllocator(),
+			fsDescribeCacheTtl: ttl,
+		}
+	}
+	accessPoint := &cloud.AccessPoint{AccessPointId: apId, FileSystemId: fsId}
+
+	testCases := []struct {
+		name     string
+		testFunc func(t *testing.T)
+	}{
+		{
+			name: "Success: Second call within the TTL uses the cache",
+			testFunc: func(t *testing.T) {
+				mockCtl := gomock.NewController(t)
+				mockCloud := mocks.NewMockCloud(mockCtl)
+				driver := newDriver(mockCloud, time.Minute)
+
+				ctx := context.Background()
+				mockCloud.EXPECT().DescribeFileSystem(gomock.Eq(ctx), gomock.Eq(fsId)).Return(fileSystem, nil).Times(1)
+				mockCloud.EXPECT().CreateAccessPoint(gomock.Eq(ctx), gomock.Any(), gomock.Any(), gomock.Any()).Return(accessPoint, nil).Times(2)
+				for _, name := range []string{"pvc-1", "pvc-2"} {
+					if _, err := driver.CreateVolume(ctx, newRequest(name)); err != nil {
+						t.Fatalf("CreateVolume failed: %v", err)
+					}
+				}
+				mockCtl.Finish()
+			},
+		},
+		{
+			name: "Success: Call after the TTL describes the file system again",
+			testFunc: func(t *testing.T) {
+				mockCtl := gomock.NewController(t)
+				mockCloud := mocks.NewMockCloud(mockCtl)
+				driver := newDriver(mockCloud, time.Minute)
+				now := time.Now()
+				driver.now = func() time.Time { return now }
+
+				ctx := context.Background()
+				mockCloud.EXPECT().DescribeFileSystem(gomock.Eq(ctx), gomock.Eq(fsId)).Return(fileSystem, nil).Times(2)
+				mockCloud.EXPECT().CreateAccessPoint(gomock.Eq(ctx), gomock.Any(), gomock.Any(), gomock.Any()).Return(accessPoint, nil).Times(2)
+				if _, err := driver.CreateVolume(ctx, newRequest("pvc-1")); err != nil {
+					t.Fatalf("CreateVolume failed: %v", err)
+				}
+				now = now.Add(time.Minute)
+				if _, err := driver.CreateVolume(ctx, newRequest("pvc-2")); err != nil {
+					t.Fatalf("CreateVolume failed: %v", err)
+				}
+				mockCtl.Finish()
+			},
+		},
+		{
+			name: "Success: Failed describe is not cached",
+			testFunc: func(t *testing.T) {
+				mockCtl := gomock.NewController(t)
+				mockCloud := mocks.NewMockCloud(mockCtl)
+				driver := newDriver(mockCloud, time.Minute)
+
+				ctx := context.Background()
+				gomock.InOrder(
+					mockCloud.EXPECT().DescribeFileSystem(gomock.Eq(ctx), gomock.Eq(fsId)).Return(nil, errors.New("ThrottlingException")),
+					mockCloud.EXPECT().DescribeFileSystem(gomock.Eq(ctx), gomock.Eq(fsId)).Return(fileSystem, nil),
+				)
+				mockCloud.EXPECT().CreateAccessPoint(gomock.Eq(ctx), gomock.Any(), gomock.Any(), gomock.Any()).Return(accessPoint, nil)
+				if _, err := driver.CreateVolume(ctx, newRequest("pvc-1")); status.Code(err) != codes.Internal {
+					t.Fatalf("Expected error code %v, got: %v", codes.Internal, err)
+				}
+				if _, err := driver.CreateVolume(ctx, newRequest("pvc-1")); err != nil {
+					t.Fatalf("CreateVolume failed: %v", err)
+				}
+				mockCtl.Finish()
+			},
+		},
+		{
+			name: "Success: File system not found by CreateAccessPoint is removed from the cache",
+			testFunc: func(t *testing.T) {
+				mockCtl := gomock.NewController(t)
+				mockCloud := mocks.NewMockCloud(mockCtl)
+				driver := newDriver(mockCloud, time.Minute)
+
+				ctx := context.Background()
+				gomock.InOrder(
+					mockCloud.EXPECT().DescribeFileSystem(gomock.Eq(ctx), gomock.Eq(fsId)).Return(fileSystem, nil),
+					mockCloud.EXPECT().CreateAccessPoint(gomock.Eq(ctx), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, cloud.ErrNotFound),
+					mockCloud.EXPECT().DescribeFileSystem(gomock.Eq(ctx), gomock.Eq(fsId)).Return(nil, cloud.ErrNotFound),
+				)
+				for _, name := range []string{"pvc-1", "pvc-2"} {
+					if _, err := driver.CreateVolume(ctx, newRequest(name)); status.Code(err) != codes.InvalidArgument {
+						t.Fatalf("Expected error code %v, got: %v", codes.InvalidArgument, err)
+					}
+				}
+				mockCtl.Finish()
+			},
+		},
+		{
+			name: "Success: Concurrent calls describe the file system once",
+			testFunc: func(t *testing.T) {
+				mockCtl := gomock.NewController(t)
+				mockCloud := mocks.NewMockCloud(mockCtl)
+				driver := newDriver(mockCloud, time.Minute)
+
+				ctx := context.Background()
+				release := make(chan struct{})
+				mockCloud.EXPECT().DescribeFileSystem(gomock.Eq(ctx), gomock.Eq(fsId)).DoAndReturn(
+					func(ctx context.Context, fileSystemId string) (*cloud.FileSystem, error) {
+						<-release
+						return fileSystem, nil
+					}).Times(1)
+				mockCloud.EXPECT().CreateAccessPoint(gomock.Eq(ctx), gomock.Any(), gomock.Any(), gomock.Any()).Return(accessPoint, nil).Times(5)
+
+				var wg sync.WaitGroup
+				for i := 0; i < 5; i++ {
+					wg.Add(1)
+					go func(i int) {
+						defer wg.Done()
+						if _, err := driver.CreateVolume(ctx, newRequest(fmt.Sprintf("pvc-%d", i))); err != nil {
+							t.Errorf("CreateVolume failed: %v", err)
+						}
+					}(i)
+				}
+				time.Sleep(50 * time.Millisecond)
+				close(release)
+				wg.Wait()
+				mockCtl.Finish()
+			},
+		},
+		{
+			name: "Success: Every call describes the file system without TTL",
+			testFunc: func(t *testing.T) {
+				mockCtl := gomock.NewController(t)
+				mockCloud := mocks.NewMockCloud(mockCtl)
+				driver := newDriver(mockCloud, 0)
+
+				ctx := context.Background()
+				mockCloud.EXPECT().DescribeFileSystem(gomock.Eq(ctx), gomock.Eq(fsId)).Return(fileSystem, nil).Times(2)
+				mockCloud.EXPECT().CreateAccessPoint(gomock.Eq(ctx), gomock.Any(), gomock.Any(), gomock.Any()).Return(accessPoint, nil).Times(2)
+				for _, name := range []string{"pvc-1", "pvc-2"} {
+					if _, err := driver.CreateVolume(ctx, newRequest(name)); err != nil {
+						t.Fatalf("CreateVolume failed: %v", err)
+					}
+				}
+				mockCtl.Finish()
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, tc.testFunc)
+	}
+}
@@ -1,8 +1,13 @@
 package driver
 
 import (
+	"context"
 	"fmt"
+	"hash/fnv"
+	"math"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/kubernetes-sigs/aws-efs-csi-driver/pkg/cloud"
 	"golang.org/x/exp/slices"
@@ -16,36 +21,317 @@ type FilesystemID struct {
 	gidMax int64
 }
 
+// GidRangeExhaustedError is returned when every GID in the requested range is used by an access point, or every GID
+// but the Headroom GIDs kept free by --gid-range-reserve
+type GidRangeExhaustedError struct {
+	FileSystemId string
+	GidMin       int64
+	GidMax       int64
+	UsedGids     int
+	Headroom     int
+}
+
+func (e *GidRangeExhaustedError) Error() string {
+	if e.Headroom > 0 {
+		return fmt.Sprintf("GIDs in range %v-%v are in use on file system %v up to the %v GIDs kept free by --gid-range-reserve (%v GIDs in use)", e.GidMin, e.GidMax, e.FileSystemId, e.Headroom, e.UsedGids)
+	}
+	return fmt.Sprintf("all GIDs in range %v-%v are in use on file system %v (%v GIDs in use)", e.GidMin, e.GidMax, e.FileSystemId, e.UsedGids)
+}
+
+// gidReservationTTL bounds how long an allocated GID stays reserved if its access point never shows up in a listing
+const gidReservationTTL = 10 * time.Minute
+
+// gidListTimeout bounds the listing of the access points of each file system warmed up at startup or refreshed
+const gidListTimeout = 30 * time.Second
+
+// gidVisibilityGrace is how long an allocated GID is kept in use while missing from listings, as its access point may not be listed yet
+const gidVisibilityGrace = time.Minute
+
+// gidRefreshJitter is the maximum fraction of the refresh interval added to it, so that drivers do not list in bursts
+const gidRefreshJitter = 0.2
+
 type GidAllocator struct {
-	mu sync.Mutex
+	mu       sync.Mutex
+	fsStates map[string]*fsGidState
+	// reservedGids are never allocated
+	reservedGids GidRanges
+	// rangeReserve is the fraction of each GID range kept free as headroom
+	rangeReserve float64
+	// now is the clock of the driver, set by NewDriver
+	now func() time.Time
+}
+
+// fsGidState tracks the GIDs handed out for a single file system whose access points may not be listed yet.
+type fsGidState struct {
+	mu       sync.Mutex
+	reserved map[int64]time.Time
+	// listed are the reserved GIDs which come from a listing of the access points rather than from an allocation
+	listed map[int64]bool
+	// cloud lists the access points of the file system when refreshing its GIDs, the driver's cloud when nil
+	cloud cloud.Cloud
+	// gidMin and gidMax are the range of the last allocation, and inUse the number of GIDs in use in it afterwards.
+	// highWaterMark is the highest inUse of the file system, to tell how close to exhaustion it got.
+	gidMin, gidMax int64
+	inUse          int
+	highWaterMark  int
 }
 
 func NewGidAllocator() GidAllocator {
-	return GidAllocator{}
+	return GidAllocator{
+		fsStates: make(map[string]*fsGidState),
+	}
 }
 
-// Retrieves the next available GID
-func (g *GidAllocator) getNextGid(fsId string, accessPoints []*cloud.AccessPoint, gidMin, gidMax int64) (int64, error) {
+// currentTime returns the current time from the clock of the driver, time.Now when the allocator was created without it
+func (g *GidAllocator) currentTime() time.Time {
+	if g.now != nil {
+		return g.now()
+	}
+	return time.Now()
+}
+
+// getFsState returns the allocation state of a file system, so that only calls for the same file system contend
+func (g *GidAllocator) getFsState(fsId string) *fsGidState {
 	g.mu.Lock()
 	defer g.mu.Unlock()
 
-	klog.V(5).Infof("Received getNextGid for fsId: %v, min: %v, max: %v", fsId, gidMin, gidMax)
+	if g.fsStates == nil {
+		g.fsStates = make(map[string]*fsGidState)
+	}
+	state, ok := g.fsStates[fsId]
+	if !ok {
+		state = &fsGidState{
+			reserved: make(map[int64]time.Time),
+			listed:   make(map[int64]bool),
+		}
+		g.fsStates[fsId] = state
+	}
+	return state
+}
+
+// Retrieves the next available GID and reserves it until its access point is listed
+func (g *GidAllocator) getNextGid(fsId string, accessPoints []*cloud.AccessPoint, gidMin, gidMax int64) (int64, error) {
+	return g.allocateGid(fsId, accessPoints, gidMin, gidMax, "")
+}
+
+// getHashedGid retrieves the first available GID from the one derived from the hash key, usually the PVC, and
+// reserves it until its access point is listed. The same key gets the same GID as long as it is not in use.
+func (g *GidAllocator) getHashedGid(fsId string, accessPoints []*cloud.AccessPoint, gidMin, gidMax int64, hashKey string) (int64, error) {
+	return g.allocateGid(fsId, accessPoints, gidMin, gidMax, hashKey)
+}
+
+// allocateGid searches an unused GID from the start of the range, or from the GID derived from the hash key if set
+func (g *GidAllocator) allocateGid(fsId string, accessPoints []*cloud.AccessPoint, gidMin, gidMax int64, hashKey string) (int64, error) {
+	state := g.getFsState(fsId)
+	state.mu.Lock()
+	defer state.mu.Unlock()
+
+	klog.V(5).Infof("Received GID allocation for fsId: %v, min: %v, max: %v, hash key: %q", fsId, gidMin, gidMax, hashKey)
 
 	usedGids, err := g.getUsedGids(fsId, accessPoints)
 	if err != nil {
 		return 0, status.Errorf(codes.Internal, "Failed to discover used GIDs for filesystem: %v: %v ", fsId, err)
 	}
 
-	gid, err := getNextUnusedGid(usedGids, gidMin, gidMax)
+	// Reservations are no longer needed once the access point is listed, or once they expired
+	now := g.currentTime()
+	for gid, reservedAt := range state.reserved {
+		if slices.Contains(usedGids, gid) || now.Sub(reservedAt) > gidReservationTTL {
+			delete(state.reserved, gid)
+			delete(state.listed, gid)
+			continue
+		}
+		usedGids = append(usedGids, gid)
+	}
+
+	var gid int64
+	err = g.checkRangeReserve(usedGids, gidMin, gidMax)
+	if err == nil {
+		gid, err = getNextUnusedGid(usedGids, g.reservedGids, gidMin, gidMax, hashKey)
+	}
 
 	if err != nil {
+		if exhaustedErr, ok := err.(*GidRangeExhaustedError); ok {
+			exhaustedErr.FileSystemId = fsId
+			state.recordUsage(exhaustedErr.GidMin, exhaustedErr.GidMax, exhaustedErr.UsedGids)
+			if exhaustedErr.Headroom > 0 {
+				klog.Warningf("GID range %v-%v is exhausted for file system %v but for the %v GIDs kept free, %v GIDs are in use", exhaustedErr.GidMin, exhaustedErr.GidMax, fsId, exhaustedErr.Headroom, exhaustedErr.UsedGids)
+			} else {
+				klog.Warningf("GID range %v-%v is exhausted for file system %v, %v GIDs are in use", exhaustedErr.GidMin, exhaustedErr.GidMax, fsId, exhaustedErr.UsedGids)
+			}
+			return 0, exhaustedErr
+		}
 		return 0, status.Errorf(codes.Internal, "Failed to locate a free GID for given file system: %v. "+
 			"Please create a new storage class with a new file-system", fsId)
 	}
 
+	state.reserved[gid] = now
+	state.recordUsage(gidMin, gidMax, g.countUsedGids(usedGids, gidMin, gidMax)+1)
 	return gid, nil
 }
 
+// checkRangeReserve returns a GidRangeExhaustedError once the GIDs in use in the range reach the share of the range
+// which --gid-range-reserve does not keep free. The capacity is the number of GIDs getNextUnusedGid can allocate,
+// i.e. those of the range limited by limitGidRange which are not reserved.
+func (g *GidAllocator) checkRangeReserve(usedGids []int64, gidMin, gidMax int64) error {
+	if g.rangeReserve <= 0 {
+		return nil
+	}
+	gidMax = limitGidRange(g.reservedGids, gidMin, gidMax)
+	capacity := gidMax - gidMin + 1 - g.reservedGids.Count(gidMin, gidMax)
+	// The epsilon keeps e.g. 0.7 of 10 GIDs from rounding down to 6
+	allowed := int(math.Floor(float64(capacity)*(1-g.rangeReserve) + 1e-9))
+	inUse := g.countUsedGids(usedGids, gidMin, gidMax)
+	if inUse < allowed {
+		return nil
+	}
+	return &GidRangeExhaustedError{GidMin: gidMin, GidMax: gidMax, UsedGids: inUse, Headroom: int(capacity) - allowed}
+}
+
+// countUsedGids returns the number of distinct GIDs in use in the range, not counting reserved GIDs. Access points
+// with a fixed GID may share one, and reserved GIDs are already left out of the capacity of the range.
+func (g *GidAllocator) countUsedGids(usedGids []int64, gidMin, gidMax int64) int {
+	counted := map[int64]bool{}
+	for _, used := range usedGids {
+		if used >= gidMin && used <= gidMax && !g.reservedGids.contains(used) {
+			counted[used] = true
+		}
+	}
+	return len(counted)
+}
+
+// recordUsage records the range of an allocation and the number of GIDs in use in it, with the state locked
+func (state *fsGidState) recordUsage(gidMin, gidMax int64, inUse int) {
+	state.gidMin, state.gidMax, state.inUse = gidMin, gidMax, inUse
+	if inUse > state.highWaterMark {
+		state.highWaterMark = inUse
+	}
+}
+
+// releaseGid drops the reservation of a GID whose access point could not be created, or was deleted.
+// Releasing a GID which is not reserved is a no-op.
+func (g *GidAllocator) releaseGid(fsId string, gid int64) {
+	state := g.getFsState(fsId)
+	state.mu.Lock()
+	defer state.mu.Unlock()
+
+	delete(state.reserved, gid)
+	delete(state.listed, gid)
+}
+
+// setCloud records the cloud listing the access points of a file system, which may be of another region or account
+func (g *GidAllocator) setCloud(fsId string, localCloud cloud.Cloud) {
+	state := g.getFsState(fsId)
+	state.mu.Lock()
+	defer state.mu.Unlock()
+
+	state.cloud = localCloud
+}
+
+// fileSystems returns the file systems GIDs were allocated or warmed up for, with the cloud listing their access points
+func (g *GidAllocator) fileSystems() map[string]cloud.Cloud {
+	g.mu.Lock()
+	states := make(map[string]*fsGidState, len(g.fsStates))
+	for fsId, state := range g.fsStates {
+		states[fsId] = state
+	}
+	g.mu.Unlock()
+
+	fileSystems := make(map[string]cloud.Cloud, len(states))
+	for fsId, state := range states {
+		state.mu.Lock()
+		fileSystems[fsId] = state.cloud
+		state.mu.Unlock()
+	}
+	return fileSystems
+}
+
+// reconcileUsedGids makes the GIDs of the access points listed at listedAt the GIDs in use on a file system, considered
+// in use until listed again by CreateVolume or until their reservation expires. GIDs missing from the listing are freed,
+// unless they were allocated less than gidVisibilityGrace before the listing. It returns the number of GIDs added and freed.
+func (g *GidAllocator) reconcileUsedGids(fsId string, accessPoints []*cloud.AccessPoint, listedAt time.Time) (added, removed int) {
+	usedGids, _ := g.getUsedGids(fsId, accessPoints)
+
+	state := g.getFsState(fsId)
+	state.mu.Lock()
+	defer state.mu.Unlock()
+
+	for gid, reservedAt := range state.reserved {
+		if slices.Contains(usedGids, gid) {
+			continue
+		}
+		if state.listed[gid] || reservedAt.Before(listedAt.Add(-gidVisibilityGrace)) {
+			delete(state.reserved, gid)
+			delete(state.listed, gid)
+			removed++
+		}
+	}
+	for _, gid := range usedGids {
+		if _, ok := state.reserved[gid]; !ok {
+			added++
+		}
+		state.reserved[gid] = listedAt
+		state.listed[gid] = true
+	}
+	return added, removed
+}
+
+// warmUpGids lists the access points of the file systems at startup, to report their utilization before the first
+// CreateVolume call and to have refreshGids list them too. CreateVolume still lists the access points on each call, the
+// warmed up GIDs only being kept in use while missing from its listings. Failures are logged and not fatal.
+func (d *Driver) warmUpGids(fileSystemIds []string) {
+	for _, fsId := range fileSystemIds {
+		listedAt := d.currentTime()
+		ctx, cancel := context.WithTimeout(context.Background(), gidListTimeout)
+		accessPoints, err := d.cloud.ListAccessPoints(ctx, fsId)
+		cancel()
+		if err != nil {
+			klog.Warningf("Failed to warm up the GIDs used on file system %v, they will be discovered by CreateVolume: %v", fsId, err)
+			continue
+		}
+		added, _ := d.gidAllocator.reconcileUsedGids(fsId, accessPoints, listedAt)
+		d.recordAccessPointUtilization(fsId, len(accessPoints))
+		klog.Infof("Warmed up %v GIDs used on file system %v", added, fsId)
+	}
+}
+
+// refreshGids lists the access points of the file systems known to the allocator again, so that the GIDs of access points
+// deleted out of band are freed and those of access points created out of band are not handed out. Failures are logged.
+func (d *Driver) refreshGids() {
+	for fsId, localCloud := range d.gidAllocator.fileSystems() {
+		if localCloud == nil {
+			localCloud = d.cloud
+		}
+		listedAt := d.currentTime()
+		ctx, cancel := context.WithTimeout(context.Background(), gidListTimeout)
+		accessPoints, err := localCloud.ListAccessPoints(ctx, fsId)
+		cancel()
+		if err != nil {
+			klog.Warningf("Failed to refresh the GIDs used on file system %v: %v", fsId, err)
+			continue
+		}
+		added, removed := d.gidAllocator.reconcileUsedGids(fsId, accessPoints, listedAt)
+		d.recordAccessPointUtilization(fsId, len(accessPoints))
+		klog.V(4).Infof("Refreshed the GIDs used on file system %v, %v added and %v freed", fsId, added, removed)
+	}
+}
+
+// ParseFileSystemIds parses a comma separated list of file system IDs.
+func ParseFileSystemIds(value string) ([]string, error) {
+	fileSystemIds := []string{}
+	for _, fsId := range strings.Split(value, ",") {
+		fsId = strings.TrimSpace(fsId)
+		if fsId == "" {
+			continue
+		}
+		if !isValidFileSystemId(fsId) {
+			return nil, fmt.Errorf("invalid file system ID %q", fsId)
+		}
+		fileSystemIds = append(fileSystemIds, fsId)
+	}
+	return fileSystemIds, nil
+}
+
 func (g *GidAllocator) getUsedGids(fsId string, accessPoints []*cloud.AccessPoint) (gids []int64, err error) {
 	gids = []int64{}
 	if len(accessPoints) == 0 {
@@ -64,18 +350,52 @@ func (g *GidAllocator) getUsedGids(fsId string, accessPoints []*cloud.AccessPoin
 	return
 }
 
-func getNextUnusedGid(usedGids []int64, gidMin, gidMax int64) (nextGid int64, err error) {
-	requestedRange := gidMax - gidMin
+// hashedGid derives the GID a hash key starts probing from, within the inclusive range
+func hashedGid(hashKey string, gidMin, gidMax int64) int64 {
+	h := fnv.New64a()
+	h.Write([]byte(hashKey))
+	return gidMin + int64(h.Sum64()%uint64(gidMax-gidMin+1))
+}
+
+// limitGidRange returns the upper bound of the GIDs allocated in the range, which is lowered when the range exceeds
+// the access point limit of a file system.
+func limitGidRange(reservedGids GidRanges, gidMin, gidMax int64) int64 {
+	// Reserved GIDs are not counted in the range, as they are never used by access points
+	requestedRange := gidMax - gidMin - reservedGids.Count(gidMin, gidMax)
+	if requestedRange <= cloud.AccessPointPerFsLimit {
+		return gidMax
+	}
 
-	if requestedRange > cloud.AccessPointPerFsLimit {
-		overrideGidMax := gidMin + cloud.AccessPointPerFsLimit
+	overrideGidMax := gidMin + cloud.AccessPointPerFsLimit
+	// The limited range is extended by the reserved GIDs it contains
+	for {
+		extended := gidMin + cloud.AccessPointPerFsLimit + reservedGids.Count(gidMin, overrideGidMax)
+		if extended == overrideGidMax {
+			return overrideGidMax
+		}
+		overrideGidMax = extended
+	}
+}
+
+func getNextUnusedGid(usedGids []int64, reservedGids GidRanges, gidMin, gidMax int64, hashKey string) (nextGid int64, err error) {
+	if overrideGidMax := limitGidRange(reservedGids, gidMin, gidMax); overrideGidMax != gidMax {
 		klog.Warningf("Requested GID range (%v:%v) exceeds EFS Access Point limit (%v) per Filesystem. Driver will use limited GID range (%v:%v)", gidMin, gidMax, cloud.AccessPointPerFsLimit, gidMin, overrideGidMax)
 		gidMax = overrideGidMax
 	}
 
+	// Hashed GIDs probe forward from their GID, wrapping around to the start of the range
+	start := gidMin
+	if hashKey != "" {
+		start = hashedGid(hashKey, gidMin, gidMax)
+	}
+
 	var lookup func(usedGids []int64)
 	lookup = func(usedGids []int64) {
-		for gid := gidMin; gid <= gidMax; gid++ {
+		for i := int64(0); i <= gidMax-gidMin; i++ {
+			gid := gidMin + (start-gidMin+i)%(gidMax-gidMin+1)
+			if reservedGids.contains(gid) {
+				continue
+			}
 			if !slices.Contains(usedGids, gid) {
 				nextGid = gid
 				return
@@ -88,7 +408,11 @@ func getNextUnusedGid(usedGids []int64, gidMin, gidMax int64) (nextGid int64, er
 	nextGid = -1
 	lookup(usedGids)
 	if nextGid == -1 {
-		err = fmt.Errorf("allocator failed to find available GID")
+		err = &GidRangeExhaustedError{
+			GidMin:   gidMin,
+			GidMax:   gidMax,
+			UsedGids: len(usedGids),
+		}
 		return
 	}
 
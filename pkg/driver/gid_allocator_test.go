@@ -0,0 +1,858 @@
+package driver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	"github.com/golang/mock/gomock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/kubernetes-sigs/aws-efs-csi-driver/pkg/cloud"
+	"github.com/kubernetes-sigs/aws-efs-csi-driver/pkg/cloud/fake"
+	"github.com/kubernetes-sigs/aws-efs-csi-driver/pkg/driver/mocks"
+)
+
+func TestGetNextGidConcurrent(t *testing.T) {
+	var (
+		fsId        = "fs-abcd1234"
+		gidMin      = int64(1000)
+		gidMax      = int64(1100)
+		goroutines  = 50
+		gidAlloc    = NewGidAllocator()
+		accessPoint = &cloud.AccessPoint{
+			AccessPointId: "fsap-abcd1234xyz987",
+			FileSystemId:  fsId,
+			PosixUser: &cloud.PosixUser{
+				Gid: gidMin,
+				Uid: gidMin,
+			},
+		}
+	)
+
+	var wg sync.WaitGroup
+	gids := make(chan int64, goroutines)
+	errs := make(chan error, goroutines)
+	for i := 0; i < goroutines; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			// Every call sees the same listing, as concurrent CreateVolume calls would
+			gid, err := gidAlloc.getNextGid(fsId, []*cloud.AccessPoint{accessPoint}, gidMin, gidMax)
+			if err != nil {
+				errs <- err
+				return
+			}
+			gids <- gid
+		}()
+	}
+	wg.Wait()
+	close(gids)
+	close(errs)
+
+	for err := range errs {
+		t.Fatalf("getNextGid failed: %v", err)
+	}
+	seen := map[int64]bool{}
+	for gid := range gids {
+		if gid == gidMin {
+			t.Fatalf("getNextGid returned GID %v which is used by an access point", gid)
+		}
+		if seen[gid] {
+			t.Fatalf("getNextGid returned GID %v more than once", gid)
+		}
+		seen[gid] = true
+	}
+	if len(seen) != goroutines {
+		t.Fatalf("Expected %v unique GIDs, got %v", goroutines, len(seen))
+	}
+}
+
+func TestGetNextGidReservations(t *testing.T) {
+	var (
+		fsId1  = "fs-abcd1234"
+		fsId2  = "fs-ef015678"
+		gidMin = int64(1000)
+		gidMax = int64(1100)
+	)
+
+	testCases := []struct {
+		name     string
+		testFunc func(t *testing.T)
+	}{
+		{
+			name: "Reservations are per file system",
+			testFunc: func(t *testing.T) {
+				gidAlloc := NewGidAllocator()
+				gid1, err := gidAlloc.getNextGid(fsId1, nil, gidMin, gidMax)
+				if err != nil {
+					t.Fatalf("getNextGid failed: %v", err)
+				}
+				gid2, err := gidAlloc.getNextGid(fsId2, nil, gidMin, gidMax)
+				if err != nil {
+					t.Fatalf("getNextGid failed: %v", err)
+				}
+				if gid1 != gidMin || gid2 != gidMin {
+					t.Fatalf("Expected both file systems to get GID %v, got %v and %v", gidMin, gid1, gid2)
+				}
+			},
+		},
+		{
+			name: "Released GID is reused",
+			testFunc: func(t *testing.T) {
+				gidAlloc := NewGidAllocator()
+				gid, err := gidAlloc.getNextGid(fsId1, nil, gidMin, gidMax)
+				if err != nil {
+					t.Fatalf("getNextGid failed: %v", err)
+				}
+				gidAlloc.releaseGid(fsId1, gid)
+				next, err := gidAlloc.getNextGid(fsId1, nil, gidMin, gidMax)
+				if err != nil {
+					t.Fatalf("getNextGid failed: %v", err)
+				}
+				if next != gid {
+					t.Fatalf("Expected released GID %v to be reused, got %v", gid, next)
+				}
+			},
+		},
+		{
+			name: "Reservation is dropped once the access point is listed and deleted",
+			testFunc: func(t *testing.T) {
+				gidAlloc := NewGidAllocator()
+				gid, err := gidAlloc.getNextGid(fsId1, nil, gidMin, gidMax)
+				if err != nil {
+					t.Fatalf("getNextGid failed: %v", err)
+				}
+				accessPoints := []*cloud.AccessPoint{
+					{
+						FileSystemId: fsId1,
+						PosixUser: &cloud.PosixUser{
+							Gid: gid,
+							Uid: gid,
+						},
+					},
+				}
+				if _, err = gidAlloc.getNextGid(fsId1, accessPoints, gidMin, gidMax); err != nil {
+					t.Fatalf("getNextGid failed: %v", err)
+				}
+				gidAlloc.releaseGid(fsId1, gid+1)
+				// The access point was deleted, its GID is free again
+				next, err := gidAlloc.getNextGid(fsId1, nil, gidMin, gidMax)
+				if err != nil {
+					t.Fatalf("getNextGid failed: %v", err)
+				}
+				if next != gid {
+					t.Fatalf("Expected GID %v to be reused, got %v", gid, next)
+				}
+			},
+		},
+		{
+			name: "Reservation expires after its TTL",
+			testFunc: func(t *testing.T) {
+				now := time.Now()
+				gidAlloc := NewGidAllocator()
+				gidAlloc.now = func() time.Time { return now }
+				gid, err := gidAlloc.getNextGid(fsId1, nil, gidMin, gidMax)
+				if err != nil {
+					t.Fatalf("getNextGid failed: %v", err)
+				}
+				// The access point never showed up in a listing
+				now = now.Add(gidReservationTTL + time.Second)
+				next, err := gidAlloc.getNextGid(fsId1, nil, gidMin, gidMax)
+				if err != nil {
+					t.Fatalf("getNextGid failed: %v", err)
+				}
+				if next != gid {
+					t.Fatalf("Expected expired GID %v to be reused, got %v", gid, next)
+				}
+			},
+		},
+		{
+			name: "Releasing an untracked GID is a no-op",
+			testFunc: func(t *testing.T) {
+				gidAlloc := NewGidAllocator()
+				gid, err := gidAlloc.getNextGid(fsId1, nil, gidMin, gidMax)
+				if err != nil {
+					t.Fatalf("getNextGid failed: %v", err)
+				}
+				gidAlloc.releaseGid(fsId1, gid+1)
+				gidAlloc.releaseGid(fsId2, gid)
+				next, err := gidAlloc.getNextGid(fsId1, nil, gidMin, gidMax)
+				if err != nil {
+					t.Fatalf("getNextGid failed: %v", err)
+				}
+				if next != gid+1 {
+					t.Fatalf("Expected GID %v, got %v", gid+1, next)
+				}
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, tc.testFunc)
+	}
+}
+
+func TestGetNextGidRangeReserve(t *testing.T) {
+	fsId := "fs-abcd1234"
+
+	testCases := []struct {
+		name          string
+		reserve       float64
+		gidMin        int64
+		gidMax        int64
+		reservedGids  GidRanges
+		listedGids    []int64
+		expectedGids  int
+		expectedSpare int
+	}{
+		{
+			name:          "Allocation stops at the reserve boundary",
+			reserve:       0.2,
+			gidMin:        1000,
+			gidMax:        1009,
+			expectedGids:  8,
+			expectedSpare: 2,
+		},
+		{
+			name:          "Fraction of the range rounded down",
+			reserve:       0.3,
+			gidMin:        1000,
+			gidMax:        1009,
+			expectedGids:  7,
+			expectedSpare: 3,
+		},
+		{
+			name:          "GIDs of listed access points count",
+			reserve:       0.5,
+			gidMin:        1000,
+			gidMax:        1009,
+			listedGids:    []int64{1000, 1001, 999, 1010},
+			expectedGids:  3,
+			expectedSpare: 5,
+		},
+		{
+			name:          "Reserved GIDs are not in the range",
+			reserve:       0.5,
+			gidMin:        1000,
+			gidMax:        1009,
+			reservedGids:  GidRanges{{Min: 1000, Max: 1003}},
+			expectedGids:  3,
+			expectedSpare: 3,
+		},
+		{
+			name:          "GIDs shared by listed access points count once",
+			reserve:       0.5,
+			gidMin:        1000,
+			gidMax:        1009,
+			listedGids:    []int64{1000, 1000, 1000, 1001, 1001},
+			expectedGids:  3,
+			expectedSpare: 5,
+		},
+		{
+			name:          "Reserved GIDs in use do not count",
+			reserve:       0.5,
+			gidMin:        1000,
+			gidMax:        1009,
+			reservedGids:  GidRanges{{Min: 1000, Max: 1003}},
+			listedGids:    []int64{1000, 1001, 1002},
+			expectedGids:  3,
+			expectedSpare: 3,
+		},
+		{
+			name:          "Default range counts all the GIDs the allocator can use",
+			reserve:       0.5,
+			gidMin:        DefaultGidMin,
+			gidMax:        DefaultGidMax,
+			expectedGids:  500,
+			expectedSpare: 501,
+		},
+		{
+			name:          "Range limited to the access point limit like the allocator",
+			reserve:       0.5,
+			gidMin:        1000,
+			gidMax:        5000,
+			expectedGids:  500,
+			expectedSpare: 501,
+		},
+		{
+			name:         "Whole range allocated without reserve",
+			gidMin:       1000,
+			gidMax:       1009,
+			expectedGids: 10,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			gidAlloc := NewGidAllocator()
+			gidAlloc.reservedGids = tc.reservedGids
+			gidAlloc.rangeReserve = tc.reserve
+			var accessPoints []*cloud.AccessPoint
+			for _, gid := range tc.listedGids {
+				accessPoints = append(accessPoints, &cloud.AccessPoint{FileSystemId: fsId, PosixUser: &cloud.PosixUser{Gid: gid, Uid: gid}})
+			}
+
+			allocated := 0
+			var err error
+			for ; allocated <= int(tc.gidMax-tc.gidMin)+1; allocated++ {
+				if _, err = gidAlloc.getNextGid(fsId, accessPoints, tc.gidMin, tc.gidMax); err != nil {
+					break
+				}
+			}
+			if allocated != tc.expectedGids {
+				t.Fatalf("Allocated GIDs mismatched. Expected: %v, Actual: %v", tc.expectedGids, allocated)
+			}
+			exhaustedErr, ok := err.(*GidRangeExhaustedError)
+			if !ok {
+				t.Fatalf("Expected a GidRangeExhaustedError, got: %v", err)
+			}
+			if exhaustedErr.Headroom != tc.expectedSpare {
+				t.Fatalf("Headroom mismatched. Expected: %v, Actual: %v", tc.expectedSpare, exhaustedErr.Headroom)
+			}
+		})
+	}
+}
+
+func TestGetNextGidUsage(t *testing.T) {
+	fsId := "fs-abcd1234"
+	gidAlloc := NewGidAllocator()
+	gidAlloc.reservedGids = GidRanges{{Min: 1005, Max: 1005}}
+	var accessPoints []*cloud.AccessPoint
+	// Access points with a fixed GID share it, and a reserved GID is in use
+	for _, gid := range []int64{1000, 1000, 1001, 1005} {
+		accessPoints = append(accessPoints, &cloud.AccessPoint{FileSystemId: fsId, PosixUser: &cloud.PosixUser{Gid: gid, Uid: gid}})
+	}
+
+	if _, err := gidAlloc.getNextGid(fsId, accessPoints, 1000, 1009); err != nil {
+		t.Fatalf("getNextGid failed: %v", err)
+	}
+	if inUse := gidAlloc.getFsState(fsId).inUse; inUse != 3 {
+		t.Fatalf("GIDs in use mismatched. Expected: 3, Actual: %v", inUse)
+	}
+}
+
+func TestGetHashedGid(t *testing.T) {
+	var (
+		fsId   = "fs-abcd1234"
+		gidMin = int64(1000)
+		gidMax = int64(1003)
+		key    = "team-a/data"
+	)
+	usedBy := func(gids ...int64) []*cloud.AccessPoint {
+		var accessPoints []*cloud.AccessPoint
+		for _, gid := range gids {
+			accessPoints = append(accessPoints, &cloud.AccessPoint{FileSystemId: fsId, PosixUser: &cloud.PosixUser{Gid: gid, Uid: gid}})
+		}
+		return accessPoints
+	}
+	// keyHashedTo finds a hash key starting at the GID
+	keyHashedTo := func(t *testing.T, gid int64) string {
+		for i := 0; i < 1000; i++ {
+			if key := fmt.Sprintf("team-a/pvc-%v", i); hashedGid(key, gidMin, gidMax) == gid {
+				return key
+			}
+		}
+		t.Fatalf("No key hashed to GID %v", gid)
+		return ""
+	}
+
+	testCases := []struct {
+		name     string
+		testFunc func(t *testing.T)
+	}{
+		{
+			name: "Same key gets the same GID",
+			testFunc: func(t *testing.T) {
+				gidAlloc1, gidAlloc2 := NewGidAllocator(), NewGidAllocator()
+				gid1, err := gidAlloc1.getHashedGid(fsId, nil, gidMin, gidMax, key)
+				if err != nil {
+					t.Fatalf("getHashedGid failed: %v", err)
+				}
+				gid2, err := gidAlloc2.getHashedGid(fsId, nil, gidMin, gidMax, key)
+				if err != nil {
+					t.Fatalf("getHashedGid failed: %v", err)
+				}
+				if gid1 != gid2 || gid1 != hashedGid(key, gidMin, gidMax) {
+					t.Fatalf("Expected GID %v twice, got %v and %v", hashedGid(key, gidMin, gidMax), gid1, gid2)
+				}
+				if gid1 < gidMin || gid1 > gidMax {
+					t.Fatalf("GID %v is out of range %v-%v", gid1, gidMin, gidMax)
+				}
+			},
+		},
+		{
+			name: "Used GID probes forward",
+			testFunc: func(t *testing.T) {
+				key := keyHashedTo(t, gidMin+1)
+				gidAlloc := NewGidAllocator()
+				gid, err := gidAlloc.getHashedGid(fsId, usedBy(gidMin+1, gidMin+2), gidMin, gidMax, key)
+				if err != nil {
+					t.Fatalf("getHashedGid failed: %v", err)
+				}
+				if gid != gidMin+3 {
+					t.Fatalf("Expected GID %v, got %v", gidMin+3, gid)
+				}
+			},
+		},
+		{
+			name: "Probing wraps around the range",
+			testFunc: func(t *testing.T) {
+				key := keyHashedTo(t, gidMax)
+				gidAlloc := NewGidAllocator()
+				gid, err := gidAlloc.getHashedGid(fsId, usedBy(gidMax), gidMin, gidMax, key)
+				if err != nil {
+					t.Fatalf("getHashedGid failed: %v", err)
+				}
+				if gid != gidMin {
+					t.Fatalf("Expected GID %v, got %v", gidMin, gid)
+				}
+			},
+		},
+		{
+			name: "Reserved GID probes forward",
+			testFunc: func(t *testing.T) {
+				gidAlloc := NewGidAllocator()
+				key := keyHashedTo(t, gidMin)
+				gid1, err := gidAlloc.getHashedGid(fsId, nil, gidMin, gidMax, key)
+				if err != nil {
+					t.Fatalf("getHashedGid failed: %v", err)
+				}
+				gid2, err := gidAlloc.getHashedGid(fsId, nil, gidMin, gidMax, key)
+				if err != nil {
+					t.Fatalf("getHashedGid failed: %v", err)
+				}
+				if gid1 != gidMin || gid2 != gidMin+1 {
+					t.Fatalf("Expected GIDs %v and %v, got %v and %v", gidMin, gidMin+1, gid1, gid2)
+				}
+			},
+		},
+		{
+			name: "Fail: Range exhausted",
+			testFunc: func(t *testing.T) {
+				gidAlloc := NewGidAllocator()
+				_, err := gidAlloc.getHashedGid(fsId, usedBy(1000, 1001, 1002, 1003), gidMin, gidMax, key)
+				var exhaustedErr *GidRangeExhaustedError
+				if !errors.As(err, &exhaustedErr) {
+					t.Fatalf("Expected a GidRangeExhaustedError, got: %v", err)
+				}
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, tc.testFunc)
+	}
+}
+
+func TestCreateVolumeGidAllocationMode(t *testing.T) {
+	fsId := "fs-abcd1234"
+	fakeCloud := fake.NewCloud()
+	fakeCloud.AddFileSystem(fsId)
+	driver := &Driver{
+		cloud:        fakeCloud,
+		gidAllocator: NewGidAllocator(),
+	}
+
+	createVolume := func(name string, params map[string]string) (*csi.Volume, error) {
+		parameters := map[string]string{
+			ProvisioningMode: AccessPointMode,
+			FsId:             fsId,
+			DirectoryPerms:   "700",
+			GidMin:           "1000",
+			GidMax:           "2000",
+		}
+		for k, v := range params {
+			parameters[k] = v
+		}
+		res, err := driver.CreateVolume(context.Background(), &csi.CreateVolumeRequest{
+			Name: name,
+			VolumeCapabilities: []*csi.VolumeCapability{
+				{
+					AccessType: &csi.VolumeCapability_Mount{
+						Mount: &csi.VolumeCapability_MountVolume{},
+					},
+					AccessMode: &csi.VolumeCapability_AccessMode{
+						Mode: csi.VolumeCapability_AccessMode_MULTI_NODE_MULTI_WRITER,
+					},
+				},
+			},
+			CapacityRange: &csi.CapacityRange{RequiredBytes: 5 * 1024 * 1024 * 1024},
+			Parameters:    parameters,
+		})
+		if err != nil {
+			return nil, err
+		}
+		return res.Volume, nil
+	}
+	gidOf := func(volume *csi.Volume) int64 {
+		_, _, apId, err := parseVolumeId(volume.VolumeId)
+		if err != nil {
+			t.Fatalf("parseVolumeId failed: %v", err)
+		}
+		accessPoint, err := fakeCloud.DescribeAccessPoint(context.Background(), apId)
+		if err != nil {
+			t.Fatalf("DescribeAccessPoint failed: %v", err)
+		}
+		return accessPoint.PosixUser.Gid
+	}
+
+	hashed := map[string]string{GidAllocationMode: GidHashed, PvcNamespace: "team-a", PvcName: "data"}
+	volume, err := createVolume("pvc-1", hashed)
+	if err != nil {
+		t.Fatalf("CreateVolume failed: %v", err)
+	}
+	gid := gidOf(volume)
+	if expected := hashedGid("team-a/data", 1000, 2000); gid != expected {
+		t.Fatalf("Expected hashed GID %v, got %v", expected, gid)
+	}
+
+	// Re-creating the PVC gets the same GID
+	if _, err := driver.DeleteVolume(context.Background(), &csi.DeleteVolumeRequest{VolumeId: volume.VolumeId}); err != nil {
+		t.Fatalf("DeleteVolume failed: %v", err)
+	}
+	volume, err = createVolume("pvc-2", hashed)
+	if err != nil {
+		t.Fatalf("CreateVolume failed: %v", err)
+	}
+	if next := gidOf(volume); next != gid {
+		t.Fatalf("Expected the re-created PVC to get GID %v, got %v", gid, next)
+	}
+
+	volume, err = createVolume("pvc-3", map[string]string{GidAllocationMode: GidSequential})
+	if err != nil {
+		t.Fatalf("CreateVolume failed: %v", err)
+	}
+	expected := int64(1000)
+	if gid == expected {
+		expected++
+	}
+	if next := gidOf(volume); next != expected {
+		t.Fatalf("Expected sequential GID %v, got %v", expected, next)
+	}
+
+	if _, err := createVolume("pvc-4", map[string]string{GidAllocationMode: GidHashed}); status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("Expected an InvalidArgument error without the PVC name, got: %v", err)
+	}
+	if _, err := createVolume("pvc-5", map[string]string{GidAllocationMode: "random"}); status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("Expected an InvalidArgument error for an unknown mode, got: %v", err)
+	}
+}
+
+func TestDeleteVolumeReleasesGid(t *testing.T) {
+	fsId := "fs-abcd1234"
+	fakeCloud := fake.NewCloud()
+	fakeCloud.AddFileSystem(fsId)
+	driver := &Driver{
+		cloud:        fakeCloud,
+		gidAllocator: NewGidAllocator(),
+	}
+
+	createVolume := func(name string) *csi.Volume {
+		res, err := driver.CreateVolume(context.Background(), &csi.CreateVolumeRequest{
+			Name: name,
+			VolumeCapabilities: []*csi.VolumeCapability{
+				{
+					AccessType: &csi.VolumeCapability_Mount{
+						Mount: &csi.VolumeCapability_MountVolume{},
+					},
+					AccessMode: &csi.VolumeCapability_AccessMode{
+						Mode: csi.VolumeCapability_AccessMode_MULTI_NODE_MULTI_WRITER,
+					},
+				},
+			},
+			CapacityRange: &csi.CapacityRange{RequiredBytes: 5 * 1024 * 1024 * 1024},
+			Parameters: map[string]string{
+				ProvisioningMode: AccessPointMode,
+				FsId:             fsId,
+				DirectoryPerms:   "700",
+				GidMin:           "1000",
+				GidMax:           "1100",
+			},
+		})
+		if err != nil {
+			t.Fatalf("CreateVolume failed: %v", err)
+		}
+		return res.Volume
+	}
+	gidOf := func(volumeId string) int64 {
+		_, _, apId, err := parseVolumeId(volumeId)
+		if err != nil {
+			t.Fatalf("parseVolumeId failed: %v", err)
+		}
+		accessPoint, err := fakeCloud.DescribeAccessPoint(context.Background(), apId)
+		if err != nil {
+			t.Fatalf("DescribeAccessPoint failed: %v", err)
+		}
+		return accessPoint.PosixUser.Gid
+	}
+
+	volume := createVolume("pvc-1")
+	gid := gidOf(volume.VolumeId)
+	// Deleting the volume again releases nothing more
+	for i := 0; i < 2; i++ {
+		if _, err := driver.DeleteVolume(context.Background(), &csi.DeleteVolumeRequest{VolumeId: volume.VolumeId}); err != nil {
+			t.Fatalf("DeleteVolume failed: %v", err)
+		}
+	}
+	if reserved := driver.gidAllocator.getFsState(fsId).reserved; len(reserved) != 0 {
+		t.Fatalf("Expected no GID in use, got %v", reserved)
+	}
+	if next := gidOf(createVolume("pvc-2").VolumeId); next != gid {
+		t.Fatalf("Expected the GID %v of the deleted volume to be reused, got %v", gid, next)
+	}
+}
+
+func TestWarmUpGids(t *testing.T) {
+	var (
+		fsId1  = "fs-abcd1234"
+		fsId2  = "fs-ef015678"
+		gidMin = int64(1000)
+		gidMax = int64(1100)
+	)
+
+	testCases := []struct {
+		name     string
+		testFunc func(t *testing.T)
+	}{
+		{
+			name: "Success: GIDs of the listed access points are in use",
+			testFunc: func(t *testing.T) {
+				mockCtl := gomock.NewController(t)
+				mockCloud := mocks.NewMockCloud(mockCtl)
+				driver := &Driver{
+					cloud:        mockCloud,
+					gidAllocator: NewGidAllocator(),
+				}
+
+				accessPoints := []*cloud.AccessPoint{
+					{FileSystemId: fsId1, PosixUser: &cloud.PosixUser{Gid: gidMin, Uid: gidMin}},
+					{FileSystemId: fsId1, PosixUser: &cloud.PosixUser{Gid: gidMin + 1, Uid: gidMin + 1}},
+				}
+				mockCloud.EXPECT().ListAccessPoints(gomock.Any(), gomock.Eq(fsId1)).Return(accessPoints, nil)
+				driver.warmUpGids([]string{fsId1})
+
+				state := driver.gidAllocator.getFsState(fsId1)
+				if len(state.reserved) != 2 {
+					t.Fatalf("Expected 2 GIDs in use, got %v", state.reserved)
+				}
+				// The first CreateVolume may not list the access points yet
+				gid, err := driver.gidAllocator.getNextGid(fsId1, nil, gidMin, gidMax)
+				if err != nil {
+					t.Fatalf("getNextGid failed: %v", err)
+				}
+				if gid != gidMin+2 {
+					t.Fatalf("Expected GID %v, got %v", gidMin+2, gid)
+				}
+				mockCtl.Finish()
+			},
+		},
+		{
+			name: "Success: Failure to list access points is not fatal",
+			testFunc: func(t *testing.T) {
+				mockCtl := gomock.NewController(t)
+				mockCloud := mocks.NewMockCloud(mockCtl)
+				driver := &Driver{
+					cloud:        mockCloud,
+					gidAllocator: NewGidAllocator(),
+				}
+
+				accessPoints := []*cloud.AccessPoint{
+					{FileSystemId: fsId2, PosixUser: &cloud.PosixUser{Gid: gidMin, Uid: gidMin}},
+				}
+				mockCloud.EXPECT().ListAccessPoints(gomock.Any(), gomock.Eq(fsId1)).Return(nil, errors.New("DescribeAccessPoints failed"))
+				mockCloud.EXPECT().ListAccessPoints(gomock.Any(), gomock.Eq(fsId2)).Return(accessPoints, nil)
+				driver.warmUpGids([]string{fsId1, fsId2})
+
+				if reserved := driver.gidAllocator.getFsState(fsId1).reserved; len(reserved) != 0 {
+					t.Fatalf("Expected no GID in use on %v, got %v", fsId1, reserved)
+				}
+				if reserved := driver.gidAllocator.getFsState(fsId2).reserved; len(reserved) != 1 {
+					t.Fatalf("Expected 1 GID in use on %v, got %v", fsId2, reserved)
+				}
+				mockCtl.Finish()
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, tc.testFunc)
+	}
+}
+
+func TestRefreshGids(t *testing.T) {
+	var (
+		fsId   = "fs-abcd1234"
+		gidMin = int64(1000)
+		gidMax = int64(1100)
+	)
+
+	accessPoint := func(gid int64) *cloud.AccessPoint {
+		return &cloud.AccessPoint{FileSystemId: fsId, PosixUser: &cloud.PosixUser{Gid: gid, Uid: gid}}
+	}
+
+	testCases := []struct {
+		name     string
+		testFunc func(t *testing.T)
+	}{
+		{
+			name: "Success: GID of an access point deleted out of band is reclaimed after refresh",
+			testFunc: func(t *testing.T) {
+				mockCtl := gomock.NewController(t)
+				mockCloud := mocks.NewMockCloud(mockCtl)
+				driver := &Driver{
+					cloud:        mockCloud,
+					gidAllocator: NewGidAllocator(),
+				}
+
+				mockCloud.EXPECT().ListAccessPoints(gomock.Any(), gomock.Eq(fsId)).Return([]*cloud.AccessPoint{accessPoint(gidMin), accessPoint(gidMin + 1)}, nil)
+				driver.warmUpGids([]string{fsId})
+				// CreateVolume may not list the access points yet
+				gid, err := driver.gidAllocator.getNextGid(fsId, nil, gidMin, gidMax)
+				if err != nil {
+					t.Fatalf("getNextGid failed: %v", err)
+				}
+				if gid != gidMin+2 {
+					t.Fatalf("Expected GID %v, got %v", gidMin+2, gid)
+				}
+				driver.gidAllocator.releaseGid(fsId, gid)
+
+				// The access point with the first GID is deleted in the AWS console
+				mockCloud.EXPECT().ListAccessPoints(gomock.Any(), gomock.Eq(fsId)).Return([]*cloud.AccessPoint{accessPoint(gidMin + 1)}, nil)
+				driver.refreshGids()
+				gid, err = driver.gidAllocator.getNextGid(fsId, nil, gidMin, gidMax)
+				if err != nil {
+					t.Fatalf("getNextGid failed: %v", err)
+				}
+				if gid != gidMin {
+					t.Fatalf("Expected GID %v of the deleted access point to be reclaimed, got %v", gidMin, gid)
+				}
+				mockCtl.Finish()
+			},
+		},
+		{
+			name: "Success: GID of an access point created out of band is in use after refresh",
+			testFunc: func(t *testing.T) {
+				mockCtl := gomock.NewController(t)
+				mockCloud := mocks.NewMockCloud(mockCtl)
+				driver := &Driver{
+					cloud:        mockCloud,
+					gidAllocator: NewGidAllocator(),
+				}
+
+				mockCloud.EXPECT().ListAccessPoints(gomock.Any(), gomock.Eq(fsId)).Return([]*cloud.AccessPoint{}, nil)
+				driver.warmUpGids([]string{fsId})
+
+				mockCloud.EXPECT().ListAccessPoints(gomock.Any(), gomock.Eq(fsId)).Return([]*cloud.AccessPoint{accessPoint(gidMin)}, nil)
+				driver.refreshGids()
+				gid, err := driver.gidAllocator.getNextGid(fsId, nil, gidMin, gidMax)
+				if err != nil {
+					t.Fatalf("getNextGid failed: %v", err)
+				}
+				if gid != gidMin+1 {
+					t.Fatalf("Expected GID %v, got %v", gidMin+1, gid)
+				}
+				mockCtl.Finish()
+			},
+		},
+		{
+			name: "Success: GID just allocated is kept while its access point is not listed",
+			testFunc: func(t *testing.T) {
+				mockCtl := gomock.NewController(t)
+				mockCloud := mocks.NewMockCloud(mockCtl)
+				driver := &Driver{
+					cloud:        mockCloud,
+					gidAllocator: NewGidAllocator(),
+				}
+
+				allocated, err := driver.gidAllocator.getNextGid(fsId, nil, gidMin, gidMax)
+				if err != nil {
+					t.Fatalf("getNextGid failed: %v", err)
+				}
+				// An allocation older than the grace period whose access point was never listed
+				driver.gidAllocator.getFsState(fsId).reserved[gidMin+50] = time.Now().Add(-2 * gidVisibilityGrace)
+
+				mockCloud.EXPECT().ListAccessPoints(gomock.Any(), gomock.Eq(fsId)).Return([]*cloud.AccessPoint{}, nil)
+				driver.refreshGids()
+				reserved := driver.gidAllocator.getFsState(fsId).reserved
+				if _, ok := reserved[allocated]; !ok {
+					t.Fatalf("GID %v just allocated was freed by the refresh", allocated)
+				}
+				if _, ok := reserved[gidMin+50]; ok {
+					t.Fatalf("GID %v missing from the listing after the grace period was not freed", gidMin+50)
+				}
+				mockCtl.Finish()
+			},
+		},
+		{
+			name: "Success: File system of another region is refreshed with its cloud",
+			testFunc: func(t *testing.T) {
+				mockCtl := gomock.NewController(t)
+				mockCloud := mocks.NewMockCloud(mockCtl)
+				regionalCloud := mocks.NewMockCloud(mockCtl)
+				driver := &Driver{
+					cloud:        mockCloud,
+					gidAllocator: NewGidAllocator(),
+				}
+
+				driver.gidAllocator.setCloud(fsId, regionalCloud)
+				regionalCloud.EXPECT().ListAccessPoints(gomock.Any(), gomock.Eq(fsId)).Return(nil, errors.New("DescribeAccessPoints failed"))
+				driver.refreshGids()
+				mockCtl.Finish()
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, tc.testFunc)
+	}
+}
+
+func TestParseFileSystemIds(t *testing.T) {
+	testCases := []struct {
+		name          string
+		value         string
+		fileSystemIds []string
+		expectError   bool
+	}{
+		{
+			name:          "Empty",
+			value:         "",
+			fileSystemIds: []string{},
+		},
+		{
+			name:          "List with spaces and empty entries",
+			value:         " fs-abcd1234, ,fs-ef015678,",
+			fileSystemIds: []string{"fs-abcd1234", "fs-ef015678"},
+		},
+		{
+			name:        "Invalid file system ID",
+			value:       "fs-abcd1234,fsap-abcd1234",
+			expectError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			fileSystemIds, err := ParseFileSystemIds(tc.value)
+			if tc.expectError {
+				if err == nil {
+					t.Fatalf("ParseFileSystemIds did not fail")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseFileSystemIds failed: %v", err)
+			}
+			if !reflect.DeepEqual(fileSystemIds, tc.fileSystemIds) {
+				t.Fatalf("Expected %v, got %v", tc.fileSystemIds, fileSystemIds)
+			}
+		})
+	}
+}
@@ -0,0 +1,146 @@
+package driver
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"sort"
+	"syscall"
+
+	"k8s.io/klog/v2"
+
+	"github.com/kubernetes-sigs/aws-efs-csi-driver/pkg/cloud"
+)
+
+// gidAudit compares the GIDs of the access points of a file system with the GIDs the allocator considers in use
+type gidAudit struct {
+	fileSystemId string
+	// accessPoints is the number of access points of the file system provisioned by the driver
+	accessPoints int
+	// trackedGids is the number of GIDs the allocator considers in use
+	trackedGids int
+	// untrackedGids are the GIDs of access points provisioned by the driver which the allocator may hand out again
+	untrackedGids []int64
+	// staleGids are considered in use by the allocator, but no access point of the file system uses them
+	staleGids []int64
+	// pendingGids were allocated by CreateVolume and not listed yet, their access point may be in creation
+	pendingGids []int64
+}
+
+// trackedGids returns the GIDs considered in use on a file system, split between the GIDs found in listings of its
+// access points and the GIDs allocated since, which are not listed yet
+func (g *GidAllocator) trackedGids(fsId string) (listed, pending []int64) {
+	state := g.getFsState(fsId)
+	state.mu.Lock()
+	defer state.mu.Unlock()
+
+	for gid := range state.reserved {
+		if state.listed[gid] {
+			listed = append(listed, gid)
+		} else {
+			pending = append(pending, gid)
+		}
+	}
+	sortGids(listed)
+	sortGids(pending)
+	return listed, pending
+}
+
+// auditGids lists the access points of the file systems known to the allocator and reports the GIDs whose state
+// differs between the allocator and EFS. It only reports, nothing is changed on either side.
+func (d *Driver) auditGids(ctx context.Context) []*gidAudit {
+	fileSystems := d.gidAllocator.fileSystems()
+	fileSystemIds := make([]string, 0, len(fileSystems))
+	for fsId := range fileSystems {
+		fileSystemIds = append(fileSystemIds, fsId)
+	}
+	sort.Strings(fileSystemIds)
+
+	audits := []*gidAudit{}
+	for _, fsId := range fileSystemIds {
+		localCloud := fileSystems[fsId]
+		if localCloud == nil {
+			localCloud = d.cloud
+		}
+		listCtx, cancel := context.WithTimeout(ctx, gidListTimeout)
+		accessPoints, err := localCloud.ListAccessPoints(listCtx, fsId)
+		cancel()
+		if err != nil {
+			klog.Warningf("Failed to list the access points of file system %v to audit its GIDs: %v", fsId, err)
+			continue
+		}
+		audits = append(audits, d.auditFileSystemGids(fsId, accessPoints))
+	}
+	return audits
+}
+
+// auditFileSystemGids compares the GIDs of the listed access points of a file system with the GIDs tracked by the allocator
+func (d *Driver) auditFileSystemGids(fsId string, accessPoints []*cloud.AccessPoint) *gidAudit {
+	listed, pending := d.gidAllocator.trackedGids(fsId)
+	audit := &gidAudit{
+		fileSystemId: fsId,
+		trackedGids:  len(listed) + len(pending),
+		pendingGids:  pending,
+	}
+
+	tracked := map[int64]bool{}
+	for _, gid := range append(listed, pending...) {
+		tracked[gid] = true
+	}
+	// Access points created out of band are tracked too, so that their GIDs are not handed out
+	used := map[int64]bool{}
+	for _, accessPoint := range accessPoints {
+		if accessPoint == nil || accessPoint.PosixUser == nil {
+			continue
+		}
+		gid := accessPoint.PosixUser.Gid
+		used[gid] = true
+		if !d.hasDriverTags(accessPoint.Tags) {
+			continue
+		}
+		audit.accessPoints++
+		if !tracked[gid] {
+			audit.untrackedGids = append(audit.untrackedGids, gid)
+		}
+	}
+	for _, gid := range listed {
+		if !used[gid] {
+			audit.staleGids = append(audit.staleGids, gid)
+		}
+	}
+	sortGids(audit.untrackedGids)
+	return audit
+}
+
+// logGidAudits logs the audit of every file system, at the default verbosity when the allocator and EFS disagree
+func logGidAudits(audits []*gidAudit) {
+	for _, audit := range audits {
+		keysAndValues := []interface{}{
+			"fileSystemId", audit.fileSystemId,
+			"accessPoints", audit.accessPoints,
+			"trackedGids", audit.trackedGids,
+			"untrackedGids", audit.untrackedGids,
+			"staleGids", audit.staleGids,
+			"pendingGids", audit.pendingGids,
+		}
+		if len(audit.untrackedGids) > 0 || len(audit.staleGids) > 0 {
+			klog.InfoS("GID audit found mismatches", keysAndValues...)
+			continue
+		}
+		klog.V(2).InfoS("GID audit found no mismatch", keysAndValues...)
+	}
+	klog.Infof("Audited the GIDs of %v file systems", len(audits))
+}
+
+// auditGidsOnSignal audits the GIDs of the file systems known to the allocator on every SIGUSR1
+func (d *Driver) auditGidsOnSignal() {
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, syscall.SIGUSR1)
+	for range sigs {
+		logGidAudits(d.auditGids(context.Background()))
+	}
+}
+
+func sortGids(gids []int64) {
+	sort.Slice(gids, func(i, j int) bool { return gids[i] < gids[j] })
+}
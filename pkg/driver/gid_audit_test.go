@@ -0,0 +1,127 @@
+package driver
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/kubernetes-sigs/aws-efs-csi-driver/pkg/cloud"
+	"github.com/kubernetes-sigs/aws-efs-csi-driver/pkg/cloud/fake"
+)
+
+func TestAuditGids(t *testing.T) {
+	var (
+		fsId1  = "fs-abcd1234"
+		fsId2  = "fs-ef015678"
+		gidMin = int64(1000)
+		gidMax = int64(1100)
+	)
+
+	createAccessPoint := func(t *testing.T, fakeCloud *fake.Cloud, fsId string, gid int64, tags map[string]string) {
+		_, err := fakeCloud.CreateAccessPoint(context.Background(), fmt.Sprintf("%v-%v", fsId, gid), &cloud.AccessPointOptions{
+			FileSystemId:  fsId,
+			Uid:           gid,
+			Gid:           gid,
+			DirectoryPath: "/",
+			Tags:          tags,
+		}, false)
+		if err != nil {
+			t.Fatalf("CreateAccessPoint failed: %v", err)
+		}
+	}
+	driverTags := map[string]string{DefaultTagKey: DefaultTagValue}
+
+	testCases := []struct {
+		name     string
+		testFunc func(t *testing.T)
+	}{
+		{
+			name: "Success: Allocator and EFS agree",
+			testFunc: func(t *testing.T) {
+				fakeCloud := fake.NewCloud()
+				fakeCloud.AddFileSystem(fsId1)
+				driver := &Driver{
+					cloud:        fakeCloud,
+					gidAllocator: NewGidAllocator(),
+				}
+				createAccessPoint(t, fakeCloud, fsId1, gidMin, driverTags)
+				createAccessPoint(t, fakeCloud, fsId1, gidMin+1, nil)
+				driver.warmUpGids([]string{fsId1})
+
+				audits := driver.auditGids(context.Background())
+				expected := []*gidAudit{{fileSystemId: fsId1, accessPoints: 1, trackedGids: 2}}
+				if !reflect.DeepEqual(audits, expected) {
+					t.Fatalf("Audits mismatched. Expected: %+v, Actual: %+v", expected[0], audits[0])
+				}
+			},
+		},
+		{
+			name: "Success: Mismatches are reported",
+			testFunc: func(t *testing.T) {
+				fakeCloud := fake.NewCloud()
+				fakeCloud.AddFileSystem(fsId1)
+				fakeCloud.AddFileSystem(fsId2)
+				driver := &Driver{
+					cloud:        fakeCloud,
+					gidAllocator: NewGidAllocator(),
+				}
+				createAccessPoint(t, fakeCloud, fsId1, gidMin, driverTags)
+				createAccessPoint(t, fakeCloud, fsId1, gidMin+1, driverTags)
+				driver.warmUpGids([]string{fsId1, fsId2})
+
+				// An access point is deleted and another one created out of band
+				accessPoints, err := fakeCloud.ListAccessPoints(context.Background(), fsId1)
+				if err != nil {
+					t.Fatalf("ListAccessPoints failed: %v", err)
+				}
+				for _, accessPoint := range accessPoints {
+					if accessPoint.PosixUser.Gid == gidMin {
+						if err := fakeCloud.DeleteAccessPoint(context.Background(), accessPoint.AccessPointId); err != nil {
+							t.Fatalf("DeleteAccessPoint failed: %v", err)
+						}
+					}
+				}
+				createAccessPoint(t, fakeCloud, fsId1, gidMin+5, driverTags)
+				// A GID is allocated by a CreateVolume call in flight
+				gid, err := driver.gidAllocator.getNextGid(fsId2, nil, gidMin, gidMax)
+				if err != nil {
+					t.Fatalf("getNextGid failed: %v", err)
+				}
+
+				audits := driver.auditGids(context.Background())
+				expected := []*gidAudit{
+					{
+						fileSystemId:  fsId1,
+						accessPoints:  2,
+						trackedGids:   2,
+						untrackedGids: []int64{gidMin + 5},
+						staleGids:     []int64{gidMin},
+					},
+					{
+						fileSystemId: fsId2,
+						trackedGids:  1,
+						pendingGids:  []int64{gid},
+					},
+				}
+				if len(audits) != len(expected) {
+					t.Fatalf("Expected %v audits, got %v", len(expected), len(audits))
+				}
+				for i := range expected {
+					if !reflect.DeepEqual(audits[i], expected[i]) {
+						t.Fatalf("Audit mismatched. Expected: %+v, Actual: %+v", expected[i], audits[i])
+					}
+				}
+
+				// The audit only reports
+				if reserved := driver.gidAllocator.getFsState(fsId1).reserved; len(reserved) != 2 {
+					t.Fatalf("Expected 2 GIDs in use on %v, got %v", fsId1, reserved)
+				}
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, tc.testFunc)
+	}
+}
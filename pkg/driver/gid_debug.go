@@ -0,0 +1,72 @@
+package driver
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+
+	"k8s.io/klog/v2"
+)
+
+// gidAllocatorState is the state of the GID allocator for a file system, as served on /debug/gids
+type gidAllocatorState struct {
+	FileSystemId string `json:"fileSystemId"`
+	// GidMin and GidMax are the range of the last allocation, 0 when only listings were made
+	GidMin int64 `json:"gidMin"`
+	GidMax int64 `json:"gidMax"`
+	// InUse is the number of GIDs in use in the range after the last allocation
+	InUse int `json:"inUse"`
+	// HighWaterMark is the highest number of GIDs in use in the range of an allocation
+	HighWaterMark int `json:"highWaterMark"`
+	// TrackedGids is the number of GIDs the allocator currently considers in use, listed or allocated
+	TrackedGids int `json:"trackedGids"`
+}
+
+// states returns the state of every file system known to the allocator, sorted by file system ID
+func (g *GidAllocator) states() []gidAllocatorState {
+	g.mu.Lock()
+	fsStates := make(map[string]*fsGidState, len(g.fsStates))
+	for fsId, state := range g.fsStates {
+		fsStates[fsId] = state
+	}
+	g.mu.Unlock()
+
+	states := make([]gidAllocatorState, 0, len(fsStates))
+	for fsId, state := range fsStates {
+		state.mu.Lock()
+		states = append(states, gidAllocatorState{
+			FileSystemId:  fsId,
+			GidMin:        state.gidMin,
+			GidMax:        state.gidMax,
+			InUse:         state.inUse,
+			HighWaterMark: state.highWaterMark,
+			TrackedGids:   len(state.reserved),
+		})
+		state.mu.Unlock()
+	}
+	sort.Slice(states, func(i, j int) bool { return states[i].FileSystemId < states[j].FileSystemId })
+	return states
+}
+
+// serveGids serves the state of the GID allocator as JSON. It is read-only, the allocator is not changed.
+func (d *Driver) serveGids(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(d.gidAllocator.states()); err != nil {
+		klog.Warningf("Failed to write the GID allocator state: %v", err)
+	}
+}
+
+// serveDebug serves the debugging endpoints of the driver at --debug-addr. Failures are logged and not fatal.
+func (d *Driver) serveDebug() {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/debug/gids", d.serveGids)
+	klog.Infof("Serving the GID allocator state on %v/debug/gids", d.debugAddress)
+	if err := http.ListenAndServe(d.debugAddress, mux); err != nil {
+		klog.Errorf("Failed to serve debugging endpoints on %v: %v", d.debugAddress, err)
+	}
+}
@@ -0,0 +1,62 @@
+package driver
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/kubernetes-sigs/aws-efs-csi-driver/pkg/cloud"
+)
+
+func TestServeGids(t *testing.T) {
+	driver := &Driver{gidAllocator: NewGidAllocator()}
+	accessPoints := []*cloud.AccessPoint{
+		{AccessPointId: "fsap-abcd1234", PosixUser: &cloud.PosixUser{Gid: 50000}},
+		{AccessPointId: "fsap-abcd5678", PosixUser: &cloud.PosixUser{Gid: 50001}},
+	}
+	for i := 0; i < 2; i++ {
+		if _, err := driver.gidAllocator.getNextGid("fs-abcd1234", accessPoints, 50000, 50010); err != nil {
+			t.Fatalf("getNextGid failed: %v", err)
+		}
+	}
+	// The GID allocated last is released, the high-water mark stays
+	driver.gidAllocator.releaseGid("fs-abcd1234", 50003)
+	if _, err := driver.gidAllocator.getNextGid("fs-ef015678", nil, 1000, 2000); err != nil {
+		t.Fatalf("getNextGid failed: %v", err)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(driver.serveGids))
+	defer server.Close()
+
+	res, err := http.Get(server.URL)
+	if err != nil {
+		t.Fatalf("GET failed: %v", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK || res.Header.Get("Content-Type") != "application/json" {
+		t.Fatalf("Unexpected response %v with content type %q", res.Status, res.Header.Get("Content-Type"))
+	}
+	var states []map[string]interface{}
+	if err := json.NewDecoder(res.Body).Decode(&states); err != nil {
+		t.Fatalf("Failed to decode the response: %v", err)
+	}
+	expected := []map[string]interface{}{
+		{"fileSystemId": "fs-abcd1234", "gidMin": 50000.0, "gidMax": 50010.0, "inUse": 4.0, "highWaterMark": 4.0, "trackedGids": 1.0},
+		{"fileSystemId": "fs-ef015678", "gidMin": 1000.0, "gidMax": 2000.0, "inUse": 1.0, "highWaterMark": 1.0, "trackedGids": 1.0},
+	}
+	if !reflect.DeepEqual(states, expected) {
+		t.Fatalf("States mismatched. Expected: %v, Actual: %v", expected, states)
+	}
+
+	// The endpoint is read-only
+	res, err = http.Post(server.URL, "application/json", nil)
+	if err != nil {
+		t.Fatalf("POST failed: %v", err)
+	}
+	res.Body.Close()
+	if res.StatusCode != http.StatusMethodNotAllowed {
+		t.Fatalf("Expected %v, got %v", http.StatusMethodNotAllowed, res.Status)
+	}
+}
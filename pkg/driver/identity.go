@@ -18,12 +18,29 @@ package driver
 
 import (
 	"context"
+	"sync"
+	"time"
 
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"k8s.io/klog/v2"
 
+	"github.com/kubernetes-sigs/aws-efs-csi-driver/pkg/cloud"
+
 	"github.com/container-storage-interface/spec/lib/go/csi"
+	"github.com/golang/protobuf/ptypes/wrappers"
 )
 
+// awsProbeTimeout bounds the EFS API call made by Probe, so that the liveness probe does not time out first
+const awsProbeTimeout = 5 * time.Second
+
+// awsProbe caches the result of the last EFS API connectivity check
+type awsProbe struct {
+	mu        sync.Mutex
+	lastCheck time.Time
+	lastErr   error
+}
+
 func (d *Driver) GetPluginInfo(ctx context.Context, req *csi.GetPluginInfoRequest) (*csi.GetPluginInfoResponse, error) {
 	resp := &csi.GetPluginInfoResponse{
 		Name:          driverName,
@@ -46,10 +63,53 @@ func (d *Driver) GetPluginCapabilities(ctx context.Context, req *csi.GetPluginCa
 			},
 		},
 	}
+	if d.volumeTopologyEnabled {
+		resp.Capabilities = append(resp.Capabilities, &csi.PluginCapability{
+			Type: &csi.PluginCapability_Service_{
+				Service: &csi.PluginCapability_Service{
+					Type: csi.PluginCapability_Service_VOLUME_ACCESSIBILITY_CONSTRAINTS,
+				},
+			},
+		})
+	}
 
 	return resp, nil
 }
 
+// Probe reports the driver as not ready until the GIDs of the file systems of --warmup-filesystem-ids are warmed up,
+// so that the sidecars wait before making calls
 func (d *Driver) Probe(ctx context.Context, req *csi.ProbeRequest) (*csi.ProbeResponse, error) {
-	return &csi.ProbeResponse{}, nil
+	if d.warmingUp.Load() {
+		klog.V(4).Infof("Probe: GIDs are being warmed up")
+		return &csi.ProbeResponse{Ready: &wrappers.BoolValue{Value: false}}, nil
+	}
+	if d.awsProbeInterval > 0 {
+		if err := d.checkAwsConnectivity(ctx); err != nil {
+			return nil, status.Errorf(codes.FailedPrecondition, "EFS API is not reachable: %v", err)
+		}
+	}
+	return &csi.ProbeResponse{Ready: &wrappers.BoolValue{Value: true}}, nil
+}
+
+// checkAwsConnectivity calls the EFS API at most once per awsProbeInterval, and returns the result of the last call otherwise
+func (d *Driver) checkAwsConnectivity(ctx context.Context) error {
+	d.awsProbe.mu.Lock()
+	defer d.awsProbe.mu.Unlock()
+	if !d.awsProbe.lastCheck.IsZero() && d.currentTime().Sub(d.awsProbe.lastCheck) < d.awsProbeInterval {
+		return d.awsProbe.lastErr
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, awsProbeTimeout)
+	defer cancel()
+	err := d.cloud.CheckConnectivity(ctx)
+	if err != nil {
+		if cloud.IsAccessDenied(err) {
+			klog.Warningf("Probe: EFS API denied access, please check the AWS credentials and permissions of the driver: %v", err)
+		} else {
+			klog.Warningf("Probe: failed to reach EFS API: %v", err)
+		}
+	}
+	d.awsProbe.lastCheck = d.currentTime()
+	d.awsProbe.lastErr = err
+	return err
 }
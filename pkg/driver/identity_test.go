@@ -0,0 +1,157 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	"github.com/golang/mock/gomock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/kubernetes-sigs/aws-efs-csi-driver/pkg/cloud"
+	"github.com/kubernetes-sigs/aws-efs-csi-driver/pkg/driver/mocks"
+)
+
+func TestProbe(t *testing.T) {
+	testCases := []struct {
+		name     string
+		testFunc func(t *testing.T)
+	}{
+		{
+			name: "Success: AWS check disabled",
+			testFunc: func(t *testing.T) {
+				mockCtl := gomock.NewController(t)
+				mockCloud := mocks.NewMockCloud(mockCtl)
+				driver := &Driver{cloud: mockCloud}
+
+				res, err := driver.Probe(context.Background(), &csi.ProbeRequest{})
+				if err != nil {
+					t.Fatalf("Probe failed: %v", err)
+				}
+				if !res.GetReady().GetValue() {
+					t.Fatalf("Expected ready, got: %v", res.GetReady())
+				}
+				mockCtl.Finish()
+			},
+		},
+		{
+			name: "Success: Not ready until the GIDs are warmed up",
+			testFunc: func(t *testing.T) {
+				mockCtl := gomock.NewController(t)
+				mockCloud := mocks.NewMockCloud(mockCtl)
+				driver := &Driver{cloud: mockCloud, awsProbeInterval: time.Minute}
+				driver.warmingUp.Store(true)
+
+				// The EFS API is not checked before the driver is ready
+				res, err := driver.Probe(context.Background(), &csi.ProbeRequest{})
+				if err != nil {
+					t.Fatalf("Probe failed: %v", err)
+				}
+				if res.GetReady() == nil || res.GetReady().GetValue() {
+					t.Fatalf("Expected not ready, got: %v", res.GetReady())
+				}
+
+				driver.warmingUp.Store(false)
+				mockCloud.EXPECT().CheckConnectivity(gomock.Any()).Return(nil)
+				res, err = driver.Probe(context.Background(), &csi.ProbeRequest{})
+				if err != nil {
+					t.Fatalf("Probe failed: %v", err)
+				}
+				if !res.GetReady().GetValue() {
+					t.Fatalf("Expected ready, got: %v", res.GetReady())
+				}
+				mockCtl.Finish()
+			},
+		},
+		{
+			name: "Success: EFS API reachable",
+			testFunc: func(t *testing.T) {
+				mockCtl := gomock.NewController(t)
+				mockCloud := mocks.NewMockCloud(mockCtl)
+				driver := &Driver{cloud: mockCloud, awsProbeInterval: time.Minute}
+
+				mockCloud.EXPECT().CheckConnectivity(gomock.Any()).Return(nil)
+				if _, err := driver.Probe(context.Background(), &csi.ProbeRequest{}); err != nil {
+					t.Fatalf("Probe failed: %v", err)
+				}
+				mockCtl.Finish()
+			},
+		},
+		{
+			name: "Fail: Invalid credentials",
+			testFunc: func(t *testing.T) {
+				mockCtl := gomock.NewController(t)
+				mockCloud := mocks.NewMockCloud(mockCtl)
+				driver := &Driver{cloud: mockCloud, awsProbeInterval: time.Minute}
+
+				mockCloud.EXPECT().CheckConnectivity(gomock.Any()).Return(cloud.ErrAccessDenied)
+				_, err := driver.Probe(context.Background(), &csi.ProbeRequest{})
+				if status.Code(err) != codes.FailedPrecondition {
+					t.Fatalf("Expected FailedPrecondition, got: %v", err)
+				}
+				mockCtl.Finish()
+			},
+		},
+		{
+			name: "Fail: Result is reused within the interval",
+			testFunc: func(t *testing.T) {
+				mockCtl := gomock.NewController(t)
+				mockCloud := mocks.NewMockCloud(mockCtl)
+				driver := &Driver{cloud: mockCloud, awsProbeInterval: time.Minute}
+
+				mockCloud.EXPECT().CheckConnectivity(gomock.Any()).Return(errors.New("RequestError: send request failed")).Times(1)
+				for i := 0; i < 3; i++ {
+					_, err := driver.Probe(context.Background(), &csi.ProbeRequest{})
+					if status.Code(err) != codes.FailedPrecondition {
+						t.Fatalf("Expected FailedPrecondition, got: %v", err)
+					}
+				}
+				mockCtl.Finish()
+			},
+		},
+		{
+			name: "Success: EFS API checked again after the interval",
+			testFunc: func(t *testing.T) {
+				mockCtl := gomock.NewController(t)
+				mockCloud := mocks.NewMockCloud(mockCtl)
+				driver := &Driver{cloud: mockCloud, awsProbeInterval: time.Minute}
+
+				gomock.InOrder(
+					mockCloud.EXPECT().CheckConnectivity(gomock.Any()).Return(errors.New("RequestError: send request failed")),
+					mockCloud.EXPECT().CheckConnectivity(gomock.Any()).Return(nil),
+				)
+				if _, err := driver.Probe(context.Background(), &csi.ProbeRequest{}); err == nil {
+					t.Fatalf("Probe did not fail")
+				}
+				driver.awsProbe.lastCheck = time.Now().Add(-2 * time.Minute)
+				if _, err := driver.Probe(context.Background(), &csi.ProbeRequest{}); err != nil {
+					t.Fatalf("Probe failed: %v", err)
+				}
+				mockCtl.Finish()
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, tc.testFunc)
+	}
+}
@@ -0,0 +1,77 @@
+package driver
+
+import (
+	"context"
+	"sync"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	"github.com/golang/protobuf/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"k8s.io/klog/v2"
+)
+
+// inFlightCreate is a CreateVolume call in flight, whose result is shared with the identical calls made meanwhile
+type inFlightCreate struct {
+	req  *csi.CreateVolumeRequest
+	done chan struct{}
+	res  *csi.CreateVolumeResponse
+	err  error
+	// waiters is the number of identical calls which waited for the result
+	waiters int
+}
+
+// inFlightCreates deduplicates the concurrent CreateVolume calls of a volume, which the external-provisioner makes
+// when it retries a call before the first one completes, and which would otherwise race to create two access points.
+// The zero value is ready to use.
+type inFlightCreates struct {
+	mu    sync.Mutex
+	calls map[string]*inFlightCreate
+}
+
+// do runs create unless a call of the same volume is in flight, in which case it waits for its result. A call of the
+// same volume with other parameters fails with Aborted, to be retried once the call in flight completed.
+func (c *inFlightCreates) do(ctx context.Context, req *csi.CreateVolumeRequest, create func() (*csi.CreateVolumeResponse, error)) (*csi.CreateVolumeResponse, error) {
+	name := req.GetName()
+	c.mu.Lock()
+	if call, ok := c.calls[name]; ok {
+		if !proto.Equal(call.req, req) {
+			c.mu.Unlock()
+			return nil, status.Errorf(codes.Aborted, "CreateVolume of volume %v with other parameters is already in progress", name)
+		}
+		call.waiters++
+		c.mu.Unlock()
+		klog.V(4).Infof("CreateVolume of volume %v is already in progress, waiting for its result", name)
+		select {
+		case <-call.done:
+		case <-ctx.Done():
+			return nil, status.FromContextError(ctx.Err()).Err()
+		}
+		if call.err != nil {
+			return nil, call.err
+		}
+		return proto.Clone(call.res).(*csi.CreateVolumeResponse), nil
+	}
+	if c.calls == nil {
+		c.calls = map[string]*inFlightCreate{}
+	}
+	call := &inFlightCreate{req: req, done: make(chan struct{})}
+	c.calls[name] = call
+	c.mu.Unlock()
+
+	defer func() {
+		c.mu.Lock()
+		delete(c.calls, name)
+		waiters := call.waiters
+		c.mu.Unlock()
+		close(call.done)
+		if waiters > 0 {
+			klog.V(4).Infof("CreateVolume of volume %v shared its result with %v identical calls", name, waiters)
+		}
+	}()
+	call.res, call.err = create()
+	if call.err != nil {
+		return nil, call.err
+	}
+	return proto.Clone(call.res).(*csi.CreateVolumeResponse), nil
+}
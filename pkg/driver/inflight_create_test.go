@@ -0,0 +1,129 @@
+package driver
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	"github.com/golang/mock/gomock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/kubernetes-sigs/aws-efs-csi-driver/pkg/cloud"
+	"github.com/kubernetes-sigs/aws-efs-csi-driver/pkg/driver/mocks"
+)
+
+func TestCreateVolumeConcurrentRetries(t *testing.T) {
+	var (
+		fsId = "fs-abcd1234"
+		apId = "fsap-abcd1234xyz987"
+	)
+	createRequest := func(name, directoryPerms string) *csi.CreateVolumeRequest {
+		return &csi.CreateVolumeRequest{
+			Name: name,
+			VolumeCapabilities: []*csi.VolumeCapability{
+				{
+					AccessType: &csi.VolumeCapability_Mount{
+						Mount: &csi.VolumeCapability_MountVolume{},
+					},
+					AccessMode: &csi.VolumeCapability_AccessMode{
+						Mode: csi.VolumeCapability_AccessMode_MULTI_NODE_MULTI_WRITER,
+					},
+				},
+			},
+			CapacityRange: &csi.CapacityRange{RequiredBytes: 5 * 1024 * 1024 * 1024},
+			Parameters: map[string]string{
+				ProvisioningMode: AccessPointMode,
+				FsId:             fsId,
+				DirectoryPerms:   directoryPerms,
+				Uid:              "1000",
+				Gid:              "1000",
+			},
+		}
+	}
+
+	mockCtl := gomock.NewController(t)
+	mockCloud := mocks.NewMockCloud(mockCtl)
+	driver := &Driver{
+		cloud:        mockCloud,
+		gidAllocator: NewGidAllocator(),
+	}
+
+	// The first call is held in CreateAccessPoint while the retries are made
+	created := make(chan struct{})
+	release := make(chan struct{})
+	mockCloud.EXPECT().DescribeFileSystem(gomock.Any(), gomock.Eq(fsId)).Return(&cloud.FileSystem{FileSystemId: fsId}, nil)
+	mockCloud.EXPECT().CreateAccessPoint(gomock.Any(), gomock.Eq("pvc-1"), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, clientToken string, accessPointOpts *cloud.AccessPointOptions, reuseAccessPoint bool) (*cloud.AccessPoint, error) {
+			close(created)
+			<-release
+			return &cloud.AccessPoint{AccessPointId: apId, FileSystemId: fsId}, nil
+		}).Times(1)
+
+	const retries = 5
+	var wg sync.WaitGroup
+	results := make([]*csi.CreateVolumeResponse, retries+1)
+	errs := make([]error, retries+1)
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		results[0], errs[0] = driver.CreateVolume(context.Background(), createRequest("pvc-1", "700"))
+	}()
+	<-created
+	for i := 1; i <= retries; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i], errs[i] = driver.CreateVolume(context.Background(), createRequest("pvc-1", "700"))
+		}(i)
+	}
+
+	// A call of the same volume with other parameters is not given the result of the call in flight
+	if _, err := driver.CreateVolume(context.Background(), createRequest("pvc-1", "755")); status.Code(err) != codes.Aborted {
+		t.Fatalf("Expected an Aborted error, got: %v", err)
+	}
+	// A retry gives up once its own context is done
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := driver.CreateVolume(ctx, createRequest("pvc-1", "700")); status.Code(err) != codes.DeadlineExceeded {
+		t.Fatalf("Expected a DeadlineExceeded error, got: %v", err)
+	}
+
+	// Calls of other volumes are not deduplicated
+	mockCloud.EXPECT().DescribeFileSystem(gomock.Any(), gomock.Eq(fsId)).Return(&cloud.FileSystem{FileSystemId: fsId}, nil)
+	mockCloud.EXPECT().CreateAccessPoint(gomock.Any(), gomock.Eq("pvc-2"), gomock.Any(), gomock.Any()).Return(&cloud.AccessPoint{AccessPointId: "fsap-abcd5678xyz987", FileSystemId: fsId}, nil)
+	if _, err := driver.CreateVolume(context.Background(), createRequest("pvc-2", "700")); err != nil {
+		t.Fatalf("CreateVolume of another volume failed: %v", err)
+	}
+
+	// The retries, and the one which gave up, wait for the call in flight
+	for waiters := 0; waiters < retries+1; {
+		time.Sleep(time.Millisecond)
+		driver.inFlightCreates.mu.Lock()
+		waiters = driver.inFlightCreates.calls["pvc-1"].waiters
+		driver.inFlightCreates.mu.Unlock()
+	}
+	close(release)
+	wg.Wait()
+	for i := range results {
+		if errs[i] != nil {
+			t.Fatalf("CreateVolume %v failed: %v", i, errs[i])
+		}
+		if results[i].Volume.VolumeId != fsId+"::"+apId {
+			t.Fatalf("Volume %v mismatched. Expected: %v, Actual: %v", i, fsId+"::"+apId, results[i].Volume.VolumeId)
+		}
+	}
+
+	// The volume is released once the call completed, later calls are not deduplicated
+	mockCloud.EXPECT().DescribeFileSystem(gomock.Any(), gomock.Eq(fsId)).Return(&cloud.FileSystem{FileSystemId: fsId}, nil)
+	mockCloud.EXPECT().CreateAccessPoint(gomock.Any(), gomock.Eq("pvc-1"), gomock.Any(), gomock.Any()).Return(&cloud.AccessPoint{AccessPointId: apId, FileSystemId: fsId}, nil)
+	if _, err := driver.CreateVolume(context.Background(), createRequest("pvc-1", "700")); err != nil {
+		t.Fatalf("CreateVolume after the call in flight failed: %v", err)
+	}
+	if len(driver.inFlightCreates.calls) != 0 {
+		t.Fatalf("Calls in flight were not released: %v", driver.inFlightCreates.calls)
+	}
+	mockCtl.Finish()
+}
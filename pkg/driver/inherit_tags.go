@@ -0,0 +1,46 @@
+package driver
+
+import (
+	"strings"
+)
+
+// driverTagKeyPrefix is the prefix of the tag keys managed by the driver, which are never inherited
+const driverTagKeyPrefix = "efs.csi.aws.com/"
+
+// ParseInheritedTagKeys parses the comma separated list of the tag keys inherited from file systems
+func ParseInheritedTagKeys(value string) []string {
+	keys := []string{}
+	for _, key := range strings.Split(value, ",") {
+		key = strings.TrimSpace(key)
+		if key != "" {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// inheritFileSystemTags adds the tags of the file system to the tags of an access point, restricted to the keys of the
+// allow-list when it is not empty. Tags already set, e.g. by --tags, take precedence. The tags reserved by AWS, such as
+// aws:elasticfilesystem:default-backup, and the tags of the driver are not inherited.
+func inheritFileSystemTags(tags, fileSystemTags map[string]string, allowedKeys []string) {
+	for key, value := range fileSystemTags {
+		if strings.HasPrefix(strings.ToLower(key), reservedTagKeyPrefix) || strings.HasPrefix(key, driverTagKeyPrefix) {
+			continue
+		}
+		if len(allowedKeys) > 0 && !isAllowedTagKey(allowedKeys, key) {
+			continue
+		}
+		if _, ok := tags[key]; !ok {
+			tags[key] = value
+		}
+	}
+}
+
+func isAllowedTagKey(allowedKeys []string, key string) bool {
+	for _, allowedKey := range allowedKeys {
+		if key == allowedKey {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,161 @@
+package driver
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	"github.com/golang/mock/gomock"
+
+	"github.com/kubernetes-sigs/aws-efs-csi-driver/pkg/cloud"
+	"github.com/kubernetes-sigs/aws-efs-csi-driver/pkg/driver/mocks"
+)
+
+func TestInheritFileSystemTags(t *testing.T) {
+	fileSystemTags := map[string]string{
+		"cost-center":                          "42",
+		"environment":                          "prod",
+		"aws:elasticfilesystem:default-backup": "enabled",
+		DefaultTagKey:                          DefaultTagValue,
+		ClusterNameTagKey:                      "other-cluster",
+	}
+
+	testCases := []struct {
+		name        string
+		tags        map[string]string
+		allowedKeys []string
+		expected    map[string]string
+	}{
+		{
+			name:     "Success: Every tag is inherited",
+			tags:     map[string]string{},
+			expected: map[string]string{"cost-center": "42", "environment": "prod"},
+		},
+		{
+			name:        "Success: Only the allowed keys are inherited",
+			tags:        map[string]string{},
+			allowedKeys: []string{"cost-center", "aws:elasticfilesystem:default-backup"},
+			expected:    map[string]string{"cost-center": "42"},
+		},
+		{
+			name:     "Success: Tags of the driver take precedence",
+			tags:     map[string]string{"environment": "dev", ClusterNameTagKey: "cluster"},
+			expected: map[string]string{"cost-center": "42", "environment": "dev", ClusterNameTagKey: "cluster"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			inheritFileSystemTags(tc.tags, fileSystemTags, tc.allowedKeys)
+			if !reflect.DeepEqual(tc.tags, tc.expected) {
+				t.Fatalf("Tags mismatched. Expected: %v, Actual: %v", tc.expected, tc.tags)
+			}
+		})
+	}
+}
+
+func TestCreateVolumeInheritFsTags(t *testing.T) {
+	var (
+		fsId      = "fs-abcd1234"
+		apId      = "fsap-abcd1234xyz987"
+		stdVolCap = &csi.VolumeCapability{
+			AccessType: &csi.VolumeCapability_Mount{
+				Mount: &csi.VolumeCapability_MountVolume{},
+			},
+			AccessMode: &csi.VolumeCapability_AccessMode{
+				Mode: csi.VolumeCapability_AccessMode_MULTI_NODE_MULTI_WRITER,
+			},
+		}
+		fileSystem = &cloud.FileSystem{
+			FileSystemId: fsId,
+			Tags:         map[string]string{"cost-center": "42", "environment": "prod", "team": "storage"},
+		}
+	)
+
+	testCases := []struct {
+		name             string
+		inheritFsTags    bool
+		inheritFsTagKeys []string
+		params           map[string]string
+		expectedTags     map[string]string
+	}{
+		{
+			name:          "Success: Tags of the file system are inherited",
+			inheritFsTags: true,
+			expectedTags: map[string]string{
+				DefaultTagKey: DefaultTagValue, RequestedBytesTagKey: "5368709120", "cost-center": "42", "environment": "dev", "team": "storage",
+			},
+		},
+		{
+			name:             "Success: Only the allowed tags of the file system are inherited",
+			inheritFsTags:    true,
+			inheritFsTagKeys: []string{"cost-center"},
+			expectedTags: map[string]string{
+				DefaultTagKey: DefaultTagValue, RequestedBytesTagKey: "5368709120", "cost-center": "42", "environment": "dev",
+			},
+		},
+		{
+			name:          "Success: File system described once with a static GID",
+			inheritFsTags: true,
+			params:        map[string]string{Uid: "1000", Gid: "1000"},
+			expectedTags: map[string]string{
+				DefaultTagKey: DefaultTagValue, RequestedBytesTagKey: "5368709120", "cost-center": "42", "environment": "dev", "team": "storage",
+			},
+		},
+		{
+			name: "Success: Tags are not inherited by default",
+			expectedTags: map[string]string{
+				DefaultTagKey: DefaultTagValue, RequestedBytesTagKey: "5368709120", "environment": "dev",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockCtl := gomock.NewController(t)
+			mockCloud := mocks.NewMockCloud(mockCtl)
+			driver := &Driver{
+				cloud:            mockCloud,
+				gidAllocator:     NewGidAllocator(),
+				tags:             map[string]string{"environment": "dev"},
+				inheritFsTags:    tc.inheritFsTags,
+				inheritFsTagKeys: tc.inheritFsTagKeys,
+			}
+
+			params := map[string]string{
+				ProvisioningMode: AccessPointMode,
+				FsId:             fsId,
+				DirectoryPerms:   "700",
+			}
+			for k, v := range tc.params {
+				params[k] = v
+			}
+			if _, ok := tc.params[Uid]; ok {
+				mockCloud.EXPECT().DescribeFileSystem(gomock.Any(), gomock.Eq(fsId)).Return(fileSystem, nil)
+			} else {
+				mockCloud.EXPECT().ListAccessPoints(gomock.Any(), gomock.Eq(fsId)).Return(nil, nil)
+				if tc.inheritFsTags {
+					mockCloud.EXPECT().DescribeFileSystem(gomock.Any(), gomock.Eq(fsId)).Return(fileSystem, nil)
+				}
+			}
+			mockCloud.EXPECT().CreateAccessPoint(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+				func(ctx context.Context, clientToken string, accessPointOpts *cloud.AccessPointOptions, reuseAccessPoint bool) (*cloud.AccessPoint, error) {
+					if !reflect.DeepEqual(accessPointOpts.Tags, tc.expectedTags) {
+						t.Errorf("Tags mismatched. Expected: %v, Actual: %v", tc.expectedTags, accessPointOpts.Tags)
+					}
+					return &cloud.AccessPoint{AccessPointId: apId, FileSystemId: fsId}, nil
+				})
+
+			_, err := driver.CreateVolume(context.Background(), &csi.CreateVolumeRequest{
+				Name:               "name",
+				VolumeCapabilities: []*csi.VolumeCapability{stdVolCap},
+				CapacityRange:      &csi.CapacityRange{RequiredBytes: 5 * 1024 * 1024 * 1024},
+				Parameters:         params,
+			})
+			if err != nil {
+				t.Fatalf("CreateVolume failed: %v", err)
+			}
+		})
+	}
+}
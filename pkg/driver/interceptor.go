@@ -0,0 +1,55 @@
+package driver
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+	"k8s.io/klog/v2"
+)
+
+// grpcBodyLogLevel is the verbosity at which the requests and responses of every call are logged
+const grpcBodyLogLevel = 5
+
+// logGRPC is a unary server interceptor logging the method, duration and status code of every call,
+// and the volume ID of the request when it has one. Errors are always logged.
+func logGRPC(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if klogV := klog.V(grpcBodyLogLevel); klogV.Enabled() {
+		klogV.InfoS("GRPC request", "method", info.FullMethod, "request", grpcRequestBody(req))
+	}
+
+	start := time.Now()
+	resp, err := handler(ctx, req)
+
+	keysAndValues := []interface{}{"method", info.FullMethod, "duration", time.Since(start), "code", status.Code(err)}
+	if volumeId := grpcVolumeId(req); volumeId != "" {
+		keysAndValues = append(keysAndValues, "volumeID", volumeId)
+	}
+	if err != nil {
+		klog.ErrorS(err, "GRPC error", keysAndValues...)
+		return resp, err
+	}
+	klog.V(4).InfoS("GRPC call", keysAndValues...)
+	if klogV := klog.V(grpcBodyLogLevel); klogV.Enabled() {
+		klogV.InfoS("GRPC response", "method", info.FullMethod, "response", fmt.Sprintf("%+v", resp))
+	}
+	return resp, err
+}
+
+// grpcVolumeId returns the volume ID of a request, or an empty string if it has none
+func grpcVolumeId(req interface{}) string {
+	if r, ok := req.(interface{ GetVolumeId() string }); ok {
+		return r.GetVolumeId()
+	}
+	return ""
+}
+
+// grpcRequestBody formats a request for logging. Requests carrying secrets are not logged.
+func grpcRequestBody(req interface{}) string {
+	if r, ok := req.(interface{ GetSecrets() map[string]string }); ok && len(r.GetSecrets()) > 0 {
+		return "<omitted, the request contains secrets>"
+	}
+	return fmt.Sprintf("%+v", req)
+}
@@ -0,0 +1,108 @@
+package driver
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"strings"
+	"testing"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"k8s.io/klog/v2"
+)
+
+func TestLogGRPC(t *testing.T) {
+	var (
+		volumeId = "fs-abcd1234::fsap-abcd1234xyz987"
+		info     = &grpc.UnaryServerInfo{FullMethod: "/csi.v1.Controller/DeleteVolume"}
+	)
+
+	// Capture the logs at the verbosity of the request and response bodies
+	fs := flag.NewFlagSet("klog", flag.ContinueOnError)
+	klog.InitFlags(fs)
+	var buf bytes.Buffer
+	fs.Set("logtostderr", "false")
+	fs.Set("v", "5")
+	klog.SetOutput(&buf)
+	defer func() {
+		fs.Set("v", "0")
+		fs.Set("logtostderr", "true")
+		klog.SetOutput(nil)
+	}()
+
+	testCases := []struct {
+		name        string
+		req         interface{}
+		resp        interface{}
+		err         error
+		expectLogs  []string
+		absentLogs  []string
+		expectError codes.Code
+	}{
+		{
+			name:       "Success: Method, code and volume ID are logged",
+			req:        &csi.DeleteVolumeRequest{VolumeId: volumeId},
+			resp:       &csi.DeleteVolumeResponse{},
+			expectLogs: []string{`"GRPC call"`, `method="/csi.v1.Controller/DeleteVolume"`, `duration=`, `code="OK"`, `volumeID="` + volumeId + `"`, `"GRPC request"`, `"GRPC response"`},
+		},
+		{
+			name:        "Fail: Errors are logged with their code",
+			req:         &csi.DeleteVolumeRequest{VolumeId: volumeId},
+			err:         status.Error(codes.Internal, "DeleteAccessPoint failed"),
+			expectLogs:  []string{`"GRPC error"`, `code="Internal"`, `err="rpc error: code = Internal desc = DeleteAccessPoint failed"`},
+			absentLogs:  []string{`"GRPC response"`},
+			expectError: codes.Internal,
+		},
+		{
+			name:       "Success: Requests with secrets are not logged",
+			req:        &csi.DeleteVolumeRequest{VolumeId: volumeId, Secrets: map[string]string{"awsRoleArn": "secret-role"}},
+			resp:       &csi.DeleteVolumeResponse{},
+			expectLogs: []string{"omitted, the request contains secrets"},
+			absentLogs: []string{"secret-role"},
+		},
+		{
+			name:       "Success: Requests without volume ID",
+			req:        &csi.GetPluginInfoRequest{},
+			resp:       &csi.GetPluginInfoResponse{Name: driverName},
+			expectLogs: []string{`"GRPC call"`, `code="OK"`},
+			absentLogs: []string{"volumeID"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			buf.Reset()
+			called := false
+			handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+				called = true
+				return tc.resp, tc.err
+			}
+
+			resp, err := logGRPC(context.Background(), tc.req, info, handler)
+			klog.Flush()
+			if !called {
+				t.Fatal("Handler was not called")
+			}
+			if status.Code(err) != tc.expectError {
+				t.Fatalf("Expected code %v, got: %v", tc.expectError, err)
+			}
+			if resp != tc.resp {
+				t.Fatalf("Response mismatched. Expected: %v, Actual: %v", tc.resp, resp)
+			}
+			logs := buf.String()
+			for _, s := range tc.expectLogs {
+				if !strings.Contains(logs, s) {
+					t.Errorf("Expected %s in logs: %s", s, logs)
+				}
+			}
+			for _, s := range tc.absentLogs {
+				if strings.Contains(logs, s) {
+					t.Errorf("Unexpected %s in logs: %s", s, logs)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,186 @@
+package driver
+
+import (
+	"context"
+	"testing"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/kubernetes-sigs/aws-efs-csi-driver/pkg/cloud"
+	"github.com/kubernetes-sigs/aws-efs-csi-driver/pkg/cloud/fake"
+)
+
+func TestVolumeLifecycle(t *testing.T) {
+	var (
+		fsId      = "fs-abcd1234"
+		stdVolCap = &csi.VolumeCapability{
+			AccessType: &csi.VolumeCapability_Mount{
+				Mount: &csi.VolumeCapability_MountVolume{},
+			},
+			AccessMode: &csi.VolumeCapability_AccessMode{
+				Mode: csi.VolumeCapability_AccessMode_MULTI_NODE_MULTI_WRITER,
+			},
+		}
+	)
+
+	createRequest := func(name string, params map[string]string) *csi.CreateVolumeRequest {
+		return &csi.CreateVolumeRequest{
+			Name:               name,
+			VolumeCapabilities: []*csi.VolumeCapability{stdVolCap},
+			CapacityRange:      &csi.CapacityRange{RequiredBytes: 5 * 1024 * 1024 * 1024},
+			Parameters:         params,
+		}
+	}
+
+	testCases := []struct {
+		name     string
+		testFunc func(t *testing.T)
+	}{
+		{
+			name: "Success: Access point volumes are created and deleted",
+			testFunc: func(t *testing.T) {
+				fakeCloud := fake.NewCloud()
+				fakeCloud.AddFileSystem(fsId)
+				driver := &Driver{
+					cloud:        fakeCloud,
+					gidAllocator: NewGidAllocator(),
+				}
+
+				ctx := context.Background()
+				params := map[string]string{
+					ProvisioningMode: AccessPointMode,
+					FsId:             fsId,
+					DirectoryPerms:   "700",
+				}
+				res1, err := driver.CreateVolume(ctx, createRequest("pvc-1", params))
+				if err != nil {
+					t.Fatalf("CreateVolume failed: %v", err)
+				}
+				res2, err := driver.CreateVolume(ctx, createRequest("pvc-2", params))
+				if err != nil {
+					t.Fatalf("CreateVolume failed: %v", err)
+				}
+
+				accessPoints, err := fakeCloud.ListAccessPoints(ctx, fsId)
+				if err != nil {
+					t.Fatalf("ListAccessPoints failed: %v", err)
+				}
+				if len(accessPoints) != 2 {
+					t.Fatalf("Expected 2 access points, got %v", len(accessPoints))
+				}
+				if gid1, gid2 := accessPoints[0].PosixUser.Gid, accessPoints[1].PosixUser.Gid; gid1 != DefaultGidMin || gid2 != DefaultGidMin+1 {
+					t.Fatalf("Expected GIDs %v and %v, got %v and %v", DefaultGidMin, DefaultGidMin+1, gid1, gid2)
+				}
+				if rootDir := accessPoints[0].AccessPointRootDir; rootDir != "/pvc-1" {
+					t.Fatalf("Root directory mismatched. Expected: %v, Actual: %v", "/pvc-1", rootDir)
+				}
+
+				// A retry of the provisioner returns the same volume
+				retry, err := driver.CreateVolume(ctx, createRequest("pvc-1", params))
+				if err != nil {
+					t.Fatalf("CreateVolume retry failed: %v", err)
+				}
+				if retry.Volume.VolumeId != res1.Volume.VolumeId {
+					t.Fatalf("Volume Id mismatched. Expected: %v, Actual: %v", res1.Volume.VolumeId, retry.Volume.VolumeId)
+				}
+
+				_, err = driver.DeleteVolume(ctx, &csi.DeleteVolumeRequest{VolumeId: res1.Volume.VolumeId})
+				if err != nil {
+					t.Fatalf("DeleteVolume failed: %v", err)
+				}
+				// Deleting a volume which no longer exists succeeds
+				_, err = driver.DeleteVolume(ctx, &csi.DeleteVolumeRequest{VolumeId: res1.Volume.VolumeId})
+				if err != nil {
+					t.Fatalf("DeleteVolume of a deleted volume failed: %v", err)
+				}
+
+				// The GID of the deleted access point is reused
+				res3, err := driver.CreateVolume(ctx, createRequest("pvc-3", params))
+				if err != nil {
+					t.Fatalf("CreateVolume failed: %v", err)
+				}
+				_, _, apId, err := parseVolumeId(res3.Volume.VolumeId)
+				if err != nil {
+					t.Fatalf("parseVolumeId failed: %v", err)
+				}
+				accessPoint, err := fakeCloud.DescribeAccessPoint(ctx, apId)
+				if err != nil {
+					t.Fatalf("DescribeAccessPoint failed: %v", err)
+				}
+				if accessPoint.PosixUser.Gid != DefaultGidMin {
+					t.Fatalf("Expected GID %v to be reused, got %v", DefaultGidMin, accessPoint.PosixUser.Gid)
+				}
+
+				for _, volumeId := range []string{res2.Volume.VolumeId, res3.Volume.VolumeId} {
+					if _, err := driver.DeleteVolume(ctx, &csi.DeleteVolumeRequest{VolumeId: volumeId}); err != nil {
+						t.Fatalf("DeleteVolume failed: %v", err)
+					}
+				}
+				accessPoints, err = fakeCloud.ListAccessPoints(ctx, fsId)
+				if err != nil {
+					t.Fatalf("ListAccessPoints failed: %v", err)
+				}
+				if len(accessPoints) != 0 {
+					t.Fatalf("Expected no access point left, got %v", len(accessPoints))
+				}
+			},
+		},
+		{
+			name: "Success: File system volumes are created and deleted",
+			testFunc: func(t *testing.T) {
+				fakeCloud := fake.NewCloud()
+				driver := &Driver{
+					cloud:        fakeCloud,
+					gidAllocator: NewGidAllocator(),
+				}
+
+				ctx := context.Background()
+				res, err := driver.CreateVolume(ctx, createRequest("pvc-1", map[string]string{
+					ProvisioningMode: FileSystemMode,
+				}))
+				if err != nil {
+					t.Fatalf("CreateVolume failed: %v", err)
+				}
+				fileSystem, err := fakeCloud.DescribeFileSystem(ctx, res.Volume.VolumeId)
+				if err != nil {
+					t.Fatalf("DescribeFileSystem failed: %v", err)
+				}
+				if fileSystem.Tags[DefaultTagKey] != DefaultTagValue {
+					t.Fatalf("File system is missing the %v tag: %v", DefaultTagKey, fileSystem.Tags)
+				}
+
+				_, err = driver.DeleteVolume(ctx, &csi.DeleteVolumeRequest{VolumeId: res.Volume.VolumeId})
+				if err != nil {
+					t.Fatalf("DeleteVolume failed: %v", err)
+				}
+				if _, err := fakeCloud.DescribeFileSystem(ctx, res.Volume.VolumeId); err != cloud.ErrNotFound {
+					t.Fatalf("Expected the file system to be deleted, got: %v", err)
+				}
+			},
+		},
+		{
+			name: "Fail: File system does not exist",
+			testFunc: func(t *testing.T) {
+				driver := &Driver{
+					cloud:        fake.NewCloud(),
+					gidAllocator: NewGidAllocator(),
+				}
+
+				_, err := driver.CreateVolume(context.Background(), createRequest("pvc-1", map[string]string{
+					ProvisioningMode: AccessPointMode,
+					FsId:             fsId,
+					DirectoryPerms:   "700",
+				}))
+				if status.Code(err) != codes.InvalidArgument {
+					t.Fatalf("Expected an InvalidArgument error, got: %v", err)
+				}
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, tc.testFunc)
+	}
+}
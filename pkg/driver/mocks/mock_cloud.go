@@ -58,6 +58,26 @@ func (mr *MockEfsMockRecorder) CreateAccessPointWithContext(arg0, arg1 interface
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAccessPointWithContext", reflect.TypeOf((*MockEfs)(nil).CreateAccessPointWithContext), varargs...)
 }
 
+// CreateFileSystemWithContext mocks base method.
+func (m *MockEfs) CreateFileSystemWithContext(arg0 aws.Context, arg1 *efs.CreateFileSystemInput, arg2 ...request.Option) (*efs.FileSystemDescription, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "CreateFileSystemWithContext", varargs...)
+	ret0, _ := ret[0].(*efs.FileSystemDescription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateFileSystemWithContext indicates an expected call of CreateFileSystemWithContext.
+func (mr *MockEfsMockRecorder) CreateFileSystemWithContext(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateFileSystemWithContext", reflect.TypeOf((*MockEfs)(nil).CreateFileSystemWithContext), varargs...)
+}
+
 // DeleteAccessPointWithContext mocks base method.
 func (m *MockEfs) DeleteAccessPointWithContext(arg0 aws.Context, arg1 *efs.DeleteAccessPointInput, arg2 ...request.Option) (*efs.DeleteAccessPointOutput, error) {
 	m.ctrl.T.Helper()
@@ -78,6 +98,26 @@ func (mr *MockEfsMockRecorder) DeleteAccessPointWithContext(arg0, arg1 interface
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAccessPointWithContext", reflect.TypeOf((*MockEfs)(nil).DeleteAccessPointWithContext), varargs...)
 }
 
+// DeleteFileSystemWithContext mocks base method.
+func (m *MockEfs) DeleteFileSystemWithContext(arg0 aws.Context, arg1 *efs.DeleteFileSystemInput, arg2 ...request.Option) (*efs.DeleteFileSystemOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DeleteFileSystemWithContext", varargs...)
+	ret0, _ := ret[0].(*efs.DeleteFileSystemOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteFileSystemWithContext indicates an expected call of DeleteFileSystemWithContext.
+func (mr *MockEfsMockRecorder) DeleteFileSystemWithContext(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteFileSystemWithContext", reflect.TypeOf((*MockEfs)(nil).DeleteFileSystemWithContext), varargs...)
+}
+
 // DescribeAccessPointsWithContext mocks base method.
 func (m *MockEfs) DescribeAccessPointsWithContext(arg0 aws.Context, arg1 *efs.DescribeAccessPointsInput, arg2 ...request.Option) (*efs.DescribeAccessPointsOutput, error) {
 	m.ctrl.T.Helper()
@@ -138,6 +178,66 @@ func (mr *MockEfsMockRecorder) DescribeMountTargetsWithContext(arg0, arg1 interf
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeMountTargetsWithContext", reflect.TypeOf((*MockEfs)(nil).DescribeMountTargetsWithContext), varargs...)
 }
 
+// DescribeReplicationConfigurationsWithContext mocks base method.
+func (m *MockEfs) DescribeReplicationConfigurationsWithContext(arg0 aws.Context, arg1 *efs.DescribeReplicationConfigurationsInput, arg2 ...request.Option) (*efs.DescribeReplicationConfigurationsOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DescribeReplicationConfigurationsWithContext", varargs...)
+	ret0, _ := ret[0].(*efs.DescribeReplicationConfigurationsOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DescribeReplicationConfigurationsWithContext indicates an expected call of DescribeReplicationConfigurationsWithContext.
+func (mr *MockEfsMockRecorder) DescribeReplicationConfigurationsWithContext(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeReplicationConfigurationsWithContext", reflect.TypeOf((*MockEfs)(nil).DescribeReplicationConfigurationsWithContext), varargs...)
+}
+
+// TagResourceWithContext mocks base method.
+func (m *MockEfs) TagResourceWithContext(arg0 aws.Context, arg1 *efs.TagResourceInput, arg2 ...request.Option) (*efs.TagResourceOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "TagResourceWithContext", varargs...)
+	ret0, _ := ret[0].(*efs.TagResourceOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TagResourceWithContext indicates an expected call of TagResourceWithContext.
+func (mr *MockEfsMockRecorder) TagResourceWithContext(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TagResourceWithContext", reflect.TypeOf((*MockEfs)(nil).TagResourceWithContext), varargs...)
+}
+
+// UntagResourceWithContext mocks base method.
+func (m *MockEfs) UntagResourceWithContext(arg0 aws.Context, arg1 *efs.UntagResourceInput, arg2 ...request.Option) (*efs.UntagResourceOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "UntagResourceWithContext", varargs...)
+	ret0, _ := ret[0].(*efs.UntagResourceOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UntagResourceWithContext indicates an expected call of UntagResourceWithContext.
+func (mr *MockEfsMockRecorder) UntagResourceWithContext(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UntagResourceWithContext", reflect.TypeOf((*MockEfs)(nil).UntagResourceWithContext), varargs...)
+}
+
 // MockCloud is a mock of Cloud interface.
 type MockCloud struct {
 	ctrl     *gomock.Controller
@@ -161,6 +261,20 @@ func (m *MockCloud) EXPECT() *MockCloudMockRecorder {
 	return m.recorder
 }
 
+// CheckConnectivity mocks base method.
+func (m *MockCloud) CheckConnectivity(arg0 context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CheckConnectivity", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CheckConnectivity indicates an expected call of CheckConnectivity.
+func (mr *MockCloudMockRecorder) CheckConnectivity(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckConnectivity", reflect.TypeOf((*MockCloud)(nil).CheckConnectivity), arg0)
+}
+
 // CreateAccessPoint mocks base method.
 func (m *MockCloud) CreateAccessPoint(ctx context.Context, clientToken string, accessPointOpts *cloud.AccessPointOptions, usePvcName bool) (*cloud.AccessPoint, error) {
 	m.ctrl.T.Helper()
@@ -176,6 +290,21 @@ func (mr *MockCloudMockRecorder) CreateAccessPoint(ctx, clientToken, accessPoint
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAccessPoint", reflect.TypeOf((*MockCloud)(nil).CreateAccessPoint), ctx, clientToken, accessPointOpts, usePvcName)
 }
 
+// CreateFileSystem mocks base method.
+func (m *MockCloud) CreateFileSystem(ctx context.Context, clientToken string, fileSystemOpts *cloud.FileSystemOptions) (*cloud.FileSystem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateFileSystem", ctx, clientToken, fileSystemOpts)
+	ret0, _ := ret[0].(*cloud.FileSystem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateFileSystem indicates an expected call of CreateFileSystem.
+func (mr *MockCloudMockRecorder) CreateFileSystem(ctx, clientToken, fileSystemOpts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateFileSystem", reflect.TypeOf((*MockCloud)(nil).CreateFileSystem), ctx, clientToken, fileSystemOpts)
+}
+
 // DeleteAccessPoint mocks base method.
 func (m *MockCloud) DeleteAccessPoint(ctx context.Context, accessPointId string) error {
 	m.ctrl.T.Helper()
@@ -190,6 +319,20 @@ func (mr *MockCloudMockRecorder) DeleteAccessPoint(ctx, accessPointId interface{
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAccessPoint", reflect.TypeOf((*MockCloud)(nil).DeleteAccessPoint), ctx, accessPointId)
 }
 
+// DeleteFileSystem mocks base method.
+func (m *MockCloud) DeleteFileSystem(ctx context.Context, fileSystemId string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteFileSystem", ctx, fileSystemId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteFileSystem indicates an expected call of DeleteFileSystem.
+func (mr *MockCloudMockRecorder) DeleteFileSystem(ctx, fileSystemId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteFileSystem", reflect.TypeOf((*MockCloud)(nil).DeleteFileSystem), ctx, fileSystemId)
+}
+
 // DescribeAccessPoint mocks base method.
 func (m *MockCloud) DescribeAccessPoint(ctx context.Context, accessPointId string) (*cloud.AccessPoint, error) {
 	m.ctrl.T.Helper()
@@ -235,6 +378,36 @@ func (mr *MockCloudMockRecorder) DescribeMountTargets(ctx, fileSystemId, az inte
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeMountTargets", reflect.TypeOf((*MockCloud)(nil).DescribeMountTargets), ctx, fileSystemId, az)
 }
 
+// DescribeReplicationConfiguration mocks base method.
+func (m *MockCloud) DescribeReplicationConfiguration(ctx context.Context, fileSystemId string) (*cloud.ReplicationConfiguration, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DescribeReplicationConfiguration", ctx, fileSystemId)
+	ret0, _ := ret[0].(*cloud.ReplicationConfiguration)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DescribeReplicationConfiguration indicates an expected call of DescribeReplicationConfiguration.
+func (mr *MockCloudMockRecorder) DescribeReplicationConfiguration(ctx, fileSystemId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeReplicationConfiguration", reflect.TypeOf((*MockCloud)(nil).DescribeReplicationConfiguration), ctx, fileSystemId)
+}
+
+// FindAccessPointByClientToken mocks base method.
+func (m *MockCloud) FindAccessPointByClientToken(ctx context.Context, clientToken string, accessPointOpts *cloud.AccessPointOptions) (*cloud.AccessPoint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindAccessPointByClientToken", ctx, clientToken, accessPointOpts)
+	ret0, _ := ret[0].(*cloud.AccessPoint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindAccessPointByClientToken indicates an expected call of FindAccessPointByClientToken.
+func (mr *MockCloudMockRecorder) FindAccessPointByClientToken(ctx, clientToken, accessPointOpts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAccessPointByClientToken", reflect.TypeOf((*MockCloud)(nil).FindAccessPointByClientToken), ctx, clientToken, accessPointOpts)
+}
+
 // GetMetadata mocks base method.
 func (m *MockCloud) GetMetadata() cloud.MetadataService {
 	m.ctrl.T.Helper()
@@ -263,3 +436,61 @@ func (mr *MockCloudMockRecorder) ListAccessPoints(arg0, arg1 interface{}) *gomoc
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAccessPoints", reflect.TypeOf((*MockCloud)(nil).ListAccessPoints), arg0, arg1)
 }
+
+// ListMountTargets mocks base method.
+func (m *MockCloud) ListMountTargets(ctx context.Context, fileSystemId string) ([]*cloud.MountTarget, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListMountTargets", ctx, fileSystemId)
+	ret0, _ := ret[0].([]*cloud.MountTarget)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListMountTargets indicates an expected call of ListMountTargets.
+func (mr *MockCloudMockRecorder) ListMountTargets(ctx, fileSystemId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListMountTargets", reflect.TypeOf((*MockCloud)(nil).ListMountTargets), ctx, fileSystemId)
+}
+
+// TagAccessPoint mocks base method.
+func (m *MockCloud) TagAccessPoint(arg0 context.Context, arg1 string, arg2 map[string]string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TagAccessPoint", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// TagAccessPoint indicates an expected call of TagAccessPoint.
+func (mr *MockCloudMockRecorder) TagAccessPoint(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TagAccessPoint", reflect.TypeOf((*MockCloud)(nil).TagAccessPoint), arg0, arg1, arg2)
+}
+
+// UntagAccessPoint mocks base method.
+func (m *MockCloud) UntagAccessPoint(ctx context.Context, accessPointId string, tagKeys []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UntagAccessPoint", ctx, accessPointId, tagKeys)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UntagAccessPoint indicates an expected call of UntagAccessPoint.
+func (mr *MockCloudMockRecorder) UntagAccessPoint(ctx, accessPointId, tagKeys interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UntagAccessPoint", reflect.TypeOf((*MockCloud)(nil).UntagAccessPoint), ctx, accessPointId, tagKeys)
+}
+
+// WithRegion mocks base method.
+func (m *MockCloud) WithRegion(arg0 string) (cloud.Cloud, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithRegion", arg0)
+	ret0, _ := ret[0].(cloud.Cloud)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// WithRegion indicates an expected call of WithRegion.
+func (mr *MockCloudMockRecorder) WithRegion(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithRegion", reflect.TypeOf((*MockCloud)(nil).WithRegion), arg0)
+}
@@ -0,0 +1,142 @@
+package driver
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"k8s.io/klog/v2"
+)
+
+var (
+	// allowedMountOptions are the NFS mount options accepted by the mountOptions parameter, with the validation of
+	// their value. Options changing how the file system is reached or authenticated, such as notls, accesspoint,
+	// awscredsuri, mounttargetip or port, are rejected, as are the generic mount options like exec or suid.
+	allowedMountOptions = map[string]func(string) bool{
+		"acdirmax":    isIntInRange(0, math.MaxInt32),
+		"acdirmin":    isIntInRange(0, math.MaxInt32),
+		"acregmax":    isIntInRange(0, math.MaxInt32),
+		"acregmin":    isIntInRange(0, math.MaxInt32),
+		"actimeo":     isIntInRange(0, math.MaxInt32),
+		"hard":        nil,
+		"lookupcache": isOneOf("all", "none", "pos", "positive"),
+		"nconnect":    isIntInRange(1, 16),
+		"noac":        nil,
+		"noresvport":  nil,
+		"retrans":     isIntInRange(0, 100),
+		"rsize":       isIntInRange(1024, 1048576),
+		"soft":        nil,
+		"timeo":       isIntInRange(1, 6000),
+		"wsize":       isIntInRange(1024, 1048576),
+	}
+	// cleanupMountOptionKeys are the keys of the mountOptions parameter honored by the DeleteVolume cleanup mount.
+	// The attribute caching options do not matter to the deletion of a directory tree.
+	cleanupMountOptionKeys = []string{"hard", "soft", "noresvport", "retrans", "rsize", "timeo", "wsize"}
+	// exclusiveMountOptions are the options which cannot be combined, keyed by the option they exclude
+	exclusiveMountOptions = map[string]string{"hard": "soft", "soft": "hard"}
+)
+
+// parseMountOptions parses the comma separated mountOptions parameter, and validates each option against
+// allowedMountOptions. Options are returned lowercased, and with their numeric values normalized.
+func parseMountOptions(value string) ([]string, error) {
+	options := []string{}
+	for _, field := range strings.Split(value, ",") {
+		option := strings.ToLower(strings.TrimSpace(field))
+		if option == "" {
+			continue
+		}
+		key, optionValue, hasValue := strings.Cut(option, "=")
+		isValid, ok := allowedMountOptions[key]
+		if !ok {
+			return nil, status.Errorf(codes.InvalidArgument, "Mount option %q is not allowed in %v. Allowed options are %v", key, MountOptions, allowedMountOptionKeys())
+		}
+		if isValid == nil && hasValue {
+			return nil, status.Errorf(codes.InvalidArgument, "Mount option %q of %v does not take a value", key, MountOptions)
+		}
+		if isValid != nil && (!hasValue || !isValid(optionValue)) {
+			return nil, status.Errorf(codes.InvalidArgument, "Invalid value %q for mount option %q of %v", optionValue, key, MountOptions)
+		}
+		if hasOptionKey(options, key) {
+			return nil, status.Errorf(codes.InvalidArgument, "Mount option %q is set more than once in %v", key, MountOptions)
+		}
+		if excluded, ok := exclusiveMountOptions[key]; ok && hasOption(options, excluded) {
+			return nil, status.Errorf(codes.InvalidArgument, "Mount options %q and %q of %v cannot be combined", excluded, key, MountOptions)
+		}
+		if n, err := strconv.ParseInt(optionValue, 10, 64); err == nil {
+			option = key + "=" + strconv.FormatInt(n, 10)
+		}
+		options = append(options, option)
+	}
+	return options, nil
+}
+
+// appendMountOptions appends the options of the mountOptions parameter to the mount options, except those whose key
+// is already set, or which exclude an option already set, so that the options set explicitly take precedence.
+func appendMountOptions(mountOptions, options []string) []string {
+	for _, option := range options {
+		key, _, _ := strings.Cut(option, "=")
+		if hasOptionKey(mountOptions, key) {
+			continue
+		}
+		if excluded, ok := exclusiveMountOptions[key]; ok && hasOption(mountOptions, excluded) {
+			continue
+		}
+		mountOptions = append(mountOptions, option)
+	}
+	return mountOptions
+}
+
+// cleanupMountOptionsFromTags returns the options of the mountOptions parameter recorded on an access point which are
+// honored by the DeleteVolume cleanup mount. Tag values cannot contain commas, the options are separated by spaces.
+func cleanupMountOptionsFromTags(tags map[string]string) []string {
+	value, ok := tags[MountOptionsTagKey]
+	if !ok {
+		return nil
+	}
+	options, err := parseMountOptions(strings.Join(strings.Fields(value), ","))
+	if err != nil {
+		klog.Warningf("Ignoring the mount options %q recorded on the access point: %v", value, err)
+		return nil
+	}
+	cleanupOptions := []string{}
+	for _, option := range options {
+		key, _, _ := strings.Cut(option, "=")
+		if hasOption(cleanupMountOptionKeys, key) {
+			cleanupOptions = append(cleanupOptions, option)
+		}
+	}
+	return cleanupOptions
+}
+
+func allowedMountOptionKeys() []string {
+	keys := make([]string, 0, len(allowedMountOptions))
+	for key := range allowedMountOptions {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func isIntInRange(min, max int64) func(string) bool {
+	return func(value string) bool {
+		n, err := strconv.ParseInt(value, 10, 64)
+		return err == nil && n >= min && n <= max
+	}
+}
+
+func isOneOf(values ...string) func(string) bool {
+	return func(value string) bool {
+		return hasOption(values, value)
+	}
+}
+
+// setMountOptions records the options of the mountOptions parameter in the volume context, for the node mounts
+func setMountOptions(volContext map[string]string, options []string) {
+	if len(options) == 0 {
+		return
+	}
+	volContext[MountOptions] = strings.Join(options, ",")
+}
@@ -0,0 +1,282 @@
+package driver
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	"github.com/golang/mock/gomock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/kubernetes-sigs/aws-efs-csi-driver/pkg/cloud"
+	"github.com/kubernetes-sigs/aws-efs-csi-driver/pkg/cloud/fake"
+	"github.com/kubernetes-sigs/aws-efs-csi-driver/pkg/driver/mocks"
+)
+
+func TestParseMountOptions(t *testing.T) {
+	testCases := []struct {
+		name      string
+		value     string
+		expected  []string
+		expectErr bool
+	}{
+		{
+			name:     "Success: Empty",
+			value:    "",
+			expected: []string{},
+		},
+		{
+			name:     "Success: Options",
+			value:    "rsize=1048576, wsize=1048576,HARD,timeo=600,retrans=2,noresvport",
+			expected: []string{"rsize=1048576", "wsize=1048576", "hard", "timeo=600", "retrans=2", "noresvport"},
+		},
+		{
+			name:     "Success: Values are normalized",
+			value:    "actimeo=030,lookupcache=Positive,nconnect=+4",
+			expected: []string{"actimeo=30", "lookupcache=positive", "nconnect=4"},
+		},
+		{
+			name:      "Fail: TLS disabled",
+			value:     "rsize=1048576,notls",
+			expectErr: true,
+		},
+		{
+			name:      "Fail: Access point",
+			value:     "accesspoint=fsap-abcd1234",
+			expectErr: true,
+		},
+		{
+			name:      "Fail: Credentials URI",
+			value:     "awscredsuri=/v2/credentials",
+			expectErr: true,
+		},
+		{
+			name:      "Fail: Generic mount option",
+			value:     "suid",
+			expectErr: true,
+		},
+		{
+			name:      "Fail: Value out of range",
+			value:     "rsize=512",
+			expectErr: true,
+		},
+		{
+			name:      "Fail: Value not a number",
+			value:     "timeo=long",
+			expectErr: true,
+		},
+		{
+			name:      "Fail: Missing value",
+			value:     "retrans",
+			expectErr: true,
+		},
+		{
+			name:      "Fail: Value of a flag",
+			value:     "hard=true",
+			expectErr: true,
+		},
+		{
+			name:      "Fail: Unknown lookupcache mode",
+			value:     "lookupcache=neg",
+			expectErr: true,
+		},
+		{
+			name:      "Fail: Duplicate option",
+			value:     "timeo=600,timeo=100",
+			expectErr: true,
+		},
+		{
+			name:      "Fail: Hard and soft",
+			value:     "hard,soft",
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			options, err := parseMountOptions(tc.value)
+			if tc.expectErr {
+				if status.Code(err) != codes.InvalidArgument {
+					t.Fatalf("Expected an InvalidArgument error, got: %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseMountOptions failed: %v", err)
+			}
+			if !reflect.DeepEqual(options, tc.expected) {
+				t.Fatalf("Options mismatched. Expected: %v, Actual: %v", tc.expected, options)
+			}
+		})
+	}
+}
+
+func TestAppendMountOptions(t *testing.T) {
+	mountOptions := []string{"tls", "soft", "timeo=100"}
+	options := appendMountOptions(mountOptions, []string{"hard", "timeo=600", "retrans=2"})
+	if expected := []string{"tls", "soft", "timeo=100", "retrans=2"}; !reflect.DeepEqual(options, expected) {
+		t.Fatalf("Options mismatched. Expected: %v, Actual: %v", expected, options)
+	}
+}
+
+func TestCreateVolumeMountOptions(t *testing.T) {
+	fsId := "fs-abcd1234"
+	fakeCloud := fake.NewCloud()
+	fakeCloud.AddFileSystem(fsId)
+	driver := &Driver{
+		cloud:        fakeCloud,
+		gidAllocator: NewGidAllocator(),
+	}
+
+	req := &csi.CreateVolumeRequest{
+		Name: "pvc-1",
+		VolumeCapabilities: []*csi.VolumeCapability{
+			{
+				AccessType: &csi.VolumeCapability_Mount{
+					Mount: &csi.VolumeCapability_MountVolume{},
+				},
+				AccessMode: &csi.VolumeCapability_AccessMode{
+					Mode: csi.VolumeCapability_AccessMode_MULTI_NODE_MULTI_WRITER,
+				},
+			},
+		},
+		CapacityRange: &csi.CapacityRange{RequiredBytes: 5 * 1024 * 1024 * 1024},
+		Parameters: map[string]string{
+			ProvisioningMode: AccessPointMode,
+			FsId:             fsId,
+			DirectoryPerms:   "700",
+			MountOptions:     "rsize=1048576, HARD,actimeo=30",
+		},
+	}
+
+	ctx := context.Background()
+	res, err := driver.CreateVolume(ctx, req)
+	if err != nil {
+		t.Fatalf("CreateVolume failed: %v", err)
+	}
+	if expected := "rsize=1048576,hard,actimeo=30"; res.Volume.VolumeContext[MountOptions] != expected {
+		t.Fatalf("Volume context mismatched. Expected: %v, Actual: %v", expected, res.Volume.VolumeContext[MountOptions])
+	}
+	accessPoints, err := fakeCloud.ListAccessPoints(ctx, fsId)
+	if err != nil {
+		t.Fatalf("ListAccessPoints failed: %v", err)
+	}
+	if len(accessPoints) != 1 {
+		t.Fatalf("Expected 1 access point, got %v", len(accessPoints))
+	}
+	if expected := "rsize=1048576 hard actimeo=30"; accessPoints[0].Tags[MountOptionsTagKey] != expected {
+		t.Fatalf("Tag mismatched. Expected: %v, Actual: %v", expected, accessPoints[0].Tags[MountOptionsTagKey])
+	}
+
+	req.Name = "pvc-2"
+	req.Parameters[MountOptions] = "rsize=1048576,notls"
+	if _, err := driver.CreateVolume(ctx, req); status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("Expected an InvalidArgument error, got: %v", err)
+	}
+}
+
+func TestNodePublishVolumeMountOptions(t *testing.T) {
+	volCap := func(mountFlags ...string) *csi.VolumeCapability {
+		return &csi.VolumeCapability{
+			AccessType: &csi.VolumeCapability_Mount{
+				Mount: &csi.VolumeCapability_MountVolume{MountFlags: mountFlags},
+			},
+			AccessMode: &csi.VolumeCapability_AccessMode{
+				Mode: csi.VolumeCapability_AccessMode_MULTI_NODE_MULTI_WRITER,
+			},
+		}
+	}
+
+	testCases := []struct {
+		name         string
+		volCap       *csi.VolumeCapability
+		mountOptions string
+		expected     []string
+		expectErr    bool
+	}{
+		{
+			name:         "Success: Options are appended",
+			volCap:       volCap(),
+			mountOptions: "rsize=1048576,hard",
+			expected:     []string{"tls", "rsize=1048576", "hard"},
+		},
+		{
+			name:         "Success: Mount flags take precedence",
+			volCap:       volCap("soft", "rsize=65536"),
+			mountOptions: "rsize=1048576,hard,timeo=600",
+			expected:     []string{"tls", "soft", "rsize=65536", "timeo=600"},
+		},
+		{
+			name:         "Fail: Option not allowed",
+			volCap:       volCap(),
+			mountOptions: "notls",
+			expectErr:    true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			mockMounter, driver, ctx := setup(mockCtrl, NewVolStatter(), true)
+			if !tc.expectErr {
+				mockMounter.EXPECT().MakeDir(gomock.Eq(targetPath)).Return(nil)
+				mockMounter.EXPECT().Mount(volumeId+":/", targetPath, "efs", tc.expected).Return(nil)
+			}
+
+			_, err := driver.NodePublishVolume(ctx, &csi.NodePublishVolumeRequest{
+				VolumeId:         volumeId,
+				VolumeCapability: tc.volCap,
+				TargetPath:       targetPath,
+				VolumeContext:    map[string]string{MountOptions: tc.mountOptions},
+			})
+			if tc.expectErr {
+				if status.Code(err) != codes.InvalidArgument {
+					t.Fatalf("Expected an InvalidArgument error, got: %v", err)
+				}
+			} else if err != nil {
+				t.Fatalf("NodePublishVolume failed: %v", err)
+			}
+			mockCtrl.Finish()
+		})
+	}
+}
+
+func TestDeleteVolumeMountOptions(t *testing.T) {
+	var (
+		fsId     = "fs-abcd1234"
+		apId     = "fsap-abcd1234xyz987"
+		volumeId = "fs-abcd1234::fsap-abcd1234xyz987"
+	)
+
+	mockCtl := gomock.NewController(t)
+	mockCloud := mocks.NewMockCloud(mockCtl)
+	mockMounter := mocks.NewMockMounter(mockCtl)
+	tempMountDir := t.TempDir()
+	driver := &Driver{
+		cloud:                    mockCloud,
+		mounter:                  mockMounter,
+		gidAllocator:             NewGidAllocator(),
+		deleteAccessPointRootDir: true,
+		tempMountPathPrefix:      tempMountDir,
+	}
+
+	ctx := context.Background()
+	accessPoint := &cloud.AccessPoint{
+		AccessPointId: apId,
+		FileSystemId:  fsId,
+		Tags:          map[string]string{MountOptionsTagKey: "rsize=1048576 hard actimeo=30"},
+	}
+	// The attribute caching options are not used by the cleanup mount
+	target := tempMountDir + "/" + apId
+	mockCloud.EXPECT().DescribeAccessPoint(gomock.Eq(ctx), gomock.Eq(apId)).Return(accessPoint, nil)
+	mockMounter.EXPECT().MakeDir(gomock.Eq(target)).Return(nil)
+	mockMounter.EXPECT().Mount(gomock.Eq(fsId), gomock.Eq(target), gomock.Any(), gomock.Eq([]string{"tls", "iam", "rsize=1048576", "hard"})).Return(nil)
+	mockMounter.EXPECT().Unmount(gomock.Eq(target)).Return(nil)
+	mockCloud.EXPECT().DeleteAccessPoint(gomock.Eq(ctx), gomock.Eq(apId)).Return(nil)
+	if _, err := driver.DeleteVolume(ctx, &csi.DeleteVolumeRequest{VolumeId: volumeId}); err != nil {
+		t.Fatalf("DeleteVolume failed: %v", err)
+	}
+	mockCtl.Finish()
+}
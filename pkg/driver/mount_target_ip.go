@@ -0,0 +1,96 @@
+package driver
+
+import (
+	"context"
+	"net"
+	"sort"
+	"strings"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"k8s.io/klog/v2"
+
+	"github.com/kubernetes-sigs/aws-efs-csi-driver/pkg/cloud"
+)
+
+// parseMountTargetIpParam validates the mountTargetIp parameter, an IP address or auto
+func parseMountTargetIpParam(value string) (string, error) {
+	value = strings.TrimSpace(value)
+	if value != MountTargetIpAuto && net.ParseIP(value) == nil {
+		return "", status.Errorf(codes.InvalidArgument, "Invalid %v %q. Supported values are an IP address and %v", MountTargetIpParam, value, MountTargetIpAuto)
+	}
+	return value, nil
+}
+
+// preferredZone returns the zone of the most preferred topology of a request, e.g. the zone of the node
+// of the first consumer of the volume with topology enabled, or an empty string if it has none
+func preferredZone(req *csi.CreateVolumeRequest) string {
+	for _, topology := range req.GetAccessibilityRequirements().GetPreferred() {
+		if zone := topology.GetSegments()[TopologyZoneKey]; zone != "" {
+			return zone
+		}
+	}
+	return ""
+}
+
+// resolveMountTargetIps returns the IP address of the mount target of the file system nodes mount the volume through,
+// in the zone if one is given and has a mount target, and the fallback list of the available mount targets,
+// formatted as zone=ip pairs, from which nodes pick the mount target of their own zone.
+func resolveMountTargetIps(ctx context.Context, localCloud cloud.Cloud, fileSystemId, zone string) (string, string, error) {
+	mountTargets, err := localCloud.ListMountTargets(ctx, fileSystemId)
+	if err != nil {
+		if cloud.IsAccessDenied(err) {
+			return "", "", status.Errorf(codes.Unauthenticated, "Access Denied. Please ensure you have the right AWS permissions: %v", err)
+		}
+		if cloud.IsNotFound(err) {
+			return "", "", status.Errorf(codes.InvalidArgument, "File System does not exist: %v", err)
+		}
+		return "", "", status.Errorf(codes.Internal, "Failed to list mount targets of File System %v: %v", fileSystemId, err)
+	}
+	if len(mountTargets) == 0 {
+		return "", "", status.Errorf(codes.InvalidArgument, "File System %v has no available mount target to resolve %v=%v", fileSystemId, MountTargetIpParam, MountTargetIpAuto)
+	}
+
+	sort.Slice(mountTargets, func(i, j int) bool {
+		return mountTargets[i].AZName < mountTargets[j].AZName
+	})
+	ip := mountTargets[0].IPAddress
+	fallback := make([]string, 0, len(mountTargets))
+	for _, mountTarget := range mountTargets {
+		if zone != "" && mountTarget.AZName == zone {
+			ip = mountTarget.IPAddress
+		}
+		fallback = append(fallback, mountTarget.AZName+"="+mountTarget.IPAddress)
+	}
+	klog.V(4).Infof("Resolved mount target IP %v of File System %v, fallbacks %v", ip, fileSystemId, fallback)
+	return ip, strings.Join(fallback, ","), nil
+}
+
+// nodeMountTargetIp returns the IP address the node mounts a volume through: the one of the zone of the node
+// in the fallback list of the volume context, otherwise the IP address resolved by the controller
+func (d *Driver) nodeMountTargetIp(ip string, volContext map[string]string) string {
+	fallback, ok := volContext[MountTargetIps]
+	if !ok || d.cloud == nil {
+		return ip
+	}
+	zone := d.cloud.GetMetadata().GetAvailabilityZone()
+	for _, entry := range strings.Split(fallback, ",") {
+		if entryZone, entryIp, ok := strings.Cut(entry, "="); ok && zone != "" && entryZone == zone {
+			return entryIp
+		}
+	}
+	return ip
+}
+
+// setMountTargetIp records the mount target IP address and its fallback list in the volume context,
+// in place of the IP address of the mount target of cross account volumes
+func setMountTargetIp(volContext map[string]string, ip, fallback string) {
+	if ip == "" {
+		return
+	}
+	volContext[MountTargetIp] = ip
+	if fallback != "" {
+		volContext[MountTargetIps] = fallback
+	}
+}
@@ -0,0 +1,154 @@
+package driver
+
+import (
+	"context"
+	"testing"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/kubernetes-sigs/aws-efs-csi-driver/pkg/cloud/fake"
+)
+
+func TestCreateVolumeMountTargetIp(t *testing.T) {
+	var (
+		fsId      = "fs-abcd1234"
+		stdVolCap = &csi.VolumeCapability{
+			AccessType: &csi.VolumeCapability_Mount{
+				Mount: &csi.VolumeCapability_MountVolume{},
+			},
+			AccessMode: &csi.VolumeCapability_AccessMode{
+				Mode: csi.VolumeCapability_AccessMode_MULTI_NODE_MULTI_WRITER,
+			},
+		}
+	)
+
+	testCases := []struct {
+		name               string
+		params             map[string]string
+		preferredZone      string
+		expectCode         codes.Code
+		expectIp           string
+		expectFallbackList string
+	}{
+		{
+			name:     "Success: Explicit IP address",
+			params:   map[string]string{MountTargetIpParam: "10.0.5.10"},
+			expectIp: "10.0.5.10",
+		},
+		{
+			name:               "Success: Auto resolves the mount target of the first zone",
+			params:             map[string]string{MountTargetIpParam: MountTargetIpAuto},
+			expectIp:           "10.0.1.10",
+			expectFallbackList: "us-east-1a=10.0.1.10,us-east-1b=10.0.0.10",
+		},
+		{
+			name:               "Success: Auto resolves the mount target of the az parameter",
+			params:             map[string]string{MountTargetIpParam: MountTargetIpAuto, AzName: "us-east-1b"},
+			expectIp:           "10.0.0.10",
+			expectFallbackList: "us-east-1a=10.0.1.10,us-east-1b=10.0.0.10",
+		},
+		{
+			name:               "Success: Auto resolves the mount target of the preferred zone",
+			params:             map[string]string{MountTargetIpParam: MountTargetIpAuto},
+			preferredZone:      "us-east-1b",
+			expectIp:           "10.0.0.10",
+			expectFallbackList: "us-east-1a=10.0.1.10,us-east-1b=10.0.0.10",
+		},
+		{
+			name:       "Fail: Invalid IP address",
+			params:     map[string]string{MountTargetIpParam: "10.0.0"},
+			expectCode: codes.InvalidArgument,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			fakeCloud := fake.NewCloud()
+			fakeCloud.AddFileSystem(fsId, "us-east-1b", "us-east-1a")
+			driver := &Driver{
+				cloud:        fakeCloud,
+				gidAllocator: NewGidAllocator(),
+			}
+
+			params := map[string]string{
+				ProvisioningMode: AccessPointMode,
+				FsId:             fsId,
+				DirectoryPerms:   "700",
+			}
+			for k, v := range tc.params {
+				params[k] = v
+			}
+			req := &csi.CreateVolumeRequest{
+				Name:               "name",
+				VolumeCapabilities: []*csi.VolumeCapability{stdVolCap},
+				CapacityRange:      &csi.CapacityRange{RequiredBytes: 5 * 1024 * 1024 * 1024},
+				Parameters:         params,
+			}
+			if tc.preferredZone != "" {
+				req.AccessibilityRequirements = &csi.TopologyRequirement{
+					Preferred: []*csi.Topology{{Segments: map[string]string{TopologyZoneKey: tc.preferredZone}}},
+				}
+			}
+
+			res, err := driver.CreateVolume(context.Background(), req)
+			if tc.expectCode != codes.OK {
+				if status.Code(err) != tc.expectCode {
+					t.Fatalf("Expected a %v error, got: %v", tc.expectCode, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("CreateVolume failed: %v", err)
+			}
+			volContext := res.Volume.VolumeContext
+			if volContext[MountTargetIp] != tc.expectIp {
+				t.Fatalf("Mount target IP mismatched. Expected: %v, Actual: %v", tc.expectIp, volContext[MountTargetIp])
+			}
+			if volContext[MountTargetIps] != tc.expectFallbackList {
+				t.Fatalf("Mount target fallback list mismatched. Expected: %v, Actual: %v", tc.expectFallbackList, volContext[MountTargetIps])
+			}
+		})
+	}
+}
+
+func TestNodeMountTargetIp(t *testing.T) {
+	testCases := []struct {
+		name       string
+		volContext map[string]string
+		expectIp   string
+	}{
+		{
+			name:       "Success: IP address without fallback list",
+			volContext: map[string]string{MountTargetIp: "10.0.0.10"},
+			expectIp:   "10.0.0.10",
+		},
+		{
+			name: "Success: Mount target of the zone of the node",
+			volContext: map[string]string{
+				MountTargetIp:  "10.0.0.10",
+				MountTargetIps: "us-east-1a=10.0.1.10,us-east-1b=10.0.0.10",
+			},
+			expectIp: "10.0.1.10",
+		},
+		{
+			name: "Success: No mount target in the zone of the node",
+			volContext: map[string]string{
+				MountTargetIp:  "10.0.0.10",
+				MountTargetIps: "us-east-1b=10.0.0.10,us-east-1c=10.0.2.10",
+			},
+			expectIp: "10.0.0.10",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			driver := &Driver{cloud: fake.NewCloud()}
+			ip := driver.nodeMountTargetIp(tc.volContext[MountTargetIp], tc.volContext)
+			if ip != tc.expectIp {
+				t.Fatalf("Mount target IP mismatched. Expected: %v, Actual: %v", tc.expectIp, ip)
+			}
+		})
+	}
+}
@@ -14,8 +14,12 @@ limitations under the License.
 package driver
 
 import (
+	"fmt"
 	"os"
+	"os/exec"
+	"strings"
 
+	"k8s.io/klog/v2"
 	mount_utils "k8s.io/mount-utils"
 )
 
@@ -28,14 +32,34 @@ type Mounter interface {
 
 type NodeMounter struct {
 	mount_utils.Interface
+	// efsHelperPath is the efs-utils mount helper run for the efs type, mount only finds helpers in /sbin
+	efsHelperPath string
 }
 
-func newNodeMounter() Mounter {
+func newNodeMounter(efsHelperPath string) Mounter {
 	return &NodeMounter{
-		Interface: mount_utils.New(""),
+		Interface:     mount_utils.New(""),
+		efsHelperPath: efsHelperPath,
 	}
 }
 
+// Mount runs the efs-utils mount helper itself for the efs type when it is not installed where mount finds it
+func (m *NodeMounter) Mount(source, target, fstype string, options []string) error {
+	if fstype != "efs" || m.efsHelperPath == "" || m.efsHelperPath == DefaultEfsMountHelperPath {
+		return m.Interface.Mount(source, target, fstype, options)
+	}
+	args := []string{source, target}
+	if len(options) > 0 {
+		args = append(args, "-o", strings.Join(options, ","))
+	}
+	klog.V(4).Infof("Mounting cmd (%s) with arguments (%s)", m.efsHelperPath, args)
+	output, err := exec.Command(m.efsHelperPath, args...).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("mount failed: %v\nMounting command: %s\nMounting arguments: %s\nOutput: %s", err, m.efsHelperPath, strings.Join(args, " "), output)
+	}
+	return nil
+}
+
 func (m *NodeMounter) MakeDir(pathname string) error {
 	err := os.MkdirAll(pathname, os.FileMode(0755))
 	if err != nil {
@@ -0,0 +1,94 @@
+package driver
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/informers"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/kubernetes-sigs/aws-efs-csi-driver/pkg/cloud"
+)
+
+const (
+	// NamespacePosixUidAnnotation is the annotation of a namespace setting the uid of the access points of its PVCs
+	NamespacePosixUidAnnotation = "efs.csi.aws.com/posix-uid"
+	// NamespacePosixGidAnnotation is the annotation of a namespace setting the gid of the access points of its PVCs
+	NamespacePosixGidAnnotation = "efs.csi.aws.com/posix-gid"
+)
+
+// newNamespaceLister returns a lister of the namespaces of the cluster, served from an informer cache synced before
+// it returns. The informer runs until the driver exits.
+func newNamespaceLister(k8sClient cloud.KubernetesAPIClient) (corelisters.NamespaceLister, error) {
+	clientset, err := k8sClient()
+	if err != nil {
+		return nil, err
+	}
+	factory := informers.NewSharedInformerFactory(clientset, 0)
+	namespaces := factory.Core().V1().Namespaces()
+	lister := namespaces.Lister()
+	factory.Start(wait.NeverStop)
+	if !cache.WaitForCacheSync(wait.NeverStop, namespaces.Informer().HasSynced) {
+		return nil, fmt.Errorf("could not sync the namespaces cache")
+	}
+	return lister, nil
+}
+
+// namespacePosixUser returns the uid and gid set by the annotations of the namespace of the PVC with
+// --namespace-posix-annotations, -1 for those which are not set. The allocator assigns the gid of a namespace without
+// annotations, as without the flag.
+func (d *Driver) namespacePosixUser(volumeParams map[string]string) (uid, gid int64, err error) {
+	uid, gid = -1, -1
+	if d.namespaceLister == nil {
+		return uid, gid, nil
+	}
+	namespace := strings.TrimSpace(volumeParams[PvcNamespace])
+	if namespace == "" {
+		return uid, gid, status.Errorf(codes.InvalidArgument, "The POSIX user of the namespace requires the %v metadata. Please run the csi-provisioner with --extra-create-metadata", PvcNamespace)
+	}
+	ns, err := d.namespaceLister.Get(namespace)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return uid, gid, status.Errorf(codes.FailedPrecondition, "Namespace %v of the PVC not found", namespace)
+		}
+		return uid, gid, status.Errorf(codes.Unavailable, "Could not get namespace %v: %v", namespace, err)
+	}
+	if uid, err = parseNamespacePosixId(ns.Name, ns.Annotations, NamespacePosixUidAnnotation); err != nil {
+		return -1, -1, err
+	}
+	if gid, err = parseNamespacePosixId(ns.Name, ns.Annotations, NamespacePosixGidAnnotation); err != nil {
+		return -1, -1, err
+	}
+	return uid, gid, nil
+}
+
+// parseNamespacePosixId parses the uid or gid annotation of a namespace, -1 when it is not set
+func parseNamespacePosixId(namespace string, annotations map[string]string, annotation string) (int64, error) {
+	value, ok := annotations[annotation]
+	if !ok {
+		return -1, nil
+	}
+	id, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+	if err != nil || id < 0 {
+		return -1, status.Errorf(codes.FailedPrecondition, "Invalid annotation %v=%q of namespace %v: must be an integer greater or equal than 0", annotation, value, namespace)
+	}
+	return id, nil
+}
+
+// mergeNamespacePosixId returns the uid or gid of the access point, from the StorageClass parameter or the namespace
+// annotation. Both may only be set to the same value, so that the StorageClass cannot override the namespace policy.
+func mergeNamespacePosixId(param string, paramId int64, annotation string, namespaceId int64) (int64, error) {
+	if namespaceId < 0 {
+		return paramId, nil
+	}
+	if paramId >= 0 && paramId != namespaceId {
+		return -1, status.Errorf(codes.InvalidArgument, "Parameter %v=%v conflicts with %v=%v of the namespace", param, paramId, annotation, namespaceId)
+	}
+	return namespaceId, nil
+}
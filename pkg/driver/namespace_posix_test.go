@@ -0,0 +1,177 @@
+package driver
+
+import (
+	"context"
+	"testing"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	"github.com/golang/mock/gomock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/kubernetes-sigs/aws-efs-csi-driver/pkg/cloud"
+	"github.com/kubernetes-sigs/aws-efs-csi-driver/pkg/driver/mocks"
+)
+
+// newFakeNamespaceLister returns a lister of the namespaces
+func newFakeNamespaceLister(t *testing.T, namespaces ...*corev1.Namespace) corelisters.NamespaceLister {
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	for _, ns := range namespaces {
+		if err := indexer.Add(ns); err != nil {
+			t.Fatalf("Failed to add namespace %v: %v", ns.Name, err)
+		}
+	}
+	return corelisters.NewNamespaceLister(indexer)
+}
+
+func TestCreateVolumeNamespacePosixUser(t *testing.T) {
+	var (
+		fsId = "fs-abcd1234"
+		apId = "fsap-abcd1234xyz987"
+	)
+	lister := func(t *testing.T) corelisters.NamespaceLister {
+		return newFakeNamespaceLister(t,
+			&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "team-a", Annotations: map[string]string{
+				NamespacePosixUidAnnotation: "2001",
+				NamespacePosixGidAnnotation: "3001",
+			}}},
+			&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "team-b", Annotations: map[string]string{
+				NamespacePosixUidAnnotation: "2002",
+			}}},
+			&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "team-c"}},
+			&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "team-d", Annotations: map[string]string{
+				NamespacePosixUidAnnotation: "root",
+			}}},
+		)
+	}
+
+	testCases := []struct {
+		name         string
+		namespace    string
+		params       map[string]string
+		expectedUid  int64
+		expectedGid  int64
+		allocatedGid bool
+		expectedCode codes.Code
+	}{
+		{
+			name:        "Success: uid and gid of the namespace",
+			namespace:   "team-a",
+			expectedUid: 2001,
+			expectedGid: 3001,
+		},
+		{
+			name:        "Success: Parameters matching the namespace",
+			namespace:   "team-a",
+			params:      map[string]string{Uid: "2001", Gid: "3001"},
+			expectedUid: 2001,
+			expectedGid: 3001,
+		},
+		{
+			name:         "Success: gid without annotation is allocated",
+			namespace:    "team-b",
+			expectedUid:  2002,
+			allocatedGid: true,
+		},
+		{
+			name:         "Success: Namespace without annotations falls back to the allocator",
+			namespace:    "team-c",
+			allocatedGid: true,
+		},
+		{
+			name:         "Fail: uid parameter conflicting with the namespace",
+			namespace:    "team-a",
+			params:       map[string]string{Uid: "1000"},
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name:         "Fail: gid parameter conflicting with the namespace",
+			namespace:    "team-a",
+			params:       map[string]string{Gid: "1000"},
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name:         "Fail: Invalid annotation",
+			namespace:    "team-d",
+			expectedCode: codes.FailedPrecondition,
+		},
+		{
+			name:         "Fail: Namespace not found",
+			namespace:    "team-e",
+			expectedCode: codes.FailedPrecondition,
+		},
+		{
+			name:         "Fail: Namespace metadata missing",
+			expectedCode: codes.InvalidArgument,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockCtl := gomock.NewController(t)
+			mockCloud := mocks.NewMockCloud(mockCtl)
+			driver := &Driver{
+				cloud:           mockCloud,
+				gidAllocator:    NewGidAllocator(),
+				namespaceLister: lister(t),
+			}
+			req := &csi.CreateVolumeRequest{
+				Name: "pvc-1",
+				VolumeCapabilities: []*csi.VolumeCapability{
+					{
+						AccessType: &csi.VolumeCapability_Mount{
+							Mount: &csi.VolumeCapability_MountVolume{},
+						},
+						AccessMode: &csi.VolumeCapability_AccessMode{
+							Mode: csi.VolumeCapability_AccessMode_MULTI_NODE_MULTI_WRITER,
+						},
+					},
+				},
+				CapacityRange: &csi.CapacityRange{RequiredBytes: 5 * 1024 * 1024 * 1024},
+				Parameters: map[string]string{
+					ProvisioningMode: AccessPointMode,
+					FsId:             fsId,
+					DirectoryPerms:   "700",
+				},
+			}
+			if tc.namespace != "" {
+				req.Parameters[PvcNamespace] = tc.namespace
+				req.Parameters[PvcName] = "pvc"
+			}
+			for k, v := range tc.params {
+				req.Parameters[k] = v
+			}
+
+			if tc.expectedCode == codes.OK {
+				if tc.allocatedGid || tc.expectedUid == 0 {
+					mockCloud.EXPECT().ListAccessPoints(gomock.Any(), gomock.Eq(fsId)).Return(nil, nil)
+				} else {
+					mockCloud.EXPECT().DescribeFileSystem(gomock.Any(), gomock.Eq(fsId)).Return(&cloud.FileSystem{FileSystemId: fsId}, nil)
+				}
+				mockCloud.EXPECT().CreateAccessPoint(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+					func(ctx context.Context, clientToken string, accessPointOpts *cloud.AccessPointOptions, reuseAccessPoint bool) (*cloud.AccessPoint, error) {
+						if tc.expectedUid != 0 && accessPointOpts.Uid != tc.expectedUid {
+							t.Fatalf("Uid mismatched. Expected: %v, Actual: %v", tc.expectedUid, accessPointOpts.Uid)
+						}
+						if tc.allocatedGid && (accessPointOpts.Gid < DefaultGidMin || accessPointOpts.Gid > DefaultGidMax) {
+							t.Fatalf("Expected an allocated gid, got %v", accessPointOpts.Gid)
+						}
+						if !tc.allocatedGid && accessPointOpts.Gid != tc.expectedGid {
+							t.Fatalf("Gid mismatched. Expected: %v, Actual: %v", tc.expectedGid, accessPointOpts.Gid)
+						}
+						return &cloud.AccessPoint{AccessPointId: apId, FileSystemId: fsId}, nil
+					})
+			}
+
+			_, err := driver.CreateVolume(context.Background(), req)
+			if status.Code(err) != tc.expectedCode {
+				t.Fatalf("Expected %v, got: %v", tc.expectedCode, err)
+			}
+			mockCtl.Finish()
+		})
+	}
+}
@@ -23,6 +23,7 @@ import (
 	"os"
 	"path"
 	"path/filepath"
+	"regexp"
 	"strconv"
 	"strings"
 
@@ -34,11 +35,16 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	k8stypes "k8s.io/apimachinery/pkg/types"
 	"k8s.io/klog/v2"
+	mount_utils "k8s.io/mount-utils"
 )
 
 var (
+	// volumeCapAccessModes are the access modes EFS can honor. Any number of nodes can mount a file system, but
+	// nothing restricts writes to a single node or workload, so modes relying on that are rejected.
+	// SINGLE_NODE_WRITER is kept as ReadWriteOnce volumes are commonly used with EFS.
 	volumeCapAccessModes = []csi.VolumeCapability_AccessMode_Mode{
 		csi.VolumeCapability_AccessMode_SINGLE_NODE_WRITER,
+		csi.VolumeCapability_AccessMode_MULTI_NODE_READER_ONLY,
 		csi.VolumeCapability_AccessMode_MULTI_NODE_MULTI_WRITER,
 	}
 	volumeIdCounter  = make(map[string]int)
@@ -78,6 +84,9 @@ func (d *Driver) NodePublishVolume(ctx context.Context, req *csi.NodePublishVolu
 	// TODO when CreateVolume is implemented, it must use the same key names
 	subpath := "/"
 	encryptInTransit := true
+	quotaBytes := int64(0)
+	readOnly := false
+	var contextMountOptions []string
 	volContext := req.GetVolumeContext()
 	for k, v := range volContext {
 		switch strings.ToLower(k) {
@@ -90,6 +99,12 @@ func (d *Driver) NodePublishVolume(ctx context.Context, req *csi.NodePublishVolu
 			subpath = filepath.Join(subpath, v)
 		case "storage.kubernetes.io/csiprovisioneridentity":
 			continue
+		case strings.ToLower(AccessPointArn):
+			// Informational, the access point is mounted by the ID in the volume handle
+			continue
+		case strings.ToLower(ReplicationDestFsId):
+			// Informational, the destination is mounted by a volume of its own after a failover
+			continue
 		case "encryptintransit":
 			var err error
 			encryptInTransit, err = strconv.ParseBool(v)
@@ -97,8 +112,44 @@ func (d *Driver) NodePublishVolume(ctx context.Context, req *csi.NodePublishVolu
 				return nil, status.Error(codes.InvalidArgument, fmt.Sprintf("Volume context property %q must be a boolean value: %v", k, err))
 			}
 		case MountTargetIp:
-			ipAddr := volContext[MountTargetIp]
+			ipAddr := d.nodeMountTargetIp(v, volContext)
 			mountOptions = append(mountOptions, MountTargetIp+"="+ipAddr)
+		case strings.ToLower(MountTargetIps):
+			// The fallback list is used with mounttargetip
+			continue
+		case strings.ToLower(MountOptions):
+			// Validated again, as the volume context of statically provisioned volumes is not checked by the controller
+			var err error
+			contextMountOptions, err = parseMountOptions(v)
+			if err != nil {
+				return nil, err
+			}
+		case AzName:
+			mountOptions = append(mountOptions, AzName+"="+v)
+		case Region:
+			mountOptions = append(mountOptions, Region+"="+v)
+		case strings.ToLower(QuotaBytes):
+			var err error
+			quotaBytes, err = strconv.ParseInt(v, 10, 64)
+			if err != nil || quotaBytes < 0 {
+				return nil, status.Errorf(codes.InvalidArgument, "Volume context property %q must be a non negative integer", k)
+			}
+			// NFS has no mount option limiting the size of a directory, the quota caps the reported volume stats
+			klog.V(4).Infof("NodePublishVolume: volume %v requested a quota of %v bytes, which is not enforced by EFS", req.GetVolumeId(), quotaBytes)
+		case strings.ToLower(ReadOnly):
+			var err error
+			readOnly, err = strconv.ParseBool(v)
+			if err != nil {
+				return nil, status.Error(codes.InvalidArgument, fmt.Sprintf("Volume context property %q must be a boolean value: %v", k, err))
+			}
+		case strings.ToLower(UseIam):
+			useIam, err := strconv.ParseBool(v)
+			if err != nil {
+				return nil, status.Error(codes.InvalidArgument, fmt.Sprintf("Volume context property %q must be a boolean value: %v", k, err))
+			}
+			if useIam && !hasOption(mountOptions, "iam") {
+				mountOptions = append(mountOptions, "iam")
+			}
 		default:
 			return nil, status.Errorf(codes.InvalidArgument, "Volume context property %s not supported", k)
 		}
@@ -133,7 +184,7 @@ func (d *Driver) NodePublishVolume(ctx context.Context, req *csi.NodePublishVolu
 		}
 	}
 
-	if req.GetReadonly() {
+	if req.GetReadonly() || readOnly || volCap.GetAccessMode().GetMode() == csi.VolumeCapability_AccessMode_MULTI_NODE_READER_ONLY {
 		mountOptions = append(mountOptions, "ro")
 	}
 
@@ -170,6 +221,11 @@ func (d *Driver) NodePublishVolume(ctx context.Context, req *csi.NodePublishVolu
 				}
 			}
 
+			if strings.HasPrefix(f, AzName+"=") && hasOptionKey(mountOptions, AzName) {
+				klog.Warningf("Ignoring %q under mountOptions, the volume is pinned to an availability zone in the volume context", f)
+				continue
+			}
+
 			if strings.HasPrefix(f, "awscredsuri") {
 				klog.Warning("awscredsuri mount option is not supported by efs-csi-driver.")
 				continue
@@ -180,6 +236,8 @@ func (d *Driver) NodePublishVolume(ctx context.Context, req *csi.NodePublishVolu
 			}
 		}
 	}
+	// The mountOptions of the PV take precedence over those of the StorageClass parameters
+	mountOptions = appendMountOptions(mountOptions, contextMountOptions)
 	klog.V(5).Infof("NodePublishVolume: creating dir %s", target)
 	if err := d.mounter.MakeDir(target); err != nil {
 		return nil, status.Errorf(codes.Internal, "Could not create dir %q: %v", target, err)
@@ -191,6 +249,9 @@ func (d *Driver) NodePublishVolume(ctx context.Context, req *csi.NodePublishVolu
 		return nil, status.Errorf(codes.Internal, "Could not mount %q at %q: %v", source, target, err)
 	}
 	klog.V(5).Infof("NodePublishVolume: %s was mounted", target)
+	if quotaBytes > 0 {
+		d.volumeQuotas.Store(target, quotaBytes)
+	}
 
 	//Increment volume Id counter
 	if d.volMetricsOptIn {
@@ -235,6 +296,7 @@ func (d *Driver) NodeUnpublishVolume(ctx context.Context, req *csi.NodeUnpublish
 		return nil, status.Errorf(codes.Internal, "Could not unmount %q: %v", target, err)
 	}
 	klog.V(5).Infof("NodeUnpublishVolume: %s unmounted", target)
+	d.volumeQuotas.Delete(target)
 
 	//TODO: If `du` is running on a volume, unmount waits for it to complete. We should stop `du` on unmount in the future for NodeUnpublish
 	//Decrement Volume ID counter and evict cache if counter is 0.
@@ -272,21 +334,65 @@ func (d *Driver) NodeGetVolumeStats(ctx context.Context, req *csi.NodeGetVolumeS
 		if os.IsNotExist(err) {
 			return nil, status.Errorf(codes.NotFound, "Volume Path %s does not exist", target)
 		}
+		if mount_utils.IsCorruptedMnt(err) {
+			return abnormalVolumeStats(target, err), nil
+		}
 
 		return nil, status.Errorf(codes.Internal, "Failed to invoke stat on volume path %s: %v", target, err)
 	}
 
-	volMetrics, err := d.volStatter.computeVolumeMetrics(volId, target, d.volMetricsRefreshPeriod, d.volMetricsFsRateLimit)
-
+	notMnt, err := d.mounter.IsLikelyNotMountPoint(target)
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "Could not get metrics: %v ", err)
+		if mount_utils.IsCorruptedMnt(err) {
+			return abnormalVolumeStats(target, err), nil
+		}
+		return nil, status.Errorf(codes.Internal, "Failed to check if volume path %s is mounted: %v", target, err)
+	}
+	if notMnt {
+		return nil, status.Errorf(codes.NotFound, "Volume Path %s is not mounted", target)
+	}
+
+	// The usage of the directory of the volume is computed in the background when opted in,
+	// otherwise the usage of the whole file system is reported
+	var usage []*csi.VolumeUsage
+	if d.volMetricsOptIn {
+		volMetrics, err := d.volStatter.computeVolumeMetrics(volId, target, d.volMetricsRefreshPeriod, d.volMetricsFsRateLimit)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "Could not get metrics: %v ", err)
+		}
+		usage = volMetrics.volUsage
+	} else {
+		usage, err = statfsVolumeUsage(target)
+		if err != nil {
+			if mount_utils.IsCorruptedMnt(err) {
+				return abnormalVolumeStats(target, err), nil
+			}
+			return nil, status.Errorf(codes.Internal, "Could not get metrics: %v ", err)
+		}
+	}
+
+	if quotaBytes, ok := d.volumeQuotas.Load(target); ok {
+		usage = capVolumeUsage(usage, quotaBytes.(int64))
 	}
 
 	return &csi.NodeGetVolumeStatsResponse{
-		Usage: volMetrics.volUsage,
+		Usage:           usage,
+		VolumeCondition: &csi.VolumeCondition{Abnormal: false, Message: "Volume is mounted"},
 	}, nil
 }
 
+// abnormalVolumeStats returns the stats of a volume whose mount is corrupted, e.g. by a stale NFS file handle or an
+// unreachable mount target, so that kubelet reports the abnormal condition on the pods using it
+func abnormalVolumeStats(target string, err error) *csi.NodeGetVolumeStatsResponse {
+	klog.Warningf("NodeGetVolumeStats: mount of volume path %s is corrupted: %v", target, err)
+	return &csi.NodeGetVolumeStatsResponse{
+		VolumeCondition: &csi.VolumeCondition{
+			Abnormal: true,
+			Message:  fmt.Sprintf("Mount of volume path %s is corrupted: %v", target, err),
+		},
+	}
+}
+
 func (d *Driver) NodeExpandVolume(ctx context.Context, req *csi.NodeExpandVolumeRequest) (*csi.NodeExpandVolumeResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "")
 }
@@ -311,7 +417,8 @@ func (d *Driver) NodeGetInfo(ctx context.Context, req *csi.NodeGetInfoRequest) (
 	klog.V(4).Infof("NodeGetInfo: called with args %+v", req)
 
 	return &csi.NodeGetInfoResponse{
-		NodeId: d.nodeID,
+		NodeId:             d.nodeID,
+		AccessibleTopology: d.nodeTopology(),
 	}, nil
 }
 
@@ -388,25 +495,44 @@ func (d *Driver) validateFStype(volCaps []*csi.VolumeCapability) error {
 //   - The `{mountPath}`, if specified, is not required to be absolute.
 //   - The `{accessPointID}` is expected to be of the form `fsap-...`.
 //
+// The file system ID must consist of `fs-` followed by lowercase hexadecimal digits, and the access point ID of
+// `fsap-` followed by letters and digits. Legacy volume IDs written by hand in static PVs, such as a bare
+// `fs-abcd1234` or `fs-abcd1234:/dir`, remain valid, and the file system may be given by its ARN instead,
+// e.g. `arn:aws:elasticfilesystem:us-east-1:123456789012:file-system/fs-abcd1234::fsap-abcd1234`.
+//
+// Volumes sharing an access point through accessPointShareKey have a fourth field, the share reference of the volume
+// (see shareRef), e.g. `fs-abcd1234::fsap-abcd1234:0123456789abcdef`, which parseShareRef returns.
+//
 // parseVolumeId returns the parsed values, of which `subpath` and `apid` may be empty; and an
 // error, which will be a `status.Error` with `codes.InvalidArgument`, or `nil` if the `volumeId`
-// was parsed successfully.
+// was parsed successfully. Use isDriverVolumeId to tell a malformed volume ID of this driver from
+// the ID of a volume which cannot exist.
 // See the following issues for some background:
 // - https://github.com/kubernetes-sigs/aws-efs-csi-driver/issues/100
 // - https://github.com/kubernetes-sigs/aws-efs-csi-driver/issues/167
 func parseVolumeId(volumeId string) (fsid, subpath, apid string, err error) {
 	// Might as well do this up front, since the FSID is required and first in the string
-	if !isValidFileSystemId(volumeId) {
+	if !isDriverVolumeId(volumeId) {
 		err = status.Errorf(codes.InvalidArgument, "volume ID '%s' is invalid: Expected a file system ID of the form 'fs-...'", volumeId)
 		return
 	}
 
-	tokens := strings.Split(volumeId, ":")
+	// Volume IDs written by hand may start with the ARN of the file system instead of its ID
+	tokens := strings.Split(trimFileSystemArn(volumeId), ":")
+	// The share reference of a volume sharing an access point is only needed by DeleteVolume
+	if parseShareRef(volumeId) != "" {
+		tokens = tokens[:3]
+	}
 	if len(tokens) > 3 {
 		err = status.Errorf(codes.InvalidArgument, "volume ID '%s' is invalid: Expected at most three fields separated by ':'", volumeId)
 		return
 	}
 
+	if !isValidFileSystemId(tokens[0]) {
+		err = status.Errorf(codes.InvalidArgument, "volume ID '%s' has an invalid file system ID '%s': Expected it to be of the form 'fs-' followed by hexadecimal digits", volumeId, tokens[0])
+		return
+	}
+
 	// Okay, we know we have a FSID
 	fsid = tokens[0]
 
@@ -427,6 +553,27 @@ func parseVolumeId(volumeId string) (fsid, subpath, apid string, err error) {
 	return
 }
 
+// parseShareRef returns the share reference of the ID of a volume sharing an access point, empty for other volume IDs
+func parseShareRef(volumeId string) string {
+	tokens := strings.Split(trimFileSystemArn(volumeId), ":")
+	if len(tokens) == 4 && tokens[2] != "" && shareRefPattern.MatchString(tokens[3]) {
+		return tokens[3]
+	}
+	return ""
+}
+
+// formatVolumeId returns the volume ID parsed back by parseVolumeId.
+// Empty trailing fields are omitted, so that volumes without a subpath keep the `{fileSystemID}::{accessPointID}` form.
+func formatVolumeId(fsid, subpath, apid string) string {
+	if apid != "" {
+		return fsid + ":" + subpath + ":" + apid
+	}
+	if subpath != "" {
+		return fsid + ":" + subpath
+	}
+	return fsid
+}
+
 // Check and avoid adding duplicate mount options
 func hasOption(options []string, opt string) bool {
 	for _, o := range options {
@@ -437,12 +584,45 @@ func hasOption(options []string, opt string) bool {
 	return false
 }
 
+var (
+	fileSystemIdPattern  = regexp.MustCompile(`^fs-[0-9a-f]+$`)
+	accessPointIdPattern = regexp.MustCompile(`^fsap-[0-9a-zA-Z]+$`)
+	shareRefPattern      = regexp.MustCompile(`^[0-9a-f]{16}$`)
+	// fileSystemArnPrefixPattern matches the ARN of a file system up to its ID, capturing its region
+	fileSystemArnPrefixPattern = regexp.MustCompile(`^arn:aws[-a-z]*:elasticfilesystem:([a-z0-9-]+):[0-9]{12}:file-system/`)
+)
+
+// isDriverVolumeId returns whether the volume ID starts with a file system ID or ARN, as every volume ID of the driver does.
+// A volume ID which does not was not created by the driver, even if it is malformed.
+func isDriverVolumeId(volumeId string) bool {
+	return strings.HasPrefix(trimFileSystemArn(volumeId), "fs-")
+}
+
 func isValidFileSystemId(filesystemId string) bool {
-	return strings.HasPrefix(filesystemId, "fs-")
+	return fileSystemIdPattern.MatchString(filesystemId)
+}
+
+// trimFileSystemArn removes the part of a file system ARN preceding its ID, leaving other values untouched
+func trimFileSystemArn(value string) string {
+	return fileSystemArnPrefixPattern.ReplaceAllString(value, "")
+}
+
+// fileSystemArnRegion returns the region of the file system ARN a value starts with, empty when it does not start with one
+func fileSystemArnRegion(value string) string {
+	if matches := fileSystemArnPrefixPattern.FindStringSubmatch(value); matches != nil {
+		return matches[1]
+	}
+	return ""
+}
+
+// normalizeFileSystemId returns the ID of a file system given by its ID or its ARN, and whether it is valid
+func normalizeFileSystemId(value string) (string, bool) {
+	fsId := trimFileSystemArn(strings.TrimSpace(value))
+	return fsId, isValidFileSystemId(fsId)
 }
 
 func isValidAccessPointId(accesspointId string) bool {
-	return strings.HasPrefix(accesspointId, "fsap-")
+	return accessPointIdPattern.MatchString(accesspointId)
 }
 
 // Struct for JSON patch operations
@@ -22,12 +22,15 @@ import (
 	"os"
 	"reflect"
 	"strings"
+	"syscall"
 	"testing"
 	"time"
 
 	"github.com/container-storage-interface/spec/lib/go/csi"
 	"github.com/golang/mock/gomock"
 	"github.com/kubernetes-sigs/aws-efs-csi-driver/pkg/driver/mocks"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/client-go/kubernetes"
 )
@@ -187,6 +190,115 @@ func TestNodePublishVolume(t *testing.T) {
 			mountArgs:     []interface{}{volumeId + ":/a/b", targetPath, "efs", []string{"tls"}},
 			mountSuccess:  true,
 		},
+		{
+			name: "success: normal with useIam in volume context",
+			req: &csi.NodePublishVolumeRequest{
+				VolumeId:         volumeId,
+				VolumeCapability: stdVolCap,
+				TargetPath:       targetPath,
+				VolumeContext:    map[string]string{UseIam: "true"},
+			},
+			expectMakeDir: true,
+			mountArgs:     []interface{}{volumeId + ":/", targetPath, "efs", []string{"iam", "tls"}},
+			mountSuccess:  true,
+		},
+		{
+			name: "success: normal with access point ARN in volume context",
+			req: &csi.NodePublishVolumeRequest{
+				VolumeId:         volumeId,
+				VolumeCapability: stdVolCap,
+				TargetPath:       targetPath,
+				VolumeContext:    map[string]string{AccessPointArn: "arn:aws:elasticfilesystem:us-east-1:123456789012:access-point/fsap-abcd1234"},
+			},
+			expectMakeDir: true,
+			mountArgs:     []interface{}{volumeId + ":/", targetPath, "efs", []string{"tls"}},
+			mountSuccess:  true,
+		},
+		{
+			name: "success: normal with az in volume context",
+			req: &csi.NodePublishVolumeRequest{
+				VolumeId:         volumeId,
+				VolumeCapability: stdVolCap,
+				TargetPath:       targetPath,
+				VolumeContext:    map[string]string{AzName: "us-east-1a"},
+			},
+			expectMakeDir: true,
+			mountArgs:     []interface{}{volumeId + ":/", targetPath, "efs", []string{"az=us-east-1a", "tls"}},
+			mountSuccess:  true,
+		},
+		{
+			name: "success: az in volume context takes precedence over mount options",
+			req: &csi.NodePublishVolumeRequest{
+				VolumeId: volumeId,
+				VolumeCapability: &csi.VolumeCapability{
+					AccessType: &csi.VolumeCapability_Mount{
+						Mount: &csi.VolumeCapability_MountVolume{
+							MountFlags: []string{"az=us-east-1b"},
+						},
+					},
+					AccessMode: &csi.VolumeCapability_AccessMode{
+						Mode: csi.VolumeCapability_AccessMode_MULTI_NODE_MULTI_WRITER,
+					},
+				},
+				TargetPath:    targetPath,
+				VolumeContext: map[string]string{AzName: "us-east-1a"},
+			},
+			expectMakeDir: true,
+			mountArgs:     []interface{}{volumeId + ":/", targetPath, "efs", []string{"az=us-east-1a", "tls"}},
+			mountSuccess:  true,
+		},
+		{
+			name: "success: normal with region in volume context",
+			req: &csi.NodePublishVolumeRequest{
+				VolumeId:         volumeId,
+				VolumeCapability: stdVolCap,
+				TargetPath:       targetPath,
+				VolumeContext:    map[string]string{Region: "eu-west-1"},
+			},
+			expectMakeDir: true,
+			mountArgs:     []interface{}{volumeId + ":/", targetPath, "efs", []string{"region=eu-west-1", "tls"}},
+			mountSuccess:  true,
+		},
+		{
+			name: "success: normal with quotaBytes in volume context",
+			req: &csi.NodePublishVolumeRequest{
+				VolumeId:         volumeId,
+				VolumeCapability: stdVolCap,
+				TargetPath:       targetPath,
+				VolumeContext:    map[string]string{QuotaBytes: "5368709120"},
+			},
+			expectMakeDir: true,
+			mountArgs:     []interface{}{volumeId + ":/", targetPath, "efs", []string{"tls"}},
+			mountSuccess:  true,
+		},
+		{
+			name: "fail: quotaBytes in volume context must be an integer",
+			req: &csi.NodePublishVolumeRequest{
+				VolumeId:         volumeId,
+				VolumeCapability: stdVolCap,
+				TargetPath:       targetPath,
+				VolumeContext:    map[string]string{QuotaBytes: "5Gi"},
+			},
+			expectMakeDir: false,
+			expectError: errtyp{
+				code:    "InvalidArgument",
+				message: `Volume context property "quotaBytes" must be a non negative integer`,
+			},
+		},
+		{
+			name: "fail: useIam in volume context must be a boolean",
+			req: &csi.NodePublishVolumeRequest{
+				VolumeId:         volumeId,
+				VolumeCapability: stdVolCap,
+				TargetPath:       targetPath,
+				VolumeContext:    map[string]string{UseIam: "yes please"},
+			},
+			expectMakeDir: false,
+			expectError: errtyp{
+				code:    "InvalidArgument",
+				message: `Volume context property "useIam" must be a boolean value: strconv.ParseBool: parsing "yes please": invalid syntax`,
+			},
+		},
 		{
 			name: "fail: path in volume context must be absolute",
 			req: &csi.NodePublishVolumeRequest{
@@ -248,6 +360,17 @@ func TestNodePublishVolume(t *testing.T) {
 			mountArgs:     []interface{}{volumeId + ":/", targetPath, "efs", []string{"accesspoint=" + accessPointID, "tls"}},
 			mountSuccess:  true,
 		},
+		{
+			name: "success: access point in volume handle of a volume sharing it",
+			req: &csi.NodePublishVolumeRequest{
+				VolumeId:         volumeId + "::" + accessPointID + ":0123456789abcdef",
+				VolumeCapability: stdVolCap,
+				TargetPath:       targetPath,
+			},
+			expectMakeDir: true,
+			mountArgs:     []interface{}{volumeId + ":/", targetPath, "efs", []string{"accesspoint=" + accessPointID, "tls"}},
+			mountSuccess:  true,
+		},
 		{
 			name: "success: path and access point in volume handle",
 			req: &csi.NodePublishVolumeRequest{
@@ -397,6 +520,24 @@ func TestNodePublishVolume(t *testing.T) {
 				message: "Volume capability not provided",
 			},
 		},
+		{
+			name: "success: multi node reader only is mounted read only",
+			req: &csi.NodePublishVolumeRequest{
+				VolumeId: volumeId,
+				VolumeCapability: &csi.VolumeCapability{
+					AccessType: &csi.VolumeCapability_Mount{
+						Mount: &csi.VolumeCapability_MountVolume{},
+					},
+					AccessMode: &csi.VolumeCapability_AccessMode{
+						Mode: csi.VolumeCapability_AccessMode_MULTI_NODE_READER_ONLY,
+					},
+				},
+				TargetPath: targetPath,
+			},
+			expectMakeDir: true,
+			mountArgs:     []interface{}{volumeId + ":/", targetPath, "efs", []string{"tls", "ro"}},
+			mountSuccess:  true,
+		},
 		{
 			name: "fail: unsupported volume capability",
 			req: &csi.NodePublishVolumeRequest{
@@ -746,6 +887,10 @@ func TestNodeGetVolumeStats(t *testing.T) {
 		name             string
 		req              *csi.NodeGetVolumeStatsRequest
 		updateCache      bool
+		checkMount       bool
+		notMounted       bool
+		mountErr         error
+		quotaBytes       int64
 		expectError      errtyp
 		expectedResponse *csi.NodeGetVolumeStatsResponse
 	}{
@@ -755,12 +900,14 @@ func TestNodeGetVolumeStats(t *testing.T) {
 				VolumeId:   volumeId,
 				VolumePath: validPath,
 			},
+			checkMount: true,
 			expectedResponse: &csi.NodeGetVolumeStatsResponse{
 				Usage: []*csi.VolumeUsage{
 					{
 						Unit: csi.VolumeUsage_UNKNOWN,
 					},
 				},
+				VolumeCondition: &csi.VolumeCondition{Abnormal: false, Message: "Volume is mounted"},
 			},
 		},
 		{
@@ -770,6 +917,7 @@ func TestNodeGetVolumeStats(t *testing.T) {
 				VolumePath: validPath,
 			},
 			updateCache: true,
+			checkMount:  true,
 			expectedResponse: &csi.NodeGetVolumeStatsResponse{
 				Usage: []*csi.VolumeUsage{
 					{
@@ -779,6 +927,56 @@ func TestNodeGetVolumeStats(t *testing.T) {
 						Used:      1,
 					},
 				},
+				VolumeCondition: &csi.VolumeCondition{Abnormal: false, Message: "Volume is mounted"},
+			},
+		},
+		{
+			name: "success: volume usage capped to its quota",
+			req: &csi.NodeGetVolumeStatsRequest{
+				VolumeId:   volumeId,
+				VolumePath: validPath,
+			},
+			updateCache: true,
+			checkMount:  true,
+			quotaBytes:  1,
+			expectedResponse: &csi.NodeGetVolumeStatsResponse{
+				Usage: []*csi.VolumeUsage{
+					{
+						Unit:      csi.VolumeUsage_BYTES,
+						Available: 0,
+						Total:     1,
+						Used:      1,
+					},
+				},
+				VolumeCondition: &csi.VolumeCondition{Abnormal: false, Message: "Volume is mounted"},
+			},
+		},
+		{
+			name: "success: corrupted mount is reported as abnormal",
+			req: &csi.NodeGetVolumeStatsRequest{
+				VolumeId:   volumeId,
+				VolumePath: validPath,
+			},
+			checkMount: true,
+			mountErr:   &os.PathError{Op: "lstat", Path: validPath, Err: syscall.ESTALE},
+			expectedResponse: &csi.NodeGetVolumeStatsResponse{
+				VolumeCondition: &csi.VolumeCondition{
+					Abnormal: true,
+					Message:  "Mount of volume path /tmp/target is corrupted: lstat /tmp/target: " + syscall.ESTALE.Error(),
+				},
+			},
+		},
+		{
+			name: "Fail: Path is not mounted",
+			req: &csi.NodeGetVolumeStatsRequest{
+				VolumeId:   volumeId,
+				VolumePath: validPath,
+			},
+			checkMount: true,
+			notMounted: true,
+			expectError: errtyp{
+				code:    "NotFound",
+				message: "Volume Path /tmp/target is not mounted",
 			},
 		},
 		{
@@ -818,14 +1016,16 @@ func TestNodeGetVolumeStats(t *testing.T) {
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			var driver *Driver
-			var ctx context.Context
-			var _ *mocks.MockMounter
-
 			//setup
 			mockCtrl := gomock.NewController(t)
 			defer mockCtrl.Finish()
-			_, driver, ctx = setup(mockCtrl, NewVolStatter(), true)
+			mockMounter, driver, ctx := setup(mockCtrl, NewVolStatter(), true)
+			if tc.checkMount {
+				mockMounter.EXPECT().IsLikelyNotMountPoint(tc.req.VolumePath).Return(tc.notMounted, tc.mountErr)
+			}
+			if tc.quotaBytes > 0 {
+				driver.volumeQuotas.Store(tc.req.VolumePath, tc.quotaBytes)
+			}
 
 			if tc.updateCache {
 				mu.Lock()
@@ -850,6 +1050,43 @@ func TestNodeGetVolumeStats(t *testing.T) {
 	os.RemoveAll(validPath)
 }
 
+func TestNodeGetCapabilities(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	_, driver, ctx := setup(mockCtrl, NewVolStatter(), true)
+
+	res, err := driver.NodeGetCapabilities(ctx, &csi.NodeGetCapabilitiesRequest{})
+	if err != nil {
+		t.Fatalf("NodeGetCapabilities failed: %v", err)
+	}
+	advertised := map[csi.NodeServiceCapability_RPC_Type]bool{}
+	for _, c := range res.GetCapabilities() {
+		advertised[c.GetRpc().GetType()] = true
+	}
+
+	// Each capability must be advertised if and only if its RPC is implemented
+	_, stageErr := driver.NodeStageVolume(ctx, &csi.NodeStageVolumeRequest{})
+	_, expandErr := driver.NodeExpandVolume(ctx, &csi.NodeExpandVolumeRequest{})
+	_, statsErr := driver.NodeGetVolumeStats(ctx, &csi.NodeGetVolumeStatsRequest{})
+	implemented := map[csi.NodeServiceCapability_RPC_Type]bool{
+		csi.NodeServiceCapability_RPC_STAGE_UNSTAGE_VOLUME: status.Code(stageErr) != codes.Unimplemented,
+		csi.NodeServiceCapability_RPC_EXPAND_VOLUME:        status.Code(expandErr) != codes.Unimplemented,
+		csi.NodeServiceCapability_RPC_GET_VOLUME_STATS:     status.Code(statsErr) != codes.Unimplemented,
+		// The volume condition is returned by NodeGetVolumeStats
+		csi.NodeServiceCapability_RPC_VOLUME_CONDITION: status.Code(statsErr) != codes.Unimplemented,
+	}
+	for capability, ok := range implemented {
+		if advertised[capability] != ok {
+			t.Errorf("Capability %v advertised: %v, implemented: %v", capability, advertised[capability], ok)
+		}
+	}
+	for capability := range advertised {
+		if _, ok := implemented[capability]; !ok {
+			t.Errorf("Capability %v is advertised, but not checked", capability)
+		}
+	}
+}
+
 func testResponse(t *testing.T, expected, actual *csi.NodeGetVolumeStatsResponse) {
 	if !reflect.DeepEqual(expected, actual) {
 		t.Errorf("Expected: %v, Actual: %v", expected, actual)
@@ -988,3 +1225,248 @@ func getNodeMock(mockCtl *gomock.Controller, nodeName string, returnNode *corev1
 
 	return mockClient, mockNode
 }
+
+func TestParseVolumeId(t *testing.T) {
+	testCases := []struct {
+		name        string
+		volumeId    string
+		fsid        string
+		subpath     string
+		apid        string
+		expectError bool
+	}{
+		{
+			name:     "File system ID only",
+			volumeId: "fs-abcd1234",
+			fsid:     "fs-abcd1234",
+		},
+		{
+			name:     "File system ID with empty fields",
+			volumeId: "fs-abcd1234::",
+			fsid:     "fs-abcd1234",
+		},
+		{
+			name:     "File system ID with a trailing separator",
+			volumeId: "fs-abcd1234:",
+			fsid:     "fs-abcd1234",
+		},
+		{
+			name:     "Subpath",
+			volumeId: "fs-abcd1234:/a/b/",
+			fsid:     "fs-abcd1234",
+			subpath:  "/a/b",
+		},
+		{
+			name:     "Relative subpath",
+			volumeId: "fs-abcd1234:a/b",
+			fsid:     "fs-abcd1234",
+			subpath:  "a/b",
+		},
+		{
+			name:     "Access point",
+			volumeId: "fs-abcd1234::fsap-abcd1234xyz987",
+			fsid:     "fs-abcd1234",
+			apid:     "fsap-abcd1234xyz987",
+		},
+		{
+			name:     "Subpath and access point",
+			volumeId: "fs-abcd1234:/a:fsap-abcd1234xyz987",
+			fsid:     "fs-abcd1234",
+			subpath:  "/a",
+			apid:     "fsap-abcd1234xyz987",
+		},
+		{
+			name:     "File system ARN",
+			volumeId: "arn:aws:elasticfilesystem:us-east-1:123456789012:file-system/fs-abcd1234",
+			fsid:     "fs-abcd1234",
+		},
+		{
+			name:     "File system ARN with subpath and access point",
+			volumeId: "arn:aws-us-gov:elasticfilesystem:us-gov-west-1:123456789012:file-system/fs-abcd1234:/a:fsap-abcd1234xyz987",
+			fsid:     "fs-abcd1234",
+			subpath:  "/a",
+			apid:     "fsap-abcd1234xyz987",
+		},
+		{
+			name:        "Fail: Double separators around the subpath",
+			volumeId:    "fs-abcd1234::/a::fsap-abcd1234xyz987",
+			expectError: true,
+		},
+		{
+			name:        "Fail: Not a file system ID",
+			volumeId:    "reallyfakevolumeid",
+			expectError: true,
+		},
+		{
+			name:        "Fail: Empty file system ID",
+			volumeId:    "fs-::fsap-abcd1234xyz987",
+			expectError: true,
+		},
+		{
+			name:        "Fail: Invalid characters in file system ID",
+			volumeId:    "fs-abcd/1234",
+			expectError: true,
+		},
+		{
+			name:        "Fail: File system ID not hexadecimal",
+			volumeId:    "fs-abcdxyz1::fsap-abcd1234xyz987",
+			expectError: true,
+		},
+		{
+			name:        "Fail: ARN of another resource",
+			volumeId:    "arn:aws:elasticfilesystem:us-east-1:123456789012:access-point/fsap-abcd1234xyz987",
+			expectError: true,
+		},
+		{
+			name:        "Fail: Empty access point ID",
+			volumeId:    "fs-abcd1234::fsap-",
+			expectError: true,
+		},
+		{
+			name:        "Fail: Invalid access point ID",
+			volumeId:    "fs-abcd1234::ap-abcd1234",
+			expectError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			fsid, subpath, apid, err := parseVolumeId(tc.volumeId)
+			if tc.expectError {
+				if status.Code(err) != codes.InvalidArgument {
+					t.Fatalf("Expected InvalidArgument, got: %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseVolumeId failed: %v", err)
+			}
+			if fsid != tc.fsid || subpath != tc.subpath || apid != tc.apid {
+				t.Fatalf("Expected (%q, %q, %q), got (%q, %q, %q)", tc.fsid, tc.subpath, tc.apid, fsid, subpath, apid)
+			}
+		})
+	}
+}
+
+func TestNormalizeFileSystemId(t *testing.T) {
+	testCases := []struct {
+		name    string
+		value   string
+		fsId    string
+		invalid bool
+	}{
+		{
+			name:  "File system ID",
+			value: "fs-0123456789abcdef0",
+			fsId:  "fs-0123456789abcdef0",
+		},
+		{
+			name:  "File system ID with spaces",
+			value: " fs-abcd1234 ",
+			fsId:  "fs-abcd1234",
+		},
+		{
+			name:  "File system ARN",
+			value: "arn:aws:elasticfilesystem:eu-west-1:123456789012:file-system/fs-abcd1234",
+			fsId:  "fs-abcd1234",
+		},
+		{
+			name:  "File system ARN of another partition",
+			value: "arn:aws-cn:elasticfilesystem:cn-north-1:123456789012:file-system/fs-abcd1234",
+			fsId:  "fs-abcd1234",
+		},
+		{
+			name:    "Fail: Prefix only",
+			value:   "fs-",
+			invalid: true,
+		},
+		{
+			name:    "Fail: Not hexadecimal",
+			value:   "fs-abcdefgh",
+			invalid: true,
+		},
+		{
+			name:    "Fail: Uppercase",
+			value:   "fs-ABCD1234",
+			invalid: true,
+		},
+		{
+			name:    "Fail: ARN of an access point",
+			value:   "arn:aws:elasticfilesystem:eu-west-1:123456789012:access-point/fsap-abcd1234xyz987",
+			invalid: true,
+		},
+		{
+			name:    "Fail: ARN of another service",
+			value:   "arn:aws:s3:eu-west-1:123456789012:file-system/fs-abcd1234",
+			invalid: true,
+		},
+		{
+			name:    "Fail: ARN with a trailing path",
+			value:   "arn:aws:elasticfilesystem:eu-west-1:123456789012:file-system/fs-abcd1234/data",
+			invalid: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			fsId, valid := normalizeFileSystemId(tc.value)
+			if valid == tc.invalid {
+				t.Fatalf("Validity of %q mismatched. Expected: %v, Actual: %v", tc.value, !tc.invalid, valid)
+			}
+			if !tc.invalid && fsId != tc.fsId {
+				t.Fatalf("File system ID mismatched. Expected: %v, Actual: %v", tc.fsId, fsId)
+			}
+		})
+	}
+}
+
+func TestFormatVolumeId(t *testing.T) {
+	testCases := []struct {
+		name     string
+		fsid     string
+		subpath  string
+		apid     string
+		volumeId string
+	}{
+		{
+			name:     "File system",
+			fsid:     "fs-abcd1234",
+			volumeId: "fs-abcd1234",
+		},
+		{
+			name:     "Subpath",
+			fsid:     "fs-abcd1234",
+			subpath:  "/a/b",
+			volumeId: "fs-abcd1234:/a/b",
+		},
+		{
+			name:     "Access point",
+			fsid:     "fs-abcd1234",
+			apid:     "fsap-abcd1234xyz987",
+			volumeId: "fs-abcd1234::fsap-abcd1234xyz987",
+		},
+		{
+			name:     "Subpath within access point",
+			fsid:     "fs-abcd1234",
+			subpath:  "/a/b",
+			apid:     "fsap-abcd1234xyz987",
+			volumeId: "fs-abcd1234:/a/b:fsap-abcd1234xyz987",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			volumeId := formatVolumeId(tc.fsid, tc.subpath, tc.apid)
+			if volumeId != tc.volumeId {
+				t.Fatalf("Volume Id mismatched. Expected: %v, Actual: %v", tc.volumeId, volumeId)
+			}
+			fsid, subpath, apid, err := parseVolumeId(volumeId)
+			if err != nil {
+				t.Fatalf("parseVolumeId failed: %v", err)
+			}
+			if fsid != tc.fsid || subpath != tc.subpath || apid != tc.apid {
+				t.Fatalf("Expected (%q, %q, %q), got (%q, %q, %q)", tc.fsid, tc.subpath, tc.apid, fsid, subpath, apid)
+			}
+		})
+	}
+}
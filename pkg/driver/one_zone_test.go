@@ -0,0 +1,103 @@
+package driver
+
+import (
+	"context"
+	"testing"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/kubernetes-sigs/aws-efs-csi-driver/pkg/cloud/fake"
+)
+
+func TestCreateVolumeOneZone(t *testing.T) {
+	testCases := []struct {
+		name           string
+		params         map[string]string
+		expectCode     codes.Code
+		expectZone     string
+		expectTopology bool
+	}{
+		{
+			name:           "Success: One Zone file system",
+			params:         map[string]string{ProvisioningMode: FileSystemMode, AvailabilityZoneName: "us-east-1b"},
+			expectZone:     "us-east-1b",
+			expectTopology: true,
+		},
+		{
+			name:   "Success: Regional file system",
+			params: map[string]string{ProvisioningMode: FileSystemMode},
+		},
+		{
+			name:       "Fail: Zone of another region",
+			params:     map[string]string{ProvisioningMode: FileSystemMode, AvailabilityZoneName: "us-west-2a"},
+			expectCode: codes.InvalidArgument,
+		},
+		{
+			name:       "Fail: Local zone",
+			params:     map[string]string{ProvisioningMode: FileSystemMode, AvailabilityZoneName: "us-east-1-bos-1a"},
+			expectCode: codes.InvalidArgument,
+		},
+		{
+			name:       "Fail: Zone with access point provisioning",
+			params:     map[string]string{ProvisioningMode: AccessPointMode, FsId: "fs-abcd1234", DirectoryPerms: "700", AvailabilityZoneName: "us-east-1b"},
+			expectCode: codes.InvalidArgument,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			fakeCloud := fake.NewCloud()
+			driver := &Driver{
+				cloud:                 fakeCloud,
+				gidAllocator:          NewGidAllocator(),
+				volumeTopologyEnabled: true,
+			}
+
+			req := &csi.CreateVolumeRequest{
+				Name: "pvc-1",
+				VolumeCapabilities: []*csi.VolumeCapability{
+					{
+						AccessType: &csi.VolumeCapability_Mount{
+							Mount: &csi.VolumeCapability_MountVolume{},
+						},
+						AccessMode: &csi.VolumeCapability_AccessMode{
+							Mode: csi.VolumeCapability_AccessMode_MULTI_NODE_MULTI_WRITER,
+						},
+					},
+				},
+				CapacityRange: &csi.CapacityRange{RequiredBytes: 5 * 1024 * 1024 * 1024},
+				Parameters:    tc.params,
+			}
+			res, err := driver.CreateVolume(context.Background(), req)
+			if status.Code(err) != tc.expectCode {
+				t.Fatalf("Expected %v, got: %v", tc.expectCode, err)
+			}
+			if tc.expectCode != codes.OK {
+				return
+			}
+
+			fileSystem, err := fakeCloud.DescribeFileSystem(context.Background(), res.Volume.VolumeId)
+			if err != nil {
+				t.Fatalf("DescribeFileSystem failed: %v", err)
+			}
+			if fileSystem.AvailabilityZoneName != tc.expectZone {
+				t.Fatalf("Zone of the file system mismatched. Expected: %q, Actual: %q", tc.expectZone, fileSystem.AvailabilityZoneName)
+			}
+			if zone, ok := res.Volume.VolumeContext[AzName]; zone != tc.expectZone || ok != (tc.expectZone != "") {
+				t.Fatalf("Zone of the volume context mismatched. Expected: %q, Actual: %v", tc.expectZone, res.Volume.VolumeContext)
+			}
+			topology := res.Volume.AccessibleTopology
+			if !tc.expectTopology {
+				if len(topology) != 0 {
+					t.Fatalf("Expected no topology, got: %v", topology)
+				}
+				return
+			}
+			if len(topology) != 1 || topology[0].Segments[TopologyZoneKey] != tc.expectZone {
+				t.Fatalf("Topology mismatched. Expected: %v, Actual: %v", tc.expectZone, topology)
+			}
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package driver
+
+import (
+	"context"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"k8s.io/klog/v2"
+
+	"github.com/kubernetes-sigs/aws-efs-csi-driver/pkg/cloud"
+)
+
+// fileSystemOverflows counts the volumes provisioned on --overflow-filesystem-id because their file system was full
+var fileSystemOverflows = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Name: "efs_csi_file_system_overflows_total",
+	Help: "Number of volumes provisioned on the overflow file system because the file system of their StorageClass was at its access point limit.",
+}, []string{"file_system_id", "overflow_file_system_id"})
+
+func init() {
+	metricsRegistry.MustRegister(fileSystemOverflows)
+}
+
+// selectFileSystem returns the file system to create the access point of a volume requested on fileSystemId on,
+// with its access points. It is --overflow-filesystem-id when fileSystemId is at its access point limit, and
+// fileSystemId otherwise. accessPoints are the access points of fileSystemId, listed unless listed is true.
+func (d *Driver) selectFileSystem(ctx context.Context, localCloud cloud.Cloud, req *csi.CreateVolumeRequest, fileSystemId string, accessPoints []*cloud.AccessPoint, listed bool) (string, []*cloud.AccessPoint, error) {
+	if d.overflowFileSystemId == "" || d.overflowFileSystemId == fileSystemId {
+		return fileSystemId, accessPoints, nil
+	}
+	var err error
+	if !listed {
+		accessPoints, err = localCloud.ListAccessPoints(ctx, fileSystemId)
+		if err != nil {
+			return "", nil, status.Errorf(codes.Internal, "Failed to list Access Points of File System %v: %v", fileSystemId, err)
+		}
+	}
+	if len(accessPoints) < cloud.AccessPointPerFsLimit {
+		return fileSystemId, accessPoints, nil
+	}
+
+	overflowAccessPoints, err := localCloud.ListAccessPoints(ctx, d.overflowFileSystemId)
+	if err != nil {
+		if cloud.IsAccessDenied(err) {
+			return "", nil, status.Errorf(codes.Unauthenticated, "Access Denied. Please ensure you have the right AWS permissions: %v", err)
+		}
+		if cloud.IsNotFound(err) {
+			return "", nil, status.Errorf(codes.FailedPrecondition, "File System %v is at its limit of %v access points and overflow File System %v does not exist: %v",
+				fileSystemId, cloud.AccessPointPerFsLimit, d.overflowFileSystemId, err)
+		}
+		return "", nil, status.Errorf(codes.Internal, "Failed to list Access Points of overflow File System %v: %v", d.overflowFileSystemId, err)
+	}
+	if len(overflowAccessPoints) >= cloud.AccessPointPerFsLimit {
+		return "", nil, status.Errorf(codes.ResourceExhausted, "File System %v and overflow File System %v are both at their limit of %v access points",
+			fileSystemId, d.overflowFileSystemId, cloud.AccessPointPerFsLimit)
+	}
+
+	klog.Warningf("File System %v is at its limit of %v access points, provisioning volume %v on overflow File System %v. Please plan more capacity for its StorageClass",
+		fileSystemId, cloud.AccessPointPerFsLimit, req.GetName(), d.overflowFileSystemId)
+	fileSystemOverflows.WithLabelValues(fileSystemId, d.overflowFileSystemId).Inc()
+	d.recordFileSystemOverflow(req, fileSystemId, d.overflowFileSystemId)
+	return d.overflowFileSystemId, overflowAccessPoints, nil
+}
@@ -0,0 +1,148 @@
+package driver
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/kubernetes-sigs/aws-efs-csi-driver/pkg/cloud"
+	"github.com/kubernetes-sigs/aws-efs-csi-driver/pkg/cloud/fake"
+)
+
+func TestCreateVolumeOverflowFileSystem(t *testing.T) {
+	var (
+		fsId         = "fs-abcd1234"
+		overflowFsId = "fs-ef015678"
+	)
+
+	testCases := []struct {
+		name string
+		// Numbers of access points of the file system of the StorageClass and of the overflow file system
+		accessPoints         int
+		overflowAccessPoints int
+		staticGid            bool
+		expectedFsId         string
+		expectedCode         codes.Code
+	}{
+		{
+			name:         "Success: File system below its limit",
+			accessPoints: cloud.AccessPointPerFsLimit - 1,
+			expectedFsId: fsId,
+		},
+		{
+			name:         "Success: File system at its limit overflows",
+			accessPoints: cloud.AccessPointPerFsLimit,
+			expectedFsId: overflowFsId,
+		},
+		{
+			name:         "Success: File system at its limit overflows with a static GID",
+			accessPoints: cloud.AccessPointPerFsLimit,
+			staticGid:    true,
+			expectedFsId: overflowFsId,
+		},
+		{
+			name:                 "Fail: Both file systems at their limit",
+			accessPoints:         cloud.AccessPointPerFsLimit,
+			overflowAccessPoints: cloud.AccessPointPerFsLimit,
+			expectedCode:         codes.ResourceExhausted,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			fakeCloud := fake.NewCloud()
+			fakeCloud.AddFileSystem(fsId)
+			fakeCloud.AddFileSystem(overflowFsId)
+			fillFileSystem(t, fakeCloud, fsId, tc.accessPoints)
+			fillFileSystem(t, fakeCloud, overflowFsId, tc.overflowAccessPoints)
+			driver := &Driver{
+				cloud:                fakeCloud,
+				gidAllocator:         NewGidAllocator(),
+				overflowFileSystemId: overflowFsId,
+			}
+			overflows := testutil.ToFloat64(fileSystemOverflows.WithLabelValues(fsId, overflowFsId))
+
+			params := map[string]string{
+				ProvisioningMode: AccessPointMode,
+				FsId:             fsId,
+				DirectoryPerms:   "700",
+			}
+			if tc.staticGid {
+				params[Uid] = "1000"
+				params[Gid] = "1000"
+			}
+			res, err := driver.CreateVolume(ctx, &csi.CreateVolumeRequest{
+				Name: "pvc-overflow",
+				VolumeCapabilities: []*csi.VolumeCapability{
+					{
+						AccessType: &csi.VolumeCapability_Mount{
+							Mount: &csi.VolumeCapability_MountVolume{},
+						},
+						AccessMode: &csi.VolumeCapability_AccessMode{
+							Mode: csi.VolumeCapability_AccessMode_MULTI_NODE_MULTI_WRITER,
+						},
+					},
+				},
+				CapacityRange: &csi.CapacityRange{RequiredBytes: 5 * 1024 * 1024 * 1024},
+				Parameters:    params,
+			})
+			if tc.expectedCode != codes.OK {
+				if status.Code(err) != tc.expectedCode {
+					t.Fatalf("Expected error code %v, got: %v", tc.expectedCode, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("CreateVolume failed: %v", err)
+			}
+
+			// The volume ID records the file system the access point was created on
+			volumeId := res.Volume.VolumeId
+			if !strings.HasPrefix(volumeId, tc.expectedFsId+"::") {
+				t.Fatalf("Volume %v is not on file system %v", volumeId, tc.expectedFsId)
+			}
+			expectedOverflows := overflows
+			if tc.expectedFsId == overflowFsId {
+				expectedOverflows++
+			}
+			if actual := testutil.ToFloat64(fileSystemOverflows.WithLabelValues(fsId, overflowFsId)); actual != expectedOverflows {
+				t.Fatalf("Overflows mismatched. Expected: %v, Actual: %v", expectedOverflows, actual)
+			}
+
+			accessPoints, err := fakeCloud.ListAccessPoints(ctx, tc.expectedFsId)
+			if err != nil {
+				t.Fatalf("ListAccessPoints failed: %v", err)
+			}
+			if _, err := driver.DeleteVolume(ctx, &csi.DeleteVolumeRequest{VolumeId: volumeId}); err != nil {
+				t.Fatalf("DeleteVolume failed: %v", err)
+			}
+			remaining, err := fakeCloud.ListAccessPoints(ctx, tc.expectedFsId)
+			if err != nil {
+				t.Fatalf("ListAccessPoints failed: %v", err)
+			}
+			if len(remaining) != len(accessPoints)-1 {
+				t.Fatalf("Access point of volume %v was not deleted from file system %v", volumeId, tc.expectedFsId)
+			}
+		})
+	}
+}
+
+// fillFileSystem creates count access points on a file system of the fake cloud
+func fillFileSystem(t *testing.T, fakeCloud *fake.Cloud, fileSystemId string, count int) {
+	for i := 0; i < count; i++ {
+		_, err := fakeCloud.CreateAccessPoint(context.Background(), fmt.Sprintf("%v-%d", fileSystemId, i), &cloud.AccessPointOptions{
+			FileSystemId:  fileSystemId,
+			DirectoryPath: fmt.Sprintf("/ap-%d", i),
+		}, false)
+		if err != nil {
+			t.Fatalf("Failed to create access point %d on %v: %v", i, fileSystemId, err)
+		}
+	}
+}
@@ -0,0 +1,191 @@
+package driver
+
+import (
+	"context"
+	"testing"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/kubernetes-sigs/aws-efs-csi-driver/pkg/cloud"
+	"github.com/kubernetes-sigs/aws-efs-csi-driver/pkg/cloud/fake"
+)
+
+func TestRootDirDrifted(t *testing.T) {
+	testCases := []struct {
+		name          string
+		existing      string
+		requested     string
+		uniqueRootDir bool
+		expected      bool
+	}{
+		{
+			name:      "Same root directory",
+			existing:  "/a/pvc-1",
+			requested: "/a/pvc-1",
+		},
+		{
+			name:      "Root directory under another base path",
+			existing:  "/a/pvc-1",
+			requested: "/b/pvc-1",
+			expected:  true,
+		},
+		{
+			name:          "Unique root directories with another suffix",
+			existing:      "/a/data-1111",
+			requested:     "/a/data-2222",
+			uniqueRootDir: true,
+		},
+		{
+			name:          "Unique root directories under another base path",
+			existing:      "/a/data-1111",
+			requested:     "/b/data-2222",
+			uniqueRootDir: true,
+			expected:      true,
+		},
+		{
+			name:      "Root directory of the access point unknown",
+			requested: "/a/pvc-1",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			accessPoint := &cloud.AccessPoint{AccessPointRootDir: tc.existing}
+			opts := &cloud.AccessPointOptions{DirectoryPath: tc.requested}
+			if drifted := rootDirDrifted(accessPoint, opts, tc.uniqueRootDir); drifted != tc.expected {
+				t.Fatalf("Drift mismatched. Expected: %v, Actual: %v", tc.expected, drifted)
+			}
+		})
+	}
+}
+
+func TestCreateVolumeParameterDrift(t *testing.T) {
+	var (
+		fsId       = "fs-abcd1234"
+		volumeName = "pvc-5f2ed5c1-1a43-4d2e-9a63-8d1a3f0c4b21"
+	)
+
+	testCases := []struct {
+		name string
+		// params of the first CreateVolume and of its retry, on top of the common parameters
+		params      map[string]string
+		retryParams map[string]string
+		// expectedRootDir is the root directory of the access point, empty when it is not checked
+		expectedRootDir string
+		expectedCode    codes.Code
+	}{
+		{
+			name:            "Success: Retry with the same parameters",
+			params:          map[string]string{BasePath: "/a"},
+			retryParams:     map[string]string{BasePath: "/a"},
+			expectedRootDir: "/a/" + volumeName,
+		},
+		{
+			name:            "Success: Retry after basePath changed returns the same volume",
+			params:          map[string]string{BasePath: "/a"},
+			retryParams:     map[string]string{BasePath: "/b"},
+			expectedRootDir: "/a/" + volumeName,
+		},
+		{
+			name:        "Success: Retry after basePath changed with a unique root directory returns the same volume",
+			params:      map[string]string{BasePath: "/a", SubPathPattern: "${.PVC.name}"},
+			retryParams: map[string]string{BasePath: "/b", SubPathPattern: "${.PVC.name}"},
+		},
+		{
+			name:            "Success: Retry after subPathPattern changed returns the same volume",
+			params:          map[string]string{SubPathPattern: "${.PVC.namespace}/${.PVC.name}", EnsureUniqueDirectory: "false"},
+			retryParams:     map[string]string{SubPathPattern: "${.PVC.name}", EnsureUniqueDirectory: "false"},
+			expectedRootDir: "/default/data",
+		},
+		{
+			name:         "Fail: Volume of another PVC with the same name under another base path",
+			params:       map[string]string{BasePath: "/a"},
+			retryParams:  map[string]string{BasePath: "/b", PvcName: "other"},
+			expectedCode: codes.AlreadyExists,
+		},
+		{
+			name:         "Fail: Retry after basePath changed without the PVC of the volume",
+			params:       map[string]string{BasePath: "/a", PvcName: "", PvcNamespace: ""},
+			retryParams:  map[string]string{BasePath: "/b", PvcName: "", PvcNamespace: ""},
+			expectedCode: codes.AlreadyExists,
+		},
+		{
+			name:         "Fail: Retry after the GID changed",
+			params:       map[string]string{BasePath: "/a", Gid: "1000"},
+			retryParams:  map[string]string{BasePath: "/a", Gid: "2000"},
+			expectedCode: codes.AlreadyExists,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			fakeCloud := fake.NewCloud()
+			fakeCloud.AddFileSystem(fsId)
+			driver := &Driver{
+				cloud:        fakeCloud,
+				gidAllocator: NewGidAllocator(),
+			}
+
+			createVolume := func(extraParams map[string]string) (*csi.CreateVolumeResponse, error) {
+				params := map[string]string{
+					ProvisioningMode: AccessPointMode,
+					FsId:             fsId,
+					DirectoryPerms:   "700",
+					Uid:              "1000",
+					Gid:              "1000",
+					PvcName:          "data",
+					PvcNamespace:     "default",
+				}
+				for k, v := range extraParams {
+					params[k] = v
+				}
+				return driver.CreateVolume(context.Background(), &csi.CreateVolumeRequest{
+					Name: volumeName,
+					VolumeCapabilities: []*csi.VolumeCapability{
+						{
+							AccessType: &csi.VolumeCapability_Mount{
+								Mount: &csi.VolumeCapability_MountVolume{},
+							},
+							AccessMode: &csi.VolumeCapability_AccessMode{
+								Mode: csi.VolumeCapability_AccessMode_MULTI_NODE_MULTI_WRITER,
+							},
+						},
+					},
+					CapacityRange: &csi.CapacityRange{RequiredBytes: 5 * 1024 * 1024 * 1024},
+					Parameters:    params,
+				})
+			}
+
+			res, err := createVolume(tc.params)
+			if err != nil {
+				t.Fatalf("CreateVolume failed: %v", err)
+			}
+			retryRes, err := createVolume(tc.retryParams)
+			if tc.expectedCode != codes.OK {
+				if status.Code(err) != tc.expectedCode {
+					t.Fatalf("Expected error code %v, got: %v", tc.expectedCode, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Retried CreateVolume failed: %v", err)
+			}
+			if retryRes.Volume.VolumeId != res.Volume.VolumeId {
+				t.Fatalf("Volume Id mismatched. Expected: %v, Actual: %v", res.Volume.VolumeId, retryRes.Volume.VolumeId)
+			}
+
+			accessPoints, err := fakeCloud.ListAccessPoints(context.Background(), fsId)
+			if err != nil {
+				t.Fatalf("ListAccessPoints failed: %v", err)
+			}
+			if len(accessPoints) != 1 {
+				t.Fatalf("Expected a single access point, got %v", len(accessPoints))
+			}
+			if tc.expectedRootDir != "" && accessPoints[0].AccessPointRootDir != tc.expectedRootDir {
+				t.Fatalf("Root directory mismatched. Expected: %v, Actual: %v", tc.expectedRootDir, accessPoints[0].AccessPointRootDir)
+			}
+		})
+	}
+}
@@ -0,0 +1,90 @@
+package driver
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"k8s.io/klog/v2"
+
+	"github.com/kubernetes-sigs/aws-efs-csi-driver/pkg/cloud"
+)
+
+const (
+	// preflightTimeout bounds each probe of the IAM permission check
+	preflightTimeout = 10 * time.Second
+	// preflightFileSystemId is a file system that does not exist. EFS authorizes a call before looking up the file
+	// system, so a call on it fails with FileSystemNotFound when permitted and with AccessDenied otherwise.
+	preflightFileSystemId = "fs-00000000"
+	// preflightClientToken is the client token of the access point probe, which is never created
+	preflightClientToken = "efs-csi-preflight"
+)
+
+// preflightProbe checks one IAM permission of the driver with an EFS API call that cannot modify any resource
+type preflightProbe struct {
+	permission string
+	probe      func(ctx context.Context, c cloud.Cloud) error
+}
+
+// preflightProbes returns the probes of the permissions the controller cannot provision access points without.
+// DeleteAccessPoint and TagResource are not probed: policies usually condition them on the tags of an existing
+// access point, which the probes cannot satisfy without creating one.
+func (d *Driver) preflightProbes() []preflightProbe {
+	tags := d.driverTags()
+	return []preflightProbe{
+		{
+			permission: "elasticfilesystem:DescribeFileSystems",
+			probe: func(ctx context.Context, c cloud.Cloud) error {
+				return c.CheckConnectivity(ctx)
+			},
+		},
+		{
+			permission: "elasticfilesystem:DescribeAccessPoints",
+			probe: func(ctx context.Context, c cloud.Cloud) error {
+				_, err := c.ListAccessPoints(ctx, preflightFileSystemId)
+				return err
+			},
+		},
+		{
+			// EFS has no dry run, the access point cannot be created on a file system that does not exist.
+			// The tags are the ones of provisioned access points, as policies may condition creation on them.
+			permission: "elasticfilesystem:CreateAccessPoint",
+			probe: func(ctx context.Context, c cloud.Cloud) error {
+				_, err := c.CreateAccessPoint(ctx, preflightClientToken, &cloud.AccessPointOptions{
+					FileSystemId:   preflightFileSystemId,
+					DirectoryPath:  "/",
+					DirectoryPerms: "700",
+					Tags:           tags,
+				}, false)
+				return err
+			},
+		},
+	}
+}
+
+// checkIamPermissions probes the IAM permissions of the driver when it starts with --preflight-iam-check, logging
+// every missing permission. It fails when a permission is denied. Probes failing for another reason, such as the
+// EFS API being unreachable, are only logged, as they do not tell whether the permission is granted.
+func (d *Driver) checkIamPermissions(ctx context.Context) error {
+	var missing []string
+	for _, p := range d.preflightProbes() {
+		probeCtx, cancel := context.WithTimeout(ctx, preflightTimeout)
+		err := p.probe(probeCtx, d.cloud)
+		cancel()
+		switch {
+		case err == nil || cloud.IsNotFound(err):
+			klog.V(4).Infof("Preflight IAM check: %v is granted", p.permission)
+		case cloud.IsAccessDenied(err):
+			klog.Errorf("Preflight IAM check: %v is missing: %v", p.permission, err)
+			missing = append(missing, p.permission)
+		default:
+			klog.Warningf("Preflight IAM check: could not verify %v: %v", p.permission, err)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("the driver is missing the IAM permissions %v, please check the IAM policy of its role", strings.Join(missing, ", "))
+	}
+	klog.Infof("Preflight IAM check passed")
+	return nil
+}
@@ -0,0 +1,98 @@
+package driver
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+
+	"github.com/kubernetes-sigs/aws-efs-csi-driver/pkg/cloud"
+	"github.com/kubernetes-sigs/aws-efs-csi-driver/pkg/driver/mocks"
+)
+
+func TestCheckIamPermissions(t *testing.T) {
+	var (
+		accessDenied = &cloud.Error{Kind: cloud.ErrAccessDenied, Err: errors.New("AccessDeniedException")}
+		notFound     = &cloud.Error{Kind: cloud.ErrNotFound, Err: errors.New("FileSystemNotFound")}
+	)
+
+	testCases := []struct {
+		name string
+		// Errors of the probes of DescribeFileSystems, DescribeAccessPoints and CreateAccessPoint
+		describeFsErr error
+		describeApErr error
+		createApErr   error
+		expectErr     bool
+	}{
+		{
+			name:          "Success: All permissions granted",
+			describeApErr: notFound,
+			createApErr:   notFound,
+		},
+		{
+			name:          "Fail: DescribeFileSystems denied",
+			describeFsErr: accessDenied,
+			describeApErr: notFound,
+			createApErr:   notFound,
+			expectErr:     true,
+		},
+		{
+			name:          "Fail: DescribeAccessPoints denied",
+			describeApErr: accessDenied,
+			createApErr:   notFound,
+			expectErr:     true,
+		},
+		{
+			name:          "Fail: CreateAccessPoint denied",
+			describeApErr: notFound,
+			createApErr:   accessDenied,
+			expectErr:     true,
+		},
+		{
+			name:          "Success: Unreachable EFS API is not a missing permission",
+			describeFsErr: errors.New("RequestError: send request failed"),
+			describeApErr: notFound,
+			createApErr:   notFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockCtl := gomock.NewController(t)
+			mockCloud := mocks.NewMockCloud(mockCtl)
+			driver := &Driver{
+				cloud:       mockCloud,
+				tags:        map[string]string{"team": "storage"},
+				clusterName: "cluster-a",
+			}
+
+			// The access point is requested with the tags of provisioned access points
+			expectedTags := map[string]string{DefaultTagKey: DefaultTagValue, "team": "storage", ClusterNameTagKey: "cluster-a"}
+			mockCloud.EXPECT().CheckConnectivity(gomock.Any()).Return(tc.describeFsErr)
+			mockCloud.EXPECT().ListAccessPoints(gomock.Any(), gomock.Eq(preflightFileSystemId)).Return(nil, tc.describeApErr)
+			mockCloud.EXPECT().CreateAccessPoint(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Eq(false)).DoAndReturn(
+				func(ctx context.Context, clientToken string, opts *cloud.AccessPointOptions, reuseAccessPoint bool) (*cloud.AccessPoint, error) {
+					if opts.FileSystemId != preflightFileSystemId {
+						t.Fatalf("Access point requested on file system %v, expected %v", opts.FileSystemId, preflightFileSystemId)
+					}
+					for k, v := range expectedTags {
+						if opts.Tags[k] != v {
+							t.Fatalf("Tag %v mismatched. Expected: %v, Actual: %v", k, v, opts.Tags[k])
+						}
+					}
+					return nil, tc.createApErr
+				})
+
+			err := driver.checkIamPermissions(context.Background())
+			if tc.expectErr {
+				if err == nil {
+					t.Fatalf("Expected an error")
+				}
+			} else if err != nil {
+				t.Fatalf("checkIamPermissions failed: %v", err)
+			}
+			mockCtl.Finish()
+		})
+	}
+}
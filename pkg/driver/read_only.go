@@ -0,0 +1,49 @@
+package driver
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+)
+
+// readerAccessModes are the access modes of volumes marked read-only by the readOnly parameter
+var readerAccessModes = []csi.VolumeCapability_AccessMode_Mode{
+	csi.VolumeCapability_AccessMode_MULTI_NODE_READER_ONLY,
+}
+
+// validateReaderAccessModes rejects the capabilities of read-only volumes which allow writes
+func validateReaderAccessModes(volCaps []*csi.VolumeCapability) error {
+	var writerModes []string
+	for _, c := range volCaps {
+		isReader := false
+		for _, m := range readerAccessModes {
+			if c.GetAccessMode().GetMode() == m {
+				isReader = true
+				break
+			}
+		}
+		if !isReader {
+			writerModes = append(writerModes, c.GetAccessMode().GetMode().String())
+		}
+	}
+	if len(writerModes) != 0 {
+		return fmt.Errorf("access mode %s allows writes to a read-only volume", strings.Join(writerModes, ","))
+	}
+	return nil
+}
+
+// isReadOnlyVolume returns whether the volume context marks the volume read-only
+func isReadOnlyVolume(volContext map[string]string) bool {
+	readOnly, err := strconv.ParseBool(volContext[ReadOnly])
+	return err == nil && readOnly
+}
+
+// setReadOnly records in the volume context that the volume must be mounted read-only
+func setReadOnly(volContext map[string]string, readOnly bool) {
+	if !readOnly {
+		return
+	}
+	volContext[ReadOnly] = "true"
+}
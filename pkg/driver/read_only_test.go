@@ -0,0 +1,202 @@
+package driver
+
+import (
+	"context"
+	"testing"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	"github.com/golang/mock/gomock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/kubernetes-sigs/aws-efs-csi-driver/pkg/cloud/fake"
+)
+
+func readOnlyTestVolCap(mode csi.VolumeCapability_AccessMode_Mode) *csi.VolumeCapability {
+	return &csi.VolumeCapability{
+		AccessType: &csi.VolumeCapability_Mount{
+			Mount: &csi.VolumeCapability_MountVolume{},
+		},
+		AccessMode: &csi.VolumeCapability_AccessMode{
+			Mode: mode,
+		},
+	}
+}
+
+func TestValidateVolumeCapabilitiesReadOnly(t *testing.T) {
+	var (
+		reader = readOnlyTestVolCap(csi.VolumeCapability_AccessMode_MULTI_NODE_READER_ONLY)
+		writer = readOnlyTestVolCap(csi.VolumeCapability_AccessMode_MULTI_NODE_MULTI_WRITER)
+		single = readOnlyTestVolCap(csi.VolumeCapability_AccessMode_SINGLE_NODE_WRITER)
+	)
+
+	testCases := []struct {
+		name              string
+		volContext        map[string]string
+		volCaps           []*csi.VolumeCapability
+		expectedConfirmed bool
+	}{
+		{
+			name:              "Success: Reader mode of a read-only volume is confirmed",
+			volContext:        map[string]string{ReadOnly: "true"},
+			volCaps:           []*csi.VolumeCapability{reader},
+			expectedConfirmed: true,
+		},
+		{
+			name:       "Success: Writer mode of a read-only volume is not confirmed",
+			volContext: map[string]string{ReadOnly: "true"},
+			volCaps:    []*csi.VolumeCapability{writer},
+		},
+		{
+			name:       "Success: Single node writer mode of a read-only volume is not confirmed",
+			volContext: map[string]string{ReadOnly: "true"},
+			volCaps:    []*csi.VolumeCapability{single},
+		},
+		{
+			name:       "Success: Reader and writer modes of a read-only volume are not confirmed",
+			volContext: map[string]string{ReadOnly: "true"},
+			volCaps:    []*csi.VolumeCapability{reader, writer},
+		},
+		{
+			name:              "Success: Writer mode of a writable volume is confirmed",
+			volContext:        map[string]string{ReadOnly: "false"},
+			volCaps:           []*csi.VolumeCapability{writer},
+			expectedConfirmed: true,
+		},
+		{
+			name:              "Success: Writer mode without volume context is confirmed",
+			volCaps:           []*csi.VolumeCapability{writer},
+			expectedConfirmed: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			driver := &Driver{}
+			res, err := driver.ValidateVolumeCapabilities(context.Background(), &csi.ValidateVolumeCapabilitiesRequest{
+				VolumeId:           "fs-abcd1234::fsap-abcd1234xyz987",
+				VolumeContext:      tc.volContext,
+				VolumeCapabilities: tc.volCaps,
+			})
+			if err != nil {
+				t.Fatalf("ValidateVolumeCapabilities failed: %v", err)
+			}
+			if confirmed := res.Confirmed != nil; confirmed != tc.expectedConfirmed {
+				t.Fatalf("Confirmation mismatched. Expected: %v, Actual: %v", tc.expectedConfirmed, confirmed)
+			}
+			if !tc.expectedConfirmed && res.Message == "" {
+				t.Fatalf("Expected a message for the unconfirmed capabilities")
+			}
+		})
+	}
+}
+
+func TestCreateVolumeReadOnly(t *testing.T) {
+	fsId := "fs-abcd1234"
+
+	testCases := []struct {
+		name         string
+		mode         csi.VolumeCapability_AccessMode_Mode
+		readOnly     string
+		expectedCode codes.Code
+	}{
+		{
+			name:         "Success: Read-only volume with the reader mode",
+			mode:         csi.VolumeCapability_AccessMode_MULTI_NODE_READER_ONLY,
+			readOnly:     "true",
+			expectedCode: codes.OK,
+		},
+		{
+			name:         "Fail: Read-only volume with a writer mode",
+			mode:         csi.VolumeCapability_AccessMode_MULTI_NODE_MULTI_WRITER,
+			readOnly:     "true",
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name:         "Fail: Invalid value",
+			mode:         csi.VolumeCapability_AccessMode_MULTI_NODE_READER_ONLY,
+			readOnly:     "yes please",
+			expectedCode: codes.InvalidArgument,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			fakeCloud := fake.NewCloud()
+			fakeCloud.AddFileSystem(fsId)
+			driver := &Driver{
+				cloud:        fakeCloud,
+				gidAllocator: NewGidAllocator(),
+			}
+
+			res, err := driver.CreateVolume(context.Background(), &csi.CreateVolumeRequest{
+				Name:               "pvc-1",
+				VolumeCapabilities: []*csi.VolumeCapability{readOnlyTestVolCap(tc.mode)},
+				CapacityRange:      &csi.CapacityRange{RequiredBytes: 5 * 1024 * 1024 * 1024},
+				Parameters: map[string]string{
+					ProvisioningMode: AccessPointMode,
+					FsId:             fsId,
+					DirectoryPerms:   "700",
+					ReadOnly:         tc.readOnly,
+				},
+			})
+			if status.Code(err) != tc.expectedCode {
+				t.Fatalf("Expected code %v, got: %v", tc.expectedCode, err)
+			}
+			if err == nil && res.Volume.VolumeContext[ReadOnly] != "true" {
+				t.Fatalf("Volume context mismatched. Expected %v=true, Actual: %v", ReadOnly, res.Volume.VolumeContext)
+			}
+		})
+	}
+}
+
+func TestNodePublishVolumeReadOnly(t *testing.T) {
+	testCases := []struct {
+		name      string
+		readOnly  string
+		expected  []string
+		expectErr bool
+	}{
+		{
+			name:     "Success: Read-only volume is mounted read-only",
+			readOnly: "true",
+			expected: []string{"tls", "ro"},
+		},
+		{
+			name:     "Success: Writable volume",
+			readOnly: "false",
+			expected: []string{"tls"},
+		},
+		{
+			name:      "Fail: Invalid value",
+			readOnly:  "yes please",
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			mockMounter, driver, ctx := setup(mockCtrl, NewVolStatter(), true)
+			if !tc.expectErr {
+				mockMounter.EXPECT().MakeDir(gomock.Eq(targetPath)).Return(nil)
+				mockMounter.EXPECT().Mount(volumeId+":/", targetPath, "efs", tc.expected).Return(nil)
+			}
+
+			_, err := driver.NodePublishVolume(ctx, &csi.NodePublishVolumeRequest{
+				VolumeId:         volumeId,
+				VolumeCapability: readOnlyTestVolCap(csi.VolumeCapability_AccessMode_MULTI_NODE_MULTI_WRITER),
+				TargetPath:       targetPath,
+				VolumeContext:    map[string]string{ReadOnly: tc.readOnly},
+			})
+			if tc.expectErr {
+				if status.Code(err) != codes.InvalidArgument {
+					t.Fatalf("Expected an InvalidArgument error, got: %v", err)
+				}
+			} else if err != nil {
+				t.Fatalf("NodePublishVolume failed: %v", err)
+			}
+			mockCtrl.Finish()
+		})
+	}
+}
@@ -0,0 +1,53 @@
+package driver
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go/service/efs"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/kubernetes-sigs/aws-efs-csi-driver/pkg/cloud"
+)
+
+// checkReplication fails with FailedPrecondition unless the file system is the source of a replication whose
+// destination is enabled. When destinationFsId is set, it must be that destination.
+func checkReplication(ctx context.Context, localCloud cloud.Cloud, fileSystemId, destinationFsId string) error {
+	replication, err := localCloud.DescribeReplicationConfiguration(ctx, fileSystemId)
+	if err != nil {
+		if cloud.IsNotFound(err) {
+			return status.Errorf(codes.FailedPrecondition, "File System %v has no replication configuration, which is required by %v", fileSystemId, RequireReplication)
+		}
+		if cloud.IsAccessDenied(err) {
+			return status.Errorf(codes.Unauthenticated, "Access Denied. Please ensure you have the right AWS permissions: %v", err)
+		}
+		return status.Errorf(codes.Internal, "Failed to describe the replication configuration of File System %v: %v", fileSystemId, err)
+	}
+	if replication.SourceFileSystemId != fileSystemId {
+		return status.Errorf(codes.FailedPrecondition, "File System %v is the destination of the replication of %v, not its source", fileSystemId, replication.SourceFileSystemId)
+	}
+
+	for _, destination := range replication.Destinations {
+		if destinationFsId != "" && destination.FileSystemId != destinationFsId {
+			continue
+		}
+		if destination.Status == efs.ReplicationStatusEnabled {
+			return nil
+		}
+		if destinationFsId != "" {
+			return status.Errorf(codes.FailedPrecondition, "Replication of File System %v to %v is %v, not %v", fileSystemId, destinationFsId, destination.Status, efs.ReplicationStatusEnabled)
+		}
+	}
+	if destinationFsId != "" {
+		return status.Errorf(codes.FailedPrecondition, "File System %v is not replicated to %v", fileSystemId, destinationFsId)
+	}
+	return status.Errorf(codes.FailedPrecondition, "File System %v has no %v replication destination", fileSystemId, efs.ReplicationStatusEnabled)
+}
+
+// setReplicationDestination records the destination of the replication of the file system in the volume context
+func setReplicationDestination(volContext map[string]string, destinationFsId string) {
+	if destinationFsId == "" {
+		return
+	}
+	volContext[ReplicationDestFsId] = destinationFsId
+}
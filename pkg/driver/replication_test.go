@@ -0,0 +1,163 @@
+package driver
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/service/efs"
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	"github.com/golang/mock/gomock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/kubernetes-sigs/aws-efs-csi-driver/pkg/cloud"
+	"github.com/kubernetes-sigs/aws-efs-csi-driver/pkg/cloud/fake"
+	"github.com/kubernetes-sigs/aws-efs-csi-driver/pkg/driver/mocks"
+)
+
+func TestCheckReplication(t *testing.T) {
+	var (
+		fsId      = "fs-abcd1234"
+		destFsId  = "fs-ef015678"
+		otherFsId = "fs-cdef9012"
+	)
+
+	testCases := []struct {
+		name         string
+		replication  *cloud.ReplicationConfiguration
+		mockError    error
+		destFsId     string
+		expectedCode codes.Code
+	}{
+		{
+			name: "Success: Enabled destination",
+			replication: &cloud.ReplicationConfiguration{
+				SourceFileSystemId: fsId,
+				Destinations:       []*cloud.ReplicationDestination{{FileSystemId: destFsId, Status: efs.ReplicationStatusEnabled}},
+			},
+			expectedCode: codes.OK,
+		},
+		{
+			name: "Success: Expected destination is enabled",
+			replication: &cloud.ReplicationConfiguration{
+				SourceFileSystemId: fsId,
+				Destinations:       []*cloud.ReplicationDestination{{FileSystemId: destFsId, Status: efs.ReplicationStatusEnabled}},
+			},
+			destFsId:     destFsId,
+			expectedCode: codes.OK,
+		},
+		{
+			name:         "Fail: Not replicated",
+			mockError:    cloud.ErrNotFound,
+			expectedCode: codes.FailedPrecondition,
+		},
+		{
+			name: "Fail: Destination is still enabling",
+			replication: &cloud.ReplicationConfiguration{
+				SourceFileSystemId: fsId,
+				Destinations:       []*cloud.ReplicationDestination{{FileSystemId: destFsId, Status: efs.ReplicationStatusEnabling}},
+			},
+			expectedCode: codes.FailedPrecondition,
+		},
+		{
+			name: "Fail: Replicated to another file system",
+			replication: &cloud.ReplicationConfiguration{
+				SourceFileSystemId: fsId,
+				Destinations:       []*cloud.ReplicationDestination{{FileSystemId: otherFsId, Status: efs.ReplicationStatusEnabled}},
+			},
+			destFsId:     destFsId,
+			expectedCode: codes.FailedPrecondition,
+		},
+		{
+			name: "Fail: File system is a destination",
+			replication: &cloud.ReplicationConfiguration{
+				SourceFileSystemId: otherFsId,
+				Destinations:       []*cloud.ReplicationDestination{{FileSystemId: fsId, Status: efs.ReplicationStatusEnabled}},
+			},
+			expectedCode: codes.FailedPrecondition,
+		},
+		{
+			name:         "Fail: Access Denied",
+			mockError:    cloud.ErrAccessDenied,
+			expectedCode: codes.Unauthenticated,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockCtl := gomock.NewController(t)
+			mockCloud := mocks.NewMockCloud(mockCtl)
+
+			ctx := context.Background()
+			mockCloud.EXPECT().DescribeReplicationConfiguration(gomock.Eq(ctx), gomock.Eq(fsId)).Return(tc.replication, tc.mockError)
+			err := checkReplication(ctx, mockCloud, fsId, tc.destFsId)
+			if status.Code(err) != tc.expectedCode {
+				t.Fatalf("Expected code %v, got: %v", tc.expectedCode, err)
+			}
+			mockCtl.Finish()
+		})
+	}
+}
+
+func TestCreateVolumeReplication(t *testing.T) {
+	var (
+		fsId     = "fs-abcd1234"
+		destFsId = "fs-ef015678"
+	)
+	fakeCloud := fake.NewCloud()
+	fakeCloud.AddFileSystem(fsId)
+	driver := &Driver{
+		cloud:        fakeCloud,
+		gidAllocator: NewGidAllocator(),
+	}
+
+	req := &csi.CreateVolumeRequest{
+		Name: "pvc-1",
+		VolumeCapabilities: []*csi.VolumeCapability{
+			{
+				AccessType: &csi.VolumeCapability_Mount{
+					Mount: &csi.VolumeCapability_MountVolume{},
+				},
+				AccessMode: &csi.VolumeCapability_AccessMode{
+					Mode: csi.VolumeCapability_AccessMode_MULTI_NODE_MULTI_WRITER,
+				},
+			},
+		},
+		CapacityRange: &csi.CapacityRange{RequiredBytes: 5 * 1024 * 1024 * 1024},
+		Parameters: map[string]string{
+			ProvisioningMode:    AccessPointMode,
+			FsId:                fsId,
+			DirectoryPerms:      "700",
+			ReplicationDestFsId: destFsId,
+			RequireReplication:  "true",
+		},
+	}
+
+	ctx := context.Background()
+	if _, err := driver.CreateVolume(ctx, req); status.Code(err) != codes.FailedPrecondition {
+		t.Fatalf("Expected a FailedPrecondition error, got: %v", err)
+	}
+
+	fakeCloud.SetReplication(fsId, &cloud.ReplicationDestination{FileSystemId: destFsId, Region: "us-west-2", Status: efs.ReplicationStatusEnabled})
+	res, err := driver.CreateVolume(ctx, req)
+	if err != nil {
+		t.Fatalf("CreateVolume failed: %v", err)
+	}
+	if res.Volume.VolumeContext[ReplicationDestFsId] != destFsId {
+		t.Fatalf("Volume context mismatched. Expected: %v, Actual: %v", destFsId, res.Volume.VolumeContext[ReplicationDestFsId])
+	}
+	accessPoints, err := fakeCloud.ListAccessPoints(ctx, fsId)
+	if err != nil {
+		t.Fatalf("ListAccessPoints failed: %v", err)
+	}
+	if len(accessPoints) != 1 || accessPoints[0].Tags[ReplicationDestTagKey] != destFsId {
+		t.Fatalf("Expected 1 access point tagged with %v, got %+v", destFsId, accessPoints)
+	}
+
+	// New file systems are not replicated
+	req.Name = "pvc-2"
+	req.Parameters[ProvisioningMode] = FileSystemMode
+	if _, err := driver.CreateVolume(ctx, req); status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("Expected an InvalidArgument error, got: %v", err)
+	}
+}
@@ -0,0 +1,88 @@
+package driver
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// GidRange is an inclusive range of GIDs
+type GidRange struct {
+	Min int64
+	Max int64
+}
+
+// GidRanges are the GIDs reserved by --reserved-gids, which are never allocated to access points
+type GidRanges []GidRange
+
+// ParseReservedGids parses a comma separated list of GIDs and inclusive GID ranges, e.g. 0-99,65534.
+// Overlapping and adjacent ranges are merged.
+func ParseReservedGids(value string) (GidRanges, error) {
+	ranges := GidRanges{}
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		minStr, maxStr, isRange := strings.Cut(entry, "-")
+		if !isRange {
+			maxStr = minStr
+		}
+		min, err := strconv.ParseInt(strings.TrimSpace(minStr), 10, 64)
+		if err != nil || min < 0 {
+			return nil, fmt.Errorf("invalid GID %q in %q: must be a non negative integer", minStr, entry)
+		}
+		max, err := strconv.ParseInt(strings.TrimSpace(maxStr), 10, 64)
+		if err != nil || max < 0 {
+			return nil, fmt.Errorf("invalid GID %q in %q: must be a non negative integer", maxStr, entry)
+		}
+		if min > max {
+			return nil, fmt.Errorf("invalid GID range %q: the start is greater than the end", entry)
+		}
+		ranges = append(ranges, GidRange{Min: min, Max: max})
+	}
+
+	sort.Slice(ranges, func(i, j int) bool {
+		return ranges[i].Min < ranges[j].Min
+	})
+	merged := GidRanges{}
+	for _, gidRange := range ranges {
+		if last := len(merged) - 1; last >= 0 && gidRange.Min <= merged[last].Max+1 {
+			if gidRange.Max > merged[last].Max {
+				merged[last].Max = gidRange.Max
+			}
+			continue
+		}
+		merged = append(merged, gidRange)
+	}
+	return merged, nil
+}
+
+// contains returns true if the GID is reserved
+func (r GidRanges) contains(gid int64) bool {
+	for _, gidRange := range r {
+		if gid >= gidRange.Min && gid <= gidRange.Max {
+			return true
+		}
+	}
+	return false
+}
+
+// Count returns the number of reserved GIDs in the inclusive range gidMin-gidMax
+func (r GidRanges) Count(gidMin, gidMax int64) int64 {
+	count := int64(0)
+	for _, gidRange := range r {
+		start, end := gidRange.Min, gidRange.Max
+		if start < gidMin {
+			start = gidMin
+		}
+		if end > gidMax {
+			end = gidMax
+		}
+		if start <= end {
+			count += end - start + 1
+		}
+	}
+	return count
+}
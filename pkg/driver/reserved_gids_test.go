@@ -0,0 +1,157 @@
+package driver
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/kubernetes-sigs/aws-efs-csi-driver/pkg/cloud"
+)
+
+func TestParseReservedGids(t *testing.T) {
+	testCases := []struct {
+		name      string
+		value     string
+		expected  GidRanges
+		expectErr bool
+	}{
+		{
+			name:     "Success: Empty",
+			value:    "",
+			expected: GidRanges{},
+		},
+		{
+			name:     "Success: GIDs and ranges",
+			value:    "0-99, 65534",
+			expected: GidRanges{{Min: 0, Max: 99}, {Min: 65534, Max: 65534}},
+		},
+		{
+			name:     "Success: Overlapping and adjacent ranges are merged",
+			value:    "50010-50020,50000-50015,50021,60000",
+			expected: GidRanges{{Min: 50000, Max: 50021}, {Min: 60000, Max: 60000}},
+		},
+		{
+			name:      "Fail: Start greater than end",
+			value:     "100-99",
+			expectErr: true,
+		},
+		{
+			name:      "Fail: Negative GID",
+			value:     "-1",
+			expectErr: true,
+		},
+		{
+			name:      "Fail: Not a number",
+			value:     "0-abc",
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ranges, err := ParseReservedGids(tc.value)
+			if tc.expectErr {
+				if err == nil {
+					t.Fatalf("ParseReservedGids did not fail, returned %v", ranges)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseReservedGids failed: %v", err)
+			}
+			if !reflect.DeepEqual(ranges, tc.expected) {
+				t.Fatalf("Ranges mismatched. Expected: %v, Actual: %v", tc.expected, ranges)
+			}
+		})
+	}
+}
+
+func TestGidRangesCount(t *testing.T) {
+	ranges := GidRanges{{Min: 0, Max: 99}, {Min: 50000, Max: 50009}, {Min: 65534, Max: 65534}}
+	testCases := []struct {
+		gidMin, gidMax int64
+		expected       int64
+	}{
+		{gidMin: 0, gidMax: 99, expected: 100},
+		{gidMin: 50, gidMax: 50004, expected: 55},
+		{gidMin: 100, gidMax: 49999, expected: 0},
+		{gidMin: 0, gidMax: 70000, expected: 111},
+	}
+	for _, tc := range testCases {
+		if count := ranges.Count(tc.gidMin, tc.gidMax); count != tc.expected {
+			t.Errorf("Count(%v, %v) mismatched. Expected: %v, Actual: %v", tc.gidMin, tc.gidMax, tc.expected, count)
+		}
+	}
+}
+
+func TestGetNextGidReservedGids(t *testing.T) {
+	fsId := "fs-abcd1234"
+
+	testCases := []struct {
+		name     string
+		testFunc func(t *testing.T)
+	}{
+		{
+			name: "Success: Reserved GIDs are never allocated",
+			testFunc: func(t *testing.T) {
+				gidAllocator := NewGidAllocator()
+				gidAllocator.reservedGids = GidRanges{{Min: 50000, Max: 50002}, {Min: 50005, Max: 50005}}
+
+				expected := []int64{50003, 50004, 50006, 50007}
+				for _, expectedGid := range expected {
+					gid, err := gidAllocator.getNextGid(fsId, nil, 50000, 50007)
+					if err != nil {
+						t.Fatalf("getNextGid failed: %v", err)
+					}
+					if gid != expectedGid {
+						t.Fatalf("GID mismatched. Expected: %v, Actual: %v", expectedGid, gid)
+					}
+				}
+				if _, err := gidAllocator.getNextGid(fsId, nil, 50000, 50007); err == nil {
+					t.Fatal("getNextGid did not fail with every unreserved GID in use")
+				}
+			},
+		},
+		{
+			name: "Fail: Every GID of the range is reserved",
+			testFunc: func(t *testing.T) {
+				gidAllocator := NewGidAllocator()
+				gidAllocator.reservedGids = GidRanges{{Min: 0, Max: 99}}
+
+				_, err := gidAllocator.getNextGid(fsId, nil, 10, 20)
+				if _, ok := err.(*GidRangeExhaustedError); !ok {
+					t.Fatalf("Expected a GidRangeExhaustedError, got: %v", err)
+				}
+			},
+		},
+		{
+			name: "Success: Limited range is extended by the reserved GIDs",
+			testFunc: func(t *testing.T) {
+				gidAllocator := NewGidAllocator()
+				gidAllocator.reservedGids = GidRanges{{Min: 1000, Max: 1009}}
+
+				// Every GID of the range limited to the access point limit is in use
+				accessPoints := []*cloud.AccessPoint{}
+				for gid := int64(1010); gid <= int64(1010+cloud.AccessPointPerFsLimit); gid++ {
+					accessPoints = append(accessPoints, &cloud.AccessPoint{PosixUser: &cloud.PosixUser{Gid: gid}})
+				}
+				_, err := gidAllocator.getNextGid(fsId, accessPoints, 1000, 10000)
+				if _, ok := err.(*GidRangeExhaustedError); !ok {
+					t.Fatalf("Expected a GidRangeExhaustedError, got: %v", err)
+				}
+
+				accessPoints = accessPoints[:len(accessPoints)-1]
+				gid, err := gidAllocator.getNextGid(fsId, accessPoints, 1000, 10000)
+				if err != nil {
+					t.Fatalf("getNextGid failed: %v", err)
+				}
+				if expectedGid := int64(1010 + cloud.AccessPointPerFsLimit); gid != expectedGid {
+					t.Fatalf("GID mismatched. Expected: %v, Actual: %v", expectedGid, gid)
+				}
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, tc.testFunc)
+	}
+}
@@ -0,0 +1,36 @@
+package driver
+
+import (
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// DefaultMaxRootDirLength is the default maximum length of the directory mounted by the nodes, the PATH_MAX of Linux
+// NFS clients minus its terminating null byte
+const DefaultMaxRootDirLength = 4095
+
+// validateRootDirLimits rejects the directory mounted by the nodes, the root directory of the access point joined with
+// accessPointSubPath, when it is longer than --max-root-dir-length, or deeper than --max-root-dir-depth when set.
+// Such directories would only fail at mount time.
+func (d *Driver) validateRootDirLimits(dir string) error {
+	maxLength := d.maxRootDirLength
+	if maxLength == 0 {
+		maxLength = DefaultMaxRootDirLength
+	}
+	if len(dir) > maxLength {
+		return status.Errorf(codes.InvalidArgument, "Directory %q is %v characters long, exceeding the maximum length %v set by --max-root-dir-length", dir, len(dir), maxLength)
+	}
+	if d.maxRootDirDepth == 0 {
+		return nil
+	}
+	depth := 0
+	if trimmed := strings.Trim(dir, "/"); trimmed != "" {
+		depth = strings.Count(trimmed, "/") + 1
+	}
+	if depth > d.maxRootDirDepth {
+		return status.Errorf(codes.InvalidArgument, "Directory %q is %v directories deep, exceeding the maximum depth %v set by --max-root-dir-depth", dir, depth, d.maxRootDirDepth)
+	}
+	return nil
+}
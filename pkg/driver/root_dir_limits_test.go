@@ -0,0 +1,134 @@
+package driver
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/kubernetes-sigs/aws-efs-csi-driver/pkg/cloud/fake"
+)
+
+func TestValidateRootDirLimits(t *testing.T) {
+	testCases := []struct {
+		name      string
+		maxLength int
+		maxDepth  int
+		dir       string
+		expectErr bool
+	}{
+		{
+			name: "Success: Root of the file system",
+			dir:  "/",
+		},
+		{
+			name: "Success: Default maximum length",
+			dir:  "/" + strings.Repeat("a", DefaultMaxRootDirLength-1),
+		},
+		{
+			name:      "Fail: Above the default maximum length",
+			dir:       "/" + strings.Repeat("a", DefaultMaxRootDirLength),
+			expectErr: true,
+		},
+		{
+			name:      "Success: Maximum length",
+			maxLength: 10,
+			dir:       "/tenant/pv",
+		},
+		{
+			name:      "Fail: Above the maximum length",
+			maxLength: 10,
+			dir:       "/tenant/pvc",
+			expectErr: true,
+		},
+		{
+			name:     "Success: Maximum depth",
+			maxDepth: 3,
+			dir:      "/tenant/pvc-1/data",
+		},
+		{
+			name:      "Fail: Above the maximum depth",
+			maxDepth:  3,
+			dir:       "/tenant/pvc-1/data/logs",
+			expectErr: true,
+		},
+		{
+			name: "Success: Depth unlimited",
+			dir:  "/a/b/c/d/e/f/g/h",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			driver := &Driver{
+				maxRootDirLength: tc.maxLength,
+				maxRootDirDepth:  tc.maxDepth,
+			}
+			err := driver.validateRootDirLimits(tc.dir)
+			if tc.expectErr {
+				if status.Code(err) != codes.InvalidArgument {
+					t.Fatalf("Expected an InvalidArgument error, got: %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("validateRootDirLimits failed: %v", err)
+			}
+		})
+	}
+}
+
+func TestCreateVolumeRootDirLimits(t *testing.T) {
+	fsId := "fs-abcd1234"
+	fakeCloud := fake.NewCloud()
+	fakeCloud.AddFileSystem(fsId)
+	driver := &Driver{
+		cloud:           fakeCloud,
+		gidAllocator:    NewGidAllocator(),
+		maxRootDirDepth: 4,
+	}
+
+	req := &csi.CreateVolumeRequest{
+		Name: "pvc-1",
+		VolumeCapabilities: []*csi.VolumeCapability{
+			{
+				AccessType: &csi.VolumeCapability_Mount{
+					Mount: &csi.VolumeCapability_MountVolume{},
+				},
+				AccessMode: &csi.VolumeCapability_AccessMode{
+					Mode: csi.VolumeCapability_AccessMode_MULTI_NODE_MULTI_WRITER,
+				},
+			},
+		},
+		CapacityRange: &csi.CapacityRange{RequiredBytes: 5 * 1024 * 1024 * 1024},
+		Parameters: map[string]string{
+			ProvisioningMode:   AccessPointMode,
+			FsId:               fsId,
+			DirectoryPerms:     "700",
+			BasePath:           "/tenant",
+			AccessPointSubPath: "/data/logs",
+		},
+	}
+
+	ctx := context.Background()
+	if _, err := driver.CreateVolume(ctx, req); err != nil {
+		t.Fatalf("CreateVolume failed: %v", err)
+	}
+
+	// The subdirectory counts, the root directory alone is within the EFS limits
+	req.Name = "pvc-2"
+	req.Parameters[AccessPointSubPath] = "/data/logs/app"
+	if _, err := driver.CreateVolume(ctx, req); status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("Expected an InvalidArgument error, got: %v", err)
+	}
+	accessPoints, err := fakeCloud.ListAccessPoints(ctx, fsId)
+	if err != nil {
+		t.Fatalf("ListAccessPoints failed: %v", err)
+	}
+	if len(accessPoints) != 1 {
+		t.Fatalf("Expected 1 access point, got %v", len(accessPoints))
+	}
+}
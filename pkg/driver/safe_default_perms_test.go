@@ -0,0 +1,114 @@
+package driver
+
+import (
+	"context"
+	"testing"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	"github.com/golang/mock/gomock"
+
+	"github.com/kubernetes-sigs/aws-efs-csi-driver/pkg/cloud"
+	"github.com/kubernetes-sigs/aws-efs-csi-driver/pkg/driver/mocks"
+)
+
+func TestValidateSafeDefaultPerms(t *testing.T) {
+	testCases := []struct {
+		value     string
+		expectErr bool
+	}{
+		{value: ""},
+		{value: "750"},
+		{value: "0750"},
+		{value: "0758", expectErr: true},
+		{value: "rwxr-x---", expectErr: true},
+		{value: "07500", expectErr: true},
+	}
+
+	for _, tc := range testCases {
+		err := ValidateSafeDefaultPerms(tc.value)
+		if tc.expectErr && err == nil {
+			t.Errorf("Expected an error for %q", tc.value)
+		}
+		if !tc.expectErr && err != nil {
+			t.Errorf("Unexpected error for %q: %v", tc.value, err)
+		}
+	}
+}
+
+func TestCreateVolumeSafeDefaultPerms(t *testing.T) {
+	var (
+		fsId = "fs-abcd1234"
+		apId = "fsap-abcd1234xyz987"
+	)
+
+	testCases := []struct {
+		name             string
+		safeDefaultPerms string
+		directoryPerms   string
+		expectedPerms    string
+	}{
+		{
+			name:             "Success: Default applied when directoryPerms is omitted",
+			safeDefaultPerms: "0750",
+			expectedPerms:    "0750",
+		},
+		{
+			name:             "Success: directoryPerms of the StorageClass wins over the default",
+			safeDefaultPerms: "0750",
+			directoryPerms:   "777",
+			expectedPerms:    "777",
+		},
+		{
+			name:          "Success: No default without the flag",
+			expectedPerms: "",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockCtl := gomock.NewController(t)
+			mockCloud := mocks.NewMockCloud(mockCtl)
+			driver := &Driver{
+				cloud:            mockCloud,
+				gidAllocator:     NewGidAllocator(),
+				safeDefaultPerms: tc.safeDefaultPerms,
+			}
+			req := &csi.CreateVolumeRequest{
+				Name: "pvc-1",
+				VolumeCapabilities: []*csi.VolumeCapability{
+					{
+						AccessType: &csi.VolumeCapability_Mount{
+							Mount: &csi.VolumeCapability_MountVolume{},
+						},
+						AccessMode: &csi.VolumeCapability_AccessMode{
+							Mode: csi.VolumeCapability_AccessMode_MULTI_NODE_MULTI_WRITER,
+						},
+					},
+				},
+				CapacityRange: &csi.CapacityRange{RequiredBytes: 5 * 1024 * 1024 * 1024},
+				Parameters: map[string]string{
+					ProvisioningMode: AccessPointMode,
+					FsId:             fsId,
+					Uid:              "1000",
+					Gid:              "1000",
+				},
+			}
+			if tc.directoryPerms != "" {
+				req.Parameters[DirectoryPerms] = tc.directoryPerms
+			}
+
+			mockCloud.EXPECT().DescribeFileSystem(gomock.Any(), gomock.Eq(fsId)).Return(&cloud.FileSystem{FileSystemId: fsId}, nil)
+			mockCloud.EXPECT().CreateAccessPoint(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+				func(ctx context.Context, clientToken string, accessPointOpts *cloud.AccessPointOptions, reuseAccessPoint bool) (*cloud.AccessPoint, error) {
+					if accessPointOpts.DirectoryPerms != tc.expectedPerms {
+						t.Fatalf("DirectoryPerms mismatched. Expected: %q, Actual: %q", tc.expectedPerms, accessPointOpts.DirectoryPerms)
+					}
+					return &cloud.AccessPoint{AccessPointId: apId, FileSystemId: fsId}, nil
+				})
+			if _, err := driver.CreateVolume(context.Background(), req); err != nil {
+				t.Fatalf("CreateVolume failed: %v", err)
+			}
+			mockCtl.Finish()
+		})
+	}
+}
@@ -80,6 +80,8 @@ func TestSanityEFSCSI(t *testing.T) {
 		volMetricsOptIn: true,
 		volStatter:      NewVolStatter(),
 		gidAllocator:    NewGidAllocator(),
+		// Cloned volumes are copied through a mount of the file system
+		tempMountPathPrefix: filepath.Join(dir, "mnt"),
 	}
 	defer func() {
 		if r := recover(); r != nil {
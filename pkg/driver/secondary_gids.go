@@ -0,0 +1,44 @@
+package driver
+
+import (
+	"strconv"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// maxSecondaryGids is the maximum number of secondary groups of the POSIX user of an access point allowed by EFS
+const maxSecondaryGids = 16
+
+// parseSecondaryGids parses the comma separated secondaryGids parameter. Each GID must be a non-negative integer not
+// above the maximum GID, and appear once.
+func parseSecondaryGids(value string, maxGid int64) ([]int64, error) {
+	if strings.TrimSpace(value) == "" {
+		return nil, nil
+	}
+	fields := strings.Split(value, ",")
+	if len(fields) > maxSecondaryGids {
+		return nil, status.Errorf(codes.InvalidArgument, "%v has %v GIDs, EFS allows at most %v", SecondaryGids, len(fields), maxSecondaryGids)
+	}
+	gids := make([]int64, 0, len(fields))
+	seen := map[int64]bool{}
+	for _, field := range fields {
+		gid, err := strconv.ParseInt(strings.TrimSpace(field), 10, 64)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "Failed to parse invalid %v: %v", SecondaryGids, err)
+		}
+		if gid < 0 {
+			return nil, status.Errorf(codes.InvalidArgument, "%v must be greater or equal than 0", SecondaryGids)
+		}
+		if gid > maxGid {
+			return nil, status.Errorf(codes.InvalidArgument, "%v %v exceeds the maximum GID %v", SecondaryGids, gid, maxGid)
+		}
+		if seen[gid] {
+			return nil, status.Errorf(codes.InvalidArgument, "%v contains GID %v more than once", SecondaryGids, gid)
+		}
+		seen[gid] = true
+		gids = append(gids, gid)
+	}
+	return gids, nil
+}
@@ -0,0 +1,132 @@
+package driver
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/kubernetes-sigs/aws-efs-csi-driver/pkg/cloud/fake"
+)
+
+func TestParseSecondaryGids(t *testing.T) {
+	testCases := []struct {
+		name      string
+		value     string
+		expected  []int64
+		expectErr bool
+	}{
+		{
+			name:  "Success: Empty",
+			value: "",
+		},
+		{
+			name:     "Success: GIDs",
+			value:    "2000, 2001,0",
+			expected: []int64{2000, 2001, 0},
+		},
+		{
+			name:     "Success: Maximum number of GIDs",
+			value:    "1,2,3,4,5,6,7,8,9,10,11,12,13,14,15,16",
+			expected: []int64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16},
+		},
+		{
+			name:      "Fail: Too many GIDs",
+			value:     "1,2,3,4,5,6,7,8,9,10,11,12,13,14,15,16,17",
+			expectErr: true,
+		},
+		{
+			name:      "Fail: Not a number",
+			value:     "2000,staff",
+			expectErr: true,
+		},
+		{
+			name:      "Fail: Negative GID",
+			value:     "-1",
+			expectErr: true,
+		},
+		{
+			name:      "Fail: GID above the maximum GID",
+			value:     "2000,2147483648",
+			expectErr: true,
+		},
+		{
+			name:      "Fail: Duplicate GID",
+			value:     "2000,2000",
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			gids, err := parseSecondaryGids(tc.value, DefaultMaxGid)
+			if tc.expectErr {
+				if status.Code(err) != codes.InvalidArgument {
+					t.Fatalf("Expected an InvalidArgument error, got: %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseSecondaryGids failed: %v", err)
+			}
+			if !reflect.DeepEqual(gids, tc.expected) {
+				t.Fatalf("GIDs mismatched. Expected: %v, Actual: %v", tc.expected, gids)
+			}
+		})
+	}
+}
+
+func TestCreateVolumeSecondaryGids(t *testing.T) {
+	fsId := "fs-abcd1234"
+	fakeCloud := fake.NewCloud()
+	fakeCloud.AddFileSystem(fsId)
+	driver := &Driver{
+		cloud:        fakeCloud,
+		gidAllocator: NewGidAllocator(),
+	}
+
+	req := &csi.CreateVolumeRequest{
+		Name: "pvc-1",
+		VolumeCapabilities: []*csi.VolumeCapability{
+			{
+				AccessType: &csi.VolumeCapability_Mount{
+					Mount: &csi.VolumeCapability_MountVolume{},
+				},
+				AccessMode: &csi.VolumeCapability_AccessMode{
+					Mode: csi.VolumeCapability_AccessMode_MULTI_NODE_MULTI_WRITER,
+				},
+			},
+		},
+		CapacityRange: &csi.CapacityRange{RequiredBytes: 5 * 1024 * 1024 * 1024},
+		Parameters: map[string]string{
+			ProvisioningMode: AccessPointMode,
+			FsId:             fsId,
+			DirectoryPerms:   "700",
+			SecondaryGids:    "2000,2001",
+		},
+	}
+
+	ctx := context.Background()
+	if _, err := driver.CreateVolume(ctx, req); err != nil {
+		t.Fatalf("CreateVolume failed: %v", err)
+	}
+	accessPoints, err := fakeCloud.ListAccessPoints(ctx, fsId)
+	if err != nil {
+		t.Fatalf("ListAccessPoints failed: %v", err)
+	}
+	if len(accessPoints) != 1 {
+		t.Fatalf("Expected 1 access point, got %v", len(accessPoints))
+	}
+	if expected := []int64{2000, 2001}; !reflect.DeepEqual(accessPoints[0].PosixUser.SecondaryGids, expected) {
+		t.Fatalf("SecondaryGids mismatched. Expected: %v, Actual: %v", expected, accessPoints[0].PosixUser.SecondaryGids)
+	}
+
+	req.Name = "pvc-2"
+	req.Parameters[SecondaryGids] = "2000,-1"
+	if _, err := driver.CreateVolume(ctx, req); status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("Expected an InvalidArgument error, got: %v", err)
+	}
+}
@@ -0,0 +1,167 @@
+package driver
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	"github.com/kubernetes-sigs/aws-efs-csi-driver/pkg/cloud"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"k8s.io/klog/v2"
+)
+
+// keyMutex serializes callers using the same key, without contention between different keys
+type keyMutex struct {
+	mu    sync.Mutex
+	locks map[string]*keyLock
+}
+
+type keyLock struct {
+	sync.Mutex
+	waiters int
+}
+
+// lock blocks until the key is free, and returns the function releasing it
+func (k *keyMutex) lock(key string) func() {
+	k.mu.Lock()
+	if k.locks == nil {
+		k.locks = make(map[string]*keyLock)
+	}
+	l, ok := k.locks[key]
+	if !ok {
+		l = &keyLock{}
+		k.locks[key] = l
+	}
+	l.waiters++
+	k.mu.Unlock()
+
+	l.Lock()
+	return func() {
+		l.Unlock()
+		k.mu.Lock()
+		l.waiters--
+		if l.waiters == 0 {
+			delete(k.locks, key)
+		}
+		k.mu.Unlock()
+	}
+}
+
+func sharedAccessPointLockKey(fileSystemId, shareKey string) string {
+	return fileSystemId + "/" + shareKey
+}
+
+// shareRef returns the reference a volume holds on a shared access point, derived from the name of the volume so that
+// retries of CreateVolume and DeleteVolume add and remove the same reference.
+func shareRef(volName string) string {
+	return get64LenHash(volName)[:16]
+}
+
+// shareRefTagKey returns the key of the tag recording the reference of a volume on a shared access point
+func shareRefTagKey(ref string) string {
+	return ShareRefTagKeyPrefix + ref
+}
+
+// getShareRefs returns the number of volumes referencing a shared access point
+func getShareRefs(accessPoint *cloud.AccessPoint) int {
+	refs := 0
+	for key := range accessPoint.Tags {
+		if strings.HasPrefix(key, ShareRefTagKeyPrefix) {
+			refs++
+		}
+	}
+	return refs
+}
+
+// createSharedAccessPoint returns the access point of the file system shared under shareKey, and adds the reference of
+// the volume to it, which is a no-op if the volume already holds it. The access point is created with the given options
+// if it does not exist yet.
+func (d *Driver) createSharedAccessPoint(ctx context.Context, localCloud cloud.Cloud, shareKey, volName string, accessPointOpts *cloud.AccessPointOptions) (*cloud.AccessPoint, error) {
+	unlock := d.sharedAccessPointLocks.lock(sharedAccessPointLockKey(accessPointOpts.FileSystemId, shareKey))
+	defer unlock()
+
+	accessPoints, err := localCloud.ListAccessPoints(ctx, accessPointOpts.FileSystemId)
+	if err != nil {
+		if cloud.IsAccessDenied(err) {
+			return nil, status.Errorf(codes.Unauthenticated, "Access Denied. Please ensure you have the right AWS permissions: %v", err)
+		}
+		return nil, status.Errorf(codes.Internal, "Failed to list Access Points of File System %v: %v", accessPointOpts.FileSystemId, err)
+	}
+
+	refKey := shareRefTagKey(shareRef(volName))
+	for _, accessPoint := range accessPoints {
+		if accessPoint == nil || accessPoint.Tags[ShareKeyTagKey] != shareKey {
+			continue
+		}
+		// A soft deleted access point lost its last reference
+		_, softDeleted := accessPoint.Tags[DeletedAtTagKey]
+		if _, ok := accessPoint.Tags[refKey]; ok && !softDeleted {
+			klog.V(2).Infof("Volume %v already references shared Access Point %v for share key %v", volName, accessPoint.AccessPointId, shareKey)
+			return accessPoint, nil
+		}
+		err = localCloud.TagAccessPoint(ctx, accessPoint.AccessPointId, map[string]string{
+			refKey: sanitizeTagValue(volName),
+		})
+		if err != nil {
+			if cloud.IsAccessDenied(err) {
+				return nil, status.Errorf(codes.Unauthenticated, "Access Denied. Please ensure you have the right AWS permissions: %v", err)
+			}
+			return nil, status.Errorf(codes.Internal, "Failed to add a reference to shared Access Point %v: %v", accessPoint.AccessPointId, err)
+		}
+		if softDeleted {
+			if err := d.undeleteAccessPoint(ctx, localCloud, accessPoint.AccessPointId); err != nil {
+				return nil, err
+			}
+		}
+		klog.V(2).Infof("Reusing shared Access Point %v for share key %v, %v references", accessPoint.AccessPointId, shareKey, getShareRefs(accessPoint)+1)
+		return accessPoint, nil
+	}
+
+	accessPointOpts.Tags[ShareKeyTagKey] = shareKey
+	accessPointOpts.Tags[refKey] = sanitizeTagValue(volName)
+	clientToken := get64LenHash(sharedAccessPointLockKey(accessPointOpts.FileSystemId, shareKey))
+	accessPoint, err := localCloud.CreateAccessPoint(ctx, clientToken, accessPointOpts, false)
+	if err != nil {
+		if cloud.IsAccessDenied(err) {
+			return nil, status.Errorf(codes.Unauthenticated, "Access Denied. Please ensure you have the right AWS permissions: %v", err)
+		}
+		return nil, status.Errorf(codes.Internal, "Failed to create shared Access point in File System %v : %v", accessPointOpts.FileSystemId, err)
+	}
+	klog.V(2).Infof("Created shared Access Point %v for share key %v", accessPoint.AccessPointId, shareKey)
+	return accessPoint, nil
+}
+
+// releaseSharedAccessPoint removes the reference of a volume to a shared access point and returns the number of remaining
+// references. Releasing a reference the access point does not hold, e.g. on a retry, only returns the count.
+// The caller must hold the lock of the share key, and delete the access point when no reference remains.
+func (d *Driver) releaseSharedAccessPoint(ctx context.Context, localCloud cloud.Cloud, accessPointId, ref string) (int, error) {
+	// Describe again under the lock, as the references may have changed
+	accessPoint, err := localCloud.DescribeAccessPoint(ctx, accessPointId)
+	if err != nil {
+		if cloud.IsAccessDenied(err) {
+			return 0, status.Errorf(codes.Unauthenticated, "Access Denied. Please ensure you have the right AWS permissions: %v", err)
+		}
+		if cloud.IsNotFound(err) {
+			return 0, nil
+		}
+		return 0, status.Errorf(codes.Internal, "Could not get describe Access Point: %v , error: %v", accessPointId, err)
+	}
+
+	refs := getShareRefs(accessPoint)
+	if _, ok := accessPoint.Tags[shareRefTagKey(ref)]; ref == "" || !ok {
+		klog.V(2).Infof("Shared Access Point %v holds no reference %q, %v references remain", accessPointId, ref, refs)
+		return refs, nil
+	}
+	// The last reference is removed too, as a retained or soft deleted access point may be shared again
+	refs--
+	err = localCloud.UntagAccessPoint(ctx, accessPointId, []string{shareRefTagKey(ref)})
+	if err != nil {
+		if cloud.IsAccessDenied(err) {
+			return 0, status.Errorf(codes.Unauthenticated, "Access Denied. Please ensure you have the right AWS permissions: %v", err)
+		}
+		return 0, status.Errorf(codes.Internal, "Failed to release a reference to shared Access Point %v: %v", accessPointId, err)
+	}
+	klog.V(2).Infof("Released a reference to shared Access Point %v, %v references remain", accessPointId, refs)
+	return refs, nil
+}
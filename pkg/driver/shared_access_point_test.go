@@ -0,0 +1,377 @@
+package driver
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	"github.com/golang/mock/gomock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/kubernetes-sigs/aws-efs-csi-driver/pkg/cloud"
+	"github.com/kubernetes-sigs/aws-efs-csi-driver/pkg/driver/mocks"
+)
+
+// fakeAccessPoints keeps the access points of the mocked cloud, so that concurrent calls observe each other
+type fakeAccessPoints struct {
+	mu           sync.Mutex
+	accessPoints map[string]*cloud.AccessPoint
+	created      int
+	deleted      int
+}
+
+func copyAccessPoint(ap *cloud.AccessPoint) *cloud.AccessPoint {
+	c := *ap
+	c.Tags = map[string]string{}
+	for k, v := range ap.Tags {
+		c.Tags[k] = v
+	}
+	return &c
+}
+
+func (f *fakeAccessPoints) expect(mockCloud *mocks.MockCloud) {
+	mockCloud.EXPECT().ListAccessPoints(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, fileSystemId string) ([]*cloud.AccessPoint, error) {
+			f.mu.Lock()
+			defer f.mu.Unlock()
+			accessPoints := []*cloud.AccessPoint{}
+			for _, ap := range f.accessPoints {
+				accessPoints = append(accessPoints, copyAccessPoint(ap))
+			}
+			return accessPoints, nil
+		}).AnyTimes()
+	mockCloud.EXPECT().CreateAccessPoint(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, clientToken string, accessPointOpts *cloud.AccessPointOptions, reuseAccessPoint bool) (*cloud.AccessPoint, error) {
+			f.mu.Lock()
+			defer f.mu.Unlock()
+			f.created++
+			ap := &cloud.AccessPoint{
+				AccessPointId: fmt.Sprintf("fsap-%d", f.created),
+				FileSystemId:  accessPointOpts.FileSystemId,
+				PosixUser:     &cloud.PosixUser{Uid: accessPointOpts.Uid, Gid: accessPointOpts.Gid},
+				Tags:          accessPointOpts.Tags,
+			}
+			f.accessPoints[ap.AccessPointId] = copyAccessPoint(ap)
+			return ap, nil
+		}).AnyTimes()
+	mockCloud.EXPECT().DescribeAccessPoint(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, accessPointId string) (*cloud.AccessPoint, error) {
+			f.mu.Lock()
+			defer f.mu.Unlock()
+			if ap, ok := f.accessPoints[accessPointId]; ok {
+				return copyAccessPoint(ap), nil
+			}
+			return nil, cloud.ErrNotFound
+		}).AnyTimes()
+	mockCloud.EXPECT().TagAccessPoint(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, accessPointId string, tags map[string]string) error {
+			f.mu.Lock()
+			defer f.mu.Unlock()
+			ap, ok := f.accessPoints[accessPointId]
+			if !ok {
+				return cloud.ErrNotFound
+			}
+			for k, v := range tags {
+				ap.Tags[k] = v
+			}
+			return nil
+		}).AnyTimes()
+	mockCloud.EXPECT().UntagAccessPoint(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, accessPointId string, tagKeys []string) error {
+			f.mu.Lock()
+			defer f.mu.Unlock()
+			ap, ok := f.accessPoints[accessPointId]
+			if !ok {
+				return cloud.ErrNotFound
+			}
+			for _, k := range tagKeys {
+				delete(ap.Tags, k)
+			}
+			return nil
+		}).AnyTimes()
+	mockCloud.EXPECT().DeleteAccessPoint(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, accessPointId string) error {
+			f.mu.Lock()
+			defer f.mu.Unlock()
+			if _, ok := f.accessPoints[accessPointId]; !ok {
+				return cloud.ErrNotFound
+			}
+			f.deleted++
+			delete(f.accessPoints, accessPointId)
+			return nil
+		}).AnyTimes()
+}
+
+func TestSharedAccessPoint(t *testing.T) {
+	var (
+		endpoint  = "endpoint"
+		fsId      = "fs-abcd1234"
+		shareKey  = "shared-data"
+		volumes   = 20
+		stdVolCap = &csi.VolumeCapability{
+			AccessType: &csi.VolumeCapability_Mount{
+				Mount: &csi.VolumeCapability_MountVolume{},
+			},
+			AccessMode: &csi.VolumeCapability_AccessMode{
+				Mode: csi.VolumeCapability_AccessMode_MULTI_NODE_MULTI_WRITER,
+			},
+		}
+	)
+
+	createRequest := func(i int, params map[string]string) *csi.CreateVolumeRequest {
+		parameters := map[string]string{
+			ProvisioningMode:    "efs-ap",
+			FsId:                fsId,
+			DirectoryPerms:      "777",
+			ReuseAccessPointKey: "true",
+			PvcNameKey:          fmt.Sprintf("pvc-%d", i),
+		}
+		for k, v := range params {
+			parameters[k] = v
+		}
+		return &csi.CreateVolumeRequest{
+			Name:               fmt.Sprintf("vol-%d", i),
+			VolumeCapabilities: []*csi.VolumeCapability{stdVolCap},
+			CapacityRange: &csi.CapacityRange{
+				RequiredBytes: 5 * 1024 * 1024 * 1024,
+			},
+			Parameters: parameters,
+		}
+	}
+
+	testCases := []struct {
+		name     string
+		testFunc func(t *testing.T)
+	}{
+		{
+			name: "Success: Concurrent create and delete of volumes sharing an access point",
+			testFunc: func(t *testing.T) {
+				mockCtl := gomock.NewController(t)
+				mockCloud := mocks.NewMockCloud(mockCtl)
+				fake := &fakeAccessPoints{accessPoints: map[string]*cloud.AccessPoint{}}
+				fake.expect(mockCloud)
+
+				driver := &Driver{
+					endpoint:     endpoint,
+					cloud:        mockCloud,
+					gidAllocator: NewGidAllocator(),
+				}
+
+				ctx := context.Background()
+				var wg sync.WaitGroup
+				volumeIds := make(chan string, volumes)
+				errs := make(chan error, volumes)
+				for i := 0; i < volumes; i++ {
+					wg.Add(1)
+					go func(i int) {
+						defer wg.Done()
+						res, err := driver.CreateVolume(ctx, createRequest(i, map[string]string{AccessPointShareKey: shareKey}))
+						if err != nil {
+							errs <- err
+							return
+						}
+						volumeIds <- res.Volume.VolumeId
+					}(i)
+				}
+				wg.Wait()
+				close(volumeIds)
+				close(errs)
+				for err := range errs {
+					t.Fatalf("CreateVolume failed: %v", err)
+				}
+
+				// Every volume gets its own volume ID of the same access point
+				var ids []string
+				accessPointIds := map[string]bool{}
+				for id := range volumeIds {
+					_, _, accessPointId, err := parseVolumeId(id)
+					if err != nil {
+						t.Fatalf("Invalid volume ID %v: %v", id, err)
+					}
+					accessPointIds[accessPointId] = true
+					ids = append(ids, id)
+				}
+				if len(accessPointIds) != 1 {
+					t.Fatalf("Expected the volumes to share 1 access point, got %v", accessPointIds)
+				}
+				if fake.created != 1 {
+					t.Fatalf("Expected 1 access point to be created, got %v", fake.created)
+				}
+				for _, ap := range fake.accessPoints {
+					if refs := getShareRefs(ap); refs != volumes {
+						t.Fatalf("Reference count mismatched. Expected: %v, Actual: %v", volumes, refs)
+					}
+				}
+
+				// Release all references but one concurrently, the access point must be kept
+				errs = make(chan error, volumes)
+				for _, id := range ids[:volumes-1] {
+					wg.Add(1)
+					go func(id string) {
+						defer wg.Done()
+						if _, err := driver.DeleteVolume(ctx, &csi.DeleteVolumeRequest{VolumeId: id}); err != nil {
+							errs <- err
+						}
+					}(id)
+				}
+				wg.Wait()
+				close(errs)
+				for err := range errs {
+					t.Fatalf("DeleteVolume failed: %v", err)
+				}
+				if fake.deleted != 0 || len(fake.accessPoints) != 1 {
+					t.Fatalf("Shared access point was deleted while still referenced")
+				}
+
+				if _, err := driver.DeleteVolume(ctx, &csi.DeleteVolumeRequest{VolumeId: ids[volumes-1]}); err != nil {
+					t.Fatalf("DeleteVolume failed: %v", err)
+				}
+				if fake.deleted != 1 || len(fake.accessPoints) != 0 {
+					t.Fatalf("Shared access point was not deleted with its last reference")
+				}
+				mockCtl.Finish()
+			},
+		},
+		{
+			name: "Success: Retried create and delete of a volume add and remove one reference",
+			testFunc: func(t *testing.T) {
+				mockCtl := gomock.NewController(t)
+				mockCloud := mocks.NewMockCloud(mockCtl)
+				fake := &fakeAccessPoints{accessPoints: map[string]*cloud.AccessPoint{}}
+				fake.expect(mockCloud)
+
+				driver := &Driver{
+					endpoint:     endpoint,
+					cloud:        mockCloud,
+					gidAllocator: NewGidAllocator(),
+				}
+
+				ctx := context.Background()
+				params := map[string]string{AccessPointShareKey: shareKey}
+				var volumeIds []string
+				for _, i := range []int{1, 1, 2} {
+					res, err := driver.CreateVolume(ctx, createRequest(i, params))
+					if err != nil {
+						t.Fatalf("CreateVolume failed: %v", err)
+					}
+					volumeIds = append(volumeIds, res.Volume.VolumeId)
+				}
+				if volumeIds[0] != volumeIds[1] {
+					t.Fatalf("Volume Id of the retry mismatched. Expected: %v, Actual: %v", volumeIds[0], volumeIds[1])
+				}
+				refs := func() int {
+					for _, ap := range fake.accessPoints {
+						return getShareRefs(ap)
+					}
+					return 0
+				}
+				if refs() != 2 {
+					t.Fatalf("Reference count mismatched. Expected: 2, Actual: %v", refs())
+				}
+
+				for i := 0; i < 2; i++ {
+					if _, err := driver.DeleteVolume(ctx, &csi.DeleteVolumeRequest{VolumeId: volumeIds[0]}); err != nil {
+						t.Fatalf("DeleteVolume failed: %v", err)
+					}
+				}
+				if fake.deleted != 0 || refs() != 1 {
+					t.Fatalf("Retried DeleteVolume released the reference of another volume, %v references remain", refs())
+				}
+
+				if _, err := driver.DeleteVolume(ctx, &csi.DeleteVolumeRequest{VolumeId: volumeIds[2]}); err != nil {
+					t.Fatalf("DeleteVolume failed: %v", err)
+				}
+				if fake.deleted != 1 || len(fake.accessPoints) != 0 {
+					t.Fatalf("Shared access point was not deleted with its last reference")
+				}
+				mockCtl.Finish()
+			},
+		},
+		{
+			name: "Success: Different share keys use different access points",
+			testFunc: func(t *testing.T) {
+				mockCtl := gomock.NewController(t)
+				mockCloud := mocks.NewMockCloud(mockCtl)
+				fake := &fakeAccessPoints{accessPoints: map[string]*cloud.AccessPoint{}}
+				fake.expect(mockCloud)
+
+				driver := &Driver{
+					endpoint:     endpoint,
+					cloud:        mockCloud,
+					gidAllocator: NewGidAllocator(),
+				}
+
+				ctx := context.Background()
+				res1, err := driver.CreateVolume(ctx, createRequest(1, map[string]string{AccessPointShareKey: "a"}))
+				if err != nil {
+					t.Fatalf("CreateVolume failed: %v", err)
+				}
+				res2, err := driver.CreateVolume(ctx, createRequest(2, map[string]string{AccessPointShareKey: "b"}))
+				if err != nil {
+					t.Fatalf("CreateVolume failed: %v", err)
+				}
+				if res1.Volume.VolumeId == res2.Volume.VolumeId {
+					t.Fatalf("Volumes with different share keys use the same access point %v", res1.Volume.VolumeId)
+				}
+				mockCtl.Finish()
+			},
+		},
+		{
+			name: "Success: GID allocated for a volume reusing a shared access point is released",
+			testFunc: func(t *testing.T) {
+				mockCtl := gomock.NewController(t)
+				mockCloud := mocks.NewMockCloud(mockCtl)
+				fake := &fakeAccessPoints{accessPoints: map[string]*cloud.AccessPoint{}}
+				fake.expect(mockCloud)
+
+				driver := &Driver{
+					endpoint:     endpoint,
+					cloud:        mockCloud,
+					gidAllocator: NewGidAllocator(),
+				}
+
+				ctx := context.Background()
+				for i := 1; i <= 2; i++ {
+					if _, err := driver.CreateVolume(ctx, createRequest(i, map[string]string{AccessPointShareKey: shareKey})); err != nil {
+						t.Fatalf("CreateVolume failed: %v", err)
+					}
+				}
+				// The GID of the shared access point is listed, the one allocated for the second volume is unused
+				if _, pending := driver.gidAllocator.trackedGids(fsId); len(pending) != 0 {
+					t.Fatalf("Expected no pending GID, got: %v", pending)
+				}
+				mockCtl.Finish()
+			},
+		},
+		{
+			name: "Fail: Share key requires reuseAccessPoint",
+			testFunc: func(t *testing.T) {
+				mockCtl := gomock.NewController(t)
+				mockCloud := mocks.NewMockCloud(mockCtl)
+
+				driver := &Driver{
+					endpoint:     endpoint,
+					cloud:        mockCloud,
+					gidAllocator: NewGidAllocator(),
+				}
+
+				ctx := context.Background()
+				_, err := driver.CreateVolume(ctx, createRequest(1, map[string]string{
+					ReuseAccessPointKey: "false",
+					AccessPointShareKey: shareKey,
+				}))
+				if status.Code(err) != codes.InvalidArgument {
+					t.Fatalf("Expected InvalidArgument, got: %v", err)
+				}
+				mockCtl.Finish()
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, tc.testFunc)
+	}
+}
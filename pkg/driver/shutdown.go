@@ -0,0 +1,67 @@
+package driver
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"google.golang.org/grpc"
+	"k8s.io/klog/v2"
+)
+
+// shutdownRollbackTimeout bounds the wait for the calls cancelled at the end of the shutdown timeout to roll back
+const shutdownRollbackTimeout = accessPointRollbackTimeout
+
+// cancelOnShutdown is a unary server interceptor cancelling the context of the call when the calls still in
+// flight at the end of the shutdown timeout are cancelled. The failing call then rolls back, e.g. deletes the
+// access point it created and releases its GID, instead of being killed mid-operation.
+func (d *Driver) cancelOnShutdown(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() {
+		select {
+		case <-d.inFlightCtx.Done():
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+	return handler(ctx, req)
+}
+
+// stopOnSignal stops the server gracefully on SIGTERM or SIGINT
+func (d *Driver) stopOnSignal() {
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, syscall.SIGTERM, syscall.SIGINT)
+	sig := <-sigs
+	klog.Infof("Received %v, shutting down", sig)
+	d.Stop()
+}
+
+// Stop stops accepting new calls and waits up to the shutdown timeout for the calls in flight to complete.
+// The calls still in flight are then cancelled, and get the time to roll back before the server stops.
+func (d *Driver) Stop() {
+	stopped := make(chan struct{})
+	go func() {
+		d.srv.GracefulStop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+		klog.Info("Server stopped, the calls in flight completed")
+		return
+	case <-time.After(d.shutdownTimeout):
+	}
+
+	klog.Warningf("Calls still in flight after the shutdown timeout of %v, cancelling them", d.shutdownTimeout)
+	d.cancelInFlight()
+	select {
+	case <-stopped:
+		klog.Info("Server stopped, the cancelled calls were rolled back")
+	case <-time.After(shutdownRollbackTimeout):
+		klog.Warningf("Calls still in flight after %v, stopping the server", shutdownRollbackTimeout)
+		d.srv.Stop()
+	}
+}
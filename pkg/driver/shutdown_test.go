@@ -0,0 +1,175 @@
+package driver
+
+import (
+	"context"
+	"net"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	"github.com/golang/mock/gomock"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+
+	"github.com/kubernetes-sigs/aws-efs-csi-driver/pkg/cloud"
+	"github.com/kubernetes-sigs/aws-efs-csi-driver/pkg/driver/mocks"
+)
+
+func TestStop(t *testing.T) {
+	var (
+		fsId      = "fs-abcd1234"
+		apId      = "fsap-abcd1234xyz987"
+		stdVolCap = &csi.VolumeCapability{
+			AccessType: &csi.VolumeCapability_Mount{
+				Mount: &csi.VolumeCapability_MountVolume{},
+			},
+			AccessMode: &csi.VolumeCapability_AccessMode{
+				Mode: csi.VolumeCapability_AccessMode_MULTI_NODE_MULTI_WRITER,
+			},
+		}
+		req = &csi.CreateVolumeRequest{
+			Name:               "name",
+			VolumeCapabilities: []*csi.VolumeCapability{stdVolCap},
+			CapacityRange:      &csi.CapacityRange{RequiredBytes: 5 * 1024 * 1024 * 1024},
+			Parameters: map[string]string{
+				ProvisioningMode: AccessPointMode,
+				FsId:             fsId,
+				DirectoryPerms:   "700",
+			},
+		}
+	)
+
+	// serve starts the server of the driver, and returns a client of its controller service
+	serve := func(t *testing.T, driver *Driver) csi.ControllerClient {
+		listener, err := net.Listen("unix", filepath.Join(t.TempDir(), "csi.sock"))
+		if err != nil {
+			t.Fatalf("Failed to listen: %v", err)
+		}
+		driver.newServer()
+		go driver.srv.Serve(listener)
+
+		conn, err := grpc.Dial("unix://"+listener.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+		if err != nil {
+			t.Fatalf("Failed to dial: %v", err)
+		}
+		t.Cleanup(func() { conn.Close() })
+		return csi.NewControllerClient(conn)
+	}
+
+	testCases := []struct {
+		name     string
+		testFunc func(t *testing.T)
+	}{
+		{
+			name: "Success: CreateVolume in flight completes",
+			testFunc: func(t *testing.T) {
+				mockCtl := gomock.NewController(t)
+				mockCloud := mocks.NewMockCloud(mockCtl)
+				driver := &Driver{
+					cloud:           mockCloud,
+					gidAllocator:    NewGidAllocator(),
+					shutdownTimeout: time.Minute,
+				}
+				client := serve(t, driver)
+
+				started, release := make(chan struct{}), make(chan struct{})
+				mockCloud.EXPECT().ListAccessPoints(gomock.Any(), gomock.Eq(fsId)).Return(nil, nil)
+				mockCloud.EXPECT().CreateAccessPoint(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+					func(ctx context.Context, clientToken string, accessPointOpts *cloud.AccessPointOptions, reuseAccessPoint bool) (*cloud.AccessPoint, error) {
+						close(started)
+						<-release
+						return &cloud.AccessPoint{AccessPointId: apId, FileSystemId: fsId}, nil
+					})
+
+				errs := make(chan error)
+				go func() {
+					_, err := client.CreateVolume(context.Background(), req)
+					errs <- err
+				}()
+				<-started
+
+				stopped := make(chan struct{})
+				go func() {
+					driver.Stop()
+					close(stopped)
+				}()
+				select {
+				case <-stopped:
+					t.Fatal("Stop returned while CreateVolume was in flight")
+				case <-time.After(100 * time.Millisecond):
+				}
+
+				close(release)
+				if err := <-errs; err != nil {
+					t.Fatalf("CreateVolume failed: %v", err)
+				}
+				<-stopped
+			},
+		},
+		{
+			name: "Success: CreateVolume in flight after the shutdown timeout rolls back",
+			testFunc: func(t *testing.T) {
+				mockCtl := gomock.NewController(t)
+				mockCloud := mocks.NewMockCloud(mockCtl)
+				driver := &Driver{
+					cloud:           mockCloud,
+					gidAllocator:    NewGidAllocator(),
+					shutdownTimeout: 100 * time.Millisecond,
+				}
+				client := serve(t, driver)
+
+				started := make(chan struct{})
+				mockCloud.EXPECT().ListAccessPoints(gomock.Any(), gomock.Eq(fsId)).Return(nil, nil)
+				mockCloud.EXPECT().CreateAccessPoint(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+					func(ctx context.Context, clientToken string, accessPointOpts *cloud.AccessPointOptions, reuseAccessPoint bool) (*cloud.AccessPoint, error) {
+						close(started)
+						<-ctx.Done()
+						return nil, ctx.Err()
+					})
+
+				errs := make(chan error)
+				go func() {
+					_, err := client.CreateVolume(context.Background(), req)
+					errs <- err
+				}()
+				<-started
+
+				start := time.Now()
+				driver.Stop()
+				if elapsed := time.Since(start); elapsed > 10*time.Second {
+					t.Fatalf("Stop did not cancel the call in flight, took %v", elapsed)
+				}
+				if err := <-errs; err == nil {
+					t.Fatal("CreateVolume did not fail")
+				}
+
+				// The GID allocated by the cancelled call is free
+				gid, err := driver.gidAllocator.getNextGid(fsId, nil, DefaultGidMin, DefaultGidMax)
+				if err != nil {
+					t.Fatalf("getNextGid failed: %v", err)
+				}
+				if gid != DefaultGidMin {
+					t.Fatalf("Expected GID %v to be released, got %v", DefaultGidMin, gid)
+				}
+			},
+		},
+		{
+			name: "Success: Stop without calls in flight returns immediately",
+			testFunc: func(t *testing.T) {
+				driver := &Driver{shutdownTimeout: time.Minute}
+				serve(t, driver)
+
+				start := time.Now()
+				driver.Stop()
+				if elapsed := time.Since(start); elapsed > 10*time.Second {
+					t.Fatalf("Stop waited for the shutdown timeout, took %v", elapsed)
+				}
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, tc.testFunc)
+	}
+}
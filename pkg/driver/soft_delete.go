@@ -0,0 +1,176 @@
+package driver
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/kubernetes-sigs/aws-efs-csi-driver/pkg/cloud"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"k8s.io/klog/v2"
+)
+
+// softDeleteReapInterval is the longest time between two purges of the expired soft deleted access points
+var softDeleteReapInterval = 10 * time.Minute
+
+// softDeletedFileSystem is a file system which may hold soft deleted access points, with the client reaching it
+type softDeletedFileSystem struct {
+	cloud   cloud.Cloud
+	roleArn string
+}
+
+// softDeleteTracker records the file systems checked by the soft delete reaper.
+// File systems are only known once a volume of them is created or deleted by this controller.
+type softDeleteTracker struct {
+	mu          sync.Mutex
+	fileSystems map[string]softDeletedFileSystem
+}
+
+func (t *softDeleteTracker) track(fileSystemId string, localCloud cloud.Cloud, roleArn string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.fileSystems == nil {
+		t.fileSystems = make(map[string]softDeletedFileSystem)
+	}
+	t.fileSystems[fileSystemId] = softDeletedFileSystem{cloud: localCloud, roleArn: roleArn}
+}
+
+func (t *softDeleteTracker) untrack(fileSystemId string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.fileSystems, fileSystemId)
+}
+
+func (t *softDeleteTracker) list() map[string]softDeletedFileSystem {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	fileSystems := make(map[string]softDeletedFileSystem, len(t.fileSystems))
+	for k, v := range t.fileSystems {
+		fileSystems[k] = v
+	}
+	return fileSystems
+}
+
+// softDeleteAccessPoint tags the access point with its deletion time instead of deleting it.
+// The time of a previous DeleteVolume call is kept, so that retries do not extend the grace period.
+func (d *Driver) softDeleteAccessPoint(ctx context.Context, localCloud cloud.Cloud, roleArn, fileSystemId, accessPointId string, tags map[string]string) error {
+	d.softDeletes.track(fileSystemId, localCloud, roleArn)
+	if _, ok := tags[DeletedAtTagKey]; ok {
+		klog.V(4).Infof("DeleteVolume: Access Point %v is already soft deleted", accessPointId)
+		return nil
+	}
+
+	err := localCloud.TagAccessPoint(ctx, accessPointId, map[string]string{
+		DeletedAtTagKey: d.currentTime().UTC().Format(time.RFC3339),
+	})
+	if err != nil {
+		if cloud.IsAccessDenied(err) {
+			return status.Errorf(codes.Unauthenticated, "Access Denied. Please ensure you have the right AWS permissions: %v", err)
+		}
+		if cloud.IsNotFound(err) {
+			klog.V(5).Infof("DeleteVolume: Access Point %v not found, returning success", accessPointId)
+			return nil
+		}
+		return status.Errorf(codes.Internal, "Failed to soft delete Access Point %v: %v", accessPointId, err)
+	}
+	klog.V(2).Infof("DeleteVolume: soft deleted Access Point %v, it is deleted after %v", accessPointId, d.softDeleteGrace)
+	return nil
+}
+
+// undeleteAccessPoint removes the deletion time of a soft deleted access point returned by CreateVolume
+func (d *Driver) undeleteAccessPoint(ctx context.Context, localCloud cloud.Cloud, accessPointId string) error {
+	accessPoint, err := localCloud.DescribeAccessPoint(ctx, accessPointId)
+	if err != nil {
+		if cloud.IsAccessDenied(err) {
+			return status.Errorf(codes.Unauthenticated, "Access Denied. Please ensure you have the right AWS permissions: %v", err)
+		}
+		return status.Errorf(codes.Internal, "Could not describe Access Point %v: %v", accessPointId, err)
+	}
+	if _, ok := accessPoint.Tags[DeletedAtTagKey]; !ok {
+		return nil
+	}
+	if err := localCloud.UntagAccessPoint(ctx, accessPointId, []string{DeletedAtTagKey}); err != nil {
+		if cloud.IsAccessDenied(err) {
+			return status.Errorf(codes.Unauthenticated, "Access Denied. Please ensure you have the right AWS permissions: %v", err)
+		}
+		return status.Errorf(codes.Internal, "Failed to recover soft deleted Access Point %v: %v", accessPointId, err)
+	}
+	klog.V(2).Infof("Recovered soft deleted Access Point %v", accessPointId)
+	return nil
+}
+
+// softDeleteExpired returns whether the soft deleted access point is past its grace period
+func (d *Driver) softDeleteExpired(accessPoint *cloud.AccessPoint, now time.Time) bool {
+	value, ok := accessPoint.Tags[DeletedAtTagKey]
+	if !ok {
+		return false
+	}
+	deletedAt, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		klog.Warningf("Ignoring soft deleted Access Point %v with invalid %v tag %q: %v", accessPoint.AccessPointId, DeletedAtTagKey, value, err)
+		return false
+	}
+	return now.Sub(deletedAt) >= d.softDeleteGrace
+}
+
+// purgeSoftDeletedAccessPoints permanently deletes the access points of a file system soft deleted more than the grace period ago
+func (d *Driver) purgeSoftDeletedAccessPoints(ctx context.Context, fileSystemId string, fs softDeletedFileSystem, now time.Time) {
+	accessPoints, err := fs.cloud.ListAccessPoints(ctx, fileSystemId)
+	if err != nil {
+		if cloud.IsNotFound(err) {
+			d.softDeletes.untrack(fileSystemId)
+			return
+		}
+		klog.Warningf("Failed to list Access Points of File System %v to purge soft deleted ones: %v", fileSystemId, err)
+		return
+	}
+
+	for _, accessPoint := range accessPoints {
+		if accessPoint == nil || !d.softDeleteExpired(accessPoint, now) {
+			continue
+		}
+		if shareKey, ok := accessPoint.Tags[ShareKeyTagKey]; ok {
+			unlock := d.sharedAccessPointLocks.lock(sharedAccessPointLockKey(fileSystemId, shareKey))
+			d.purgeSoftDeletedAccessPoint(ctx, fileSystemId, fs, accessPoint.AccessPointId, now)
+			unlock()
+		} else {
+			d.purgeSoftDeletedAccessPoint(ctx, fileSystemId, fs, accessPoint.AccessPointId, now)
+		}
+	}
+}
+
+func (d *Driver) purgeSoftDeletedAccessPoint(ctx context.Context, fileSystemId string, fs softDeletedFileSystem, accessPointId string, now time.Time) {
+	// Describe again, as a CreateVolume call may have recovered the access point since it was listed
+	accessPoint, err := fs.cloud.DescribeAccessPoint(ctx, accessPointId)
+	if err != nil {
+		if !cloud.IsNotFound(err) {
+			klog.Warningf("Failed to describe soft deleted Access Point %v: %v", accessPointId, err)
+		}
+		return
+	}
+	if !d.softDeleteExpired(accessPoint, now) {
+		return
+	}
+	if err := d.destroyAccessPoint(ctx, fs.cloud, fs.roleArn, fileSystemId, accessPoint); err != nil {
+		klog.Warningf("Failed to purge soft deleted Access Point %v: %v", accessPointId, err)
+		return
+	}
+	klog.V(2).Infof("Purged soft deleted Access Point %v of File System %v", accessPointId, fileSystemId)
+}
+
+// purgeExpiredSoftDeletes purges the expired soft deleted access points of every tracked file system
+func (d *Driver) purgeExpiredSoftDeletes() {
+	now := d.currentTime()
+	for fileSystemId, fs := range d.softDeletes.list() {
+		d.purgeSoftDeletedAccessPoints(context.Background(), fileSystemId, fs, now)
+	}
+}
+
+// softDeleteReapPeriod returns the period of the soft delete reaper, short enough to honor the grace period
+func (d *Driver) softDeleteReapPeriod() time.Duration {
+	if d.softDeleteGrace < softDeleteReapInterval {
+		return d.softDeleteGrace
+	}
+	return softDeleteReapInterval
+}
@@ -0,0 +1,233 @@
+package driver
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	"github.com/golang/mock/gomock"
+
+	"github.com/kubernetes-sigs/aws-efs-csi-driver/pkg/cloud"
+	"github.com/kubernetes-sigs/aws-efs-csi-driver/pkg/driver/mocks"
+)
+
+func TestSoftDelete(t *testing.T) {
+	var (
+		endpoint  = "endpoint"
+		fsId      = "fs-abcd1234"
+		apId      = "fsap-abcd1234xyz987"
+		volumeId  = "fs-abcd1234::fsap-abcd1234xyz987"
+		grace     = time.Hour
+		stdVolCap = &csi.VolumeCapability{
+			AccessType: &csi.VolumeCapability_Mount{
+				Mount: &csi.VolumeCapability_MountVolume{},
+			},
+			AccessMode: &csi.VolumeCapability_AccessMode{
+				Mode: csi.VolumeCapability_AccessMode_MULTI_NODE_MULTI_WRITER,
+			},
+		}
+	)
+
+	testCases := []struct {
+		name     string
+		testFunc func(t *testing.T)
+	}{
+		{
+			name: "Success: DeleteVolume tags the access point instead of deleting it",
+			testFunc: func(t *testing.T) {
+				mockCtl := gomock.NewController(t)
+				mockCloud := mocks.NewMockCloud(mockCtl)
+
+				now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+				driver := &Driver{
+					endpoint:        endpoint,
+					cloud:           mockCloud,
+					softDeleteGrace: grace,
+					now:             func() time.Time { return now },
+				}
+
+				ctx := context.Background()
+				accessPoint := &cloud.AccessPoint{
+					AccessPointId: apId,
+					FileSystemId:  fsId,
+				}
+				mockCloud.EXPECT().DescribeAccessPoint(gomock.Eq(ctx), gomock.Eq(apId)).Return(accessPoint, nil)
+				mockCloud.EXPECT().TagAccessPoint(gomock.Eq(ctx), gomock.Eq(apId), gomock.Eq(map[string]string{DeletedAtTagKey: "2024-01-01T12:00:00Z"})).Return(nil)
+
+				_, err := driver.DeleteVolume(ctx, &csi.DeleteVolumeRequest{VolumeId: volumeId})
+				if err != nil {
+					t.Fatalf("DeleteVolume failed: %v", err)
+				}
+				if _, ok := driver.softDeletes.list()[fsId]; !ok {
+					t.Fatalf("File System %v is not tracked by the soft delete reaper", fsId)
+				}
+				mockCtl.Finish()
+			},
+		},
+		{
+			name: "Success: DeleteVolume retry keeps the deletion time",
+			testFunc: func(t *testing.T) {
+				mockCtl := gomock.NewController(t)
+				mockCloud := mocks.NewMockCloud(mockCtl)
+
+				driver := &Driver{
+					endpoint:        endpoint,
+					cloud:           mockCloud,
+					softDeleteGrace: grace,
+				}
+
+				ctx := context.Background()
+				accessPoint := &cloud.AccessPoint{
+					AccessPointId: apId,
+					FileSystemId:  fsId,
+					Tags:          map[string]string{DeletedAtTagKey: time.Now().UTC().Format(time.RFC3339)},
+				}
+				mockCloud.EXPECT().DescribeAccessPoint(gomock.Eq(ctx), gomock.Eq(apId)).Return(accessPoint, nil)
+
+				_, err := driver.DeleteVolume(ctx, &csi.DeleteVolumeRequest{VolumeId: volumeId})
+				if err != nil {
+					t.Fatalf("DeleteVolume failed: %v", err)
+				}
+				mockCtl.Finish()
+			},
+		},
+		{
+			name: "Success: CreateVolume recovers a soft deleted access point",
+			testFunc: func(t *testing.T) {
+				mockCtl := gomock.NewController(t)
+				mockCloud := mocks.NewMockCloud(mockCtl)
+
+				driver := &Driver{
+					endpoint:        endpoint,
+					cloud:           mockCloud,
+					gidAllocator:    NewGidAllocator(),
+					softDeleteGrace: grace,
+				}
+
+				req := &csi.CreateVolumeRequest{
+					Name:               "volumeName",
+					VolumeCapabilities: []*csi.VolumeCapability{stdVolCap},
+					Parameters: map[string]string{
+						ProvisioningMode: "efs-ap",
+						FsId:             fsId,
+						DirectoryPerms:   "777",
+					},
+				}
+
+				ctx := context.Background()
+				accessPoint := &cloud.AccessPoint{
+					AccessPointId: apId,
+					FileSystemId:  fsId,
+					Tags:          map[string]string{DeletedAtTagKey: time.Now().UTC().Format(time.RFC3339)},
+				}
+				mockCloud.EXPECT().ListAccessPoints(gomock.Eq(ctx), gomock.Eq(fsId)).Return(nil, nil)
+				mockCloud.EXPECT().CreateAccessPoint(gomock.Eq(ctx), gomock.Eq("volumeName"), gomock.Any(), gomock.Eq(false)).Return(accessPoint, nil)
+				mockCloud.EXPECT().DescribeAccessPoint(gomock.Eq(ctx), gomock.Eq(apId)).Return(accessPoint, nil)
+				mockCloud.EXPECT().UntagAccessPoint(gomock.Eq(ctx), gomock.Eq(apId), gomock.Eq([]string{DeletedAtTagKey})).Return(nil)
+
+				res, err := driver.CreateVolume(ctx, req)
+				if err != nil {
+					t.Fatalf("CreateVolume failed: %v", err)
+				}
+				if res.Volume.VolumeId != volumeId {
+					t.Fatalf("Volume Id mismatched. Expected: %v, Actual: %v", volumeId, res.Volume.VolumeId)
+				}
+				mockCtl.Finish()
+			},
+		},
+		{
+			name: "Success: Reaper deletes the access points past the grace period",
+			testFunc: func(t *testing.T) {
+				mockCtl := gomock.NewController(t)
+				mockCloud := mocks.NewMockCloud(mockCtl)
+
+				driver := &Driver{
+					endpoint:        endpoint,
+					cloud:           mockCloud,
+					softDeleteGrace: grace,
+				}
+				driver.softDeletes.track(fsId, mockCloud, "")
+
+				now := time.Now()
+				expired := &cloud.AccessPoint{
+					AccessPointId: "fsap-expired",
+					FileSystemId:  fsId,
+					Tags:          map[string]string{DeletedAtTagKey: now.Add(-2 * grace).UTC().Format(time.RFC3339)},
+				}
+				recent := &cloud.AccessPoint{
+					AccessPointId: "fsap-recent",
+					FileSystemId:  fsId,
+					Tags:          map[string]string{DeletedAtTagKey: now.Add(-grace / 2).UTC().Format(time.RFC3339)},
+				}
+				live := &cloud.AccessPoint{
+					AccessPointId: "fsap-live",
+					FileSystemId:  fsId,
+					Tags:          map[string]string{},
+				}
+				mockCloud.EXPECT().ListAccessPoints(gomock.Any(), gomock.Eq(fsId)).Return([]*cloud.AccessPoint{expired, recent, live}, nil)
+				mockCloud.EXPECT().DescribeAccessPoint(gomock.Any(), gomock.Eq("fsap-expired")).Return(expired, nil)
+				mockCloud.EXPECT().DeleteAccessPoint(gomock.Any(), gomock.Eq("fsap-expired")).Return(nil)
+
+				driver.purgeExpiredSoftDeletes()
+				mockCtl.Finish()
+			},
+		},
+		{
+			name: "Success: Reaper keeps an access point recovered since it was listed",
+			testFunc: func(t *testing.T) {
+				mockCtl := gomock.NewController(t)
+				mockCloud := mocks.NewMockCloud(mockCtl)
+
+				driver := &Driver{
+					endpoint:        endpoint,
+					cloud:           mockCloud,
+					softDeleteGrace: grace,
+				}
+				driver.softDeletes.track(fsId, mockCloud, "")
+
+				expired := &cloud.AccessPoint{
+					AccessPointId: apId,
+					FileSystemId:  fsId,
+					Tags:          map[string]string{DeletedAtTagKey: time.Now().Add(-2 * grace).UTC().Format(time.RFC3339)},
+				}
+				recovered := &cloud.AccessPoint{
+					AccessPointId: apId,
+					FileSystemId:  fsId,
+					Tags:          map[string]string{},
+				}
+				mockCloud.EXPECT().ListAccessPoints(gomock.Any(), gomock.Eq(fsId)).Return([]*cloud.AccessPoint{expired}, nil)
+				mockCloud.EXPECT().DescribeAccessPoint(gomock.Any(), gomock.Eq(apId)).Return(recovered, nil)
+
+				driver.purgeExpiredSoftDeletes()
+				mockCtl.Finish()
+			},
+		},
+		{
+			name: "Success: Reaper forgets a deleted file system",
+			testFunc: func(t *testing.T) {
+				mockCtl := gomock.NewController(t)
+				mockCloud := mocks.NewMockCloud(mockCtl)
+
+				driver := &Driver{
+					endpoint:        endpoint,
+					cloud:           mockCloud,
+					softDeleteGrace: grace,
+				}
+				driver.softDeletes.track(fsId, mockCloud, "")
+
+				mockCloud.EXPECT().ListAccessPoints(gomock.Any(), gomock.Eq(fsId)).Return(nil, cloud.ErrNotFound)
+
+				driver.purgeExpiredSoftDeletes()
+				if len(driver.softDeletes.list()) != 0 {
+					t.Fatalf("Deleted File System %v is still tracked", fsId)
+				}
+				mockCtl.Finish()
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, tc.testFunc)
+	}
+}
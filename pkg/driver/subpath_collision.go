@@ -0,0 +1,43 @@
+package driver
+
+import (
+	"context"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"k8s.io/klog/v2"
+
+	"github.com/kubernetes-sigs/aws-efs-csi-driver/pkg/cloud"
+)
+
+// checkSubPathCollision fails with AlreadyExists when another access point of the file system has the root directory
+// of the access point about to be created, as a subPathPattern without a unique suffix may resolve to the same
+// directory for different volumes. The access points listed by the caller are used, if any.
+// The access point created by a previous attempt of the same request is not a collision.
+func (d *Driver) checkSubPathCollision(ctx context.Context, localCloud cloud.Cloud, clientToken string, accessPointOpts *cloud.AccessPointOptions, accessPoints []*cloud.AccessPoint) error {
+	if accessPoints == nil {
+		var err error
+		accessPoints, err = localCloud.ListAccessPoints(ctx, accessPointOpts.FileSystemId)
+		if err != nil {
+			if cloud.IsAccessDenied(err) {
+				return status.Errorf(codes.Unauthenticated, "Access Denied. Please ensure you have the right AWS permissions: %v", err)
+			}
+			return status.Errorf(codes.Internal, "Failed to list Access Points of File System %v: %v", accessPointOpts.FileSystemId, err)
+		}
+	}
+
+	for _, accessPoint := range accessPoints {
+		if accessPoint == nil || accessPoint.AccessPointRootDir != accessPointOpts.DirectoryPath {
+			continue
+		}
+		existing, err := localCloud.FindAccessPointByClientToken(ctx, clientToken, accessPointOpts)
+		if err == nil && existing != nil && existing.AccessPointId == accessPoint.AccessPointId {
+			klog.V(2).Infof("Access Point %v with root directory %q was created by a previous attempt of this request", accessPoint.AccessPointId, accessPointOpts.DirectoryPath)
+			return nil
+		}
+		return status.Errorf(codes.AlreadyExists, "Root directory %q is already used by Access Point %v of File System %v. "+
+			"Please use a %v producing distinct directories, or set %v to true", accessPointOpts.DirectoryPath, accessPoint.AccessPointId,
+			accessPointOpts.FileSystemId, SubPathPattern, EnsureUniqueDirectory)
+	}
+	return nil
+}
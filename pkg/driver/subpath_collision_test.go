@@ -0,0 +1,179 @@
+package driver
+
+import (
+	"context"
+	"testing"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/kubernetes-sigs/aws-efs-csi-driver/pkg/cloud"
+	"github.com/kubernetes-sigs/aws-efs-csi-driver/pkg/cloud/fake"
+)
+
+func TestCreateVolumeSubPathCollision(t *testing.T) {
+	var (
+		fsId      = "fs-abcd1234"
+		stdVolCap = &csi.VolumeCapability{
+			AccessType: &csi.VolumeCapability_Mount{
+				Mount: &csi.VolumeCapability_MountVolume{},
+			},
+			AccessMode: &csi.VolumeCapability_AccessMode{
+				Mode: csi.VolumeCapability_AccessMode_MULTI_NODE_MULTI_WRITER,
+			},
+		}
+	)
+
+	createRequest := func(name, pvcName, ensureUniqueDirectory string) *csi.CreateVolumeRequest {
+		return &csi.CreateVolumeRequest{
+			Name:               name,
+			VolumeCapabilities: []*csi.VolumeCapability{stdVolCap},
+			CapacityRange:      &csi.CapacityRange{RequiredBytes: 5 * 1024 * 1024 * 1024},
+			Parameters: map[string]string{
+				ProvisioningMode:      AccessPointMode,
+				FsId:                  fsId,
+				DirectoryPerms:        "700",
+				SubPathPattern:        "${.PVC.namespace}/${.PVC.name}",
+				EnsureUniqueDirectory: ensureUniqueDirectory,
+				PvcName:               pvcName,
+				PvcNamespace:          "default",
+			},
+		}
+	}
+
+	newDriver := func(detectSubPathCollisions bool) (*Driver, *fake.Cloud) {
+		fakeCloud := fake.NewCloud()
+		fakeCloud.AddFileSystem(fsId)
+		return &Driver{
+			cloud:                   fakeCloud,
+			gidAllocator:            NewGidAllocator(),
+			detectSubPathCollisions: detectSubPathCollisions,
+		}, fakeCloud
+	}
+
+	testCases := []struct {
+		name     string
+		testFunc func(t *testing.T)
+	}{
+		{
+			name: "Fail: Root directory is used by another access point",
+			testFunc: func(t *testing.T) {
+				driver, fakeCloud := newDriver(true)
+				ctx := context.Background()
+				if _, err := driver.CreateVolume(ctx, createRequest("pvc-1", "data", "false")); err != nil {
+					t.Fatalf("CreateVolume failed: %v", err)
+				}
+
+				_, err := driver.CreateVolume(ctx, createRequest("pvc-2", "data", "false"))
+				if status.Code(err) != codes.AlreadyExists {
+					t.Fatalf("Expected an AlreadyExists error, got: %v", err)
+				}
+				accessPoints, err := fakeCloud.ListAccessPoints(ctx, fsId)
+				if err != nil {
+					t.Fatalf("ListAccessPoints failed: %v", err)
+				}
+				if len(accessPoints) != 1 {
+					t.Fatalf("Expected 1 access point, got %v", len(accessPoints))
+				}
+			},
+		},
+		{
+			name: "Fail: Root directory is used by an access point created out of band",
+			testFunc: func(t *testing.T) {
+				driver, fakeCloud := newDriver(true)
+				ctx := context.Background()
+				// With a static GID, the access points are not listed before the check
+				_, err := fakeCloud.CreateAccessPoint(ctx, "out-of-band", &cloud.AccessPointOptions{
+					FileSystemId:  fsId,
+					Uid:           1000,
+					Gid:           1000,
+					DirectoryPath: "/default/data",
+				}, false)
+				if err != nil {
+					t.Fatalf("CreateAccessPoint failed: %v", err)
+				}
+
+				req := createRequest("pvc-1", "data", "false")
+				req.Parameters[Uid] = "1000"
+				req.Parameters[Gid] = "1000"
+				_, err = driver.CreateVolume(ctx, req)
+				if status.Code(err) != codes.AlreadyExists {
+					t.Fatalf("Expected an AlreadyExists error, got: %v", err)
+				}
+			},
+		},
+		{
+			name: "Success: Root directories are distinct",
+			testFunc: func(t *testing.T) {
+				driver, fakeCloud := newDriver(true)
+				ctx := context.Background()
+				for _, pvcName := range []string{"data", "logs"} {
+					if _, err := driver.CreateVolume(ctx, createRequest("pvc-"+pvcName, pvcName, "false")); err != nil {
+						t.Fatalf("CreateVolume failed: %v", err)
+					}
+				}
+				accessPoints, err := fakeCloud.ListAccessPoints(ctx, fsId)
+				if err != nil {
+					t.Fatalf("ListAccessPoints failed: %v", err)
+				}
+				if len(accessPoints) != 2 {
+					t.Fatalf("Expected 2 access points, got %v", len(accessPoints))
+				}
+			},
+		},
+		{
+			name: "Success: Retry of the request creating the access point",
+			testFunc: func(t *testing.T) {
+				driver, _ := newDriver(true)
+				ctx := context.Background()
+				res, err := driver.CreateVolume(ctx, createRequest("pvc-1", "data", "false"))
+				if err != nil {
+					t.Fatalf("CreateVolume failed: %v", err)
+				}
+				retry, err := driver.CreateVolume(ctx, createRequest("pvc-1", "data", "false"))
+				if err != nil {
+					t.Fatalf("CreateVolume retry failed: %v", err)
+				}
+				if retry.Volume.VolumeId != res.Volume.VolumeId {
+					t.Fatalf("Volume Id mismatched. Expected: %v, Actual: %v", res.Volume.VolumeId, retry.Volume.VolumeId)
+				}
+			},
+		},
+		{
+			name: "Success: Unique directories are not checked",
+			testFunc: func(t *testing.T) {
+				driver, _ := newDriver(true)
+				ctx := context.Background()
+				for _, name := range []string{"pvc-1", "pvc-2"} {
+					if _, err := driver.CreateVolume(ctx, createRequest(name, "data", "true")); err != nil {
+						t.Fatalf("CreateVolume failed: %v", err)
+					}
+				}
+			},
+		},
+		{
+			name: "Success: Collisions are not detected by default",
+			testFunc: func(t *testing.T) {
+				driver, fakeCloud := newDriver(false)
+				ctx := context.Background()
+				for _, name := range []string{"pvc-1", "pvc-2"} {
+					if _, err := driver.CreateVolume(ctx, createRequest(name, "data", "false")); err != nil {
+						t.Fatalf("CreateVolume failed: %v", err)
+					}
+				}
+				accessPoints, err := fakeCloud.ListAccessPoints(ctx, fsId)
+				if err != nil {
+					t.Fatalf("ListAccessPoints failed: %v", err)
+				}
+				if len(accessPoints) != 2 {
+					t.Fatalf("Expected 2 access points, got %v", len(accessPoints))
+				}
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, tc.testFunc)
+	}
+}
@@ -0,0 +1,45 @@
+package driver
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// reservedTagKeyPrefix is the prefix of the tag keys reserved by AWS
+const reservedTagKeyPrefix = "aws:"
+
+// normalizeTags validates the tags of the EFS resources created by a volume against the restrictions of AWS tags,
+// after trimming the surrounding whitespace of their keys and values. Keys which become identical are merged
+// when their values are the same, as they would be the same tag.
+func normalizeTags(tags map[string]string) (map[string]string, error) {
+	normalized := make(map[string]string, len(tags))
+	for k, v := range tags {
+		key, value := strings.TrimSpace(k), strings.TrimSpace(v)
+		if key == "" {
+			return nil, status.Errorf(codes.InvalidArgument, "Tag %q has an empty key", k)
+		}
+		if utf8.RuneCountInString(key) > maxTagKeyLength {
+			return nil, status.Errorf(codes.InvalidArgument, "Tag key %q is longer than %v characters", key, maxTagKeyLength)
+		}
+		if utf8.RuneCountInString(value) > maxTagValueLength {
+			return nil, status.Errorf(codes.InvalidArgument, "Value of tag %q is longer than %v characters", key, maxTagValueLength)
+		}
+		if strings.HasPrefix(strings.ToLower(key), reservedTagKeyPrefix) {
+			return nil, status.Errorf(codes.InvalidArgument, "Tag key %q uses the prefix %q reserved by AWS", key, reservedTagKeyPrefix)
+		}
+		if invalidTagChars.MatchString(key) {
+			return nil, status.Errorf(codes.InvalidArgument, "Tag key %q contains characters not allowed by AWS. Allowed characters are letters, numbers, spaces and _.:/=+-@", key)
+		}
+		if invalidTagChars.MatchString(value) {
+			return nil, status.Errorf(codes.InvalidArgument, "Value %q of tag %q contains characters not allowed by AWS. Allowed characters are letters, numbers, spaces and _.:/=+-@", value, key)
+		}
+		if existing, ok := normalized[key]; ok && existing != value {
+			return nil, status.Errorf(codes.InvalidArgument, "Tag %q is set twice with the values %q and %q", key, existing, value)
+		}
+		normalized[key] = value
+	}
+	return normalized, nil
+}
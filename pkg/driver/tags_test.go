@@ -0,0 +1,237 @@
+package driver
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	"github.com/golang/mock/gomock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/kubernetes-sigs/aws-efs-csi-driver/pkg/cloud"
+	"github.com/kubernetes-sigs/aws-efs-csi-driver/pkg/driver/mocks"
+)
+
+func TestNormalizeTags(t *testing.T) {
+	testCases := []struct {
+		name      string
+		tags      map[string]string
+		expected  map[string]string
+		expectErr string
+	}{
+		{
+			name:     "Success: Valid tags",
+			tags:     map[string]string{DefaultTagKey: DefaultTagValue, "team": "storage@example.com", "Name": "default/pvc 1", "é": "ü"},
+			expected: map[string]string{DefaultTagKey: DefaultTagValue, "team": "storage@example.com", "Name": "default/pvc 1", "é": "ü"},
+		},
+		{
+			name:     "Success: Whitespace is trimmed",
+			tags:     map[string]string{" env ": " prod "},
+			expected: map[string]string{"env": "prod"},
+		},
+		{
+			name:     "Success: Empty value",
+			tags:     map[string]string{"env": ""},
+			expected: map[string]string{"env": ""},
+		},
+		{
+			name:     "Success: Identical tags are merged",
+			tags:     map[string]string{"env": "prod", "env ": "prod"},
+			expected: map[string]string{"env": "prod"},
+		},
+		{
+			name:     "Success: Maximum lengths",
+			tags:     map[string]string{strings.Repeat("k", maxTagKeyLength): strings.Repeat("é", maxTagValueLength)},
+			expected: map[string]string{strings.Repeat("k", maxTagKeyLength): strings.Repeat("é", maxTagValueLength)},
+		},
+		{
+			name:      "Fail: Conflicting duplicate keys",
+			tags:      map[string]string{"env": "prod", " env": "dev"},
+			expectErr: `Tag "env" is set twice`,
+		},
+		{
+			name:      "Fail: Empty key",
+			tags:      map[string]string{" ": "value"},
+			expectErr: "has an empty key",
+		},
+		{
+			name:      "Fail: Key too long",
+			tags:      map[string]string{strings.Repeat("k", maxTagKeyLength+1): "value"},
+			expectErr: "is longer than 128 characters",
+		},
+		{
+			name:      "Fail: Value too long",
+			tags:      map[string]string{"key": strings.Repeat("é", maxTagValueLength+1)},
+			expectErr: `Value of tag "key" is longer than 256 characters`,
+		},
+		{
+			name:      "Fail: Reserved prefix",
+			tags:      map[string]string{"AWS:cloudformation": "stack"},
+			expectErr: `Tag key "AWS:cloudformation" uses the prefix "aws:" reserved by AWS`,
+		},
+		{
+			name:      "Fail: Invalid character in key",
+			tags:      map[string]string{"cost#center": "42"},
+			expectErr: `Tag key "cost#center" contains characters not allowed by AWS`,
+		},
+		{
+			name:      "Fail: Invalid character in value",
+			tags:      map[string]string{"owner": "team*"},
+			expectErr: `Value "team*" of tag "owner" contains characters not allowed by AWS`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tags, err := normalizeTags(tc.tags)
+			if tc.expectErr != "" {
+				if status.Code(err) != codes.InvalidArgument || !strings.Contains(err.Error(), tc.expectErr) {
+					t.Fatalf("Expected an InvalidArgument error containing %q, got: %v", tc.expectErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("normalizeTags failed: %v", err)
+			}
+			if !reflect.DeepEqual(tags, tc.expected) {
+				t.Fatalf("Tags mismatched. Expected: %v, Actual: %v", tc.expected, tags)
+			}
+		})
+	}
+}
+
+func TestCreateVolumeInvalidTags(t *testing.T) {
+	mockCtl := gomock.NewController(t)
+	mockCloud := mocks.NewMockCloud(mockCtl)
+	driver := &Driver{
+		cloud:        mockCloud,
+		gidAllocator: NewGidAllocator(),
+		tags:         map[string]string{"cost#center": "42"},
+	}
+
+	// The request fails before any AWS call
+	_, err := driver.CreateVolume(context.Background(), &csi.CreateVolumeRequest{
+		Name: "name",
+		VolumeCapabilities: []*csi.VolumeCapability{
+			{
+				AccessType: &csi.VolumeCapability_Mount{
+					Mount: &csi.VolumeCapability_MountVolume{},
+				},
+				AccessMode: &csi.VolumeCapability_AccessMode{
+					Mode: csi.VolumeCapability_AccessMode_MULTI_NODE_MULTI_WRITER,
+				},
+			},
+		},
+		CapacityRange: &csi.CapacityRange{RequiredBytes: 5 * 1024 * 1024 * 1024},
+		Parameters: map[string]string{
+			ProvisioningMode: AccessPointMode,
+			FsId:             "fs-abcd1234",
+			DirectoryPerms:   "700",
+		},
+	})
+	if status.Code(err) != codes.InvalidArgument || !strings.Contains(err.Error(), "cost#center") {
+		t.Fatalf("Expected an InvalidArgument error naming the tag, got: %v", err)
+	}
+}
+
+func TestCreateVolumeDefaultTagValue(t *testing.T) {
+	var (
+		fsId = "fs-abcd1234"
+		apId = "fsap-abcd1234xyz987"
+	)
+
+	testCases := []struct {
+		name               string
+		value              string
+		disableDefaultTags bool
+		expectedCode       codes.Code
+	}{
+		{
+			name:  "Success: Custom value",
+			value: "prod-cluster-1",
+		},
+		{
+			name:         "Fail: Empty value",
+			value:        " ",
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name:         "Fail: Value with characters not allowed by AWS",
+			value:        "prod#1",
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name:               "Fail: Default tag disabled",
+			value:              "prod-cluster-1",
+			disableDefaultTags: true,
+			expectedCode:       codes.InvalidArgument,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockCtl := gomock.NewController(t)
+			mockCloud := mocks.NewMockCloud(mockCtl)
+			driver := &Driver{
+				cloud:              mockCloud,
+				gidAllocator:       NewGidAllocator(),
+				disableDefaultTags: tc.disableDefaultTags,
+				tags:               map[string]string{"team": "storage"},
+			}
+			req := &csi.CreateVolumeRequest{
+				Name: "pvc-1",
+				VolumeCapabilities: []*csi.VolumeCapability{
+					{
+						AccessType: &csi.VolumeCapability_Mount{
+							Mount: &csi.VolumeCapability_MountVolume{},
+						},
+						AccessMode: &csi.VolumeCapability_AccessMode{
+							Mode: csi.VolumeCapability_AccessMode_MULTI_NODE_MULTI_WRITER,
+						},
+					},
+				},
+				CapacityRange: &csi.CapacityRange{RequiredBytes: 5 * 1024 * 1024 * 1024},
+				Parameters: map[string]string{
+					ProvisioningMode:     AccessPointMode,
+					FsId:                 fsId,
+					DirectoryPerms:       "700",
+					Uid:                  "1000",
+					Gid:                  "1000",
+					DefaultTagValueParam: tc.value,
+				},
+			}
+
+			if tc.expectedCode == codes.OK {
+				mockCloud.EXPECT().DescribeFileSystem(gomock.Any(), gomock.Eq(fsId)).Return(&cloud.FileSystem{FileSystemId: fsId}, nil)
+				mockCloud.EXPECT().CreateAccessPoint(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+					func(ctx context.Context, clientToken string, accessPointOpts *cloud.AccessPointOptions, reuseAccessPoint bool) (*cloud.AccessPoint, error) {
+						if accessPointOpts.Tags[DefaultTagKey] != tc.value || accessPointOpts.Tags["team"] != "storage" {
+							t.Fatalf("Unexpected tags %v", accessPointOpts.Tags)
+						}
+						return &cloud.AccessPoint{AccessPointId: apId, FileSystemId: fsId}, nil
+					})
+			}
+			_, err := driver.CreateVolume(context.Background(), req)
+			if status.Code(err) != tc.expectedCode {
+				t.Fatalf("Expected %v, got: %v", tc.expectedCode, err)
+			}
+			mockCtl.Finish()
+		})
+	}
+}
+
+func TestHasDriverTagsCustomTagValue(t *testing.T) {
+	driver := &Driver{}
+	// Resources are recognized by the key of the default tag, whatever its value
+	for _, value := range []string{DefaultTagValue, "prod-cluster-1", ""} {
+		if !driver.hasDriverTags(map[string]string{DefaultTagKey: value}) {
+			t.Errorf("Resource with the %v tag %q is not recognized", DefaultTagKey, value)
+		}
+	}
+	if driver.hasDriverTags(map[string]string{ClusterNameTagKey: "prod-cluster-1"}) {
+		t.Errorf("Resource without the %v tag is recognized", DefaultTagKey)
+	}
+}
@@ -0,0 +1,63 @@
+package driver
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ParseTenantBasePaths parses the comma separated --tenant-base-paths value, a list of namespace=prefix entries,
+// e.g. team-a=/team-a,team-b=/teams/b. Prefixes must be absolute and free of '..', and are cleaned.
+// It returns nil when the value is empty, allowing every namespace to provision anywhere.
+func ParseTenantBasePaths(value string) (map[string]string, error) {
+	var prefixes map[string]string
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		namespace, prefix, ok := strings.Cut(entry, "=")
+		namespace, prefix = strings.TrimSpace(namespace), strings.TrimSpace(prefix)
+		if !ok || namespace == "" || prefix == "" {
+			return nil, fmt.Errorf("invalid entry %q: must be namespace=prefix", entry)
+		}
+		if !strings.HasPrefix(prefix, "/") {
+			return nil, fmt.Errorf("invalid prefix %q of namespace %v: must be an absolute path", prefix, namespace)
+		}
+		for _, segment := range strings.Split(prefix, "/") {
+			if segment == ".." {
+				return nil, fmt.Errorf("invalid prefix %q of namespace %v: must not contain '..'", prefix, namespace)
+			}
+		}
+		if prefixes == nil {
+			prefixes = map[string]string{}
+		}
+		if _, ok := prefixes[namespace]; ok {
+			return nil, fmt.Errorf("namespace %v is set more than once", namespace)
+		}
+		prefixes[namespace] = path.Clean(prefix)
+	}
+	return prefixes, nil
+}
+
+// checkTenantBasePath rejects the root directory of an access point which is not under the prefix assigned to the
+// namespace of the PVC by --tenant-base-paths. Namespaces without a prefix cannot provision access points.
+func (d *Driver) checkTenantBasePath(namespace, rootDir string) error {
+	if d.tenantBasePaths == nil {
+		return nil
+	}
+	if namespace == "" {
+		return status.Errorf(codes.PermissionDenied, "Provisioning without the %v metadata is denied by --tenant-base-paths. Please run the csi-provisioner with --extra-create-metadata", PvcNamespace)
+	}
+	prefix, ok := d.tenantBasePaths[namespace]
+	if !ok {
+		return status.Errorf(codes.PermissionDenied, "Namespace %v has no base path assigned by --tenant-base-paths", namespace)
+	}
+	if prefix != "/" && rootDir != prefix && !strings.HasPrefix(rootDir, prefix+"/") {
+		return status.Errorf(codes.PermissionDenied, "Directory %q is outside of the base path %q assigned to namespace %v", rootDir, prefix, namespace)
+	}
+	return nil
+}
@@ -0,0 +1,157 @@
+package driver
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/kubernetes-sigs/aws-efs-csi-driver/pkg/cloud/fake"
+)
+
+func TestParseTenantBasePaths(t *testing.T) {
+	testCases := []struct {
+		name      string
+		value     string
+		expected  map[string]string
+		expectErr bool
+	}{
+		{
+			name:  "Success: Empty",
+			value: "",
+		},
+		{
+			name:     "Success: Prefixes",
+			value:    "team-a=/team-a, team-b = /teams/b/",
+			expected: map[string]string{"team-a": "/team-a", "team-b": "/teams/b"},
+		},
+		{
+			name:      "Fail: Missing prefix",
+			value:     "team-a",
+			expectErr: true,
+		},
+		{
+			name:      "Fail: Relative prefix",
+			value:     "team-a=team-a",
+			expectErr: true,
+		},
+		{
+			name:      "Fail: Prefix escaping its directory",
+			value:     "team-a=/team-a/../team-b",
+			expectErr: true,
+		},
+		{
+			name:      "Fail: Duplicate namespace",
+			value:     "team-a=/team-a,team-a=/teams/a",
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			prefixes, err := ParseTenantBasePaths(tc.value)
+			if tc.expectErr {
+				if err == nil {
+					t.Fatalf("Expected an error, got prefixes %v", prefixes)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseTenantBasePaths failed: %v", err)
+			}
+			if !reflect.DeepEqual(prefixes, tc.expected) {
+				t.Fatalf("Prefixes mismatched. Expected: %v, Actual: %v", tc.expected, prefixes)
+			}
+		})
+	}
+}
+
+func TestCreateVolumeTenantBasePaths(t *testing.T) {
+	fsId := "fs-abcd1234"
+
+	testCases := []struct {
+		name         string
+		params       map[string]string
+		expectedCode codes.Code
+	}{
+		{
+			name:         "Success: Base path under the prefix of the namespace",
+			params:       map[string]string{PvcNamespace: "team-a", BasePath: "/team-a/data"},
+			expectedCode: codes.OK,
+		},
+		{
+			name:         "Success: Sub path pattern under the prefix of the namespace",
+			params:       map[string]string{PvcNamespace: "team-a", BasePath: "/team-a", SubPathPattern: "${.PVC.namespace}/${.PVC.name}", PvcName: "data"},
+			expectedCode: codes.OK,
+		},
+		{
+			name:         "Fail: Base path of another namespace",
+			params:       map[string]string{PvcNamespace: "team-a", BasePath: "/team-b"},
+			expectedCode: codes.PermissionDenied,
+		},
+		{
+			name:         "Fail: Base path sharing the prefix name",
+			params:       map[string]string{PvcNamespace: "team-a", BasePath: "/team-ab"},
+			expectedCode: codes.PermissionDenied,
+		},
+		{
+			name:         "Fail: No base path",
+			params:       map[string]string{PvcNamespace: "team-a"},
+			expectedCode: codes.PermissionDenied,
+		},
+		{
+			name:         "Fail: Namespace without a prefix",
+			params:       map[string]string{PvcNamespace: "team-c", BasePath: "/team-a"},
+			expectedCode: codes.PermissionDenied,
+		},
+		{
+			name:         "Fail: Missing namespace metadata",
+			params:       map[string]string{BasePath: "/team-a"},
+			expectedCode: codes.PermissionDenied,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			fakeCloud := fake.NewCloud()
+			fakeCloud.AddFileSystem(fsId)
+			driver := &Driver{
+				cloud:           fakeCloud,
+				gidAllocator:    NewGidAllocator(),
+				tenantBasePaths: map[string]string{"team-a": "/team-a", "team-b": "/team-b"},
+			}
+
+			params := map[string]string{
+				ProvisioningMode: AccessPointMode,
+				FsId:             fsId,
+				DirectoryPerms:   "700",
+			}
+			for k, v := range tc.params {
+				params[k] = v
+			}
+			req := &csi.CreateVolumeRequest{
+				Name: "pvc-1",
+				VolumeCapabilities: []*csi.VolumeCapability{
+					{
+						AccessType: &csi.VolumeCapability_Mount{
+							Mount: &csi.VolumeCapability_MountVolume{},
+						},
+						AccessMode: &csi.VolumeCapability_AccessMode{
+							Mode: csi.VolumeCapability_AccessMode_MULTI_NODE_MULTI_WRITER,
+						},
+					},
+				},
+				CapacityRange: &csi.CapacityRange{RequiredBytes: 5 * 1024 * 1024 * 1024},
+				Parameters:    params,
+			}
+
+			_, err := driver.CreateVolume(context.Background(), req)
+			if status.Code(err) != tc.expectedCode {
+				t.Fatalf("Expected code %v, got: %v", tc.expectedCode, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package driver
+
+import (
+	"context"
+	"sort"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	"k8s.io/klog/v2"
+
+	"github.com/kubernetes-sigs/aws-efs-csi-driver/pkg/cloud"
+)
+
+const (
+	// TopologyZoneKey is the topology key of the availability zone of nodes and volumes
+	TopologyZoneKey = "topology.efs.csi.aws.com/zone"
+)
+
+// nodeTopology returns the topology of the node, or nil when its availability zone is unknown
+func (d *Driver) nodeTopology() *csi.Topology {
+	zone := d.cloud.GetMetadata().GetAvailabilityZone()
+	if zone == "" {
+		return nil
+	}
+	return &csi.Topology{Segments: map[string]string{TopologyZoneKey: zone}}
+}
+
+// volumeTopology returns the zones a volume is accessible from: the zone the volume is pinned to,
+// or the zones of the available mount targets of its file system, a single one for One Zone file systems.
+// It returns nil, making the volume accessible from every node, when topology is disabled, when the zones
+// of the file system cannot be compared with the zones of the nodes, or when they cannot be listed.
+func (d *Driver) volumeTopology(ctx context.Context, localCloud cloud.Cloud, fileSystemId, azName, region, roleArn string) []*csi.Topology {
+	if !d.volumeTopologyEnabled {
+		return nil
+	}
+	// Zone names of other regions and accounts do not match the zones of the nodes
+	if region != "" || roleArn != "" {
+		return nil
+	}
+	if azName != "" {
+		return []*csi.Topology{{Segments: map[string]string{TopologyZoneKey: azName}}}
+	}
+
+	mountTargets, err := localCloud.ListMountTargets(ctx, fileSystemId)
+	if err != nil {
+		klog.Warningf("Failed to list mount targets of file system %v, the volume is accessible from every zone: %v", fileSystemId, err)
+		return nil
+	}
+	zones := map[string]bool{}
+	for _, mountTarget := range mountTargets {
+		zones[mountTarget.AZName] = true
+	}
+	if len(zones) == 0 {
+		klog.Warningf("File system %v has no available mount target, the volume is accessible from every zone", fileSystemId)
+		return nil
+	}
+
+	topology := []*csi.Topology{}
+	for zone := range zones {
+		topology = append(topology, &csi.Topology{Segments: map[string]string{TopologyZoneKey: zone}})
+	}
+	sort.Slice(topology, func(i, j int) bool {
+		return topology[i].Segments[TopologyZoneKey] < topology[j].Segments[TopologyZoneKey]
+	})
+	return topology
+}
@@ -0,0 +1,175 @@
+package driver
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	"github.com/golang/mock/gomock"
+
+	"github.com/kubernetes-sigs/aws-efs-csi-driver/pkg/cloud"
+	"github.com/kubernetes-sigs/aws-efs-csi-driver/pkg/driver/mocks"
+)
+
+func zoneTopology(zones ...string) []*csi.Topology {
+	topology := []*csi.Topology{}
+	for _, zone := range zones {
+		topology = append(topology, &csi.Topology{Segments: map[string]string{TopologyZoneKey: zone}})
+	}
+	return topology
+}
+
+func TestVolumeTopology(t *testing.T) {
+	var (
+		fsId    = "fs-abcd1234"
+		roleArn = "arn:aws:iam::1234567890:role/EFSCrossAccountRole"
+	)
+
+	testCases := []struct {
+		name             string
+		disabled         bool
+		azName           string
+		region           string
+		roleArn          string
+		mountTargets     []*cloud.MountTarget
+		mountTargetsErr  error
+		expectListing    bool
+		expectedTopology []*csi.Topology
+	}{
+		{
+			name:             "Success: One Zone file system",
+			mountTargets:     []*cloud.MountTarget{{AZName: "us-east-1a"}},
+			expectListing:    true,
+			expectedTopology: zoneTopology("us-east-1a"),
+		},
+		{
+			name: "Success: Regional file system",
+			mountTargets: []*cloud.MountTarget{
+				{AZName: "us-east-1c"},
+				{AZName: "us-east-1a"},
+				{AZName: "us-east-1b"},
+			},
+			expectListing:    true,
+			expectedTopology: zoneTopology("us-east-1a", "us-east-1b", "us-east-1c"),
+		},
+		{
+			name:             "Success: Volume pinned to a zone",
+			azName:           "us-east-1b",
+			expectedTopology: zoneTopology("us-east-1b"),
+		},
+		{
+			name: "Success: Topology disabled",
+			mountTargets: []*cloud.MountTarget{
+				{AZName: "us-east-1a"},
+			},
+			disabled: true,
+		},
+		{
+			name:   "Success: File system of another region",
+			region: "eu-west-1",
+		},
+		{
+			name:    "Success: File system of another account",
+			roleArn: roleArn,
+		},
+		{
+			name:            "Success: Mount targets cannot be listed",
+			mountTargetsErr: errors.New("DescribeMountTargets failed"),
+			expectListing:   true,
+		},
+		{
+			name:          "Success: File system without mount targets",
+			mountTargets:  []*cloud.MountTarget{},
+			expectListing: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockCtl := gomock.NewController(t)
+			mockCloud := mocks.NewMockCloud(mockCtl)
+			driver := &Driver{
+				cloud:                 mockCloud,
+				volumeTopologyEnabled: !tc.disabled,
+			}
+
+			ctx := context.Background()
+			if tc.expectListing {
+				mockCloud.EXPECT().ListMountTargets(gomock.Eq(ctx), gomock.Eq(fsId)).Return(tc.mountTargets, tc.mountTargetsErr)
+			}
+			topology := driver.volumeTopology(ctx, mockCloud, fsId, tc.azName, tc.region, tc.roleArn)
+			if !reflect.DeepEqual(topology, tc.expectedTopology) {
+				t.Fatalf("Topology mismatched. Expected: %v, Actual: %v", tc.expectedTopology, topology)
+			}
+			mockCtl.Finish()
+		})
+	}
+}
+
+func TestCreateVolumeTopology(t *testing.T) {
+	var (
+		fsId = "fs-abcd1234"
+		apId = "fsap-abcd1234xyz987"
+	)
+
+	mockCtl := gomock.NewController(t)
+	mockCloud := mocks.NewMockCloud(mockCtl)
+	driver := &Driver{
+		cloud:                 mockCloud,
+		gidAllocator:          NewGidAllocator(),
+		volumeTopologyEnabled: true,
+	}
+
+	req := &csi.CreateVolumeRequest{
+		Name: "volumeName",
+		VolumeCapabilities: []*csi.VolumeCapability{
+			{
+				AccessType: &csi.VolumeCapability_Mount{
+					Mount: &csi.VolumeCapability_MountVolume{},
+				},
+				AccessMode: &csi.VolumeCapability_AccessMode{
+					Mode: csi.VolumeCapability_AccessMode_MULTI_NODE_MULTI_WRITER,
+				},
+			},
+		},
+		Parameters: map[string]string{
+			ProvisioningMode: "efs-ap",
+			FsId:             fsId,
+			DirectoryPerms:   "777",
+		},
+	}
+
+	ctx := context.Background()
+	accessPoint := &cloud.AccessPoint{
+		AccessPointId: apId,
+		FileSystemId:  fsId,
+	}
+	mockCloud.EXPECT().ListAccessPoints(gomock.Eq(ctx), gomock.Any()).Return(nil, nil)
+	mockCloud.EXPECT().CreateAccessPoint(gomock.Eq(ctx), gomock.Any(), gomock.Any(), gomock.Any()).Return(accessPoint, nil)
+	mockCloud.EXPECT().ListMountTargets(gomock.Eq(ctx), gomock.Eq(fsId)).Return([]*cloud.MountTarget{{AZName: "us-east-1a"}}, nil)
+
+	res, err := driver.CreateVolume(ctx, req)
+	if err != nil {
+		t.Fatalf("CreateVolume failed: %v", err)
+	}
+	if expected := zoneTopology("us-east-1a"); !reflect.DeepEqual(res.Volume.AccessibleTopology, expected) {
+		t.Fatalf("Topology mismatched. Expected: %v, Actual: %v", expected, res.Volume.AccessibleTopology)
+	}
+
+	capabilities, err := driver.GetPluginCapabilities(ctx, &csi.GetPluginCapabilitiesRequest{})
+	if err != nil {
+		t.Fatalf("GetPluginCapabilities failed: %v", err)
+	}
+	found := false
+	for _, capability := range capabilities.Capabilities {
+		if capability.GetService().GetType() == csi.PluginCapability_Service_VOLUME_ACCESSIBILITY_CONSTRAINTS {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatalf("VOLUME_ACCESSIBILITY_CONSTRAINTS capability is not advertised: %v", capabilities.Capabilities)
+	}
+	mockCtl.Finish()
+}
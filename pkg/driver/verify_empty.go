@@ -0,0 +1,58 @@
+package driver
+
+import (
+	"context"
+	"io"
+	"os"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"k8s.io/klog/v2"
+
+	"github.com/kubernetes-sigs/aws-efs-csi-driver/pkg/cloud"
+)
+
+// verifyRootDirEmpty fails with FailedPrecondition when the root directory of the access point about to be created
+// already exists and is not empty, with --verify-empty-on-reuse. A subPathPattern without a unique suffix resolves
+// to the same directory for a re-created PVC, which would otherwise get the files of the previous volume.
+// The access point created by a previous attempt of the same request, or restored from soft delete, owns its files.
+func (d *Driver) verifyRootDirEmpty(ctx context.Context, localCloud cloud.Cloud, roleArn string, useIam bool, volName, clientToken string, accessPointOpts *cloud.AccessPointOptions) error {
+	existing, err := localCloud.FindAccessPointByClientToken(ctx, clientToken, accessPointOpts)
+	if err == nil && existing != nil && existing.AccessPointRootDir == accessPointOpts.DirectoryPath {
+		klog.V(2).Infof("Root directory %q belongs to Access Point %v of this request, not verifying it is empty", accessPointOpts.DirectoryPath, existing.AccessPointId)
+		return nil
+	}
+
+	fileSystemId, rootDir := accessPointOpts.FileSystemId, accessPointOpts.DirectoryPath
+	target, unmount, err := d.mountFileSystemRoot(ctx, localCloud, roleArn, useIam, fileSystemId, volName)
+	if err != nil {
+		return err
+	}
+	defer unmount()
+
+	empty, err := isEmptyDir(target + rootDir)
+	if err != nil {
+		return status.Errorf(codes.Internal, "Could not verify root directory %q of File System %v is empty: %v", rootDir, fileSystemId, err)
+	}
+	if !empty {
+		return status.Errorf(codes.FailedPrecondition, "Root directory %q of File System %v is not empty, refusing to reuse it with --verify-empty-on-reuse. "+
+			"Please empty it, or use a %v producing distinct directories", rootDir, fileSystemId, SubPathPattern)
+	}
+	return nil
+}
+
+// isEmptyDir returns whether the directory has no entries, true when it does not exist
+func isEmptyDir(dir string) (bool, error) {
+	f, err := os.Open(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return true, nil
+		}
+		return false, err
+	}
+	defer f.Close()
+	if _, err := f.Readdirnames(1); err != io.EOF {
+		return false, err
+	}
+	return true, nil
+}
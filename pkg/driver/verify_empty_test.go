@@ -0,0 +1,136 @@
+package driver
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	"github.com/golang/mock/gomock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/kubernetes-sigs/aws-efs-csi-driver/pkg/cloud"
+	"github.com/kubernetes-sigs/aws-efs-csi-driver/pkg/driver/mocks"
+)
+
+func TestCreateVolumeVerifyEmptyOnReuse(t *testing.T) {
+	var (
+		fsId    = "fs-abcd1234"
+		apId    = "fsap-abcd1234xyz987"
+		volName = "volumeName"
+		rootDir = "/data/ns/pvc"
+	)
+
+	testCases := []struct {
+		name string
+		// files are created in the root directory, which is not created when nil
+		files        []string
+		existingAp   *cloud.AccessPoint
+		expectedCode codes.Code
+	}{
+		{
+			name: "Success: Root directory does not exist",
+		},
+		{
+			name:  "Success: Root directory is empty",
+			files: []string{},
+		},
+		{
+			name:         "Fail: Root directory has files of a previous volume",
+			files:        []string{"data.db"},
+			expectedCode: codes.FailedPrecondition,
+		},
+		{
+			name:         "Fail: Root directory has hidden files",
+			files:        []string{".bash_history"},
+			expectedCode: codes.FailedPrecondition,
+		},
+		{
+			name:       "Success: Root directory of the access point of a previous attempt",
+			files:      []string{"data.db"},
+			existingAp: &cloud.AccessPoint{AccessPointId: apId, FileSystemId: fsId, AccessPointRootDir: rootDir},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockCtl := gomock.NewController(t)
+			mockCloud := mocks.NewMockCloud(mockCtl)
+			mockMounter := mocks.NewMockMounter(mockCtl)
+			tempMountDir := t.TempDir()
+			driver := &Driver{
+				cloud:               mockCloud,
+				mounter:             mockMounter,
+				gidAllocator:        NewGidAllocator(),
+				tempMountPathPrefix: tempMountDir,
+				verifyEmptyOnReuse:  true,
+			}
+
+			// The file system is simulated by a directory, populated by a previous volume
+			fsDir := tempMountDir + "/fs"
+			if err := os.MkdirAll(fsDir, 0755); err != nil {
+				t.Fatalf("Could not create %v: %v", fsDir, err)
+			}
+			if tc.files != nil {
+				if err := os.MkdirAll(fsDir+rootDir, 0755); err != nil {
+					t.Fatalf("Could not create %v: %v", rootDir, err)
+				}
+				for _, file := range tc.files {
+					if err := os.WriteFile(fsDir+rootDir+"/"+file, []byte("data"), 0644); err != nil {
+						t.Fatalf("Could not create %v: %v", file, err)
+					}
+				}
+			}
+
+			ctx := context.Background()
+			target := tempMountDir + "/" + volName
+			mockCloud.EXPECT().DescribeFileSystem(gomock.Eq(ctx), gomock.Eq(fsId)).Return(&cloud.FileSystem{FileSystemId: fsId}, nil)
+			mockCloud.EXPECT().FindAccessPointByClientToken(gomock.Eq(ctx), gomock.Any(), gomock.Any()).Return(tc.existingAp, nil)
+			if tc.existingAp == nil {
+				mockMounter.EXPECT().MakeDir(gomock.Eq(target)).Return(nil)
+				mockMounter.EXPECT().Mount(gomock.Eq(fsId), gomock.Eq(target), gomock.Eq("efs"), gomock.Any()).DoAndReturn(
+					func(source, target, fstype string, options []string) error {
+						return os.Symlink(fsDir, target)
+					})
+				mockMounter.EXPECT().Unmount(gomock.Eq(target)).DoAndReturn(func(target string) error {
+					return os.Remove(target)
+				})
+			}
+			if tc.expectedCode == codes.OK {
+				mockCloud.EXPECT().CreateAccessPoint(gomock.Eq(ctx), gomock.Any(), gomock.Any(), gomock.Any()).Return(
+					&cloud.AccessPoint{AccessPointId: apId, FileSystemId: fsId}, nil)
+			}
+
+			_, err := driver.CreateVolume(ctx, &csi.CreateVolumeRequest{
+				Name: volName,
+				VolumeCapabilities: []*csi.VolumeCapability{
+					{
+						AccessType: &csi.VolumeCapability_Mount{
+							Mount: &csi.VolumeCapability_MountVolume{},
+						},
+						AccessMode: &csi.VolumeCapability_AccessMode{
+							Mode: csi.VolumeCapability_AccessMode_MULTI_NODE_MULTI_WRITER,
+						},
+					},
+				},
+				Parameters: map[string]string{
+					ProvisioningMode:      AccessPointMode,
+					FsId:                  fsId,
+					DirectoryPerms:        "700",
+					Uid:                   "1000",
+					Gid:                   "1000",
+					BasePath:              "/data",
+					SubPathPattern:        "${.PVC.namespace}/${.PVC.name}",
+					EnsureUniqueDirectory: "false",
+					PvcNamespace:          "ns",
+					PvcName:               "pvc",
+				},
+			})
+			if status.Code(err) != tc.expectedCode {
+				t.Fatalf("Expected %v, got: %v", tc.expectedCode, err)
+			}
+			mockCtl.Finish()
+		})
+	}
+}
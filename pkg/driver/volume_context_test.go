@@ -0,0 +1,75 @@
+package driver
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+
+	"github.com/kubernetes-sigs/aws-efs-csi-driver/pkg/cloud/fake"
+)
+
+func TestCreateVolumeOmitsEmptyVolumeContext(t *testing.T) {
+	fsId := "fs-abcd1234"
+	fakeCloud := fake.NewCloud()
+	fakeCloud.AddFileSystem(fsId, "us-east-1a", "us-east-1b")
+	driver := &Driver{
+		cloud:                  fakeCloud,
+		gidAllocator:           NewGidAllocator(),
+		omitEmptyVolumeContext: true,
+	}
+
+	req := &csi.CreateVolumeRequest{
+		Name: "pvc-1",
+		VolumeCapabilities: []*csi.VolumeCapability{
+			{
+				AccessType: &csi.VolumeCapability_Mount{
+					Mount: &csi.VolumeCapability_MountVolume{},
+				},
+				AccessMode: &csi.VolumeCapability_AccessMode{
+					Mode: csi.VolumeCapability_AccessMode_MULTI_NODE_MULTI_WRITER,
+				},
+			},
+		},
+		CapacityRange: &csi.CapacityRange{RequiredBytes: 5 * 1024 * 1024 * 1024},
+		Parameters: map[string]string{
+			ProvisioningMode:   AccessPointMode,
+			FsId:               fsId,
+			DirectoryPerms:     "700",
+			AzName:             "us-east-1b",
+			MountTargetIpParam: MountTargetIpAuto,
+			MountOptions:       "noresvport",
+			EnforceQuota:       "true",
+		},
+	}
+	res, err := driver.CreateVolume(context.Background(), req)
+	if err != nil {
+		t.Fatalf("CreateVolume failed: %v", err)
+	}
+	if len(res.Volume.VolumeContext) == 0 {
+		t.Fatalf("Expected a volume context")
+	}
+	for key, value := range res.Volume.VolumeContext {
+		if value == "" {
+			t.Fatalf("Volume context %v has an empty value for key %v", res.Volume.VolumeContext, key)
+		}
+	}
+}
+
+func TestOmitEmptyValues(t *testing.T) {
+	volContext := map[string]string{
+		AzName:        "us-east-1a",
+		MountTargetIp: "",
+		Region:        "",
+		UseIam:        "true",
+	}
+	omitEmptyValues(volContext)
+	expected := map[string]string{
+		AzName: "us-east-1a",
+		UseIam: "true",
+	}
+	if !reflect.DeepEqual(volContext, expected) {
+		t.Fatalf("Volume context mismatched. Expected: %v, Actual: %v", expected, volContext)
+	}
+}
@@ -0,0 +1,50 @@
+package driver
+
+import (
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	"k8s.io/kubernetes/pkg/volume/util/fs"
+)
+
+// statfsVolumeUsage returns the byte and inode usage of the file system mounted at path, from a single statfs call.
+// EFS reports the metered size of the whole file system as used, and a practically unlimited total size.
+func statfsVolumeUsage(path string) ([]*csi.VolumeUsage, error) {
+	available, capacity, used, inodes, inodesFree, inodesUsed, err := fs.Info(path)
+	if err != nil {
+		return nil, err
+	}
+	return []*csi.VolumeUsage{
+		{
+			Unit:      csi.VolumeUsage_BYTES,
+			Available: available,
+			Total:     capacity,
+			Used:      used,
+		},
+		{
+			Unit:      csi.VolumeUsage_INODES,
+			Available: inodesFree,
+			Total:     inodes,
+			Used:      inodesUsed,
+		},
+	}, nil
+}
+
+// capVolumeUsage caps the byte usage of a volume to its quota, which EFS does not enforce. The total is the quota
+// when it is below the size of the file system, and the available bytes are what remains of it. The usage is copied,
+// as it may be cached.
+func capVolumeUsage(usage []*csi.VolumeUsage, quotaBytes int64) []*csi.VolumeUsage {
+	capped := make([]*csi.VolumeUsage, 0, len(usage))
+	for _, u := range usage {
+		if u.Unit == csi.VolumeUsage_BYTES && quotaBytes < u.Total {
+			available := quotaBytes - u.Used
+			if available < 0 {
+				available = 0
+			}
+			if available > u.Available {
+				available = u.Available
+			}
+			u = &csi.VolumeUsage{Unit: u.Unit, Available: available, Total: quotaBytes, Used: u.Used}
+		}
+		capped = append(capped, u)
+	}
+	return capped
+}
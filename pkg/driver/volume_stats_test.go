@@ -0,0 +1,115 @@
+package driver
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	"github.com/golang/mock/gomock"
+)
+
+func TestNodeGetVolumeStatsStatfs(t *testing.T) {
+	testCases := []struct {
+		name       string
+		quotaBytes int64
+	}{
+		{
+			name: "Success: Usage of the file system",
+		},
+		{
+			name:       "Success: Usage capped to the quota of the volume",
+			quotaBytes: 1024,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			mockMounter, driver, ctx := setup(mockCtrl, NewVolStatter(), false)
+			driver.volMetricsOptIn = false
+			dir := t.TempDir()
+			mockMounter.EXPECT().IsLikelyNotMountPoint(dir).Return(false, nil)
+			if tc.quotaBytes > 0 {
+				driver.volumeQuotas.Store(dir, tc.quotaBytes)
+			}
+
+			res, err := driver.NodeGetVolumeStats(ctx, &csi.NodeGetVolumeStatsRequest{
+				VolumeId:   volumeId,
+				VolumePath: dir,
+			})
+			if err != nil {
+				t.Fatalf("NodeGetVolumeStats failed: %v", err)
+			}
+			if len(res.Usage) != 2 {
+				t.Fatalf("Expected the usage of bytes and inodes, got %v", res.Usage)
+			}
+			bytes, inodes := res.Usage[0], res.Usage[1]
+			if bytes.Unit != csi.VolumeUsage_BYTES || inodes.Unit != csi.VolumeUsage_INODES {
+				t.Fatalf("Unexpected units %v and %v", bytes.Unit, inodes.Unit)
+			}
+			if bytes.Total <= 0 || inodes.Total <= 0 {
+				t.Fatalf("Expected positive totals, got %v bytes and %v inodes", bytes.Total, inodes.Total)
+			}
+			if tc.quotaBytes > 0 {
+				if bytes.Total != tc.quotaBytes || bytes.Available > tc.quotaBytes {
+					t.Fatalf("Usage is not capped to the quota %v: %v", tc.quotaBytes, bytes)
+				}
+			} else if bytes.Available > bytes.Total {
+				t.Fatalf("Available bytes exceed the total: %v", bytes)
+			}
+		})
+	}
+}
+
+func TestCapVolumeUsage(t *testing.T) {
+	testCases := []struct {
+		name       string
+		usage      []*csi.VolumeUsage
+		quotaBytes int64
+		expected   []*csi.VolumeUsage
+	}{
+		{
+			name:       "Success: Quota below the size of the file system",
+			usage:      []*csi.VolumeUsage{{Unit: csi.VolumeUsage_BYTES, Available: 900, Total: 1000, Used: 100}},
+			quotaBytes: 500,
+			expected:   []*csi.VolumeUsage{{Unit: csi.VolumeUsage_BYTES, Available: 400, Total: 500, Used: 100}},
+		},
+		{
+			name:       "Success: Usage above the quota",
+			usage:      []*csi.VolumeUsage{{Unit: csi.VolumeUsage_BYTES, Available: 300, Total: 1000, Used: 700}},
+			quotaBytes: 500,
+			expected:   []*csi.VolumeUsage{{Unit: csi.VolumeUsage_BYTES, Available: 0, Total: 500, Used: 700}},
+		},
+		{
+			name:       "Success: Less available than what remains of the quota",
+			usage:      []*csi.VolumeUsage{{Unit: csi.VolumeUsage_BYTES, Available: 50, Total: 1000, Used: 100}},
+			quotaBytes: 500,
+			expected:   []*csi.VolumeUsage{{Unit: csi.VolumeUsage_BYTES, Available: 50, Total: 500, Used: 100}},
+		},
+		{
+			name:       "Success: Quota above the size of the file system",
+			usage:      []*csi.VolumeUsage{{Unit: csi.VolumeUsage_BYTES, Available: 900, Total: 1000, Used: 100}},
+			quotaBytes: 5000,
+			expected:   []*csi.VolumeUsage{{Unit: csi.VolumeUsage_BYTES, Available: 900, Total: 1000, Used: 100}},
+		},
+		{
+			name:       "Success: Inodes are not capped",
+			usage:      []*csi.VolumeUsage{{Unit: csi.VolumeUsage_INODES, Available: 900, Total: 1000, Used: 100}},
+			quotaBytes: 500,
+			expected:   []*csi.VolumeUsage{{Unit: csi.VolumeUsage_INODES, Available: 900, Total: 1000, Used: 100}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			original := tc.usage[0].Total
+			usage := capVolumeUsage(tc.usage, tc.quotaBytes)
+			if !reflect.DeepEqual(usage, tc.expected) {
+				t.Fatalf("Usage mismatched. Expected: %v, Actual: %v", tc.expected, usage)
+			}
+			if tc.usage[0].Total != original {
+				t.Fatalf("The usage passed to capVolumeUsage was modified")
+			}
+		})
+	}
+}
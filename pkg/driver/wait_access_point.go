@@ -0,0 +1,157 @@
+package driver
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog/v2"
+
+	"github.com/kubernetes-sigs/aws-efs-csi-driver/pkg/cloud"
+)
+
+const (
+	accessPointAvailable = "available"
+	accessPointCreating  = "creating"
+	accessPointDeleting  = "deleting"
+	accessPointError     = "error"
+
+	// DefaultDeleteCreatingApWait is the default bound of the wait of DeleteVolume for an access point still creating
+	DefaultDeleteCreatingApWait = 30 * time.Second
+
+	// accessPointWaitTimeout bounds the wait for an access point to become available, for requests without a deadline
+	accessPointWaitTimeout = 2 * time.Minute
+)
+
+// accessPointPollInterval is the delay between the descriptions of an access point waited for
+var accessPointPollInterval = time.Second
+
+// waitForAccessPoint polls the access point until it is available, with --wait-ap-available.
+// It returns Unavailable, which the provisioner retries, when the request is done before the access point is.
+// Describe failures are retried, as a newly created access point may not be described yet.
+func waitForAccessPoint(ctx context.Context, localCloud cloud.Cloud, accessPoint *cloud.AccessPoint) error {
+	if accessPoint.LifeCycleState == accessPointAvailable {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(ctx, accessPointWaitTimeout)
+	defer cancel()
+
+	state := accessPoint.LifeCycleState
+	var describeErr error
+	err := wait.PollImmediateUntilWithContext(ctx, accessPointPollInterval, func(ctx context.Context) (bool, error) {
+		ap, err := localCloud.DescribeAccessPoint(ctx, accessPoint.AccessPointId)
+		if err != nil {
+			describeErr = err
+			klog.V(4).Infof("Failed to describe Access Point %v while waiting for it to be available: %v", accessPoint.AccessPointId, err)
+			return false, nil
+		}
+		state, describeErr = ap.LifeCycleState, nil
+		switch state {
+		case accessPointAvailable:
+			return true, nil
+		case accessPointError:
+			return false, status.Errorf(codes.Internal, "Access Point %v is in the %v state", accessPoint.AccessPointId, state)
+		}
+		return false, nil
+	})
+	if err == nil {
+		klog.V(2).Infof("Access Point %v is available", accessPoint.AccessPointId)
+		return nil
+	}
+	if status.Code(err) == codes.Internal {
+		return err
+	}
+	if describeErr != nil {
+		return status.Errorf(codes.Unavailable, "Access Point %v is not available yet, last description failed: %v", accessPoint.AccessPointId, describeErr)
+	}
+	return status.Errorf(codes.Unavailable, "Access Point %v is not available yet, it is in the %q state", accessPoint.AccessPointId, state)
+}
+
+// waitForCreatingAccessPoint polls an access point DeleteVolume found creating, for up to --delete-creating-ap-wait,
+// as EFS fails to delete it until it is available. It returns the access point once available, nil once it is
+// deleted or deleting, and Unavailable, which the provisioner retries, when it is still creating at the deadline.
+func waitForCreatingAccessPoint(ctx context.Context, localCloud cloud.Cloud, accessPoint *cloud.AccessPoint, timeout time.Duration) (*cloud.AccessPoint, error) {
+	if timeout <= 0 {
+		return nil, status.Errorf(codes.Unavailable, "Access Point %v is still creating, it can only be deleted once available", accessPoint.AccessPointId)
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	klog.V(2).Infof("DeleteVolume: Access Point %v is creating, waiting up to %v for it to be available", accessPoint.AccessPointId, timeout)
+	current := accessPoint
+	gone := false
+	err := wait.PollUntilWithContext(ctx, accessPointPollInterval, func(ctx context.Context) (bool, error) {
+		ap, err := localCloud.DescribeAccessPoint(ctx, accessPoint.AccessPointId)
+		if err != nil {
+			if cloud.IsNotFound(err) {
+				gone = true
+				return true, nil
+			}
+			if cloud.IsAccessDenied(err) {
+				return false, status.Errorf(codes.Unauthenticated, "Access Denied. Please ensure you have the right AWS permissions: %v", err)
+			}
+			klog.V(4).Infof("Failed to describe Access Point %v while waiting for it to be available: %v", accessPoint.AccessPointId, err)
+			return false, nil
+		}
+		current = ap
+		switch ap.LifeCycleState {
+		case accessPointCreating:
+			return false, nil
+		case accessPointDeleting:
+			gone = true
+		}
+		return true, nil
+	})
+	if err != nil {
+		if _, ok := status.FromError(err); ok {
+			return nil, err
+		}
+		return nil, status.Errorf(codes.Unavailable, "Access Point %v is still %v, it can only be deleted once available", accessPoint.AccessPointId, current.LifeCycleState)
+	}
+	if gone {
+		klog.V(2).Infof("DeleteVolume: Access Point %v was deleted while creating", accessPoint.AccessPointId)
+		return nil, nil
+	}
+	klog.V(2).Infof("DeleteVolume: Access Point %v is %v", accessPoint.AccessPointId, current.LifeCycleState)
+	return current, nil
+}
+
+// waitForDeletedAccessPoint polls an access point deleted by DeleteVolume until it is gone, for up to
+// --wait-ap-deleted, as an access point still deleting counts against the access point limit of its file system.
+// It returns Unavailable, which the provisioner retries, when the access point is still there at the deadline.
+func waitForDeletedAccessPoint(ctx context.Context, localCloud cloud.Cloud, accessPointId string, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	state := accessPointDeleting
+	var describeErr error
+	err := wait.PollUntilWithContext(ctx, accessPointPollInterval, func(ctx context.Context) (bool, error) {
+		ap, err := localCloud.DescribeAccessPoint(ctx, accessPointId)
+		if err != nil {
+			if cloud.IsNotFound(err) {
+				return true, nil
+			}
+			if cloud.IsAccessDenied(err) {
+				return false, status.Errorf(codes.Unauthenticated, "Access Denied. Please ensure you have the right AWS permissions: %v", err)
+			}
+			describeErr = err
+			klog.V(4).Infof("Failed to describe Access Point %v while waiting for it to be deleted: %v", accessPointId, err)
+			return false, nil
+		}
+		state, describeErr = ap.LifeCycleState, nil
+		return false, nil
+	})
+	if err == nil {
+		klog.V(2).Infof("DeleteVolume: Access Point %v is deleted", accessPointId)
+		return nil
+	}
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+	if describeErr != nil {
+		return status.Errorf(codes.Unavailable, "Access Point %v is not deleted yet, last description failed: %v", accessPointId, describeErr)
+	}
+	return status.Errorf(codes.Unavailable, "Access Point %v is not deleted yet, it is in the %q state", accessPointId, state)
+}
@@ -0,0 +1,369 @@
+package driver
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	"github.com/golang/mock/gomock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/kubernetes-sigs/aws-efs-csi-driver/pkg/cloud"
+	"github.com/kubernetes-sigs/aws-efs-csi-driver/pkg/cloud/fake"
+	"github.com/kubernetes-sigs/aws-efs-csi-driver/pkg/driver/mocks"
+)
+
+func TestCreateVolumeWaitAccessPointAvailable(t *testing.T) {
+	var (
+		fsId      = "fs-abcd1234"
+		stdVolCap = &csi.VolumeCapability{
+			AccessType: &csi.VolumeCapability_Mount{
+				Mount: &csi.VolumeCapability_MountVolume{},
+			},
+			AccessMode: &csi.VolumeCapability_AccessMode{
+				Mode: csi.VolumeCapability_AccessMode_MULTI_NODE_MULTI_WRITER,
+			},
+		}
+		req = &csi.CreateVolumeRequest{
+			Name:               "pvc-1",
+			VolumeCapabilities: []*csi.VolumeCapability{stdVolCap},
+			CapacityRange:      &csi.CapacityRange{RequiredBytes: 5 * 1024 * 1024 * 1024},
+			Parameters: map[string]string{
+				ProvisioningMode: AccessPointMode,
+				FsId:             fsId,
+				DirectoryPerms:   "700",
+			},
+		}
+	)
+
+	defer func(interval time.Duration) { accessPointPollInterval = interval }(accessPointPollInterval)
+	accessPointPollInterval = time.Millisecond
+
+	newDriver := func(waitAccessPointAvailable bool, creatingDescribes int) (*Driver, *fake.Cloud) {
+		fakeCloud := fake.NewCloud()
+		fakeCloud.AddFileSystem(fsId)
+		fakeCloud.SetCreatingDescribes(creatingDescribes)
+		return &Driver{
+			cloud:                    fakeCloud,
+			gidAllocator:             NewGidAllocator(),
+			waitAccessPointAvailable: waitAccessPointAvailable,
+		}, fakeCloud
+	}
+
+	testCases := []struct {
+		name     string
+		testFunc func(t *testing.T)
+	}{
+		{
+			name: "Success: Access point becomes available",
+			testFunc: func(t *testing.T) {
+				driver, fakeCloud := newDriver(true, 3)
+				ctx := context.Background()
+				res, err := driver.CreateVolume(ctx, req)
+				if err != nil {
+					t.Fatalf("CreateVolume failed: %v", err)
+				}
+				accessPoints, err := fakeCloud.ListAccessPoints(ctx, fsId)
+				if err != nil {
+					t.Fatalf("ListAccessPoints failed: %v", err)
+				}
+				if len(accessPoints) != 1 || accessPoints[0].LifeCycleState != accessPointAvailable {
+					t.Fatalf("Expected 1 available access point, got %+v", accessPoints)
+				}
+				if res.Volume.VolumeId != fsId+"::"+accessPoints[0].AccessPointId {
+					t.Fatalf("Unexpected volume Id %v", res.Volume.VolumeId)
+				}
+			},
+		},
+		{
+			name: "Fail: Access point is still creating at the deadline",
+			testFunc: func(t *testing.T) {
+				driver, fakeCloud := newDriver(true, -1)
+				ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+				defer cancel()
+				_, err := driver.CreateVolume(ctx, req)
+				if status.Code(err) != codes.Unavailable {
+					t.Fatalf("Expected an Unavailable error, got: %v", err)
+				}
+				accessPoints, err := fakeCloud.ListAccessPoints(context.Background(), fsId)
+				if err != nil {
+					t.Fatalf("ListAccessPoints failed: %v", err)
+				}
+				if len(accessPoints) != 0 {
+					t.Fatalf("Expected the access point to be rolled back, got %+v", accessPoints)
+				}
+			},
+		},
+		{
+			name: "Success: Access point is not waited for by default",
+			testFunc: func(t *testing.T) {
+				driver, fakeCloud := newDriver(false, -1)
+				ctx := context.Background()
+				if _, err := driver.CreateVolume(ctx, req); err != nil {
+					t.Fatalf("CreateVolume failed: %v", err)
+				}
+				accessPoints, err := fakeCloud.ListAccessPoints(ctx, fsId)
+				if err != nil {
+					t.Fatalf("ListAccessPoints failed: %v", err)
+				}
+				if len(accessPoints) != 1 {
+					t.Fatalf("Expected 1 access point, got %v", len(accessPoints))
+				}
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, tc.testFunc)
+	}
+}
+
+func TestDeleteVolumeCreatingAccessPoint(t *testing.T) {
+	var (
+		fsId     = "fs-abcd1234"
+		apId     = "fsap-abcd1234xyz987"
+		volumeId = fsId + "::" + apId
+	)
+
+	defer func(interval time.Duration) { accessPointPollInterval = interval }(accessPointPollInterval)
+	accessPointPollInterval = time.Millisecond
+
+	withState := func(state string) *cloud.AccessPoint {
+		return &cloud.AccessPoint{AccessPointId: apId, FileSystemId: fsId, LifeCycleState: state}
+	}
+
+	testCases := []struct {
+		name         string
+		wait         time.Duration
+		setup        func(mockCloud *mocks.MockCloud)
+		expectedCode codes.Code
+	}{
+		{
+			name: "Success: Access point is deleted once available",
+			wait: time.Minute,
+			setup: func(mockCloud *mocks.MockCloud) {
+				gomock.InOrder(
+					mockCloud.EXPECT().DescribeAccessPoint(gomock.Any(), gomock.Eq(apId)).Return(withState(accessPointCreating), nil),
+					mockCloud.EXPECT().DescribeAccessPoint(gomock.Any(), gomock.Eq(apId)).Return(withState(accessPointCreating), nil),
+					mockCloud.EXPECT().DescribeAccessPoint(gomock.Any(), gomock.Eq(apId)).Return(withState(accessPointAvailable), nil),
+					mockCloud.EXPECT().DeleteAccessPoint(gomock.Any(), gomock.Eq(apId)).Return(nil),
+				)
+			},
+		},
+		{
+			name: "Success: Access point deleted while creating",
+			wait: time.Minute,
+			setup: func(mockCloud *mocks.MockCloud) {
+				gomock.InOrder(
+					mockCloud.EXPECT().DescribeAccessPoint(gomock.Any(), gomock.Eq(apId)).Return(withState(accessPointCreating), nil),
+					mockCloud.EXPECT().DescribeAccessPoint(gomock.Any(), gomock.Eq(apId)).Return(nil, cloud.ErrNotFound),
+				)
+			},
+		},
+		{
+			name: "Success: Access point deleting is not deleted again",
+			wait: time.Minute,
+			setup: func(mockCloud *mocks.MockCloud) {
+				mockCloud.EXPECT().DescribeAccessPoint(gomock.Any(), gomock.Eq(apId)).Return(withState(accessPointDeleting), nil)
+			},
+		},
+		{
+			name: "Fail: Access point still creating at the end of the wait",
+			wait: 20 * time.Millisecond,
+			setup: func(mockCloud *mocks.MockCloud) {
+				mockCloud.EXPECT().DescribeAccessPoint(gomock.Any(), gomock.Eq(apId)).Return(withState(accessPointCreating), nil).MinTimes(1)
+			},
+			expectedCode: codes.Unavailable,
+		},
+		{
+			name: "Fail: Access point creating without wait",
+			setup: func(mockCloud *mocks.MockCloud) {
+				mockCloud.EXPECT().DescribeAccessPoint(gomock.Any(), gomock.Eq(apId)).Return(withState(accessPointCreating), nil)
+			},
+			expectedCode: codes.Unavailable,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockCtl := gomock.NewController(t)
+			mockCloud := mocks.NewMockCloud(mockCtl)
+			driver := &Driver{
+				cloud:                mockCloud,
+				gidAllocator:         NewGidAllocator(),
+				deleteCreatingApWait: tc.wait,
+			}
+			tc.setup(mockCloud)
+
+			_, err := driver.DeleteVolume(context.Background(), &csi.DeleteVolumeRequest{VolumeId: volumeId})
+			if status.Code(err) != tc.expectedCode {
+				t.Fatalf("Expected %v, got: %v", tc.expectedCode, err)
+			}
+			mockCtl.Finish()
+		})
+	}
+}
+
+func TestDeleteVolumeWaitAccessPointDeleted(t *testing.T) {
+	var (
+		fsId     = "fs-abcd1234"
+		apId     = "fsap-abcd1234xyz987"
+		volumeId = fsId + "::" + apId
+		gid      = int64(50000)
+	)
+
+	defer func(interval time.Duration) { accessPointPollInterval = interval }(accessPointPollInterval)
+	accessPointPollInterval = time.Millisecond
+
+	withState := func(state string) *cloud.AccessPoint {
+		return &cloud.AccessPoint{AccessPointId: apId, FileSystemId: fsId, LifeCycleState: state, PosixUser: &cloud.PosixUser{Uid: gid, Gid: gid}}
+	}
+
+	testCases := []struct {
+		name         string
+		wait         time.Duration
+		timeout      time.Duration
+		setup        func(mockCloud *mocks.MockCloud)
+		expectedCode codes.Code
+		released     bool
+	}{
+		{
+			name: "Success: GID released once the access point is gone",
+			wait: time.Minute,
+			setup: func(mockCloud *mocks.MockCloud) {
+				gomock.InOrder(
+					mockCloud.EXPECT().DescribeAccessPoint(gomock.Any(), gomock.Eq(apId)).Return(withState(accessPointAvailable), nil),
+					mockCloud.EXPECT().DeleteAccessPoint(gomock.Any(), gomock.Eq(apId)).Return(nil),
+					mockCloud.EXPECT().DescribeAccessPoint(gomock.Any(), gomock.Eq(apId)).Return(withState(accessPointDeleting), nil),
+					mockCloud.EXPECT().DescribeAccessPoint(gomock.Any(), gomock.Eq(apId)).Return(nil, errors.New("DescribeAccessPoint failed")),
+					mockCloud.EXPECT().DescribeAccessPoint(gomock.Any(), gomock.Eq(apId)).Return(withState(accessPointDeleting), nil),
+					mockCloud.EXPECT().DescribeAccessPoint(gomock.Any(), gomock.Eq(apId)).Return(nil, cloud.ErrNotFound),
+				)
+			},
+			released: true,
+		},
+		{
+			name: "Success: Access point deleting by a previous call is waited for",
+			wait: time.Minute,
+			setup: func(mockCloud *mocks.MockCloud) {
+				gomock.InOrder(
+					mockCloud.EXPECT().DescribeAccessPoint(gomock.Any(), gomock.Eq(apId)).Return(withState(accessPointDeleting), nil),
+					mockCloud.EXPECT().DescribeAccessPoint(gomock.Any(), gomock.Eq(apId)).Return(withState(accessPointDeleting), nil),
+					mockCloud.EXPECT().DescribeAccessPoint(gomock.Any(), gomock.Eq(apId)).Return(nil, cloud.ErrNotFound),
+				)
+			},
+			released: true,
+		},
+		{
+			name: "Success: Access point already gone is not waited for",
+			wait: time.Minute,
+			setup: func(mockCloud *mocks.MockCloud) {
+				gomock.InOrder(
+					mockCloud.EXPECT().DescribeAccessPoint(gomock.Any(), gomock.Eq(apId)).Return(withState(accessPointAvailable), nil),
+					mockCloud.EXPECT().DeleteAccessPoint(gomock.Any(), gomock.Eq(apId)).Return(cloud.ErrNotFound),
+				)
+			},
+			released: true,
+		},
+		{
+			name: "Success: Access point not waited for without wait",
+			setup: func(mockCloud *mocks.MockCloud) {
+				gomock.InOrder(
+					mockCloud.EXPECT().DescribeAccessPoint(gomock.Any(), gomock.Eq(apId)).Return(withState(accessPointAvailable), nil),
+					mockCloud.EXPECT().DeleteAccessPoint(gomock.Any(), gomock.Eq(apId)).Return(nil),
+				)
+			},
+			released: true,
+		},
+		{
+			name: "Success: GID released for access point deleting by a previous call without wait",
+			setup: func(mockCloud *mocks.MockCloud) {
+				mockCloud.EXPECT().DescribeAccessPoint(gomock.Any(), gomock.Eq(apId)).Return(withState(accessPointDeleting), nil)
+			},
+			released: true,
+		},
+		{
+			name: "Success: GID released for access point deleted while creating",
+			wait: time.Minute,
+			setup: func(mockCloud *mocks.MockCloud) {
+				gomock.InOrder(
+					mockCloud.EXPECT().DescribeAccessPoint(gomock.Any(), gomock.Eq(apId)).Return(withState(accessPointCreating), nil),
+					mockCloud.EXPECT().DescribeAccessPoint(gomock.Any(), gomock.Eq(apId)).Return(nil, cloud.ErrNotFound),
+				)
+			},
+			released: true,
+		},
+		{
+			name: "Fail: Access point still deleting at the end of the wait",
+			wait: 20 * time.Millisecond,
+			setup: func(mockCloud *mocks.MockCloud) {
+				gomock.InOrder(
+					mockCloud.EXPECT().DescribeAccessPoint(gomock.Any(), gomock.Eq(apId)).Return(withState(accessPointAvailable), nil),
+					mockCloud.EXPECT().DeleteAccessPoint(gomock.Any(), gomock.Eq(apId)).Return(nil),
+					mockCloud.EXPECT().DescribeAccessPoint(gomock.Any(), gomock.Eq(apId)).Return(withState(accessPointDeleting), nil).AnyTimes(),
+				)
+			},
+			expectedCode: codes.Unavailable,
+		},
+		{
+			name:    "Fail: Access point still deleting at the deadline of the call",
+			wait:    time.Minute,
+			timeout: 20 * time.Millisecond,
+			setup: func(mockCloud *mocks.MockCloud) {
+				gomock.InOrder(
+					mockCloud.EXPECT().DescribeAccessPoint(gomock.Any(), gomock.Eq(apId)).Return(withState(accessPointAvailable), nil),
+					mockCloud.EXPECT().DeleteAccessPoint(gomock.Any(), gomock.Eq(apId)).Return(nil),
+					mockCloud.EXPECT().DescribeAccessPoint(gomock.Any(), gomock.Eq(apId)).Return(withState(accessPointDeleting), nil).AnyTimes(),
+				)
+			},
+			expectedCode: codes.Unavailable,
+		},
+		{
+			name: "Fail: Access denied while waiting",
+			wait: time.Minute,
+			setup: func(mockCloud *mocks.MockCloud) {
+				gomock.InOrder(
+					mockCloud.EXPECT().DescribeAccessPoint(gomock.Any(), gomock.Eq(apId)).Return(withState(accessPointDeleting), nil),
+					mockCloud.EXPECT().DescribeAccessPoint(gomock.Any(), gomock.Eq(apId)).Return(nil, cloud.ErrAccessDenied),
+				)
+			},
+			expectedCode: codes.Unauthenticated,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockCtl := gomock.NewController(t)
+			mockCloud := mocks.NewMockCloud(mockCtl)
+			driver := &Driver{
+				cloud:                  mockCloud,
+				gidAllocator:           NewGidAllocator(),
+				waitAccessPointDeleted: tc.wait,
+				deleteCreatingApWait:   tc.wait,
+			}
+			if _, err := driver.gidAllocator.getNextGid(fsId, nil, gid, gid+10); err != nil {
+				t.Fatalf("getNextGid failed: %v", err)
+			}
+			tc.setup(mockCloud)
+
+			ctx := context.Background()
+			if tc.timeout > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, tc.timeout)
+				defer cancel()
+			}
+			_, err := driver.DeleteVolume(ctx, &csi.DeleteVolumeRequest{VolumeId: volumeId})
+			if status.Code(err) != tc.expectedCode {
+				t.Fatalf("Expected %v, got: %v", tc.expectedCode, err)
+			}
+			_, pending := driver.gidAllocator.trackedGids(fsId)
+			if released := len(pending) == 0; released != tc.released {
+				t.Fatalf("GID released mismatched. Expected: %v, Actual: %v, pending GIDs: %v", tc.released, released, pending)
+			}
+			mockCtl.Finish()
+		})
+	}
+}